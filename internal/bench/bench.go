@@ -0,0 +1,230 @@
+// Package bench load-tests an MCP server. Workers send the same request
+// concurrently over one shared session, and the run is summarized as
+// throughput, latency percentiles, a latency histogram and the errors
+// broken down by category.
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	mcperrors "github.com/standardbeagle/mcp-tui/internal/mcp/errors"
+)
+
+// ErrToolResult is returned by an operation whose tool call was answered
+// with an error result. Such requests count as failed, in their own
+// category, since the server answered them at the protocol level.
+var ErrToolResult = errors.New("tool returned an error result")
+
+// ToolResultCategory is the error category of ErrToolResult
+const ToolResultCategory = "tool_result"
+
+// Op is the request being benchmarked
+type Op func(ctx context.Context) error
+
+// Config is how a benchmark runs. At least one of Requests and Duration
+// must be set; with both, the run ends at whichever comes first.
+type Config struct {
+	Concurrency int           // Requests in flight at once; 1 when zero
+	Requests    int           // Requests to send in all
+	Duration    time.Duration // How long to keep sending requests
+	Timeout     time.Duration // Timeout of each request; none when zero
+}
+
+// Latency summarizes the latencies of a run
+type Latency struct {
+	Min  time.Duration
+	Mean time.Duration
+	P50  time.Duration
+	P90  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+}
+
+// MarshalJSON writes the latencies in milliseconds
+func (l Latency) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Min  float64 `json:"minMs"`
+		Mean float64 `json:"meanMs"`
+		P50  float64 `json:"p50Ms"`
+		P90  float64 `json:"p90Ms"`
+		P99  float64 `json:"p99Ms"`
+		Max  float64 `json:"maxMs"`
+	}{
+		milliseconds(l.Min), milliseconds(l.Mean), milliseconds(l.P50),
+		milliseconds(l.P90), milliseconds(l.P99), milliseconds(l.Max),
+	})
+}
+
+// ErrorCount is how many requests failed with errors of a category
+type ErrorCount struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+	Example  string `json:"example"` // One of the errors
+}
+
+// Result is the outcome of a benchmark. Latencies cover every request the
+// server answered, failed ones included.
+type Result struct {
+	Operation    string        `json:"operation"`
+	Concurrency  int           `json:"concurrency"`
+	Requests     int           `json:"requests"`
+	Succeeded    int           `json:"succeeded"`
+	Failed       int           `json:"failed"`
+	Elapsed      time.Duration `json:"-"`
+	Throughput   float64       `json:"throughput"` // Requests per second
+	Latency      Latency       `json:"latency"`
+	Errors       []ErrorCount  `json:"errors"`
+	Distribution []Step        `json:"distribution"`          // Percentile distribution of the latencies
+	Histogram    []Bucket      `json:"histogram"`             // Latency histogram buckets holding values
+	Interrupted  bool          `json:"interrupted,omitempty"` // Cancelled before the end
+}
+
+// MarshalJSON adds the elapsed time in milliseconds
+func (r *Result) MarshalJSON() ([]byte, error) {
+	type result Result
+	return json.Marshal(struct {
+		*result
+		ElapsedMs float64 `json:"elapsedMs"`
+	}{(*result)(r), milliseconds(r.Elapsed)})
+}
+
+// worker sends requests until the run ends, recording what it sees
+type worker struct {
+	histogram  Histogram
+	succeeded  int
+	errors     map[string]*ErrorCount
+	classifier *mcperrors.ErrorClassifier
+}
+
+// Run benchmarks op. Requests still in flight when the duration is up are
+// waited for; cancelling ctx stops the run early, leaving the requests it
+// cut short out of the result.
+func Run(ctx context.Context, operation string, cfg Config, op Op) (*Result, error) {
+	if cfg.Requests <= 0 && cfg.Duration <= 0 {
+		return nil, fmt.Errorf("a number of requests or a duration is required")
+	}
+	concurrency := max(cfg.Concurrency, 1)
+	if cfg.Requests > 0 {
+		concurrency = min(concurrency, cfg.Requests)
+	}
+
+	// sending gates new requests; the requests themselves use ctx, so the
+	// last ones complete after the duration is up
+	sending, stop := ctx, context.CancelFunc(func() {})
+	if cfg.Duration > 0 {
+		sending, stop = context.WithTimeout(ctx, cfg.Duration)
+	}
+	defer stop()
+
+	var issued atomic.Int64
+	workers := make([]*worker, concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := range workers {
+		w := &worker{errors: make(map[string]*ErrorCount), classifier: mcperrors.NewErrorClassifier()}
+		workers[i] = w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for sending.Err() == nil {
+				if cfg.Requests > 0 && issued.Add(1) > int64(cfg.Requests) {
+					return
+				}
+				w.send(ctx, cfg.Timeout, op)
+			}
+		}()
+	}
+	wg.Wait()
+
+	result := &Result{
+		Operation:   operation,
+		Concurrency: concurrency,
+		Elapsed:     time.Since(start),
+		Errors:      []ErrorCount{},
+		Interrupted: ctx.Err() != nil,
+	}
+	h := &Histogram{}
+	errorCounts := make(map[string]*ErrorCount)
+	for _, w := range workers {
+		h.Merge(&w.histogram)
+		result.Succeeded += w.succeeded
+		for category, count := range w.errors {
+			if total, ok := errorCounts[category]; ok {
+				total.Count += count.Count
+			} else {
+				errorCounts[category] = count
+			}
+		}
+	}
+	for _, count := range errorCounts {
+		result.Errors = append(result.Errors, *count)
+		result.Failed += count.Count
+	}
+	sort.Slice(result.Errors, func(i, j int) bool {
+		if result.Errors[i].Count != result.Errors[j].Count {
+			return result.Errors[i].Count > result.Errors[j].Count
+		}
+		return result.Errors[i].Category < result.Errors[j].Category
+	})
+
+	result.Requests = int(h.Count())
+	if result.Elapsed > 0 {
+		result.Throughput = float64(result.Requests) / result.Elapsed.Seconds()
+	}
+	result.Latency = Latency{
+		Min:  h.Min(),
+		Mean: h.Mean(),
+		P50:  h.Percentile(50),
+		P90:  h.Percentile(90),
+		P99:  h.Percentile(99),
+		Max:  h.Max(),
+	}
+	result.Distribution = h.Distribution()
+	if result.Distribution == nil {
+		result.Distribution = []Step{}
+	}
+	result.Histogram = h.Buckets()
+	if result.Histogram == nil {
+		result.Histogram = []Bucket{}
+	}
+	return result, nil
+}
+
+// send sends one request and records its latency and outcome
+func (w *worker) send(ctx context.Context, timeout time.Duration, op Op) {
+	reqCtx, cancel := ctx, context.CancelFunc(func() {})
+	if timeout > 0 {
+		reqCtx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	start := time.Now()
+	err := op(reqCtx)
+	latency := time.Since(start)
+	if err != nil && ctx.Err() != nil {
+		// Cut short by the run being cancelled, not failed by the server
+		return
+	}
+	w.histogram.Record(latency)
+	if err == nil {
+		w.succeeded++
+		return
+	}
+
+	category := ToolResultCategory
+	if !errors.Is(err, ErrToolResult) {
+		category = w.classifier.Classify(err, nil).Category.String()
+	}
+	if count, ok := w.errors[category]; ok {
+		count.Count++
+	} else {
+		w.errors[category] = &ErrorCount{Category: category, Count: 1, Example: err.Error()}
+	}
+}
@@ -0,0 +1,244 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/mock"
+)
+
+func TestBucketIndex(t *testing.T) {
+	for _, v := range []uint64{0, 1, 63, 64, 65, 100, 1000, 123456, 1 << 40} {
+		low, high := bucketRange(bucketIndex(v))
+		assert.LessOrEqual(t, low, v, "value %d", v)
+		assert.GreaterOrEqual(t, high, v, "value %d", v)
+		assert.LessOrEqual(t, float64(high-low), float64(v)/subBuckets, "value %d", v)
+	}
+	// Buckets follow each other without gaps
+	for i := 1; i < 500; i++ {
+		_, prevHigh := bucketRange(i - 1)
+		low, _ := bucketRange(i)
+		assert.Equal(t, prevHigh+1, low, "bucket %d", i)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	var h Histogram
+	for i := 1; i <= 100; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+
+	assert.Equal(t, uint64(100), h.Count())
+	assert.Equal(t, time.Millisecond, h.Min())
+	assert.Equal(t, 100*time.Millisecond, h.Max())
+	assert.Equal(t, 50500*time.Microsecond, h.Mean())
+	assert.InDelta(t, float64(50*time.Millisecond), float64(h.Percentile(50)), float64(50*time.Millisecond)/subBuckets)
+	assert.InDelta(t, float64(90*time.Millisecond), float64(h.Percentile(90)), float64(90*time.Millisecond)/subBuckets)
+	assert.InDelta(t, float64(99*time.Millisecond), float64(h.Percentile(99)), float64(99*time.Millisecond)/subBuckets)
+	assert.Equal(t, 100*time.Millisecond, h.Percentile(100))
+
+	steps := h.Distribution()
+	assert.Equal(t, []float64{0, 50, 75, 87.5, 93.75, 96.875, 98.4375, 100}, percentiles(steps))
+	assert.Equal(t, Step{Percentile: 0, Latency: time.Millisecond, Count: 1}, steps[0])
+	assert.Equal(t, Step{Percentile: 100, Latency: 100 * time.Millisecond, Count: 100}, steps[len(steps)-1])
+	for i := 1; i < len(steps); i++ {
+		assert.GreaterOrEqual(t, steps[i].Latency, steps[i-1].Latency)
+		assert.GreaterOrEqual(t, float64(steps[i].Count), steps[i].Percentile)
+	}
+
+	buckets := h.Buckets()
+	assert.Len(t, buckets, 81, "values above 64ms share buckets 2ms wide")
+	assert.Equal(t, Bucket{From: 992 * time.Microsecond, To: 1007 * time.Microsecond, Count: 1}, buckets[0])
+	assert.Equal(t, Bucket{From: 98304 * time.Microsecond, To: 100351 * time.Microsecond, Count: 2}, buckets[80])
+
+	var merged Histogram
+	merged.Merge(&h)
+	merged.Record(time.Second)
+	assert.Equal(t, uint64(101), merged.Count())
+	assert.Equal(t, time.Millisecond, merged.Min())
+	assert.Equal(t, time.Second, merged.Max())
+}
+
+func percentiles(steps []Step) []float64 {
+	out := make([]float64, len(steps))
+	for i, step := range steps {
+		out[i] = step.Percentile
+	}
+	return out
+}
+
+func TestRun(t *testing.T) {
+	var calls, inFlight, peak atomic.Int64
+	op := func(ctx context.Context) error {
+		n := calls.Add(1)
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if current <= p || peak.CompareAndSwap(p, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		switch {
+		case n%10 == 0:
+			return ErrToolResult
+		case n%25 == 0:
+			return fmt.Errorf("request failed: %w", context.DeadlineExceeded)
+		}
+		return nil
+	}
+
+	result, err := Run(context.Background(), "call slow", Config{Concurrency: 4, Requests: 100}, op)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), calls.Load())
+	assert.Equal(t, int64(4), peak.Load())
+	assert.Equal(t, 100, result.Requests)
+	assert.Equal(t, 88, result.Succeeded)
+	assert.Equal(t, 12, result.Failed)
+	assert.Equal(t, []string{ToolResultCategory, "timeout"}, []string{result.Errors[0].Category, result.Errors[1].Category})
+	assert.Equal(t, []int{10, 2}, []int{result.Errors[0].Count, result.Errors[1].Count})
+	assert.Greater(t, result.Throughput, 0.0)
+	assert.GreaterOrEqual(t, result.Latency.P50, 5*time.Millisecond)
+	assert.False(t, result.Interrupted)
+
+	_, err = Run(context.Background(), "call slow", Config{}, op)
+	assert.Error(t, err)
+}
+
+func TestRunDuration(t *testing.T) {
+	op := func(ctx context.Context) error {
+		time.Sleep(time.Millisecond)
+		return nil
+	}
+	result, err := Run(context.Background(), "list tools", Config{Concurrency: 2, Duration: 50 * time.Millisecond}, op)
+	require.NoError(t, err)
+	assert.Greater(t, result.Requests, 10)
+	assert.GreaterOrEqual(t, result.Elapsed, 50*time.Millisecond)
+}
+
+func TestRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	op := func(ctx context.Context) error {
+		cancel()
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	result, err := Run(ctx, "read mock://x", Config{Requests: 10}, op)
+	require.NoError(t, err)
+	assert.True(t, result.Interrupted)
+	assert.Zero(t, result.Requests, "requests cut short by the cancellation are not counted")
+}
+
+// TestSharedSession checks that requests from many workers are in flight at
+// once over a single session
+func TestSharedSession(t *testing.T) {
+	def, err := mock.ParseDefinition([]byte(`
+tools:
+  - name: slow
+    delay: 100ms
+    response: {text: done}
+  - name: broken
+    response: {text: failed, isError: true}
+resources:
+  - uri: mock://readme
+    text: "read me"
+`))
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(mock.NewServer(def).HTTPHandler())
+	t.Cleanup(httpServer.Close)
+
+	service := mcp.NewService()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, service.Connect(ctx, &config.ConnectionConfig{Type: config.TransportHTTP, URL: httpServer.URL}))
+	t.Cleanup(func() { service.Disconnect() })
+
+	// Sequentially, 20 calls would take 2s
+	result, err := Run(ctx, "call slow", Config{Concurrency: 10, Requests: 20}, CallTool(service, "slow", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 20, result.Succeeded)
+	assert.Less(t, result.Elapsed, time.Second)
+
+	result, err = Run(ctx, "call broken", Config{Concurrency: 2, Requests: 4}, CallTool(service, "broken", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []ErrorCount{{Category: ToolResultCategory, Count: 4, Example: ErrToolResult.Error()}}, result.Errors)
+
+	result, err = Run(ctx, "read mock://readme", Config{Concurrency: 2, Requests: 4}, ReadResource(service, "mock://readme"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.Succeeded)
+
+	op, err := List(service, "tools")
+	require.NoError(t, err)
+	result, err = Run(ctx, "list tools", Config{Requests: 3}, op)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Succeeded)
+
+	_, err = List(service, "widgets")
+	assert.ErrorContains(t, err, "expected tools, resources or prompts")
+}
+
+func TestWriteText(t *testing.T) {
+	result := &Result{
+		Operation:   "call search",
+		Concurrency: 4,
+		Requests:    40,
+		Succeeded:   37,
+		Failed:      3,
+		Elapsed:     2 * time.Second,
+		Throughput:  20,
+		Latency: Latency{
+			Min:  800 * time.Microsecond,
+			Mean: 12345 * time.Microsecond,
+			P50:  10 * time.Millisecond,
+			P90:  25 * time.Millisecond,
+			P99:  40 * time.Millisecond,
+			Max:  41 * time.Millisecond,
+		},
+		Errors: []ErrorCount{
+			{Category: "timeout", Count: 2, Example: "context deadline exceeded"},
+			{Category: ToolResultCategory, Count: 1, Example: ErrToolResult.Error()},
+		},
+		Distribution: []Step{
+			{Percentile: 0, Latency: 800 * time.Microsecond, Count: 1},
+			{Percentile: 50, Latency: 10 * time.Millisecond, Count: 20},
+			{Percentile: 75, Latency: 15 * time.Millisecond, Count: 30},
+			{Percentile: 87.5, Latency: 22500 * time.Microsecond, Count: 35},
+			{Percentile: 100, Latency: 1500 * time.Millisecond, Count: 40},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteText(&buf, result))
+	assert.Equal(t, `Benchmark: call search (4 concurrent)
+
+  Requests:    40 in 2s (20.0/s)
+  Succeeded:   37
+  Failed:      3
+
+Latency
+  min 800µs  mean 12.35ms  p50 10ms  p90 25ms  p99 40ms  max 41ms
+
+Distribution
+  Percentile     Latency    Count
+      0.000%       800µs        1
+     50.000%        10ms       20
+     75.000%        15ms       30
+     87.500%      22.5ms       35
+    100.000%        1.5s       40
+
+Errors
+  timeout          2  context deadline exceeded
+  tool_result      1  tool returned an error result
+`, buf.String())
+}
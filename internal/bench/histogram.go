@@ -0,0 +1,201 @@
+package bench
+
+import (
+	"encoding/json"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// subBucketBits sets the histogram's precision: each power of two is split
+// into 2^subBucketBits buckets, so a recorded value is off by at most 1/32
+const subBucketBits = 5
+
+const subBuckets = 1 << subBucketBits
+
+// Histogram records latencies in microseconds in log-linear buckets, as HDR
+// histograms do: exact below 64µs, then 32 buckets per power of two. It
+// takes constant memory whatever the number of values recorded.
+type Histogram struct {
+	counts []uint64
+	total  uint64
+	sum    uint64
+	min    uint64
+	max    uint64
+}
+
+// bucketIndex returns the bucket holding a value
+func bucketIndex(v uint64) int {
+	if v < 2*subBuckets {
+		return int(v)
+	}
+	shift := bits.Len64(v) - subBucketBits - 1
+	return shift*subBuckets + int(v>>shift)
+}
+
+// bucketRange returns the lowest and highest values a bucket holds
+func bucketRange(index int) (uint64, uint64) {
+	if index < 2*subBuckets {
+		return uint64(index), uint64(index)
+	}
+	shift := index/subBuckets - 1
+	sub := uint64(index - shift*subBuckets)
+	return sub << shift, (sub+1)<<shift - 1
+}
+
+// Record adds a latency
+func (h *Histogram) Record(d time.Duration) {
+	v := uint64(max(d.Microseconds(), 0))
+	index := bucketIndex(v)
+	if index >= len(h.counts) {
+		h.counts = append(h.counts, make([]uint64, index+1-len(h.counts))...)
+	}
+	h.counts[index]++
+	if h.total == 0 || v < h.min {
+		h.min = v
+	}
+	h.max = max(h.max, v)
+	h.total++
+	h.sum += v
+}
+
+// Merge adds the values recorded in another histogram
+func (h *Histogram) Merge(other *Histogram) {
+	if other.total == 0 {
+		return
+	}
+	if len(other.counts) > len(h.counts) {
+		h.counts = append(h.counts, make([]uint64, len(other.counts)-len(h.counts))...)
+	}
+	for i, n := range other.counts {
+		h.counts[i] += n
+	}
+	if h.total == 0 || other.min < h.min {
+		h.min = other.min
+	}
+	h.max = max(h.max, other.max)
+	h.total += other.total
+	h.sum += other.sum
+}
+
+// Count returns the number of values recorded
+func (h *Histogram) Count() uint64 {
+	return h.total
+}
+
+// Min returns the lowest value recorded
+func (h *Histogram) Min() time.Duration {
+	return micros(h.min)
+}
+
+// Max returns the highest value recorded
+func (h *Histogram) Max() time.Duration {
+	return micros(h.max)
+}
+
+// Mean returns the mean of the values recorded
+func (h *Histogram) Mean() time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	return time.Duration(float64(h.sum) / float64(h.total) * float64(time.Microsecond))
+}
+
+// Percentile returns the value below which q percent of the values fall:
+// the highest value of its bucket, but never above the maximum recorded
+func (h *Histogram) Percentile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	return h.step(q).Latency
+}
+
+// Bucket is a range of latencies and how many requests fell in it. From
+// and To are both included.
+type Bucket struct {
+	From  time.Duration
+	To    time.Duration
+	Count uint64
+}
+
+// MarshalJSON writes the range in milliseconds
+func (b Bucket) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		FromMs float64 `json:"fromMs"`
+		ToMs   float64 `json:"toMs"`
+		Count  uint64  `json:"count"`
+	}{milliseconds(b.From), milliseconds(b.To), b.Count})
+}
+
+// Buckets returns the buckets holding values, lowest first. Histograms of
+// different runs can be compared bucket by bucket, since the buckets are
+// always the same.
+func (h *Histogram) Buckets() []Bucket {
+	var buckets []Bucket
+	for i, n := range h.counts {
+		if n > 0 {
+			low, high := bucketRange(i)
+			buckets = append(buckets, Bucket{From: micros(low), To: micros(high), Count: n})
+		}
+	}
+	return buckets
+}
+
+// Step is a row of a percentile distribution: the latency under which a
+// percentage of the requests completed, and how many they were
+type Step struct {
+	Percentile float64
+	Latency    time.Duration
+	Count      uint64
+}
+
+// MarshalJSON writes the latency in milliseconds
+func (s Step) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Percentile float64 `json:"percentile"`
+		LatencyMs  float64 `json:"latencyMs"`
+		Count      uint64  `json:"count"`
+	}{s.Percentile, milliseconds(s.Latency), s.Count})
+}
+
+// Distribution returns the percentile distribution as HDR histograms print
+// it: the steps halve the distance to 100% each time (0, 50, 75, 87.5...),
+// stopping once they are finer than one value, then 100%
+func (h *Histogram) Distribution() []Step {
+	if h.total == 0 {
+		return nil
+	}
+	var steps []Step
+	for remaining := 100.0; remaining*float64(h.total) >= 100; remaining /= 2 {
+		steps = append(steps, h.step(100-remaining))
+	}
+	return append(steps, h.step(100))
+}
+
+// step returns the distribution step for a percentile
+func (h *Histogram) step(q float64) Step {
+	rank := uint64(math.Ceil(q / 100 * float64(h.total)))
+	rank = min(max(rank, 1), h.total)
+	var seen uint64
+	for i, n := range h.counts {
+		seen += n
+		if seen >= rank {
+			latency := h.min
+			if rank > 1 {
+				_, high := bucketRange(i)
+				latency = min(high, h.max)
+			}
+			return Step{Percentile: q, Latency: micros(latency), Count: seen}
+		}
+	}
+	return Step{Percentile: q, Latency: micros(h.max), Count: h.total}
+}
+
+func micros(v uint64) time.Duration {
+	return time.Duration(v) * time.Microsecond
+}
+
+// milliseconds converts a duration for JSON output, to the microsecond
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,53 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+)
+
+// CallTool calls a tool. A call answered with an error result fails with
+// ErrToolResult.
+func CallTool(service mcp.Service, name string, args map[string]interface{}) Op {
+	return func(ctx context.Context) error {
+		result, err := service.CallTool(ctx, mcp.CallToolRequest{Name: name, Arguments: args})
+		if err != nil {
+			return err
+		}
+		if result.IsError {
+			return ErrToolResult
+		}
+		return nil
+	}
+}
+
+// ReadResource reads a resource
+func ReadResource(service mcp.Service, uri string) Op {
+	return func(ctx context.Context) error {
+		_, err := service.ReadResource(ctx, uri)
+		return err
+	}
+}
+
+// List lists the tools, resources or prompts of a server
+func List(service mcp.Service, what string) (Op, error) {
+	switch what {
+	case "tools":
+		return func(ctx context.Context) error {
+			_, err := service.ListTools(ctx)
+			return err
+		}, nil
+	case "resources":
+		return func(ctx context.Context) error {
+			_, err := service.ListResources(ctx)
+			return err
+		}, nil
+	case "prompts":
+		return func(ctx context.Context) error {
+			_, err := service.ListPrompts(ctx)
+			return err
+		}, nil
+	}
+	return nil, fmt.Errorf("cannot list %q: expected tools, resources or prompts", what)
+}
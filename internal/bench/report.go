@@ -0,0 +1,60 @@
+package bench
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteText writes a readable report: the totals and throughput, the
+// latency percentiles, their distribution and the errors by category
+func WriteText(w io.Writer, result *Result) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Benchmark: %s (%d concurrent)\n", result.Operation, result.Concurrency)
+	if result.Interrupted {
+		b.WriteString("Interrupted: the results cover the requests completed so far\n")
+	}
+	fmt.Fprintf(&b, "\n  Requests:    %d in %s (%.1f/s)\n", result.Requests, formatDuration(result.Elapsed), result.Throughput)
+	fmt.Fprintf(&b, "  Succeeded:   %d\n", result.Succeeded)
+	fmt.Fprintf(&b, "  Failed:      %d\n", result.Failed)
+
+	if result.Requests > 0 {
+		l := result.Latency
+		b.WriteString("\nLatency\n")
+		fmt.Fprintf(&b, "  min %s  mean %s  p50 %s  p90 %s  p99 %s  max %s\n",
+			formatDuration(l.Min), formatDuration(l.Mean), formatDuration(l.P50),
+			formatDuration(l.P90), formatDuration(l.P99), formatDuration(l.Max))
+
+		b.WriteString("\nDistribution\n")
+		b.WriteString("  Percentile     Latency    Count\n")
+		for _, step := range result.Distribution {
+			fmt.Fprintf(&b, "  %9.3f%%  %10s  %7d\n", step.Percentile, formatDuration(step.Latency), step.Count)
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		categoryWidth := 0
+		for _, count := range result.Errors {
+			categoryWidth = max(categoryWidth, len(count.Category))
+		}
+		b.WriteString("\nErrors\n")
+		for _, count := range result.Errors {
+			fmt.Fprintf(&b, "  %-*s %6d  %s\n", categoryWidth, count.Category, count.Count, count.Example)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatDuration rounds a latency to a readable precision
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Millisecond:
+		return d.Round(time.Microsecond).String()
+	case d < time.Second:
+		return d.Round(10 * time.Microsecond).String()
+	}
+	return d.Round(time.Millisecond).String()
+}
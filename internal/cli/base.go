@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -10,21 +11,36 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/standardbeagle/mcp-tui/internal/config"
 	"github.com/standardbeagle/mcp-tui/internal/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/query"
 )
 
 // OutputFormat represents supported output formats
 type OutputFormat string
 
 const (
-	OutputFormatText OutputFormat = "text"
-	OutputFormatJSON OutputFormat = "json"
+	OutputFormatText   OutputFormat = "text"
+	OutputFormatJSON   OutputFormat = "json"
+	OutputFormatYAML   OutputFormat = "yaml"
+	OutputFormatTable  OutputFormat = "table"
+	OutputFormatCSV    OutputFormat = "csv"
+	OutputFormatNDJSON OutputFormat = "ndjson"
 )
 
+// outputFormats are the formats --format accepts
+var outputFormats = []OutputFormat{
+	OutputFormatText, OutputFormatJSON, OutputFormatYAML,
+	OutputFormatTable, OutputFormatCSV, OutputFormatNDJSON,
+}
+
 // BaseCommand provides common functionality for all CLI commands
 type BaseCommand struct {
 	service      mcp.Service
+	shared       bool // The service belongs to the caller, e.g. the shell
 	timeout      time.Duration
 	outputFormat OutputFormat
+	columns      []string     // Table and CSV columns chosen with --columns
+	query        *query.Query // Expression chosen with --query
+	output       io.Writer    // Where Render prints
 }
 
 // getGlobalConnection returns the global connection config if available
@@ -47,6 +63,7 @@ func NewBaseCommand() *BaseCommand {
 	return &BaseCommand{
 		timeout:      30 * time.Second,
 		outputFormat: OutputFormatText,
+		output:       os.Stdout,
 	}
 }
 
@@ -59,13 +76,42 @@ func (c *BaseCommand) WithTimeout(timeout time.Duration) *BaseCommand {
 // SetOutputFormat sets the output format for the command
 func (c *BaseCommand) SetOutputFormat(cmd *cobra.Command) error {
 	format, _ := cmd.Flags().GetString("format")
-	switch format {
-	case "text", "":
-		c.outputFormat = OutputFormatText
-	case "json":
-		c.outputFormat = OutputFormatJSON
-	default:
-		return fmt.Errorf("unsupported output format: %s (supported: text, json)", format)
+	// The prompt commands take the format as --output
+	if output := cmd.Flags().Lookup("output"); output != nil && output.Changed {
+		format = output.Value.String()
+	}
+	if format == "" {
+		format = string(OutputFormatText)
+	}
+
+	c.outputFormat = ""
+	names := make([]string, len(outputFormats))
+	for i, supported := range outputFormats {
+		names[i] = string(supported)
+		if strings.EqualFold(format, names[i]) {
+			c.outputFormat = supported
+		}
+	}
+	if c.outputFormat == "" {
+		return fmt.Errorf("unsupported output format: %s (supported: %s)", format, strings.Join(names, ", "))
+	}
+
+	c.columns = nil
+	columns, _ := cmd.Flags().GetStringSlice("columns")
+	for _, column := range columns {
+		if column = strings.TrimSpace(column); column != "" {
+			c.columns = append(c.columns, column)
+		}
+	}
+
+	// Parse the query now so a mistake is reported before connecting
+	c.query = nil
+	if expr, _ := cmd.Flags().GetString("query"); strings.TrimSpace(expr) != "" {
+		q, err := query.Parse(expr)
+		if err != nil {
+			return err
+		}
+		c.query = q
 	}
 	return nil
 }
@@ -75,42 +121,29 @@ func (c *BaseCommand) GetOutputFormat() OutputFormat {
 	return c.outputFormat
 }
 
-// CreateClient creates and initializes an MCP client
-func (c *BaseCommand) CreateClient(cmd *cobra.Command) error {
-	var connConfig *config.ConnectionConfig
-
-	// Check if we have a global connection config (from natural CLI usage)
-	// This is set when using: mcp-tui "server command" tool list
-	if globalConnConfig := c.getGlobalConnection(); globalConnConfig != nil {
-		connConfig = globalConnConfig
-	} else {
-		// Parse from flags
-		cmdFlag, _ := cmd.Flags().GetString("cmd")
-		urlFlag, _ := cmd.Flags().GetString("url")
-		transportFlag, _ := cmd.Flags().GetString("transport")
-
-		// Get args as string slice (multiple --args flags)
-		argsFlag, _ := cmd.Flags().GetStringSlice("args")
+// StructuredOutput reports whether output goes through Render: for any
+// format but text, and for text when a query selects from the JSON document
+func (c *BaseCommand) StructuredOutput() bool {
+	return c.outputFormat != OutputFormatText || c.query != nil
+}
 
-		// Use the unified parser
-		parsedArgs := config.ParseArgs(cmd.Flags().Args(), cmdFlag, urlFlag, argsFlag)
-		connConfig = parsedArgs.Connection
+// UseService makes the command run against a service that is already
+// connected, which it leaves connected when it finishes
+func (c *BaseCommand) UseService(service mcp.Service) *BaseCommand {
+	c.service = service
+	c.shared = true
+	return c
+}
 
-		// Apply explicit transport type if specified (and not the default)
-		if transportFlag != "" && transportFlag != "stdio" && connConfig != nil {
-			connConfig.Type = config.TransportType(transportFlag)
-		} else if urlFlag != "" && connConfig != nil {
-			// Auto-detect transport from URL if not explicitly specified
-			if strings.Contains(urlFlag, "/events") || strings.Contains(urlFlag, "sse") {
-				connConfig.Type = config.TransportSSE
-			} else {
-				connConfig.Type = config.TransportHTTP
-			}
-		}
+// CreateClient creates and initializes an MCP client
+func (c *BaseCommand) CreateClient(cmd *cobra.Command) error {
+	if c.shared {
+		return nil
 	}
 
-	if connConfig == nil {
-		return fmt.Errorf("no MCP server connection specified\n\nConnection options:\n- Use --cmd for stdio servers: --cmd 'npx @modelcontextprotocol/server-everything stdio'\n- Use --url for HTTP servers: --url 'http://localhost:8080'\n- Use --url for SSE servers: --url 'http://localhost:8080/events'\n\nExamples:\n  mcp-tui tool list --cmd npx --args '@modelcontextprotocol/server-everything,stdio'\n  mcp-tui tool list --url 'http://localhost:8080'")
+	connConfig, err := c.connectionConfig(cmd)
+	if err != nil {
+		return err
 	}
 
 	// Check if porcelain mode is enabled
@@ -134,8 +167,11 @@ func (c *BaseCommand) CreateClient(cmd *cobra.Command) error {
 		switch connConfig.Type {
 		case config.TransportStdio:
 			fmt.Fprintf(os.Stderr, "🚀 Starting process: %s %s\n", connConfig.Command, strings.Join(connConfig.Args, " "))
-		case config.TransportHTTP, config.TransportSSE:
+		case config.TransportHTTP, config.TransportSSE, config.TransportWebSocket,
+			config.TransportUnix, config.TransportTCP, config.TransportAuto:
 			fmt.Fprintf(os.Stderr, "🌐 Connecting to URL: %s\n", connConfig.URL)
+		case config.TransportReplay:
+			fmt.Fprintf(os.Stderr, "📼 Replaying cassette: %s\n", connConfig.URL)
 		}
 
 		fmt.Fprintf(os.Stderr, "⏳ Establishing connection (timeout: %s)...\n", c.timeout)
@@ -161,9 +197,65 @@ func (c *BaseCommand) CreateClient(cmd *cobra.Command) error {
 	return nil
 }
 
+// connectionConfig returns the connection given on the command line, with
+// the connection options applied
+func (c *BaseCommand) connectionConfig(cmd *cobra.Command) (*config.ConnectionConfig, error) {
+	var connConfig *config.ConnectionConfig
+
+	// Check if we have a global connection config (from natural CLI usage)
+	// This is set when using: mcp-tui "server command" tool list
+	if globalConnConfig := c.getGlobalConnection(); globalConnConfig != nil {
+		connConfig = globalConnConfig
+	} else {
+		// Parse from flags
+		cmdFlag, _ := cmd.Flags().GetString("cmd")
+		urlFlag, _ := cmd.Flags().GetString("url")
+		transportFlag, _ := cmd.Flags().GetString("transport")
+
+		// Get args as string slice (multiple --args flags)
+		argsFlag, _ := cmd.Flags().GetStringSlice("args")
+
+		// Use the unified parser
+		parsedArgs := config.ParseArgs(cmd.Flags().Args(), cmdFlag, urlFlag, argsFlag)
+		connConfig = parsedArgs.Connection
+
+		// Apply explicit transport type if specified (and not the default)
+		if transportFlag != "" && transportFlag != "stdio" && connConfig != nil {
+			connConfig.Type = config.TransportType(transportFlag)
+		} else if urlFlag != "" && connConfig != nil {
+			// Auto-detect transport from URL if not explicitly specified
+			connConfig.Type = config.TransportForURL(urlFlag)
+		}
+	}
+
+	if connConfig == nil {
+		return nil, fmt.Errorf("no MCP server connection specified\n\nConnection options:\n- Use --cmd for stdio servers: --cmd 'npx @modelcontextprotocol/server-everything stdio'\n- Use --url for HTTP servers: --url 'http://localhost:8080'\n- Use --url for SSE servers: --url 'http://localhost:8080/events'\n\nExamples:\n  mcp-tui tool list --cmd npx --args '@modelcontextprotocol/server-everything,stdio'\n  mcp-tui tool list --url 'http://localhost:8080'")
+	}
+
+	ApplyConnectionOptions(cmd, connConfig)
+	return connConfig, nil
+}
+
+// ApplyConnectionOptions applies the session recording, replay and fault
+// injection flags (--record, --replay-match and --chaos) to a connection
+func ApplyConnectionOptions(cmd *cobra.Command, connConfig *config.ConnectionConfig) {
+	if connConfig == nil {
+		return
+	}
+	if recordPath, _ := cmd.Flags().GetString("record"); recordPath != "" {
+		connConfig.RecordPath = recordPath
+	}
+	if replayMatch, _ := cmd.Flags().GetString("replay-match"); replayMatch != "" {
+		connConfig.ReplayMatch = replayMatch
+	}
+	if chaosPath, _ := cmd.Flags().GetString("chaos"); chaosPath != "" {
+		connConfig.ChaosPath = chaosPath
+	}
+}
+
 // CloseClient properly closes the MCP client
 func (c *BaseCommand) CloseClient() error {
-	if c.service == nil {
+	if c.service == nil || c.shared {
 		return nil
 	}
 
@@ -0,0 +1,217 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/standardbeagle/mcp-tui/internal/bench"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/schema"
+)
+
+// defaultBenchRequests is how many requests are sent when neither
+// --requests nor --duration is given
+const defaultBenchRequests = 100
+
+// BenchCommand load-tests a server by sending one request many times,
+// concurrently, over a single session
+type BenchCommand struct {
+	*BaseCommand
+	config bench.Config
+}
+
+// NewBenchCommand creates a new bench command
+func NewBenchCommand() *BenchCommand {
+	return &BenchCommand{
+		BaseCommand: NewBaseCommand(),
+	}
+}
+
+// CreateCommand creates the cobra command
+func (bc *BenchCommand) CreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Measure the throughput and latency of a server",
+		Long: `Send the same request to a server many times, with several requests in
+flight at once over a single session, and report:
+
+  - throughput, in requests per second
+  - latency: min, mean, p50, p90, p99 and max
+  - the percentile distribution of the latencies, as HDR histograms print
+    it, from a histogram with 3% precision
+  - the errors broken down by category (timeout, transport, protocol...);
+    tool calls answered with an error result count as tool_result
+
+The run sends --requests requests in all (100 by default), or keeps sending
+for --duration; requests in flight when it ends are waited for. Each request
+is limited by --timeout. Interrupting the run reports what completed so far.
+
+Use --format json to keep the results and compare them across runs; it also
+holds the histogram buckets, which are the same in every run.
+
+Examples:
+  mcp-tui "node server.js" bench call search query=test --concurrency 10 --requests 1000
+  mcp-tui --url http://localhost:8080/mcp bench read file:///data.csv --duration 30s
+  mcp-tui "node server.js" bench list tools --format json > baseline.json`,
+	}
+
+	cmd.PersistentFlags().IntVarP(&bc.config.Concurrency, "concurrency", "c", 10, "Requests in flight at once")
+	cmd.PersistentFlags().IntVarP(&bc.config.Requests, "requests", "n", 0, "Requests to send in all (default 100 unless --duration is given)")
+	cmd.PersistentFlags().DurationVarP(&bc.config.Duration, "duration", "d", 0, "Keep sending requests for this long, e.g. 30s")
+	cmd.MarkFlagsMutuallyExclusive("requests", "duration")
+
+	cmd.AddCommand(bc.createCallCommand())
+	cmd.AddCommand(bc.createReadCommand())
+	cmd.AddCommand(bc.createListCommand())
+
+	return cmd
+}
+
+// createCallCommand creates the bench call command
+func (bc *BenchCommand) createCallCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "call <tool-name> [arguments...]",
+		Short: "Benchmark a tool call",
+		Long: `Benchmark calling a tool. Arguments are given as for tool call: key=value,
+key:=<json>, key=@file and parent.key=value, or with --input-json.`,
+		Args:     cobra.MinimumNArgs(1),
+		PreRunE:  bc.PreRunE,
+		PostRunE: bc.PostRunE,
+		RunE:     bc.runCall,
+	}
+
+	cmd.Flags().String("input-json", "", "Read the arguments object from a JSON file, or stdin with \"-\"")
+	cmd.Flags().Bool("no-validate", false, "Send the arguments without checking them against the tool's input schema")
+
+	return cmd
+}
+
+// createReadCommand creates the bench read command
+func (bc *BenchCommand) createReadCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:      "read <uri>",
+		Short:    "Benchmark reading a resource",
+		Args:     cobra.ExactArgs(1),
+		PreRunE:  bc.PreRunE,
+		PostRunE: bc.PostRunE,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bc.ValidateConnection(); err != nil {
+				return bc.HandleError(err, "validate connection")
+			}
+			return bc.run(cmd, "read "+args[0], bench.ReadResource(bc.GetService(), args[0]))
+		},
+	}
+}
+
+// createListCommand creates the bench list command
+func (bc *BenchCommand) createListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:       "list <tools|resources|prompts>",
+		Short:     "Benchmark listing tools, resources or prompts",
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: []string{"tools", "resources", "prompts"},
+		PreRunE:   bc.PreRunE,
+		PostRunE:  bc.PostRunE,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := bc.ValidateConnection(); err != nil {
+				return bc.HandleError(err, "validate connection")
+			}
+			op, err := bench.List(bc.GetService(), args[0])
+			if err != nil {
+				return err
+			}
+			return bc.run(cmd, "list "+args[0], op)
+		},
+	}
+}
+
+// runCall builds the tool call's arguments, checking them once against the
+// tool's input schema, and benchmarks the call
+func (bc *BenchCommand) runCall(cmd *cobra.Command, args []string) error {
+	if err := bc.ValidateConnection(); err != nil {
+		return bc.HandleError(err, "validate connection")
+	}
+	toolName := args[0]
+
+	ctx, cancel := bc.WithContext()
+	defer cancel()
+	tools, err := bc.GetService().ListTools(ctx)
+	if err != nil {
+		return bc.HandleError(err, "list tools")
+	}
+	var inputSchema map[string]interface{}
+	found := false
+	for _, tool := range tools {
+		if tool.Name == toolName {
+			inputSchema, found = tool.InputSchema, true
+			break
+		}
+	}
+	if !found && bc.showProgress(cmd) {
+		fmt.Fprintf(os.Stderr, "⚠️  Tool '%s' is not listed by the server; arguments are sent unchecked\n", toolName)
+	}
+
+	var toolArgs map[string]interface{}
+	if inputJSON, _ := cmd.Flags().GetString("input-json"); inputJSON != "" {
+		if toolArgs, err = readInputJSON(inputJSON, cmd.InOrStdin()); err != nil {
+			return err
+		}
+	}
+	toolArgs, err = newToolArgumentParser(inputSchema).parse(toolArgs, args[1:])
+	if err != nil {
+		return err
+	}
+	if noValidate, _ := cmd.Flags().GetBool("no-validate"); found && !noValidate {
+		if violations := schema.Validate(inputSchema, toolArgs); len(violations) > 0 {
+			return schemaViolationsError(toolName, violations)
+		}
+	}
+
+	return bc.run(cmd, "call "+toolName, bench.CallTool(bc.GetService(), toolName, toolArgs))
+}
+
+// run benchmarks op and prints the result
+func (bc *BenchCommand) run(cmd *cobra.Command, operation string, op bench.Op) error {
+	cfg := bc.config
+	if cfg.Requests == 0 && cfg.Duration == 0 {
+		cfg.Requests = defaultBenchRequests
+	}
+	if cfg.Concurrency < 1 || cfg.Requests < 0 || cfg.Duration < 0 {
+		return fmt.Errorf("--concurrency must be at least 1, and --requests and --duration positive")
+	}
+	if cmd.Flags().Changed("timeout") {
+		bc.timeout, _ = cmd.Flags().GetDuration("timeout")
+	}
+	cfg.Timeout = bc.timeout
+
+	if bc.showProgress(cmd) {
+		extent := pluralize(cfg.Requests, "request")
+		if cfg.Duration > 0 {
+			extent = "for " + cfg.Duration.String()
+		}
+		fmt.Fprintf(os.Stderr, "⏱️  Benchmarking %s on %s: %s, %d concurrent...\n",
+			operation, bc.GetService().GetServerInfo().Name, extent, cfg.Concurrency)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	result, err := bench.Run(ctx, operation, cfg, op)
+	if err != nil {
+		return err
+	}
+
+	if bc.StructuredOutput() {
+		return bc.Render(document{Data: result, Items: result.Distribution, Columns: []string{"percentile", "latencyMs", "count"}})
+	}
+	return bench.WriteText(bc.output, result)
+}
+
+// showProgress reports whether progress messages are shown
+func (bc *BenchCommand) showProgress(cmd *cobra.Command) bool {
+	porcelainMode, _ := cmd.Flags().GetBool("porcelain")
+	return bc.GetOutputFormat() == OutputFormatText && !porcelainMode
+}
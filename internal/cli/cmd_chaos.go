@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/chaos"
+)
+
+// ChaosCommand runs a stdio server behind a fault-injecting proxy
+type ChaosCommand struct {
+	configPath string
+	faults     []string
+	seed       int64
+}
+
+// NewChaosCommand creates a new chaos command
+func NewChaosCommand() *ChaosCommand {
+	return &ChaosCommand{}
+}
+
+// CreateCommand creates the cobra command
+func (c *ChaosCommand) CreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "chaos [--config faults.yaml] [--fault spec]... -- <server command> [args...]",
+		Short: "Run a stdio MCP server behind a fault-injecting proxy",
+		Long: `Start a stdio MCP server and relay its JSON-RPC stream through a proxy
+that injects faults: latency with jitter, dropped, duplicated or reordered
+messages, truncated or corrupted JSON, oversized payloads and disconnects.
+
+Each fault fires with a probability or on a scripted schedule of frame
+numbers, and can be limited to one direction (client or server) or method.
+Faults come from a YAML/JSON schedule (--config) and/or --fault specs of the
+form "type,key=value,...":
+
+  latency,latency=200ms,jitter=100ms
+  drop,probability=0.1,from=server
+  reorder,method=tools/call
+  corrupt,from=server,at=3,at=5
+  oversize,size=4194304
+  disconnect,at=20
+
+The proxy speaks stdio, so it is used as the server command:
+  mcp-tui "mcp-tui chaos --fault drop,probability=0.2 -- node server.js" tool list
+
+To inject faults in-process instead, pass a schedule with --chaos:
+  mcp-tui --chaos faults.yaml "node server.js" tool list`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: c.RunE,
+	}
+
+	cmd.Flags().StringVar(&c.configPath, "config", "", "Fault schedule file (YAML or JSON)")
+	cmd.Flags().StringArrayVar(&c.faults, "fault", nil, "Fault spec, e.g. drop,probability=0.1 (repeatable)")
+	cmd.Flags().Int64Var(&c.seed, "seed", 0, "Random seed for a reproducible run (default: from the schedule, else random)")
+
+	return cmd
+}
+
+// RunE executes the chaos command
+func (c *ChaosCommand) RunE(cmd *cobra.Command, args []string) error {
+	schedule, err := c.loadSchedule()
+	if err != nil {
+		return err
+	}
+
+	command, commandArgs := args[0], args[1:]
+	if len(args) == 1 && strings.Contains(args[0], " ") {
+		// Accept the server as a single quoted string too
+		conn := config.ParseConnectionString(args[0])
+		command, commandArgs = conn.Command, conn.Args
+	}
+	if err := config.ValidateCommand(command, commandArgs); err != nil {
+		return fmt.Errorf("command validation failed: %w", err)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	server := exec.CommandContext(ctx, command, commandArgs...)
+	server.Stderr = os.Stderr
+	serverIn, err := server.StdinPipe()
+	if err != nil {
+		return err
+	}
+	serverOut, err := server.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := server.Start(); err != nil {
+		return fmt.Errorf("failed to start server: %w", err)
+	}
+
+	injector := chaos.NewInjector(schedule)
+	// stdout carries the protocol, so only stderr is used for messages
+	fmt.Fprintf(os.Stderr, "🌪️  Chaos proxy running %s with %d fault(s), seed %d\n",
+		strings.Join(append([]string{command}, commandArgs...), " "), len(schedule.Faults), injector.Seed())
+
+	proxyErr := injector.Proxy(ctx, os.Stdin, os.Stdout, serverIn, serverOut)
+	if proxyErr != nil {
+		server.Process.Kill()
+	}
+	waitErr := server.Wait()
+
+	if errors.Is(proxyErr, chaos.ErrDisconnect) || errors.Is(proxyErr, context.Canceled) {
+		return nil
+	}
+	if proxyErr != nil {
+		return proxyErr
+	}
+	if waitErr != nil {
+		return fmt.Errorf("server exited: %w", waitErr)
+	}
+	return nil
+}
+
+// loadSchedule combines the schedule file with --fault and --seed
+func (c *ChaosCommand) loadSchedule() (*chaos.Config, error) {
+	schedule := &chaos.Config{}
+	if c.configPath != "" {
+		loaded, err := chaos.LoadConfig(c.configPath)
+		if err != nil {
+			return nil, err
+		}
+		schedule = loaded
+	}
+	for _, spec := range c.faults {
+		fault, err := chaos.ParseFault(spec)
+		if err != nil {
+			return nil, err
+		}
+		schedule.Faults = append(schedule.Faults, fault)
+	}
+	if c.seed != 0 {
+		schedule.Seed = c.seed
+	}
+	if len(schedule.Faults) == 0 {
+		return nil, fmt.Errorf("no faults configured: use --config or --fault")
+	}
+	return schedule, nil
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp/conformance"
+)
+
+// CheckCommand probes an MCP server for spec conformance
+type CheckCommand struct {
+	*BaseCommand
+}
+
+// NewCheckCommand creates a new check command
+func NewCheckCommand() *CheckCommand {
+	return &CheckCommand{
+		BaseCommand: NewBaseCommand(),
+	}
+}
+
+// CreateCommand creates the cobra command
+func (cc *CheckCommand) CreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check an MCP server for protocol conformance",
+		Long: `Run protocol conformance probes against an MCP server and report each as
+pass, warn or fail, with a link to the part of the specification it checks:
+
+  initialize           The handshake result has a version, capabilities and server info
+  version-negotiation  An unknown protocol version is answered with a supported one
+  ping                 Ping returns an empty result
+  unknown-method       Unknown methods fail with -32601 (method not found)
+  invalid-params       Missing required params fail with -32602 (invalid params)
+  capabilities         Advertised capabilities work, and working ones are advertised
+  pagination           Cursors end, do not loop, and invalid ones are refused
+  tool-schemas         Every inputSchema is a valid JSON Schema of type object
+  tool-errors          Unknown tools are protocol errors; bad arguments fail
+  notifications        Notifications get no response and do not upset the server
+  stdout               A stdio server writes nothing but JSON-RPC messages to stdout
+
+The check opens two connections, one for the version negotiation probe. It
+calls no tool with valid arguments, only an unknown tool and, if there is
+one, a tool with its required arguments left out.
+
+Warnings are SHOULDs of the specification or likely mistakes; the command
+exits non-zero only when a probe fails. Use --format json for CI.
+
+Examples:
+  mcp-tui "node server.js" check
+  mcp-tui --url http://localhost:8080/mcp check --format json
+  mcp-tui "npx -y @modelcontextprotocol/server-everything stdio" check --query '.results[] | select(.status == "fail")'`,
+		Args: cobra.NoArgs,
+		RunE: cc.run,
+	}
+
+	return cmd
+}
+
+func (cc *CheckCommand) run(cmd *cobra.Command, args []string) error {
+	if err := cc.SetOutputFormat(cmd); err != nil {
+		return err
+	}
+	connConfig, err := cc.connectionConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timeout := conformance.DefaultTimeout
+	if cmd.Flags().Changed("timeout") {
+		timeout, _ = cmd.Flags().GetDuration("timeout")
+	}
+	dial, transport, err := conformance.NewDialer(ctx, connConfig, timeout)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+
+	porcelainMode, _ := cmd.Flags().GetBool("porcelain")
+	if !porcelainMode && !cc.StructuredOutput() {
+		fmt.Fprintf(os.Stderr, "🔎 Checking %s over %s...\n", describeUpstream(connConfig), transport)
+	}
+	checker := &conformance.Checker{Dial: dial, Transport: transport, Timeout: timeout}
+	report := checker.Run(ctx)
+
+	if cc.StructuredOutput() {
+		err = cc.Render(document{Data: report, Items: report.Results, Columns: []string{"id", "status", "message"}})
+	} else {
+		err = conformance.WriteText(cc.output, report)
+	}
+	if err != nil {
+		return err
+	}
+
+	if report.Failed() {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d conformance probes failed", report.Summary.Failed, len(report.Results))
+	}
+	return nil
+}
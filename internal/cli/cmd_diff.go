@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/standardbeagle/mcp-tui/internal/compat"
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+)
+
+// DiffCommand compares the catalogs of two servers, or two versions of one
+type DiffCommand struct {
+	*BaseCommand
+}
+
+// NewDiffCommand creates a new diff command
+func NewDiffCommand() *DiffCommand {
+	return &DiffCommand{
+		BaseCommand: NewBaseCommand(),
+	}
+}
+
+// CreateCommand creates the cobra command
+func (dc *DiffCommand) CreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <old> <new>",
+		Short: "Compare the tools, resources and prompts of two servers",
+		Long: `Connect to two servers, or two versions of one, and report the tools,
+resources and prompts that were added, removed or changed.
+
+Each change is classified as breaking for clients written against the old
+server or not. Removing an entry is breaking and adding one is not. In tool
+input schemas, removed properties, newly required ones, narrowed types,
+enums and bounds, and disallowed extra properties are breaking; the
+opposites are not. Descriptions are compared word by word and shown inline
+as [-removed-] {+added+}.
+
+Either side may be a connection string, a URL, or a snapshot directory or
+file saved with snapshot save. With one argument, the old side is the
+connection given before the command or with --cmd or --url.
+
+The command exits non-zero when there are breaking changes.
+
+Examples:
+  mcp-tui diff "node server-v1.js" "node server-v2.js"
+  mcp-tui diff testdata/golden "node server.js"
+  mcp-tui "node server.js" diff http://staging:8080/mcp --format json`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: dc.run,
+	}
+
+	return cmd
+}
+
+// side is one of the two catalogs compared
+type side struct {
+	source     string
+	connConfig *config.ConnectionConfig // Nil for a snapshot
+}
+
+// sides resolves the arguments to the two catalogs compared
+func (dc *DiffCommand) sides(cmd *cobra.Command, args []string) (side, side, error) {
+	resolve := func(arg string) (side, error) {
+		if compat.IsSnapshot(arg) {
+			return side{source: arg}, nil
+		}
+		connConfig := config.ParseConnectionString(arg)
+		if connConfig == nil {
+			return side{}, fmt.Errorf("empty server argument")
+		}
+		ApplyConnectionOptions(cmd, connConfig)
+		return side{source: arg, connConfig: connConfig}, nil
+	}
+	if len(args) == 2 {
+		oldSide, err := resolve(args[0])
+		if err != nil {
+			return side{}, side{}, err
+		}
+		newSide, err := resolve(args[1])
+		return oldSide, newSide, err
+	}
+
+	cmdFlag, _ := cmd.Flags().GetString("cmd")
+	urlFlag, _ := cmd.Flags().GetString("url")
+	if dc.getGlobalConnection() == nil && cmdFlag == "" && urlFlag == "" {
+		return side{}, side{}, fmt.Errorf("diff needs two servers or snapshots, e.g. mcp-tui diff \"node v1.js\" \"node v2.js\"")
+	}
+	connConfig, err := dc.connectionConfig(cmd)
+	if err != nil {
+		return side{}, side{}, err
+	}
+	newSide, err := resolve(args[0])
+	return side{source: describeUpstream(connConfig), connConfig: connConfig}, newSide, err
+}
+
+func (dc *DiffCommand) run(cmd *cobra.Command, args []string) error {
+	if err := dc.SetOutputFormat(cmd); err != nil {
+		return err
+	}
+	oldSide, newSide, err := dc.sides(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	porcelainMode, _ := cmd.Flags().GetBool("porcelain")
+	if !porcelainMode && !dc.StructuredOutput() {
+		fmt.Fprintf(os.Stderr, "🔍 Comparing %s with %s...\n", oldSide.source, newSide.source)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if cmd.Flags().Changed("timeout") {
+		dc.timeout, _ = cmd.Flags().GetDuration("timeout")
+	}
+	debugMode, _ := cmd.Flags().GetBool("debug")
+	var catalogs [2]*compat.Catalog
+	for i, s := range []side{oldSide, newSide} {
+		if catalogs[i], err = dc.catalog(ctx, s, debugMode); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+	}
+	report := compat.Compare(catalogs[0], catalogs[1])
+
+	if dc.StructuredOutput() {
+		err = dc.Render(document{Data: report, Items: report.Changes, Columns: []string{"kind", "name", "change", "breaking"}})
+	} else {
+		err = compat.WriteText(dc.output, report)
+	}
+	if err != nil {
+		return err
+	}
+
+	if report.Breaking() {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%s found", pluralize(report.Summary.Breaking, "breaking change"))
+	}
+	return nil
+}
+
+// catalog loads a snapshot or lists the catalog of a server
+func (dc *DiffCommand) catalog(ctx context.Context, s side, debugMode bool) (*compat.Catalog, error) {
+	if s.connConfig == nil {
+		return compat.LoadSnapshot(s.source)
+	}
+	ctx, cancel := context.WithTimeout(ctx, dc.timeout)
+	defer cancel()
+	service := mcp.NewService()
+	service.SetDebugMode(debugMode)
+	if err := service.Connect(ctx, s.connConfig); err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", s.source, err)
+	}
+	defer service.Disconnect()
+	return compat.FromService(ctx, service, s.source)
+}
@@ -0,0 +1,194 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/fuzz"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/conformance"
+)
+
+// FuzzCommand calls a server's tools with inputs generated from their
+// input schemas, looking for crashes, hangs and protocol errors
+type FuzzCommand struct {
+	*BaseCommand
+	all        bool
+	categories []string
+	maxCases   int
+	noMinimize bool
+	reproDir   string
+}
+
+// NewFuzzCommand creates a new fuzz command
+func NewFuzzCommand() *FuzzCommand {
+	return &FuzzCommand{
+		BaseCommand: NewBaseCommand(),
+	}
+}
+
+// CreateCommand creates the cobra command
+func (fc *FuzzCommand) CreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fuzz <tool-name>... | --all",
+		Short: "Fuzz tools with inputs generated from their input schemas",
+		Long: `Call tools with inputs generated from their input schemas and report the
+calls the server handles badly. The inputs, by category:
+
+  valid     Sample values for the required properties, and for all of them
+  boundary  Limits of numbers, string lengths, array sizes and enums
+  type      Each property given a value of the wrong JSON type
+  missing   Each required property left out
+  huge      Strings of 1 MiB, arrays of 100000 items, 10000-digit numbers
+  unicode   NUL bytes, control characters, bidi overrides, emoji, noncharacters...
+  nesting   Objects and arrays nested 1000 and 5000 deep
+
+A server may refuse any input, with an isError result or, when the
+arguments do not match the schema, with error -32602 (invalid params). The
+findings are:
+
+  crash           The server exited or the connection failed
+  protocol_error  Any other JSON-RPC error, where a result was expected
+  timeout         No response within --timeout, though the server answers ping
+  hang            No response within --timeout, and no answer to ping either
+
+The server is started again after a crash or hang. Each finding is
+minimized, by dropping properties and halving strings, arrays and nesting
+while the call still fails the same way, and given as a tool call command
+line that reproduces it; arguments too long for a command line are written
+to a file in --repro-dir.
+
+The tools are really called: fuzz a server you can afford to break, never
+one with production data. --all skips tools annotated as destructive; name
+them to fuzz them. The command exits non-zero when there are findings.
+
+Examples:
+  mcp-tui "node server.js" fuzz search
+  mcp-tui "node server.js" fuzz --all --category boundary --category type
+  mcp-tui --url http://localhost:8080/mcp fuzz --all --timeout 5s --format json > findings.json`,
+		RunE: fc.run,
+	}
+
+	cmd.Flags().BoolVar(&fc.all, "all", false, "Fuzz every tool the server lists, except destructive ones")
+	cmd.Flags().StringSliceVar(&fc.categories, "category", nil, "Only generate inputs of these categories (repeatable)")
+	cmd.Flags().IntVar(&fc.maxCases, "max-cases", 0, "Try at most this many inputs per tool")
+	cmd.Flags().BoolVar(&fc.noMinimize, "no-minimize", false, "Report findings with the inputs that caused them, without minimizing")
+	cmd.Flags().StringVar(&fc.reproDir, "repro-dir", ".", "Directory for the arguments of reproducers too long for a command line")
+
+	return cmd
+}
+
+func (fc *FuzzCommand) run(cmd *cobra.Command, args []string) error {
+	if fc.all == (len(args) > 0) {
+		return fmt.Errorf("name the tools to fuzz, or give --all")
+	}
+	var categories []fuzz.Category
+	for _, name := range fc.categories {
+		category, err := fuzz.ParseCategory(name)
+		if err != nil {
+			return err
+		}
+		categories = append(categories, category)
+	}
+	if fc.maxCases < 0 {
+		return fmt.Errorf("--max-cases must not be negative")
+	}
+
+	if err := fc.SetOutputFormat(cmd); err != nil {
+		return err
+	}
+	connConfig, err := fc.connectionConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timeout := conformance.DefaultTimeout
+	if cmd.Flags().Changed("timeout") {
+		timeout, _ = cmd.Flags().GetDuration("timeout")
+	}
+	dial, transport, err := conformance.NewDialer(ctx, connConfig, timeout)
+	if err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+
+	porcelainMode, _ := cmd.Flags().GetBool("porcelain")
+	showProgress := !porcelainMode && !fc.StructuredOutput()
+	if showProgress {
+		fmt.Fprintf(os.Stderr, "🎲 Fuzzing %s over %s...\n", describeUpstream(connConfig), transport)
+	}
+	fuzzer := &fuzz.Fuzzer{
+		Dial:       dial,
+		Timeout:    timeout,
+		Categories: categories,
+		MaxCases:   fc.maxCases,
+		NoMinimize: fc.noMinimize,
+	}
+	if showProgress {
+		fuzzer.Progress = func(tool string, cases int) {
+			fmt.Fprintf(os.Stderr, "   %s: %s\n", tool, pluralize(cases, "case"))
+		}
+	}
+
+	report, runErr := fuzzer.Run(ctx, args)
+	if runErr != nil && !report.Interrupted {
+		cmd.SilenceUsage = true
+		if len(report.Tools) == 0 {
+			return runErr
+		}
+		// A server that cannot be restarted ends the run; report what ran
+		fmt.Fprintf(os.Stderr, "⚠️  Fuzzing stopped: %v\n", runErr)
+	}
+
+	prefix := reproducerPrefix(cmd, connConfig)
+	for i := range report.Findings {
+		reproducer, err := fuzz.Reproducer(prefix, fc.reproDir, i+1, report.Findings[i])
+		if err != nil {
+			return err
+		}
+		report.Findings[i].Reproducer = reproducer
+	}
+
+	if fc.StructuredOutput() {
+		err = fc.Render(document{Data: report, Items: report.Findings, Columns: []string{"tool", "kind", "input", "reproducer"}})
+	} else {
+		err = fuzz.WriteText(fc.output, report)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case report.Summary.Findings > 0:
+		cmd.SilenceUsage = true
+		return fmt.Errorf("fuzzing found %s", pluralize(report.Summary.Findings, "failure"))
+	case runErr != nil && !errors.Is(runErr, context.Canceled):
+		return runErr
+	}
+	return nil
+}
+
+// reproducerPrefix returns the start of a command line that connects to the
+// server as this one did
+func reproducerPrefix(cmd *cobra.Command, connConfig *config.ConnectionConfig) string {
+	words := []string{"mcp-tui"}
+	if connConfig.Type == config.TransportStdio {
+		words = append(words, fuzz.ShellQuote(describeUpstream(connConfig)))
+	} else {
+		if cmd.Flags().Changed("transport") {
+			transport, _ := cmd.Flags().GetString("transport")
+			words = append(words, "--transport", fuzz.ShellQuote(transport))
+		}
+		words = append(words, "--url", fuzz.ShellQuote(connConfig.URL))
+	}
+	return strings.Join(words, " ")
+}
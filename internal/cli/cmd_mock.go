@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp/mock"
+)
+
+// MockCommand runs a mock MCP server from a definition file
+type MockCommand struct {
+	httpAddr string
+}
+
+// NewMockCommand creates a new mock command
+func NewMockCommand() *MockCommand {
+	return &MockCommand{}
+}
+
+// CreateCommand creates the cobra command
+func (c *MockCommand) CreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mock <definition.yaml|definition.json>",
+		Short: "Run a mock MCP server from a YAML or JSON definition",
+		Long: `Run a configurable MCP server for tests and demos.
+
+The definition declares tools (input schemas plus canned or templated
+responses), resources, prompts, notifications to emit, delays and error
+injection. See examples/mock-server.yaml for the format.
+
+The server speaks stdio by default, so it can be launched like any other server:
+  mcp-tui "mcp-tui mock examples/mock-server.yaml" tool list
+
+Use --http to serve the streamable HTTP transport instead:
+  mcp-tui mock examples/mock-server.yaml --http :8080
+  mcp-tui --url http://localhost:8080 tool list`,
+		Args: cobra.ExactArgs(1),
+		RunE: c.RunE,
+	}
+
+	cmd.Flags().StringVar(&c.httpAddr, "http", "", "Serve streamable HTTP on this address (e.g. :8080, which listens on 127.0.0.1) instead of stdio")
+
+	return cmd
+}
+
+// RunE executes the mock command
+func (c *MockCommand) RunE(cmd *cobra.Command, args []string) error {
+	def, err := mock.LoadDefinition(args[0])
+	if err != nil {
+		return err
+	}
+	server := mock.NewServer(def)
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if c.httpAddr == "" {
+		// stdout carries the protocol, so only stderr is used for messages
+		return server.ServeStream(ctx, os.Stdin, os.Stdout)
+	}
+
+	listener, err := listenLocal(c.httpAddr)
+	if err != nil {
+		return err
+	}
+	httpServer := &http.Server{
+		Handler:           server.HTTPHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "🧪 Mock MCP server %q listening on http://%s\n", def.Server.Name, listener.Addr())
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("mock server failed: %w", err)
+	}
+	return nil
+}
@@ -53,6 +53,41 @@ func validatePromptArgument(key, value string) error {
 	return nil
 }
 
+// parsePromptArguments adds key=value arguments to args. A value of @file is
+// read from the file, which may be longer than a value typed in; "@@" starts
+// a literal "@".
+func parsePromptArguments(args map[string]string, arguments []string, readFile func(string) ([]byte, error)) error {
+	for _, arg := range arguments {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Errorf("invalid argument format: %s (expected key=value)", arg)
+		}
+
+		if strings.HasPrefix(value, "@@") {
+			value = value[1:]
+		} else if name, ok := strings.CutPrefix(value, "@"); ok {
+			data, err := readFile(name)
+			if err != nil {
+				return fmt.Errorf("argument %s: %w", key, err)
+			}
+			if err := validatePromptArgument(key, ""); err != nil {
+				return fmt.Errorf("invalid argument %s: %w", key, err)
+			}
+			if !utf8.Valid(data) {
+				return fmt.Errorf("argument %s: file %s is not valid UTF-8", key, name)
+			}
+			args[key] = string(data)
+			continue
+		}
+
+		if err := validatePromptArgument(key, value); err != nil {
+			return fmt.Errorf("invalid argument %s: %w", key, err)
+		}
+		args[key] = value
+	}
+	return nil
+}
+
 // NewPromptCommand creates a new prompt command
 func NewPromptCommand() *PromptCommand {
 	return &PromptCommand{
@@ -69,7 +104,7 @@ func (pc *PromptCommand) CreateCommand() *cobra.Command {
 	}
 
 	// Add output format flag to all subcommands
-	cmd.PersistentFlags().StringP("output", "o", "text", "Output format (text, json)")
+	cmd.PersistentFlags().StringP("output", "o", "text", "Output format (text, json, yaml, table, csv, ndjson)")
 
 	// Add subcommands
 	cmd.AddCommand(pc.createListCommand())
@@ -111,10 +146,17 @@ func (pc *PromptCommand) createGetCommand() *cobra.Command {
 // createExecuteCommand creates the prompt execute command
 func (pc *PromptCommand) createExecuteCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:      "execute <prompt-name>",
-		Aliases:  []string{"exec", "run"},
-		Short:    "Execute a prompt",
-		Long:     "Execute a prompt with optional arguments",
+		Use:     "execute <prompt-name> [key=value...]",
+		Aliases: []string{"exec", "run"},
+		Short:   "Execute a prompt",
+		Long: `Execute a prompt with optional arguments.
+
+Arguments are given as key=value pairs, or with --arg. A value of @file is
+read from the file (use @@ for a literal @).
+
+Examples:
+  prompt execute greeting name=World
+  prompt execute review code=@main.go`,
 		Args:     cobra.MinimumNArgs(1),
 		PreRunE:  pc.PreRunE,
 		PostRunE: pc.PostRunE,
@@ -156,20 +198,16 @@ func (pc *PromptCommand) runListCommand(cmd *cobra.Command, args []string) error
 		fmt.Fprintf(os.Stderr, "✅ Prompts retrieved successfully\n\n")
 	}
 
-	// Handle JSON output format
-	if pc.GetOutputFormat() == OutputFormatJSON {
-		outputData := map[string]interface{}{
-			"prompts": prompts,
-			"count":   len(prompts),
-		}
-
-		jsonBytes, err := json.MarshalIndent(outputData, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal prompts to JSON: %w", err)
-		}
-
-		fmt.Println(string(jsonBytes))
-		return nil
+	// Handle structured output formats
+	if pc.StructuredOutput() {
+		return pc.Render(document{
+			Data: map[string]interface{}{
+				"prompts": prompts,
+				"count":   len(prompts),
+			},
+			Items:   prompts,
+			Columns: []string{"name", "description"},
+		})
 	}
 
 	// Text output format
@@ -278,15 +316,9 @@ func (pc *PromptCommand) runGetCommand(cmd *cobra.Command, args []string) error
 		fmt.Fprintf(os.Stderr, "✅ Prompt retrieved successfully\n\n")
 	}
 
-	// Handle JSON output format
-	if pc.GetOutputFormat() == OutputFormatJSON {
-		jsonBytes, err := json.MarshalIndent(prompt, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal prompt to JSON: %w", err)
-		}
-
-		fmt.Println(string(jsonBytes))
-		return nil
+	// Handle structured output formats
+	if pc.StructuredOutput() {
+		return pc.Render(document{Data: prompt, Columns: []string{"name", "description"}})
 	}
 
 	// Text output format
@@ -356,6 +388,12 @@ func (pc *PromptCommand) runExecuteCommand(cmd *cobra.Command, args []string) er
 			return fmt.Errorf("invalid argument %s: %w", key, err)
 		}
 	}
+	if promptArgs == nil {
+		promptArgs = make(map[string]string)
+	}
+	if err := parsePromptArguments(promptArgs, args[1:], os.ReadFile); err != nil {
+		return err
+	}
 
 	if err := pc.ValidateConnection(); err != nil {
 		return pc.HandleError(err, "validate connection")
@@ -393,15 +431,9 @@ func (pc *PromptCommand) runExecuteCommand(cmd *cobra.Command, args []string) er
 		fmt.Fprintf(os.Stderr, "✅ Prompt executed successfully\n\n")
 	}
 
-	// Handle JSON output format
-	if pc.GetOutputFormat() == OutputFormatJSON {
-		jsonBytes, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal result to JSON: %w", err)
-		}
-
-		fmt.Println(string(jsonBytes))
-		return nil
+	// Handle structured output formats
+	if pc.StructuredOutput() {
+		return pc.Render(document{Data: result, Columns: []string{"description", "messages"}})
 	}
 
 	// Text output format
@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/proxy"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
+)
+
+// ProxyCommand relays a third-party MCP client to a real server, capturing the traffic
+type ProxyCommand struct {
+	listen         string
+	allowedOrigins []string
+	monitorPath    string
+	noMonitor      bool
+}
+
+// NewProxyCommand creates a new proxy command
+func NewProxyCommand() *ProxyCommand {
+	return &ProxyCommand{}
+}
+
+// CreateCommand creates the cobra command
+func (c *ProxyCommand) CreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proxy [--listen stdio|http://host:port] -- <server command> [args...]",
+		Short: "Relay an MCP client to a server and capture the traffic",
+		Long: `Sit between any MCP client (Claude Desktop, VS Code, ...) and a real
+server, relaying every message while capturing it in the MCP logger and
+event tracer.
+
+By default the proxy speaks stdio, so it replaces the server command in the
+client's configuration:
+  "command": "mcp-tui", "args": ["proxy", "--", "node", "server.js"]
+
+With --listen http://host:port it serves the streamable HTTP transport
+instead, giving each client session its own upstream connection:
+  mcp-tui proxy --listen http://:8080 -- node server.js
+Without a host it listens on 127.0.0.1 only; give one, such as 0.0.0.0, to
+accept clients from the network. Browser pages are served only from
+loopback origins and those given with --allow-origin.
+
+The upstream may also be a URL:
+  mcp-tui proxy -- http://localhost:8000/mcp
+
+Watch the live traffic from another terminal with:
+  mcp-tui attach`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: c.RunE,
+	}
+
+	cmd.Flags().StringVar(&c.listen, "listen", "stdio", "Where clients connect: stdio or http://host:port")
+	cmd.Flags().StringSliceVar(&c.allowedOrigins, "allow-origin", nil, "Browser origins to serve over HTTP besides loopback ones, e.g. https://app.example.com")
+	cmd.Flags().StringVar(&c.monitorPath, "monitor", "", "Monitor socket for 'mcp-tui attach' (default: per-process socket in a private temp directory)")
+	cmd.Flags().BoolVar(&c.noMonitor, "no-monitor", false, "Do not serve the traffic to attached viewers")
+
+	return cmd
+}
+
+// RunE executes the proxy command
+func (c *ProxyCommand) RunE(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var addr string
+	if c.listen != "stdio" {
+		listenURL, err := url.Parse(c.listen)
+		if err != nil || listenURL.Scheme != "http" || listenURL.Host == "" {
+			return fmt.Errorf("invalid --listen %q: use stdio or http://host:port", c.listen)
+		}
+		addr = listenURL.Host
+	}
+
+	upstream, err := parseUpstream(cmd, args)
+	if err != nil {
+		return err
+	}
+	newUpstream, err := upstreamTransports(ctx, upstream)
+	if err != nil {
+		return err
+	}
+	p := proxy.New(newUpstream)
+
+	if !c.noMonitor {
+		path := c.monitorPath
+		if path == "" {
+			if path, err = proxy.DefaultMonitorPath(); err != nil {
+				return err
+			}
+		}
+		monitor, err := proxy.ListenMonitor(path)
+		if err != nil {
+			return err
+		}
+		defer monitor.Close()
+		// stdout may carry the protocol, so only stderr is used for messages
+		fmt.Fprintf(os.Stderr, "🔍 Proxy traffic available to: mcp-tui attach %s\n", monitor.Path())
+	}
+
+	if addr == "" {
+		return p.ServeStream(ctx, os.Stdin, os.Stdout)
+	}
+	handler := p.HTTPHandler()
+	handler.AllowedOrigins = c.allowedOrigins
+	return serveHTTP(ctx, addr, handler, handler)
+}
+
+// parseUpstream builds the connection to the real server from the arguments
+// after "--" and the session flags
+func parseUpstream(cmd *cobra.Command, args []string) (*config.ConnectionConfig, error) {
+	var upstream *config.ConnectionConfig
+	if len(args) == 1 {
+		// A single argument may be a URL or a quoted command line
+		if upstream = config.ParseConnectionString(args[0]); upstream == nil {
+			return nil, fmt.Errorf("no upstream server given")
+		}
+	} else {
+		upstream = &config.ConnectionConfig{Type: config.TransportStdio, Command: args[0], Args: args[1:]}
+	}
+	if upstream.Type == config.TransportStdio {
+		if err := config.ValidateCommand(upstream.Command, upstream.Args); err != nil {
+			return nil, fmt.Errorf("command validation failed: %w", err)
+		}
+	}
+
+	if recordPath, _ := cmd.Flags().GetString("record"); recordPath != "" {
+		upstream.RecordPath = recordPath
+	}
+	if chaosPath, _ := cmd.Flags().GetString("chaos"); chaosPath != "" {
+		upstream.ChaosPath = chaosPath
+	}
+	return upstream, nil
+}
+
+// upstreamTransports resolves the upstream's transport once and returns a
+// constructor for a fresh transport per client connection
+func upstreamTransports(ctx context.Context, upstream *config.ConnectionConfig) (proxy.Upstream, error) {
+	transportConfig := transports.FromConnectionConfig(upstream, true, 30*time.Second)
+	if transportConfig.Type == transports.TransportAuto {
+		negotiated, err := transports.NegotiateTransport(ctx, transportConfig, nil)
+		if err != nil {
+			return nil, fmt.Errorf("transport negotiation failed: %w", err)
+		}
+		transportConfig.Type = negotiated
+	}
+	factory := transports.NewFactory()
+	if err := factory.ValidateConfig(transportConfig); err != nil {
+		return nil, fmt.Errorf("invalid upstream: %w", err)
+	}
+	return func() (officialMCP.Transport, error) {
+		transport, _, err := factory.CreateTransport(transportConfig)
+		return transport, err
+	}, nil
+}
+
+// serveHTTP serves handler on addr until ctx is cancelled, then closes the
+// sessions held by closers before shutting the server down
+func serveHTTP(ctx context.Context, addr string, handler http.Handler, closers ...io.Closer) error {
+	listener, err := listenLocal(addr)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		for _, closer := range closers {
+			closer.Close()
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "🔀 Listening on http://%s\n", listener.Addr())
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server failed: %w", err)
+	}
+	// Let open sessions finish closing their upstream connections
+	<-stopped
+	return nil
+}
+
+// listenLocal listens on addr, on the loopback interface when addr names no
+// host, so that a server is not reachable from the network unless asked
+func listenLocal(addr string) (net.Listener, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen: %w", err)
+	}
+	return listener, nil
+}
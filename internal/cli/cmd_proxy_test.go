@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListenLocal(t *testing.T) {
+	// Without a host only the loopback interface is used
+	listener, err := listenLocal(":0")
+	require.NoError(t, err)
+	defer listener.Close()
+	assert.True(t, listener.Addr().(*net.TCPAddr).IP.IsLoopback(), listener.Addr().String())
+
+	listener, err = listenLocal("127.0.0.1:0")
+	require.NoError(t, err)
+	listener.Close()
+
+	_, err = listenLocal("8080")
+	assert.Error(t, err)
+}
@@ -2,7 +2,6 @@ package cli
 
 import (
 	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -32,7 +31,8 @@ func (rc *ResourceCommand) CreateCommand() *cobra.Command {
 	}
 
 	// Add format flag to all subcommands
-	cmd.PersistentFlags().StringP("format", "f", "text", "Output format (text, json)")
+	cmd.PersistentFlags().StringP("format", "f", "text", "Output format (text, json, yaml, table, csv, ndjson)")
+	cmd.PersistentFlags().StringSlice("columns", nil, "Columns shown by the table and csv formats (e.g. uri,name)")
 	cmd.PersistentFlags().Bool("porcelain", false, "Machine-readable output (disables progress messages)")
 
 	// Add subcommands
@@ -102,20 +102,16 @@ func (rc *ResourceCommand) runListCommand(cmd *cobra.Command, args []string) err
 		fmt.Fprintf(os.Stderr, "✅ Resources retrieved successfully\n\n")
 	}
 
-	// Handle JSON output format
-	if rc.GetOutputFormat() == OutputFormatJSON {
-		outputData := map[string]interface{}{
-			"resources": resources,
-			"count":     len(resources),
-		}
-
-		jsonBytes, err := json.MarshalIndent(outputData, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal resources to JSON: %w", err)
-		}
-
-		fmt.Println(string(jsonBytes))
-		return nil
+	// Handle structured output formats
+	if rc.StructuredOutput() {
+		return rc.Render(document{
+			Data: map[string]interface{}{
+				"resources": resources,
+				"count":     len(resources),
+			},
+			Items:   resources,
+			Columns: []string{"uri", "name", "mimeType"},
+		})
 	}
 
 	// Text output format
@@ -210,21 +206,16 @@ func (rc *ResourceCommand) runGetCommand(cmd *cobra.Command, args []string) erro
 		fmt.Fprintf(os.Stderr, "✅ Resource read successfully\n\n")
 	}
 
-	// Handle JSON output format
-	if rc.GetOutputFormat() == OutputFormatJSON {
-		outputData := map[string]interface{}{
-			"uri":      resourceURI,
-			"contents": contents,
-			"count":    len(contents),
-		}
-
-		jsonBytes, err := json.MarshalIndent(outputData, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal resource to JSON: %w", err)
-		}
-
-		fmt.Println(string(jsonBytes))
-		return nil
+	// Handle structured output formats
+	if rc.StructuredOutput() {
+		return rc.Render(document{
+			Data: map[string]interface{}{
+				"uri":      resourceURI,
+				"contents": contents,
+				"count":    len(contents),
+			},
+			Columns: []string{"uri", "count", "contents"},
+		})
 	}
 
 	// Text output format
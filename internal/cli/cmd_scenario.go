@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/scenario"
+)
+
+// ScenarioCommand runs test scenarios against MCP servers
+type ScenarioCommand struct {
+	*BaseCommand
+	report     string
+	reportFile string
+	vars       []string
+}
+
+// NewScenarioCommand creates a new test command
+func NewScenarioCommand() *ScenarioCommand {
+	return &ScenarioCommand{
+		BaseCommand: NewBaseCommand(),
+	}
+}
+
+// CreateCommand creates the cobra command
+func (sc *ScenarioCommand) CreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test <scenario.yaml>...",
+		Short: "Run test scenarios against an MCP server",
+		Long: `Run YAML or JSON test scenarios against MCP servers, for CI.
+
+A scenario lists steps, each one operation with assertions on its result:
+
+  name: echo server
+  server: node server.js
+  steps:
+    - list: tools
+      expect:
+        - {path: "[.tools[].name]", equals: [echo]}
+    - call: {tool: echo, arguments: {message: hi}}
+      expect:
+        - {path: ".content[0].text", matches: "^echo: hi$"}
+        - {latency: 500ms}
+
+Steps are connect, list (tools, resources or prompts), call, read, prompt and
+wait (for a notification). Assertions check the value a jq-like path selects
+(equals, matches, schema), isError, the latency of the step, or the error it
+must fail with. Values captured from a result with capture: {name: path} are
+used later as ${name}, and ${env.NAME} reads the environment. Setup failures
+skip the steps, a failed step skips the rest, and teardown always runs.
+
+The server comes from the scenario's server field, otherwise from the
+connection given on the command line. See examples/scenario.yaml.
+
+--format and --query apply to the JSON document of the run, a list of
+scenarios with their steps; tables and CSV show a row per step. They replace
+the --report formats, so the two cannot be combined.
+
+Examples:
+  mcp-tui test examples/scenario.yaml
+  mcp-tui "node server.js" test tests/*.yaml --report junit --report-file report.xml
+  mcp-tui test smoke.yaml --var city=Paris --report tap
+  mcp-tui test tests/*.yaml --query '.scenarios[] | select(.status == "failed") | .name'`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: sc.run,
+	}
+
+	cmd.Flags().StringVar(&sc.report, "report", "text", "Report format (text, junit, tap)")
+	cmd.Flags().StringVar(&sc.reportFile, "report-file", "", "Write the report to a file instead of stdout")
+	cmd.Flags().StringArrayVar(&sc.vars, "var", nil, "Set a scenario variable, e.g. --var city=Paris (repeatable)")
+
+	return cmd
+}
+
+func (sc *ScenarioCommand) run(cmd *cobra.Command, args []string) error {
+	format, err := scenario.ParseFormat(sc.report)
+	if err != nil {
+		return err
+	}
+	if err := sc.SetOutputFormat(cmd); err != nil {
+		return err
+	}
+	if sc.StructuredOutput() && cmd.Flags().Changed("report") {
+		return fmt.Errorf("--report %s cannot be combined with --format or --query", sc.report)
+	}
+	vars := make(map[string]any)
+	for _, v := range sc.vars {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid --var %q: expected name=value", v)
+		}
+		vars[name] = value
+	}
+
+	// Load every scenario first so a mistake is reported before anything runs
+	scenarios := make([]*scenario.Scenario, len(args))
+	for i, path := range args {
+		if scenarios[i], err = scenario.Load(path); err != nil {
+			return err
+		}
+	}
+
+	debugMode, _ := cmd.Flags().GetBool("debug")
+	runner := &scenario.Runner{
+		Resolve: func(server string) (*config.ConnectionConfig, error) {
+			if server == "" {
+				// The arguments are scenario files, so only a connection
+				// given before the command or with --cmd or --url counts
+				cmdFlag, _ := cmd.Flags().GetString("cmd")
+				urlFlag, _ := cmd.Flags().GetString("url")
+				if sc.getGlobalConnection() == nil && cmdFlag == "" && urlFlag == "" {
+					return nil, fmt.Errorf("no server to connect to: set server in the scenario or give a connection, e.g. mcp-tui \"node server.js\" test scenario.yaml")
+				}
+				return sc.connectionConfig(cmd)
+			}
+			connConfig := config.ParseConnectionString(server)
+			ApplyConnectionOptions(cmd, connConfig)
+			return connConfig, nil
+		},
+		NewService: func() mcp.Service {
+			service := mcp.NewService()
+			service.SetDebugMode(debugMode)
+			return service
+		},
+		Vars: vars,
+	}
+	if cmd.Flags().Changed("timeout") {
+		runner.Timeout, _ = cmd.Flags().GetDuration("timeout")
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	porcelainMode, _ := cmd.Flags().GetBool("porcelain")
+	var results []*scenario.Result
+	failed := 0
+	for _, s := range scenarios {
+		if !porcelainMode && (format != scenario.FormatText || sc.reportFile != "" || sc.StructuredOutput()) {
+			fmt.Fprintf(os.Stderr, "🧪 Running %s\n", s.Name)
+		}
+		result := runner.Run(ctx, s)
+		if result.Failed() {
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	if sc.reportFile != "" {
+		f, err := os.Create(sc.reportFile)
+		if err != nil {
+			return fmt.Errorf("failed to create report: %w", err)
+		}
+		defer f.Close()
+		sc.output = f
+	}
+	if sc.StructuredOutput() {
+		report := scenario.NewReport(results)
+		err = sc.Render(document{Data: report, Items: report.Steps(), Columns: []string{"scenario", "name", "status", "durationMs"}})
+	} else {
+		err = scenario.Write(sc.output, format, results)
+	}
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d scenarios failed", failed, len(results))
+	}
+	return nil
+}
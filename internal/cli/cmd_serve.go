@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/proxy"
+)
+
+// ServeCommand bridges an MCP server to another transport
+type ServeCommand struct {
+	httpAddr       string
+	path           string
+	sse            bool
+	shared         bool
+	allowedOrigins []string
+}
+
+// NewServeCommand creates a new serve command
+func NewServeCommand() *ServeCommand {
+	return &ServeCommand{}
+}
+
+// CreateCommand creates the cobra command
+func (c *ServeCommand) CreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve [--http :8080] -- <server command or URL>",
+		Short: "Expose a stdio server over HTTP, or a remote server over stdio",
+		Long: `Bridge an MCP server to another transport.
+
+With --http, a stdio server is exposed over the streamable HTTP transport,
+so colleagues and containers can reach a locally built server:
+  mcp-tui serve --http 0.0.0.0:8080 -- npx some-stdio-server
+An address without a host, such as :8080, listens on 127.0.0.1 only.
+Browser pages are served only from loopback origins and those given with
+--allow-origin.
+
+Each client session gets its own server process unless --shared is given,
+in which case every session is multiplexed onto one process. --sse also
+serves the legacy HTTP+SSE transport at /sse for older clients.
+
+Without --http, the server is presented over stdio instead, which lets
+clients that only speak stdio use a remote HTTP server:
+  mcp-tui serve -- https://example.com/mcp
+
+Stopping the bridge closes every session and ends the server processes.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: c.RunE,
+	}
+
+	cmd.Flags().StringVar(&c.httpAddr, "http", "", "Serve streamable HTTP on this address, e.g. :8080 (default: serve stdio)")
+	cmd.Flags().StringVar(&c.path, "path", "/mcp", "URL path of the streamable HTTP endpoint")
+	cmd.Flags().BoolVar(&c.sse, "sse", false, "Also serve the legacy HTTP+SSE transport at /sse")
+	cmd.Flags().BoolVar(&c.shared, "shared", false, "Share one server process between all sessions")
+	cmd.Flags().StringSliceVar(&c.allowedOrigins, "allow-origin", nil, "Browser origins to serve besides loopback ones, e.g. https://app.example.com")
+
+	return cmd
+}
+
+// RunE executes the serve command
+func (c *ServeCommand) RunE(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if c.httpAddr == "" && (c.sse || c.shared) {
+		return fmt.Errorf("--sse and --shared require --http")
+	}
+
+	upstream, err := parseUpstream(cmd, args)
+	if err != nil {
+		return err
+	}
+	if c.httpAddr == "" && upstream.Type == config.TransportStdio {
+		return fmt.Errorf("the server already speaks stdio: use --http to expose it over HTTP")
+	}
+	newUpstream, err := upstreamTransports(ctx, upstream)
+	if err != nil {
+		return err
+	}
+
+	if c.httpAddr == "" {
+		return proxy.New(newUpstream).ServeStream(ctx, os.Stdin, os.Stdout)
+	}
+
+	var closers []io.Closer
+	if c.shared {
+		shared := proxy.NewShared(newUpstream)
+		newUpstream = shared.Upstream()
+		// Sessions close before the shared process, so it is closed last
+		defer shared.Close()
+	}
+	p := proxy.New(newUpstream)
+
+	mux := http.NewServeMux()
+	streamable := p.HTTPHandler()
+	streamable.AllowedOrigins = c.allowedOrigins
+	mux.Handle(c.path, streamable)
+	closers = append(closers, streamable)
+	if c.sse {
+		sse := p.SSEHandler()
+		sse.AllowedOrigins = c.allowedOrigins
+		mux.Handle("/sse", sse)
+		closers = append(closers, sse)
+	}
+
+	addr := strings.TrimPrefix(c.httpAddr, "http://")
+	fmt.Fprintf(os.Stderr, "🌉 Serving %s at %s", describeUpstream(upstream), c.path)
+	if c.sse {
+		fmt.Fprint(os.Stderr, " (legacy SSE at /sse)")
+	}
+	fmt.Fprintln(os.Stderr)
+	return serveHTTP(ctx, addr, mux, closers...)
+}
+
+// describeUpstream names the upstream server for messages
+func describeUpstream(upstream *config.ConnectionConfig) string {
+	if upstream.Type == config.TransportStdio {
+		return strings.Join(append([]string{upstream.Command}, upstream.Args...), " ")
+	}
+	return upstream.URL
+}
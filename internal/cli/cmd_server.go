@@ -38,16 +38,72 @@ func (c *ServerCommand) CreateCommand() *cobra.Command {
 
 // RunE executes the server command
 func (c *ServerCommand) RunE(cmd *cobra.Command, args []string) error {
-	fmt.Fprintf(os.Stderr, "📊 Gathering server information...\n")
+	// Progress messages are only for text output outside porcelain mode
+	porcelainMode, _ := cmd.Flags().GetBool("porcelain")
+	showProgress := c.GetOutputFormat() == OutputFormatText && !porcelainMode
+	progress := func(format string, args ...interface{}) {
+		if showProgress {
+			fmt.Fprintf(os.Stderr, format, args...)
+		}
+	}
+
+	progress("📊 Gathering server information...\n")
 
 	info := c.service.GetServerInfo()
 
 	if !info.Connected {
-		fmt.Fprintf(os.Stderr, "❌ Not connected to MCP server\n")
+		progress("❌ Not connected to MCP server\n")
 		return fmt.Errorf("not connected to MCP server - use 'mcp-tui' to start the TUI and connect to a server, or specify connection parameters with --cmd, --url, etc.")
 	}
 
-	fmt.Fprintf(os.Stderr, "✅ Connected to server\n\n")
+	progress("✅ Connected to server\n\n")
+
+	// Get the available items
+	ctx, cancel := c.WithContext()
+	defer cancel()
+
+	progress("📋 Querying available features...\n")
+
+	progress("  • Fetching tools...\n")
+	tools, toolsErr := c.service.ListTools(ctx)
+	progress("  • Fetching resources...\n")
+	resources, resourcesErr := c.service.ListResources(ctx)
+	progress("  • Fetching prompts...\n")
+	prompts, promptsErr := c.service.ListPrompts(ctx)
+
+	// Handle structured output formats
+	if c.StructuredOutput() {
+		outputData := map[string]interface{}{
+			"name":            info.Name,
+			"version":         info.Version,
+			"protocolVersion": info.ProtocolVersion,
+			"capabilities":    info.Capabilities,
+		}
+		errors := make(map[string]string)
+		if toolsErr == nil {
+			outputData["tools"] = len(tools)
+		} else {
+			errors["tools"] = toolsErr.Error()
+		}
+		if resourcesErr == nil {
+			outputData["resources"] = len(resources)
+		} else {
+			errors["resources"] = resourcesErr.Error()
+		}
+		if promptsErr == nil {
+			outputData["prompts"] = len(prompts)
+		} else {
+			errors["prompts"] = promptsErr.Error()
+		}
+		if len(errors) > 0 {
+			outputData["errors"] = errors
+		}
+
+		return c.Render(document{
+			Data:    outputData,
+			Columns: []string{"name", "version", "protocolVersion"},
+		})
+	}
 
 	// Print server information
 	fmt.Printf("Server Information\n")
@@ -72,16 +128,8 @@ func (c *ServerCommand) RunE(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Printf("\n")
 
-	// Get counts of available items
-	ctx, cancel := c.WithContext()
-	defer cancel()
-
-	fmt.Fprintf(os.Stderr, "📋 Querying available features...\n")
-
-	// Count tools
-	fmt.Fprintf(os.Stderr, "  • Fetching tools...\n")
-	tools, err := c.service.ListTools(ctx)
-	if err == nil {
+	// Counts of available items
+	if toolsErr == nil {
 		fmt.Printf("Available Tools:     %d\n", len(tools))
 		if len(tools) > 0 && len(tools) <= 5 {
 			// Show tool names if there are only a few
@@ -90,13 +138,10 @@ func (c *ServerCommand) RunE(cmd *cobra.Command, args []string) error {
 			}
 		}
 	} else {
-		fmt.Printf("Available Tools:     Error: %v\n", err)
+		fmt.Printf("Available Tools:     Error: %v\n", toolsErr)
 	}
 
-	// Count resources
-	fmt.Fprintf(os.Stderr, "  • Fetching resources...\n")
-	resources, err := c.service.ListResources(ctx)
-	if err == nil {
+	if resourcesErr == nil {
 		fmt.Printf("Available Resources: %d\n", len(resources))
 		if len(resources) > 0 && len(resources) <= 5 {
 			// Show resource names if there are only a few
@@ -105,13 +150,10 @@ func (c *ServerCommand) RunE(cmd *cobra.Command, args []string) error {
 			}
 		}
 	} else {
-		fmt.Printf("Available Resources: Error: %v\n", err)
+		fmt.Printf("Available Resources: Error: %v\n", resourcesErr)
 	}
 
-	// Count prompts
-	fmt.Fprintf(os.Stderr, "  • Fetching prompts...\n")
-	prompts, err := c.service.ListPrompts(ctx)
-	if err == nil {
+	if promptsErr == nil {
 		fmt.Printf("Available Prompts:   %d\n", len(prompts))
 		if len(prompts) > 0 && len(prompts) <= 5 {
 			// Show prompt names if there are only a few
@@ -120,10 +162,10 @@ func (c *ServerCommand) RunE(cmd *cobra.Command, args []string) error {
 			}
 		}
 	} else {
-		fmt.Printf("Available Prompts:   Error: %v\n", err)
+		fmt.Printf("Available Prompts:   Error: %v\n", promptsErr)
 	}
 
-	fmt.Fprintf(os.Stderr, "\n✅ Server information complete\n")
+	progress("\n✅ Server information complete\n")
 
 	return nil
 }
@@ -0,0 +1,500 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/query"
+)
+
+// errExitShell is returned by a shell command that ends the shell
+var errExitShell = errors.New("exit")
+
+// shellCommands are the commands the shell accepts, for help and completion
+var shellCommands = []struct{ name, usage, help string }{
+	{"tools", "tools", "List the server's tools"},
+	{"describe", "describe <tool>", "Show a tool and its input schema"},
+	{"call", "call <tool> [key=value | key:=json | key=@file | {json}...]", "Call a tool"},
+	{"resources", "resources", "List the server's resources"},
+	{"read", "read <uri>", "Read a resource"},
+	{"prompts", "prompts", "List the server's prompts"},
+	{"prompt", "prompt <name> [key=value | key=@file...]", "Get a prompt with arguments"},
+	{"server", "server", "Show the server's information"},
+	{"events", "events [count]", "Show the latest notifications from the server (default 20)"},
+	{"set", "set [timeout|format|columns|query] [value]", "Show or change a setting; no value resets it"},
+	{"refresh", "refresh", "Reload the names used for tab completion"},
+	{"history", "history", "Show the command history"},
+	{"help", "help", "Show this help"},
+	{"exit", "exit", "Leave the shell (also quit or Ctrl+D)"},
+}
+
+// shellSettings are the settings changed with set, applied to every command
+type shellSettings struct {
+	timeout time.Duration
+	format  string
+	columns []string
+	query   string
+}
+
+// ShellCommand keeps one connection open and runs commands typed at a prompt
+type ShellCommand struct {
+	*BaseCommand
+	editor      *lineEditor
+	interactive bool
+	settings    shellSettings
+
+	// Names for tab completion
+	tools     []mcp.Tool
+	resources []mcp.Resource
+	prompts   []mcp.Prompt
+}
+
+// NewShellCommand creates a new shell command
+func NewShellCommand() *ShellCommand {
+	return &ShellCommand{
+		BaseCommand: NewBaseCommand(),
+	}
+}
+
+// CreateCommand creates the cobra command for the shell
+func (sc *ShellCommand) CreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Run commands against one connection at an interactive prompt",
+		Long: `Connect once and run commands at a prompt, keeping the server running
+between them.
+
+Commands:
+` + shellHelp() + `
+Arguments take the same forms as 'tool call'. A JSON value may span several
+lines, and so may a line ending with a backslash. Tab completes commands,
+tool, resource and prompt names, argument names and file names after @.
+History is kept across sessions.
+
+When input is not a terminal, commands are read one per line and the shell
+stops at the first that fails.
+
+Examples:
+  mcp-tui "node server.js" shell
+  echo 'call echo message=hi' | mcp-tui "node server.js" shell`,
+		Args:     cobra.NoArgs,
+		PreRunE:  sc.PreRunE,
+		PostRunE: sc.PostRunE,
+		RunE:     sc.run,
+	}
+
+	cmd.Flags().String("history-file", "", "Shell history file (default ~/.config/mcp-tui/shell_history)")
+
+	return cmd
+}
+
+// shellHelp lists the shell commands
+func shellHelp() string {
+	var b strings.Builder
+	for _, c := range shellCommands {
+		fmt.Fprintf(&b, "  %-58s %s\n", c.usage, c.help)
+	}
+	return b.String()
+}
+
+// historyPath returns the history file to use: file if set, otherwise
+// shell_history next to the saved connections
+func historyPath(file string) string {
+	if file != "" {
+		return file
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".config", "mcp-tui", "shell_history")
+}
+
+func (sc *ShellCommand) run(cmd *cobra.Command, args []string) error {
+	if err := sc.ValidateConnection(); err != nil {
+		return sc.HandleError(err, "validate connection")
+	}
+
+	sc.settings = shellSettings{timeout: sc.timeout, format: string(sc.outputFormat), columns: sc.columns}
+	if sc.query != nil {
+		sc.settings.query = sc.query.String()
+	}
+
+	sc.editor = newLineEditor(os.Stdin, os.Stdout)
+	sc.editor.complete = sc.complete
+	sc.interactive = sc.editor.terminal
+	if sc.interactive {
+		historyFile, _ := cmd.Flags().GetString("history-file")
+		if err := sc.editor.loadHistory(historyPath(historyFile)); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		}
+	}
+	sc.refresh()
+
+	info := sc.service.GetServerInfo()
+	if sc.interactive {
+		fmt.Printf("Connected to %s %s. Type help for commands, exit to leave.\n", info.Name, info.Version)
+	}
+	prompt := "mcp> "
+	if info.Name != "" {
+		prompt = info.Name + "> "
+	}
+
+	for {
+		text, err := sc.readCommand(prompt)
+		if errors.Is(err, errInterrupted) {
+			continue
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := sc.editor.addHistory(text); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  %v\n", err)
+		}
+
+		err = sc.execute(text)
+		if errors.Is(err, errExitShell) {
+			return nil
+		}
+		if err != nil {
+			if !sc.interactive {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	}
+}
+
+// readCommand reads a command, which continues over further lines while a
+// quote or bracket is open or a line ends with a backslash
+func (sc *ShellCommand) readCommand(prompt string) (string, error) {
+	var lines []string
+	for {
+		line, err := sc.editor.readLine(prompt)
+		if err == io.EOF && len(lines) > 0 {
+			return "", fmt.Errorf("input ended inside a command: %s", lines[0])
+		}
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, line)
+
+		text := strings.Join(lines, "\n")
+		if _, err := splitWords(text); !errors.Is(err, errIncomplete) {
+			return text, nil
+		}
+		prompt = "... "
+	}
+}
+
+// execute runs one shell command
+func (sc *ShellCommand) execute(text string) error {
+	words, err := splitWords(text)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return nil
+	}
+
+	name, rest := words[0], words[1:]
+	switch name {
+	case "exit", "quit":
+		return errExitShell
+	case "help", "?":
+		fmt.Print(shellHelp())
+		return nil
+	case "history":
+		for i, entry := range sc.editor.history {
+			fmt.Printf("%5d  %s\n", i+1, entry)
+		}
+		return nil
+	case "refresh":
+		sc.refresh()
+		fmt.Printf("%d tools, %d resources, %d prompts\n", len(sc.tools), len(sc.resources), len(sc.prompts))
+		return nil
+	case "events":
+		return sc.showEvents(rest)
+	case "set":
+		return sc.set(text, rest)
+
+	case "tools":
+		return sc.runCLI([]string{"tool", "list"}, rest)
+	case "describe":
+		return sc.runCLI([]string{"tool", "describe"}, rest)
+	case "call":
+		arguments, err := objectArguments(rest, true)
+		if err != nil {
+			return err
+		}
+		return sc.runCLI([]string{"tool", "call"}, arguments)
+	case "resources":
+		return sc.runCLI([]string{"resource", "list"}, rest)
+	case "read":
+		return sc.runCLI([]string{"resource", "get"}, rest)
+	case "prompts":
+		return sc.runCLI([]string{"prompt", "list"}, rest)
+	case "prompt":
+		if len(rest) > 0 && isPromptSubcommand(rest[0]) {
+			return sc.runCLI([]string{"prompt", rest[0]}, rest[1:])
+		}
+		arguments, err := objectArguments(rest, false)
+		if err != nil {
+			return err
+		}
+		return sc.runCLI([]string{"prompt", "execute"}, arguments)
+	case "server", "info":
+		return sc.runCLI([]string{"server"}, rest)
+	case "tool", "resource":
+		return sc.runCLI([]string{name}, rest)
+	}
+	return fmt.Errorf("unknown command %q (type help for the commands)", name)
+}
+
+func isPromptSubcommand(word string) bool {
+	switch word {
+	case "list", "get", "execute", "exec", "run":
+		return true
+	}
+	return false
+}
+
+// runCLI runs a CLI command against the shell's connection, with the
+// shell's settings given as flags before the user's arguments so that
+// flags on the line override them
+func (sc *ShellCommand) runCLI(path, args []string) error {
+	root := &cobra.Command{Use: "mcp-tui", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().StringP("format", "f", "text", "Output format (text, json, yaml, table, csv, ndjson)")
+	root.PersistentFlags().StringSlice("columns", nil, "Columns shown by the table and csv formats")
+	root.PersistentFlags().StringP("query", "q", "", "jq-like expression applied to the JSON output")
+	root.PersistentFlags().Bool("porcelain", false, "Machine-readable output (disables progress messages)")
+
+	toolCmd := NewToolCommand()
+	toolCmd.UseService(sc.service).WithTimeout(sc.settings.timeout)
+	resourceCmd := NewResourceCommand()
+	resourceCmd.UseService(sc.service).WithTimeout(sc.settings.timeout)
+	promptCmd := NewPromptCommand()
+	promptCmd.UseService(sc.service).WithTimeout(sc.settings.timeout)
+	serverCmd := NewServerCommand()
+	serverCmd.UseService(sc.service).WithTimeout(sc.settings.timeout)
+	root.AddCommand(toolCmd.CreateCommand(), resourceCmd.CreateCommand(), promptCmd.CreateCommand(), serverCmd.CreateCommand())
+
+	argv := append([]string{}, path...)
+	argv = append(argv, "--porcelain", "--format", sc.settings.format)
+	if len(sc.settings.columns) > 0 {
+		argv = append(argv, "--columns", strings.Join(sc.settings.columns, ","))
+	}
+	if sc.settings.query != "" {
+		argv = append(argv, "--query", sc.settings.query)
+	}
+	argv = append(argv, args...)
+
+	root.SetArgs(argv)
+	return root.Execute()
+}
+
+// showEvents prints the latest notifications the server sent
+func (sc *ShellCommand) showEvents(args []string) error {
+	count := 20
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("events takes a positive count, not %q", args[0])
+		}
+		count = n
+	}
+
+	logger := sc.service.EventLog()
+	if logger == nil {
+		return nil
+	}
+	var events []debug.MCPLogEntry
+	for _, entry := range logger.GetEntries() {
+		if entry.MessageType == debug.MCPMessageNotification || entry.ID == nil {
+			events = append(events, entry)
+		}
+	}
+	if len(events) == 0 {
+		fmt.Println("No events yet")
+		return nil
+	}
+	if len(events) > count {
+		events = events[len(events)-count:]
+	}
+	for _, entry := range events {
+		fmt.Println(entry.String())
+	}
+	return nil
+}
+
+// set shows or changes the settings. The query is taken from the rest of the
+// line as typed, unless it is wholly quoted.
+func (sc *ShellCommand) set(text string, args []string) error {
+	if len(args) == 0 {
+		fmt.Printf("timeout  %s\n", sc.settings.timeout)
+		fmt.Printf("format   %s\n", sc.settings.format)
+		fmt.Printf("columns  %s\n", strings.Join(sc.settings.columns, ","))
+		fmt.Printf("query    %s\n", sc.settings.query)
+		return nil
+	}
+
+	value := ""
+	if len(args) > 1 {
+		value = args[1]
+	}
+	switch args[0] {
+	case "timeout":
+		if value == "" {
+			sc.settings.timeout = sc.timeout
+			return nil
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid timeout %q (e.g. 60s or 2m)", value)
+		}
+		sc.settings.timeout = timeout
+	case "format":
+		if value == "" {
+			sc.settings.format = string(OutputFormatText)
+			return nil
+		}
+		for _, format := range outputFormats {
+			if strings.EqualFold(value, string(format)) {
+				sc.settings.format = string(format)
+				return nil
+			}
+		}
+		return fmt.Errorf("unsupported output format: %s", value)
+	case "columns":
+		sc.settings.columns = nil
+		for _, column := range strings.Split(value, ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				sc.settings.columns = append(sc.settings.columns, column)
+			}
+		}
+	case "query":
+		expr := strings.TrimSpace(text)
+		expr = strings.TrimSpace(strings.TrimPrefix(expr, "set"))
+		expr = strings.TrimSpace(strings.TrimPrefix(expr, "query"))
+		if len(args) == 2 && len(expr) >= 2 && (expr[0] == '\'' || expr[0] == '"') && expr[len(expr)-1] == expr[0] {
+			expr = args[1]
+		}
+		if expr != "" {
+			if _, err := query.Parse(expr); err != nil {
+				return err
+			}
+		}
+		sc.settings.query = expr
+	default:
+		return fmt.Errorf("unknown setting %q (timeout, format, columns or query)", args[0])
+	}
+	return nil
+}
+
+// refresh loads the tool, resource and prompt names for completion. A server
+// may not offer all three, so failures leave the lists empty.
+func (sc *ShellCommand) refresh() {
+	ctx, cancel := sc.WithContext()
+	defer cancel()
+	if tools, err := sc.service.ListTools(ctx); err == nil {
+		sc.tools = tools
+	}
+	if resources, err := sc.service.ListResources(ctx); err == nil {
+		sc.resources = resources
+	}
+	if prompts, err := sc.service.ListPrompts(ctx); err == nil {
+		sc.prompts = prompts
+	}
+}
+
+// complete returns the completions of the word before the cursor
+func (sc *ShellCommand) complete(before string) (string, []string) {
+	words, current, ok := completeWord(before)
+	if !ok {
+		return current, nil
+	}
+	if len(words) == 0 {
+		var names []string
+		for _, c := range shellCommands {
+			names = append(names, c.name)
+		}
+		return current, matching(current, names)
+	}
+
+	// Files after key=@
+	if key, file, ok := strings.Cut(current, "=@"); ok {
+		var candidates []string
+		for _, path := range completePath(file) {
+			candidates = append(candidates, key+"=@"+path)
+		}
+		return current, candidates
+	}
+
+	var names []string
+	switch command := words[0]; {
+	case len(words) == 1 && (command == "call" || command == "describe"):
+		for _, tool := range sc.tools {
+			names = append(names, tool.Name)
+		}
+	case len(words) >= 2 && command == "call":
+		for _, tool := range sc.tools {
+			if tool.Name == words[1] {
+				properties, _ := tool.InputSchema["properties"].(map[string]interface{})
+				names = argumentNames(properties, words[2:])
+			}
+		}
+	case len(words) == 1 && command == "read":
+		for _, resource := range sc.resources {
+			names = append(names, resource.URI)
+		}
+	case len(words) == 1 && command == "prompt":
+		for _, prompt := range sc.prompts {
+			names = append(names, prompt.Name)
+		}
+	case len(words) >= 2 && command == "prompt":
+		for _, prompt := range sc.prompts {
+			if prompt.Name == words[1] {
+				names = argumentNames(prompt.Arguments, words[2:])
+			}
+		}
+	case len(words) == 1 && command == "set":
+		names = []string{"timeout", "format", "columns", "query"}
+	case len(words) == 2 && command == "set" && words[1] == "format":
+		for _, format := range outputFormats {
+			names = append(names, string(format))
+		}
+	case len(words) == 1 && command == "help":
+		for _, c := range shellCommands {
+			names = append(names, c.name)
+		}
+	}
+	return current, matching(current, names)
+}
+
+// argumentNames returns key= for each argument not given yet
+func argumentNames(arguments map[string]interface{}, given []string) []string {
+	used := make(map[string]bool)
+	for _, word := range given {
+		key, _, _ := strings.Cut(word, "=")
+		used[strings.TrimSuffix(key, ":")] = true
+	}
+	var names []string
+	for name := range arguments {
+		if !used[name] {
+			names = append(names, name+"=")
+		}
+	}
+	return names
+}
@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/standardbeagle/mcp-tui/internal/snapshot"
+)
+
+// SnapshotCommand saves golden snapshots of a server's catalog and verifies
+// the server against them
+type SnapshotCommand struct {
+	*BaseCommand
+	masks []string
+}
+
+// NewSnapshotCommand creates a new snapshot command
+func NewSnapshotCommand() *SnapshotCommand {
+	return &SnapshotCommand{
+		BaseCommand: NewBaseCommand(),
+	}
+}
+
+// CreateCommand creates the cobra command
+func (sc *SnapshotCommand) CreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save and verify golden snapshots of a server's catalog",
+		Long: `Capture a server's tools, resources and prompts, and the results of
+scripted tool calls, as normalized JSON files, then verify the live server
+against them. A renamed tool, a changed schema or a reworded description
+shows up as a diff, so refactors cannot change them silently.
+
+A snapshot directory holds:
+
+  server.json              Server name, protocol version and capabilities
+  tools.json               Tools sorted by name
+  resources.json           Resources sorted by URI
+  prompts.json             Prompts sorted by name
+  calls/<name>.json        The result of each scripted call
+  snapshot.yaml            Calls to make and values to mask (optional)
+
+snapshot.yaml lists the calls and the masks, paths to volatile values that
+are replaced with "<masked>" before saving and comparing:
+
+  calls:
+    - {tool: echo, arguments: {message: hi}}
+    - {name: weather-paris, tool: weather, arguments: {city: Paris}}
+  mask:
+    - .calls."weather-paris".content[0].text
+    - ..timestamp
+
+A mask is a --query path that starts at the whole snapshot: .name (or
+."other-chars") selects a key, [N] an element, [] every element or value,
+and ..name a key at any depth. Masks given with --mask when saving are
+added to snapshot.yaml.
+
+Examples:
+  mcp-tui "node server.js" snapshot save testdata/golden
+  mcp-tui "node server.js" snapshot verify testdata/golden
+  mcp-tui --url http://localhost:8080/mcp snapshot save golden --mask '..requestId'`,
+	}
+
+	cmd.PersistentFlags().StringArrayVar(&sc.masks, "mask", nil, "Mask the values at a path, e.g. '..timestamp' (repeatable)")
+
+	cmd.AddCommand(sc.createSaveCommand())
+	cmd.AddCommand(sc.createVerifyCommand())
+
+	return cmd
+}
+
+// createSaveCommand creates the snapshot save command
+func (sc *SnapshotCommand) createSaveCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:      "save <dir>",
+		Short:    "Save a snapshot of the server",
+		Long:     "Capture the server and write the snapshot files to a directory, replacing an earlier snapshot there",
+		Args:     cobra.ExactArgs(1),
+		PreRunE:  sc.preRun,
+		PostRunE: sc.PostRunE,
+		RunE:     sc.runSave,
+	}
+}
+
+// createVerifyCommand creates the snapshot verify command
+func (sc *SnapshotCommand) createVerifyCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:      "verify <dir>",
+		Short:    "Verify the server against a saved snapshot",
+		Long:     "Capture the server and compare it with the snapshot in a directory, printing a diff of every file that differs",
+		Args:     cobra.ExactArgs(1),
+		PreRunE:  sc.preRun,
+		PostRunE: sc.PostRunE,
+		RunE:     sc.runVerify,
+	}
+}
+
+// preRun connects to the server. The argument is the snapshot directory, so
+// only a connection given before the command or with --cmd or --url counts.
+func (sc *SnapshotCommand) preRun(cmd *cobra.Command, args []string) error {
+	cmdFlag, _ := cmd.Flags().GetString("cmd")
+	urlFlag, _ := cmd.Flags().GetString("url")
+	if sc.getGlobalConnection() == nil && cmdFlag == "" && urlFlag == "" {
+		return fmt.Errorf("no server to snapshot: give a connection, e.g. mcp-tui \"node server.js\" snapshot %s %s", cmd.Name(), args[0])
+	}
+	return sc.PreRunE(cmd, args)
+}
+
+// capture loads the directory's configuration, adds the --mask paths and
+// captures the server
+func (sc *SnapshotCommand) capture(cmd *cobra.Command, dir string) (*snapshot.Snapshot, *snapshot.Config, bool, error) {
+	if err := sc.ValidateConnection(); err != nil {
+		return nil, nil, false, sc.HandleError(err, "validate connection")
+	}
+	cfg, err := snapshot.LoadConfig(filepath.Join(dir, snapshot.ConfigFile))
+	if err != nil {
+		return nil, nil, false, err
+	}
+	added, err := cfg.AddMasks(sc.masks)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	porcelainMode, _ := cmd.Flags().GetBool("porcelain")
+	if sc.GetOutputFormat() == OutputFormatText && !porcelainMode {
+		fmt.Fprintf(os.Stderr, "📸 Capturing %s...\n", sc.GetService().GetServerInfo().Name)
+	}
+
+	ctx, cancel := sc.WithContext()
+	defer cancel()
+	snap, err := snapshot.Capture(ctx, sc.GetService(), cfg)
+	if err != nil {
+		return nil, nil, false, sc.HandleError(err, "capture snapshot")
+	}
+	return snap, cfg, added, nil
+}
+
+// runSave executes the snapshot save command
+func (sc *SnapshotCommand) runSave(cmd *cobra.Command, args []string) error {
+	dir := args[0]
+	snap, cfg, added, err := sc.capture(cmd, dir)
+	if err != nil {
+		return err
+	}
+	if err := snap.Save(dir); err != nil {
+		return err
+	}
+	// Record masks given on the command line, so verify applies them too
+	if added {
+		if err := cfg.Save(filepath.Join(dir, snapshot.ConfigFile)); err != nil {
+			return err
+		}
+	}
+
+	files := snap.Names()
+	if sc.StructuredOutput() {
+		return sc.Render(document{
+			Data:  map[string]interface{}{"dir": dir, "files": files, "count": len(files)},
+			Items: files,
+		})
+	}
+	fmt.Fprintf(sc.output, "Saved %s to %s:\n", pluralize(len(files), "file"), dir)
+	for _, name := range files {
+		fmt.Fprintf(sc.output, "  %s\n", name)
+	}
+	if added {
+		fmt.Fprintf(sc.output, "Recorded the masks in %s\n", filepath.Join(dir, snapshot.ConfigFile))
+	}
+	return nil
+}
+
+// runVerify executes the snapshot verify command
+func (sc *SnapshotCommand) runVerify(cmd *cobra.Command, args []string) error {
+	dir := args[0]
+	saved, err := snapshot.Load(dir)
+	if err != nil {
+		return err
+	}
+	live, _, _, err := sc.capture(cmd, dir)
+	if err != nil {
+		return err
+	}
+	diffs := snapshot.Compare(saved, live)
+
+	if sc.StructuredOutput() {
+		err = sc.Render(document{
+			Data:    map[string]interface{}{"dir": dir, "matches": len(diffs) == 0, "files": diffs, "count": len(diffs)},
+			Items:   diffs,
+			Columns: []string{"file", "change"},
+		})
+	} else {
+		err = sc.writeDiffs(dir, saved, diffs)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(diffs) > 0 {
+		files := len(saved.Names())
+		for _, diff := range diffs {
+			if diff.Change == snapshot.Added {
+				files++
+			}
+		}
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d snapshot files differ; if the change is intended, run snapshot save %s",
+			len(diffs), files, dir)
+	}
+	return nil
+}
+
+// writeDiffs writes the files that differ and their diffs
+func (sc *SnapshotCommand) writeDiffs(dir string, saved *snapshot.Snapshot, diffs []snapshot.FileDiff) error {
+	if len(diffs) == 0 {
+		_, err := fmt.Fprintf(sc.output, "✅ The server matches the snapshot in %s (%s)\n", dir, pluralize(len(saved.Names()), "file"))
+		return err
+	}
+	var b strings.Builder
+	for _, diff := range diffs {
+		fmt.Fprintf(&b, "✗ %s %s\n", diff.File, diff.Change)
+	}
+	for _, diff := range diffs {
+		b.WriteString("\n" + diff.Diff)
+	}
+	_, err := fmt.Fprint(sc.output, b.String())
+	return err
+}
+
+// pluralize formats a count and a noun
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,339 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/standardbeagle/mcp-tui/internal/query"
+	"gopkg.in/yaml.v3"
+)
+
+// maxTableCell is the widest a table cell is shown; CSV keeps whole values
+const maxTableCell = 60
+
+// document is what a command prints in the structured output formats. Data
+// is the command's JSON document, whose structure scripts depend on. For list
+// commands, Items are its entries: tables and CSV show one row per item and
+// NDJSON writes one line per item. Other commands show Data as a single row,
+// or a single line. Columns are the default table and CSV columns.
+type document struct {
+	Data    interface{}
+	Items   interface{}
+	Columns []string
+}
+
+// Render prints a command's output in the selected structured format, or
+// what the --query expression selects from it
+func (c *BaseCommand) Render(doc document) error {
+	if c.query != nil {
+		results, err := c.query.Run(doc.Data)
+		if err != nil {
+			return err
+		}
+		return renderQueryResults(c.output, c.outputFormat, c.columns, results)
+	}
+	return renderDocument(c.output, c.outputFormat, c.columns, doc)
+}
+
+// renderQueryResults writes the outputs of a query. Text prints strings as
+// they are and other values as JSON, one per output, like jq -r. JSON and
+// YAML print a single output as it is and several as an array. NDJSON writes
+// a line per output, and tables and CSV a row per output, where a single
+// array output gives a row per item and values other than objects are shown
+// in a value column.
+func renderQueryResults(w io.Writer, format OutputFormat, columns []string, results []interface{}) error {
+	switch format {
+	case OutputFormatText:
+		text, err := query.FormatText(results)
+		if err != nil || len(results) == 0 {
+			return err
+		}
+		_, err = fmt.Fprintln(w, text)
+		return err
+
+	case OutputFormatJSON, OutputFormatYAML:
+		var data interface{} = results
+		if len(results) == 1 {
+			data = results[0]
+		} else if results == nil {
+			data = []interface{}{}
+		}
+		return renderDocument(w, format, columns, document{Data: data})
+
+	case OutputFormatNDJSON:
+		if results == nil {
+			return nil
+		}
+		return renderDocument(w, format, columns, document{Items: results})
+
+	default:
+		rows := results
+		if len(results) == 1 {
+			if items, ok := results[0].([]interface{}); ok {
+				rows = items
+			}
+		}
+		// Rows that are not objects are shown in a single value column
+		objects := make([]interface{}, len(rows))
+		for i, row := range rows {
+			if _, ok := row.(map[string]interface{}); ok {
+				objects[i] = row
+			} else {
+				objects[i] = map[string]interface{}{"value": row}
+			}
+		}
+		return renderDocument(w, format, columns, document{Items: objects})
+	}
+}
+
+// renderDocument writes doc to w in format, showing columns in tables and
+// CSV, or the document's default columns when none are given
+func renderDocument(w io.Writer, format OutputFormat, columns []string, doc document) error {
+	switch format {
+	case OutputFormatJSON:
+		data, err := json.MarshalIndent(doc.Data, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal output to JSON: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+
+	case OutputFormatYAML:
+		// Going through JSON keeps the field names and omissions of the JSON output
+		data, err := toJSONValue(doc.Data)
+		if err != nil {
+			return err
+		}
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(yamlNumbers(data)); err != nil {
+			return fmt.Errorf("failed to marshal output to YAML: %w", err)
+		}
+		return encoder.Close()
+
+	case OutputFormatNDJSON:
+		records := []interface{}{doc.Data}
+		if doc.Items != nil {
+			items, err := toJSONValue(doc.Items)
+			if err != nil {
+				return err
+			}
+			records, _ = items.([]interface{})
+		}
+		for _, record := range records {
+			data, err := json.Marshal(record)
+			if err != nil {
+				return fmt.Errorf("failed to marshal output to JSON: %w", err)
+			}
+			if _, err := fmt.Fprintln(w, string(data)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case OutputFormatTable, OutputFormatCSV:
+		rows, err := documentRows(doc)
+		if err != nil {
+			return err
+		}
+		if len(columns) > 0 {
+			if err := checkColumns(rows, columns); err != nil {
+				return err
+			}
+		} else if columns = doc.Columns; len(columns) == 0 {
+			columns = rowKeys(rows)
+		}
+		if format == OutputFormatCSV {
+			return writeCSV(w, columns, rows)
+		}
+		return writeTable(w, columns, rows)
+	}
+	return fmt.Errorf("output format %s has no structured rendering", format)
+}
+
+// toJSONValue converts v to the maps, slices and scalars it marshals to as JSON
+func toJSONValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal output: %w", err)
+	}
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // Keep integers from turning into floats
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to convert output: %w", err)
+	}
+	return value, nil
+}
+
+// yamlNumbers turns the JSON numbers in a value into ints and floats, which
+// YAML writes unquoted
+func yamlNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = yamlNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = yamlNumbers(item)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+	}
+	return value
+}
+
+// documentRows returns the rows tables and CSV show for doc
+func documentRows(doc document) ([]interface{}, error) {
+	if doc.Items == nil {
+		data, err := toJSONValue(doc.Data)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{data}, nil
+	}
+	items, err := toJSONValue(doc.Items)
+	if err != nil {
+		return nil, err
+	}
+	rows, _ := items.([]interface{})
+	return rows, nil
+}
+
+// rowKeys returns the keys found in any row, sorted
+func rowKeys(rows []interface{}) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, row := range rows {
+		object, _ := row.(map[string]interface{})
+		for key := range object {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkColumns rejects a chosen column no row has, most likely a typo
+func checkColumns(rows []interface{}, columns []string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	for _, column := range columns {
+		found := false
+		for _, row := range rows {
+			if _, ok := lookupColumn(row, column); ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown column %q (available: %s)", column, strings.Join(rowKeys(rows), ", "))
+		}
+	}
+	return nil
+}
+
+// lookupColumn returns the value of a column of a row. A column may be a
+// dotted path into nested objects and arrays, e.g. "result.content.0.text".
+func lookupColumn(row interface{}, column string) (interface{}, bool) {
+	value := row
+	for _, key := range strings.Split(column, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// formatCell renders a value as a single cell: scalars as they are, and
+// objects and arrays as compact JSON
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func writeCSV(w io.Writer, columns []string, rows []interface{}) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			value, _ := lookupColumn(row, column)
+			record[i] = formatCell(value)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeTable(w io.Writer, columns []string, rows []interface{}) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.ToUpper(column)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			value, _ := lookupColumn(row, column)
+			// Keep each row on one line and within a readable width
+			cell := strings.Join(strings.Fields(formatCell(value)), " ")
+			if runes := []rune(cell); len(runes) > maxTableCell {
+				cell = string(runes[:maxTableCell-1]) + "…"
+			}
+			cells[i] = cell
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/query"
+)
+
+func TestRenderDocument(t *testing.T) {
+	tools := []mcp.Tool{
+		{Name: "search", Description: "Search files\nby name", InputSchema: map[string]interface{}{"type": "object"}},
+		{Name: "read", Description: "Read a file, whole"},
+	}
+	list := document{
+		Data:    map[string]interface{}{"tools": tools, "count": len(tools)},
+		Items:   tools,
+		Columns: []string{"name", "description"},
+	}
+
+	tests := []struct {
+		name    string
+		format  OutputFormat
+		columns []string
+		doc     document
+		want    string
+	}{
+		{
+			name:   "table",
+			format: OutputFormatTable,
+			doc:    list,
+			want: "NAME    DESCRIPTION\n" +
+				"search  Search files by name\n" +
+				"read    Read a file, whole\n",
+		},
+		{
+			name:    "table with chosen columns",
+			format:  OutputFormatTable,
+			columns: []string{"name", "inputSchema.type"},
+			doc:     list,
+			want: "NAME    INPUTSCHEMA.TYPE\n" +
+				"search  object\n" +
+				"read    \n",
+		},
+		{
+			name:   "csv",
+			format: OutputFormatCSV,
+			doc:    list,
+			want:   "name,description\nsearch,\"Search files\nby name\"\nread,\"Read a file, whole\"\n",
+		},
+		{
+			name:   "ndjson writes an item per line",
+			format: OutputFormatNDJSON,
+			doc:    list,
+			want: `{"description":"Search files\nby name","inputSchema":{"type":"object"},"name":"search"}` + "\n" +
+				`{"description":"Read a file, whole","name":"read"}` + "\n",
+		},
+		{
+			name:   "yaml",
+			format: OutputFormatYAML,
+			doc:    document{Data: map[string]interface{}{"count": 2, "ratio": 0.5, "name": "x"}},
+			want:   "count: 2\nname: x\nratio: 0.5\n",
+		},
+		{
+			name:   "json",
+			format: OutputFormatJSON,
+			doc:    document{Data: map[string]interface{}{"count": 2}},
+			want:   "{\n  \"count\": 2\n}\n",
+		},
+		{
+			name:   "single results are one row",
+			format: OutputFormatCSV,
+			doc: document{
+				Data:    map[string]interface{}{"tool": "echo", "result": mcp.CallToolResult{Content: []mcp.Content{{Type: "text", Text: "hi"}}}},
+				Columns: []string{"tool", "result.isError", "result.content"},
+			},
+			want: "tool,result.isError,result.content\necho,,\"[{\"\"text\"\":\"\"hi\"\",\"\"type\"\":\"\"text\"\"}]\"\n",
+		},
+		{
+			name:   "ndjson writes single results on one line",
+			format: OutputFormatNDJSON,
+			doc:    document{Data: map[string]interface{}{"uri": "file:///a", "count": 1}},
+			want:   `{"count":1,"uri":"file:///a"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := renderDocument(&buf, tt.format, tt.columns, tt.doc); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+
+	err := renderDocument(&bytes.Buffer{}, OutputFormatTable, []string{"nmae"}, list)
+	if err == nil || !strings.Contains(err.Error(), `unknown column "nmae"`) {
+		t.Errorf("error = %v, want an unknown column error", err)
+	}
+}
+
+func TestRenderWithQuery(t *testing.T) {
+	result := mcp.CallToolResult{Content: []mcp.Content{{Type: "text", Text: `{"items": [{"id": 1, "status": "open"}, {"id": 2, "status": "closed"}]}`}}}
+	doc := document{Data: map[string]interface{}{"tool": "issues", "result": result}}
+
+	tests := []struct {
+		name   string
+		format OutputFormat
+		query  string
+		want   string
+	}{
+		{
+			name:   "text prints strings as they are",
+			format: OutputFormatText,
+			query:  ".tool",
+			want:   "issues\n",
+		},
+		{
+			name:   "text content is JSON",
+			format: OutputFormatText,
+			query:  `.result.content[0].text | fromjson | .items[] | select(.status=="open") | .id`,
+			want:   "1\n",
+		},
+		{
+			name:   "json wraps several results in an array",
+			format: OutputFormatJSON,
+			query:  ".result.content[0].text.items[].id",
+			want:   "[\n  1,\n  2\n]\n",
+		},
+		{
+			name:   "ndjson writes a line per result",
+			format: OutputFormatNDJSON,
+			query:  ".result.content[0].text.items[]",
+			want:   `{"id":1,"status":"open"}` + "\n" + `{"id":2,"status":"closed"}` + "\n",
+		},
+		{
+			name:   "a single array result is a row per item",
+			format: OutputFormatCSV,
+			query:  ".result.content[0].text.items",
+			want:   "id,status\n1,open\n2,closed\n",
+		},
+		{
+			name:   "other values are a value column",
+			format: OutputFormatCSV,
+			query:  ".result.content[0].text.items[].status",
+			want:   "value\nopen\nclosed\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			c := NewBaseCommand()
+			c.output = &buf
+			c.outputFormat = tt.format
+			q, err := query.Parse(tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			c.query = q
+			if err := c.Render(doc); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,349 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// maxHistory is how many entries the shell history keeps
+const maxHistory = 1000
+
+// errInterrupted is returned by readLine when Ctrl+C discards the line
+var errInterrupted = errors.New("interrupted")
+
+// completer returns the word before the cursor that is being completed and
+// the words it may complete to
+type completer func(before string) (word string, candidates []string)
+
+// lineEditor reads lines with editing, history and tab completion when its
+// input is a terminal, and plain lines otherwise
+type lineEditor struct {
+	reader   *bufio.Reader
+	out      io.Writer
+	fd       int
+	terminal bool
+
+	history     []string
+	historyFile string // Where entries are appended; "" keeps them in memory
+	complete    completer
+}
+
+// newLineEditor creates an editor reading from in. Editing is enabled only
+// when in is a terminal.
+func newLineEditor(in *os.File, out io.Writer) *lineEditor {
+	fd := int(in.Fd())
+	return &lineEditor{
+		reader:   bufio.NewReader(in),
+		out:      out,
+		fd:       fd,
+		terminal: isTerminal(fd),
+	}
+}
+
+// loadHistory reads the history file, one entry per line, keeping the most
+// recent entries
+func (e *lineEditor) loadHistory(path string) error {
+	e.historyFile = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read shell history: %w", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			e.history = append(e.history, line)
+		}
+	}
+	if len(e.history) > maxHistory {
+		// Rewrite the file so it does not grow without bound
+		e.history = e.history[len(e.history)-maxHistory:]
+		content := strings.Join(e.history, "\n") + "\n"
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			return fmt.Errorf("failed to trim shell history: %w", err)
+		}
+	}
+	return nil
+}
+
+// addHistory records an entry, appending it to the history file. Lines of a
+// multi-line entry are joined with spaces.
+func (e *lineEditor) addHistory(entry string) error {
+	entry = strings.TrimSpace(strings.ReplaceAll(entry, "\n", " "))
+	if entry == "" || len(e.history) > 0 && e.history[len(e.history)-1] == entry {
+		return nil
+	}
+	e.history = append(e.history, entry)
+	if len(e.history) > maxHistory {
+		e.history = e.history[1:]
+	}
+
+	if e.historyFile == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(e.historyFile), 0o700); err != nil {
+		return fmt.Errorf("failed to save shell history: %w", err)
+	}
+	f, err := os.OpenFile(e.historyFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to save shell history: %w", err)
+	}
+	defer f.Close()
+	if _, err := fmt.Fprintln(f, entry); err != nil {
+		return fmt.Errorf("failed to save shell history: %w", err)
+	}
+	return nil
+}
+
+// readLine reads a line after showing prompt. It returns io.EOF at the end of
+// the input or on Ctrl+D, and errInterrupted on Ctrl+C.
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	if !e.terminal {
+		return e.readPlainLine()
+	}
+
+	state, err := makeRaw(e.fd)
+	if err != nil {
+		fmt.Fprint(e.out, prompt)
+		return e.readPlainLine()
+	}
+	defer restoreTerminal(e.fd, state)
+	return e.edit(prompt)
+}
+
+func (e *lineEditor) readPlainLine() (string, error) {
+	line, err := e.reader.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// editState is the line being edited
+type editState struct {
+	prompt  string
+	buf     []rune
+	pos     int
+	history int    // Index of the history entry shown; len(history) is the new line
+	pending string // The new line, kept while browsing history
+}
+
+// edit runs the editing loop over keys read in raw mode
+func (e *lineEditor) edit(prompt string) (string, error) {
+	s := &editState{prompt: prompt, history: len(e.history)}
+	e.refresh(s)
+
+	for {
+		r, _, err := e.reader.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(s.buf), nil
+		case 3: // Ctrl+C
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errInterrupted
+		case 4: // Ctrl+D
+			if len(s.buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			s.deleteAt(s.pos)
+		case 1: // Ctrl+A
+			s.pos = 0
+		case 5: // Ctrl+E
+			s.pos = len(s.buf)
+		case 2: // Ctrl+B
+			s.move(-1)
+		case 6: // Ctrl+F
+			s.move(1)
+		case 8, 127: // Backspace
+			if s.pos > 0 {
+				s.pos--
+				s.deleteAt(s.pos)
+			}
+		case 11: // Ctrl+K
+			s.buf = s.buf[:s.pos]
+		case 21: // Ctrl+U
+			s.buf = append([]rune{}, s.buf[s.pos:]...)
+			s.pos = 0
+		case 23: // Ctrl+W
+			start := s.wordStart()
+			s.buf = append(s.buf[:start], s.buf[s.pos:]...)
+			s.pos = start
+		case 12: // Ctrl+L
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case 16: // Ctrl+P
+			e.browseHistory(s, -1)
+		case 14: // Ctrl+N
+			e.browseHistory(s, 1)
+		case '\t':
+			e.completeWord(s)
+		case 27:
+			e.escape(s)
+		default:
+			if unicode.IsPrint(r) {
+				s.buf = append(s.buf[:s.pos], append([]rune{r}, s.buf[s.pos:]...)...)
+				s.pos++
+			}
+		}
+		e.refresh(s)
+	}
+}
+
+// escape handles the escape sequences of arrow, Home, End and Delete keys,
+// and Alt+B and Alt+F word movement
+func (e *lineEditor) escape(s *editState) {
+	r, _, err := e.reader.ReadRune()
+	if err != nil {
+		return
+	}
+	switch r {
+	case 'b':
+		s.pos = s.wordStart()
+		return
+	case 'f':
+		for s.pos < len(s.buf) && unicode.IsSpace(s.buf[s.pos]) {
+			s.pos++
+		}
+		for s.pos < len(s.buf) && !unicode.IsSpace(s.buf[s.pos]) {
+			s.pos++
+		}
+		return
+	case '[', 'O':
+	default:
+		return
+	}
+
+	// CSI: parameters, then a final letter or ~
+	var params strings.Builder
+	for {
+		r, _, err = e.reader.ReadRune()
+		if err != nil {
+			return
+		}
+		if r >= '0' && r <= '9' || r == ';' {
+			params.WriteRune(r)
+			continue
+		}
+		break
+	}
+
+	switch {
+	case r == 'A':
+		e.browseHistory(s, -1)
+	case r == 'B':
+		e.browseHistory(s, 1)
+	case r == 'C':
+		s.move(1)
+	case r == 'D':
+		s.move(-1)
+	case r == 'H' || r == '~' && (params.String() == "1" || params.String() == "7"):
+		s.pos = 0
+	case r == 'F' || r == '~' && (params.String() == "4" || params.String() == "8"):
+		s.pos = len(s.buf)
+	case r == '~' && params.String() == "3":
+		s.deleteAt(s.pos)
+	}
+}
+
+// browseHistory shows the previous (-1) or next (1) history entry
+func (e *lineEditor) browseHistory(s *editState, step int) {
+	next := s.history + step
+	if next < 0 || next > len(e.history) {
+		return
+	}
+	if s.history == len(e.history) {
+		s.pending = string(s.buf)
+	}
+	s.history = next
+	if next == len(e.history) {
+		s.buf = []rune(s.pending)
+	} else {
+		s.buf = []rune(e.history[next])
+	}
+	s.pos = len(s.buf)
+}
+
+// completeWord completes the word before the cursor. A single match is filled
+// in, several are extended to their common prefix or listed when that adds
+// nothing.
+func (e *lineEditor) completeWord(s *editState) {
+	if e.complete == nil {
+		return
+	}
+	word, candidates := e.complete(string(s.buf[:s.pos]))
+	if len(candidates) == 0 {
+		fmt.Fprint(e.out, "\a")
+		return
+	}
+
+	completion := commonPrefix(candidates)
+	if len(candidates) == 1 && !strings.HasSuffix(completion, "=") && !strings.HasSuffix(completion, "/") {
+		completion += " "
+	}
+	if len([]rune(completion)) > len([]rune(word)) && strings.HasPrefix(completion, word) {
+		insert := []rune(completion[len(word):])
+		s.buf = append(s.buf[:s.pos], append(insert, s.buf[s.pos:]...)...)
+		s.pos += len(insert)
+		return
+	}
+
+	fmt.Fprint(e.out, "\r\n"+strings.Join(candidates, "  ")+"\r\n")
+}
+
+// refresh redraws the prompt and line and places the cursor
+func (e *lineEditor) refresh(s *editState) {
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", s.prompt, string(s.buf))
+	if back := len(s.buf) - s.pos; back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+func (s *editState) move(step int) {
+	if next := s.pos + step; next >= 0 && next <= len(s.buf) {
+		s.pos = next
+	}
+}
+
+func (s *editState) deleteAt(i int) {
+	if i < len(s.buf) {
+		s.buf = append(s.buf[:i], s.buf[i+1:]...)
+	}
+}
+
+// wordStart returns where the word before the cursor starts
+func (s *editState) wordStart() int {
+	i := s.pos
+	for i > 0 && unicode.IsSpace(s.buf[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(s.buf[i-1]) {
+		i--
+	}
+	return i
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
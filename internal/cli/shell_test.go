@@ -0,0 +1,231 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{`call search query="x y"`, []string{"call", "search", "query=x y"}},
+		{`call search query='say "hi"'`, []string{"call", "search", `query=say "hi"`}},
+		{`read file:///a\ b`, []string{"read", "file:///a b"}},
+		{`call search filter:={"status": "open", "tags": ["a b"]}`, []string{"call", "search", `filter:={"status": "open", "tags": ["a b"]}`}},
+		{"call search {\"query\":\n \"x\"}", []string{"call", "search", "{\"query\":\n \"x\"}"}},
+		{`call search {"q": "}"}`, []string{"call", "search", `{"q": "}"}`}},
+		{"tools \\\n-f json", []string{"tools", "-f", "json"}},
+		{`set query .tools[].name`, []string{"set", "query", ".tools[].name"}},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		got, err := splitWords(tt.text)
+		if err != nil {
+			t.Errorf("splitWords(%q) error: %v", tt.text, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitWords(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	for _, text := range []string{`call x q="open`, `call x {"a": 1`, `call x a:=[1,`, "tools \\"} {
+		if _, err := splitWords(text); !errors.Is(err, errIncomplete) {
+			t.Errorf("splitWords(%q) error = %v, want errIncomplete", text, err)
+		}
+	}
+}
+
+func TestObjectArguments(t *testing.T) {
+	got, err := objectArguments([]string{"search", `{"query": "x", "limit": 5}`, "a=b"}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"search", "limit:=5", `query:="x"`, "a=b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("objectArguments = %q, want %q", got, want)
+	}
+
+	got, err = objectArguments([]string{"review", `{"code": "x := 1", "n": 2}`}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = []string{"review", "code=x := 1", "n=2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("objectArguments = %q, want %q", got, want)
+	}
+
+	if _, err := objectArguments([]string{"{nope}"}, true); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func TestShellComplete(t *testing.T) {
+	sc := NewShellCommand()
+	sc.tools = []mcp.Tool{
+		{Name: "search", InputSchema: map[string]interface{}{
+			"properties": map[string]interface{}{"query": map[string]interface{}{}, "limit": map[string]interface{}{}},
+		}},
+		{Name: "send"},
+	}
+	sc.resources = []mcp.Resource{{URI: "file:///a"}, {URI: "file:///b"}}
+	sc.prompts = []mcp.Prompt{{Name: "review", Arguments: map[string]interface{}{"code": nil}}}
+
+	tests := []struct {
+		before string
+		word   string
+		want   []string
+	}{
+		{"to", "to", []string{"tools"}},
+		{"call s", "s", []string{"search", "send"}},
+		{"call search ", "", []string{"limit=", "query="}},
+		{"call search query=x ", "", []string{"limit="}},
+		{"read file:///", "file:///", []string{"file:///a", "file:///b"}},
+		{"prompt review c", "c", []string{"code="}},
+		{"set format y", "y", []string{"yaml"}},
+		{`call search query="x`, "", nil},
+	}
+	for _, tt := range tests {
+		word, got := sc.complete(tt.before)
+		if word != tt.word || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("complete(%q) = %q, %q, want %q, %q", tt.before, word, got, tt.word, tt.want)
+		}
+	}
+}
+
+func TestShellSet(t *testing.T) {
+	sc := NewShellCommand()
+	sc.settings = shellSettings{timeout: sc.timeout, format: "text"}
+
+	run := func(text string) error {
+		words, err := splitWords(text)
+		if err != nil {
+			t.Fatalf("splitWords(%q): %v", text, err)
+		}
+		return sc.set(text, words[1:])
+	}
+
+	if err := run("set timeout 60s"); err != nil || sc.settings.timeout.Seconds() != 60 {
+		t.Errorf("set timeout: err %v, timeout %s", err, sc.settings.timeout)
+	}
+	if err := run("set format JSON"); err != nil || sc.settings.format != "json" {
+		t.Errorf("set format: err %v, format %s", err, sc.settings.format)
+	}
+	if err := run(`set query .tools[] | select(.name == "x")`); err != nil || sc.settings.query != `.tools[] | select(.name == "x")` {
+		t.Errorf("set query: err %v, query %s", err, sc.settings.query)
+	}
+	if err := run(`set query '.a | .b'`); err != nil || sc.settings.query != ".a | .b" {
+		t.Errorf("set quoted query: err %v, query %s", err, sc.settings.query)
+	}
+	if err := run("set query"); err != nil || sc.settings.query != "" {
+		t.Errorf("reset query: err %v, query %s", err, sc.settings.query)
+	}
+
+	for _, text := range []string{"set timeout soon", "set format xml", "set query .[", "set colour red"} {
+		if err := run(text); err == nil {
+			t.Errorf("%s: expected an error", text)
+		}
+	}
+}
+
+func TestLineEditorEdit(t *testing.T) {
+	tests := []struct {
+		name string
+		keys string
+		want string
+	}{
+		{"typing", "tools\r", "tools"},
+		{"backspace", "toolx\x7fs\r", "tools"},
+		{"cursor movement", "ools\x1b[Ht\x1b[F -f json\r", "tools -f json"},
+		{"kill to start", "junk\x15tools\r", "tools"},
+		{"delete word", "call junk\x17echo\r", "call echo"},
+		{"history", "\x1b[A\x1b[A\r", "call echo"},
+		{"history and back", "new\x1b[A\x1b[B\r", "new"},
+		{"completion", "to\t\r", "tools "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &lineEditor{
+				reader:  bufio.NewReader(strings.NewReader(tt.keys)),
+				out:     io.Discard,
+				history: []string{"call echo", "tools"},
+				complete: func(before string) (string, []string) {
+					return before, matching(before, []string{"tools", "call"})
+				},
+			}
+			got, err := e.edit("> ")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("line = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	e := &lineEditor{reader: bufio.NewReader(strings.NewReader("x\x03")), out: io.Discard}
+	if _, err := e.edit("> "); !errors.Is(err, errInterrupted) {
+		t.Errorf("Ctrl+C error = %v, want errInterrupted", err)
+	}
+	e = &lineEditor{reader: bufio.NewReader(strings.NewReader("\x04")), out: io.Discard}
+	if _, err := e.edit("> "); err != io.EOF {
+		t.Errorf("Ctrl+D error = %v, want io.EOF", err)
+	}
+}
+
+func TestLineEditorHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	e := &lineEditor{}
+	if err := e.loadHistory(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, entry := range []string{"tools", "tools", "", "call echo {\n\"message\": \"hi\"}"} {
+		if err := e.addHistory(entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	reloaded := &lineEditor{}
+	if err := reloaded.loadHistory(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"tools", `call echo { "message": "hi"}`}
+	if !reflect.DeepEqual(reloaded.history, want) {
+		t.Errorf("history = %q, want %q", reloaded.history, want)
+	}
+}
+
+func TestParsePromptArguments(t *testing.T) {
+	files := map[string]string{"main.go": "package main\n"}
+	readFile := func(name string) ([]byte, error) {
+		if content, ok := files[name]; ok {
+			return []byte(content), nil
+		}
+		return nil, os.ErrNotExist
+	}
+
+	args := map[string]string{}
+	if err := parsePromptArguments(args, []string{"code=@main.go", "user=@@me", "lang=go"}, readFile); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"code": "package main\n", "user": "@me", "lang": "go"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("arguments = %v, want %v", args, want)
+	}
+
+	for _, arg := range []string{"novalue", "code=@missing.go", "bad key=x"} {
+		if err := parsePromptArguments(map[string]string{}, []string{arg}, readFile); err == nil {
+			t.Errorf("%s: expected an error", arg)
+		}
+	}
+}
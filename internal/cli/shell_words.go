@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// errIncomplete means a shell command continues on the next line
+var errIncomplete = errors.New("unterminated quote or bracket")
+
+// splitWords splits a shell command into words. Single and double quotes
+// group words and are removed, a backslash escapes the next character and
+// ends a line that continues. A JSON object or array starting a word, or
+// following = or :=, is kept whole with its quotes, so it may span several
+// words and lines. errIncomplete is returned while a quote or bracket is open.
+func splitWords(text string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune   // Open quote outside JSON
+	escaped := false // After a backslash
+	depth := 0       // Open brackets of a JSON value
+	inString := false
+
+	for _, r := range text {
+		switch {
+		case depth > 0:
+			word.WriteRune(r)
+			switch {
+			case escaped:
+				escaped = false
+			case inString && r == '\\':
+				escaped = true
+			case r == '"':
+				inString = !inString
+			case inString:
+			case r == '{' || r == '[':
+				depth++
+			case r == '}' || r == ']':
+				depth--
+			}
+
+		case escaped:
+			escaped = false
+			if r != '\n' {
+				word.WriteRune(r)
+				inWord = true
+			}
+
+		case quote != 0:
+			switch {
+			case r == '\\' && quote == '"':
+				escaped = true
+			case r == quote:
+				quote = 0
+			default:
+				word.WriteRune(r)
+			}
+
+		case r == '\\':
+			escaped = true
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case (r == '{' || r == '[') && (word.Len() == 0 || strings.HasSuffix(word.String(), "=")):
+			depth = 1
+			word.WriteRune(r)
+			inWord = true
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if depth > 0 || quote != 0 || escaped {
+		return words, errIncomplete
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// objectArguments expands a JSON object given as a word into key:=value
+// arguments, or key=value ones for prompts, whose arguments are strings
+func objectArguments(words []string, raw bool) ([]string, error) {
+	var out []string
+	for _, word := range words {
+		if !strings.HasPrefix(word, "{") {
+			out = append(out, word)
+			continue
+		}
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(word), &object); err != nil {
+			return nil, fmt.Errorf("arguments object is not valid JSON: %w", err)
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if s, ok := object[key].(string); ok && !raw {
+				out = append(out, key+"="+s)
+				continue
+			}
+			data, err := json.Marshal(object[key])
+			if err != nil {
+				return nil, err
+			}
+			if raw {
+				out = append(out, key+":="+string(data))
+			} else {
+				out = append(out, key+"="+string(data))
+			}
+		}
+	}
+	return out, nil
+}
+
+// completeWord splits the text before the cursor into the words before the
+// one being typed and that word, which is empty after a space
+func completeWord(before string) (words []string, current string, ok bool) {
+	words, err := splitWords(before)
+	if err != nil {
+		return nil, "", false
+	}
+	if before == "" || unicode.IsSpace([]rune(before)[len([]rune(before))-1]) {
+		return words, "", true
+	}
+	return words[:len(words)-1], words[len(words)-1], true
+}
+
+// matching returns the candidates starting with prefix, sorted
+func matching(prefix string, candidates []string) []string {
+	var out []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			out = append(out, candidate)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// completePath completes a file name, adding / to directories
+func completePath(prefix string) []string {
+	dir, base := filepath.Split(prefix)
+	entries, err := os.ReadDir(filepath.Join(".", dir))
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, base) || strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".") {
+			continue
+		}
+		if entry.IsDir() {
+			name += "/"
+		}
+		out = append(out, dir+name)
+	}
+	return out
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 	"github.com/standardbeagle/mcp-tui/internal/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/schema"
 )
 
 // ToolCommand handles tool-related CLI operations
@@ -17,15 +18,13 @@ type ToolCommand struct {
 	*BaseCommand
 }
 
-// validateToolArgument validates a tool argument for security
+// validateToolArgument validates a tool argument for security. The key may be
+// a dotted path into nested objects.
 func validateToolArgument(key, value string) error {
 	// Check for reasonable length limits
 	if len(key) > 1000 {
 		return fmt.Errorf("argument key too long (max 1000 characters)")
 	}
-	if len(value) > 10000 {
-		return fmt.Errorf("argument value too long (max 10000 characters)")
-	}
 
 	// Check for valid UTF-8
 	if !utf8.ValidString(key) {
@@ -36,17 +35,14 @@ func validateToolArgument(key, value string) error {
 	}
 
 	// Check for dangerous characters in key (should be alphanumeric/underscore/dash)
-	for _, r := range key {
-		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == '-') {
-			return fmt.Errorf("argument key contains invalid character: %c", r)
+	for _, segment := range strings.Split(key, ".") {
+		if segment == "" {
+			return fmt.Errorf("argument key has an empty path segment: %q", key)
 		}
-	}
-
-	// If value looks like JSON, validate it's well-formed
-	if strings.HasPrefix(strings.TrimSpace(value), "{") || strings.HasPrefix(strings.TrimSpace(value), "[") {
-		var temp interface{}
-		if err := json.Unmarshal([]byte(value), &temp); err != nil {
-			return fmt.Errorf("argument value appears to be JSON but is malformed: %w", err)
+		for _, r := range segment {
+			if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == '-') {
+				return fmt.Errorf("argument key contains invalid character: %c", r)
+			}
 		}
 	}
 
@@ -69,7 +65,8 @@ func (tc *ToolCommand) CreateCommand() *cobra.Command {
 	}
 
 	// Add format flag to all subcommands
-	cmd.PersistentFlags().StringP("format", "f", "text", "Output format (text, json)")
+	cmd.PersistentFlags().StringP("format", "f", "text", "Output format (text, json, yaml, table, csv, ndjson)")
+	cmd.PersistentFlags().StringSlice("columns", nil, "Columns shown by the table and csv formats (e.g. name,description)")
 	cmd.PersistentFlags().Bool("porcelain", false, "Machine-readable output (disables progress messages)")
 
 	// Add subcommands
@@ -115,8 +112,24 @@ func (tc *ToolCommand) createCallCommand() *cobra.Command {
 		Use:   "call <tool-name> [arguments...]",
 		Short: "Call a tool with arguments",
 		Long: `Call a tool with the provided arguments.
-Arguments should be provided as key=value pairs.
-Example: tool call myTool name=John age=30`,
+
+Arguments are given as key=value pairs. Each value is converted to the type
+the tool's input schema gives it (numbers, booleans, arrays and objects), and
+the arguments are checked against the schema before they are sent.
+
+  key=value         value converted by the schema; arrays may be comma-separated
+  key:=<json>       value given as raw JSON
+  key=@file         value read from a file (use @@ for a literal @)
+  parent.key=value  field of a nested object
+
+--input-json reads a complete arguments object from a file, or from stdin with
+"-"; key=value pairs given as well override its fields.
+
+Examples:
+  tool call myTool name=John age=30
+  tool call search filter.status=open tags=bug,ui limit:=10
+  tool call upload content=@report.md
+  echo '{"query": "x"}' | tool call search --input-json -`,
 		Args:     cobra.MinimumNArgs(1),
 		PreRunE:  tc.PreRunE,
 		PostRunE: tc.PostRunE,
@@ -125,6 +138,9 @@ Example: tool call myTool name=John age=30`,
 		},
 	}
 
+	cmd.Flags().String("input-json", "", "Read the arguments object from a JSON file, or stdin with \"-\"")
+	cmd.Flags().Bool("no-validate", false, "Send the arguments without checking them against the tool's input schema")
+
 	return cmd
 }
 
@@ -157,20 +173,16 @@ func (tc *ToolCommand) handleList(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "✅ Tools retrieved successfully\n\n")
 	}
 
-	// Handle JSON output format
-	if tc.GetOutputFormat() == OutputFormatJSON {
-		outputData := map[string]interface{}{
-			"tools": tools,
-			"count": len(tools),
-		}
-
-		jsonBytes, err := json.MarshalIndent(outputData, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal tools to JSON: %w", err)
-		}
-
-		fmt.Println(string(jsonBytes))
-		return nil
+	// Handle structured output formats
+	if tc.StructuredOutput() {
+		return tc.Render(document{
+			Data: map[string]interface{}{
+				"tools": tools,
+				"count": len(tools),
+			},
+			Items:   tools,
+			Columns: []string{"name", "description"},
+		})
 	}
 
 	// Text output format
@@ -268,15 +280,9 @@ func (tc *ToolCommand) handleDescribe(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("tool '%s' not found", toolName)
 	}
 
-	// Handle JSON output format
-	if tc.GetOutputFormat() == OutputFormatJSON {
-		jsonBytes, err := json.MarshalIndent(foundTool, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal tool to JSON: %w", err)
-		}
-
-		fmt.Println(string(jsonBytes))
-		return nil
+	// Handle structured output formats
+	if tc.StructuredOutput() {
+		return tc.Render(document{Data: foundTool, Columns: []string{"name", "description"}})
 	}
 
 	// Text output format
@@ -341,51 +347,67 @@ func (tc *ToolCommand) handleCall(cmd *cobra.Command, args []string) error {
 	}
 
 	toolName := args[0]
-	toolArgs := make(map[string]interface{})
 
 	// Check if porcelain mode is enabled
 	porcelainMode, _ := cmd.Flags().GetBool("porcelain")
+	showProgress := tc.GetOutputFormat() == OutputFormatText && !porcelainMode
 
 	// Only show progress messages for text output and not porcelain mode
-	if tc.GetOutputFormat() == OutputFormatText && !porcelainMode {
+	if showProgress {
 		fmt.Fprintf(os.Stderr, "🛠️  Preparing to call tool '%s'...\n", toolName)
 	}
 
-	// Parse arguments (key=value pairs)
-	if len(args) > 1 && tc.GetOutputFormat() == OutputFormatText && !porcelainMode {
-		fmt.Fprintf(os.Stderr, "📝 Parsing arguments...\n")
+	ctx, cancel := tc.WithContext()
+	defer cancel()
+
+	// The tool's input schema gives the arguments their types
+	tools, err := tc.GetService().ListTools(ctx)
+	if err != nil {
+		if showProgress {
+			fmt.Fprintf(os.Stderr, "❌ Failed to retrieve tools\n")
+		}
+		return tc.HandleError(err, "list tools")
 	}
-	for _, arg := range args[1:] {
-		parts := strings.SplitN(arg, "=", 2)
-		if len(parts) != 2 {
-			if tc.GetOutputFormat() == OutputFormatText && !porcelainMode {
-				fmt.Fprintf(os.Stderr, "❌ Invalid argument format\n")
-			}
-			return fmt.Errorf("invalid argument format: %s (expected key=value)", arg)
+	var inputSchema map[string]interface{}
+	found := false
+	for _, tool := range tools {
+		if tool.Name == toolName {
+			inputSchema, found = tool.InputSchema, true
+			break
 		}
+	}
+	if !found && showProgress {
+		fmt.Fprintf(os.Stderr, "⚠️  Tool '%s' is not listed by the server; arguments are sent unchecked\n", toolName)
+	}
 
-		key := parts[0]
-		value := parts[1]
-
-		// Validate argument for security
-		if err := validateToolArgument(key, value); err != nil {
-			if tc.GetOutputFormat() == OutputFormatText && !porcelainMode {
-				fmt.Fprintf(os.Stderr, "❌ Invalid argument\n")
-			}
-			return fmt.Errorf("argument validation failed: %w", err)
+	var toolArgs map[string]interface{}
+	if inputJSON, _ := cmd.Flags().GetString("input-json"); inputJSON != "" {
+		if toolArgs, err = readInputJSON(inputJSON, cmd.InOrStdin()); err != nil {
+			return err
 		}
+	}
 
-		// Try to parse as JSON first, then fall back to string
-		var parsedValue interface{}
-		if err := json.Unmarshal([]byte(value), &parsedValue); err != nil {
-			parsedValue = value
+	// Parse arguments (key=value pairs)
+	if len(args) > 1 && showProgress {
+		fmt.Fprintf(os.Stderr, "📝 Parsing arguments...\n")
+	}
+	toolArgs, err = newToolArgumentParser(inputSchema).parse(toolArgs, args[1:])
+	if err != nil {
+		if showProgress {
+			fmt.Fprintf(os.Stderr, "❌ Invalid argument\n")
 		}
-
-		toolArgs[key] = parsedValue
+		return err
 	}
 
-	ctx, cancel := tc.WithContext()
-	defer cancel()
+	// Check the arguments before sending them
+	if noValidate, _ := cmd.Flags().GetBool("no-validate"); found && !noValidate {
+		if violations := schema.Validate(inputSchema, toolArgs); len(violations) > 0 {
+			if showProgress {
+				fmt.Fprintf(os.Stderr, "❌ Arguments do not match the tool's input schema\n")
+			}
+			return schemaViolationsError(toolName, violations)
+		}
+	}
 
 	if tc.GetOutputFormat() == OutputFormatText && !porcelainMode {
 		fmt.Fprintf(os.Stderr, "🚀 Executing tool...\n")
@@ -403,21 +425,16 @@ func (tc *ToolCommand) handleCall(cmd *cobra.Command, args []string) error {
 		return tc.HandleError(err, "call tool")
 	}
 
-	// Handle JSON output format
-	if tc.GetOutputFormat() == OutputFormatJSON {
-		outputData := map[string]interface{}{
-			"tool":      toolName,
-			"arguments": toolArgs,
-			"result":    result,
-		}
-
-		jsonBytes, err := json.MarshalIndent(outputData, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal result to JSON: %w", err)
-		}
-
-		fmt.Println(string(jsonBytes))
-		return nil
+	// Handle structured output formats
+	if tc.StructuredOutput() {
+		return tc.Render(document{
+			Data: map[string]interface{}{
+				"tool":      toolName,
+				"arguments": toolArgs,
+				"result":    result,
+			},
+			Columns: []string{"tool", "result.isError", "result.content"},
+		})
 	}
 
 	// Text output format
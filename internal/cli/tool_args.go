@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp/schema"
+)
+
+// toolArgumentParser builds the arguments of a tool call from command-line
+// arguments, using the tool's input schema to give each value its type
+type toolArgumentParser struct {
+	inputSchema map[string]interface{}
+	readFile    func(name string) ([]byte, error)
+}
+
+// newToolArgumentParser creates a parser for a tool with the given input
+// schema, which may be nil when the tool is not known
+func newToolArgumentParser(inputSchema map[string]interface{}) *toolArgumentParser {
+	return &toolArgumentParser{inputSchema: inputSchema, readFile: os.ReadFile}
+}
+
+// readInputJSON reads a complete arguments object from a file, or from stdin
+// when name is "-"
+func readInputJSON(name string, stdin io.Reader) (map[string]interface{}, error) {
+	var data []byte
+	var err error
+	if name == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input JSON: %w", err)
+	}
+
+	var args map[string]interface{}
+	if err := json.Unmarshal(data, &args); err != nil {
+		return nil, fmt.Errorf("input JSON is not valid: %w", err)
+	}
+	if args == nil {
+		return nil, fmt.Errorf("input JSON must be an object")
+	}
+	return args, nil
+}
+
+// parse adds each argument to args, creating args when it is nil. Arguments
+// take these forms:
+//
+//	key=value        value coerced to the type the schema gives key
+//	key:=<json>      value taken as raw JSON
+//	key=@file        value read from file ("@@" starts a literal "@")
+//	parent.key=value nested objects are created along a dotted path
+//
+// Repeating a key whose schema is an array adds to it; repeating any other key
+// replaces the earlier value.
+func (p *toolArgumentParser) parse(args map[string]interface{}, arguments []string) (map[string]interface{}, error) {
+	if args == nil {
+		args = make(map[string]interface{})
+	}
+	seen := make(map[string]bool)
+
+	for _, arg := range arguments {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid argument format: %s (expected key=value or key:=json)", arg)
+		}
+		key, raw := strings.CutSuffix(key, ":")
+
+		if err := validateToolArgument(key, value); err != nil {
+			return nil, fmt.Errorf("argument validation failed: %w", err)
+		}
+
+		if strings.HasPrefix(value, "@@") {
+			value = value[1:]
+		} else if name, ok := strings.CutPrefix(value, "@"); ok {
+			data, err := p.readFile(name)
+			if err != nil {
+				return nil, fmt.Errorf("argument %s: %w", key, err)
+			}
+			value = string(data)
+			if err := validateToolArgument(key, value); err != nil {
+				return nil, fmt.Errorf("argument %s: file %s: %w", key, name, err)
+			}
+		}
+
+		path := strings.Split(key, ".")
+		property := schema.Property(p.inputSchema, path)
+
+		var parsed interface{}
+		switch {
+		case raw:
+			if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+				return nil, fmt.Errorf("argument %s: value is not valid JSON: %w", key, err)
+			}
+		case property != nil:
+			coerced, err := schema.Coerce(property, value)
+			if err != nil {
+				return nil, fmt.Errorf("argument %s: %w", key, err)
+			}
+			parsed = coerced
+		default:
+			// Without a schema, try JSON first, then fall back to a string
+			trimmed := strings.TrimSpace(value)
+			if err := json.Unmarshal([]byte(value), &parsed); err != nil {
+				if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+					return nil, fmt.Errorf("argument %s: value appears to be JSON but is malformed: %w", key, err)
+				}
+				parsed = value
+			}
+		}
+
+		appendItems := seen[key] && containsType(schema.Types(property), "array")
+		if err := setArgument(args, path, parsed, appendItems); err != nil {
+			return nil, err
+		}
+		seen[key] = true
+	}
+
+	return args, nil
+}
+
+// setArgument stores value at a dotted path, creating the objects on the way.
+// With appendItems, array values are added to the array already there.
+func setArgument(args map[string]interface{}, path []string, value interface{}, appendItems bool) error {
+	object := args
+	for i, key := range path[:len(path)-1] {
+		switch next := object[key].(type) {
+		case map[string]interface{}:
+			object = next
+		case nil:
+			child := make(map[string]interface{})
+			object[key] = child
+			object = child
+		default:
+			return fmt.Errorf("argument %s: %s is not an object", strings.Join(path, "."), strings.Join(path[:i+1], "."))
+		}
+	}
+
+	key := path[len(path)-1]
+	if existing, ok := object[key].([]interface{}); ok && appendItems {
+		if items, ok := value.([]interface{}); ok {
+			object[key] = append(existing, items...)
+		} else {
+			object[key] = append(existing, value)
+		}
+		return nil
+	}
+	object[key] = value
+	return nil
+}
+
+// containsType reports whether types includes t
+func containsType(types []string, t string) bool {
+	for _, candidate := range types {
+		if candidate == t {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaViolationsError reports every place the arguments of a tool call do
+// not match its input schema
+func schemaViolationsError(toolName string, violations []schema.Violation) error {
+	lines := make([]string, len(violations))
+	for i, violation := range violations {
+		lines[i] = "  " + violation.String()
+	}
+	return fmt.Errorf("arguments do not match the input schema of tool '%s':\n%s\n(use --no-validate to send them anyway)",
+		toolName, strings.Join(lines, "\n"))
+}
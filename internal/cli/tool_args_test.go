@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const searchSchema = `{
+	"type": "object",
+	"properties": {
+		"query": {"type": "string"},
+		"limit": {"type": "integer"},
+		"score": {"type": "number"},
+		"exact": {"type": "boolean"},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"ids": {"type": "array", "items": {"type": "integer"}},
+		"filter": {
+			"type": "object",
+			"properties": {"status": {"type": "string"}, "since": {"type": "integer"}}
+		}
+	}
+}`
+
+func TestToolArgumentParser(t *testing.T) {
+	var inputSchema map[string]interface{}
+	if err := json.Unmarshal([]byte(searchSchema), &inputSchema); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string // JSON of the arguments
+		wantErr string
+	}{
+		{
+			name: "coerced by schema",
+			args: []string{"query=42", "limit=10", "score=0.5", "exact=true", "tags=bug,ui", "ids=1,2"},
+			want: `{"exact":true,"ids":[1,2],"limit":10,"query":"42","score":0.5,"tags":["bug","ui"]}`,
+		},
+		{
+			name: "dotted paths",
+			args: []string{"filter.status=open", "filter.since=3"},
+			want: `{"filter":{"since":3,"status":"open"}}`,
+		},
+		{
+			name: "raw JSON",
+			args: []string{`filter:={"status":"closed"}`, "query:=null"},
+			want: `{"filter":{"status":"closed"},"query":null}`,
+		},
+		{
+			name: "repeated array keys add items",
+			args: []string{"tags=a", "tags=b,c", "query=x", "query=y"},
+			want: `{"query":"y","tags":["a","b","c"]}`,
+		},
+		{
+			name: "JSON arrays",
+			args: []string{`tags=["a,b"]`},
+			want: `{"tags":["a,b"]}`,
+		},
+		{
+			name: "unknown keys fall back to JSON then string",
+			args: []string{"extra=5", "other=hello"},
+			want: `{"extra":5,"other":"hello"}`,
+		},
+		{
+			name: "files",
+			args: []string{"query=@query.txt", "filter:=@filter.json", "tags=@@home"},
+			want: `{"filter":{"status":"open"},"query":"find me\n","tags":["@home"]}`,
+		},
+		{
+			name:    "not a number",
+			args:    []string{"limit=ten"},
+			wantErr: `argument limit: expected integer, got "ten"`,
+		},
+		{
+			name:    "malformed raw JSON",
+			args:    []string{"filter:={"},
+			wantErr: "argument filter: value is not valid JSON",
+		},
+		{
+			name:    "missing file",
+			args:    []string{"query=@missing.txt"},
+			wantErr: "argument query: missing.txt",
+		},
+		{
+			name:    "path through a value",
+			args:    []string{"query=x", "query.deep=y"},
+			wantErr: "argument query.deep: query is not an object",
+		},
+		{
+			name:    "empty path segment",
+			args:    []string{"filter..status=x"},
+			wantErr: "empty path segment",
+		},
+		{
+			name:    "no value",
+			args:    []string{"query"},
+			wantErr: "invalid argument format",
+		},
+	}
+
+	files := map[string]string{"query.txt": "find me\n", "filter.json": `{"status": "open"}`}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := newToolArgumentParser(inputSchema)
+			parser.readFile = func(name string) ([]byte, error) {
+				if content, ok := files[name]; ok {
+					return []byte(content), nil
+				}
+				return nil, fmt.Errorf("%s: %w", name, os.ErrNotExist)
+			}
+
+			args, err := parser.parse(nil, tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, _ := json.Marshal(args)
+			if string(got) != tt.want {
+				t.Errorf("arguments = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolArgumentsOverrideInputJSON(t *testing.T) {
+	base, err := readInputJSON("-", strings.NewReader(`{"query": "x", "filter": {"status": "open", "since": 1}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args, err := newToolArgumentParser(nil).parse(base, []string{"filter.status=closed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"query":  "x",
+		"filter": map[string]interface{}{"status": "closed", "since": float64(1)},
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("arguments = %v, want %v", args, want)
+	}
+
+	if _, err := readInputJSON("-", strings.NewReader(`[1, 2]`)); err == nil {
+		t.Error("expected an error for input JSON that is not an object")
+	}
+}
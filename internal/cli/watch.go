@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/standardbeagle/mcp-tui/internal/platform/process"
+	"github.com/standardbeagle/mcp-tui/internal/watch"
+)
+
+// SplitWatchFlag removes every --watch flag from args, returning the watched
+// patterns and the remaining arguments. Arguments after "--" belong to a
+// server command and are left alone.
+func SplitWatchFlag(args []string) (patterns []string, rest []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return patterns, append(rest, args[i:]...)
+		case arg == "--watch" && i+1 < len(args):
+			i++
+			patterns = append(patterns, splitPatterns(args[i])...)
+		case strings.HasPrefix(arg, "--watch="):
+			patterns = append(patterns, splitPatterns(strings.TrimPrefix(arg, "--watch="))...)
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return patterns, rest
+}
+
+// splitPatterns splits a comma-separated --watch value
+func splitPatterns(value string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// RunWatch runs mcp-tui with args, which start their own server, and runs it
+// again whenever the watched files change, stopping a run still in progress.
+// It returns when ctx is done.
+func RunWatch(ctx context.Context, watcher *watch.Watcher, args []string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate mcp-tui: %w", err)
+	}
+
+	changes := watcher.Watch(ctx)
+	fmt.Fprintf(os.Stderr, "👀 Watching %s\n", strings.Join(watcher.Patterns(), ", "))
+	if !watcher.Exists() {
+		fmt.Fprintln(os.Stderr, "⚠️  No files match yet")
+	}
+
+	for {
+		runCtx, cancel := context.WithCancel(ctx)
+		done := startRun(runCtx, executable, args)
+
+		select {
+		case err := <-done:
+			cancel()
+			reportRun(err)
+		case <-ctx.Done():
+			cancel()
+			<-done
+			return nil
+		case paths := <-changes:
+			// The run is still going, e.g. a long tool call: start over
+			cancel()
+			<-done
+			reportChange(paths)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case paths := <-changes:
+			reportChange(paths)
+		}
+	}
+}
+
+// startRun runs mcp-tui once, reporting how it exited on the returned channel
+func startRun(ctx context.Context, executable string, args []string) <-chan error {
+	cmd := exec.CommandContext(ctx, executable, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// Let the run shut its server down before it is killed
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = process.DefaultKillTimeout
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Run()
+	}()
+	return done
+}
+
+// reportRun prints how a run ended
+func reportRun(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Run failed (%v), waiting for changes...\n", err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, "✅ Run finished, waiting for changes...")
+}
+
+// reportChange prints which files triggered a new run
+func reportChange(paths []string) {
+	changed := paths[0]
+	if len(paths) > 1 {
+		changed = fmt.Sprintf("%s and %d more", paths[0], len(paths)-1)
+	}
+	fmt.Fprintf(os.Stderr, "🔁 %s changed, restarting...\n", changed)
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitWatchFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		patterns []string
+		rest     []string
+	}{
+		{
+			name:     "before connection string",
+			args:     []string{"--watch", "src/**/*.go", "go run ./server", "tool", "list"},
+			patterns: []string{"src/**/*.go"},
+			rest:     []string{"go run ./server", "tool", "list"},
+		},
+		{
+			name:     "comma separated and repeated",
+			args:     []string{"node server.js", "--watch=src,lib/*.js", "--watch", "package.json"},
+			patterns: []string{"src", "lib/*.js", "package.json"},
+			rest:     []string{"node server.js"},
+		},
+		{
+			name:     "server arguments are left alone",
+			args:     []string{"serve", "--http", ":8080", "--watch", "bin", "--", "node", "--watch", "x"},
+			patterns: []string{"bin"},
+			rest:     []string{"serve", "--http", ":8080", "--", "node", "--watch", "x"},
+		},
+		{
+			name: "no watch",
+			args: []string{"node server.js"},
+			rest: []string{"node server.js"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patterns, rest := SplitWatchFlag(tt.args)
+			if !reflect.DeepEqual(patterns, tt.patterns) {
+				t.Errorf("patterns = %q, want %q", patterns, tt.patterns)
+			}
+			if !reflect.DeepEqual(rest, tt.rest) {
+				t.Errorf("rest = %q, want %q", rest, tt.rest)
+			}
+		})
+	}
+}
@@ -0,0 +1,398 @@
+// Package compat compares two versions of an MCP server's catalog, its
+// tools, resources and prompts, and classifies each difference as breaking
+// for clients written against the older version or not. The catalogs come
+// from live servers or from snapshots saved with mcp-tui snapshot save.
+package compat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/snapshot"
+)
+
+// Kind is a kind of catalog entry
+type Kind string
+
+const (
+	KindTool     Kind = "tool"
+	KindResource Kind = "resource"
+	KindPrompt   Kind = "prompt"
+)
+
+// kinds are the kinds of entry in the order they are reported
+var kinds = []Kind{KindTool, KindResource, KindPrompt}
+
+// snapshotFiles are the snapshot files holding each kind of entry
+var snapshotFiles = map[Kind]string{
+	KindTool:     "tools.json",
+	KindResource: "resources.json",
+	KindPrompt:   "prompts.json",
+}
+
+// Catalog is what a server offers. Kinds that are not known, as when a
+// single snapshot file was loaded, are left out of comparisons.
+type Catalog struct {
+	Source    string // What the catalog came from, for reports
+	Tools     []mcp.Tool
+	Resources []mcp.Resource
+	Prompts   []mcp.Prompt
+	Known     map[Kind]bool
+}
+
+// FromService lists the catalog of a connected server. Lists the server
+// does not advertise are empty.
+func FromService(ctx context.Context, service mcp.Service, source string) (*Catalog, error) {
+	catalog := &Catalog{Source: source, Known: map[Kind]bool{KindTool: true, KindResource: true, KindPrompt: true}}
+	capabilities := service.GetServerInfo().Capabilities
+
+	var err error
+	if _, ok := capabilities["tools"]; ok {
+		if catalog.Tools, err = service.ListTools(ctx); err != nil {
+			return nil, fmt.Errorf("failed to list tools of %s: %w", source, err)
+		}
+	}
+	if _, ok := capabilities["resources"]; ok {
+		if catalog.Resources, err = service.ListResources(ctx); err != nil {
+			return nil, fmt.Errorf("failed to list resources of %s: %w", source, err)
+		}
+	}
+	if _, ok := capabilities["prompts"]; ok {
+		if catalog.Prompts, err = service.ListPrompts(ctx); err != nil {
+			return nil, fmt.Errorf("failed to list prompts of %s: %w", source, err)
+		}
+	}
+	return catalog, nil
+}
+
+// IsSnapshot reports whether path names a snapshot directory or file rather
+// than a server
+func IsSnapshot(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.IsDir() || isSnapshotFile(path)
+}
+
+func isSnapshotFile(path string) bool {
+	for _, name := range snapshotFiles {
+		if filepath.Base(path) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadSnapshot loads a catalog from a snapshot directory, in which a
+// missing file is an empty list, or from one of its tools.json,
+// resources.json and prompts.json files
+func LoadSnapshot(path string) (*Catalog, error) {
+	catalog := &Catalog{Source: path, Known: make(map[Kind]bool)}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	files := make(map[string][]byte)
+	if info.IsDir() {
+		snap, err := snapshot.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		files = snap.Files
+		for _, kind := range kinds {
+			catalog.Known[kind] = true
+		}
+	} else {
+		if !isSnapshotFile(path) {
+			return nil, fmt.Errorf("%s is not a snapshot file: expected tools.json, resources.json or prompts.json", path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		files[filepath.Base(path)] = data
+	}
+
+	for _, kind := range kinds {
+		data, ok := files[snapshotFiles[kind]]
+		if !ok {
+			continue
+		}
+		catalog.Known[kind] = true
+		var target any
+		switch kind {
+		case KindTool:
+			target = &catalog.Tools
+		case KindResource:
+			target = &catalog.Resources
+		case KindPrompt:
+			target = &catalog.Prompts
+		}
+		if err := json.Unmarshal(data, target); err != nil {
+			return nil, fmt.Errorf("invalid snapshot file %s: %w", snapshotFiles[kind], err)
+		}
+	}
+	return catalog, nil
+}
+
+// ChangeType is how an entry changed
+type ChangeType string
+
+const (
+	Added   ChangeType = "added"
+	Removed ChangeType = "removed"
+	Changed ChangeType = "changed"
+)
+
+// Change is an entry that was added, removed or changed
+type Change struct {
+	Kind     Kind       `json:"kind"`
+	Name     string     `json:"name"` // Name, or URI for resources
+	Change   ChangeType `json:"change"`
+	Breaking bool       `json:"breaking"`
+	Details  []Detail   `json:"details,omitempty"`
+}
+
+// Detail is one difference within a changed entry
+type Detail struct {
+	Path     string `json:"path"` // Where in the entry, e.g. inputSchema.properties.limit.type
+	Message  string `json:"message"`
+	Breaking bool   `json:"breaking"`
+}
+
+// Summary counts the changes in a report
+type Summary struct {
+	Added       int `json:"added"`
+	Removed     int `json:"removed"`
+	Changed     int `json:"changed"`
+	Breaking    int `json:"breaking"`
+	NonBreaking int `json:"nonBreaking"`
+}
+
+// Report is the comparison of an old catalog with a new one
+type Report struct {
+	Old     string   `json:"old"`
+	New     string   `json:"new"`
+	Changes []Change `json:"changes"`
+	Skipped []Kind   `json:"skipped,omitempty"` // Kinds one of the catalogs does not know
+	Summary Summary  `json:"summary"`
+}
+
+// Breaking reports whether any change is breaking
+func (r *Report) Breaking() bool {
+	return r.Summary.Breaking > 0
+}
+
+// Compare compares an old catalog with a new one. Changes are ordered by
+// kind, then name.
+func Compare(old, new *Catalog) *Report {
+	report := &Report{Old: old.Source, New: new.Source, Changes: []Change{}}
+	for _, kind := range kinds {
+		if !old.Known[kind] || !new.Known[kind] {
+			report.Skipped = append(report.Skipped, kind)
+			continue
+		}
+		var oldEntries, newEntries map[string]any
+		switch kind {
+		case KindTool:
+			oldEntries, newEntries = byName(old.Tools, toolName), byName(new.Tools, toolName)
+		case KindResource:
+			oldEntries, newEntries = byName(old.Resources, resourceURI), byName(new.Resources, resourceURI)
+		case KindPrompt:
+			oldEntries, newEntries = byName(old.Prompts, promptName), byName(new.Prompts, promptName)
+		}
+		report.Changes = append(report.Changes, compareEntries(kind, oldEntries, newEntries)...)
+	}
+
+	for _, change := range report.Changes {
+		switch change.Change {
+		case Added:
+			report.Summary.Added++
+		case Removed:
+			report.Summary.Removed++
+		case Changed:
+			report.Summary.Changed++
+		}
+		if change.Breaking {
+			report.Summary.Breaking++
+		} else {
+			report.Summary.NonBreaking++
+		}
+	}
+	return report
+}
+
+func toolName(t mcp.Tool) string        { return t.Name }
+func resourceURI(r mcp.Resource) string { return r.URI }
+func promptName(p mcp.Prompt) string    { return p.Name }
+
+// byName indexes entries by their name
+func byName[T any](entries []T, name func(T) string) map[string]any {
+	out := make(map[string]any, len(entries))
+	for _, entry := range entries {
+		out[name(entry)] = entry
+	}
+	return out
+}
+
+// compareEntries compares the entries of one kind. Removing an entry breaks
+// its callers; adding one does not.
+func compareEntries(kind Kind, old, new map[string]any) []Change {
+	names := make([]string, 0, len(old)+len(new))
+	for name := range old {
+		names = append(names, name)
+	}
+	for name := range new {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []Change
+	for _, name := range names {
+		before, inOld := old[name]
+		after, inNew := new[name]
+		switch {
+		case !inNew:
+			changes = append(changes, Change{Kind: kind, Name: name, Change: Removed, Breaking: true})
+		case !inOld:
+			changes = append(changes, Change{Kind: kind, Name: name, Change: Added})
+		default:
+			var details []Detail
+			switch kind {
+			case KindTool:
+				details = compareTools(before.(mcp.Tool), after.(mcp.Tool))
+			case KindResource:
+				details = compareResources(before.(mcp.Resource), after.(mcp.Resource))
+			case KindPrompt:
+				details = comparePrompts(before.(mcp.Prompt), after.(mcp.Prompt))
+			}
+			if len(details) == 0 {
+				continue
+			}
+			change := Change{Kind: kind, Name: name, Change: Changed, Details: details}
+			for _, detail := range details {
+				change.Breaking = change.Breaking || detail.Breaking
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func compareTools(old, new mcp.Tool) []Detail {
+	var details []Detail
+	if d, ok := describeChange("description", old.Description, new.Description); ok {
+		details = append(details, d)
+	}
+	return append(details, compareSchemas("inputSchema", old.InputSchema, new.InputSchema)...)
+}
+
+// compareResources compares resources with the same URI. A new MIME type
+// breaks readers that parse the content.
+func compareResources(old, new mcp.Resource) []Detail {
+	var details []Detail
+	if d, ok := describeChange("name", old.Name, new.Name); ok {
+		details = append(details, d)
+	}
+	if d, ok := describeChange("description", old.Description, new.Description); ok {
+		details = append(details, d)
+	}
+	if old.MimeType != new.MimeType {
+		details = append(details, Detail{
+			Path:     "mimeType",
+			Message:  fmt.Sprintf("changed from %s to %s", quoteOrNone(old.MimeType), quoteOrNone(new.MimeType)),
+			Breaking: true,
+		})
+	}
+	return details
+}
+
+// comparePrompts compares prompts with the same name. Removed arguments and
+// newly required ones break callers.
+func comparePrompts(old, new mcp.Prompt) []Detail {
+	var details []Detail
+	if d, ok := describeChange("description", old.Description, new.Description); ok {
+		details = append(details, d)
+	}
+	for _, name := range unionKeys(old.Arguments, new.Arguments) {
+		path := "arguments." + name
+		before, inOld := old.Arguments[name]
+		after, inNew := new.Arguments[name]
+		switch {
+		case !inNew:
+			details = append(details, Detail{Path: path, Message: "removed", Breaking: true})
+		case !inOld && argumentRequired(after):
+			details = append(details, Detail{Path: path, Message: "added as required", Breaking: true})
+		case !inOld:
+			details = append(details, Detail{Path: path, Message: "added as optional"})
+		default:
+			if !argumentRequired(before) && argumentRequired(after) {
+				details = append(details, Detail{Path: path, Message: "is now required", Breaking: true})
+			} else if argumentRequired(before) && !argumentRequired(after) {
+				details = append(details, Detail{Path: path, Message: "is no longer required"})
+			}
+			if d, ok := describeChange(path+".description", argumentDescription(before), argumentDescription(after)); ok {
+				details = append(details, d)
+			}
+		}
+	}
+	return details
+}
+
+func argumentRequired(arg any) bool {
+	m, _ := arg.(map[string]interface{})
+	required, _ := m["required"].(bool)
+	return required
+}
+
+func argumentDescription(arg any) string {
+	m, _ := arg.(map[string]interface{})
+	description, _ := m["description"].(string)
+	return description
+}
+
+// describeChange reports a changed description, or other free text, with
+// the words that changed marked inline
+func describeChange(path, old, new string) (Detail, bool) {
+	if old == new {
+		return Detail{}, false
+	}
+	switch {
+	case old == "":
+		return Detail{Path: path, Message: fmt.Sprintf("added %q", new)}, true
+	case new == "":
+		return Detail{Path: path, Message: fmt.Sprintf("removed %q", old)}, true
+	}
+	return Detail{Path: path, Message: InlineDiff(old, new)}, true
+}
+
+func quoteOrNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return fmt.Sprintf("%q", s)
+}
+
+// unionKeys returns the keys of both maps in order
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,266 @@
+package compat
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+)
+
+func schema(t *testing.T, s string) map[string]interface{} {
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &out))
+	return out
+}
+
+func TestCompareSchemas(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     []Detail
+	}{
+		{
+			name: "unchanged",
+			old:  `{"type": "object", "properties": {"a": {"type": "string"}}}`,
+			new:  `{"type": "object", "properties": {"a": {"type": "string"}}}`,
+		},
+		{
+			name: "property removed",
+			old:  `{"properties": {"a": {"type": "string"}, "b": {"type": "string"}}}`,
+			new:  `{"properties": {"a": {"type": "string"}}}`,
+			want: []Detail{{Path: "s.properties.b", Message: "property removed", Breaking: true}},
+		},
+		{
+			name: "optional and required properties added",
+			old:  `{"properties": {}}`,
+			new:  `{"properties": {"a": {}, "b": {}}, "required": ["b"]}`,
+			want: []Detail{
+				{Path: "s.properties.a", Message: "optional property added"},
+				{Path: "s.properties.b", Message: "required property added", Breaking: true},
+			},
+		},
+		{
+			name: "required changes",
+			old:  `{"properties": {"a": {}, "b": {}}, "required": ["a"]}`,
+			new:  `{"properties": {"a": {}, "b": {}}, "required": ["b"]}`,
+			want: []Detail{
+				{Path: "s.required", Message: `"b" is now required`, Breaking: true},
+				{Path: "s.required", Message: `"a" is no longer required`},
+			},
+		},
+		{
+			name: "type narrowed",
+			old:  `{"type": ["string", "null"]}`,
+			new:  `{"type": "string"}`,
+			want: []Detail{{Path: "s.type", Message: "narrowed from null|string to string", Breaking: true}},
+		},
+		{
+			name: "number narrowed to integer",
+			old:  `{"type": "number"}`,
+			new:  `{"type": "integer"}`,
+			want: []Detail{{Path: "s.type", Message: "narrowed from number to integer", Breaking: true}},
+		},
+		{
+			name: "type widened",
+			old:  `{"type": "integer"}`,
+			new:  `{}`,
+			want: []Detail{{Path: "s.type", Message: "widened from integer to any"}},
+		},
+		{
+			name: "type changed",
+			old:  `{"type": "string"}`,
+			new:  `{"type": "number"}`,
+			want: []Detail{{Path: "s.type", Message: "changed from string to number", Breaking: true}},
+		},
+		{
+			name: "enum values",
+			old:  `{"enum": ["a", "b"]}`,
+			new:  `{"enum": ["a", "c"]}`,
+			want: []Detail{
+				{Path: "s.enum", Message: `no longer accepts "b"`, Breaking: true},
+				{Path: "s.enum", Message: `now also accepts "c"`},
+			},
+		},
+		{
+			name: "enum added",
+			old:  `{"type": "string"}`,
+			new:  `{"type": "string", "enum": ["a"]}`,
+			want: []Detail{{Path: "s.enum", Message: `now limited to ["a"]`, Breaking: true}},
+		},
+		{
+			name: "bounds",
+			old:  `{"minimum": 1, "maximum": 10, "maxLength": 5}`,
+			new:  `{"minimum": 0, "maximum": 5, "minLength": 2}`,
+			want: []Detail{
+				{Path: "s.minimum", Message: "changed from 1 to 0"},
+				{Path: "s.minLength", Message: "2 added", Breaking: true},
+				{Path: "s.maximum", Message: "changed from 10 to 5", Breaking: true},
+				{Path: "s.maxLength", Message: "5 removed"},
+			},
+		},
+		{
+			name: "additional properties refused",
+			old:  `{"type": "object"}`,
+			new:  `{"type": "object", "additionalProperties": {"not": {}}}`,
+			want: []Detail{{Path: "s.additionalProperties", Message: "other properties are no longer allowed", Breaking: true}},
+		},
+		{
+			name: "additional properties allowed",
+			old:  `{"additionalProperties": false}`,
+			new:  `{"additionalProperties": true}`,
+			want: []Detail{{Path: "s.additionalProperties", Message: "other properties are now allowed"}},
+		},
+		{
+			name: "nested items",
+			old:  `{"type": "array", "items": {"properties": {"id": {"type": "number"}}}}`,
+			new:  `{"type": "array", "items": {"properties": {"id": {"type": "string"}}}}`,
+			want: []Detail{{Path: "s.items.properties.id.type", Message: "changed from number to string", Breaking: true}},
+		},
+		{
+			name: "description and default",
+			old:  `{"description": "How many results", "default": 10}`,
+			new:  `{"description": "How many results to return", "default": 20}`,
+			want: []Detail{
+				{Path: "s.description", Message: "How many results {+to return+}"},
+				{Path: "s.default", Message: "changed from 10 to 20"},
+			},
+		},
+		{
+			name: "combinators",
+			old:  `{"anyOf": [{"type": "string"}]}`,
+			new:  `{"anyOf": [{"type": "string"}, {"type": "null"}]}`,
+			want: []Detail{{Path: "s.anyOf", Message: "changed; compatibility is not analyzed, so it counts as breaking", Breaking: true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, compareSchemas("s", schema(t, tt.old), schema(t, tt.new)))
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	known := map[Kind]bool{KindTool: true, KindResource: true, KindPrompt: true}
+	old := &Catalog{
+		Source: "v1",
+		Tools: []mcp.Tool{
+			{Name: "search", Description: "Search the catalog", InputSchema: map[string]interface{}{"type": "object"}},
+			{Name: "legacy"},
+		},
+		Resources: []mcp.Resource{{URI: "file:///data.csv", MimeType: "text/csv"}},
+		Prompts: []mcp.Prompt{{Name: "summarize", Arguments: map[string]interface{}{
+			"text":  map[string]interface{}{"required": true},
+			"style": map[string]interface{}{"required": false},
+		}}},
+		Known: known,
+	}
+	new := &Catalog{
+		Source: "v2",
+		Tools: []mcp.Tool{
+			{Name: "search", Description: "Search the whole catalog", InputSchema: map[string]interface{}{"type": "object"}},
+			{Name: "fetch"},
+		},
+		Resources: []mcp.Resource{{URI: "file:///data.csv", MimeType: "application/json"}},
+		Prompts: []mcp.Prompt{{Name: "summarize", Arguments: map[string]interface{}{
+			"text":  map[string]interface{}{"required": true},
+			"style": map[string]interface{}{"required": true},
+		}}},
+		Known: known,
+	}
+
+	report := Compare(old, new)
+	assert.Equal(t, []Change{
+		{Kind: KindTool, Name: "fetch", Change: Added},
+		{Kind: KindTool, Name: "legacy", Change: Removed, Breaking: true},
+		{Kind: KindTool, Name: "search", Change: Changed, Details: []Detail{
+			{Path: "description", Message: "Search the {+whole+} catalog"},
+		}},
+		{Kind: KindResource, Name: "file:///data.csv", Change: Changed, Breaking: true, Details: []Detail{
+			{Path: "mimeType", Message: `changed from "text/csv" to "application/json"`, Breaking: true},
+		}},
+		{Kind: KindPrompt, Name: "summarize", Change: Changed, Breaking: true, Details: []Detail{
+			{Path: "arguments.style", Message: "is now required", Breaking: true},
+		}},
+	}, report.Changes)
+	assert.Equal(t, Summary{Added: 1, Removed: 1, Changed: 3, Breaking: 3, NonBreaking: 2}, report.Summary)
+	assert.True(t, report.Breaking())
+
+	// Kinds only one side knows are not compared
+	toolsOnly := &Catalog{Source: "tools.json", Tools: old.Tools, Known: map[Kind]bool{KindTool: true}}
+	report = Compare(toolsOnly, old)
+	assert.Empty(t, report.Changes)
+	assert.Equal(t, []Kind{KindResource, KindPrompt}, report.Skipped)
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "server.json"), []byte(`{"name": "demo"}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tools.json"), []byte(`[{"name": "echo", "inputSchema": {"type": "object"}}]`), 0o644))
+
+	catalog, err := LoadSnapshot(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []mcp.Tool{{Name: "echo", InputSchema: map[string]interface{}{"type": "object"}}}, catalog.Tools)
+	assert.Empty(t, catalog.Prompts)
+	assert.True(t, catalog.Known[KindPrompt], "a directory knows every kind")
+	assert.True(t, IsSnapshot(dir))
+
+	catalog, err = LoadSnapshot(filepath.Join(dir, "tools.json"))
+	require.NoError(t, err)
+	assert.Equal(t, map[Kind]bool{KindTool: true}, catalog.Known)
+	assert.True(t, IsSnapshot(filepath.Join(dir, "tools.json")))
+
+	_, err = LoadSnapshot(filepath.Join(dir, "server.json"))
+	assert.ErrorContains(t, err, "is not a snapshot file")
+	assert.False(t, IsSnapshot("node server.js"))
+}
+
+func TestInlineDiff(t *testing.T) {
+	assert.Equal(t, "Search [-the-] {+all+} documents", InlineDiff("Search the documents", "Search all documents"))
+	assert.Equal(t, "[-Old-] {+New+} text {+here+}", InlineDiff("Old text", "New text here"))
+	assert.Equal(t, "whitespace changed", InlineDiff("a  b", "a b"))
+}
+
+func TestWriteText(t *testing.T) {
+	report := &Report{
+		Old: "v1",
+		New: "v2",
+		Changes: []Change{
+			{Kind: KindTool, Name: "fetch", Change: Added},
+			{Kind: KindTool, Name: "search", Change: Changed, Breaking: true, Details: []Detail{
+				{Path: "description", Message: "Search {+all+}"},
+				{Path: "inputSchema.properties.q", Message: "property removed", Breaking: true},
+			}},
+			{Kind: KindPrompt, Name: "ask", Change: Removed, Breaking: true},
+		},
+		Skipped: []Kind{KindResource},
+		Summary: Summary{Added: 1, Removed: 1, Changed: 1, Breaking: 2, NonBreaking: 1},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteText(&buf, report))
+	assert.Equal(t, `Comparing v1 → v2
+
+Tools
+  + fetch added
+  ~ search changed (breaking)
+      • description: Search {+all+}
+      ✗ inputSchema.properties.q: property removed
+
+Prompts
+  - ask removed (breaking)
+
+Resources not compared: only one side lists them
+
+2 breaking, 1 non-breaking (1 added, 1 removed, 1 changed)
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteText(&buf, &Report{Old: "a", New: "b"}))
+	assert.Equal(t, "Comparing a → b\n\nNo changes\n", buf.String())
+}
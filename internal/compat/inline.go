@@ -0,0 +1,68 @@
+package compat
+
+import (
+	"strings"
+)
+
+// maxInlineWords bounds the texts compared word by word; longer ones are
+// shown whole, old then new
+const maxInlineWords = 2000
+
+// InlineDiff shows how one text became another, marking removed words as
+// [-words-] and added ones as {+words+}, as git diff --word-diff does
+func InlineDiff(old, new string) string {
+	a, b := strings.Fields(old), strings.Fields(new)
+	if len(a) > maxInlineWords || len(b) > maxInlineWords {
+		return "[-" + old + "-] {+" + new + "+}"
+	}
+
+	// Longest common subsequence of words, from the end
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var parts, removed, added []string
+	changed := false
+	flush := func() {
+		changed = changed || len(removed) > 0 || len(added) > 0
+		if len(removed) > 0 {
+			parts = append(parts, "[-"+strings.Join(removed, " ")+"-]")
+		}
+		if len(added) > 0 {
+			parts = append(parts, "{+"+strings.Join(added, " ")+"+}")
+		}
+		removed, added = nil, nil
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			flush()
+			parts = append(parts, a[i])
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed = append(removed, a[i])
+			i++
+		default:
+			added = append(added, b[j])
+			j++
+		}
+	}
+	flush()
+	if !changed {
+		// Only the whitespace changed
+		return "whitespace changed"
+	}
+	return strings.Join(parts, " ")
+}
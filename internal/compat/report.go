@@ -0,0 +1,71 @@
+package compat
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// changeMarks mark each change in the text report
+var changeMarks = map[ChangeType]string{
+	Added:   "+",
+	Removed: "-",
+	Changed: "~",
+}
+
+// kindTitles head each kind's changes in the text report
+var kindTitles = map[Kind]string{
+	KindTool:     "Tools",
+	KindResource: "Resources",
+	KindPrompt:   "Prompts",
+}
+
+// WriteText writes a readable report: the changes of each kind with
+// breaking ones flagged, the details of changed entries indented below
+// them, and a summary
+func WriteText(w io.Writer, report *Report) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Comparing %s → %s\n", report.Old, report.New)
+
+	for _, kind := range kinds {
+		var changes []Change
+		for _, change := range report.Changes {
+			if change.Kind == kind {
+				changes = append(changes, change)
+			}
+		}
+		if len(changes) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n%s\n", kindTitles[kind])
+		for _, change := range changes {
+			line := fmt.Sprintf("  %s %s %s", changeMarks[change.Change], change.Name, change.Change)
+			if change.Breaking {
+				line += " (breaking)"
+			}
+			b.WriteString(line + "\n")
+			for _, detail := range change.Details {
+				mark := "•"
+				if detail.Breaking {
+					mark = "✗"
+				}
+				fmt.Fprintf(&b, "      %s %s: %s\n", mark, detail.Path, detail.Message)
+			}
+		}
+	}
+
+	for _, kind := range report.Skipped {
+		fmt.Fprintf(&b, "\n%s not compared: only one side lists them\n", kindTitles[kind])
+	}
+
+	s := report.Summary
+	if len(report.Changes) == 0 {
+		b.WriteString("\nNo changes\n")
+	} else {
+		fmt.Fprintf(&b, "\n%d breaking, %d non-breaking (%d added, %d removed, %d changed)\n",
+			s.Breaking, s.NonBreaking, s.Added, s.Removed, s.Changed)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,278 @@
+package compat
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// lowerBounds and upperBounds are the keywords that narrow what a schema
+// accepts when they rise and fall respectively
+var (
+	lowerBounds = []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties"}
+	upperBounds = []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties"}
+)
+
+// opaqueKeywords combine or reference other schemas. Whether a change to
+// them narrows the schema is not worked out, so any change counts as
+// breaking.
+var opaqueKeywords = []string{"$ref", "allOf", "anyOf", "oneOf", "not", "if", "then", "else", "dependentRequired", "dependentSchemas"}
+
+// compareSchemas compares two input schemas from the point of view of a
+// caller written against the old one: a change is breaking when arguments
+// the old schema accepts may be refused by the new one.
+func compareSchemas(path string, old, new map[string]interface{}) []Detail {
+	var details []Detail
+	add := func(path, message string, breaking bool) {
+		details = append(details, Detail{Path: path, Message: message, Breaking: breaking})
+	}
+
+	if d, ok := describeChange(path+".description", text(old["description"]), text(new["description"])); ok {
+		details = append(details, d)
+	}
+
+	// Type
+	oldTypes, newTypes := typeSet(old["type"]), typeSet(new["type"])
+	switch {
+	case slices.Equal(oldTypes, newTypes):
+	case accepts(newTypes, oldTypes):
+		add(path+".type", fmt.Sprintf("widened from %s to %s", typeList(oldTypes), typeList(newTypes)), false)
+	case accepts(oldTypes, newTypes):
+		add(path+".type", fmt.Sprintf("narrowed from %s to %s", typeList(oldTypes), typeList(newTypes)), true)
+	default:
+		add(path+".type", fmt.Sprintf("changed from %s to %s", typeList(oldTypes), typeList(newTypes)), true)
+	}
+
+	// Allowed values
+	oldEnum, oldHasEnum := old["enum"].([]interface{})
+	newEnum, newHasEnum := new["enum"].([]interface{})
+	switch {
+	case !oldHasEnum && newHasEnum:
+		add(path+".enum", "now limited to "+compact(newEnum), true)
+	case oldHasEnum && !newHasEnum:
+		add(path+".enum", "no longer limited to "+compact(oldEnum), false)
+	case oldHasEnum && newHasEnum:
+		if removed := missing(oldEnum, newEnum); len(removed) > 0 {
+			add(path+".enum", "no longer accepts "+strings.Join(removed, ", "), true)
+		}
+		if added := missing(newEnum, oldEnum); len(added) > 0 {
+			add(path+".enum", "now also accepts "+strings.Join(added, ", "), false)
+		}
+	}
+	if oldConst, newConst := compact(old["const"]), compact(new["const"]); oldConst != newConst {
+		add(path+".const", fmt.Sprintf("changed from %s to %s", oldConst, newConst), new["const"] != nil)
+	}
+
+	// Bounds
+	for _, keyword := range lowerBounds {
+		compareBound(path, keyword, old[keyword], new[keyword], func(o, n float64) bool { return n > o }, add)
+	}
+	for _, keyword := range upperBounds {
+		compareBound(path, keyword, old[keyword], new[keyword], func(o, n float64) bool { return n < o }, add)
+	}
+	for _, keyword := range []string{"pattern", "format"} {
+		oldValue, newValue := text(old[keyword]), text(new[keyword])
+		switch {
+		case oldValue == newValue:
+		case newValue == "":
+			add(path+"."+keyword, fmt.Sprintf("%q removed", oldValue), false)
+		case oldValue == "":
+			add(path+"."+keyword, fmt.Sprintf("%q added", newValue), true)
+		default:
+			add(path+"."+keyword, fmt.Sprintf("changed from %q to %q", oldValue, newValue), true)
+		}
+	}
+	if oldDefault, newDefault := compact(old["default"]), compact(new["default"]); oldDefault != newDefault {
+		add(path+".default", fmt.Sprintf("changed from %s to %s", oldDefault, newDefault), false)
+	}
+
+	// Properties
+	oldProps, _ := old["properties"].(map[string]interface{})
+	newProps, _ := new["properties"].(map[string]interface{})
+	oldRequired, newRequired := stringSet(old["required"]), stringSet(new["required"])
+	for _, name := range unionKeys(oldProps, newProps) {
+		propPath := path + ".properties." + name
+		before, inOld := oldProps[name]
+		after, inNew := newProps[name]
+		switch {
+		case !inNew:
+			add(propPath, "property removed", true)
+		case !inOld && newRequired[name]:
+			add(propPath, "required property added", true)
+		case !inOld:
+			add(propPath, "optional property added", false)
+		default:
+			details = append(details, compareSchemas(propPath, schemaOf(before), schemaOf(after))...)
+		}
+	}
+	for _, name := range sortedSet(newRequired) {
+		if !oldRequired[name] && !(newProps[name] != nil && oldProps[name] == nil) {
+			add(path+".required", fmt.Sprintf("%q is now required", name), true)
+		}
+	}
+	for _, name := range sortedSet(oldRequired) {
+		if !newRequired[name] && !(oldProps[name] != nil && newProps[name] == nil) {
+			add(path+".required", fmt.Sprintf("%q is no longer required", name), false)
+		}
+	}
+
+	// Additional properties and array items
+	oldAdditional, newAdditional := old["additionalProperties"], new["additionalProperties"]
+	switch {
+	case allowsAdditional(oldAdditional) && refusesAll(newAdditional):
+		add(path+".additionalProperties", "other properties are no longer allowed", true)
+	case refusesAll(oldAdditional) && allowsAdditional(newAdditional):
+		add(path+".additionalProperties", "other properties are now allowed", false)
+	default:
+		oldSchema, oldIsSchema := oldAdditional.(map[string]interface{})
+		newSchema, newIsSchema := newAdditional.(map[string]interface{})
+		if (oldIsSchema || newIsSchema) && !refusesAll(oldAdditional) && !refusesAll(newAdditional) {
+			details = append(details, compareSchemas(path+".additionalProperties", oldSchema, newSchema)...)
+		}
+	}
+	if oldItems, newItems := schemaOf(old["items"]), schemaOf(new["items"]); old["items"] != nil || new["items"] != nil {
+		details = append(details, compareSchemas(path+".items", oldItems, newItems)...)
+	}
+
+	for _, keyword := range opaqueKeywords {
+		if oldValue, newValue := compact(old[keyword]), compact(new[keyword]); oldValue != newValue {
+			add(path+"."+keyword, "changed; compatibility is not analyzed, so it counts as breaking", true)
+		}
+	}
+	return details
+}
+
+// compareBound compares a numeric bound. Adding a bound, or moving it the
+// way narrows says, is breaking; removing or relaxing it is not.
+func compareBound(path, keyword string, old, new interface{}, narrows func(old, new float64) bool, add func(string, string, bool)) {
+	oldValue, oldSet := old.(float64)
+	newValue, newSet := new.(float64)
+	switch {
+	case !oldSet && !newSet:
+	case !newSet:
+		add(path+"."+keyword, fmt.Sprintf("%v removed", oldValue), false)
+	case !oldSet:
+		add(path+"."+keyword, fmt.Sprintf("%v added", newValue), true)
+	case oldValue != newValue:
+		add(path+"."+keyword, fmt.Sprintf("changed from %v to %v", oldValue, newValue), narrows(oldValue, newValue))
+	}
+}
+
+// typeSet returns the JSON types a schema's type keyword allows; empty for
+// any type
+func typeSet(value interface{}) []string {
+	var types []string
+	switch v := value.(type) {
+	case string:
+		types = []string{v}
+	case []interface{}:
+		for _, t := range v {
+			if s, ok := t.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// accepts reports whether every value of the types in narrow is also one of
+// the types in wide. An empty set is any type, and integers are numbers.
+func accepts(wide, narrow []string) bool {
+	if len(wide) == 0 {
+		return true
+	}
+	if len(narrow) == 0 {
+		return false
+	}
+	for _, t := range narrow {
+		if !slices.Contains(wide, t) && !(t == "integer" && slices.Contains(wide, "number")) {
+			return false
+		}
+	}
+	return true
+}
+
+func typeList(types []string) string {
+	if len(types) == 0 {
+		return "any"
+	}
+	return strings.Join(types, "|")
+}
+
+// allowsAdditional reports whether an additionalProperties value allows
+// any property that is not listed: absent, true or the empty schema
+func allowsAdditional(value interface{}) bool {
+	schema, isSchema := value.(map[string]interface{})
+	return value == nil || value == true || (isSchema && len(schema) == 0)
+}
+
+// refusesAll reports whether a schema accepts nothing: false, or {"not": {}}
+// as some SDKs write it
+func refusesAll(value interface{}) bool {
+	if value == false {
+		return true
+	}
+	schema, _ := value.(map[string]interface{})
+	not, isSchema := schema["not"].(map[string]interface{})
+	return len(schema) == 1 && isSchema && len(not) == 0
+}
+
+func schemaOf(value interface{}) map[string]interface{} {
+	schema, _ := value.(map[string]interface{})
+	return schema
+}
+
+func stringSet(value interface{}) map[string]bool {
+	set := make(map[string]bool)
+	list, _ := value.([]interface{})
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+func sortedSet(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// missing returns the values of a, as JSON, that b lacks
+func missing(a, b []interface{}) []string {
+	have := make(map[string]bool, len(b))
+	for _, v := range b {
+		have[compact(v)] = true
+	}
+	var out []string
+	for _, v := range a {
+		if !have[compact(v)] {
+			out = append(out, compact(v))
+		}
+	}
+	return out
+}
+
+func text(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
+
+// compact formats a value as one line of JSON; none for nil
+func compact(value interface{}) string {
+	if value == nil {
+		return "none"
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -22,6 +22,14 @@ type Config struct {
 	// UI settings
 	EnableClipboard bool
 	ColorScheme     string
+
+	// Development settings
+	Watch []string // Files whose changes restart the server
+
+	// Session persistence
+	PersistSession  bool          // Save the session so the next launch can resume it
+	PersistFile     string        // Where sessions are saved, suffixed per server ("" uses ~/.config/mcp-tui/session.json)
+	PersistInterval time.Duration // How often the session is saved while connected
 }
 
 // Default returns the default configuration
@@ -34,6 +42,7 @@ func Default() *Config {
 		EnableClipboard:    true,
 		ColorScheme:        "default",
 		ServerCapabilities: make(map[string]interface{}),
+		PersistInterval:    time.Minute,
 	}
 }
 
@@ -45,6 +54,11 @@ const (
 	TransportSSE            = TransportType("sse")
 	TransportHTTP           = TransportType("http")
 	TransportStreamableHTTP = TransportType("streamable-http")
+	TransportWebSocket      = TransportType("websocket")
+	TransportUnix           = TransportType("unix")
+	TransportTCP            = TransportType("tcp")
+	TransportAuto           = TransportType("auto")
+	TransportReplay         = TransportType("replay")
 )
 
 // ConnectionConfig holds connection-specific settings
@@ -54,6 +68,10 @@ type ConnectionConfig struct {
 	Args    []string
 	URL     string
 	Headers map[string]string
+
+	RecordPath  string // Cassette file to record the session to
+	ReplayMatch string // Request matching mode for the replay transport
+	ChaosPath   string // Fault schedule to inject into the session
 }
 
 // Validate checks if the configuration is valid
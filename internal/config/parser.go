@@ -16,15 +16,12 @@ type ParsedArgs struct {
 //   - "npx -y @modelcontextprotocol/server-everything stdio"
 //   - "./my-server --mcp"
 //   - "http://localhost:8000/mcp"
+//   - "ws://localhost:8000/mcp"
 func ParseConnectionString(connStr string) *ConnectionConfig {
 	// Check if it's a URL
-	if strings.HasPrefix(connStr, "http://") || strings.HasPrefix(connStr, "https://") {
-		transportType := TransportHTTP
-		if strings.Contains(connStr, "/events") || strings.Contains(connStr, "sse") {
-			transportType = TransportSSE
-		}
+	if isURL(connStr) {
 		return &ConnectionConfig{
-			Type: transportType,
+			Type: TransportForURL(connStr),
 			URL:  connStr,
 		}
 	}
@@ -58,12 +55,8 @@ func ParseArgs(args []string, cmdFlag, urlFlag string, argsFlag []string) *Parse
 			Args:    argsFlag,
 		}
 	} else if urlFlag != "" {
-		transportType := TransportHTTP
-		if strings.Contains(urlFlag, "/events") || strings.Contains(urlFlag, "sse") {
-			transportType = TransportSSE
-		}
 		result.Connection = &ConnectionConfig{
-			Type: transportType,
+			Type: TransportForURL(urlFlag),
 			URL:  urlFlag,
 		}
 	}
@@ -71,8 +64,17 @@ func ParseArgs(args []string, cmdFlag, urlFlag string, argsFlag []string) *Parse
 	// Check if we need to parse positional connection string
 	argsToProcess := args
 	if result.Connection == nil && len(argsToProcess) > 0 {
-		// Skip if first arg is a subcommand
-		if !isKnownSubcommand(argsToProcess[0]) && !strings.HasPrefix(argsToProcess[0], "-") {
+		if argsToProcess[0] == "replay" {
+			// "replay <cassette>" serves a recorded session instead of a live server
+			if len(argsToProcess) > 1 {
+				result.Connection = &ConnectionConfig{
+					Type: TransportReplay,
+					URL:  argsToProcess[1],
+				}
+				argsToProcess = argsToProcess[2:]
+			}
+		} else if !isKnownSubcommand(argsToProcess[0]) && !strings.HasPrefix(argsToProcess[0], "-") {
+			// Skip if first arg is a subcommand
 			result.Connection = ParseConnectionString(argsToProcess[0])
 			argsToProcess = argsToProcess[1:] // consume the connection string
 		}
@@ -97,9 +99,57 @@ func ParseArgs(args []string, cmdFlag, urlFlag string, argsFlag []string) *Parse
 	return result
 }
 
+// isURL reports whether a connection string is a URL rather than a command
+func isURL(connStr string) bool {
+	for _, prefix := range []string{"http://", "https://", "ws://", "wss://", "unix://", "tcp://"} {
+		if strings.HasPrefix(connStr, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// TransportForURL picks the transport type implied by a URL
+func TransportForURL(url string) TransportType {
+	if strings.HasPrefix(url, "ws://") || strings.HasPrefix(url, "wss://") {
+		return TransportWebSocket
+	}
+	if strings.HasPrefix(url, "unix://") {
+		return TransportUnix
+	}
+	if strings.HasPrefix(url, "tcp://") {
+		return TransportTCP
+	}
+	// Only an explicit legacy endpoint path selects SSE directly; anything else is
+	// negotiated at connect time so paths like /assessment are not misrouted
+	path := strings.TrimPrefix(strings.TrimPrefix(url, "http://"), "https://")
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	path = strings.TrimSuffix(path, "/")
+	if strings.HasSuffix(path, "/sse") || strings.HasSuffix(path, "/events") {
+		return TransportSSE
+	}
+	return TransportAuto
+}
+
+// HasSubcommand reports whether args run a subcommand rather than the TUI.
+// Arguments after "--" belong to a server command and are not considered.
+func HasSubcommand(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if isKnownSubcommand(arg) {
+			return true
+		}
+	}
+	return false
+}
+
 // isKnownSubcommand checks if a string is a known subcommand
 func isKnownSubcommand(arg string) bool {
-	knownCommands := []string{"tool", "resource", "prompt", "server", "completion", "help"}
+	knownCommands := []string{"tool", "resource", "prompt", "server", "shell", "test", "check", "snapshot", "diff", "bench", "fuzz", "mock", "chaos", "proxy", "attach", "serve", "completion", "help"}
 	for _, cmd := range knownCommands {
 		if arg == cmd {
 			return true
@@ -2,6 +2,7 @@ package config
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -33,7 +34,7 @@ func TestParseConnectionString(t *testing.T) {
 			name:  "http url",
 			input: "http://localhost:8000/mcp",
 			expected: &ConnectionConfig{
-				Type: TransportHTTP,
+				Type: TransportAuto,
 				URL:  "http://localhost:8000/mcp",
 			},
 		},
@@ -45,6 +46,54 @@ func TestParseConnectionString(t *testing.T) {
 				URL:  "http://localhost:8000/sse",
 			},
 		},
+		{
+			name:  "url containing sse is not guessed",
+			input: "https://example.com/assessment",
+			expected: &ConnectionConfig{
+				Type: TransportAuto,
+				URL:  "https://example.com/assessment",
+			},
+		},
+		{
+			name:  "events url",
+			input: "http://localhost:8000/events/",
+			expected: &ConnectionConfig{
+				Type: TransportSSE,
+				URL:  "http://localhost:8000/events/",
+			},
+		},
+		{
+			name:  "websocket url",
+			input: "ws://localhost:8000/mcp",
+			expected: &ConnectionConfig{
+				Type: TransportWebSocket,
+				URL:  "ws://localhost:8000/mcp",
+			},
+		},
+		{
+			name:  "secure websocket url",
+			input: "wss://example.com/sse",
+			expected: &ConnectionConfig{
+				Type: TransportWebSocket,
+				URL:  "wss://example.com/sse",
+			},
+		},
+		{
+			name:  "unix socket",
+			input: "unix:///tmp/mcp.sock",
+			expected: &ConnectionConfig{
+				Type: TransportUnix,
+				URL:  "unix:///tmp/mcp.sock",
+			},
+		},
+		{
+			name:  "tcp socket",
+			input: "tcp://localhost:9000",
+			expected: &ConnectionConfig{
+				Type: TransportTCP,
+				URL:  "tcp://localhost:9000",
+			},
+		},
 		{
 			name:     "empty string",
 			input:    "",
@@ -109,6 +158,113 @@ func TestParseArgs(t *testing.T) {
 			},
 			description: "Should parse connection and tool call with parameters",
 		},
+		{
+			name: "natural CLI with shell",
+			args: []string{"node server.js", "shell"},
+			expected: &ParsedArgs{
+				Connection: &ConnectionConfig{
+					Type:    TransportStdio,
+					Command: "node",
+					Args:    []string{"server.js"},
+				},
+				SubCommand:     "shell",
+				SubCommandArgs: []string{},
+			},
+			description: "Should parse connection and the shell subcommand",
+		},
+		{
+			name: "test scenarios without connection",
+			args: []string{"test", "smoke.yaml"},
+			expected: &ParsedArgs{
+				SubCommand:     "test",
+				SubCommandArgs: []string{"smoke.yaml"},
+			},
+			description: "Should parse the test subcommand without treating it as a server",
+		},
+		{
+			name: "stdio server with check",
+			args: []string{"node server.js", "check"},
+			expected: &ParsedArgs{
+				Connection: &ConnectionConfig{
+					Type:    TransportStdio,
+					Command: "node",
+					Args:    []string{"server.js"},
+				},
+				SubCommand:     "check",
+				SubCommandArgs: []string{},
+			},
+			description: "Should parse connection and the check subcommand",
+		},
+		{
+			name: "stdio server with snapshot verify",
+			args: []string{"node server.js", "snapshot", "verify", "golden"},
+			expected: &ParsedArgs{
+				Connection: &ConnectionConfig{
+					Type:    TransportStdio,
+					Command: "node",
+					Args:    []string{"server.js"},
+				},
+				SubCommand:     "snapshot",
+				SubCommandArgs: []string{"verify", "golden"},
+			},
+			description: "Should parse connection and the snapshot subcommand with its directory",
+		},
+		{
+			name: "diff of two servers without connection",
+			args: []string{"diff", "node v1.js", "node v2.js"},
+			expected: &ParsedArgs{
+				SubCommand:     "diff",
+				SubCommandArgs: []string{"node v1.js", "node v2.js"},
+			},
+			description: "Should leave both servers of a diff to the subcommand",
+		},
+		{
+			name: "bench with connection and flags",
+			args: []string{"node server.js", "bench", "call", "search", "q=x", "-c", "10", "--duration", "5s"},
+			expected: &ParsedArgs{
+				Connection: &ConnectionConfig{
+					Type:    TransportStdio,
+					Command: "node",
+					Args:    []string{"server.js"},
+				},
+				SubCommand:     "bench",
+				SubCommandArgs: []string{"call", "search", "q=x", "-c", "10", "--duration", "5s"},
+			},
+			description: "Should parse connection and pass the bench operation and its flags through",
+		},
+		{
+			name: "fuzz with connection and flags",
+			args: []string{"node server.js", "fuzz", "--all", "--category", "unicode", "--timeout", "2s"},
+			expected: &ParsedArgs{
+				Connection: &ConnectionConfig{
+					Type:    TransportStdio,
+					Command: "node",
+					Args:    []string{"server.js"},
+				},
+				SubCommand:     "fuzz",
+				SubCommandArgs: []string{"--all", "--category", "unicode", "--timeout", "2s"},
+			},
+			description: "Should parse connection and pass the fuzz flags through",
+		},
+		{
+			name: "replay cassette with tool list",
+			args: []string{"replay", "session.ndjson", "tool", "list"},
+			expected: &ParsedArgs{
+				Connection: &ConnectionConfig{
+					Type: TransportReplay,
+					URL:  "session.ndjson",
+				},
+				SubCommand:     "tool",
+				SubCommandArgs: []string{"list"},
+			},
+			description: "Should treat replay <cassette> as the connection",
+		},
+		{
+			name:        "replay without cassette",
+			args:        []string{"replay"},
+			expected:    &ParsedArgs{},
+			description: "Should leave replay to the replay command when no cassette is given",
+		},
 		{
 			name:     "flag-based connection",
 			args:     []string{"tool", "list"},
@@ -131,7 +287,7 @@ func TestParseArgs(t *testing.T) {
 			urlFlag: "http://localhost:8000/mcp",
 			expected: &ParsedArgs{
 				Connection: &ConnectionConfig{
-					Type: TransportHTTP,
+					Type: TransportAuto,
 					URL:  "http://localhost:8000/mcp",
 				},
 				SubCommand:     "server",
@@ -215,6 +371,11 @@ func TestIsKnownSubcommand(t *testing.T) {
 		{"resource", true},
 		{"prompt", true},
 		{"server", true},
+		{"mock", true},
+		{"chaos", true},
+		{"proxy", true},
+		{"attach", true},
+		{"serve", true},
 		{"completion", true},
 		{"help", true},
 		{"unknown", false},
@@ -231,3 +392,28 @@ func TestIsKnownSubcommand(t *testing.T) {
 		})
 	}
 }
+
+func TestHasSubcommand(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected bool
+	}{
+		{[]string{"node server.js"}, false},
+		{[]string{"node server.js", "tool", "list"}, true},
+		{[]string{"--cmd", "node", "--args", "server.js", "tool", "list"}, true},
+		{[]string{"replay", "session.ndjson", "tool", "list"}, true},
+		{[]string{"--url", "http://localhost:8000/mcp"}, false},
+		{[]string{"serve", "--http", ":8080", "--", "node", "server.js"}, true},
+		{[]string{"--transport", "stdio", "--", "node", "tool"}, false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			result := HasSubcommand(tt.args)
+			if result != tt.expected {
+				t.Errorf("HasSubcommand(%q) = %v, want %v", tt.args, result, tt.expected)
+			}
+		})
+	}
+}
@@ -131,9 +131,11 @@ func (e MCPLogEntry) GetFormattedJSON() string {
 
 // MCPLogger captures all MCP protocol communication
 type MCPLogger struct {
-	mu      sync.RWMutex
-	entries []MCPLogEntry
-	maxSize int
+	mu          sync.RWMutex
+	entries     []MCPLogEntry
+	maxSize     int
+	subscribers map[int]func(MCPLogEntry)
+	nextSubID   int
 }
 
 // NewMCPLogger creates a new MCP protocol logger
@@ -157,7 +159,6 @@ func (ml *MCPLogger) LogIncoming(rawMessage string, parsedMessage interface{}) {
 // logMessage logs a message with the specified direction
 func (ml *MCPLogger) logMessage(direction, rawMessage string, parsedMessage interface{}) {
 	ml.mu.Lock()
-	defer ml.mu.Unlock()
 
 	entry := MCPLogEntry{
 		Timestamp:  time.Now(),
@@ -184,6 +185,35 @@ func (ml *MCPLogger) logMessage(direction, rawMessage string, parsedMessage inte
 		copy(ml.entries, ml.entries[len(ml.entries)-ml.maxSize:])
 		ml.entries = ml.entries[:ml.maxSize]
 	}
+
+	subscribers := make([]func(MCPLogEntry), 0, len(ml.subscribers))
+	for _, fn := range ml.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	ml.mu.Unlock()
+
+	// Notify subscribers outside the lock so they may read the logger
+	for _, fn := range subscribers {
+		fn(entry)
+	}
+}
+
+// Subscribe calls fn for every entry logged from now on, outside the logger's
+// lock. The returned function removes the subscription.
+func (ml *MCPLogger) Subscribe(fn func(MCPLogEntry)) func() {
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+	if ml.subscribers == nil {
+		ml.subscribers = make(map[int]func(MCPLogEntry))
+	}
+	id := ml.nextSubID
+	ml.nextSubID++
+	ml.subscribers[id] = fn
+	return func() {
+		ml.mu.Lock()
+		defer ml.mu.Unlock()
+		delete(ml.subscribers, id)
+	}
 }
 
 // parseMessage extracts structured information from a parsed message
@@ -0,0 +1,351 @@
+// Package fuzz calls a server's tools with inputs generated from their input
+// schemas: boundary values, values of the wrong type, missing required
+// properties, huge strings, unicode edge cases and deeply nested values. A
+// server may refuse any of them, as a tool error result or, for arguments
+// that do not match the schema, as an invalid params error; the calls that
+// crash or hang the server, or get another protocol error, are findings.
+// Each finding is minimized to a smaller input that fails the same way.
+package fuzz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp/conformance"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
+)
+
+// Kind is how a call failed
+type Kind string
+
+const (
+	Crash         Kind = "crash"          // The server exited or the connection failed
+	ProtocolError Kind = "protocol_error" // A JSON-RPC error where a result was expected
+	Timeout       Kind = "timeout"        // No response in time, though the server answers ping
+	Hang          Kind = "hang"           // No response in time, and no answer to ping either
+)
+
+// codeInvalidParams is the JSON-RPC error arguments that do not match the
+// input schema may get instead of an error result
+const codeInvalidParams = -32602
+
+const (
+	// maxToolPages bounds the pages of tools/list read, in case they loop
+	maxToolPages = 100
+
+	// maxAttempts bounds the calls made to minimize a finding; calls that
+	// time out are slow, so those findings get fewer
+	maxAttempts        = 100
+	maxTimeoutAttempts = 10
+)
+
+// Finding is a call that failed
+type Finding struct {
+	Tool      string                 `json:"tool"`
+	Kind      Kind                   `json:"kind"`
+	Category  Category               `json:"category"`
+	Input     string                 `json:"input"` // The generated input, before minimizing
+	Detail    string                 `json:"detail"`
+	Arguments map[string]interface{} `json:"arguments"`
+	Valid     bool                   `json:"valid"` // Whether the arguments match the input schema
+	Minimized bool                   `json:"minimized"`
+	// Also lists the other inputs that failed the same way with the same
+	// arguments, once minimized
+	Also []string `json:"also,omitempty"`
+	// Reproducer is a command line that makes the call again, when known
+	Reproducer string `json:"reproducer,omitempty"`
+}
+
+// ToolResult counts the cases tried on a tool
+type ToolResult struct {
+	Name     string `json:"name"`
+	Cases    int    `json:"cases"`
+	Findings int    `json:"findings"`
+	Skipped  string `json:"skipped,omitempty"` // Why the tool was not fuzzed
+}
+
+// Summary counts the findings by kind
+type Summary struct {
+	Cases          int `json:"cases"`
+	Findings       int `json:"findings"`
+	Crashes        int `json:"crashes"`
+	ProtocolErrors int `json:"protocolErrors"`
+	Timeouts       int `json:"timeouts"`
+	Hangs          int `json:"hangs"`
+}
+
+// Report is the outcome of a fuzzing run
+type Report struct {
+	Server      string       `json:"server"`
+	Tools       []ToolResult `json:"tools"`
+	Findings    []Finding    `json:"findings"`
+	Summary     Summary      `json:"summary"`
+	Interrupted bool         `json:"interrupted,omitempty"`
+	DurationMs  int64        `json:"durationMs"`
+}
+
+// Fuzzer calls tools with generated inputs
+type Fuzzer struct {
+	Dial       conformance.Dialer
+	Timeout    time.Duration // Per call; conformance.DefaultTimeout when zero
+	Categories []Category    // Every category when empty
+	MaxCases   int           // Per tool; no limit when zero
+	NoMinimize bool
+
+	// Progress, if set, is called before each tool is fuzzed
+	Progress func(tool string, cases int)
+
+	session *conformance.Session
+}
+
+// tool is a tool as listed by the server
+type tool struct {
+	Name        string                 `json:"name"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+	Annotations struct {
+		DestructiveHint *bool `json:"destructiveHint"`
+	} `json:"annotations"`
+}
+
+// Run fuzzes the named tools or, when none are named, every tool the server
+// lists except those annotated as destructive. The report covers what ran
+// before a failure to connect or reconnect, which is returned as well.
+func (f *Fuzzer) Run(ctx context.Context, names []string) (*Report, error) {
+	if f.Timeout <= 0 {
+		f.Timeout = conformance.DefaultTimeout
+	}
+	start := time.Now()
+	report := &Report{Tools: []ToolResult{}, Findings: []Finding{}}
+	defer func() {
+		f.close()
+		report.summarize()
+		report.DurationMs = time.Since(start).Milliseconds()
+	}()
+
+	session, err := f.open(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.Server = session.ServerName
+
+	tools, err := f.listTools(ctx)
+	if err != nil {
+		return report, err
+	}
+	selected, err := selectTools(tools, names)
+	if err != nil {
+		return report, err
+	}
+
+	for _, t := range selected {
+		result := ToolResult{Name: t.Name}
+		if len(names) == 0 && t.Annotations.DestructiveHint != nil && *t.Annotations.DestructiveHint {
+			result.Skipped = "annotated as destructive; name it to fuzz it"
+			report.Tools = append(report.Tools, result)
+			continue
+		}
+
+		cases := Generate(t.InputSchema, f.Categories...)
+		if f.MaxCases > 0 && len(cases) > f.MaxCases {
+			cases = cases[:f.MaxCases]
+		}
+		if f.Progress != nil {
+			f.Progress(t.Name, len(cases))
+		}
+		for _, c := range cases {
+			kind, detail, err := f.try(ctx, t.Name, c.Arguments, c.Valid)
+			if err != nil {
+				report.Interrupted = ctx.Err() != nil
+				report.Tools = append(report.Tools, result)
+				return report, err
+			}
+			result.Cases++
+			if kind == "" {
+				continue
+			}
+			finding := Finding{
+				Tool:      t.Name,
+				Kind:      kind,
+				Category:  c.Category,
+				Input:     c.Name,
+				Detail:    detail,
+				Arguments: c.Arguments,
+				Valid:     c.Valid,
+			}
+			if !f.NoMinimize {
+				f.minimize(ctx, t, &finding)
+			}
+			if same := sameFinding(report.Findings, finding); same != nil {
+				same.Also = append(same.Also, finding.Input)
+				continue
+			}
+			result.Findings++
+			report.Findings = append(report.Findings, finding)
+		}
+		report.Tools = append(report.Tools, result)
+	}
+
+	return report, nil
+}
+
+// summarize counts the cases and findings
+func (r *Report) summarize() {
+	r.Summary = Summary{}
+	for _, result := range r.Tools {
+		r.Summary.Cases += result.Cases
+	}
+	for _, finding := range r.Findings {
+		r.Summary.Findings++
+		switch finding.Kind {
+		case Crash:
+			r.Summary.Crashes++
+		case ProtocolError:
+			r.Summary.ProtocolErrors++
+		case Timeout:
+			r.Summary.Timeouts++
+		case Hang:
+			r.Summary.Hangs++
+		}
+	}
+}
+
+// sameFinding returns the finding already made with the same tool, kind
+// and arguments, if there is one
+func sameFinding(findings []Finding, finding Finding) *Finding {
+	for i := range findings {
+		same := &findings[i]
+		if same.Tool == finding.Tool && same.Kind == finding.Kind && reflect.DeepEqual(same.Arguments, finding.Arguments) {
+			return same
+		}
+	}
+	return nil
+}
+
+// selectTools returns the named tools, in the order named, or every tool
+func selectTools(tools []tool, names []string) ([]tool, error) {
+	if len(names) == 0 {
+		return tools, nil
+	}
+	byName := make(map[string]tool, len(tools))
+	available := make([]string, 0, len(tools))
+	for _, t := range tools {
+		byName[t.Name] = t
+		available = append(available, t.Name)
+	}
+	sort.Strings(available)
+
+	var selected []tool
+	for _, name := range names {
+		t, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("tool %q not found; the server lists: %s", name, strings.Join(available, ", "))
+		}
+		selected = append(selected, t)
+	}
+	return selected, nil
+}
+
+// open returns the session, connecting again if the last one ended
+func (f *Fuzzer) open(ctx context.Context) (*conformance.Session, error) {
+	if f.session != nil && !f.session.Closed() {
+		return f.session, nil
+	}
+	f.close()
+	session, err := conformance.Open(ctx, f.Dial, f.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	f.session = session
+	return session, nil
+}
+
+// close ends the session, if there is one
+func (f *Fuzzer) close() {
+	if f.session != nil {
+		f.session.Close()
+		f.session = nil
+	}
+}
+
+// listTools reads every page of tools/list
+func (f *Fuzzer) listTools(ctx context.Context) ([]tool, error) {
+	var tools []tool
+	var cursor string
+	for page := 0; page < maxToolPages; page++ {
+		var params map[string]interface{}
+		if cursor != "" {
+			params = map[string]interface{}{"cursor": cursor}
+		}
+		data, err := f.session.Call(ctx, "tools/list", params, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		var result struct {
+			Tools      []tool `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		tools = append(tools, result.Tools...)
+		if result.NextCursor == "" || result.NextCursor == cursor {
+			break
+		}
+		cursor = result.NextCursor
+	}
+	return tools, nil
+}
+
+// try calls a tool and reports how the call failed, if it did. An error is
+// returned only when the run cannot go on: it was cancelled, or the server
+// cannot be reached again after a crash.
+func (f *Fuzzer) try(ctx context.Context, name string, args map[string]interface{}, valid bool) (Kind, string, error) {
+	session, err := f.open(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	params := map[string]interface{}{"name": name, "arguments": args}
+	_, err = session.Call(ctx, "tools/call", params, f.Timeout)
+	if ctx.Err() != nil {
+		return "", "", ctx.Err()
+	}
+
+	var wireErr *transports.WireError
+	switch {
+	case err == nil:
+		return "", "", nil
+	case errors.As(err, &wireErr):
+		if !valid && wireErr.Code == codeInvalidParams {
+			return "", "", nil
+		}
+		detail := fmt.Sprintf("error %d (%s)", wireErr.Code, wireErr.Message)
+		if valid {
+			return ProtocolError, detail + " for arguments that match the input schema; want a result", nil
+		}
+		return ProtocolError, fmt.Sprintf("%s; want an isError result or %d (invalid params)", detail, codeInvalidParams), nil
+	case session.Closed():
+		f.close()
+		return Crash, crashDetail(err, session), nil
+	case errors.Is(err, context.DeadlineExceeded):
+		if _, pingErr := session.Call(ctx, "ping", nil, f.Timeout); pingErr == nil {
+			return Timeout, fmt.Sprintf("no response within %s", f.Timeout), nil
+		}
+		f.close()
+		return Hang, fmt.Sprintf("no response within %s, and ping went unanswered", f.Timeout), nil
+	}
+	f.close()
+	return Crash, crashDetail(err, session), nil
+}
+
+// crashDetail explains a lost connection, with the server's last words
+func crashDetail(err error, session *conformance.Session) string {
+	details := append([]string{err.Error()}, session.ExitDetails()...)
+	return strings.Join(details, "; ")
+}
@@ -0,0 +1,439 @@
+package fuzz
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp/conformance"
+)
+
+const searchSchema = `{
+	"type": "object",
+	"properties": {
+		"query": {"type": "string", "maxLength": 5},
+		"limit": {"type": "integer", "minimum": 1, "maximum": 100},
+		"mode": {"enum": ["fast", "slow"]},
+		"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2}
+	},
+	"required": ["query"]
+}`
+
+func parseSchema(t *testing.T, text string) map[string]interface{} {
+	var s map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(text), &s))
+	return s
+}
+
+func casesByName(cases []Case) map[string]Case {
+	out := make(map[string]Case)
+	for _, c := range cases {
+		out[c.Name] = c
+	}
+	return out
+}
+
+func TestGenerate(t *testing.T) {
+	cases := Generate(parseSchema(t, searchSchema))
+	require.NotEmpty(t, cases)
+	assert.Equal(t, Case{
+		Category:  Valid,
+		Name:      "sample values for the required properties",
+		Arguments: map[string]interface{}{"query": "test"},
+		Valid:     true,
+	}, cases[0])
+
+	byName := casesByName(cases)
+	for name, valid := range map[string]bool{
+		"sample values for every property":                   true,
+		"limit = 100 (maximum)":                              true,
+		"limit = 101 (maximum + 1)":                          false,
+		"limit = 0 (minimum - 1)":                            false,
+		"limit = 1.5 (a fraction)":                           false,
+		"limit = 9007199254740993 (2^53 + 1)":                false,
+		`mode = "slow" (listed)`:                             true,
+		`mode = "not-a-listed-value" (not listed)`:           false,
+		"query has 5 characters (maxLength)":                 true,
+		"query has 6 characters (maxLength + 1)":             false,
+		"tags = [\"test\",\"test\",\"test\"] (maxItems + 1)": false,
+		"without query":                                      false,
+		"limit is a numeric string":                          false,
+		"query is null":                                      false,
+		"query is a 1 MiB string":                            false,
+		"tags has 100000 items":                              false,
+		"limit is a 10000-digit number":                      false,
+		"query has a NUL byte":                               false,
+		"mode has a right-to-left override":                  false,
+		"tags nests arrays 1000 deep":                        false,
+	} {
+		c, ok := byName[name]
+		if assert.True(t, ok, "no case %q", name) {
+			assert.Equal(t, valid, c.Valid, name)
+			if !strings.Contains(name, "query") {
+				assert.Equal(t, "test", c.Arguments["query"], "%s keeps the required properties", name)
+			}
+		}
+	}
+	for _, name := range []string{"query is a numeric string", "limit is a number", "mode is null"} {
+		assert.NotContains(t, byName, name, "%s is not a type confusion", name)
+	}
+	assert.Equal(t, 5000, depth(byName["tags nests arrays 5000 deep"].Arguments["tags"]))
+
+	missing := Generate(parseSchema(t, searchSchema), Missing)
+	assert.Equal(t, []Case{{Category: Missing, Name: "without query", Arguments: map[string]interface{}{}}}, missing)
+}
+
+func TestGenerateWithoutProperties(t *testing.T) {
+	cases := Generate(map[string]interface{}{"type": "object"}, Valid, Type, Unicode, Nesting)
+	assert.Equal(t, Case{Category: Valid, Name: "no arguments", Arguments: map[string]interface{}{}, Valid: true}, cases[0])
+	byName := casesByName(cases)
+	assert.Contains(t, byName, "input has an emoji ZWJ sequence")
+	assert.Contains(t, byName, "input nests arrays 1000 deep")
+	for _, c := range cases[1:] {
+		assert.NotEqual(t, Type, c.Category, "nothing is of the wrong type when no type is given")
+	}
+}
+
+func TestSample(t *testing.T) {
+	for _, test := range []struct {
+		schema string
+		want   interface{}
+	}{
+		{`{"type": "string", "format": "email"}`, "user@example.com"},
+		{`{"type": "string", "minLength": 6}`, "testxx"},
+		{`{"type": "string", "maxLength": 2}`, "te"},
+		{`{"type": "integer", "minimum": 5}`, int64(5)},
+		{`{"type": "number", "exclusiveMaximum": 0}`, -1.0},
+		{`{"type": ["null", "boolean"]}`, true},
+		{`{"type": "string", "default": "x"}`, "x"},
+		{`{"anyOf": [{"type": "integer"}, {"type": "string"}]}`, int64(1)},
+		{`{"type": "array", "items": {"type": "integer"}, "minItems": 2}`, []interface{}{int64(1), int64(1)}},
+		{`{"type": "object", "properties": {"a": {"type": "boolean"}}, "required": ["a"]}`, map[string]interface{}{"a": true}},
+	} {
+		assert.Equal(t, test.want, sample(parseSchema(t, test.schema), 0), test.schema)
+	}
+}
+
+func TestShrinkValue(t *testing.T) {
+	assert.Equal(t, []interface{}{"ab", "cd"}, shrinkValue("abcd", 1))
+	assert.Empty(t, shrinkValue("a", 1))
+	assert.Empty(t, shrinkValue(42.0, 1))
+
+	candidates := shrinkValue(nested("object", 1000), 1)
+	require.NotEmpty(t, candidates)
+	assert.Equal(t, 500, depth(candidates[0]))
+
+	candidates = shrinkValue([]interface{}{"abcd", true}, 1)
+	assert.Equal(t, []interface{}{
+		[]interface{}{"abcd"},
+		[]interface{}{true},
+		[]interface{}{"ab", true},
+		[]interface{}{"cd", true},
+	}, candidates)
+
+	objects := shrinkObject(map[string]interface{}{"a": "xy", "b": 1.0}, 0)
+	assert.Equal(t, []map[string]interface{}{
+		{"b": 1.0},
+		{"a": "xy"},
+		{"a": "x", "b": 1.0},
+		{"a": "y", "b": 1.0},
+	}, objects)
+}
+
+func TestReproducer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "repro")
+	finding := Finding{
+		Tool:      "search",
+		Arguments: map[string]interface{}{"query": "it's \u202e\U0001F600", "limit": 3.0},
+		Valid:     false,
+	}
+	line, err := Reproducer("mcp-tui 'node server.js'", dir, 1, finding)
+	require.NoError(t, err)
+	assert.Equal(t, `mcp-tui 'node server.js' tool call search --no-validate limit:=3 'query:="it'\''s \u202e\ud83d\ude00"'`, line)
+
+	finding.Valid = true
+	finding.Arguments = map[string]interface{}{"a.b": "dotted"}
+	line, err = Reproducer("mcp-tui --url http://localhost:8080/mcp", dir, 2, finding)
+	require.NoError(t, err)
+	file := filepath.Join(dir, "fuzz-2-search.json")
+	assert.Equal(t, "mcp-tui --url http://localhost:8080/mcp tool call search --input-json "+file, line)
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"a.b\":\"dotted\"}\n", string(data))
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(file)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		info, err = os.Stat(dir)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+	}
+
+	finding.Arguments = map[string]interface{}{"text": strings.Repeat("A", maxCommandLine)}
+	line, err = Reproducer("mcp-tui server", dir, 3, finding)
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(line, "--input-json "+filepath.Join(dir, "fuzz-3-search.json")), line)
+}
+
+// fragileServer serves three tools, badly. echo crashes the server on text
+// longer than 1000 bytes and fails on NUL bytes; count never answers
+// n = 11 and fails on values that are not numbers, instead of refusing
+// them as invalid params; wipe is destructive.
+func fragileServer(r io.Reader, w io.Writer) {
+	var mu sync.Mutex
+	send := func(msg map[string]interface{}) {
+		msg["jsonrpc"] = "2.0"
+		data, _ := json.Marshal(msg)
+		mu.Lock()
+		defer mu.Unlock()
+		w.Write(append(data, '\n'))
+	}
+	result := func(id interface{}, text string, isError bool) {
+		send(map[string]interface{}{"id": id, "result": map[string]interface{}{
+			"content": []interface{}{map[string]interface{}{"type": "text", "text": text}},
+			"isError": isError,
+		}})
+	}
+	fail := func(id interface{}, code int, message string) {
+		send(map[string]interface{}{"id": id, "error": map[string]interface{}{"code": code, "message": message}})
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16<<20)
+	for scanner.Scan() {
+		var req struct {
+			ID     interface{} `json:"id"`
+			Method string      `json:"method"`
+			Params struct {
+				Name      string                 `json:"name"`
+				Arguments map[string]interface{} `json:"arguments"`
+			} `json:"params"`
+		}
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.UseNumber()
+		if decoder.Decode(&req) != nil || req.ID == nil {
+			continue
+		}
+
+		switch req.Method {
+		case "initialize":
+			send(map[string]interface{}{"id": req.ID, "result": map[string]interface{}{
+				"protocolVersion": conformance.ProtocolVersion,
+				"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+				"serverInfo":      map[string]interface{}{"name": "fragile", "version": "1.0"},
+			}})
+		case "ping":
+			send(map[string]interface{}{"id": req.ID, "result": map[string]interface{}{}})
+		case "tools/list":
+			send(map[string]interface{}{"id": req.ID, "result": map[string]interface{}{"tools": []interface{}{
+				map[string]interface{}{"name": "echo", "inputSchema": map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"text": map[string]interface{}{"type": "string", "maxLength": 10}},
+					"required":   []interface{}{"text"},
+				}},
+				map[string]interface{}{"name": "count", "inputSchema": map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"n": map[string]interface{}{"type": "integer", "minimum": 0, "maximum": 10}},
+					"required":   []interface{}{"n"},
+				}},
+				map[string]interface{}{"name": "wipe", "inputSchema": map[string]interface{}{"type": "object"},
+					"annotations": map[string]interface{}{"destructiveHint": true}},
+			}}})
+		case "tools/call":
+			args := req.Params.Arguments
+			switch req.Params.Name {
+			case "echo":
+				text, ok := args["text"].(string)
+				switch {
+				case !ok:
+					fail(req.ID, -32602, "text must be a string")
+				case len(text) > 1000:
+					return
+				case strings.ContainsRune(text, 0):
+					fail(req.ID, -32603, "internal error")
+				default:
+					result(req.ID, text, false)
+				}
+			case "count":
+				n, ok := args["n"].(json.Number)
+				value, err := n.Float64()
+				switch {
+				case !ok || err != nil:
+					fail(req.ID, -32603, "cannot read n")
+				case value == 11:
+				case value < 0 || value > 10:
+					result(req.ID, "n is out of range", true)
+				default:
+					result(req.ID, n.String(), false)
+				}
+			default:
+				result(req.ID, "done", false)
+			}
+		default:
+			fail(req.ID, -32601, "method not found")
+		}
+	}
+}
+
+func findingsByInput(report *Report) map[string]Finding {
+	out := make(map[string]Finding)
+	for _, finding := range report.Findings {
+		out[finding.Tool+": "+finding.Input] = finding
+	}
+	return out
+}
+
+func TestRun(t *testing.T) {
+	var progress []string
+	fuzzer := &Fuzzer{
+		Dial:    conformance.StreamDialer(fragileServer),
+		Timeout: 500 * time.Millisecond,
+		Progress: func(tool string, cases int) {
+			progress = append(progress, tool)
+		},
+	}
+	report, err := fuzzer.Run(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "fragile", report.Server)
+	assert.Equal(t, []string{"echo", "count"}, progress)
+	require.Len(t, report.Tools, 3)
+	assert.Equal(t, "annotated as destructive; name it to fuzz it", report.Tools[2].Skipped)
+	assert.Equal(t, report.Tools[0].Cases+report.Tools[1].Cases, report.Summary.Cases)
+
+	findings := findingsByInput(report)
+	crash := findings["echo: text is a 1 MiB string"]
+	assert.Equal(t, Crash, crash.Kind)
+	assert.Contains(t, crash.Detail, "connection closed")
+	assert.True(t, crash.Minimized)
+	assert.Len(t, crash.Arguments["text"], 1024, "halved while it still crashes the server")
+
+	nul := findings["echo: text has a NUL byte"]
+	assert.Equal(t, ProtocolError, nul.Kind)
+	assert.Equal(t, map[string]interface{}{"text": "\x00"}, nul.Arguments)
+	assert.True(t, nul.Valid)
+	assert.Equal(t, "error -32603 (internal error) for arguments that match the input schema; want a result", nul.Detail)
+
+	timeout := findings["count: n = 11 (maximum + 1)"]
+	assert.Equal(t, Timeout, timeout.Kind)
+	assert.Equal(t, "no response within 500ms", timeout.Detail)
+	assert.False(t, timeout.Minimized)
+
+	confused := findings["count: n is a string"]
+	assert.Equal(t, ProtocolError, confused.Kind)
+	assert.Equal(t, map[string]interface{}{"n": "t"}, confused.Arguments, "required properties are kept")
+	assert.Contains(t, confused.Detail, "want an isError result or -32602 (invalid params)")
+
+	for _, finding := range report.Findings {
+		assert.NotEqual(t, Hang, finding.Kind, finding.Input)
+		if finding.Tool == "echo" {
+			assert.Contains(t, []string{"text is a 1 MiB string", "text has a NUL byte"}, finding.Input)
+		}
+	}
+	assert.Equal(t, 1, report.Summary.Crashes)
+	assert.Equal(t, 1, report.Summary.Timeouts)
+	assert.Equal(t, len(report.Findings)-2, report.Summary.ProtocolErrors)
+
+	// Named tools are fuzzed even when destructive
+	fuzzer = &Fuzzer{Dial: conformance.StreamDialer(fragileServer), Categories: []Category{Valid}}
+	report, err = fuzzer.Run(context.Background(), []string{"wipe"})
+	require.NoError(t, err)
+	assert.Equal(t, []ToolResult{{Name: "wipe", Cases: 1}}, report.Tools)
+
+	_, err = fuzzer.Run(context.Background(), []string{"missing"})
+	assert.EqualError(t, err, `tool "missing" not found; the server lists: count, echo, wipe`)
+}
+
+func TestRunHang(t *testing.T) {
+	// A server that stops reading after initializing
+	hung := func(r io.Reader, w io.Writer) {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			var req struct {
+				ID     interface{} `json:"id"`
+				Method string      `json:"method"`
+			}
+			json.Unmarshal(scanner.Bytes(), &req)
+			switch req.Method {
+			case "initialize":
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%v,"result":{"protocolVersion":%q,"capabilities":{},"serverInfo":{"name":"hung"}}}`+"\n", req.ID, conformance.ProtocolVersion)
+			case "tools/list":
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%v,"result":{"tools":[{"name":"wait","inputSchema":{"type":"object"}}]}}`+"\n", req.ID)
+			case "tools/call":
+				io.Copy(io.Discard, r)
+				return
+			}
+		}
+	}
+	fuzzer := &Fuzzer{Dial: conformance.StreamDialer(hung), Timeout: 100 * time.Millisecond, Categories: []Category{Valid}}
+	report, err := fuzzer.Run(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, report.Findings, 1)
+	assert.Equal(t, Hang, report.Findings[0].Kind)
+	assert.Equal(t, "no response within 100ms, and ping went unanswered", report.Findings[0].Detail)
+}
+
+func TestRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	fuzzer := &Fuzzer{
+		Dial:     conformance.StreamDialer(fragileServer),
+		Progress: func(string, int) { cancel() },
+	}
+	report, err := fuzzer.Run(ctx, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.True(t, report.Interrupted)
+	assert.Equal(t, []ToolResult{{Name: "echo"}}, report.Tools)
+}
+
+func TestWriteText(t *testing.T) {
+	report := &Report{
+		Server: "fragile",
+		Tools: []ToolResult{
+			{Name: "echo", Cases: 40, Findings: 1},
+			{Name: "count", Cases: 30},
+			{Name: "wipe", Skipped: "annotated as destructive; name it to fuzz it"},
+		},
+		Findings: []Finding{{
+			Tool:       "echo",
+			Kind:       Crash,
+			Category:   Huge,
+			Input:      "text is a 1 MiB string",
+			Detail:     "connection closed while waiting for tools/call: server closed its stdout",
+			Arguments:  map[string]interface{}{"text": strings.Repeat("A", 200)},
+			Minimized:  true,
+			Reproducer: "mcp-tui server tool call echo 'text:=\"AAAA\"'",
+		}},
+		Summary:    Summary{Cases: 70, Findings: 1, Crashes: 1},
+		DurationMs: 1500,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteText(&buf, report))
+	assert.Equal(t, `Fuzzing fragile: 3 tools, 70 cases
+
+  ✗ echo: 40 cases, 1 finding
+  ✓ count: 30 cases
+  - wipe (skipped: annotated as destructive; name it to fuzz it)
+
+Findings
+
+  1. echo: crash on "text is a 1 MiB string" (huge)
+     connection closed while waiting for tools/call: server closed its stdout
+     minimized: {"text":"`+strings.Repeat("A", 111)+`... (211 bytes)
+     reproduce: mcp-tui server tool call echo 'text:="AAAA"'
+
+1 finding (1 crash) in 70 cases, 1.5s
+`, buf.String())
+}
@@ -0,0 +1,539 @@
+package fuzz
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp/schema"
+)
+
+// Category groups generated inputs by what they try
+type Category string
+
+const (
+	Valid    Category = "valid"    // Sample arguments that match the schema
+	Boundary Category = "boundary" // Limits of numbers, strings, arrays and enums
+	Type     Category = "type"     // Values of the wrong JSON type
+	Missing  Category = "missing"  // Required properties left out
+	Huge     Category = "huge"     // Mebibyte strings, arrays of many items, long numbers
+	Unicode  Category = "unicode"  // Control characters, bidi overrides, emoji...
+	Nesting  Category = "nesting"  // Objects and arrays nested thousands deep
+)
+
+// Categories lists every category, in the order inputs are generated
+var Categories = []Category{Valid, Boundary, Type, Missing, Huge, Unicode, Nesting}
+
+// ParseCategory returns the category with a name
+func ParseCategory(name string) (Category, error) {
+	for _, category := range Categories {
+		if string(category) == name {
+			return category, nil
+		}
+	}
+	names := make([]string, len(Categories))
+	for i, category := range Categories {
+		names[i] = string(category)
+	}
+	return "", fmt.Errorf("unknown category %q: expected %s", name, strings.Join(names, ", "))
+}
+
+const (
+	hugeStringSize  = 1 << 20
+	hugeArrayItems  = 100000
+	hugeNumberDigit = 10000
+
+	// sampleDepth is how deep sample values of nested schemas go
+	sampleDepth = 8
+
+	// otherProperty is the property inputs are sent in when the schema
+	// lists none
+	otherProperty = "input"
+)
+
+// nestingDepths are the depths of the nested inputs: past the recursion
+// limits of common JSON parsers, and far past them
+var nestingDepths = []int{1000, 5000}
+
+// unicodeStrings are strings that trip up code handling text as bytes, or
+// that look different from what they are
+var unicodeStrings = []struct {
+	name  string
+	value string
+}{
+	{"a NUL byte", "before\x00after"},
+	{"control characters", "\x01\x07\x08\x1b[31mred\x1b[0m\x7f"},
+	{"a right-to-left override", "invoice\u202efdp.exe\u202c"},
+	{"zero-width characters", "zero\u200bwidth\u200c\u200d\u2060\ufeff"},
+	{"an emoji ZWJ sequence", "\U0001F469\u200d\U0001F469\u200d\U0001F467\u200d\U0001F466 \U0001F3F3\ufe0f\u200d\U0001F308"},
+	{"stacked combining marks", "Z" + strings.Repeat("\u0301\u0336\u0353", 40)},
+	{"astral-plane characters", "\U0001D573\U0001D58A\U0001D591\U0001D591\U0001D594 \U0001F600 \U0010FFFD"},
+	{"noncharacters", "\ufffe\uffff\U0010FFFF"},
+	{"line and paragraph separators", "one\u2028two\u2029three\r\nfour"},
+	{"mixed scripts", "Ελληνικά Русский 中文 العربية हिन्दी"},
+}
+
+// wrongTypes are values of each JSON type, tried where the schema allows
+// another
+var wrongTypes = []struct {
+	name  string
+	value interface{}
+}{
+	{"null", nil},
+	{"a boolean", true},
+	{"a number", 42},
+	{"a numeric string", "42"},
+	{"a string", "text"},
+	{"an array", []interface{}{"text"}},
+	{"an object", map[string]interface{}{"key": "text"}},
+}
+
+// Case is one input to call a tool with
+type Case struct {
+	Category  Category
+	Name      string // What the input tries, e.g. "limit = 101 (maximum + 1)"
+	Arguments map[string]interface{}
+	Valid     bool // Whether the arguments match the input schema
+}
+
+// Generate returns the inputs to try on a tool with an input schema, for
+// the given categories or, when there are none, for all of them
+func Generate(inputSchema map[string]interface{}, categories ...Category) []Case {
+	if len(categories) == 0 {
+		categories = Categories
+	}
+	g := newGenerator(inputSchema)
+	for _, category := range Categories {
+		for _, wanted := range categories {
+			if wanted == category {
+				g.generate(category)
+				break
+			}
+		}
+	}
+	return g.cases
+}
+
+// target is a property inputs are generated for
+type target struct {
+	name   string
+	schema map[string]interface{}
+	types  []string
+}
+
+// allows reports whether the property can hold a value of a type
+func (t target) allows(typ string) bool {
+	if len(t.types) == 0 {
+		return true
+	}
+	for _, allowed := range t.types {
+		if allowed == typ || (allowed == "number" && typ == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+type generator struct {
+	schema   map[string]interface{}
+	required []string
+	targets  []target
+	listed   bool                   // Whether the targets are listed in the schema
+	base     map[string]interface{} // The required properties, with sample values
+	cases    []Case
+}
+
+func newGenerator(inputSchema map[string]interface{}) *generator {
+	g := &generator{schema: inputSchema, base: make(map[string]interface{})}
+	properties, _ := inputSchema["properties"].(map[string]interface{})
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property, _ := properties[name].(map[string]interface{})
+		g.targets = append(g.targets, target{name: name, schema: property, types: schema.Types(property)})
+	}
+	g.listed = len(g.targets) > 0
+	if !g.listed {
+		g.targets = []target{{name: otherProperty}}
+	}
+
+	required, _ := inputSchema["required"].([]interface{})
+	for _, r := range required {
+		if name, ok := r.(string); ok {
+			g.required = append(g.required, name)
+			property, _ := properties[name].(map[string]interface{})
+			g.base[name] = sample(property, 0)
+		}
+	}
+	return g
+}
+
+// add records a case, checking its arguments against the schema
+func (g *generator) add(category Category, name string, args map[string]interface{}) {
+	g.cases = append(g.cases, Case{
+		Category:  category,
+		Name:      name,
+		Arguments: args,
+		Valid:     len(schema.Validate(g.schema, args)) == 0,
+	})
+}
+
+// with returns the base arguments with one property set
+func (g *generator) with(name string, value interface{}) map[string]interface{} {
+	args := maps.Clone(g.base)
+	args[name] = value
+	return args
+}
+
+func (g *generator) generate(category Category) {
+	switch category {
+	case Valid:
+		g.valid()
+	case Missing:
+		g.missing()
+	case Nesting:
+		g.nesting()
+	default:
+		for _, t := range g.targets {
+			switch category {
+			case Boundary:
+				g.boundary(t)
+			case Type:
+				g.wrongType(t)
+			case Huge:
+				g.huge(t)
+			case Unicode:
+				g.unicode(t)
+			}
+		}
+	}
+}
+
+func (g *generator) valid() {
+	if len(g.required) == 0 {
+		g.add(Valid, "no arguments", maps.Clone(g.base))
+	} else {
+		g.add(Valid, "sample values for the required properties", maps.Clone(g.base))
+	}
+	if !g.listed || len(g.targets) == len(g.required) {
+		return
+	}
+	args := maps.Clone(g.base)
+	for _, t := range g.targets {
+		if _, ok := args[t.name]; !ok {
+			args[t.name] = sample(t.schema, 0)
+		}
+	}
+	g.add(Valid, "sample values for every property", args)
+}
+
+func (g *generator) missing() {
+	for _, name := range g.required {
+		args := maps.Clone(g.base)
+		delete(args, name)
+		g.add(Missing, "without "+name, args)
+	}
+	if len(g.required) > 1 {
+		g.add(Missing, "no arguments", map[string]interface{}{})
+	}
+}
+
+func (g *generator) boundary(t target) {
+	seen := make(map[string]bool)
+	try := func(value interface{}, note string) {
+		data, _ := json.Marshal(value)
+		if seen[string(data)] {
+			return
+		}
+		seen[string(data)] = true
+		name := t.name + " = " + truncate(string(data))
+		if note != "" {
+			name += " (" + note + ")"
+		}
+		g.add(Boundary, name, g.with(t.name, value))
+	}
+	tryLength := func(length int, note string) {
+		if length < 0 || length > hugeStringSize {
+			return
+		}
+		value := strings.Repeat("a", length)
+		if seen[value] {
+			return
+		}
+		seen[value] = true
+		g.add(Boundary, fmt.Sprintf("%s has %d characters (%s)", t.name, length, note), g.with(t.name, value))
+	}
+
+	if enum, ok := t.schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		for _, value := range enum {
+			try(value, "listed")
+		}
+		try("not-a-listed-value", "not listed")
+	}
+
+	if t.allows("integer") || t.allows("number") {
+		integer := !t.allows("number") && len(t.types) > 0
+		for _, key := range []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum"} {
+			if n, ok := numberValue(t.schema[key]); ok {
+				try(n-1, key+" - 1")
+				try(n, key)
+				try(n+1, key+" + 1")
+			}
+		}
+		try(0, "")
+		try(-1, "")
+		if integer {
+			try(1.5, "a fraction")
+			try(int64(math.MaxInt32)+1, "2^31")
+			try(json.Number("9007199254740993"), "2^53 + 1")
+		} else {
+			try(1e308, "")
+			try(-1e308, "")
+			try(5e-324, "the smallest double")
+		}
+	}
+
+	if t.allows("string") && len(t.types) > 0 {
+		try("", "empty")
+		try("   ", "whitespace")
+		if n, ok := numberValue(t.schema["minLength"]); ok && n > 0 {
+			tryLength(int(n)-1, "minLength - 1")
+		}
+		if n, ok := numberValue(t.schema["maxLength"]); ok {
+			tryLength(int(n), "maxLength")
+			tryLength(int(n)+1, "maxLength + 1")
+		}
+		if format, ok := t.schema["format"].(string); ok {
+			try("not-a-"+format, "not a "+format)
+		}
+	}
+
+	if t.allows("array") && len(t.types) > 0 {
+		items, _ := t.schema["items"].(map[string]interface{})
+		try([]interface{}{}, "empty")
+		if n, ok := numberValue(t.schema["minItems"]); ok && n > 0 {
+			try(repeat(sample(items, 1), int(n)-1), "minItems - 1")
+		}
+		if n, ok := numberValue(t.schema["maxItems"]); ok && n < hugeArrayItems {
+			try(repeat(sample(items, 1), int(n)+1), "maxItems + 1")
+		}
+		if unique, _ := t.schema["uniqueItems"].(bool); unique {
+			try(repeat(sample(items, 1), 2), "duplicate items")
+		}
+	}
+
+	if t.allows("object") && len(t.types) > 0 {
+		try(map[string]interface{}{}, "empty")
+	}
+	if t.allows("boolean") && len(t.types) > 0 {
+		try(false, "")
+		try(true, "")
+	}
+}
+
+func (g *generator) wrongType(t target) {
+	if len(t.types) == 0 {
+		return
+	}
+	typeOnly := map[string]interface{}{"type": t.schema["type"]}
+	for _, wrong := range wrongTypes {
+		if len(schema.Validate(typeOnly, wrong.value)) > 0 {
+			g.add(Type, t.name+" is "+wrong.name, g.with(t.name, wrong.value))
+		}
+	}
+}
+
+func (g *generator) huge(t target) {
+	if t.allows("string") {
+		g.add(Huge, t.name+" is a 1 MiB string", g.with(t.name, strings.Repeat("A", hugeStringSize)))
+	}
+	if t.allows("array") {
+		items, _ := t.schema["items"].(map[string]interface{})
+		g.add(Huge, fmt.Sprintf("%s has %d items", t.name, hugeArrayItems), g.with(t.name, repeat(sample(items, 1), hugeArrayItems)))
+	}
+	if (t.allows("integer") || t.allows("number")) && len(t.types) > 0 {
+		digits := json.Number(strings.Repeat("9", hugeNumberDigit))
+		g.add(Huge, fmt.Sprintf("%s is a %d-digit number", t.name, hugeNumberDigit), g.with(t.name, digits))
+	}
+}
+
+func (g *generator) unicode(t target) {
+	if !t.allows("string") {
+		return
+	}
+	for _, s := range unicodeStrings {
+		g.add(Unicode, t.name+" has "+s.name, g.with(t.name, s.value))
+	}
+}
+
+func (g *generator) nesting() {
+	added := false
+	for _, t := range g.targets {
+		for _, kind := range []string{"object", "array"} {
+			if !t.allows(kind) {
+				continue
+			}
+			for _, depth := range nestingDepths {
+				g.add(Nesting, fmt.Sprintf("%s nests %ss %d deep", t.name, kind, depth), g.with(t.name, nested(kind, depth)))
+				added = true
+			}
+		}
+	}
+	if !added {
+		t := g.targets[0]
+		for _, depth := range nestingDepths {
+			g.add(Nesting, fmt.Sprintf("%s nests objects %d deep", t.name, depth), g.with(t.name, nested("object", depth)))
+		}
+	}
+}
+
+// nested returns objects or arrays nested depth deep
+func nested(kind string, depth int) interface{} {
+	var value interface{}
+	for i := 0; i < depth; i++ {
+		if kind == "array" {
+			value = []interface{}{value}
+		} else {
+			value = map[string]interface{}{"a": value}
+		}
+	}
+	return value
+}
+
+// repeat returns an array of n copies of value
+func repeat(value interface{}, n int) []interface{} {
+	items := make([]interface{}, n)
+	for i := range items {
+		items[i] = value
+	}
+	return items
+}
+
+// sampleStrings are values for string formats
+var sampleStrings = map[string]string{
+	"date-time": "2024-01-01T00:00:00Z",
+	"date":      "2024-01-01",
+	"time":      "00:00:00Z",
+	"email":     "user@example.com",
+	"uri":       "https://example.com/",
+	"url":       "https://example.com/",
+	"uuid":      "00000000-0000-4000-8000-000000000000",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"hostname":  "example.com",
+}
+
+// sample returns a plausible value for a schema: its const, default, first
+// enum value or example, or else a small value of its first type within
+// its limits
+func sample(s map[string]interface{}, depth int) interface{} {
+	if value, ok := s["const"]; ok {
+		return value
+	}
+	if value, ok := s["default"]; ok {
+		return value
+	}
+	for _, key := range []string{"enum", "examples"} {
+		if values, ok := s[key].([]interface{}); ok && len(values) > 0 {
+			return values[0]
+		}
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		if alternatives, ok := s[key].([]interface{}); ok && len(alternatives) > 0 {
+			alternative, _ := alternatives[0].(map[string]interface{})
+			return sample(alternative, depth)
+		}
+	}
+
+	typ := "string"
+	for _, t := range schema.Types(s) {
+		if t != "null" {
+			typ = t
+			break
+		}
+	}
+	switch typ {
+	case "integer", "number":
+		n := 1.0
+		if limit, ok := numberValue(s["minimum"]); ok && n < limit {
+			n = limit
+		}
+		if limit, ok := numberValue(s["exclusiveMinimum"]); ok && n <= limit {
+			n = limit + 1
+		}
+		if limit, ok := numberValue(s["maximum"]); ok && n > limit {
+			n = limit
+		}
+		if limit, ok := numberValue(s["exclusiveMaximum"]); ok && n >= limit {
+			n = limit - 1
+		}
+		if typ == "integer" {
+			return int64(math.Ceil(n))
+		}
+		return n
+	case "boolean":
+		return true
+	case "array":
+		items, _ := s["items"].(map[string]interface{})
+		n, _ := numberValue(s["minItems"])
+		out := make([]interface{}, 0, int(n))
+		for i := 0; i < int(n) && depth < sampleDepth; i++ {
+			out = append(out, sample(items, depth+1))
+		}
+		return out
+	case "object":
+		out := make(map[string]interface{})
+		if depth >= sampleDepth {
+			return out
+		}
+		properties, _ := s["properties"].(map[string]interface{})
+		required, _ := s["required"].([]interface{})
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				property, _ := properties[name].(map[string]interface{})
+				out[name] = sample(property, depth+1)
+			}
+		}
+		return out
+	}
+
+	value := "test"
+	if format, ok := s["format"].(string); ok && sampleStrings[format] != "" {
+		value = sampleStrings[format]
+	}
+	if n, ok := numberValue(s["minLength"]); ok && len(value) < int(n) {
+		value += strings.Repeat("x", int(n)-len(value))
+	}
+	if n, ok := numberValue(s["maxLength"]); ok && len(value) > int(n) && n >= 0 {
+		value = value[:int(n)]
+	}
+	return value
+}
+
+// numberValue returns a JSON number as a float64
+func numberValue(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// truncate shortens a value for a case name
+func truncate(s string) string {
+	const max = 40
+	if runes := []rune(s); len(runes) > max {
+		return string(runes[:max]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,195 @@
+package fuzz
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"sort"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp/schema"
+)
+
+const (
+	// shrinkLevels is how far into the arguments values are shrunk one at
+	// a time; deeper values are only cut down by halving their nesting
+	shrinkLevels = 3
+
+	// shrinkItems is the most items an array can have for them to be
+	// shrunk one at a time; longer arrays are cut in half first
+	shrinkItems = 16
+)
+
+// minimize looks for smaller arguments that fail the same way, greedily:
+// the first smaller candidate that still fails replaces the arguments, until
+// none does or the attempts run out. Required properties the input has are
+// kept, since leaving them out is a case of its own, and one that would
+// otherwise stand in for every failure it also causes. Calls that crash the
+// server are repeated on a new connection.
+func (f *Fuzzer) minimize(ctx context.Context, t tool, finding *Finding) {
+	attempts := maxAttempts
+	if finding.Kind == Timeout || finding.Kind == Hang {
+		attempts = maxTimeoutAttempts
+	}
+	var keep []string
+	required, _ := t.InputSchema["required"].([]interface{})
+	for _, r := range required {
+		if name, ok := r.(string); ok {
+			if _, present := finding.Arguments[name]; present {
+				keep = append(keep, name)
+			}
+		}
+	}
+
+	args := finding.Arguments
+	for shrunk := true; shrunk && attempts > 0; {
+		shrunk = false
+		for _, candidate := range shrinkObject(args, 0) {
+			if attempts == 0 || ctx.Err() != nil {
+				break
+			}
+			if !hasKeys(candidate, keep) {
+				continue
+			}
+			attempts--
+			valid := len(schema.Validate(t.InputSchema, candidate)) == 0
+			kind, detail, err := f.try(ctx, t.Name, candidate, valid)
+			if err != nil {
+				return
+			}
+			if kind == finding.Kind {
+				args = candidate
+				finding.Arguments, finding.Valid, finding.Detail = candidate, valid, detail
+				finding.Minimized = true
+				shrunk = true
+				break
+			}
+		}
+	}
+}
+
+// hasKeys reports whether an object has every one of keys
+func hasKeys(object map[string]interface{}, keys []string) bool {
+	for _, key := range keys {
+		if _, ok := object[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// shrinkObject returns smaller versions of an object, each one change away:
+// a property removed, or a property's value shrunk
+func shrinkObject(args map[string]interface{}, level int) []map[string]interface{} {
+	keys := make([]string, 0, len(args))
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var candidates []map[string]interface{}
+	for _, key := range keys {
+		candidate := maps.Clone(args)
+		delete(candidate, key)
+		candidates = append(candidates, candidate)
+	}
+	for _, key := range keys {
+		for _, value := range shrinkValue(args[key], level+1) {
+			candidate := maps.Clone(args)
+			candidate[key] = value
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
+
+// shrinkValue returns smaller versions of a value: strings and arrays cut
+// in half, objects with a property removed, and nested values with half
+// their depth
+func shrinkValue(value interface{}, level int) []interface{} {
+	var candidates []interface{}
+	switch v := value.(type) {
+	case string:
+		runes := []rune(v)
+		if len(runes) > 1 {
+			half := len(runes) / 2
+			candidates = append(candidates, string(runes[:half]), string(runes[half:]))
+		}
+	case []interface{}:
+		if d := depth(v); d > 2 {
+			candidates = append(candidates, descend(v, d/2))
+		}
+		if len(v) > 1 {
+			half := len(v) / 2
+			candidates = append(candidates, v[:half], v[half:])
+		}
+		if level < shrinkLevels && len(v) <= shrinkItems {
+			for i, item := range v {
+				for _, smaller := range shrinkValue(item, level+1) {
+					candidate := append([]interface{}(nil), v...)
+					candidate[i] = smaller
+					candidates = append(candidates, candidate)
+				}
+			}
+		}
+	case map[string]interface{}:
+		if d := depth(v); d > 2 {
+			candidates = append(candidates, descend(v, d/2))
+		}
+		if level < shrinkLevels {
+			for _, smaller := range shrinkObject(v, level) {
+				candidates = append(candidates, smaller)
+			}
+		}
+	}
+
+	// A candidate equal to the value would be accepted over and over
+	out := candidates[:0]
+	for _, candidate := range candidates {
+		if !reflect.DeepEqual(candidate, value) {
+			out = append(out, candidate)
+		}
+	}
+	return out
+}
+
+// depth returns how deeply objects and arrays are nested in a value
+func depth(value interface{}) int {
+	deepest := 0
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			deepest = max(deepest, depth(item))
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			deepest = max(deepest, depth(item))
+		}
+	default:
+		return 0
+	}
+	return deepest + 1
+}
+
+// descend follows a value's only child n levels down, stopping at a value
+// without exactly one
+func descend(value interface{}, n int) interface{} {
+	for ; n > 0; n-- {
+		switch v := value.(type) {
+		case []interface{}:
+			if len(v) != 1 {
+				return value
+			}
+			value = v[0]
+		case map[string]interface{}:
+			if len(v) != 1 {
+				return value
+			}
+			for _, child := range v {
+				value = child
+			}
+		default:
+			return value
+		}
+	}
+	return value
+}
@@ -0,0 +1,110 @@
+package fuzz
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxShownArguments is how much of a finding's arguments the text report
+// shows
+const maxShownArguments = 120
+
+// kindNames name each kind of finding in the summary, singular and plural
+var kindNames = map[Kind][2]string{
+	Crash:         {"crash", "crashes"},
+	ProtocolError: {"protocol error", "protocol errors"},
+	Timeout:       {"timeout", "timeouts"},
+	Hang:          {"hang", "hangs"},
+}
+
+// WriteText writes a readable report: a line per tool, then each finding
+// with what went wrong, its arguments and reproducer, and a summary
+func WriteText(w io.Writer, report *Report) error {
+	var b strings.Builder
+	server := report.Server
+	if server == "" {
+		server = "MCP server"
+	}
+	fmt.Fprintf(&b, "Fuzzing %s: %s, %s\n", server, count(len(report.Tools), "tool", "tools"), count(report.Summary.Cases, "case", "cases"))
+	if report.Interrupted {
+		b.WriteString("Interrupted: the results cover the cases tried so far\n")
+	}
+	b.WriteString("\n")
+
+	for _, tool := range report.Tools {
+		switch {
+		case tool.Skipped != "":
+			fmt.Fprintf(&b, "  - %s (skipped: %s)\n", tool.Name, tool.Skipped)
+		case tool.Findings > 0:
+			fmt.Fprintf(&b, "  ✗ %s: %s, %s\n", tool.Name, count(tool.Cases, "case", "cases"), count(tool.Findings, "finding", "findings"))
+		default:
+			fmt.Fprintf(&b, "  ✓ %s: %s\n", tool.Name, count(tool.Cases, "case", "cases"))
+		}
+	}
+
+	if len(report.Findings) > 0 {
+		b.WriteString("\nFindings\n")
+	}
+	for i, finding := range report.Findings {
+		fmt.Fprintf(&b, "\n  %d. %s: %s on %q (%s)\n", i+1, finding.Tool, kindNames[finding.Kind][0], finding.Input, finding.Category)
+		fmt.Fprintf(&b, "     %s\n", finding.Detail)
+		label := "arguments"
+		if finding.Minimized {
+			label = "minimized"
+		}
+		fmt.Fprintf(&b, "     %s: %s\n", label, showArguments(finding.Arguments))
+		if len(finding.Also) > 0 {
+			also := make([]string, len(finding.Also))
+			for i, input := range finding.Also {
+				also[i] = strconv.Quote(input)
+			}
+			fmt.Fprintf(&b, "     also from: %s\n", strings.Join(also, ", "))
+		}
+		if finding.Reproducer != "" {
+			fmt.Fprintf(&b, "     reproduce: %s\n", finding.Reproducer)
+		}
+	}
+
+	s := report.Summary
+	var kinds []string
+	for _, kind := range []struct {
+		kind Kind
+		n    int
+	}{{Crash, s.Crashes}, {ProtocolError, s.ProtocolErrors}, {Timeout, s.Timeouts}, {Hang, s.Hangs}} {
+		if kind.n > 0 {
+			kinds = append(kinds, count(kind.n, kindNames[kind.kind][0], kindNames[kind.kind][1]))
+		}
+	}
+	duration := (time.Duration(report.DurationMs) * time.Millisecond).String()
+	if len(kinds) == 0 {
+		fmt.Fprintf(&b, "\nNo findings in %s, %s\n", count(s.Cases, "case", "cases"), duration)
+	} else {
+		fmt.Fprintf(&b, "\n%s (%s) in %s, %s\n", count(s.Findings, "finding", "findings"), strings.Join(kinds, ", "),
+			count(s.Cases, "case", "cases"), duration)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// showArguments shows the start of the arguments, escaped as in reproducers
+func showArguments(args map[string]interface{}) string {
+	data, err := asciiJSON(args)
+	if err != nil {
+		return err.Error()
+	}
+	if len(data) > maxShownArguments {
+		return fmt.Sprintf("%s... (%d bytes)", data[:maxShownArguments], len(data))
+	}
+	return string(data)
+}
+
+// count formats a number with a singular or plural noun
+func count(n int, singular, plural string) string {
+	if n == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
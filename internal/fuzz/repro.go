@@ -0,0 +1,118 @@
+package fuzz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// maxCommandLine is the longest reproducer written as a command line; the
+// arguments of longer ones go in a file for --input-json
+const maxCommandLine = 4096
+
+var (
+	// plainKey matches the keys tool call takes as key:=<json>
+	plainKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+	// plainWord matches the words a shell leaves alone
+	plainWord = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+	// unsafeFileChars are replaced in the names of argument files
+	unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+)
+
+// Reproducer returns a command line that makes a finding's call again with
+// tool call, after prefix, which runs mcp-tui with the server's connection.
+// Arguments are given as key:=<json>, with every character outside ASCII
+// escaped so that none hides in a terminal. Arguments too long for a
+// command line, or with keys tool call would read as paths, are written to
+// a file in dir, named after the finding's index, for --input-json; dir is
+// created if needed.
+func Reproducer(prefix, dir string, index int, finding Finding) (string, error) {
+	words := []string{prefix, "tool", "call", ShellQuote(finding.Tool)}
+	if !finding.Valid {
+		words = append(words, "--no-validate")
+	}
+
+	keys := make([]string, 0, len(finding.Arguments))
+	for key := range finding.Arguments {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	inline := append([]string(nil), words...)
+	length := len(strings.Join(words, " "))
+	for _, key := range keys {
+		if !plainKey.MatchString(key) || length > maxCommandLine {
+			inline = nil
+			break
+		}
+		data, err := asciiJSON(finding.Arguments[key])
+		if err != nil {
+			return "", err
+		}
+		word := ShellQuote(key + ":=" + string(data))
+		inline = append(inline, word)
+		length += len(word) + 1
+	}
+	if inline != nil && length <= maxCommandLine {
+		return strings.Join(inline, " "), nil
+	}
+
+	data, err := asciiJSON(finding.Arguments)
+	if err != nil {
+		return "", err
+	}
+	name := filepath.Join(dir, fmt.Sprintf("fuzz-%d-%s.json", index, unsafeFileChars.ReplaceAllString(finding.Tool, "_")))
+	// The arguments may hold whatever the server was sent, so only the
+	// owner may read them
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create the reproducer directory: %w", err)
+	}
+	if err := os.WriteFile(name, append(data, '\n'), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write the arguments of finding %d: %w", index, err)
+	}
+	if err := os.Chmod(name, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write the arguments of finding %d: %w", index, err)
+	}
+	return strings.Join(append(words, "--input-json", ShellQuote(name)), " "), nil
+}
+
+// asciiJSON encodes a value as JSON with every character outside printable
+// ASCII escaped
+func asciiJSON(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	for _, r := range strings.TrimSuffix(buf.String(), "\n") {
+		switch {
+		case r < 0x7f:
+			out.WriteRune(r)
+		case r > 0xffff:
+			high, low := utf16.EncodeRune(r)
+			fmt.Fprintf(&out, `\u%04x\u%04x`, high, low)
+		default:
+			fmt.Fprintf(&out, `\u%04x`, r)
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// ShellQuote quotes a word for a POSIX shell, when it needs quoting
+func ShellQuote(word string) string {
+	if plainWord.MatchString(word) {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
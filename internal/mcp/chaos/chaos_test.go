@@ -0,0 +1,246 @@
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/yamlutil"
+)
+
+const (
+	request  = `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"echo"}}`
+	response = `{"jsonrpc":"2.0","id":1,"result":{"content":[{"type":"text","text":"a, b: {c}"}]}}`
+)
+
+// collect runs frames through a pipe built from the faults and returns what came out
+func collect(t *testing.T, from Direction, faults []Fault, frames ...string) []string {
+	t.Helper()
+	config := &Config{Seed: 1, Faults: faults}
+	require.NoError(t, config.Validate())
+
+	var out []string
+	pipe := NewInjector(config).Pipe(from, func(frame []byte) error {
+		out = append(out, string(frame))
+		return nil
+	})
+	for _, frame := range frames {
+		require.NoError(t, pipe.Send(context.Background(), []byte(frame)))
+	}
+	require.NoError(t, pipe.Flush())
+	return out
+}
+
+func TestPipeFaults(t *testing.T) {
+	t.Run("drop on schedule", func(t *testing.T) {
+		out := collect(t, FromServer, []Fault{{Type: FaultDrop, At: []int{2}}}, "1", "2", "3")
+		assert.Equal(t, []string{"1", "3"}, out)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		out := collect(t, FromServer, []Fault{{Type: FaultDuplicate, At: []int{1}}}, "1", "2")
+		assert.Equal(t, []string{"1", "1", "2"}, out)
+	})
+
+	t.Run("reorder swaps with the next frame", func(t *testing.T) {
+		out := collect(t, FromServer, []Fault{{Type: FaultReorder, At: []int{1}}}, "1", "2", "3")
+		assert.Equal(t, []string{"2", "1", "3"}, out)
+	})
+
+	t.Run("direction filter", func(t *testing.T) {
+		out := collect(t, FromClient, []Fault{{Type: FaultDrop, From: FromServer}}, "1", "2")
+		assert.Equal(t, []string{"1", "2"}, out)
+	})
+
+	t.Run("truncate and corrupt break the JSON", func(t *testing.T) {
+		for _, faultType := range []FaultType{FaultTruncate, FaultCorrupt} {
+			for seed := int64(1); seed <= 50; seed++ {
+				config := &Config{Seed: seed, Faults: []Fault{{Type: faultType}}}
+				require.NoError(t, config.Validate())
+				var out []byte
+				pipe := NewInjector(config).Pipe(FromServer, func(frame []byte) error {
+					out = frame
+					return nil
+				})
+				require.NoError(t, pipe.Send(context.Background(), []byte(response)))
+				assert.False(t, json.Valid(out), "%s with seed %d produced valid JSON: %s", faultType, seed, out)
+			}
+		}
+	})
+
+	t.Run("oversize keeps the JSON valid", func(t *testing.T) {
+		out := collect(t, FromServer, []Fault{{Type: FaultOversize, Size: 4096}}, response, `{}`)
+		require.Len(t, out, 2)
+		for _, frame := range out {
+			assert.True(t, json.Valid([]byte(frame)), frame)
+			assert.Greater(t, len(frame), 4096)
+		}
+		var msg struct {
+			Result map[string]any `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(out[0]), &msg))
+		assert.Contains(t, msg.Result, "_chaos", "padding should go inside the result")
+		assert.Contains(t, msg.Result, "content")
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		start := time.Now()
+		collect(t, FromServer, []Fault{{Type: FaultLatency, Latency: yamlutil.Duration(50 * time.Millisecond)}}, "1")
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("disconnect", func(t *testing.T) {
+		config := &Config{Faults: []Fault{{Type: FaultDisconnect, At: []int{2}}}}
+		require.NoError(t, config.Validate())
+		pipe := NewInjector(config).Pipe(FromClient, func([]byte) error { return nil })
+		assert.NoError(t, pipe.Send(context.Background(), []byte("1")))
+		assert.ErrorIs(t, pipe.Send(context.Background(), []byte("2")), ErrDisconnect)
+	})
+}
+
+func TestMethodFilterMatchesResponses(t *testing.T) {
+	config := &Config{Faults: []Fault{{Type: FaultDrop, From: FromServer, Method: "tools/call"}}}
+	require.NoError(t, config.Validate())
+	injector := NewInjector(config)
+
+	var delivered []string
+	client := injector.Pipe(FromClient, func([]byte) error { return nil })
+	server := injector.Pipe(FromServer, func(frame []byte) error {
+		delivered = append(delivered, string(frame))
+		return nil
+	})
+
+	ctx := context.Background()
+	require.NoError(t, client.Send(ctx, []byte(request)))
+	require.NoError(t, client.Send(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)))
+	require.NoError(t, server.Send(ctx, []byte(`{"jsonrpc":"2.0","id":2,"result":{"tools":[]}}`)))
+	require.NoError(t, server.Send(ctx, []byte(response)))
+
+	assert.Equal(t, []string{`{"jsonrpc":"2.0","id":2,"result":{"tools":[]}}`}, delivered,
+		"only the tools/call response should be dropped")
+}
+
+func TestSeedIsReproducible(t *testing.T) {
+	p := 0.5
+	faults := []Fault{{Type: FaultDrop, Probability: &p}}
+	frames := strings.Split("1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16", " ")
+
+	first := collect(t, FromServer, faults, frames...)
+	second := collect(t, FromServer, faults, frames...)
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, len(frames), len(first), "some frames should be dropped")
+	assert.NotEmpty(t, first, "some frames should survive")
+}
+
+func TestParseFault(t *testing.T) {
+	fault, err := ParseFault("latency,latency=200ms,jitter=50,from=server,method=tools/call")
+	require.NoError(t, err)
+	assert.Equal(t, FaultLatency, fault.Type)
+	assert.Equal(t, yamlutil.Duration(200*time.Millisecond), fault.Latency)
+	assert.Equal(t, yamlutil.Duration(50*time.Millisecond), fault.Jitter)
+	assert.Equal(t, FromServer, fault.From)
+	assert.Equal(t, "tools/call", fault.Method)
+
+	fault, err = ParseFault("corrupt,at=3,at=5,probability=0.5")
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 5}, fault.At)
+	assert.Equal(t, 0.5, *fault.Probability)
+	assert.Equal(t, FromBoth, fault.From)
+
+	fault, err = ParseFault("oversize")
+	require.NoError(t, err)
+	assert.Equal(t, defaultOversize, fault.Size)
+
+	for spec, errMsg := range map[string]string{
+		"explode":              "unknown fault type",
+		"drop,probability=2":   "between 0 and 1",
+		"drop,from=nowhere":    "from must be",
+		"drop,at=0":            "1-based",
+		"drop,chance=0.5":      "unknown field",
+		"drop,probability":     "key=value",
+		"latency":              "needs latency or jitter",
+		"latency,latency=soon": "invalid duration",
+		"reorder,hold=-1s":     "must not be negative",
+		",probability=0.1":     "type is required",
+	} {
+		_, err := ParseFault(spec)
+		require.Error(t, err, spec)
+		assert.Contains(t, err.Error(), errMsg, spec)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte(`
+seed: 42
+faults:
+  - type: latency
+    latency: 100ms
+    jitter: 50ms
+  - type: reorder
+    from: server
+  - type: disconnect
+    at: [20]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), config.Seed)
+	require.Len(t, config.Faults, 3)
+	assert.Equal(t, yamlutil.Duration(defaultHold), config.Faults[1].Hold)
+	assert.Equal(t, []int{20}, config.Faults[2].At)
+	assert.Equal(t, int64(42), NewInjector(config).Seed())
+
+	_, err = ParseConfig([]byte(`faults: [{type: drop, probabilty: 0.5}]`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown field")
+}
+
+func TestProxy(t *testing.T) {
+	config := &Config{Faults: []Fault{
+		{Type: FaultDuplicate, From: FromServer, At: []int{1}},
+		{Type: FaultDrop, From: FromClient, At: []int{2}},
+	}}
+	require.NoError(t, config.Validate())
+
+	// The "server" echoes every line it receives
+	serverInReader, serverIn := io.Pipe()
+	serverOutReader, serverOut := io.Pipe()
+	go func() {
+		io.Copy(serverOut, serverInReader)
+		serverOut.Close()
+	}()
+
+	var clientOut bytes.Buffer
+	clientIn := strings.NewReader("one\ntwo\r\nthree\n\nfour")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, NewInjector(config).Proxy(ctx, clientIn, &clientOut, serverIn, serverOutReader))
+	assert.Equal(t, "one\none\nthree\nfour\n", clientOut.String())
+}
+
+func TestProxyDisconnect(t *testing.T) {
+	config := &Config{Faults: []Fault{{Type: FaultDisconnect, From: FromClient, At: []int{1}}}}
+	require.NoError(t, config.Validate())
+
+	_, serverIn := io.Pipe()
+	serverOutReader, _ := io.Pipe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := NewInjector(config).Proxy(ctx, strings.NewReader("one\n"), io.Discard, serverIn, serverOutReader)
+	assert.ErrorIs(t, err, ErrDisconnect)
+}
+
+func TestExampleConfig(t *testing.T) {
+	config, err := LoadConfig(filepath.Join("..", "..", "..", "examples", "chaos.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234), config.Seed)
+	assert.Len(t, config.Faults, 7)
+}
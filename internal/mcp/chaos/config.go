@@ -0,0 +1,202 @@
+// Package chaos injects faults into a JSON-RPC stream. Faults operate on
+// newline-delimited frames so the same schedule can drive the in-process
+// transport wrapper and the byte-level proxy in front of a server process.
+package chaos
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/standardbeagle/mcp-tui/internal/yamlutil"
+)
+
+// FaultType names a kind of fault
+type FaultType string
+
+const (
+	FaultLatency    FaultType = "latency"    // Delay the frame by latency plus up to jitter
+	FaultDrop       FaultType = "drop"       // Discard the frame
+	FaultDuplicate  FaultType = "duplicate"  // Deliver the frame twice
+	FaultReorder    FaultType = "reorder"    // Hold the frame until the next one has been delivered
+	FaultTruncate   FaultType = "truncate"   // Cut the frame short
+	FaultCorrupt    FaultType = "corrupt"    // Break the JSON syntax of the frame
+	FaultOversize   FaultType = "oversize"   // Pad the frame with a large field
+	FaultDisconnect FaultType = "disconnect" // Close the connection
+)
+
+// Direction selects which side's frames a fault applies to
+type Direction string
+
+const (
+	FromClient Direction = "client" // Frames sent by the client to the server
+	FromServer Direction = "server" // Frames sent by the server to the client
+	FromBoth   Direction = "both"
+)
+
+const (
+	defaultOversize = 1 << 20
+	defaultHold     = 500 * time.Millisecond
+)
+
+// Config is a fault schedule
+type Config struct {
+	Seed   int64   `json:"seed"` // Random seed for reproducible runs; 0 picks one
+	Faults []Fault `json:"faults"`
+}
+
+// Fault is one fault rule. A frame is affected when it matches the direction
+// and method filters and either its number is listed in At or, without a
+// schedule, the probability roll succeeds. Faults apply in the order listed.
+type Fault struct {
+	Type        FaultType `json:"type"`
+	From        Direction `json:"from"`        // client, server or both (the default)
+	Method      string    `json:"method"`      // Only frames for this method; responses match their request's method
+	Probability *float64  `json:"probability"` // 0..1, always when omitted
+	At          []int     `json:"at"`          // Scripted schedule: 1-based frame numbers in each direction
+
+	Latency yamlutil.Duration `json:"latency"` // latency: fixed delay
+	Jitter  yamlutil.Duration `json:"jitter"`  // latency: random extra delay up to this much
+	Hold    yamlutil.Duration `json:"hold"`    // reorder: how long a held frame waits for the next one
+	Size    int               `json:"size"`    // oversize: padding in bytes
+}
+
+// LoadConfig reads a fault schedule from a YAML or JSON file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chaos config: %w", err)
+	}
+	config, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chaos config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// ParseConfig parses a YAML or JSON fault schedule
+func ParseConfig(data []byte) (*Config, error) {
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := decodeStrict(raw, &config); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// ParseFault parses the compact command line form of a fault:
+// a type followed by comma-separated key=value options, for example
+// "latency,latency=200ms,jitter=50ms" or "disconnect,from=server,at=5".
+// Repeating "at" adds frames to the schedule.
+func ParseFault(spec string) (Fault, error) {
+	parts := strings.Split(spec, ",")
+	raw := map[string]any{"type": strings.TrimSpace(parts[0])}
+	var at []any
+	for _, part := range parts[1:] {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Fault{}, fmt.Errorf("fault %q: expected key=value, got %q", spec, part)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		var parsed any = value
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			parsed = n
+		}
+		if key == "at" {
+			at = append(at, parsed)
+			continue
+		}
+		raw[key] = parsed
+	}
+	if at != nil {
+		raw["at"] = at
+	}
+
+	var fault Fault
+	if err := decodeStrict(raw, &fault); err != nil {
+		return Fault{}, fmt.Errorf("fault %q: %w", spec, err)
+	}
+	if err := fault.validate(); err != nil {
+		return Fault{}, fmt.Errorf("fault %q: %w", spec, err)
+	}
+	return fault, nil
+}
+
+// decodeStrict converts generic YAML values through JSON, rejecting unknown
+// fields so a misspelled option does not silently disable a fault
+func decodeStrict(raw any, v any) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("config is not JSON-compatible: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+// Validate checks every fault and applies defaults
+func (c *Config) Validate() error {
+	for i := range c.Faults {
+		if err := c.Faults[i].validate(); err != nil {
+			return fmt.Errorf("faults[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validate checks a fault and applies its defaults
+func (f *Fault) validate() error {
+	switch f.Type {
+	case FaultLatency:
+		if f.Latency <= 0 && f.Jitter <= 0 {
+			return fmt.Errorf("latency fault needs latency or jitter")
+		}
+	case FaultOversize:
+		if f.Size == 0 {
+			f.Size = defaultOversize
+		}
+	case FaultReorder:
+		if f.Hold == 0 {
+			f.Hold = yamlutil.Duration(defaultHold)
+		}
+	case FaultDrop, FaultDuplicate, FaultTruncate, FaultCorrupt, FaultDisconnect:
+	case "":
+		return fmt.Errorf("type is required")
+	default:
+		return fmt.Errorf("unknown fault type %q", f.Type)
+	}
+
+	switch f.From {
+	case "":
+		f.From = FromBoth
+	case FromClient, FromServer, FromBoth:
+	default:
+		return fmt.Errorf("from must be client, server or both, got %q", f.From)
+	}
+	if f.Probability != nil {
+		if p := *f.Probability; p < 0 || p > 1 {
+			return fmt.Errorf("probability must be between 0 and 1, got %v", p)
+		}
+	}
+	for _, n := range f.At {
+		if n < 1 {
+			return fmt.Errorf("at lists 1-based frame numbers, got %d", n)
+		}
+	}
+	if f.Latency < 0 || f.Jitter < 0 || f.Hold < 0 || f.Size < 0 {
+		return fmt.Errorf("latency, jitter, hold and size must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+)
+
+// ErrDisconnect is returned when a disconnect fault fires
+var ErrDisconnect = errors.New("chaos: injected disconnect")
+
+// Injector decides which faults hit each frame. One injector is shared by the
+// pipes of a connection so frame numbers and request methods are tracked
+// across both directions.
+type Injector struct {
+	faults []Fault
+	seed   int64
+
+	mu      sync.Mutex
+	rng     *rand.Rand
+	counts  map[Direction]int
+	methods map[string]string // Request ID → method, so responses can match a method filter
+}
+
+// NewInjector creates an injector for a validated config
+func NewInjector(config *Config) *Injector {
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Injector{
+		faults:  config.Faults,
+		seed:    seed,
+		rng:     rand.New(rand.NewSource(seed)),
+		counts:  make(map[Direction]int),
+		methods: make(map[string]string),
+	}
+}
+
+// Seed returns the random seed, so a run can be reproduced
+func (i *Injector) Seed() int64 {
+	return i.seed
+}
+
+// plan numbers a frame and returns the faults that apply to it
+func (i *Injector) plan(from Direction, frame []byte) (int, []Fault) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.counts[from]++
+	n := i.counts[from]
+	method := i.methodOf(from, frame)
+
+	var faults []Fault
+	for _, f := range i.faults {
+		if f.From != FromBoth && f.From != from {
+			continue
+		}
+		if f.Method != "" && f.Method != method {
+			continue
+		}
+		if len(f.At) > 0 {
+			if slices.Contains(f.At, n) {
+				faults = append(faults, f)
+			}
+			continue
+		}
+		if f.Probability == nil || i.rng.Float64() < *f.Probability {
+			faults = append(faults, f)
+		}
+	}
+	return n, faults
+}
+
+// methodOf returns the method a frame belongs to. Requests are remembered by
+// ID so the response travelling the other way reports the same method.
+func (i *Injector) methodOf(from Direction, frame []byte) string {
+	var msg struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	if json.Unmarshal(frame, &msg) != nil {
+		return ""
+	}
+	if msg.Method != "" {
+		if len(msg.ID) > 0 {
+			i.methods[string(from)+string(msg.ID)] = msg.Method
+		}
+		return msg.Method
+	}
+	requester := FromClient
+	if from == FromClient {
+		requester = FromServer
+	}
+	key := string(requester) + string(msg.ID)
+	method := i.methods[key]
+	delete(i.methods, key)
+	return method
+}
+
+// intn returns a random number in [0, n)
+func (i *Injector) intn(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rng.Intn(n)
+}
+
+// delay returns the latency for a fault, including random jitter
+func (i *Injector) delay(f Fault) time.Duration {
+	d := time.Duration(f.Latency)
+	if f.Jitter > 0 {
+		i.mu.Lock()
+		d += time.Duration(i.rng.Int63n(int64(f.Jitter) + 1))
+		i.mu.Unlock()
+	}
+	return d
+}
+
+// truncate cuts a frame at a random point, which never leaves valid JSON
+func (i *Injector) truncate(frame []byte) []byte {
+	if len(frame) < 2 {
+		return nil
+	}
+	return frame[:1+i.intn(len(frame)-1)]
+}
+
+// corrupt replaces a structural character outside of strings, so the frame
+// is guaranteed not to parse
+func (i *Injector) corrupt(frame []byte) []byte {
+	var positions []int
+	inString, escaped := false, false
+	for pos, b := range frame {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && b == '\\':
+			escaped = true
+		case b == '"':
+			inString = !inString
+			positions = append(positions, pos)
+		case !inString && bytes.IndexByte([]byte("{}[]:,"), b) >= 0:
+			positions = append(positions, pos)
+		}
+	}
+	if len(positions) == 0 {
+		return frame
+	}
+	corrupted := bytes.Clone(frame)
+	corrupted[positions[i.intn(len(positions))]] = '#'
+	return corrupted
+}
+
+// oversize pads the result or params object of a frame with a large field,
+// so the payload survives decoding; the frame stays valid JSON
+func oversize(frame []byte, size int) []byte {
+	var msg map[string]json.RawMessage
+	if json.Unmarshal(frame, &msg) != nil {
+		return padObject(frame, size)
+	}
+	for _, key := range []string{"result", "params"} {
+		if value, ok := msg[key]; ok && bytes.HasPrefix(bytes.TrimSpace(value), []byte("{")) {
+			msg[key] = padObject(value, size)
+			if padded, err := json.Marshal(msg); err == nil {
+				return padded
+			}
+		}
+	}
+	return padObject(frame, size)
+}
+
+// padObject adds a "_chaos" field of size bytes to a JSON object
+func padObject(object []byte, size int) []byte {
+	trimmed := bytes.TrimSpace(object)
+	if len(trimmed) < 2 || trimmed[0] != '{' {
+		return object
+	}
+	padded := make([]byte, 0, len(trimmed)+size+16)
+	padded = append(padded, `{"_chaos":"`...)
+	padded = append(padded, bytes.Repeat([]byte("x"), size)...)
+	padded = append(padded, '"')
+	if rest := bytes.TrimSpace(trimmed[1:]); len(rest) > 0 && rest[0] != '}' {
+		padded = append(padded, ',')
+	}
+	return append(padded, trimmed[1:]...)
+}
+
+// Pipe applies faults to the frames travelling in one direction and passes
+// the survivors to emit
+type Pipe struct {
+	injector *Injector
+	from     Direction
+	emit     func([]byte) error
+
+	mu    sync.Mutex // Serializes emit, including held frames released by the timer
+	held  []byte
+	timer *time.Timer
+}
+
+// Pipe creates a pipe for frames sent by one side of the connection
+func (i *Injector) Pipe(from Direction, emit func([]byte) error) *Pipe {
+	return &Pipe{injector: i, from: from, emit: emit}
+}
+
+// Send runs one frame through the fault schedule. It returns ErrDisconnect
+// when the connection should be closed.
+func (p *Pipe) Send(ctx context.Context, frame []byte) error {
+	n, faults := p.injector.plan(p.from, frame)
+
+	var delay, hold time.Duration
+	duplicate := false
+	for _, f := range faults {
+		debug.Info("Chaos: Injecting fault", debug.F("fault", f.Type), debug.F("from", p.from), debug.F("frame", n))
+		switch f.Type {
+		case FaultDisconnect:
+			return ErrDisconnect
+		case FaultDrop:
+			return nil
+		case FaultLatency:
+			delay += p.injector.delay(f)
+		case FaultDuplicate:
+			duplicate = true
+		case FaultReorder:
+			hold = time.Duration(f.Hold)
+		case FaultTruncate:
+			frame = p.injector.truncate(frame)
+		case FaultCorrupt:
+			frame = p.injector.corrupt(frame)
+		case FaultOversize:
+			frame = oversize(frame, f.Size)
+		}
+	}
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if hold > 0 && p.held == nil {
+		p.held = frame
+		p.timer = time.AfterFunc(hold, p.release)
+		return nil
+	}
+	if err := p.emit(frame); err != nil {
+		return err
+	}
+	if duplicate {
+		if err := p.emit(frame); err != nil {
+			return err
+		}
+	}
+	return p.flushLocked()
+}
+
+// Flush delivers a frame held for reordering
+func (p *Pipe) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.flushLocked()
+}
+
+// release delivers a held frame that no other frame overtook in time
+func (p *Pipe) release() {
+	if err := p.Flush(); err != nil {
+		debug.Warn("Chaos: Failed to deliver held frame", debug.F("error", err))
+	}
+}
+
+func (p *Pipe) flushLocked() error {
+	if p.held == nil {
+		return nil
+	}
+	frame := p.held
+	p.held = nil
+	p.timer.Stop()
+	return p.emit(frame)
+}
@@ -0,0 +1,69 @@
+package chaos
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+)
+
+// Proxy copies newline-delimited frames between a client and a server,
+// injecting faults in both directions. Because it works on raw bytes,
+// truncated and corrupted frames reach the other side exactly as produced.
+// When the client closes its input the server's input is closed too, and
+// Proxy returns once the server's output ends, a disconnect fault fires or
+// ctx is cancelled.
+func (i *Injector) Proxy(ctx context.Context, clientIn io.Reader, clientOut io.Writer, serverIn io.WriteCloser, serverOut io.Reader) error {
+	clientDone := make(chan error, 1)
+	serverDone := make(chan error, 1)
+	go func() {
+		err := i.pump(ctx, FromClient, clientIn, serverIn)
+		serverIn.Close()
+		clientDone <- err
+	}()
+	go func() {
+		serverDone <- i.pump(ctx, FromServer, serverOut, clientOut)
+	}()
+
+	for {
+		select {
+		case err := <-clientDone:
+			if err != nil {
+				return err
+			}
+			clientDone = nil
+		case err := <-serverDone:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// pump reads frames from r, runs them through a pipe and writes them to w
+func (i *Injector) pump(ctx context.Context, from Direction, r io.Reader, w io.Writer) error {
+	pipe := i.Pipe(from, func(frame []byte) error {
+		_, err := w.Write(append(bytes.Clone(frame), '\n'))
+		return err
+	})
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if frame := bytes.TrimRight(line, "\r\n"); len(frame) > 0 {
+			if sendErr := pipe.Send(ctx, frame); sendErr != nil {
+				return sendErr
+			}
+		}
+		if err != nil {
+			if flushErr := pipe.Flush(); flushErr != nil {
+				return flushErr
+			}
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"time"
 
 	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
@@ -56,6 +57,28 @@ func (b *ConfigBuilder) WithStreamableHTTPTransport(url string) *ConfigBuilder {
 	return b
 }
 
+// WithWebSocketTransport configures WebSocket transport
+func (b *ConfigBuilder) WithWebSocketTransport(url string, subprotocols ...string) *ConfigBuilder {
+	b.config.Connection.Type = transports.TransportWebSocket
+	b.config.Connection.URL = url
+	if len(subprotocols) > 0 {
+		b.config.Transport.WebSocket.Subprotocols = subprotocols
+	}
+	return b
+}
+
+// WithSocketTransport configures a Unix domain socket or TCP transport from a
+// unix:///path/to.sock or tcp://host:port address
+func (b *ConfigBuilder) WithSocketTransport(address string) *ConfigBuilder {
+	if strings.HasPrefix(address, "unix://") {
+		b.config.Connection.Type = transports.TransportUnix
+	} else {
+		b.config.Connection.Type = transports.TransportTCP
+	}
+	b.config.Connection.URL = address
+	return b
+}
+
 // WithConnectionTimeout sets the connection timeout
 func (b *ConfigBuilder) WithConnectionTimeout(timeout time.Duration) *ConfigBuilder {
 	b.config.Connection.ConnectionTimeout = timeout
@@ -206,6 +229,13 @@ func (b *ConfigBuilder) WithSTDIOEnvironment(workDir string, env map[string]stri
 	return b
 }
 
+// WithSTDIOLimits configures resource limits for STDIO server processes
+func (b *ConfigBuilder) WithSTDIOLimits(limits ResourceLimits, killTimeout time.Duration) *ConfigBuilder {
+	b.config.Transport.STDIO.Limits = limits
+	b.config.Transport.STDIO.KillTimeout = killTimeout
+	return b
+}
+
 // WithSSEConfig configures SSE transport settings
 func (b *ConfigBuilder) WithSSEConfig(bufferSize int, readTimeout, writeTimeout time.Duration) *ConfigBuilder {
 	b.config.Transport.SSE.BufferSize = bufferSize
@@ -3,9 +3,12 @@ package config
 import (
 	"fmt"
 	"net/http"
+	"runtime"
+	"strings"
 	"time"
 
 	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
+	"github.com/standardbeagle/mcp-tui/internal/platform/process"
 )
 
 // UnifiedConfig represents the complete configuration for MCP-TUI
@@ -32,12 +35,19 @@ type UnifiedConfig struct {
 // ConnectionConfig holds connection-specific settings
 type ConnectionConfig struct {
 	// Basic connection parameters
-	Type    transports.TransportType `json:"type" yaml:"type" validate:"required,oneof=stdio sse http streamable-http"`
+	Type    transports.TransportType `json:"type" yaml:"type" validate:"required,oneof=stdio sse http streamable-http websocket unix tcp auto replay"`
 	Command string                   `json:"command,omitempty" yaml:"command,omitempty"`
 	Args    []string                 `json:"args,omitempty" yaml:"args,omitempty"`
 	URL     string                   `json:"url,omitempty" yaml:"url,omitempty"`
 	Headers map[string]string        `json:"headers,omitempty" yaml:"headers,omitempty"`
 
+	// Recording and replay
+	RecordPath  string `json:"record_path,omitempty" yaml:"record_path,omitempty"`
+	ReplayMatch string `json:"replay_match,omitempty" yaml:"replay_match,omitempty" validate:"omitempty,oneof=exact fuzzy method"`
+
+	// Fault injection
+	ChaosPath string `json:"chaos_path,omitempty" yaml:"chaos_path,omitempty"`
+
 	// Timeout settings
 	ConnectionTimeout  time.Duration `json:"connection_timeout" yaml:"connection_timeout" validate:"min=1s,max=300s"`
 	RequestTimeout     time.Duration `json:"request_timeout" yaml:"request_timeout" validate:"min=1s,max=300s"`
@@ -58,6 +68,9 @@ type TransportConfig struct {
 
 	// SSE transport settings
 	SSE SSETransportConfig `json:"sse" yaml:"sse"`
+
+	// WebSocket transport settings
+	WebSocket WebSocketTransportConfig `json:"websocket" yaml:"websocket"`
 }
 
 // HTTPTransportConfig contains HTTP-specific settings
@@ -83,6 +96,9 @@ type HTTPTransportConfig struct {
 	// User agent and headers
 	UserAgent      string            `json:"user_agent" yaml:"user_agent"`
 	DefaultHeaders map[string]string `json:"default_headers,omitempty" yaml:"default_headers,omitempty"`
+
+	// Resumability: how often a dropped response stream is resumed with Last-Event-ID
+	StreamResumeAttempts int `json:"stream_resume_attempts" yaml:"stream_resume_attempts" validate:"min=0,max=100"`
 }
 
 // STDIOTransportConfig contains STDIO-specific settings
@@ -97,9 +113,32 @@ type STDIOTransportConfig struct {
 	DenyPatterns      []string `json:"deny_patterns,omitempty" yaml:"deny_patterns,omitempty"`
 
 	// Process limits
-	MaxProcesses   int           `json:"max_processes" yaml:"max_processes" validate:"min=1,max=100"`
-	ProcessTimeout time.Duration `json:"process_timeout" yaml:"process_timeout" validate:"min=1s,max=600s"`
-	KillTimeout    time.Duration `json:"kill_timeout" yaml:"kill_timeout" validate:"min=1s,max=60s"`
+	MaxProcesses   int            `json:"max_processes" yaml:"max_processes" validate:"min=1,max=100"`
+	ProcessTimeout time.Duration  `json:"process_timeout" yaml:"process_timeout" validate:"min=1s,max=600s"`
+	KillTimeout    time.Duration  `json:"kill_timeout" yaml:"kill_timeout" validate:"min=1s,max=60s"`
+	Limits         ResourceLimits `json:"limits" yaml:"limits"`
+}
+
+// ResourceLimits are rlimits applied to each STDIO server process and
+// inherited by its children (Linux only). Zero leaves a limit unchanged.
+type ResourceLimits struct {
+	CPUSeconds   uint64 `json:"cpu_seconds,omitempty" yaml:"cpu_seconds,omitempty"`
+	AddressSpace uint64 `json:"address_space,omitempty" yaml:"address_space,omitempty"` // Bytes
+	OpenFiles    uint64 `json:"open_files,omitempty" yaml:"open_files,omitempty"`
+	Processes    uint64 `json:"processes,omitempty" yaml:"processes,omitempty"` // Per user, not enforced for root
+}
+
+// ApplyTo copies the process settings onto a transport configuration
+func (s *STDIOTransportConfig) ApplyTo(config *transports.TransportConfig) {
+	config.WorkingDirectory = s.WorkingDirectory
+	config.Environment = s.Environment
+	config.KillTimeout = s.KillTimeout
+	config.Limits = process.Limits{
+		CPUSeconds:   s.Limits.CPUSeconds,
+		AddressSpace: s.Limits.AddressSpace,
+		OpenFiles:    s.Limits.OpenFiles,
+		Processes:    s.Limits.Processes,
+	}
 }
 
 // SSETransportConfig contains SSE-specific settings
@@ -118,6 +157,15 @@ type SSETransportConfig struct {
 	IgnoreEvents []string `json:"ignore_events,omitempty" yaml:"ignore_events,omitempty"`
 }
 
+// WebSocketTransportConfig contains WebSocket-specific settings
+type WebSocketTransportConfig struct {
+	// Subprotocols offered in Sec-WebSocket-Protocol, in order of preference
+	Subprotocols []string `json:"subprotocols,omitempty" yaml:"subprotocols,omitempty"`
+
+	// Keepalive ping interval (0 disables pings)
+	PingInterval time.Duration `json:"ping_interval" yaml:"ping_interval" validate:"min=0s,max=300s"`
+}
+
 // SessionConfig holds session management settings
 type SessionConfig struct {
 	// Health monitoring
@@ -221,6 +269,8 @@ func Default() *UnifiedConfig {
 				IdleConnTimeout:     90 * time.Second,
 				TLSMinVersion:       "1.2",
 				UserAgent:           "mcp-tui/0.1.0",
+
+				StreamResumeAttempts: transports.DefaultStreamResumeAttempts,
 			},
 			STDIO: STDIOTransportConfig{
 				CommandValidation: true,
@@ -235,6 +285,10 @@ func Default() *UnifiedConfig {
 				ReadTimeout:          30 * time.Second,
 				WriteTimeout:         10 * time.Second,
 			},
+			WebSocket: WebSocketTransportConfig{
+				Subprotocols: []string{transports.DefaultWebSocketSubprotocol},
+				PingInterval: 30 * time.Second,
+			},
 		},
 		Session: SessionConfig{
 			HealthCheckInterval:  30 * time.Second,
@@ -314,6 +368,29 @@ func (c *UnifiedConfig) validateConnection() error {
 		if conn.URL == "" {
 			return fmt.Errorf("URL is required for %s transport", conn.Type)
 		}
+	case transports.TransportWebSocket:
+		if !strings.HasPrefix(conn.URL, "ws://") && !strings.HasPrefix(conn.URL, "wss://") {
+			return fmt.Errorf("a ws:// or wss:// URL is required for %s transport", conn.Type)
+		}
+	case transports.TransportAuto:
+		if !strings.HasPrefix(conn.URL, "http://") && !strings.HasPrefix(conn.URL, "https://") {
+			return fmt.Errorf("an http:// or https:// URL is required for %s transport", conn.Type)
+		}
+	case transports.TransportReplay:
+		if conn.URL == "" {
+			return fmt.Errorf("cassette path is required for %s transport", conn.Type)
+		}
+		if _, err := transports.ParseReplayMatch(conn.ReplayMatch); err != nil {
+			return err
+		}
+	case transports.TransportUnix, transports.TransportTCP:
+		network, _, err := transports.ParseSocketAddress(conn.URL)
+		if err != nil {
+			return err
+		}
+		if network != string(conn.Type) {
+			return fmt.Errorf("a %s:// URL is required for %s transport", conn.Type, conn.Type)
+		}
 	default:
 		return fmt.Errorf("unsupported transport type: %s", conn.Type)
 	}
@@ -342,6 +419,9 @@ func (c *UnifiedConfig) validateTransport() error {
 	if http.MaxIdleConns < 1 {
 		return fmt.Errorf("HTTP max idle connections must be at least 1")
 	}
+	if http.StreamResumeAttempts < 0 {
+		return fmt.Errorf("HTTP stream resume attempts cannot be negative")
+	}
 
 	// Validate STDIO transport settings
 	stdio := &c.Transport.STDIO
@@ -351,6 +431,9 @@ func (c *UnifiedConfig) validateTransport() error {
 	if stdio.ProcessTimeout <= 0 {
 		return fmt.Errorf("STDIO process timeout must be positive")
 	}
+	if stdio.Limits != (ResourceLimits{}) && runtime.GOOS != "linux" {
+		return fmt.Errorf("STDIO resource limits are only supported on Linux")
+	}
 
 	// Validate SSE transport settings
 	sse := &c.Transport.SSE
@@ -361,6 +444,11 @@ func (c *UnifiedConfig) validateTransport() error {
 		return fmt.Errorf("SSE read timeout must be positive")
 	}
 
+	// Validate WebSocket transport settings
+	if c.Transport.WebSocket.PingInterval < 0 {
+		return fmt.Errorf("WebSocket ping interval cannot be negative")
+	}
+
 	return nil
 }
 
@@ -428,13 +516,23 @@ func (c *UnifiedConfig) ToTransportConfig() *transports.TransportConfig {
 		}
 	}
 
-	return &transports.TransportConfig{
-		Type:       c.Connection.Type,
-		Command:    c.Connection.Command,
-		Args:       c.Connection.Args,
-		URL:        c.Connection.URL,
-		HTTPClient: httpClient,
-		Timeout:    c.Connection.RequestTimeout,
-		DebugMode:  c.Debug.Enabled,
+	config := &transports.TransportConfig{
+		Type:         c.Connection.Type,
+		Command:      c.Connection.Command,
+		Args:         c.Connection.Args,
+		URL:          c.Connection.URL,
+		HTTPClient:   httpClient,
+		Headers:      c.Connection.Headers,
+		Subprotocols: c.Transport.WebSocket.Subprotocols,
+		PingInterval: c.Transport.WebSocket.PingInterval,
+		Timeout:      c.Connection.RequestTimeout,
+		DebugMode:    c.Debug.Enabled,
+
+		StreamResumeAttempts: c.Transport.HTTP.StreamResumeAttempts,
+		ReplayMatch:          c.Connection.ReplayMatch,
+		RecordPath:           c.Connection.RecordPath,
+		ChaosPath:            c.Connection.ChaosPath,
 	}
+	c.Transport.STDIO.ApplyTo(config)
+	return config
 }
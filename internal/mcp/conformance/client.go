@@ -0,0 +1,233 @@
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
+)
+
+// client speaks raw JSON-RPC over a connection, so that probes can send
+// requests a well-behaved client never would and see the server's exact
+// replies. It answers the server's own requests the way a minimal client
+// does.
+type client struct {
+	conn    officialMCP.Connection
+	timeout time.Duration
+
+	mu            sync.Mutex
+	nextID        int64
+	pending       map[string]chan *jsonrpc.Response
+	notifications []*jsonrpc.Request
+	unexpected    []*jsonrpc.Response // Responses to requests never sent
+	readErr       error
+	done          chan struct{}
+}
+
+// newClient starts reading messages from a connection
+func newClient(conn officialMCP.Connection, timeout time.Duration) *client {
+	c := &client{
+		conn:    conn,
+		timeout: timeout,
+		pending: make(map[string]chan *jsonrpc.Response),
+		done:    make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+// call sends a request and waits for its response. A JSON-RPC error
+// response is returned as a *transports.WireError.
+func (c *client) call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req := &jsonrpc.Request{Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s params: %w", method, err)
+		}
+		req.Params = data
+	}
+
+	responses := make(chan *jsonrpc.Response, 1)
+	c.mu.Lock()
+	c.nextID++
+	req.ID, _ = transports.MakeID(c.nextID)
+	key := idKey(req.ID)
+	c.pending[key] = responses
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, key)
+		c.mu.Unlock()
+	}()
+
+	if err := c.conn.Write(ctx, req); err != nil {
+		return nil, fmt.Errorf("failed to send %s: %w", method, err)
+	}
+	select {
+	case resp := <-responses:
+		if resp.Error != nil {
+			return nil, wireError(resp.Error)
+		}
+		return resp.Result, nil
+	case <-c.done:
+		return nil, fmt.Errorf("connection closed while waiting for %s: %w", method, c.err())
+	case <-ctx.Done():
+		return nil, fmt.Errorf("no response to %s: %w", method, ctx.Err())
+	}
+}
+
+// notify sends a notification
+func (c *client) notify(ctx context.Context, method string, params any) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req := &jsonrpc.Request{Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s params: %w", method, err)
+		}
+		req.Params = data
+	}
+	if err := c.conn.Write(ctx, req); err != nil {
+		return fmt.Errorf("failed to send %s: %w", method, err)
+	}
+	return nil
+}
+
+// close closes the connection
+func (c *client) close() {
+	c.conn.Close()
+}
+
+// err returns why the connection stopped
+func (c *client) err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.readErr == nil {
+		return officialMCP.ErrConnectionClosed
+	}
+	return c.readErr
+}
+
+// closed reports whether the connection has ended
+func (c *client) closed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// exitDetails describes how a stdio server ended, from its stderr
+func (c *client) exitDetails() []string {
+	if conn, ok := c.conn.(interface{ Stderr() string }); ok {
+		if stderr := conn.Stderr(); stderr != "" {
+			return []string{"server stderr: " + stderr}
+		}
+	}
+	return nil
+}
+
+// received returns the notifications and unexpected responses so far
+func (c *client) received() ([]*jsonrpc.Request, []*jsonrpc.Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*jsonrpc.Request(nil), c.notifications...), append([]*jsonrpc.Response(nil), c.unexpected...)
+}
+
+// readLoop dispatches responses to their callers until the connection ends
+func (c *client) readLoop() {
+	defer close(c.done)
+	for {
+		msg, err := c.conn.Read(context.Background())
+		if err != nil {
+			c.mu.Lock()
+			c.readErr = err
+			c.mu.Unlock()
+			return
+		}
+
+		switch m := msg.(type) {
+		case *jsonrpc.Request:
+			if m.ID.IsValid() {
+				go c.answer(m)
+				continue
+			}
+			c.mu.Lock()
+			c.notifications = append(c.notifications, m)
+			c.mu.Unlock()
+		case *jsonrpc.Response:
+			c.mu.Lock()
+			responses, ok := c.pending[idKey(m.ID)]
+			if ok {
+				delete(c.pending, idKey(m.ID))
+			} else {
+				c.unexpected = append(c.unexpected, m)
+			}
+			c.mu.Unlock()
+			if ok {
+				responses <- m
+			}
+		}
+	}
+}
+
+// answer replies to a request from the server: ping, an empty roots list,
+// and method not found for anything else
+func (c *client) answer(req *jsonrpc.Request) {
+	resp := &jsonrpc.Response{ID: req.ID}
+	switch req.Method {
+	case "ping":
+		resp.Result = json.RawMessage(`{}`)
+	case "roots/list":
+		resp.Result = json.RawMessage(`{"roots":[]}`)
+	default:
+		resp.Error = &transports.WireError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	c.conn.Write(ctx, resp)
+}
+
+// idKey identifies a request ID in the pending map
+func idKey(id jsonrpc.ID) string {
+	return fmt.Sprintf("%T:%v", id.Raw(), id.Raw())
+}
+
+// wireError converts a response error to its wire form. Transports that
+// decode messages themselves produce *transports.WireError already; the
+// SDK's own error type marshals to the same JSON.
+func wireError(err error) *transports.WireError {
+	var wireErr *transports.WireError
+	if errors.As(err, &wireErr) {
+		return wireErr
+	}
+	if data, marshalErr := json.Marshal(err); marshalErr == nil {
+		var decoded transports.WireError
+		if json.Unmarshal(data, &decoded) == nil && (decoded.Code != 0 || decoded.Message != "") {
+			return &decoded
+		}
+	}
+	return &transports.WireError{Message: err.Error()}
+}
+
+// protocolError returns the JSON-RPC error a call failed with, if it failed
+// with one rather than, say, a timeout
+func protocolError(err error) (*transports.WireError, bool) {
+	var wireErr *transports.WireError
+	ok := errors.As(err, &wireErr)
+	return wireErr, ok
+}
@@ -0,0 +1,230 @@
+// Package conformance probes an MCP server for conformance to the protocol
+// specification: the initialization handshake, JSON-RPC error codes,
+// capabilities, pagination, tool schemas and errors, notifications and the
+// stdio framing. Each probe reports pass, warn or fail with a reference to
+// the part of the specification it checks.
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/errors"
+)
+
+// ProtocolVersion is the protocol version the checker asks for
+const ProtocolVersion = "2025-06-18"
+
+// ProtocolVersions are the published protocol versions, newest first
+var ProtocolVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// DefaultTimeout bounds each request the checker sends
+const DefaultTimeout = 10 * time.Second
+
+// Status is the outcome of a probe
+type Status string
+
+const (
+	Pass Status = "pass"
+	Warn Status = "warn"
+	Fail Status = "fail"
+	Skip Status = "skip"
+)
+
+// Result is the outcome of one probe
+type Result struct {
+	ID      string   `json:"id"`
+	Title   string   `json:"title"`
+	Status  Status   `json:"status"`
+	Message string   `json:"message"`
+	Details []string `json:"details,omitempty"`
+	Spec    string   `json:"spec"`
+}
+
+// ServerInfo describes the server under test, as it introduced itself
+type ServerInfo struct {
+	Name            string   `json:"name"`
+	Version         string   `json:"version"`
+	ProtocolVersion string   `json:"protocolVersion"`
+	Capabilities    []string `json:"capabilities"`
+	Transport       string   `json:"transport"`
+}
+
+// Summary counts the results by status
+type Summary struct {
+	Passed   int `json:"passed"`
+	Warnings int `json:"warnings"`
+	Failed   int `json:"failed"`
+	Skipped  int `json:"skipped"`
+}
+
+// Report is the outcome of a conformance check
+type Report struct {
+	Server     ServerInfo `json:"server"`
+	Results    []Result   `json:"results"`
+	Summary    Summary    `json:"summary"`
+	DurationMs int64      `json:"durationMs"`
+}
+
+// Failed reports whether any probe failed
+func (r *Report) Failed() bool {
+	return r.Summary.Failed > 0
+}
+
+// Dialer opens a new connection to the server under test. The checker opens
+// more than one, since the initialization handshake can only run once per
+// connection.
+type Dialer func(ctx context.Context) (officialMCP.Connection, error)
+
+// Checker runs the conformance probes against a server
+type Checker struct {
+	Dial      Dialer
+	Transport string        // Named in the report
+	Timeout   time.Duration // Per request; DefaultTimeout when zero
+}
+
+// Run runs every probe in order and reports the outcomes. Probes that need
+// the initialized session are skipped when the handshake fails or the server
+// goes away.
+func (c *Checker) Run(ctx context.Context) *Report {
+	start := time.Now()
+	r := &run{checker: c, timeout: c.Timeout}
+	if r.timeout <= 0 {
+		r.timeout = DefaultTimeout
+	}
+	defer r.close()
+
+	report := &Report{Server: ServerInfo{Transport: c.Transport}}
+	for _, p := range probes {
+		var out outcome
+		switch {
+		case p.standalone:
+			out = p.run(ctx, r)
+		case r.client == nil:
+			out = skip("the server did not complete initialization")
+		case r.client.closed():
+			out = skip("the server closed the connection during an earlier probe")
+		default:
+			out = p.run(ctx, r)
+			if r.client.closed() {
+				out.details = append(out.details, r.client.exitDetails()...)
+			}
+		}
+		report.Results = append(report.Results, Result{
+			ID:      p.id,
+			Title:   p.title,
+			Status:  out.status,
+			Message: out.message,
+			Details: out.details,
+			Spec:    p.spec,
+		})
+		switch out.status {
+		case Pass:
+			report.Summary.Passed++
+		case Warn:
+			report.Summary.Warnings++
+		case Fail:
+			report.Summary.Failed++
+		case Skip:
+			report.Summary.Skipped++
+		}
+	}
+
+	report.Server.Name = r.serverName
+	report.Server.Version = r.serverVersion
+	report.Server.ProtocolVersion = r.protocolVersion
+	report.Server.Capabilities = sortedKeys(r.capabilities)
+	report.DurationMs = time.Since(start).Milliseconds()
+	return report
+}
+
+// run is the state shared by the probes of one check
+type run struct {
+	checker *Checker
+	timeout time.Duration
+	clients []*client // Every connection opened, closed when the check ends
+
+	client          *client // The initialized session most probes use
+	serverName      string
+	serverVersion   string
+	protocolVersion string
+	capabilities    map[string]any
+
+	tools       []map[string]any // Every page of tools/list, once paginated
+	toolsListed bool
+}
+
+// open dials a new connection
+func (r *run) open(ctx context.Context) (*client, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	conn, err := r.checker.Dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cl := newClient(conn, r.timeout)
+	r.clients = append(r.clients, cl)
+	return cl, nil
+}
+
+// close closes every connection
+func (r *run) close() {
+	for _, cl := range r.clients {
+		cl.close()
+	}
+}
+
+// has reports whether the server advertised a capability
+func (r *run) has(capability string) bool {
+	_, ok := r.capabilities[capability]
+	return ok
+}
+
+// outcome is what a probe found
+type outcome struct {
+	status  Status
+	message string
+	details []string
+}
+
+func pass(format string, args ...any) outcome {
+	return outcome{status: Pass, message: fmt.Sprintf(format, args...)}
+}
+
+func warn(details []string, format string, args ...any) outcome {
+	return outcome{status: Warn, message: fmt.Sprintf(format, args...), details: details}
+}
+
+func fail(details []string, format string, args ...any) outcome {
+	return outcome{status: Fail, message: fmt.Sprintf(format, args...), details: details}
+}
+
+func skip(format string, args ...any) outcome {
+	return outcome{status: Skip, message: fmt.Sprintf(format, args...)}
+}
+
+// connectionFailure explains why a connection could not be set up, with the
+// error classifier's diagnosis and suggested fixes. A stdio server's stderr
+// is classified along with the error, since it usually holds the cause.
+func connectionFailure(what string, err error, cl *client) outcome {
+	cause := err
+	var stderr []string
+	if cl != nil {
+		if stderr = cl.exitDetails(); len(stderr) > 0 {
+			cause = fmt.Errorf("%w: %s", err, stderr[0])
+		}
+	}
+
+	classifier := errors.NewErrorClassifier()
+	classified := classifier.Classify(cause, nil)
+	var details []string
+	if classified.Category != errors.CategoryUnknown {
+		details = append(details, classified.Message)
+	}
+	for _, action := range classifier.GetRecoveryActions(classified) {
+		details = append(details, "try: "+action)
+	}
+	return fail(append(details, stderr...), "%s: %v", what, err)
+}
@@ -0,0 +1,301 @@
+package conformance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/mock"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
+)
+
+const testServer = `
+server:
+  name: conformance-mock
+  version: 1.0.0
+tools:
+  - name: greet
+    inputSchema:
+      type: object
+      properties:
+        name: {type: string}
+      required: [name]
+    response:
+      text: "Hello, {{.args.name}}!"
+resources:
+  - uri: mock://readme
+    text: "read me"
+prompts:
+  - name: ask
+    messages:
+      - role: user
+        text: "Tell me something"
+`
+
+func newMockServer(t *testing.T) *mock.Server {
+	def, err := mock.ParseDefinition([]byte(testServer))
+	require.NoError(t, err)
+	return mock.NewServer(def)
+}
+
+func statuses(report *Report) map[string]Status {
+	out := make(map[string]Status)
+	for _, result := range report.Results {
+		out[result.ID] = result.Status
+	}
+	return out
+}
+
+func TestCheckMockServer(t *testing.T) {
+	server := newMockServer(t)
+	checker := &Checker{
+		Dial: StreamDialer(func(r io.Reader, w io.Writer) {
+			server.ServeStream(context.Background(), r, w)
+		}),
+		Transport: "stdio",
+		Timeout:   5 * time.Second,
+	}
+
+	report := checker.Run(context.Background())
+	for _, result := range report.Results {
+		assert.Equal(t, Pass, result.Status, "%s: %s %v", result.ID, result.Message, result.Details)
+		assert.NotEmpty(t, result.Spec, result.ID)
+	}
+	assert.Len(t, report.Results, len(probes))
+	assert.False(t, report.Failed())
+	assert.Equal(t, ServerInfo{
+		Name:            "conformance-mock",
+		Version:         "1.0.0",
+		ProtocolVersion: ProtocolVersion,
+		Capabilities:    []string{"logging", "prompts", "resources", "tools"},
+		Transport:       "stdio",
+	}, report.Server)
+}
+
+func TestCheckMockServerOverHTTP(t *testing.T) {
+	httpServer := httptest.NewServer(newMockServer(t).HTTPHandler())
+	t.Cleanup(httpServer.Close)
+
+	ctx := context.Background()
+	dial, transport, err := NewDialer(ctx, &config.ConnectionConfig{Type: config.TransportHTTP, URL: httpServer.URL}, 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "http", transport)
+
+	report := (&Checker{Dial: dial, Transport: transport, Timeout: 5 * time.Second}).Run(ctx)
+	for _, result := range report.Results {
+		want := Pass
+		if result.ID == "stdout" {
+			want = Skip
+		}
+		assert.Equal(t, want, result.Status, "%s: %s %v", result.ID, result.Message, result.Details)
+	}
+}
+
+// badServer gets most things wrong: it logs to stdout, agrees to any
+// protocol version, answers notifications, loops through the pages of its
+// tools and runs tools that do not exist
+func badServer(r io.Reader, w io.Writer) {
+	fmt.Fprintln(w, "Server listening on stdio")
+	send := func(msg map[string]any) {
+		msg["jsonrpc"] = "2.0"
+		data, _ := json.Marshal(msg)
+		fmt.Fprintf(w, "%s\n", data)
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var req struct {
+			ID     any            `json:"id"`
+			Method string         `json:"method"`
+			Params map[string]any `json:"params"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &req) != nil {
+			continue
+		}
+		if req.ID == nil {
+			send(map[string]any{"id": "not-a-request", "result": map[string]any{}})
+			continue
+		}
+
+		switch req.Method {
+		case "initialize":
+			send(map[string]any{"id": req.ID, "result": map[string]any{
+				"protocolVersion": req.Params["protocolVersion"],
+				"capabilities":    map[string]any{"tools": map[string]any{}, "prompts": map[string]any{}},
+				"serverInfo":      map[string]any{"name": "bad-server"},
+			}})
+		case "ping":
+			send(map[string]any{"id": req.ID, "result": map[string]any{"status": "ok"}})
+		case "tools/list":
+			send(map[string]any{"id": req.ID, "result": map[string]any{
+				"tools": []any{
+					map[string]any{"name": "run", "inputSchema": map[string]any{"type": "string"}},
+					map[string]any{"name": "run"},
+				},
+				"nextCursor": "again",
+			}})
+		case "tools/call":
+			send(map[string]any{"id": req.ID, "result": map[string]any{"content": []any{map[string]any{"type": "text", "text": "done"}}}})
+		default:
+			send(map[string]any{"id": req.ID, "error": map[string]any{"code": -32603, "message": "internal error"}})
+		}
+	}
+}
+
+func TestCheckMisbehavingServer(t *testing.T) {
+	checker := &Checker{Dial: StreamDialer(badServer), Transport: "stdio", Timeout: 5 * time.Second}
+	report := checker.Run(context.Background())
+
+	assert.Equal(t, map[string]Status{
+		"initialize":          Fail,
+		"version-negotiation": Fail,
+		"ping":                Warn,
+		"unknown-method":      Warn,
+		"invalid-params":      Fail,
+		"capabilities":        Fail,
+		"pagination":          Fail,
+		"tool-schemas":        Fail,
+		"tool-errors":         Fail,
+		"notifications":       Fail,
+		"stdout":              Fail,
+	}, statuses(report))
+	assert.True(t, report.Failed())
+
+	byID := make(map[string]Result)
+	for _, result := range report.Results {
+		byID[result.ID] = result
+	}
+	assert.Equal(t, []string{"serverInfo.version is missing"}, byID["initialize"].Details)
+	assert.Equal(t, `the server agreed to protocol version "1999-01-01"; it must answer with a version it supports`, byID["version-negotiation"].Message)
+	assert.Equal(t, "an unknown method returned error -32603 (internal error); want -32601 (method not found)", byID["unknown-method"].Message)
+	assert.Contains(t, byID["capabilities"].Message, "prompts is advertised, but prompts/list failed")
+	assert.Contains(t, byID["pagination"].Details, `tools/list returned cursor "again" twice, so its pages loop`)
+	assert.Subset(t, byID["tool-schemas"].Details, []string{
+		`run: inputSchema has type "string"; it must be "object"`,
+		"run: the name is not unique",
+		"run: inputSchema is missing",
+	})
+	assert.Equal(t, "calling an unknown tool succeeded", byID["tool-errors"].Message)
+	assert.Contains(t, byID["notifications"].Message, "response with id not-a-request")
+	assert.Equal(t, "the server wrote 2 lines to stdout that did not hold a JSON-RPC message", byID["stdout"].Message)
+}
+
+func TestCheckConnectionFailure(t *testing.T) {
+	checker := &Checker{Dial: func(ctx context.Context) (officialMCP.Connection, error) {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused")}
+	}}
+	report := checker.Run(context.Background())
+
+	require.Len(t, report.Results, len(probes))
+	initialize := report.Results[0]
+	assert.Equal(t, Fail, initialize.Status)
+	assert.Contains(t, initialize.Message, "failed to connect")
+	assert.Contains(t, initialize.Details, "try: Verify the server is running and accessible")
+	assert.Equal(t, Summary{Failed: 1, Skipped: len(probes) - 1}, report.Summary)
+}
+
+func TestSession(t *testing.T) {
+	ctx := context.Background()
+	session, err := Open(ctx, StreamDialer(badServer), 5*time.Second)
+	require.NoError(t, err)
+	defer session.Close()
+	assert.Equal(t, "bad-server", session.ServerName)
+
+	data, err := session.Call(ctx, "tools/call", map[string]any{"name": "run"}, 0)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"content": [{"type": "text", "text": "done"}]}`, string(data))
+
+	_, err = session.Call(ctx, "resources/list", nil, 0)
+	var wireErr *transports.WireError
+	require.ErrorAs(t, err, &wireErr)
+	assert.Equal(t, int64(-32603), wireErr.Code)
+	assert.False(t, session.Closed())
+
+	session.Close()
+	_, err = session.Call(ctx, "ping", nil, time.Second)
+	assert.Error(t, err)
+	assert.Eventually(t, session.Closed, time.Second, 10*time.Millisecond)
+}
+
+func TestSessionServerNotReading(t *testing.T) {
+	// The server stops reading once initialized, so requests are never taken
+	// off the pipe until the test ends
+	release := make(chan struct{})
+	stuck := func(r io.Reader, w io.Writer) {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			var req struct {
+				ID     any    `json:"id"`
+				Method string `json:"method"`
+			}
+			json.Unmarshal(scanner.Bytes(), &req)
+			if req.Method == "initialize" {
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%v,"result":{"protocolVersion":%q,"capabilities":{},"serverInfo":{"name":"stuck"}}}`+"\n", req.ID, ProtocolVersion)
+			}
+			if req.Method == "notifications/initialized" {
+				<-release
+			}
+		}
+	}
+	ctx := context.Background()
+	session, err := Open(ctx, StreamDialer(stuck), 5*time.Second)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		close(release)
+		session.Close()
+	})
+
+	_, err = session.Call(ctx, "ping", nil, 100*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The blocked write was ended by closing stdin, so the next call fails
+	// at once instead of waiting behind it
+	start := time.Now()
+	_, err = session.Call(ctx, "ping", nil, 0)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestWriteText(t *testing.T) {
+	report := &Report{
+		Server: ServerInfo{Name: "demo", Version: "1.0", ProtocolVersion: "2025-06-18", Transport: "stdio"},
+		Results: []Result{
+			{ID: "ping", Title: "Ping", Status: Pass, Message: "ping returned an empty result", Spec: "https://spec/ping"},
+			{ID: "pagination", Title: "Pagination", Status: Warn, Message: "tools/list accepted an invalid cursor",
+				Details: []string{"tools/list accepted an invalid cursor", "prompts/list lists name \"a\" more than once"}, Spec: "https://spec/pagination"},
+			{ID: "stdout", Title: "Stdout framing", Status: Fail, Message: "the server wrote 1 line to stdout", Details: []string{`"hello" (not JSON)`}, Spec: "https://spec/stdio"},
+			{ID: "tool-errors", Title: "Tool errors", Status: Skip, Message: "the server does not advertise tools", Spec: "https://spec/tools"},
+		},
+		Summary:    Summary{Passed: 1, Warnings: 1, Failed: 1, Skipped: 1},
+		DurationMs: 1250,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteText(&buf, report))
+	assert.Equal(t, `demo 1.0, protocol 2025-06-18 over stdio
+
+  ✓ Ping: ping returned an empty result
+  ! Pagination: tools/list accepted an invalid cursor
+      prompts/list lists name "a" more than once
+      spec: https://spec/pagination
+  ✗ Stdout framing: the server wrote 1 line to stdout
+      "hello" (not JSON)
+      spec: https://spec/stdio
+  - Tool errors (skipped: the server does not advertise tools)
+
+1 passed, 1 warning, 1 failed, 1 skipped in 1.25s
+`, buf.String())
+}
@@ -0,0 +1,340 @@
+package conformance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
+	"github.com/standardbeagle/mcp-tui/internal/platform/process"
+)
+
+// maxNoiseLine is how much of a stray stdout line is kept for the report
+const maxNoiseLine = 120
+
+// NewDialer returns a dialer for a connection and the name of its transport.
+// Stdio servers are started directly rather than through the stdio
+// transport, so that output which is not a JSON-RPC message is recorded for
+// the report instead of breaking the connection. Other transports come from
+// the transport factory, after negotiation for URLs of unknown kind.
+func NewDialer(ctx context.Context, connConfig *config.ConnectionConfig, timeout time.Duration) (Dialer, string, error) {
+	if connConfig.Type == config.TransportStdio {
+		if err := config.ValidateCommand(connConfig.Command, connConfig.Args); err != nil {
+			return nil, "", fmt.Errorf("command validation failed: %w", err)
+		}
+		server := &stdioServer{command: connConfig.Command, args: connConfig.Args}
+		return server.dial, string(config.TransportStdio), nil
+	}
+
+	transportConfig := transports.FromConnectionConfig(connConfig, false, timeout)
+	if transportConfig.Type == transports.TransportAuto {
+		negotiated, err := transports.NegotiateTransport(ctx, transportConfig, nil)
+		if err != nil {
+			return nil, "", fmt.Errorf("transport negotiation failed: %w", err)
+		}
+		transportConfig.Type = negotiated
+	}
+	factory := transports.NewFactory()
+	if err := factory.ValidateConfig(transportConfig); err != nil {
+		return nil, "", err
+	}
+	dial := func(ctx context.Context) (officialMCP.Connection, error) {
+		transport, _, err := factory.CreateTransport(transportConfig)
+		if err != nil {
+			return nil, err
+		}
+		return transport.Connect(ctx)
+	}
+	return dial, string(transportConfig.Type), nil
+}
+
+// StreamDialer returns a dialer that runs serve in process for each
+// connection, over pipes framed as on stdio, for servers such as
+// mock.Server.ServeStream that need no process of their own. The connection
+// ends when serve returns, and writes to it fail, as they would when a stdio
+// server exits.
+func StreamDialer(serve func(r io.Reader, w io.Writer)) Dialer {
+	return func(ctx context.Context) (officialMCP.Connection, error) {
+		clientReader, serverWriter := io.Pipe()
+		serverReader, clientWriter := io.Pipe()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			serve(serverReader, serverWriter)
+			serverWriter.Close()
+			serverReader.Close()
+		}()
+		stop := func() {
+			clientReader.Close()
+			<-done
+		}
+		return newLineConn(clientWriter, clientReader, stop, nil), nil
+	}
+}
+
+// noiseRecorder is implemented by connections that record stdout lines which
+// are not JSON-RPC messages
+type noiseRecorder interface {
+	Noise() []string
+}
+
+// stdioServer starts a process per connection
+type stdioServer struct {
+	command string
+	args    []string
+
+	once    sync.Once
+	manager process.Manager
+}
+
+// dial starts the server and connects to its standard streams
+func (s *stdioServer) dial(ctx context.Context) (officialMCP.Connection, error) {
+	s.once.Do(func() {
+		s.manager = process.NewPlatformManager(context.Background())
+	})
+
+	stdinReader, stdinWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		stdinReader.Close()
+		stdinWriter.Close()
+		return nil, err
+	}
+
+	stderr := &tailBuffer{max: 1024}
+	proc, err := s.manager.StartWithOptions(context.Background(), s.command, s.args, process.Options{
+		Stdin:  stdinReader,
+		Stdout: stdoutWriter,
+		Stderr: stderr,
+	})
+	// The server holds its own copies of its ends of the pipes
+	stdinReader.Close()
+	stdoutWriter.Close()
+	if err != nil {
+		stdinWriter.Close()
+		stdoutReader.Close()
+		return nil, fmt.Errorf("failed to start server command: %w", err)
+	}
+
+	stop := func() {
+		exited := make(chan struct{})
+		go func() {
+			proc.Wait()
+			close(exited)
+		}()
+		select {
+		case <-exited:
+		case <-time.After(process.DefaultKillTimeout):
+		}
+		proc.Kill()
+		stdoutReader.Close()
+	}
+	return newLineConn(stdinWriter, stdoutReader, stop, stderr), nil
+}
+
+// lineConn is a connection to a stdio server that reads its stdout line by
+// line. Lines that are not JSON-RPC 2.0 messages are recorded as noise and
+// skipped, where the stdio transport would give up on the stream.
+type lineConn struct {
+	stdin  io.WriteCloser
+	stdout io.Reader
+	stop   func()      // Shuts the server down once its stdin is closed
+	stderr *tailBuffer // The end of the server's stderr, if known
+
+	writeMu  sync.Mutex
+	incoming chan jsonrpc.Message
+	done     chan struct{}
+
+	mu        sync.Mutex
+	noise     []string
+	readErr   error
+	closeOnce sync.Once
+}
+
+// newLineConn starts reading messages from a server's stdout
+func newLineConn(stdin io.WriteCloser, stdout io.Reader, stop func(), stderr *tailBuffer) *lineConn {
+	c := &lineConn{
+		stdin:    stdin,
+		stdout:   stdout,
+		stop:     stop,
+		stderr:   stderr,
+		incoming: make(chan jsonrpc.Message, 64),
+		done:     make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+// SessionID returns the session ID, which stdio does not have
+func (c *lineConn) SessionID() string {
+	return ""
+}
+
+// Read returns the next message from the server
+func (c *lineConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case msg, ok := <-c.incoming:
+		if ok {
+			return msg, nil
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return nil, c.readErr
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Write sends a message as one line on the server's stdin. A server that
+// stops reading fills the pipe and blocks the write. If ctx ends before the
+// write finishes, stdin is closed to end it: the server can no longer be sent
+// a whole line, so the connection takes no more writes.
+func (c *lineConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	data, err := transports.EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// mu orders the end of the write against ctx ending, so a write that
+	// finished is not reported as timed out
+	var mu sync.Mutex
+	written, closed := false, false
+	stop := context.AfterFunc(ctx, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if !written {
+			closed = true
+			c.stdin.Close()
+		}
+	})
+	defer stop()
+	_, err = c.stdin.Write(append(data, '\n'))
+
+	mu.Lock()
+	defer mu.Unlock()
+	written = true
+	if err != nil && closed {
+		return ctx.Err()
+	}
+	return err
+}
+
+// Close closes the server's stdin and shuts it down
+func (c *lineConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.stdin.Close()
+		c.stop()
+	})
+	return nil
+}
+
+// Noise returns the stdout lines that were not JSON-RPC messages
+func (c *lineConn) Noise() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.noise...)
+}
+
+// readLoop decodes stdout a line at a time until it ends
+func (c *lineConn) readLoop() {
+	defer close(c.incoming)
+
+	reader := bufio.NewReader(c.stdout)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			msgs, decodeErr := transports.DecodeMessages(line)
+			if decodeErr != nil {
+				c.recordNoise(line, decodeErr)
+			}
+			for _, msg := range msgs {
+				select {
+				case c.incoming <- msg:
+				case <-c.done:
+					return
+				}
+			}
+		}
+		if err != nil {
+			c.mu.Lock()
+			c.readErr = c.exitError(err)
+			c.mu.Unlock()
+			return
+		}
+	}
+}
+
+// recordNoise keeps the start of a line that is not a JSON-RPC message
+func (c *lineConn) recordNoise(line []byte, err error) {
+	text := string(line)
+	if len(text) > maxNoiseLine {
+		text = text[:maxNoiseLine] + "..."
+	}
+	c.mu.Lock()
+	c.noise = append(c.noise, fmt.Sprintf("%q (%v)", text, err))
+	c.mu.Unlock()
+}
+
+// exitError explains the end of stdout
+func (c *lineConn) exitError(err error) error {
+	select {
+	case <-c.done:
+		return officialMCP.ErrConnectionClosed
+	default:
+	}
+	if errors.Is(err, io.EOF) {
+		return errors.New("server closed its stdout")
+	}
+	return err
+}
+
+// Stderr returns the end of what the server wrote to stderr
+func (c *lineConn) Stderr() string {
+	if c.stderr == nil {
+		return ""
+	}
+	return c.stderr.String()
+}
+
+// tailBuffer keeps the last bytes written to it
+type tailBuffer struct {
+	mu  sync.Mutex
+	max int
+	buf []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.max {
+		b.buf = b.buf[len(b.buf)-b.max:]
+	}
+	return len(p), nil
+}
+
+// String returns the buffered output on one line
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Join(strings.Fields(string(b.buf)), " ")
+}
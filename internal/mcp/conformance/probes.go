@@ -0,0 +1,628 @@
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+)
+
+// specBase is the specification revision the probes cite
+const specBase = "https://modelcontextprotocol.io/specification/2025-06-18"
+
+// jsonrpcErrors cites the JSON-RPC error codes
+const jsonrpcErrors = "https://www.jsonrpc.org/specification#error_object"
+
+// JSON-RPC error codes the probes expect
+const (
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+var codeNames = map[int64]string{
+	-32700: "parse error",
+	-32600: "invalid request",
+	-32601: "method not found",
+	-32602: "invalid params",
+	-32603: "internal error",
+}
+
+// Values no real server knows, sent to see how it refuses them
+const (
+	bogusVersion  = "1999-01-01"
+	unknownMethod = "mcp-tui/check/unknown-method"
+	unknownTool   = "mcp-tui-check-unknown-tool"
+	invalidCursor = "mcp-tui-check-invalid-cursor"
+)
+
+// maxPages bounds how far pagination is followed
+const maxPages = 50
+
+// maxDetails bounds how many offending items a result lists
+const maxDetails = 5
+
+// probe is one conformance check. Standalone probes run whatever state the
+// session is in; the others need it initialized and connected.
+type probe struct {
+	id         string
+	title      string
+	spec       string
+	run        func(ctx context.Context, r *run) outcome
+	standalone bool
+}
+
+// probes run in order: initialize opens the session the others use, and
+// pagination lists the tools the tool probes inspect
+var probes = []probe{
+	{"initialize", "Initialization", specBase + "/basic/lifecycle#initialization", checkInitialize, true},
+	{"version-negotiation", "Version negotiation", specBase + "/basic/lifecycle#version-negotiation", checkVersionNegotiation, false},
+	{"ping", "Ping", specBase + "/basic/utilities/ping", checkPing, false},
+	{"unknown-method", "Unknown methods", jsonrpcErrors, checkUnknownMethod, false},
+	{"invalid-params", "Invalid params", jsonrpcErrors, checkInvalidParams, false},
+	{"capabilities", "Capability honesty", specBase + "/basic/lifecycle#capability-negotiation", checkCapabilities, false},
+	{"pagination", "Pagination", specBase + "/server/utilities/pagination", checkPagination, false},
+	{"tool-schemas", "Tool schemas", specBase + "/server/tools#tool", checkToolSchemas, false},
+	{"tool-errors", "Tool errors", specBase + "/server/tools#error-handling", checkToolErrors, false},
+	{"notifications", "Notifications", specBase + "/basic#notifications", checkNotifications, false},
+	{"stdout", "Stdout framing", specBase + "/basic/transports#stdio", checkStdout, true},
+}
+
+// initialize performs the handshake on a connection
+func initialize(ctx context.Context, cl *client, version string) (map[string]any, error) {
+	data, err := cl.call(ctx, "initialize", map[string]any{
+		"protocolVersion": version,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]any{"name": "mcp-tui-check", "version": "0.1.0"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]any
+	if err := json.Unmarshal(data, &result); err != nil || result == nil {
+		return nil, fmt.Errorf("initialize result is not an object: %s", compact(data))
+	}
+	return result, nil
+}
+
+func checkInitialize(ctx context.Context, r *run) outcome {
+	cl, err := r.open(ctx)
+	if err != nil {
+		return connectionFailure("failed to connect", err, nil)
+	}
+	result, err := initialize(ctx, cl, ProtocolVersion)
+	if err != nil {
+		return connectionFailure("initialize failed", err, cl)
+	}
+	r.client = cl
+
+	version, _ := result["protocolVersion"].(string)
+	info, _ := result["serverInfo"].(map[string]any)
+	capabilities, capabilitiesOK := result["capabilities"].(map[string]any)
+	r.protocolVersion = version
+	r.serverName, _ = info["name"].(string)
+	r.serverVersion, _ = info["version"].(string)
+	r.capabilities = capabilities
+
+	var problems []string
+	if version == "" {
+		problems = append(problems, "protocolVersion is missing")
+	}
+	if !capabilitiesOK {
+		problems = append(problems, "capabilities is missing or not an object")
+	}
+	if r.serverName == "" {
+		problems = append(problems, "serverInfo.name is missing")
+	}
+	if r.serverVersion == "" {
+		problems = append(problems, "serverInfo.version is missing")
+	}
+	if err := cl.notify(ctx, "notifications/initialized", nil); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if len(problems) > 0 {
+		return fail(problems, "the initialize result is incomplete")
+	}
+	if !slices.Contains(ProtocolVersions, version) {
+		return warn(nil, "the server chose protocol version %q, which is not a published version", version)
+	}
+	return pass("%s %s speaks protocol %s", r.serverName, r.serverVersion, version)
+}
+
+func checkVersionNegotiation(ctx context.Context, r *run) outcome {
+	cl, err := r.open(ctx)
+	if err != nil {
+		return fail(nil, "failed to open a second connection: %v", err)
+	}
+	defer cl.close()
+
+	result, err := initialize(ctx, cl, bogusVersion)
+	if err != nil {
+		if wireErr, ok := protocolError(err); ok {
+			return warn(nil, "protocol version %q was refused with error %d (%s) rather than answered with a version the server supports",
+				bogusVersion, wireErr.Code, wireErr.Message)
+		}
+		return fail(nil, "initialize with protocol version %q failed: %v", bogusVersion, err)
+	}
+	version, _ := result["protocolVersion"].(string)
+	switch {
+	case version == bogusVersion:
+		return fail(nil, "the server agreed to protocol version %q; it must answer with a version it supports", bogusVersion)
+	case version == "":
+		return fail(nil, "the initialize result has no protocolVersion")
+	case !slices.Contains(ProtocolVersions, version):
+		return warn(nil, "asked for %q, the server offered %q, which is not a published version", bogusVersion, version)
+	}
+	return pass("asked for %q, the server offered %s", bogusVersion, version)
+}
+
+func checkPing(ctx context.Context, r *run) outcome {
+	data, err := r.client.call(ctx, "ping", nil)
+	if err != nil {
+		return fail(nil, "ping failed: %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal(data, &result); err != nil || result == nil {
+		return fail(nil, "ping returned %s; the result must be an empty object", compact(data))
+	}
+	if len(result) > 0 {
+		return warn(nil, "ping returned %s; the result should be empty", compact(data))
+	}
+	return pass("ping returned an empty result")
+}
+
+func checkUnknownMethod(ctx context.Context, r *run) outcome {
+	_, err := r.client.call(ctx, unknownMethod, nil)
+	return expectError(err, codeMethodNotFound, "an unknown method")
+}
+
+func checkInvalidParams(ctx context.Context, r *run) outcome {
+	// Each of these requires a parameter that is left out
+	for _, c := range []struct{ capability, method, missing string }{
+		{"tools", "tools/call", "name"},
+		{"prompts", "prompts/get", "name"},
+		{"resources", "resources/read", "uri"},
+	} {
+		if r.has(c.capability) {
+			_, err := r.client.call(ctx, c.method, map[string]any{})
+			return expectError(err, codeInvalidParams, fmt.Sprintf("%s without %s", c.method, c.missing))
+		}
+	}
+	return skip("the server offers no method with required parameters to test")
+}
+
+// expectError checks that a call failed with a JSON-RPC error code
+func expectError(err error, code int64, what string) outcome {
+	if err == nil {
+		return fail(nil, "%s succeeded; want error %d (%s)", what, code, codeNames[code])
+	}
+	wireErr, ok := protocolError(err)
+	if !ok {
+		return fail(nil, "%s: %v", what, err)
+	}
+	if wireErr.Code != code {
+		return warn(nil, "%s returned error %d (%s); want %d (%s)", what, wireErr.Code, wireErr.Message, code, codeNames[code])
+	}
+	return pass("%s returned error %d (%s)", what, code, codeNames[code])
+}
+
+func checkCapabilities(ctx context.Context, r *run) outcome {
+	var f findings
+	var working, absent []string
+	for _, c := range []struct {
+		capability, method string
+		params             any
+	}{
+		{"tools", "tools/list", nil},
+		{"resources", "resources/list", nil},
+		{"prompts", "prompts/list", nil},
+		{"logging", "logging/setLevel", map[string]any{"level": "info"}},
+	} {
+		_, err := r.client.call(ctx, c.method, c.params)
+		advertised := r.has(c.capability)
+		switch {
+		case advertised && err != nil:
+			f.add(Fail, "%s is advertised, but %s failed: %v", c.capability, c.method, err)
+		case !advertised && err == nil:
+			f.add(Warn, "%s answers, but the %s capability is not advertised", c.method, c.capability)
+		case advertised:
+			working = append(working, c.capability)
+		default:
+			absent = append(absent, c.capability)
+		}
+	}
+
+	summary := "advertised and working: " + listOrNone(working)
+	if len(absent) > 0 {
+		summary += "; not offered: " + strings.Join(absent, ", ")
+	}
+	return f.outcome(summary)
+}
+
+func checkPagination(ctx context.Context, r *run) outcome {
+	var f findings
+	var listed []string
+	for _, l := range []struct{ capability, method, field string }{
+		{"tools", "tools/list", "tools"},
+		{"resources", "resources/list", "resources"},
+		{"resources", "resources/templates/list", "resourceTemplates"},
+		{"prompts", "prompts/list", "prompts"},
+	} {
+		if !r.has(l.capability) {
+			continue
+		}
+		items, pages, err := r.paginate(ctx, l.method, l.field, &f)
+		if err != nil {
+			f.add(Fail, "%s failed on page %d: %v", l.method, pages+1, err)
+			continue
+		}
+		if l.method == "tools/list" {
+			r.tools, r.toolsListed = items, true
+		}
+		listed = append(listed, fmt.Sprintf("%s: %s, %s", l.field, plural(pages, "page"), plural(len(items), "item")))
+	}
+	if len(listed) == 0 && len(f.lines) == 0 {
+		return skip("the server advertises no tools, resources or prompts")
+	}
+
+	// A cursor the server never issued
+	cursorMethod := ""
+	for _, l := range []struct{ capability, method string }{
+		{"prompts", "prompts/list"},
+		{"resources", "resources/list"},
+		{"tools", "tools/list"},
+	} {
+		if r.has(l.capability) {
+			cursorMethod = l.method
+		}
+	}
+	if cursorMethod != "" {
+		_, err := r.client.call(ctx, cursorMethod, map[string]any{"cursor": invalidCursor})
+		wireErr, ok := protocolError(err)
+		switch {
+		case err == nil:
+			f.add(Warn, "%s accepted an invalid cursor; it should return error %d (invalid params)", cursorMethod, codeInvalidParams)
+		case !ok:
+			f.add(Fail, "%s with an invalid cursor: %v", cursorMethod, err)
+		case wireErr.Code != codeInvalidParams:
+			f.add(Warn, "%s refused an invalid cursor with error %d (%s); want %d (invalid params)", cursorMethod, wireErr.Code, wireErr.Message, codeInvalidParams)
+		default:
+			listed = append(listed, "invalid cursors are refused")
+		}
+	}
+	return f.outcome(strings.Join(listed, "; "))
+}
+
+// paginate follows nextCursor through every page of a list, reporting
+// cursors that loop and items listed twice
+func (r *run) paginate(ctx context.Context, method, field string, f *findings) ([]map[string]any, int, error) {
+	var items []map[string]any
+	seenCursors := make(map[string]bool)
+	seenItems := make(map[string]bool)
+	cursor := ""
+	for pages := 0; pages < maxPages; {
+		var params any
+		if cursor != "" {
+			params = map[string]any{"cursor": cursor}
+		}
+		data, err := r.client.call(ctx, method, params)
+		if err != nil {
+			return items, pages, err
+		}
+		pages++
+
+		var page map[string]any
+		if err := json.Unmarshal(data, &page); err != nil || page == nil {
+			f.add(Fail, "%s page %d is not an object", method, pages)
+			return items, pages, nil
+		}
+		list, ok := page[field].([]any)
+		if !ok {
+			f.add(Fail, "%s page %d has no %s array", method, pages, field)
+			return items, pages, nil
+		}
+		for _, item := range list {
+			entry, _ := item.(map[string]any)
+			items = append(items, entry)
+			if key := itemKey(entry); key != "" {
+				if seenItems[key] {
+					f.add(Warn, "%s lists %s more than once", method, key)
+				}
+				seenItems[key] = true
+			}
+		}
+
+		next, ok := page["nextCursor"]
+		if !ok || next == nil {
+			return items, pages, nil
+		}
+		cursor, ok = next.(string)
+		switch {
+		case !ok:
+			f.add(Fail, "%s page %d has nextCursor %s; a cursor must be a string", method, pages, compact(next))
+			return items, pages, nil
+		case cursor == "":
+			f.add(Warn, "%s page %d has an empty nextCursor; leave it out on the last page", method, pages)
+			return items, pages, nil
+		case seenCursors[cursor]:
+			f.add(Fail, "%s returned cursor %q twice, so its pages loop", method, cursor)
+			return items, pages, nil
+		}
+		seenCursors[cursor] = true
+	}
+	f.add(Warn, "%s still had more pages after %d", method, maxPages)
+	return items, maxPages, nil
+}
+
+// itemKey identifies a listed tool, prompt, resource or resource template
+func itemKey(item map[string]any) string {
+	for _, field := range []string{"name", "uri", "uriTemplate"} {
+		if value, ok := item[field].(string); ok && value != "" {
+			return fmt.Sprintf("%s %q", field, value)
+		}
+	}
+	return ""
+}
+
+func checkToolSchemas(ctx context.Context, r *run) outcome {
+	if !r.has("tools") {
+		return skip("the server does not advertise tools")
+	}
+	if !r.toolsListed {
+		return skip("tools/list failed")
+	}
+
+	var problems []string
+	names := make(map[string]bool)
+	for i, tool := range r.tools {
+		if tool == nil {
+			problems = append(problems, fmt.Sprintf("tool %d is not an object", i+1))
+			continue
+		}
+		name, _ := tool["name"].(string)
+		switch {
+		case name == "":
+			problems = append(problems, fmt.Sprintf("tool %d has no name", i+1))
+			name = fmt.Sprintf("tool %d", i+1)
+		case names[name]:
+			problems = append(problems, fmt.Sprintf("%s: the name is not unique", name))
+		}
+		names[name] = true
+
+		problems = append(problems, checkSchema(name, "inputSchema", tool["inputSchema"], true)...)
+		problems = append(problems, checkSchema(name, "outputSchema", tool["outputSchema"], false)...)
+	}
+	if len(problems) > 0 {
+		return fail(limit(problems), "%s with invalid definitions", plural(len(problems), "problem"))
+	}
+	return pass("%s with valid object schemas", plural(len(r.tools), "tool"))
+}
+
+// checkSchema checks that a tool schema is a JSON Schema for an object
+func checkSchema(tool, field string, value any, required bool) []string {
+	if value == nil {
+		if required {
+			return []string{fmt.Sprintf("%s: %s is missing", tool, field)}
+		}
+		return nil
+	}
+	schema, ok := value.(map[string]any)
+	if !ok {
+		return []string{fmt.Sprintf("%s: %s is %s, not an object", tool, field, compact(value))}
+	}
+	if schema["type"] != "object" {
+		return []string{fmt.Sprintf("%s: %s has type %s; it must be \"object\"", tool, field, compact(schema["type"]))}
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %s: %v", tool, field, err)}
+	}
+	var s jsonschema.Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return []string{fmt.Sprintf("%s: %s is not a valid JSON Schema: %v", tool, field, err)}
+	}
+	if _, err := s.Resolve(nil); err != nil {
+		return []string{fmt.Sprintf("%s: %s is not a valid JSON Schema: %v", tool, field, err)}
+	}
+	return nil
+}
+
+func checkToolErrors(ctx context.Context, r *run) outcome {
+	if !r.has("tools") {
+		return skip("the server does not advertise tools")
+	}
+
+	var f findings
+	data, err := r.client.call(ctx, "tools/call", map[string]any{"name": unknownTool, "arguments": map[string]any{}})
+	wireErr, isProtocolError := protocolError(err)
+	switch {
+	case err == nil && isErrorResult(data):
+		f.add(Warn, "an unknown tool gave an isError result; unknown tools are protocol errors")
+	case err == nil:
+		f.add(Fail, "calling an unknown tool succeeded")
+	case isProtocolError:
+		f.add(Pass, "an unknown tool is protocol error %d (%s)", wireErr.Code, wireErr.Message)
+	default:
+		f.add(Fail, "calling an unknown tool failed: %v", err)
+	}
+
+	// Leaving out a required argument must fail one way or the other,
+	// which keeps the tool from doing anything
+	if name, required := toolWithRequiredArguments(r.tools); name != "" {
+		data, err := r.client.call(ctx, "tools/call", map[string]any{"name": name, "arguments": map[string]any{}})
+		wireErr, isProtocolError := protocolError(err)
+		switch {
+		case err == nil && isErrorResult(data):
+			f.add(Pass, "%s without its required arguments gave an isError result", name)
+		case err == nil:
+			f.add(Warn, "%s succeeded without its required arguments %s", name, strings.Join(required, ", "))
+		case isProtocolError:
+			f.add(Pass, "%s without its required arguments is protocol error %d (%s)", name, wireErr.Code, wireErr.Message)
+		default:
+			f.add(Fail, "calling %s without its required arguments failed: %v", name, err)
+		}
+	}
+	return f.outcome(strings.Join(f.lines, "; "))
+}
+
+// toolWithRequiredArguments returns the first tool with required arguments
+func toolWithRequiredArguments(tools []map[string]any) (string, []string) {
+	for _, tool := range tools {
+		name, _ := tool["name"].(string)
+		schema, _ := tool["inputSchema"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		if name == "" || len(required) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(required))
+		for _, r := range required {
+			names = append(names, fmt.Sprint(r))
+		}
+		return name, names
+	}
+	return "", nil
+}
+
+// isErrorResult reports whether a tool result is flagged isError
+func isErrorResult(data json.RawMessage) bool {
+	var result struct {
+		IsError bool `json:"isError"`
+	}
+	json.Unmarshal(data, &result)
+	return result.IsError
+}
+
+func checkNotifications(ctx context.Context, r *run) outcome {
+	if err := r.client.notify(ctx, "notifications/mcp-tui/check", map[string]any{"note": "an unknown notification"}); err != nil {
+		return fail(nil, "%v", err)
+	}
+	if err := r.client.notify(ctx, "notifications/cancelled", map[string]any{"requestId": "mcp-tui-check-never-sent", "reason": "conformance check"}); err != nil {
+		return fail(nil, "%v", err)
+	}
+	// The server handles messages in order, so by the time ping is answered
+	// any reply to the notifications has arrived
+	if _, err := r.client.call(ctx, "ping", nil); err != nil {
+		return fail(nil, "the server stopped answering after unknown notifications: %v", err)
+	}
+
+	notifications, unexpected := r.client.received()
+	var f findings
+	for _, resp := range unexpected {
+		f.add(Fail, "the server sent a response with id %v, which matches no request", resp.ID.Raw())
+	}
+	for _, n := range notifications {
+		if !strings.HasPrefix(n.Method, "notifications/") {
+			f.add(Warn, "the server sent notification %q; notification methods start with notifications/", n.Method)
+		}
+	}
+	return f.outcome(fmt.Sprintf("unknown and cancellation notifications were ignored; %s received", plural(len(notifications), "server notification")))
+}
+
+func checkStdout(ctx context.Context, r *run) outcome {
+	stdio := false
+	var noise []string
+	for _, cl := range r.clients {
+		if recorder, ok := cl.conn.(noiseRecorder); ok {
+			stdio = true
+			noise = append(noise, recorder.Noise()...)
+		}
+	}
+	if !stdio {
+		return skip("only stdio servers carry messages on stdout")
+	}
+	if len(noise) > 0 {
+		return fail(limit(noise), "the server wrote %s to stdout that did not hold a JSON-RPC message", plural(len(noise), "line"))
+	}
+	return pass("every line on stdout was a JSON-RPC message")
+}
+
+// findings collects what a probe with several checks found. The worst
+// status decides the outcome.
+type findings struct {
+	status Status
+	lines  []string
+	worst  string
+}
+
+func (f *findings) add(status Status, format string, args ...any) {
+	line := fmt.Sprintf(format, args...)
+	f.lines = append(f.lines, line)
+	if rank(status) > rank(f.status) {
+		f.status, f.worst = status, line
+	}
+}
+
+// outcome passes with a summary, or reports the first of the worst findings
+// with every finding as details
+func (f *findings) outcome(summary string) outcome {
+	switch f.status {
+	case Fail:
+		return fail(limit(f.lines), "%s", f.worst)
+	case Warn:
+		return warn(limit(f.lines), "%s", f.worst)
+	}
+	return pass("%s", summary)
+}
+
+func rank(status Status) int {
+	switch status {
+	case Warn:
+		return 1
+	case Fail:
+		return 2
+	}
+	return 0
+}
+
+// limit keeps a result's details short
+func limit(details []string) []string {
+	if len(details) <= maxDetails {
+		return details
+	}
+	return append(details[:maxDetails:maxDetails], fmt.Sprintf("and %d more", len(details)-maxDetails))
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func listOrNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// compact formats a value as one short line of JSON
+func compact(value any) string {
+	var data []byte
+	if raw, ok := value.(json.RawMessage); ok {
+		data = raw
+	} else {
+		var err error
+		if data, err = json.Marshal(value); err != nil {
+			return fmt.Sprint(value)
+		}
+	}
+	const max = 120
+	if len(data) > max {
+		return string(data[:max]) + "..."
+	}
+	return string(data)
+}
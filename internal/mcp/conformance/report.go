@@ -0,0 +1,55 @@
+package conformance
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// statusMarks mark each result in the text report
+var statusMarks = map[Status]string{
+	Pass: "✓",
+	Warn: "!",
+	Fail: "✗",
+	Skip: "-",
+}
+
+// WriteText writes a readable report: a line per probe, with the details and
+// specification reference of warnings and failures indented below it, and a
+// summary
+func WriteText(w io.Writer, report *Report) error {
+	var b strings.Builder
+	server := report.Server
+	switch {
+	case server.Name == "":
+		fmt.Fprintf(&b, "MCP server over %s\n\n", server.Transport)
+	case server.Version == "":
+		fmt.Fprintf(&b, "%s, protocol %s over %s\n\n", server.Name, server.ProtocolVersion, server.Transport)
+	default:
+		fmt.Fprintf(&b, "%s %s, protocol %s over %s\n\n", server.Name, server.Version, server.ProtocolVersion, server.Transport)
+	}
+
+	for _, result := range report.Results {
+		if result.Status == Skip {
+			fmt.Fprintf(&b, "  %s %s (skipped: %s)\n", statusMarks[Skip], result.Title, result.Message)
+			continue
+		}
+		fmt.Fprintf(&b, "  %s %s: %s\n", statusMarks[result.Status], result.Title, result.Message)
+		if result.Status == Pass {
+			continue
+		}
+		for _, detail := range result.Details {
+			if detail != result.Message {
+				fmt.Fprintf(&b, "      %s\n", detail)
+			}
+		}
+		fmt.Fprintf(&b, "      spec: %s\n", result.Spec)
+	}
+
+	s := report.Summary
+	fmt.Fprintf(&b, "\n%d passed, %s, %d failed, %d skipped in %s\n", s.Passed, plural(s.Warnings, "warning"), s.Failed, s.Skipped,
+		(time.Duration(report.DurationMs) * time.Millisecond).String())
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,72 @@
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Session is an initialized connection that, like the checker's, shows the
+// server's exact replies: a JSON-RPC error response is returned as a
+// *transports.WireError, and the connection can be asked whether the server
+// went away. It is for tools that send requests a well-behaved client never
+// would.
+type Session struct {
+	client *client
+
+	// ServerName is the name the server gave in its initialize result
+	ServerName string
+}
+
+// Open dials a connection and performs the initialization handshake
+func Open(ctx context.Context, dial Dialer, timeout time.Duration) (*Session, error) {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	conn, err := dial(dialCtx)
+	if err != nil {
+		return nil, err
+	}
+	cl := newClient(conn, timeout)
+
+	result, err := initialize(ctx, cl, ProtocolVersion)
+	if err != nil {
+		cl.close()
+		return nil, err
+	}
+	if err := cl.notify(ctx, "notifications/initialized", nil); err != nil {
+		cl.close()
+		return nil, err
+	}
+	info, _ := result["serverInfo"].(map[string]any)
+	name, _ := info["name"].(string)
+	return &Session{client: cl, ServerName: name}, nil
+}
+
+// Call sends a request and waits for its response, for at most timeout
+// when it is shorter than the session's
+func (s *Session) Call(ctx context.Context, method string, params any, timeout time.Duration) (json.RawMessage, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return s.client.call(ctx, method, params)
+}
+
+// Closed reports whether the connection has ended
+func (s *Session) Closed() bool {
+	return s.client.closed()
+}
+
+// ExitDetails describes how a stdio server ended, from its stderr
+func (s *Session) ExitDetails() []string {
+	return s.client.exitDetails()
+}
+
+// Close closes the connection
+func (s *Session) Close() {
+	s.client.close()
+}
@@ -1,7 +1,10 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptrace"
 	"sync"
 	"time"
 )
@@ -11,6 +14,7 @@ type ConnectionStage string
 
 const (
 	StageConnecting       ConnectionStage = "connecting"
+	StageNegotiating      ConnectionStage = "negotiating"
 	StageDNSLookup        ConnectionStage = "dns_lookup"
 	StageTCPConnect       ConnectionStage = "tcp_connect"
 	StageTLSHandshake     ConnectionStage = "tls_handshake"
@@ -31,50 +35,53 @@ type ConnectionStateInfo struct {
 	Timestamp time.Time       `json:"timestamp"`
 }
 
-var (
-	currentConnectionState *ConnectionStateInfo
-	connectionStateMux     sync.RWMutex
-)
+// connectionState tracks how far one service's connection has got, for its
+// status display. The zero value has no state yet.
+type connectionState struct {
+	mu   sync.RWMutex
+	info *ConnectionStateInfo
+}
 
-// SetConnectionState updates the current connection state
-func SetConnectionState(stage ConnectionStage, message string, url string, err error) {
-	connectionStateMux.Lock()
-	defer connectionStateMux.Unlock()
+// set updates the connection state
+func (cs *connectionState) set(stage ConnectionStage, message string, url string, err error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
 
-	currentConnectionState = &ConnectionStateInfo{
+	cs.info = &ConnectionStateInfo{
 		Stage:     stage,
 		Message:   message,
 		URL:       url,
 		Timestamp: time.Now(),
 	}
-
 	if err != nil {
-		currentConnectionState.Error = err.Error()
+		cs.info.Error = err.Error()
 	}
+}
 
-	if currentConnectionState != nil {
-		currentConnectionState.Duration = time.Since(currentConnectionState.Timestamp)
-	}
+// reset forgets the state of an earlier connection
+func (cs *connectionState) reset() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.info = nil
 }
 
-// GetConnectionState returns the current connection state
-func GetConnectionState() *ConnectionStateInfo {
-	connectionStateMux.RLock()
-	defer connectionStateMux.RUnlock()
+// get returns a copy of the connection state, or nil if there is none
+func (cs *connectionState) get() *ConnectionStateInfo {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
 
-	if currentConnectionState == nil {
+	if cs.info == nil {
 		return nil
 	}
 
 	// Return a copy to avoid race conditions
-	state := *currentConnectionState
-	state.Duration = time.Since(currentConnectionState.Timestamp)
+	state := *cs.info
+	state.Duration = time.Since(cs.info.Timestamp)
 	return &state
 }
 
-// GetConnectionDisplayMessage returns a user-friendly message for the current state
-func GetConnectionDisplayMessage() string {
-	state := GetConnectionState()
+// DisplayMessage returns a user-friendly message for the state
+func (state *ConnectionStateInfo) DisplayMessage() string {
 	if state == nil {
 		return "Initializing connection..."
 	}
@@ -83,27 +90,26 @@ func GetConnectionDisplayMessage() string {
 	case StageDNSLookup:
 		return fmt.Sprintf("Resolving DNS for %s...", state.URL)
 	case StageTCPConnect:
-		return fmt.Sprintf("Establishing TCP connection...")
+		return "Establishing TCP connection..."
 	case StageTLSHandshake:
-		return fmt.Sprintf("Performing TLS handshake...")
+		return "Performing TLS handshake..."
 	case StageRequestSent:
-		return fmt.Sprintf("MCP initialize request sent...")
+		return "MCP initialize request sent..."
 	case StageWaitingResponse:
 		return fmt.Sprintf("Waiting for server response... (%s)", state.Duration.Round(time.Second))
 	case StageResponseReceived:
-		return fmt.Sprintf("Processing server response...")
+		return "Processing server response..."
 	case StageFailed:
 		return fmt.Sprintf("Connection failed: %s", state.Error)
 	case StageCompleted:
-		return fmt.Sprintf("Connected successfully!")
+		return "Connected successfully!"
 	default:
 		return state.Message
 	}
 }
 
-// GetServerDiagnosticMessage returns guidance for server-side issues
-func GetServerDiagnosticMessage() string {
-	state := GetConnectionState()
+// DiagnosticMessage returns guidance for server-side issues in the state
+func (state *ConnectionStateInfo) DiagnosticMessage() string {
 	if state == nil {
 		return ""
 	}
@@ -129,3 +135,56 @@ func GetServerDiagnosticMessage() string {
 		return ""
 	}
 }
+
+// streamStateKey is the request context key of the service's stream state
+type streamStateKey struct{}
+
+// stateRoundTripper records the stages of a service's HTTP requests in its
+// connection state, and passes its stream state on to the debug round
+// tripper through the request context
+type stateRoundTripper struct {
+	base   http.RoundTripper
+	state  *connectionState
+	stream *streamState
+}
+
+// trackHTTPClient returns a copy of client whose requests are tracked in
+// the service's connection state
+func trackHTTPClient(client *http.Client, state *connectionState, stream *streamState) *http.Client {
+	tracked := *client
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	tracked.Transport = &stateRoundTripper{base: base, state: state, stream: stream}
+	return &tracked
+}
+
+func (t *stateRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	url := req.URL.String()
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(info httptrace.DNSStartInfo) {
+			t.state.set(StageDNSLookup, "DNS lookup started", info.Host, nil)
+		},
+		ConnectStart: func(network, addr string) {
+			t.state.set(StageTCPConnect, "TCP connection started", addr, nil)
+		},
+		TLSHandshakeStart: func() {
+			t.state.set(StageTLSHandshake, "TLS handshake started", url, nil)
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			t.state.set(StageWaitingResponse, "Waiting for server response", url, nil)
+		},
+	}
+	ctx := httptrace.WithClientTrace(req.Context(), trace)
+	req = req.WithContext(context.WithValue(ctx, streamStateKey{}, t.stream))
+
+	t.state.set(StageRequestSent, "MCP initialize request sent", url, nil)
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.state.set(StageFailed, "Request failed", url, err)
+		return nil, err
+	}
+	t.state.set(StageResponseReceived, "Response received", url, nil)
+	return resp, nil
+}
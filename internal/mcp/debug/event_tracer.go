@@ -130,7 +130,9 @@ func (et *EventTracer) TraceConnectionEnd(startEvent *Event, success bool, error
 	}
 
 	event := et.addEvent(EventConnectionEnd, "", nil, data)
-	event.Duration = duration
+	if event != nil {
+		event.Duration = duration
+	}
 	return event
 }
 
@@ -219,6 +221,25 @@ func (et *EventTracer) TraceNotificationReceived(method string, params interface
 	return et.addEvent(EventNotificationReceived, method, nil, data)
 }
 
+// TraceNotificationSent records an outgoing MCP notification
+func (et *EventTracer) TraceNotificationSent(method string, params interface{}) *Event {
+	data := map[string]interface{}{
+		"direction": "outgoing",
+	}
+
+	if params != nil {
+		// Safely serialize params
+		if paramsJSON, err := json.Marshal(params); err == nil {
+			var paramsMap map[string]interface{}
+			if json.Unmarshal(paramsJSON, &paramsMap) == nil {
+				data["params"] = paramsMap
+			}
+		}
+	}
+
+	return et.addEvent(EventNotificationSent, method, nil, data)
+}
+
 // TraceError records an error event
 func (et *EventTracer) TraceError(operation string, error error, context map[string]interface{}) *Event {
 	data := map[string]interface{}{
@@ -375,6 +396,21 @@ func (et *EventTracer) GetRecentEvents(count int) []*Event {
 	return events
 }
 
+// Restore puts events saved by an earlier run ahead of the events traced
+// since, keeping the buffer within its size
+func (et *EventTracer) Restore(events []*Event) {
+	et.mu.Lock()
+	defer et.mu.Unlock()
+
+	restored := make([]*Event, 0, len(events)+len(et.events))
+	restored = append(restored, events...)
+	restored = append(restored, et.events...)
+	if len(restored) > et.maxEvents {
+		restored = restored[len(restored)-et.maxEvents:]
+	}
+	et.events = restored
+}
+
 // GetEventsByType returns events filtered by type
 func (et *EventTracer) GetEventsByType(eventType EventType) []*Event {
 	et.mu.RLock()
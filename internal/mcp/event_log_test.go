@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+)
+
+func TestServicesKeepSeparateEventLogs(t *testing.T) {
+	server := officialMCP.NewServer(&officialMCP.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	server.AddTool(&officialMCP.Tool{Name: "echo", Description: "Echo input"},
+		func(ctx context.Context, ss *officialMCP.ServerSession, params *officialMCP.CallToolParamsFor[map[string]any]) (*officialMCP.CallToolResult, error) {
+			if err := ss.Log(ctx, &officialMCP.LoggingMessageParams{Level: "info", Data: "echoing"}); err != nil {
+				return nil, err
+			}
+			return &officialMCP.CallToolResult{Content: []officialMCP.Content{&officialMCP.TextContent{Text: "ok"}}}, nil
+		})
+	httpServer := httptest.NewServer(officialMCP.NewStreamableHTTPHandler(func(*http.Request) *officialMCP.Server { return server }, nil))
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn := &config.ConnectionConfig{Type: config.TransportStreamableHTTP, URL: httpServer.URL}
+
+	first, second := NewServiceWithConfig(nil), NewServiceWithConfig(nil)
+	require.NoError(t, first.Connect(ctx, conn))
+	defer first.Disconnect()
+	require.NoError(t, second.Connect(ctx, conn))
+	defer second.Disconnect()
+
+	assert.Equal(t, "test-server", first.GetServerInfo().Name, "The server's reported name is kept")
+	require.NoError(t, first.SetLogLevel(ctx, "debug"))
+	_, err := first.CallTool(ctx, CallToolRequest{Name: "echo"})
+	require.NoError(t, err)
+
+	methods := func(s Service, messageType debug.MCPMessageType) []string {
+		var methods []string
+		for _, entry := range s.EventLog().GetEntries() {
+			if entry.MessageType == messageType {
+				methods = append(methods, entry.Method)
+			}
+		}
+		return methods
+	}
+
+	assert.Contains(t, methods(first, debug.MCPMessageRequest), "tools/call")
+	assert.Eventually(t, func() bool {
+		for _, method := range methods(first, debug.MCPMessageNotification) {
+			if method == "notifications/message" {
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond, "Server notifications are logged")
+	assert.Contains(t, methods(second, debug.MCPMessageRequest), "initialize")
+	assert.NotContains(t, methods(second, debug.MCPMessageRequest), "tools/call",
+		"Each service logs only its own traffic")
+}
@@ -4,7 +4,6 @@ package mcp
 func NewService() Service {
 	s := &service{
 		info:      &ServerInfo{},
-		requestID: 0,
 		debugMode: true, // Always enable debug mode - this is a testing tool
 	}
 	// Enable HTTP debugging immediately
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
 )
 
 var (
@@ -56,6 +57,15 @@ type SSEConnectionInfo struct {
 	ConnectionDrops int
 	StreamDuration  time.Duration
 	LastEventData   string
+
+	// Resumability of streamable HTTP response streams
+	SessionID           string
+	LastEventID         string
+	ResumptionAttempts  int
+	ResumptionSuccesses int
+	ResumptionFailures  int
+	SessionTerminated   bool
+	LastStreamError     string
 }
 
 // GetLastHTTPError returns the last HTTP error info
@@ -103,7 +113,6 @@ func (t *debugRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 	trace := &httptrace.ClientTrace{
 		DNSStart: func(info httptrace.DNSStartInfo) {
 			dnsStart = time.Now()
-			SetConnectionState(StageDNSLookup, "DNS lookup started", info.Host, nil)
 			if t.debugMode {
 				debug.Info("DNS lookup started", debug.F("host", info.Host))
 			}
@@ -120,7 +129,6 @@ func (t *debugRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 		},
 		ConnectStart: func(network, addr string) {
 			connectStart = time.Now()
-			SetConnectionState(StageTCPConnect, "TCP connection started", addr, nil)
 			if t.debugMode {
 				debug.Info("TCP connection started", debug.F("addr", addr))
 			}
@@ -138,7 +146,6 @@ func (t *debugRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 		},
 		TLSHandshakeStart: func() {
 			tlsStart = time.Now()
-			SetConnectionState(StageTLSHandshake, "TLS handshake started", req.URL.String(), nil)
 			if t.debugMode {
 				debug.Info("TLS handshake started")
 			}
@@ -193,7 +200,6 @@ func (t *debugRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 		req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
 	}
 
-	SetConnectionState(StageRequestSent, "MCP initialize request sent", req.URL.String(), nil)
 	if t.debugMode {
 		debug.Info("Starting HTTP request",
 			debug.F("method", req.Method),
@@ -202,10 +208,8 @@ func (t *debugRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 	}
 
 	// Execute the request
-	SetConnectionState(StageWaitingResponse, "Waiting for server response", req.URL.String(), nil)
 	resp, err := t.base.RoundTrip(req)
 	if err != nil {
-		SetConnectionState(StageFailed, "Request failed", req.URL.String(), err)
 		// Even on failure, capture the connection details for debugging
 		headers := make(map[string]string)
 		errorInfo := &HTTPErrorInfo{
@@ -231,7 +235,6 @@ func (t *debugRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 		return nil, err
 	}
 
-	SetConnectionState(StageResponseReceived, "Response received", req.URL.String(), nil)
 	// Capture response body
 	if resp.Body != nil {
 		bodyBytes, err := io.ReadAll(resp.Body)
@@ -263,12 +266,17 @@ func (t *debugRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 			// Create SSE info if this is an SSE connection
 			var sseInfo *SSEConnectionInfo
 			if isSSE {
-				sseInfo = &SSEConnectionInfo{
-					EventsReceived: 0, // Will be updated by SSE handler
-					LastEventTime:  time.Now(),
-					StreamDuration: time.Since(firstByteStart),
-					LastEventData:  string(bodyBytes),
+				// Start from the stream counters the transport recorded for
+				// the service making the request, if any
+				if stream, ok := req.Context().Value(streamStateKey{}).(*streamState); ok {
+					sseInfo = stream.get()
+				}
+				if sseInfo == nil {
+					sseInfo = &SSEConnectionInfo{}
 				}
+				sseInfo.LastEventTime = time.Now()
+				sseInfo.StreamDuration = time.Since(firstByteStart)
+				sseInfo.LastEventData = string(bodyBytes)
 			}
 
 			errorInfo := &HTTPErrorInfo{
@@ -310,6 +318,76 @@ func (t *debugRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 	return resp, nil
 }
 
+// streamState holds the stream state of one service's streamable HTTP
+// connection. The zero value has seen no stream activity.
+type streamState struct {
+	mu   sync.RWMutex
+	info *SSEConnectionInfo
+}
+
+// reset clears stream state at the start of a new connection
+func (ss *streamState) reset() {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ss.info = nil
+}
+
+// record updates stream state from streamable HTTP transport events
+func (ss *streamState) record(evt transports.StreamEvent) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if ss.info == nil {
+		ss.info = &SSEConnectionInfo{}
+	}
+	info := ss.info
+	if evt.SessionID != "" {
+		info.SessionID = evt.SessionID
+	}
+	if evt.LastEventID != "" {
+		info.LastEventID = evt.LastEventID
+	}
+	if evt.Err != nil {
+		info.LastStreamError = evt.Err.Error()
+	}
+
+	switch evt.Type {
+	case transports.StreamEventReceived:
+		info.EventsReceived++
+		info.LastEventTime = time.Now()
+	case transports.StreamEventDropped:
+		info.ConnectionDrops++
+	case transports.StreamEventResumeAttempt:
+		info.ResumptionAttempts++
+		debug.Info("Resuming dropped stream",
+			debug.F("sessionID", evt.SessionID),
+			debug.F("lastEventID", evt.LastEventID),
+			debug.F("attempt", evt.Attempt))
+	case transports.StreamEventResumed:
+		info.ResumptionSuccesses++
+	case transports.StreamEventResumeFailed:
+		info.ResumptionFailures++
+		debug.Error("Stream resumption failed",
+			debug.F("sessionID", evt.SessionID),
+			debug.F("error", evt.Err))
+	case transports.StreamEventSessionTerminated:
+		info.SessionTerminated = true
+	}
+}
+
+// get returns a copy of the stream state, or nil if no stream activity has
+// been seen
+func (ss *streamState) get() *SSEConnectionInfo {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	if ss.info == nil {
+		return nil
+	}
+	info := *ss.info
+	return &info
+}
+
 // FormatHTTPError formats the HTTP error information for display
 func FormatHTTPError(info *HTTPErrorInfo) string {
 	if info == nil {
@@ -351,6 +429,11 @@ func FormatHTTPError(info *HTTPErrorInfo) string {
 		sb.WriteString(fmt.Sprintf("  Last Event Time: %s\n", sse.LastEventTime.Format(time.RFC3339)))
 		sb.WriteString(fmt.Sprintf("  Stream Duration: %v\n", sse.StreamDuration))
 		sb.WriteString(fmt.Sprintf("  Connection Drops: %d\n", sse.ConnectionDrops))
+		if sse.ResumptionAttempts > 0 {
+			sb.WriteString(fmt.Sprintf("  Resumption Attempts: %d (succeeded: %d, failed: %d)\n",
+				sse.ResumptionAttempts, sse.ResumptionSuccesses, sse.ResumptionFailures))
+			sb.WriteString(fmt.Sprintf("  Last Event ID: %s\n", sse.LastEventID))
+		}
 		if sse.LastEventData != "" {
 			sb.WriteString(fmt.Sprintf("  Last Event Data: %s\n", truncateString(sse.LastEventData, 100)))
 		}
@@ -0,0 +1,242 @@
+// Package mock runs a configurable MCP server from a YAML or JSON definition.
+// It is used by tests and CI that should not depend on Node-based servers, and
+// lets client developers prototype against a server that does not exist yet.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonschema"
+	"gopkg.in/yaml.v3"
+
+	"github.com/standardbeagle/mcp-tui/internal/yamlutil"
+)
+
+// Definition describes a mock MCP server
+type Definition struct {
+	Server        ServerInfo     `json:"server"`
+	Tools         []Tool         `json:"tools"`
+	Resources     []Resource     `json:"resources"`
+	Prompts       []Prompt       `json:"prompts"`
+	Notifications []Notification `json:"notifications"` // Sent once the client has initialized
+}
+
+// ServerInfo is reported in the initialize result
+type ServerInfo struct {
+	Name            string `json:"name"`
+	Version         string `json:"version"`
+	Instructions    string `json:"instructions"`
+	ProtocolVersion string `json:"protocolVersion"` // Defaults to the version the client asks for, if known
+}
+
+// Behavior holds the options shared by tools, resources and prompts
+type Behavior struct {
+	Delay         yamlutil.Duration `json:"delay"`         // Wait before answering
+	Error         *ErrorSpec        `json:"error"`         // Inject a failure
+	Notifications []Notification    `json:"notifications"` // Sent before the response
+}
+
+// ErrorSpec injects a failure. Without a code, tools answer with an isError
+// result; with a code, or for resources and prompts, a JSON-RPC error is sent.
+type ErrorSpec struct {
+	Probability *float64 `json:"probability"` // 0..1, always when omitted
+	Code        int64    `json:"code"`
+	Message     string   `json:"message"`
+}
+
+// Notification is a templated JSON-RPC notification
+type Notification struct {
+	Method string            `json:"method"`
+	Params map[string]any    `json:"params"`
+	After  yamlutil.Duration `json:"after"` // Delay before sending
+}
+
+// Tool declares a tool and how it answers
+type Tool struct {
+	Name        string             `json:"name"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	InputSchema *jsonschema.Schema `json:"inputSchema"`
+	Response    ToolResponse       `json:"response"`
+	Responses   []ToolResponse     `json:"responses"` // Tried in order; the first whose "when" matches wins
+	Progress    []float64          `json:"progress"`  // Progress values reported when the call has a progress token
+	Behavior
+
+	resolved *jsonschema.Resolved
+}
+
+// ToolResponse is a canned or templated tool result
+type ToolResponse struct {
+	When              map[string]any `json:"when"`              // Argument values that select this response
+	Text              string         `json:"text"`              // Templated text content
+	Content           []any          `json:"content"`           // Raw content blocks, string values templated
+	StructuredContent any            `json:"structuredContent"` // Returned as-is
+	IsError           bool           `json:"isError"`
+}
+
+// Resource declares a readable resource
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MIMEType    string `json:"mimeType"`
+	Text        string `json:"text"` // Templated contents
+	Blob        string `json:"blob"` // Base64 contents
+	Behavior
+}
+
+// Prompt declares a prompt template
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Arguments   []PromptArgument `json:"arguments"`
+	Messages    []PromptMessage  `json:"messages"`
+	Behavior
+}
+
+// PromptArgument declares a prompt argument
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+// PromptMessage is a templated prompt message
+type PromptMessage struct {
+	Role string `json:"role"`
+	Text string `json:"text"`
+}
+
+// LoadDefinition reads a definition from a YAML or JSON file
+func LoadDefinition(path string) (*Definition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock definition: %w", err)
+	}
+	def, err := ParseDefinition(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mock definition %s: %w", path, err)
+	}
+	return def, nil
+}
+
+// ParseDefinition parses a YAML or JSON definition. YAML is decoded to generic
+// values and re-encoded as JSON so both formats share the JSON field names and
+// JSON Schema decoding.
+func ParseDefinition(data []byte) (*Definition, error) {
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	jsonData, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("definition is not JSON-compatible: %w", err)
+	}
+
+	var def Definition
+	if err := json.Unmarshal(jsonData, &def); err != nil {
+		return nil, err
+	}
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+	return &def, nil
+}
+
+// Validate checks the definition and resolves tool input schemas
+func (d *Definition) Validate() error {
+	if d.Server.Name == "" {
+		d.Server.Name = "mcp-tui-mock"
+	}
+	if d.Server.Version == "" {
+		d.Server.Version = "0.0.0"
+	}
+
+	seen := make(map[string]bool)
+	for i := range d.Tools {
+		tool := &d.Tools[i]
+		if tool.Name == "" {
+			return fmt.Errorf("tools[%d]: name is required", i)
+		}
+		if seen["tool:"+tool.Name] {
+			return fmt.Errorf("tools[%d]: duplicate tool %q", i, tool.Name)
+		}
+		seen["tool:"+tool.Name] = true
+
+		if tool.InputSchema == nil {
+			tool.InputSchema = &jsonschema.Schema{Type: "object"}
+		}
+		resolved, err := tool.InputSchema.Resolve(nil)
+		if err != nil {
+			return fmt.Errorf("tool %q: invalid input schema: %w", tool.Name, err)
+		}
+		tool.resolved = resolved
+		if err := tool.Behavior.validate(); err != nil {
+			return fmt.Errorf("tool %q: %w", tool.Name, err)
+		}
+	}
+
+	for i := range d.Resources {
+		resource := &d.Resources[i]
+		if !strings.Contains(resource.URI, ":") {
+			return fmt.Errorf("resources[%d]: an absolute uri is required", i)
+		}
+		if seen["resource:"+resource.URI] {
+			return fmt.Errorf("resources[%d]: duplicate resource %q", i, resource.URI)
+		}
+		seen["resource:"+resource.URI] = true
+		if resource.Name == "" {
+			resource.Name = resource.URI
+		}
+		if err := resource.Behavior.validate(); err != nil {
+			return fmt.Errorf("resource %q: %w", resource.URI, err)
+		}
+	}
+
+	for i := range d.Prompts {
+		prompt := &d.Prompts[i]
+		if prompt.Name == "" {
+			return fmt.Errorf("prompts[%d]: name is required", i)
+		}
+		if seen["prompt:"+prompt.Name] {
+			return fmt.Errorf("prompts[%d]: duplicate prompt %q", i, prompt.Name)
+		}
+		seen["prompt:"+prompt.Name] = true
+		for j, msg := range prompt.Messages {
+			if msg.Role != "user" && msg.Role != "assistant" {
+				return fmt.Errorf("prompt %q: messages[%d]: role must be user or assistant", prompt.Name, j)
+			}
+		}
+		if err := prompt.Behavior.validate(); err != nil {
+			return fmt.Errorf("prompt %q: %w", prompt.Name, err)
+		}
+	}
+
+	return validateNotifications(d.Notifications)
+}
+
+// validate checks the shared behavior options
+func (b *Behavior) validate() error {
+	if b.Delay < 0 {
+		return fmt.Errorf("delay must not be negative")
+	}
+	if b.Error != nil && b.Error.Probability != nil {
+		if p := *b.Error.Probability; p < 0 || p > 1 {
+			return fmt.Errorf("error probability must be between 0 and 1, got %v", p)
+		}
+	}
+	return validateNotifications(b.Notifications)
+}
+
+// validateNotifications checks that notifications name a method
+func validateNotifications(notifications []Notification) error {
+	for i, n := range notifications {
+		if n.Method == "" {
+			return fmt.Errorf("notifications[%d]: method is required", i)
+		}
+	}
+	return nil
+}
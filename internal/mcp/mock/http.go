@@ -0,0 +1,87 @@
+package mock
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/streamable"
+)
+
+// HTTPHandler returns a streamable HTTP handler for the mock server
+func (s *Server) HTTPHandler() http.Handler {
+	handler := streamable.NewHandler(func(ctx context.Context) (officialMCP.Connection, error) {
+		return s.newConn(ctx), nil
+	})
+	handler.Name = "Mock"
+	return handler
+}
+
+// conn presents a session as a connection: requests written to it are
+// answered concurrently, and the responses and notifications are read back
+type conn struct {
+	*session
+	cancel   context.CancelFunc
+	incoming chan jsonrpc.Message
+}
+
+// newConn starts a session whose lifetime is bound to ctx
+func (s *Server) newConn(ctx context.Context) *conn {
+	ctx, cancel := context.WithCancel(ctx)
+	c := &conn{cancel: cancel, incoming: make(chan jsonrpc.Message, 16)}
+	c.session = s.newSession(ctx, c.send)
+	return c
+}
+
+// SessionID returns the session ID, which the HTTP handler assigns
+func (c *conn) SessionID() string {
+	return ""
+}
+
+// Read returns the next response or notification
+func (c *conn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case msg := <-c.incoming:
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.ctx.Done():
+		return nil, officialMCP.ErrConnectionClosed
+	}
+}
+
+// Write handles a client message
+func (c *conn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	req, ok := msg.(*jsonrpc.Request)
+	if !ok {
+		return nil // Responses to server requests are not expected
+	}
+	if !req.ID.IsValid() {
+		c.handleNotification(req)
+		return nil
+	}
+	go func() {
+		if err := c.send(c.handleRequest(c.ctx, req, c.send)); err != nil {
+			debug.Error("Mock: Failed to write response", debug.F("error", err))
+		}
+	}()
+	return nil
+}
+
+// Close ends the session
+func (c *conn) Close() error {
+	c.cancel()
+	return nil
+}
+
+// send queues a message for Read
+func (c *conn) send(msg jsonrpc.Message) error {
+	select {
+	case c.incoming <- msg:
+		return nil
+	case <-c.ctx.Done():
+		return officialMCP.ErrConnectionClosed
+	}
+}
@@ -0,0 +1,298 @@
+package mock
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
+	"github.com/standardbeagle/mcp-tui/internal/yamlutil"
+)
+
+const testDefinition = `
+server:
+  name: test-mock
+  version: 2.0.0
+tools:
+  - name: greet
+    description: Greets someone
+    inputSchema:
+      type: object
+      properties:
+        name: {type: string}
+      required: [name]
+    responses:
+      - when: {name: nobody}
+        text: "nobody to greet"
+        isError: true
+    response:
+      text: "Hello, {{.args.name}}!"
+    progress: [1, 2]
+    notifications:
+      - method: notifications/message
+        params: {level: info, data: "greeting {{.args.name}}"}
+  - name: broken
+    error:
+      code: -32050
+      message: "broken on purpose"
+  - name: failing
+    error:
+      message: "tool failed"
+  - name: slow
+    delay: 5s
+resources:
+  - uri: mock://readme
+    mimeType: text/plain
+    text: "read me"
+prompts:
+  - name: ask
+    arguments:
+      - name: topic
+        required: true
+    messages:
+      - role: user
+        text: "Tell me about {{.args.topic}}"
+`
+
+func loadTestServer(t *testing.T) *Server {
+	t.Helper()
+	def, err := ParseDefinition([]byte(testDefinition))
+	require.NoError(t, err)
+	return NewServer(def)
+}
+
+// clientHandlers collects notifications received by the SDK client
+type clientHandlers struct {
+	mu       sync.Mutex
+	logs     []any
+	progress []float64
+}
+
+func (h *clientHandlers) options() *officialMCP.ClientOptions {
+	return &officialMCP.ClientOptions{
+		LoggingMessageHandler: func(ctx context.Context, cs *officialMCP.ClientSession, p *officialMCP.LoggingMessageParams) {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.logs = append(h.logs, p.Data)
+		},
+		ProgressNotificationHandler: func(ctx context.Context, cs *officialMCP.ClientSession, p *officialMCP.ProgressNotificationParams) {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			h.progress = append(h.progress, p.Progress)
+		},
+	}
+}
+
+// waitFor waits until the expected number of notifications have been handled
+func (h *clientHandlers) waitFor(t *testing.T, logs, progress int) {
+	t.Helper()
+	assert.Eventually(t, func() bool {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		return len(h.logs) >= logs && len(h.progress) >= progress
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func connectClient(t *testing.T, transport officialMCP.Transport, handlers *clientHandlers) *officialMCP.ClientSession {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := officialMCP.NewClient(&officialMCP.Implementation{Name: "test-client", Version: "1.0.0"}, handlers.options())
+	session, err := client.Connect(ctx, transport)
+	require.NoError(t, err)
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+// serveOnSocket serves the mock's stdio framing on a Unix socket and returns a client transport for it
+func serveOnSocket(t *testing.T, server *Server) officialMCP.Transport {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mock.sock")
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		server.ServeStream(context.Background(), conn, conn)
+	}()
+
+	transport, _, err := transports.NewFactory().CreateTransport(&transports.TransportConfig{
+		Type: transports.TransportUnix,
+		URL:  "unix://" + path,
+	})
+	require.NoError(t, err)
+	return transport
+}
+
+func TestMockServerOverStream(t *testing.T) {
+	handlers := &clientHandlers{}
+	session := connectClient(t, serveOnSocket(t, loadTestServer(t)), handlers)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run("templated tool response with notifications", func(t *testing.T) {
+		result, err := session.CallTool(ctx, &officialMCP.CallToolParams{
+			Meta:      officialMCP.Meta{"progressToken": "greet-1"},
+			Name:      "greet",
+			Arguments: map[string]any{"name": "Ada"},
+		})
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, "Hello, Ada!", result.Content[0].(*officialMCP.TextContent).Text)
+
+		// The SDK client dispatches notifications asynchronously
+		handlers.waitFor(t, 1, 2)
+		handlers.mu.Lock()
+		defer handlers.mu.Unlock()
+		assert.Equal(t, []any{"greeting Ada"}, handlers.logs)
+		assert.Equal(t, []float64{1, 2}, handlers.progress)
+	})
+
+	t.Run("conditional response", func(t *testing.T) {
+		result, err := session.CallTool(ctx, &officialMCP.CallToolParams{Name: "greet", Arguments: map[string]any{"name": "nobody"}})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "nobody to greet", result.Content[0].(*officialMCP.TextContent).Text)
+	})
+
+	t.Run("schema validation", func(t *testing.T) {
+		_, err := session.CallTool(ctx, &officialMCP.CallToolParams{Name: "greet", Arguments: map[string]any{}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid arguments")
+	})
+
+	t.Run("injected errors", func(t *testing.T) {
+		_, err := session.CallTool(ctx, &officialMCP.CallToolParams{Name: "broken"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "broken on purpose")
+
+		result, err := session.CallTool(ctx, &officialMCP.CallToolParams{Name: "failing"})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "tool failed", result.Content[0].(*officialMCP.TextContent).Text)
+	})
+
+	t.Run("delay honors cancellation", func(t *testing.T) {
+		shortCtx, shortCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer shortCancel()
+		start := time.Now()
+		_, err := session.CallTool(shortCtx, &officialMCP.CallToolParams{Name: "slow"})
+		require.Error(t, err)
+		assert.Less(t, time.Since(start), 2*time.Second)
+	})
+
+	t.Run("resources and prompts", func(t *testing.T) {
+		resource, err := session.ReadResource(ctx, &officialMCP.ReadResourceParams{URI: "mock://readme"})
+		require.NoError(t, err)
+		assert.Equal(t, "read me", resource.Contents[0].Text)
+
+		_, err = session.ReadResource(ctx, &officialMCP.ReadResourceParams{URI: "mock://missing"})
+		assert.Error(t, err)
+
+		prompt, err := session.GetPrompt(ctx, &officialMCP.GetPromptParams{Name: "ask", Arguments: map[string]string{"topic": "Go"}})
+		require.NoError(t, err)
+		assert.Equal(t, "Tell me about Go", prompt.Messages[0].Content.(*officialMCP.TextContent).Text)
+
+		_, err = session.GetPrompt(ctx, &officialMCP.GetPromptParams{Name: "ask"})
+		assert.Error(t, err)
+	})
+}
+
+func TestMockServerInitialize(t *testing.T) {
+	result, err := loadTestServer(t).initialize([]byte(`{"protocolVersion":"2025-03-26"}`))
+	require.NoError(t, err)
+
+	init := result.(map[string]any)
+	assert.Equal(t, "2025-03-26", init["protocolVersion"], "should echo the client's protocol version")
+	assert.Equal(t, map[string]any{"name": "test-mock", "version": "2.0.0"}, init["serverInfo"])
+	assert.Contains(t, init["capabilities"], "tools")
+	assert.Contains(t, init["capabilities"], "resources")
+	assert.Contains(t, init["capabilities"], "prompts")
+
+	result, err = loadTestServer(t).initialize([]byte(`{"protocolVersion":"1999-01-01"}`))
+	require.NoError(t, err)
+	assert.Equal(t, defaultProtocolVersion, result.(map[string]any)["protocolVersion"], "should offer its own version for unknown ones")
+}
+
+func TestMockServerOverHTTP(t *testing.T) {
+	httpServer := httptest.NewServer(loadTestServer(t).HTTPHandler())
+	defer httpServer.Close()
+
+	transport, _, err := transports.NewFactory().CreateTransport(&transports.TransportConfig{
+		Type: transports.TransportHTTP,
+		URL:  httpServer.URL,
+	})
+	require.NoError(t, err)
+
+	handlers := &clientHandlers{}
+	session := connectClient(t, transport, handlers)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tools, err := session.ListTools(ctx, nil)
+	require.NoError(t, err)
+	assert.Len(t, tools.Tools, 4)
+
+	result, err := session.CallTool(ctx, &officialMCP.CallToolParams{Name: "greet", Arguments: map[string]any{"name": "Grace"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, Grace!", result.Content[0].(*officialMCP.TextContent).Text)
+
+	handlers.waitFor(t, 1, 0)
+	handlers.mu.Lock()
+	assert.Equal(t, []any{"greeting Grace"}, handlers.logs, "notifications should arrive on the POST stream")
+	handlers.mu.Unlock()
+}
+
+func TestParseDefinitionErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		definition string
+		errMsg     string
+	}{
+		{"missing tool name", "tools: [{description: x}]", "name is required"},
+		{"duplicate tool", "tools: [{name: a}, {name: a}]", "duplicate tool"},
+		{"bad probability", "tools: [{name: a, error: {probability: 2}}]", "between 0 and 1"},
+		{"relative resource uri", "resources: [{uri: readme}]", "absolute uri"},
+		{"bad prompt role", "prompts: [{name: p, messages: [{role: system, text: x}]}]", "role must be"},
+		{"bad duration", "tools: [{name: a, delay: soon}]", "invalid duration"},
+		{"notification without method", "notifications: [{after: 1s}]", "method is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDefinition([]byte(tt.definition))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestParseDefinitionJSONAndDefaults(t *testing.T) {
+	def, err := ParseDefinition([]byte(`{"tools": [{"name": "ping", "delay": 150}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "mcp-tui-mock", def.Server.Name)
+	assert.Equal(t, yamlutil.Duration(150*time.Millisecond), def.Tools[0].Delay)
+	assert.NotNil(t, def.Tools[0].InputSchema)
+}
+
+func TestExampleDefinition(t *testing.T) {
+	def, err := LoadDefinition(filepath.Join("..", "..", "..", "examples", "mock-server.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "mock-weather", def.Server.Name)
+}
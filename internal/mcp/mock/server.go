@@ -0,0 +1,644 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
+)
+
+// defaultProtocolVersion is offered when the client asks for no version, or
+// for one the mock does not know
+const defaultProtocolVersion = "2025-06-18"
+
+// supportedProtocolVersions are the versions the mock agrees to speak
+var supportedProtocolVersions = []string{defaultProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes used by the mock
+const (
+	codeMethodNotFound   = -32601
+	codeInvalidParams    = -32602
+	codeInternalError    = -32603
+	codeResourceNotFound = -32002
+)
+
+// Server answers MCP requests from a Definition. It is transport-agnostic:
+// ServeStream and HTTPHandler feed it JSON-RPC messages.
+type Server struct {
+	def *Definition
+}
+
+// NewServer creates a mock server for a validated definition
+func NewServer(def *Definition) *Server {
+	return &Server{def: def}
+}
+
+// notifyFunc sends a message to the client while a request is being handled
+type notifyFunc func(jsonrpc.Message) error
+
+// session is one client connection
+type session struct {
+	server *Server
+	ctx    context.Context
+	send   notifyFunc // Delivers server-initiated notifications
+
+	mu          sync.Mutex
+	inflight    map[string]context.CancelFunc
+	initialized bool
+}
+
+// newSession starts a session whose lifetime is bound to ctx
+func (s *Server) newSession(ctx context.Context, send notifyFunc) *session {
+	return &session{
+		server:   s,
+		ctx:      ctx,
+		send:     send,
+		inflight: make(map[string]context.CancelFunc),
+	}
+}
+
+// handleRequest answers a request. Cancelled requests still get an error
+// response because SDK clients keep the call in flight until one arrives.
+func (ss *session) handleRequest(ctx context.Context, req *jsonrpc.Request, notify notifyFunc) *jsonrpc.Response {
+	ctx, cancel := context.WithCancel(ctx)
+	key := fmt.Sprint(req.ID.Raw())
+	ss.mu.Lock()
+	ss.inflight[key] = cancel
+	ss.mu.Unlock()
+	defer func() {
+		ss.mu.Lock()
+		delete(ss.inflight, key)
+		ss.mu.Unlock()
+		cancel()
+	}()
+
+	resp := ss.server.handle(ctx, req, notify)
+	if ctx.Err() != nil {
+		return &jsonrpc.Response{ID: req.ID, Error: &transports.WireError{Code: codeInternalError, Message: "request cancelled"}}
+	}
+	return resp
+}
+
+// handleNotification reacts to client notifications
+func (ss *session) handleNotification(req *jsonrpc.Request) {
+	switch req.Method {
+	case "notifications/initialized":
+		ss.mu.Lock()
+		first := !ss.initialized
+		ss.initialized = true
+		ss.mu.Unlock()
+		if first && len(ss.server.def.Notifications) > 0 {
+			go func() {
+				templateData := map[string]any{"server": ss.server.def.Server.Name}
+				if err := sendNotifications(ss.ctx, ss.server.def.Notifications, templateData, ss.send); err != nil {
+					debug.Warn("Mock: Failed to send session notification", debug.F("error", err))
+				}
+			}()
+		}
+	case "notifications/cancelled":
+		var params struct {
+			RequestID any `json:"requestId"`
+		}
+		if json.Unmarshal(req.Params, &params) == nil {
+			ss.mu.Lock()
+			if cancel := ss.inflight[fmt.Sprint(params.RequestID)]; cancel != nil {
+				cancel()
+			}
+			ss.mu.Unlock()
+		}
+	}
+}
+
+// handle dispatches a request to the method handlers
+func (s *Server) handle(ctx context.Context, req *jsonrpc.Request, notify notifyFunc) *jsonrpc.Response {
+	debug.Info("Mock: Request", debug.F("method", req.Method), debug.F("id", req.ID.Raw()))
+
+	if strings.HasSuffix(req.Method, "/list") {
+		if wireErr := checkCursor(req.Params); wireErr != nil {
+			return &jsonrpc.Response{ID: req.ID, Error: wireErr}
+		}
+	}
+
+	var result any
+	var err error
+	switch req.Method {
+	case "initialize":
+		result, err = s.initialize(req.Params)
+	case "ping", "logging/setLevel":
+		result = struct{}{}
+	case "tools/list":
+		result = s.listTools()
+	case "tools/call":
+		result, err = s.callTool(ctx, req.Params, notify)
+	case "resources/list":
+		result = s.listResources()
+	case "resources/templates/list":
+		result = map[string]any{"resourceTemplates": []any{}}
+	case "resources/read":
+		result, err = s.readResource(ctx, req.Params, notify)
+	case "prompts/list":
+		result = s.listPrompts()
+	case "prompts/get":
+		result, err = s.getPrompt(ctx, req.Params, notify)
+	case "completion/complete":
+		result = map[string]any{"completion": map[string]any{"values": []string{}, "total": 0, "hasMore": false}}
+	default:
+		err = &transports.WireError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", req.Method)}
+	}
+
+	if err != nil {
+		wireErr, ok := err.(*transports.WireError)
+		if !ok {
+			wireErr = &transports.WireError{Code: codeInternalError, Message: err.Error()}
+		}
+		return &jsonrpc.Response{ID: req.ID, Error: wireErr}
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return &jsonrpc.Response{ID: req.ID, Error: &transports.WireError{Code: codeInternalError, Message: err.Error()}}
+	}
+	return &jsonrpc.Response{ID: req.ID, Result: data}
+}
+
+// checkCursor refuses list cursors, since the mock returns every item on one
+// page and so never issues one
+func checkCursor(params json.RawMessage) *transports.WireError {
+	var p struct {
+		Cursor string `json:"cursor"`
+	}
+	if json.Unmarshal(params, &p) == nil && p.Cursor != "" {
+		return &transports.WireError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid cursor: %s", p.Cursor)}
+	}
+	return nil
+}
+
+// initialize answers the handshake, echoing the client's protocol version
+// when the mock supports it
+func (s *Server) initialize(params json.RawMessage) (any, error) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	json.Unmarshal(params, &p)
+
+	version := s.def.Server.ProtocolVersion
+	if version == "" && slices.Contains(supportedProtocolVersions, p.ProtocolVersion) {
+		version = p.ProtocolVersion
+	}
+	if version == "" {
+		version = defaultProtocolVersion
+	}
+
+	capabilities := map[string]any{"logging": map[string]any{}}
+	if len(s.def.Tools) > 0 {
+		capabilities["tools"] = map[string]any{}
+	}
+	if len(s.def.Resources) > 0 {
+		capabilities["resources"] = map[string]any{}
+	}
+	if len(s.def.Prompts) > 0 {
+		capabilities["prompts"] = map[string]any{}
+	}
+
+	result := map[string]any{
+		"protocolVersion": version,
+		"capabilities":    capabilities,
+		"serverInfo":      map[string]any{"name": s.def.Server.Name, "version": s.def.Server.Version},
+	}
+	if s.def.Server.Instructions != "" {
+		result["instructions"] = s.def.Server.Instructions
+	}
+	return result, nil
+}
+
+// listTools returns the declared tools
+func (s *Server) listTools() any {
+	tools := make([]map[string]any, 0, len(s.def.Tools))
+	for _, tool := range s.def.Tools {
+		entry := map[string]any{"name": tool.Name, "inputSchema": tool.InputSchema}
+		if tool.Title != "" {
+			entry["title"] = tool.Title
+		}
+		if tool.Description != "" {
+			entry["description"] = tool.Description
+		}
+		tools = append(tools, entry)
+	}
+	return map[string]any{"tools": tools}
+}
+
+// callTool validates the arguments and produces the configured result
+func (s *Server) callTool(ctx context.Context, params json.RawMessage, notify notifyFunc) (any, error) {
+	var p struct {
+		Name      string         `json:"name"`
+		Arguments map[string]any `json:"arguments"`
+		Meta      struct {
+			ProgressToken any `json:"progressToken"`
+		} `json:"_meta"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &transports.WireError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	tool := s.findTool(p.Name)
+	if tool == nil {
+		return nil, &transports.WireError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool: %s", p.Name)}
+	}
+	if p.Arguments == nil {
+		p.Arguments = map[string]any{}
+	}
+	if err := tool.resolved.Validate(p.Arguments); err != nil {
+		return nil, &transports.WireError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid arguments for %s: %v", tool.Name, err)}
+	}
+
+	data := map[string]any{"name": tool.Name, "args": p.Arguments, "progressToken": p.Meta.ProgressToken}
+	if err := s.applyBehavior(ctx, &tool.Behavior, data, notify); err != nil {
+		if toolErr, ok := err.(*toolError); ok {
+			return textResult(toolErr.message, true), nil
+		}
+		return nil, err
+	}
+
+	if p.Meta.ProgressToken != nil {
+		for i, progress := range tool.Progress {
+			notification := map[string]any{"progressToken": p.Meta.ProgressToken, "progress": progress}
+			if total := tool.Progress[len(tool.Progress)-1]; total > 0 {
+				notification["total"] = total
+			}
+			if err := sendMessage(notify, "notifications/progress", notification); err != nil {
+				debug.Warn("Mock: Failed to send progress", debug.F("step", i), debug.F("error", err))
+			}
+		}
+	}
+
+	response := selectResponse(tool, p.Arguments)
+	return buildToolResult(response, data)
+}
+
+// findTool looks up a tool by name
+func (s *Server) findTool(name string) *Tool {
+	for i := range s.def.Tools {
+		if s.def.Tools[i].Name == name {
+			return &s.def.Tools[i]
+		}
+	}
+	return nil
+}
+
+// selectResponse picks the first response whose "when" matches the arguments
+func selectResponse(tool *Tool, args map[string]any) ToolResponse {
+	for _, response := range tool.Responses {
+		if matchesWhen(response.When, args) {
+			return response
+		}
+	}
+	return tool.Response
+}
+
+// matchesWhen reports whether every "when" value equals the argument of that name
+func matchesWhen(when map[string]any, args map[string]any) bool {
+	for key, want := range when {
+		got, ok := args[key]
+		if !ok || !reflect.DeepEqual(normalize(want), normalize(got)) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize round-trips a value through JSON so YAML ints compare equal to JSON numbers
+func normalize(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	json.Unmarshal(data, &out)
+	return out
+}
+
+// buildToolResult renders a tool response
+func buildToolResult(response ToolResponse, data map[string]any) (any, error) {
+	content := []any{}
+	if response.Text != "" {
+		text, err := render(response.Text, data)
+		if err != nil {
+			return nil, err
+		}
+		content = append(content, map[string]any{"type": "text", "text": text})
+	}
+	for _, block := range response.Content {
+		rendered, err := renderValue(block, data)
+		if err != nil {
+			return nil, err
+		}
+		content = append(content, rendered)
+	}
+	if len(content) == 0 && response.StructuredContent == nil {
+		content = append(content, map[string]any{"type": "text", "text": "ok"})
+	}
+
+	result := map[string]any{"content": content}
+	if response.StructuredContent != nil {
+		result["structuredContent"] = response.StructuredContent
+	}
+	if response.IsError {
+		result["isError"] = true
+	}
+	return result, nil
+}
+
+// textResult is a tool result with a single text block
+func textResult(text string, isError bool) map[string]any {
+	result := map[string]any{"content": []any{map[string]any{"type": "text", "text": text}}}
+	if isError {
+		result["isError"] = true
+	}
+	return result
+}
+
+// listResources returns the declared resources
+func (s *Server) listResources() any {
+	resources := make([]map[string]any, 0, len(s.def.Resources))
+	for _, resource := range s.def.Resources {
+		entry := map[string]any{"uri": resource.URI, "name": resource.Name}
+		if resource.Description != "" {
+			entry["description"] = resource.Description
+		}
+		if resource.MIMEType != "" {
+			entry["mimeType"] = resource.MIMEType
+		}
+		resources = append(resources, entry)
+	}
+	return map[string]any{"resources": resources}
+}
+
+// readResource returns a resource's contents
+func (s *Server) readResource(ctx context.Context, params json.RawMessage, notify notifyFunc) (any, error) {
+	var p struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &transports.WireError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	var resource *Resource
+	for i := range s.def.Resources {
+		if s.def.Resources[i].URI == p.URI {
+			resource = &s.def.Resources[i]
+			break
+		}
+	}
+	if resource == nil {
+		return nil, &transports.WireError{Code: codeResourceNotFound, Message: fmt.Sprintf("resource not found: %s", p.URI)}
+	}
+
+	data := map[string]any{"uri": resource.URI, "name": resource.Name}
+	if err := s.applyBehavior(ctx, &resource.Behavior, data, notify); err != nil {
+		return nil, asWireError(err)
+	}
+
+	contents := map[string]any{"uri": resource.URI}
+	if resource.MIMEType != "" {
+		contents["mimeType"] = resource.MIMEType
+	}
+	if resource.Blob != "" {
+		contents["blob"] = resource.Blob
+	} else {
+		text, err := render(resource.Text, data)
+		if err != nil {
+			return nil, err
+		}
+		contents["text"] = text
+	}
+	return map[string]any{"contents": []any{contents}}, nil
+}
+
+// listPrompts returns the declared prompts
+func (s *Server) listPrompts() any {
+	prompts := make([]map[string]any, 0, len(s.def.Prompts))
+	for _, prompt := range s.def.Prompts {
+		entry := map[string]any{"name": prompt.Name}
+		if prompt.Description != "" {
+			entry["description"] = prompt.Description
+		}
+		if len(prompt.Arguments) > 0 {
+			entry["arguments"] = prompt.Arguments
+		}
+		prompts = append(prompts, entry)
+	}
+	return map[string]any{"prompts": prompts}
+}
+
+// getPrompt renders a prompt's messages
+func (s *Server) getPrompt(ctx context.Context, params json.RawMessage, notify notifyFunc) (any, error) {
+	var p struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &transports.WireError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	var prompt *Prompt
+	for i := range s.def.Prompts {
+		if s.def.Prompts[i].Name == p.Name {
+			prompt = &s.def.Prompts[i]
+			break
+		}
+	}
+	if prompt == nil {
+		return nil, &transports.WireError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown prompt: %s", p.Name)}
+	}
+	for _, arg := range prompt.Arguments {
+		if _, ok := p.Arguments[arg.Name]; arg.Required && !ok {
+			return nil, &transports.WireError{Code: codeInvalidParams, Message: fmt.Sprintf("missing required argument: %s", arg.Name)}
+		}
+	}
+
+	args := make(map[string]any, len(p.Arguments))
+	for k, v := range p.Arguments {
+		args[k] = v
+	}
+	data := map[string]any{"name": prompt.Name, "args": args}
+	if err := s.applyBehavior(ctx, &prompt.Behavior, data, notify); err != nil {
+		return nil, asWireError(err)
+	}
+
+	messages := make([]any, 0, len(prompt.Messages))
+	for _, msg := range prompt.Messages {
+		text, err := render(msg.Text, data)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, map[string]any{
+			"role":    msg.Role,
+			"content": map[string]any{"type": "text", "text": text},
+		})
+	}
+
+	result := map[string]any{"messages": messages}
+	if prompt.Description != "" {
+		result["description"] = prompt.Description
+	}
+	return result, nil
+}
+
+// toolError is an injected failure reported as an isError tool result
+type toolError struct {
+	message string
+}
+
+func (e *toolError) Error() string {
+	return e.message
+}
+
+// asWireError converts an injected tool failure to a JSON-RPC error
+func asWireError(err error) error {
+	if toolErr, ok := err.(*toolError); ok {
+		return &transports.WireError{Code: codeInternalError, Message: toolErr.message}
+	}
+	return err
+}
+
+// applyBehavior sends the configured notifications, waits out the delay and
+// rolls for an injected error
+func (s *Server) applyBehavior(ctx context.Context, behavior *Behavior, data map[string]any, notify notifyFunc) error {
+	if err := sendNotifications(ctx, behavior.Notifications, data, notify); err != nil {
+		return err
+	}
+
+	if behavior.Delay > 0 {
+		select {
+		case <-time.After(time.Duration(behavior.Delay)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	spec := behavior.Error
+	if spec == nil || (spec.Probability != nil && rand.Float64() >= *spec.Probability) {
+		return nil
+	}
+	message := spec.Message
+	if message == "" {
+		message = "injected error"
+	}
+	message, err := render(message, data)
+	if err != nil {
+		return err
+	}
+	if spec.Code != 0 {
+		return &transports.WireError{Code: spec.Code, Message: message}
+	}
+	return &toolError{message: message}
+}
+
+// sendNotifications renders and sends notifications in order, honoring their delays
+func sendNotifications(ctx context.Context, notifications []Notification, data map[string]any, notify notifyFunc) error {
+	for _, n := range notifications {
+		if n.After > 0 {
+			select {
+			case <-time.After(time.Duration(n.After)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		params, err := renderValue(n.Params, data)
+		if err != nil {
+			return err
+		}
+		if err := sendMessage(notify, n.Method, params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendMessage encodes params and sends a notification
+func sendMessage(notify notifyFunc, method string, params any) error {
+	var raw json.RawMessage
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		raw = data
+	}
+	return notify(&jsonrpc.Request{Method: method, Params: raw})
+}
+
+// singleFieldPattern matches a template that is just one field reference
+var singleFieldPattern = regexp.MustCompile(`^\{\{\s*\.(\w+)\s*\}\}$`)
+
+// renderValue renders every string in a decoded JSON value. A string that is a
+// single field reference such as "{{.progressToken}}" keeps the field's type.
+func renderValue(v any, data map[string]any) (any, error) {
+	switch val := v.(type) {
+	case string:
+		if m := singleFieldPattern.FindStringSubmatch(val); m != nil {
+			if field, ok := data[m[1]]; ok {
+				return field, nil
+			}
+		}
+		return render(val, data)
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			rendered, err := renderValue(item, data)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = rendered
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			rendered, err := renderValue(item, data)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// templateFuncs are available in response templates
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// render executes a text/template against the request data
+func render(text string, data map[string]any) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New("response").Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template %q: %w", text, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("template %q failed: %w", text, err)
+	}
+	return buf.String(), nil
+}
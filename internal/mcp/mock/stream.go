@@ -0,0 +1,92 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
+)
+
+// ServeStream serves one client over newline-delimited JSON, the stdio framing.
+// Requests are answered concurrently so delays do not block other requests.
+// It returns when r reaches EOF and in-flight requests are done, or ctx ends.
+func (s *Server) ServeStream(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var writeMu sync.Mutex
+	send := func(msg jsonrpc.Message) error {
+		data, err := transports.EncodeMessage(msg)
+		if err != nil {
+			return err
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_, err = w.Write(append(data, '\n'))
+		return err
+	}
+	sess := s.newSession(ctx, send)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	messages := make(chan json.RawMessage)
+	readErr := make(chan error, 1)
+	go func() {
+		decoder := json.NewDecoder(r)
+		for {
+			var raw json.RawMessage
+			if err := decoder.Decode(&raw); err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case messages <- raw:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		var raw json.RawMessage
+		select {
+		case raw = <-messages:
+		case err := <-readErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		msgs, err := transports.DecodeMessages(raw)
+		if err != nil {
+			debug.Error("Mock: Failed to decode JSON-RPC message", debug.F("error", err), debug.F("data", string(raw)))
+			continue
+		}
+		for _, msg := range msgs {
+			req, ok := msg.(*jsonrpc.Request)
+			if !ok {
+				continue // Responses to server requests are not expected
+			}
+			if !req.ID.IsValid() {
+				sess.handleNotification(req)
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := send(sess.handleRequest(ctx, req, send)); err != nil {
+					debug.Error("Mock: Failed to write response", debug.F("error", err))
+				}
+			}()
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package proxy
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/streamable"
+)
+
+// HTTPHandler returns a streamable HTTP handler that relays to the upstream
+// server, giving each session its own upstream connection
+func (p *Proxy) HTTPHandler() *streamable.Handler {
+	handler := streamable.NewHandler(func(ctx context.Context) (officialMCP.Connection, error) {
+		conn, err := p.connect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &observedConn{Connection: conn, proxy: p}, nil
+	})
+	handler.Name = "Proxy"
+	handler.OnInvalid = func(body []byte, err error) {
+		// Keep the broken body in the log; it is often why a client misbehaves
+		debug.LogMCPOutgoing(string(body), nil)
+		p.tracer.TraceError("decode_client_message", err, nil)
+	}
+	return handler
+}
+
+// observedConn is an upstream connection whose traffic the proxy records
+type observedConn struct {
+	officialMCP.Connection
+	proxy *Proxy
+}
+
+// Read returns the next server message
+func (c *observedConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	msg, err := c.Connection.Read(ctx)
+	if err == nil {
+		c.proxy.observe(msg, false)
+	}
+	return msg, err
+}
+
+// Write sends a client message upstream
+func (c *observedConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	return c.proxy.forward(ctx, c.Connection, msg)
+}
+
+// Close closes the upstream connection
+func (c *observedConn) Close() error {
+	c.proxy.disconnect(c.Connection)
+	return nil
+}
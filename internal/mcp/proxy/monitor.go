@@ -0,0 +1,232 @@
+package proxy
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+)
+
+// monitorPattern names the sockets of running proxies in the monitor directory
+const monitorPattern = "mcp-tui-proxy-*.sock"
+
+// Monitor streams captured MCP traffic to attached viewers over a Unix
+// socket. Each viewer first receives the traffic captured so far, then every
+// new message, as newline-delimited debug.MCPLogEntry values.
+type Monitor struct {
+	listener    net.Listener
+	path        string
+	unsubscribe func()
+
+	mu      sync.Mutex
+	viewers map[chan debug.MCPLogEntry]struct{}
+	closed  bool
+}
+
+// monitorDir returns the per-user directory holding the monitor sockets. The
+// temp directory is shared, so the sockets must not be reachable by others.
+func monitorDir() string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("mcp-tui-%d", os.Getuid()))
+}
+
+// DefaultMonitorPath returns the monitor socket path for this process,
+// creating the private directory it lives in
+func DefaultMonitorPath() (string, error) {
+	dir := monitorDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create monitor directory: %w", err)
+	}
+	// The directory may predate this process; refuse one others can enter
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to check monitor directory: %w", err)
+	}
+	if !info.IsDir() || info.Mode().Perm()&0077 != 0 {
+		return "", fmt.Errorf("monitor directory %s must be a directory only its owner can access", dir)
+	}
+	return filepath.Join(dir, fmt.Sprintf("mcp-tui-proxy-%d.sock", os.Getpid())), nil
+}
+
+// ListenMonitor serves the MCP logger's traffic on a Unix socket at path.
+// The socket is only accessible to its owner, as the traffic may carry secrets.
+func ListenMonitor(path string) (*Monitor, error) {
+	// A socket left behind by a crashed proxy would make Listen fail
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("another proxy is already serving %s", path)
+	}
+	os.Remove(path)
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on monitor socket: %w", err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to restrict monitor socket: %w", err)
+	}
+
+	m := &Monitor{
+		listener: listener,
+		path:     path,
+		viewers:  make(map[chan debug.MCPLogEntry]struct{}),
+	}
+	m.unsubscribe = debug.GetMCPLogger().Subscribe(m.publish)
+	go m.acceptLoop()
+	return m, nil
+}
+
+// Path returns the socket path viewers attach to
+func (m *Monitor) Path() string {
+	return m.path
+}
+
+// Close stops serving viewers and removes the socket
+func (m *Monitor) Close() error {
+	m.unsubscribe()
+
+	m.mu.Lock()
+	m.closed = true
+	for viewer := range m.viewers {
+		close(viewer)
+		delete(m.viewers, viewer)
+	}
+	m.mu.Unlock()
+
+	err := m.listener.Close()
+	os.Remove(m.path)
+	return err
+}
+
+// acceptLoop serves each viewer that connects
+func (m *Monitor) acceptLoop() {
+	for {
+		conn, err := m.listener.Accept()
+		if err != nil {
+			return
+		}
+		go m.serve(conn)
+	}
+}
+
+// serve sends the captured history, then live traffic, to one viewer
+func (m *Monitor) serve(conn net.Conn) {
+	defer conn.Close()
+
+	viewer := make(chan debug.MCPLogEntry, 256)
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	history := debug.GetMCPLogger().GetEntries()
+	m.viewers[viewer] = struct{}{}
+	m.mu.Unlock()
+	defer m.remove(viewer)
+
+	debug.Info("Proxy: Monitor attached")
+	writer := bufio.NewWriter(conn)
+	encoder := json.NewEncoder(writer)
+	for _, entry := range history {
+		if encoder.Encode(entry) != nil {
+			return
+		}
+	}
+	if writer.Flush() != nil {
+		return
+	}
+
+	for entry := range viewer {
+		if encoder.Encode(entry) != nil || writer.Flush() != nil {
+			return
+		}
+	}
+}
+
+// remove detaches a viewer
+func (m *Monitor) remove(viewer chan debug.MCPLogEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.viewers[viewer]; ok {
+		delete(m.viewers, viewer)
+		close(viewer)
+	}
+	debug.Info("Proxy: Monitor detached")
+}
+
+// publish forwards a logged entry to every viewer, skipping viewers that fall behind
+func (m *Monitor) publish(entry debug.MCPLogEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for viewer := range m.viewers {
+		select {
+		case viewer <- entry:
+		default:
+			debug.Warn("Proxy: Monitor is not keeping up, dropping traffic")
+		}
+	}
+}
+
+// FindMonitor returns the socket of the most recently started proxy that is
+// still accepting viewers
+func FindMonitor() (string, error) {
+	paths, err := filepath.Glob(filepath.Join(monitorDir(), monitorPattern))
+	if err != nil {
+		return "", err
+	}
+
+	type candidate struct {
+		path    string
+		modTime time.Time
+	}
+	var candidates []candidate
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			candidates = append(candidates, candidate{path, info.ModTime()})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].modTime.After(candidates[j].modTime) })
+
+	for _, c := range candidates {
+		if conn, err := net.DialTimeout("unix", c.path, time.Second); err == nil {
+			conn.Close()
+			return c.path, nil
+		}
+	}
+	return "", errors.New("no running proxy found; start one with: mcp-tui proxy -- <server>")
+}
+
+// Attachment is a viewer's connection to a proxy monitor
+type Attachment struct {
+	conn    net.Conn
+	decoder *json.Decoder
+}
+
+// Attach connects to the monitor socket at path
+func Attach(path string) (*Attachment, error) {
+	conn, err := net.DialTimeout("unix", path, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach to proxy at %s: %w", path, err)
+	}
+	return &Attachment{conn: conn, decoder: json.NewDecoder(bufio.NewReader(conn))}, nil
+}
+
+// Next blocks until the proxy relays another message
+func (a *Attachment) Next() (debug.MCPLogEntry, error) {
+	var entry debug.MCPLogEntry
+	err := a.decoder.Decode(&entry)
+	return entry, err
+}
+
+// Close detaches from the proxy
+func (a *Attachment) Close() error {
+	return a.conn.Close()
+}
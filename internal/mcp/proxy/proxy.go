@@ -0,0 +1,223 @@
+// Package proxy relays MCP traffic between a third-party client and a real
+// server. Every message is captured in the MCP logger and an EventTracer,
+// and can be watched live by attaching to the proxy's monitor socket.
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	mcpDebug "github.com/standardbeagle/mcp-tui/internal/mcp/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
+)
+
+// Upstream creates a transport to the real server. It is called once per
+// client, since transports such as stdio can only be connected once.
+type Upstream func() (officialMCP.Transport, error)
+
+// Proxy connects each downstream client to its own upstream connection
+type Proxy struct {
+	upstream Upstream
+	tracer   *mcpDebug.EventTracer
+}
+
+// New creates a proxy to the server reached through upstream
+func New(upstream Upstream) *Proxy {
+	return &Proxy{
+		upstream: upstream,
+		tracer:   mcpDebug.NewEventTracer(1000),
+	}
+}
+
+// Tracer returns the event tracer recording the relayed traffic
+func (p *Proxy) Tracer() *mcpDebug.EventTracer {
+	return p.tracer
+}
+
+// ServeStream relays one client speaking newline-delimited JSON-RPC, such as
+// the stdio transport, until either side closes
+func (p *Proxy) ServeStream(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	conn, err := p.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer p.disconnect(conn)
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- p.relayClient(ctx, r, conn)
+	}()
+	go func() {
+		var writeMu sync.Mutex
+		errs <- p.relayServer(ctx, conn, func(msg jsonrpc.Message) error {
+			data, err := transports.EncodeMessage(msg)
+			if err != nil {
+				return err
+			}
+			writeMu.Lock()
+			defer writeMu.Unlock()
+			_, err = w.Write(append(data, '\n'))
+			return err
+		})
+	}()
+
+	if err := <-errs; err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// ServeConnection relays a downstream connection, such as one made by an SDK
+// server transport, until either side closes
+func (p *Proxy) ServeConnection(ctx context.Context, downstream officialMCP.Connection) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	conn, err := p.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer p.disconnect(conn)
+
+	errs := make(chan error, 2)
+	go func() {
+		for {
+			msg, err := downstream.Read(ctx)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if err := p.forward(ctx, conn, msg); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+	go func() {
+		errs <- p.relayServer(ctx, conn, func(msg jsonrpc.Message) error {
+			return downstream.Write(ctx, msg)
+		})
+	}()
+
+	if err := <-errs; err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// connect opens an upstream connection for a new client
+func (p *Proxy) connect(ctx context.Context) (officialMCP.Connection, error) {
+	start := p.tracer.TraceConnectionStart("proxy", "upstream")
+	transport, err := p.upstream()
+	if err != nil {
+		p.tracer.TraceConnectionEnd(start, false, err.Error())
+		return nil, err
+	}
+	conn, err := transport.Connect(ctx)
+	if err != nil {
+		p.tracer.TraceConnectionEnd(start, false, err.Error())
+		return nil, err
+	}
+	p.tracer.TraceConnectionEnd(start, true, "")
+	p.tracer.TraceSessionState("connected", map[string]interface{}{"session_id": conn.SessionID()})
+	debug.Info("Proxy: Client connected upstream")
+	return conn, nil
+}
+
+// disconnect closes an upstream connection
+func (p *Proxy) disconnect(conn officialMCP.Connection) {
+	conn.Close()
+	p.tracer.TraceSessionState("closed", nil)
+	debug.Info("Proxy: Client disconnected")
+}
+
+// relayClient forwards frames read from a client stream to the server
+func (p *Proxy) relayClient(ctx context.Context, r io.Reader, conn officialMCP.Connection) error {
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if frame := bytes.TrimSpace(line); len(frame) > 0 {
+			msgs, decodeErr := transports.DecodeMessages(frame)
+			if decodeErr != nil {
+				// Keep the broken frame in the log; it is often why a client misbehaves
+				debug.LogMCPOutgoing(string(frame), nil)
+				p.tracer.TraceError("decode_client_message", decodeErr, nil)
+			}
+			for _, msg := range msgs {
+				if writeErr := p.forward(ctx, conn, msg); writeErr != nil {
+					return writeErr
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// forward records a client message and writes it upstream
+func (p *Proxy) forward(ctx context.Context, conn officialMCP.Connection, msg jsonrpc.Message) error {
+	p.observe(msg, true)
+	return conn.Write(ctx, msg)
+}
+
+// relayServer reads server messages, records them and hands them to deliver
+func (p *Proxy) relayServer(ctx context.Context, conn officialMCP.Connection, deliver func(jsonrpc.Message) error) error {
+	for {
+		msg, err := conn.Read(ctx)
+		if err != nil {
+			return err
+		}
+		p.observe(msg, false)
+		if err := deliver(msg); err != nil {
+			return err
+		}
+	}
+}
+
+// observe captures a relayed message in the MCP logger and the event tracer
+func (p *Proxy) observe(msg jsonrpc.Message, fromClient bool) {
+	data, err := transports.EncodeMessage(msg)
+	if err != nil {
+		debug.Error("Proxy: Failed to encode message", debug.F("error", err))
+		return
+	}
+	if fromClient {
+		debug.LogMCPOutgoing(string(data), nil)
+	} else {
+		debug.LogMCPIncoming(string(data), nil)
+	}
+
+	switch m := msg.(type) {
+	case *jsonrpc.Request:
+		switch {
+		case m.ID.IsValid() && fromClient:
+			p.tracer.TraceRequestSent(m.Method, m.ID.Raw(), m.Params)
+		case !m.ID.IsValid() && fromClient:
+			p.tracer.TraceNotificationSent(m.Method, m.Params)
+		case !m.ID.IsValid():
+			p.tracer.TraceNotificationReceived(m.Method, m.Params)
+		}
+	case *jsonrpc.Response:
+		if !fromClient {
+			var result, respErr interface{}
+			if len(m.Result) > 0 {
+				result = m.Result
+			}
+			if m.Error != nil {
+				respErr = m.Error
+			}
+			p.tracer.TraceResponseReceived(m.ID.Raw(), result, respErr)
+		}
+	}
+}
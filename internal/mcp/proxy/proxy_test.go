@@ -0,0 +1,435 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	mcpDebug "github.com/standardbeagle/mcp-tui/internal/mcp/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/mock"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/streamable"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
+)
+
+const testDefinition = `
+server:
+  name: upstream
+tools:
+  - name: greet
+    response:
+      text: "Hello, {{.args.name}}!"
+    notifications:
+      - method: notifications/message
+        params: {level: info, data: "greeting {{.args.name}}"}
+`
+
+// socketDir returns a short temporary directory, as Unix socket paths are length-limited
+func socketDir(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "proxy")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+// unixTransport returns a client transport for a Unix socket
+func unixTransport(t *testing.T, path string) officialMCP.Transport {
+	t.Helper()
+	transport, _, err := transports.NewFactory().CreateTransport(&transports.TransportConfig{
+		Type: transports.TransportUnix,
+		URL:  "unix://" + path,
+	})
+	require.NoError(t, err)
+	return transport
+}
+
+// startUpstream serves a mock server on a Unix socket and returns a proxy to it
+func startUpstream(t *testing.T) *Proxy {
+	t.Helper()
+	upstream, _ := mockUpstream(t)
+	return New(upstream)
+}
+
+// mockUpstream serves a mock server on a Unix socket, returning transports to
+// it and a count of the connections it has accepted
+func mockUpstream(t *testing.T) (Upstream, *atomic.Int32) {
+	t.Helper()
+	def, err := mock.ParseDefinition([]byte(testDefinition))
+	require.NoError(t, err)
+	server := mock.NewServer(def)
+
+	path := filepath.Join(socketDir(t), "upstream.sock")
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	accepted := &atomic.Int32{}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			go func() {
+				defer conn.Close()
+				server.ServeStream(context.Background(), conn, conn)
+			}()
+		}
+	}()
+
+	return func() (officialMCP.Transport, error) {
+		transport, _, err := transports.NewFactory().CreateTransport(&transports.TransportConfig{
+			Type: transports.TransportUnix,
+			URL:  "unix://" + path,
+		})
+		return transport, err
+	}, accepted
+}
+
+// logCollector records the log notifications received by a client
+type logCollector struct {
+	mu   sync.Mutex
+	logs []any
+}
+
+func (c *logCollector) connect(t *testing.T, transport officialMCP.Transport) *officialMCP.ClientSession {
+	t.Helper()
+	// The SDK's SSE client keeps its event stream on the Connect context
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	client := officialMCP.NewClient(&officialMCP.Implementation{Name: "test-client", Version: "1.0.0"}, &officialMCP.ClientOptions{
+		LoggingMessageHandler: func(ctx context.Context, cs *officialMCP.ClientSession, p *officialMCP.LoggingMessageParams) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.logs = append(c.logs, p.Data)
+		},
+	})
+	session, err := client.Connect(ctx, transport)
+	require.NoError(t, err)
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func (c *logCollector) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.logs)
+}
+
+// callGreet calls the upstream tool through the proxy and checks the relayed reply
+func callGreet(t *testing.T, session *officialMCP.ClientSession, logs *logCollector) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tools, err := session.ListTools(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, tools.Tools, 1)
+	assert.Equal(t, "greet", tools.Tools[0].Name)
+
+	result, err := session.CallTool(ctx, &officialMCP.CallToolParams{Name: "greet", Arguments: map[string]any{"name": "Ada"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, Ada!", result.Content[0].(*officialMCP.TextContent).Text)
+
+	assert.Eventually(t, func() bool { return logs.count() == 1 }, 2*time.Second, 10*time.Millisecond,
+		"server notifications should be relayed")
+}
+
+func TestProxyOverStream(t *testing.T) {
+	p := startUpstream(t)
+
+	path := filepath.Join(socketDir(t), "proxy.sock")
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	served := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			served <- err
+			return
+		}
+		defer conn.Close()
+		served <- p.ServeStream(context.Background(), conn, conn)
+	}()
+
+	logs := &logCollector{}
+	session := logs.connect(t, unixTransport(t, path))
+	callGreet(t, session, logs)
+
+	session.Close()
+	select {
+	case err := <-served:
+		assert.NoError(t, err, "a client hanging up is a normal end of the relay")
+	case <-time.After(5 * time.Second):
+		t.Fatal("relay did not end after the client disconnected")
+	}
+
+	var requests, responses, notifications int
+	for _, event := range p.Tracer().GetEvents() {
+		switch event.Type {
+		case mcpDebug.EventRequestSent:
+			requests++
+		case mcpDebug.EventResponseReceived:
+			responses++
+		case mcpDebug.EventNotificationReceived:
+			notifications++
+		}
+	}
+	assert.Equal(t, 3, requests, "initialize, tools/list and tools/call")
+	assert.Equal(t, 3, responses)
+	assert.Equal(t, 1, notifications)
+}
+
+func TestProxyOverHTTP(t *testing.T) {
+	p := startUpstream(t)
+	httpServer := httptest.NewServer(p.HTTPHandler())
+	defer httpServer.Close()
+
+	for _, name := range []string{"first", "second"} {
+		t.Run(name, func(t *testing.T) {
+			transport, _, err := transports.NewFactory().CreateTransport(&transports.TransportConfig{
+				Type: transports.TransportHTTP,
+				URL:  httpServer.URL,
+			})
+			require.NoError(t, err)
+
+			logs := &logCollector{}
+			session := logs.connect(t, transport)
+			callGreet(t, session, logs)
+		})
+	}
+}
+
+func TestProxyOverSSE(t *testing.T) {
+	p := startUpstream(t)
+	handler := p.SSEHandler()
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+
+	transport, _, err := transports.NewFactory().CreateTransport(&transports.TransportConfig{
+		Type: transports.TransportSSE,
+		URL:  httpServer.URL,
+	})
+	require.NoError(t, err)
+
+	logs := &logCollector{}
+	session := logs.connect(t, transport)
+	callGreet(t, session, logs)
+
+	require.NoError(t, handler.Close())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = session.ListTools(ctx, nil)
+	assert.Error(t, err, "closing the handler should end its sessions")
+}
+
+func TestSharedUpstream(t *testing.T) {
+	upstream, accepted := mockUpstream(t)
+	shared := NewShared(upstream)
+	defer shared.Close()
+
+	httpServer := httptest.NewServer(New(shared.Upstream()).HTTPHandler())
+	defer httpServer.Close()
+
+	// Both sessions number their requests alike; the IDs must not collide upstream
+	var sessions []*officialMCP.ClientSession
+	var collectors []*logCollector
+	for i := 0; i < 2; i++ {
+		transport, _, err := transports.NewFactory().CreateTransport(&transports.TransportConfig{
+			Type: transports.TransportHTTP,
+			URL:  httpServer.URL,
+		})
+		require.NoError(t, err)
+		logs := &logCollector{}
+		sessions = append(sessions, logs.connect(t, transport))
+		collectors = append(collectors, logs)
+	}
+
+	var wg sync.WaitGroup
+	for i, session := range sessions {
+		wg.Add(1)
+		go func(session *officialMCP.ClientSession, logs *logCollector) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			for j := 0; j < 5; j++ {
+				result, err := session.CallTool(ctx, &officialMCP.CallToolParams{Name: "greet", Arguments: map[string]any{"name": "Ada"}})
+				if assert.NoError(t, err) {
+					assert.Equal(t, "Hello, Ada!", result.Content[0].(*officialMCP.TextContent).Text)
+				}
+			}
+		}(session, collectors[i])
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), accepted.Load(), "all sessions should share one upstream connection")
+
+	// A session leaving must not end the others
+	sessions[0].Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := sessions[1].ListTools(ctx, nil)
+	assert.NoError(t, err)
+}
+
+func TestSharedInitializeOutlivesSession(t *testing.T) {
+	upstream, _ := mockUpstream(t)
+	shared := NewShared(upstream)
+	defer shared.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// A probe that hangs up during the handshake must not strand later sessions
+	initialize := func(id int64) *jsonrpc.Request {
+		reqID, err := transports.MakeID(id)
+		require.NoError(t, err)
+		return &jsonrpc.Request{ID: reqID, Method: "initialize", Params: json.RawMessage(`{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"probe","version":"1"}}`)}
+	}
+	probe, err := shared.open(ctx)
+	require.NoError(t, err)
+	require.NoError(t, probe.Write(ctx, initialize(1)))
+	probe.Close()
+
+	session, err := shared.open(ctx)
+	require.NoError(t, err)
+	defer session.Close()
+	require.NoError(t, session.Write(ctx, initialize(1)))
+
+	msg, err := session.Read(ctx)
+	require.NoError(t, err)
+	resp, ok := msg.(*jsonrpc.Response)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), resp.ID.Raw())
+	assert.NoError(t, resp.Error)
+	assert.Contains(t, string(resp.Result), "upstream")
+}
+
+func TestSharedSessionBehind(t *testing.T) {
+	upstream, _ := mockUpstream(t)
+	shared := NewShared(upstream)
+	defer shared.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	request := func(id int64, method, params string) *jsonrpc.Request {
+		reqID, err := transports.MakeID(id)
+		require.NoError(t, err)
+		return &jsonrpc.Request{ID: reqID, Method: method, Params: json.RawMessage(params)}
+	}
+	session, err := shared.open(ctx)
+	require.NoError(t, err)
+	defer session.Close()
+	require.NoError(t, session.Write(ctx, request(1, "initialize", `{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"slow","version":"1"}}`)))
+
+	// More responses than the session's inbox holds, none of them read
+	const requests = 300
+	for i := int64(2); i < requests; i++ {
+		if err := session.Write(ctx, request(i, "ping", `{}`)); err != nil {
+			assert.ErrorIs(t, err, ErrSessionBehind)
+			break
+		}
+	}
+
+	select {
+	case <-session.behind:
+	case <-ctx.Done():
+		t.Fatal("the session was not ended for falling behind")
+	}
+
+	// The responses that fit are still read; then the session ends rather
+	// than leaving a request without its response
+	read := 0
+	for {
+		_, err := session.Read(ctx)
+		if err != nil {
+			assert.ErrorIs(t, err, ErrSessionBehind)
+			break
+		}
+		read++
+	}
+	assert.LessOrEqual(t, read, cap(session.inbox))
+}
+
+func TestHTTPUnknownSession(t *testing.T) {
+	p := startUpstream(t)
+	httpServer := httptest.NewServer(p.HTTPHandler())
+	defer httpServer.Close()
+
+	req, err := http.NewRequest(http.MethodPost, httpServer.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	require.NoError(t, err)
+	req.Header.Set(streamable.SessionIDHeader, "missing")
+	resp, err := httpServer.Client().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestMonitor(t *testing.T) {
+	debug.InitMCPLogger(100)
+	debug.LogMCPOutgoing(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`, nil)
+
+	monitor, err := ListenMonitor(filepath.Join(socketDir(t), "monitor.sock"))
+	require.NoError(t, err)
+	defer monitor.Close()
+
+	_, err = ListenMonitor(monitor.Path())
+	assert.Error(t, err, "a second proxy must not take over a live socket")
+
+	info, err := os.Stat(monitor.Path())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "other users must not read the traffic")
+
+	attachment, err := Attach(monitor.Path())
+	require.NoError(t, err)
+	defer attachment.Close()
+
+	entry, err := attachment.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "tools/list", entry.Method, "viewers should receive the traffic captured before attaching")
+	assert.Equal(t, "→", entry.Direction)
+
+	debug.LogMCPIncoming(`{"jsonrpc":"2.0","id":1,"result":{"tools":[]}}`, nil)
+	entry, err = attachment.Next()
+	require.NoError(t, err)
+	assert.Equal(t, debug.MCPMessageResponse, entry.MessageType)
+	assert.Equal(t, "←", entry.Direction)
+
+	require.NoError(t, monitor.Close())
+	_, err = attachment.Next()
+	assert.Error(t, err, "viewers should see the proxy go away")
+}
+
+func TestDefaultMonitorPath(t *testing.T) {
+	t.Setenv("TMPDIR", socketDir(t))
+
+	path, err := DefaultMonitorPath()
+	require.NoError(t, err)
+	info, err := os.Stat(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	require.NoError(t, os.Chmod(filepath.Dir(path), 0755))
+	_, err = DefaultMonitorPath()
+	assert.Error(t, err, "a directory others can enter must not hold the socket")
+}
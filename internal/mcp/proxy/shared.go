@@ -0,0 +1,480 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
+)
+
+var (
+	// ErrUpstreamClosed is returned to shared sessions when the upstream connection ends
+	ErrUpstreamClosed = errors.New("shared upstream connection closed")
+
+	// ErrSessionBehind ends a shared session that stopped reading while a
+	// response or server request for it was waiting to be queued
+	ErrSessionBehind = errors.New("shared session fell too far behind and was closed")
+)
+
+// Shared multiplexes many client sessions onto one upstream connection, so a
+// single server process serves every client. Request IDs are rewritten so
+// sessions cannot collide, the first initialize is forwarded and its result
+// replayed to later sessions, and server notifications are broadcast except
+// for progress, which goes to the session that asked for it.
+type Shared struct {
+	upstream Upstream
+
+	mu           sync.Mutex
+	conn         officialMCP.Connection // nil until the first session connects
+	connDone     chan struct{}          // Closed when conn ends
+	sessions     map[*sharedConn]struct{}
+	pending      map[string]sharedRequest // By upstream request ID
+	progress     map[string]*sharedConn   // Progress token owners
+	nextID       int64
+	initResult   json.RawMessage
+	initializing chan struct{} // Closed when the forwarded initialize is answered
+	initialized  bool          // notifications/initialized has been forwarded
+	lastActive   *sharedConn   // Receives server-to-client requests
+}
+
+// sharedRequest remembers which session sent a rewritten request
+type sharedRequest struct {
+	session    *sharedConn
+	id         jsonrpc.ID // The session's own ID
+	upstreamID int64
+	initialize bool
+}
+
+// NewShared creates a multiplexer over connections made by upstream
+func NewShared(upstream Upstream) *Shared {
+	return &Shared{
+		upstream: upstream,
+		sessions: make(map[*sharedConn]struct{}),
+		pending:  make(map[string]sharedRequest),
+		progress: make(map[string]*sharedConn),
+	}
+}
+
+// Upstream returns transports whose connections share the upstream connection
+func (s *Shared) Upstream() Upstream {
+	return func() (officialMCP.Transport, error) {
+		return &sharedTransport{shared: s}, nil
+	}
+}
+
+// Close closes the upstream connection, ending every session
+func (s *Shared) Close() error {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	return conn.Close()
+}
+
+// sharedTransport connects one session to the shared upstream
+type sharedTransport struct {
+	shared *Shared
+}
+
+// Connect implements officialMCP.Transport
+func (t *sharedTransport) Connect(ctx context.Context) (officialMCP.Connection, error) {
+	return t.shared.open(ctx)
+}
+
+// open adds a session, connecting upstream if this is the first
+func (s *Shared) open(ctx context.Context) (*sharedConn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		transport, err := s.upstream()
+		if err != nil {
+			return nil, err
+		}
+		conn, err := transport.Connect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.conn = conn
+		s.connDone = make(chan struct{})
+		go s.readLoop(conn, s.connDone)
+		debug.Info("Proxy: Shared upstream connected")
+	}
+
+	session := &sharedConn{
+		shared:       s,
+		inbox:        make(chan jsonrpc.Message, 256),
+		done:         make(chan struct{}),
+		behind:       make(chan struct{}),
+		upstreamDone: s.connDone,
+	}
+	s.sessions[session] = struct{}{}
+	return session, nil
+}
+
+// readLoop routes upstream messages to sessions until the connection ends
+func (s *Shared) readLoop(conn officialMCP.Connection, done chan struct{}) {
+	for {
+		msg, err := conn.Read(context.Background())
+		if err != nil {
+			debug.Info("Proxy: Shared upstream ended", debug.F("error", err))
+			s.reset(conn, done)
+			return
+		}
+		s.dispatch(msg)
+	}
+}
+
+// reset forgets an ended upstream connection so the next session reconnects
+func (s *Shared) reset(conn officialMCP.Connection, done chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	close(done)
+	if s.conn != conn {
+		return
+	}
+	s.conn = nil
+	s.pending = make(map[string]sharedRequest)
+	s.progress = make(map[string]*sharedConn)
+	s.initResult = nil
+	s.initialized = false
+	if s.initializing != nil {
+		close(s.initializing)
+		s.initializing = nil
+	}
+}
+
+// dispatch delivers an upstream message to the sessions it concerns
+func (s *Shared) dispatch(msg jsonrpc.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch m := msg.(type) {
+	case *jsonrpc.Response:
+		req, ok := s.pending[idKey(m.ID)]
+		if !ok {
+			debug.Warn("Proxy: Dropping response to unknown request", debug.F("id", m.ID.Raw()))
+			return
+		}
+		delete(s.pending, idKey(m.ID))
+		if req.initialize {
+			if m.Error == nil {
+				s.initResult = m.Result
+			}
+			if s.initializing != nil {
+				close(s.initializing)
+				s.initializing = nil
+			}
+		}
+		if req.session != nil {
+			s.deliverOrEnd(req.session, &jsonrpc.Response{ID: req.id, Result: m.Result, Error: m.Error})
+		}
+
+	case *jsonrpc.Request:
+		switch {
+		case m.ID.IsValid():
+			// Server-to-client requests go to the session that spoke last
+			if s.lastActive == nil {
+				debug.Warn("Proxy: Dropping server request, no session to answer it", debug.F("method", m.Method))
+				return
+			}
+			s.deliverOrEnd(s.lastActive, m)
+		case m.Method == "notifications/progress":
+			if owner := s.progress[progressToken(m.Params)]; owner != nil {
+				owner.deliver(m)
+			}
+		default:
+			for session := range s.sessions {
+				session.deliver(m)
+			}
+		}
+	}
+}
+
+// deliverOrEnd queues a message that must not be lost, a response or a
+// server request, ending the session instead if it is not keeping up: its
+// client would otherwise wait forever for the message. Called with s.mu held.
+func (s *Shared) deliverOrEnd(session *sharedConn, msg jsonrpc.Message) {
+	if session.deliver(msg) {
+		return
+	}
+	debug.Warn("Proxy: Closing a session that is not keeping up")
+	session.behindOnce.Do(func() { close(session.behind) })
+	s.detachLocked(session)
+}
+
+// write forwards a session's message upstream, rewriting request IDs
+func (s *Shared) write(ctx context.Context, session *sharedConn, msg jsonrpc.Message) error {
+	s.mu.Lock()
+	s.lastActive = session
+	s.mu.Unlock()
+
+	req, ok := msg.(*jsonrpc.Request)
+	if !ok {
+		// Answers to server requests keep the server's ID
+		return s.forward(ctx, session, msg)
+	}
+
+	if !req.ID.IsValid() {
+		switch req.Method {
+		case "notifications/initialized":
+			s.mu.Lock()
+			already := s.initialized
+			s.initialized = true
+			s.mu.Unlock()
+			if already {
+				return nil
+			}
+		case "notifications/cancelled":
+			req = s.rewriteCancel(session, req)
+		}
+		return s.forward(ctx, session, req)
+	}
+
+	initialize := req.Method == "initialize"
+	if initialize {
+		if result, err := s.awaitInitialize(ctx); err != nil {
+			return err
+		} else if result != nil {
+			// The upstream is already initialized; answer from the first handshake
+			s.mu.Lock()
+			s.deliverOrEnd(session, &jsonrpc.Response{ID: req.ID, Result: result})
+			s.mu.Unlock()
+			return nil
+		}
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	upstreamID, err := transports.MakeID(s.nextID)
+	if err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	s.pending[idKey(upstreamID)] = sharedRequest{session: session, id: req.ID, upstreamID: s.nextID, initialize: initialize}
+	if token := progressToken(req.Params); token != "" {
+		s.progress[token] = session
+	}
+	s.mu.Unlock()
+
+	err = s.forward(ctx, session, &jsonrpc.Request{ID: upstreamID, Method: req.Method, Params: req.Params})
+	if err != nil {
+		s.mu.Lock()
+		delete(s.pending, idKey(upstreamID))
+		if initialize && s.initializing != nil {
+			// Let a waiting session try its own initialize
+			close(s.initializing)
+			s.initializing = nil
+		}
+		s.mu.Unlock()
+	}
+	return err
+}
+
+// awaitInitialize returns the cached initialize result, or nil if the caller
+// should forward its own initialize, waiting while another one is in flight
+func (s *Shared) awaitInitialize(ctx context.Context) (json.RawMessage, error) {
+	for {
+		s.mu.Lock()
+		if s.initResult != nil {
+			result := s.initResult
+			s.mu.Unlock()
+			return result, nil
+		}
+		wait := s.initializing
+		if wait == nil {
+			s.initializing = make(chan struct{})
+			s.mu.Unlock()
+			return nil, nil
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// forward writes a message on the current upstream connection
+func (s *Shared) forward(ctx context.Context, session *sharedConn, msg jsonrpc.Message) error {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+	if conn == nil || session.upstreamGone() {
+		return ErrUpstreamClosed
+	}
+	return conn.Write(ctx, msg)
+}
+
+// rewriteCancel points a cancellation at the rewritten ID of the session's request
+func (s *Shared) rewriteCancel(session *sharedConn, req *jsonrpc.Request) *jsonrpc.Request {
+	var params map[string]interface{}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return req
+	}
+	original, err := transports.MakeID(params["requestId"])
+	if err != nil {
+		return req
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, pending := range s.pending {
+		if pending.session == session && idKey(pending.id) == idKey(original) {
+			params["requestId"] = pending.upstreamID
+			if data, err := json.Marshal(params); err == nil {
+				return &jsonrpc.Request{Method: req.Method, Params: data}
+			}
+			break
+		}
+	}
+	return req
+}
+
+// detach removes a session and forgets its requests
+func (s *Shared) detach(session *sharedConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.detachLocked(session)
+}
+
+// detachLocked is detach with s.mu held
+func (s *Shared) detachLocked(session *sharedConn) {
+	delete(s.sessions, session)
+	for key, pending := range s.pending {
+		if pending.session != session {
+			continue
+		}
+		if pending.initialize {
+			// Other sessions are waiting on this handshake's result
+			pending.session = nil
+			s.pending[key] = pending
+		} else {
+			delete(s.pending, key)
+		}
+	}
+	for token, owner := range s.progress {
+		if owner == session {
+			delete(s.progress, token)
+		}
+	}
+	if s.lastActive == session {
+		s.lastActive = nil
+	}
+}
+
+// progressToken extracts _meta.progressToken from request or notification params
+func progressToken(params json.RawMessage) string {
+	if len(params) == 0 {
+		return ""
+	}
+	var p struct {
+		ProgressToken interface{} `json:"progressToken"`
+		Meta          struct {
+			ProgressToken interface{} `json:"progressToken"`
+		} `json:"_meta"`
+	}
+	if json.Unmarshal(params, &p) != nil {
+		return ""
+	}
+	token := p.Meta.ProgressToken
+	if token == nil {
+		token = p.ProgressToken
+	}
+	if token == nil {
+		return ""
+	}
+	return fmt.Sprintf("%T:%v", token, token)
+}
+
+// sharedConn is one session's view of the shared upstream
+type sharedConn struct {
+	shared       *Shared
+	inbox        chan jsonrpc.Message
+	done         chan struct{}
+	behind       chan struct{} // Closed when the session is ended for not keeping up
+	upstreamDone chan struct{}
+	closeOnce    sync.Once
+	behindOnce   sync.Once
+}
+
+// deliver queues a message for the session without blocking the upstream
+// reader, reporting whether there was room. Notifications that do not fit
+// are dropped.
+func (c *sharedConn) deliver(msg jsonrpc.Message) bool {
+	select {
+	case c.inbox <- msg:
+		return true
+	default:
+		debug.Warn("Proxy: Dropping message for a session that is not keeping up")
+		return false
+	}
+}
+
+// upstreamGone reports whether the upstream this session joined has ended
+func (c *sharedConn) upstreamGone() bool {
+	select {
+	case <-c.upstreamDone:
+		return true
+	default:
+		return false
+	}
+}
+
+// Read implements officialMCP.Connection
+func (c *sharedConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case msg := <-c.inbox:
+		return msg, nil
+	case <-c.done:
+		return nil, io.EOF
+	case <-c.behind:
+		return nil, ErrSessionBehind
+	case <-c.upstreamDone:
+		return nil, ErrUpstreamClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Write implements officialMCP.Connection
+func (c *sharedConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	select {
+	case <-c.done:
+		return io.EOF
+	case <-c.behind:
+		return ErrSessionBehind
+	default:
+	}
+	return c.shared.write(ctx, c, msg)
+}
+
+// Close implements officialMCP.Connection; the upstream stays open for other sessions
+func (c *sharedConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.shared.detach(c)
+	})
+	return nil
+}
+
+// SessionID implements officialMCP.Connection
+func (c *sharedConn) SessionID() string {
+	return ""
+}
+
+// idKey makes a map key for a JSON-RPC ID
+func idKey(id jsonrpc.ID) string {
+	return fmt.Sprintf("%T:%v", id.Raw(), id.Raw())
+}
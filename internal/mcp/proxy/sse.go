@@ -0,0 +1,117 @@
+package proxy
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/streamable"
+)
+
+// SSEHandler serves the proxy over the legacy HTTP+SSE transport (protocol
+// version 2024-11-05). A GET opens a session's event stream and announces
+// the endpoint that the client POSTs its messages to.
+type SSEHandler struct {
+	// AllowedOrigins are the browser origins served besides loopback ones
+	AllowedOrigins []string
+
+	proxy  *Proxy
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	sessions map[string]*officialMCP.SSEServerTransport
+}
+
+// SSEHandler returns a legacy SSE handler that relays to the upstream server
+func (p *Proxy) SSEHandler() *SSEHandler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &SSEHandler{
+		proxy:    p,
+		ctx:      ctx,
+		cancel:   cancel,
+		sessions: make(map[string]*officialMCP.SSEServerTransport),
+	}
+}
+
+// Close ends every session's event stream and upstream connection
+func (h *SSEHandler) Close() error {
+	h.cancel()
+	return nil
+}
+
+// ServeHTTP implements http.Handler
+func (h *SSEHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !streamable.CheckOrigin(w, r, h.AllowedOrigins) {
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, streamable.MaxBodySize)
+		sessionID := r.URL.Query().Get("sessionid")
+		h.mu.Lock()
+		transport := h.sessions[sessionID]
+		h.mu.Unlock()
+		if transport == nil {
+			http.Error(w, "unknown session", http.StatusNotFound)
+			return
+		}
+		transport.ServeHTTP(w, r)
+	case http.MethodGet:
+		h.serveStream(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// serveStream relays one session until the client or the upstream goes away
+func (h *SSEHandler) serveStream(w http.ResponseWriter, r *http.Request) {
+	idBytes := make([]byte, 16)
+	rand.Read(idBytes)
+	sessionID := hex.EncodeToString(idBytes)
+
+	endpoint, err := r.URL.Parse("?sessionid=" + sessionID)
+	if err != nil {
+		http.Error(w, "failed to create session endpoint", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	transport := officialMCP.NewSSEServerTransport(endpoint.RequestURI(), w)
+	h.mu.Lock()
+	h.sessions[sessionID] = transport
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		delete(h.sessions, sessionID)
+		h.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-h.ctx.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	downstream, err := transport.Connect(ctx)
+	if err != nil {
+		return
+	}
+	defer downstream.Close()
+
+	if err := h.proxy.ServeConnection(ctx, downstream); err != nil {
+		debug.Info("Proxy: SSE session ended", debug.F("session", sessionID), debug.F("error", err))
+	}
+}
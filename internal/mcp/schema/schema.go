@@ -0,0 +1,494 @@
+// Package schema works with the JSON Schemas servers give for tool inputs. It
+// coerces command-line values to the types a schema asks for and checks
+// arguments against a schema, reporting every violation rather than the first.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Violation is a place where a value does not match its schema
+type Violation struct {
+	Pointer string // JSON pointer to the offending value, "" for the whole value
+	Message string
+}
+
+func (v Violation) String() string {
+	pointer := v.Pointer
+	if pointer == "" {
+		pointer = "(root)"
+	}
+	return pointer + ": " + v.Message
+}
+
+// Pointer returns the JSON pointer of a path of object keys
+func Pointer(path []string) string {
+	var b strings.Builder
+	for _, key := range path {
+		b.WriteByte('/')
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(key))
+	}
+	return b.String()
+}
+
+// Property returns the schema of the value at a path of object keys, or nil
+// when the schema does not describe it
+func Property(schema map[string]interface{}, path []string) map[string]interface{} {
+	for _, key := range path {
+		if schema == nil {
+			return nil
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		if property, ok := properties[key].(map[string]interface{}); ok {
+			schema = property
+			continue
+		}
+		// Keys not listed are described by additionalProperties, if anything
+		schema, _ = schema["additionalProperties"].(map[string]interface{})
+	}
+	return schema
+}
+
+// Types returns the types a schema allows, in the order they were given
+func Types(schema map[string]interface{}) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		var types []string
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// Coerce converts a command-line value to the type its schema asks for.
+// Numbers, booleans and null are parsed, objects are read as JSON, and arrays
+// are read as JSON or as a comma-separated list of items. When several types
+// are allowed, a string is tried last.
+func Coerce(schema map[string]interface{}, raw string) (interface{}, error) {
+	types := Types(schema)
+	if len(types) == 0 {
+		// Without a type, the alternatives may still say what is expected
+		for _, keyword := range []string{"anyOf", "oneOf"} {
+			alternatives, _ := schema[keyword].([]interface{})
+			for _, alternative := range alternatives {
+				if alternative, ok := alternative.(map[string]interface{}); ok && len(Types(alternative)) > 0 {
+					if value, err := Coerce(alternative, raw); err == nil {
+						return value, nil
+					}
+				}
+			}
+		}
+		return raw, nil
+	}
+
+	ordered := make([]string, 0, len(types))
+	for _, t := range types {
+		if t != "string" {
+			ordered = append(ordered, t)
+		}
+	}
+	if len(ordered) < len(types) {
+		ordered = append(ordered, "string")
+	}
+
+	for _, t := range ordered {
+		if value, ok := coerceTo(schema, t, raw); ok {
+			return value, nil
+		}
+	}
+	return nil, fmt.Errorf("expected %s, got %q", strings.Join(types, " or "), raw)
+}
+
+// coerceTo converts raw to a single type
+func coerceTo(schema map[string]interface{}, t, raw string) (interface{}, bool) {
+	trimmed := strings.TrimSpace(raw)
+	switch t {
+	case "string":
+		return raw, true
+	case "integer":
+		if n, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+			return n, true
+		}
+		// Accept whole numbers written as JSON numbers, e.g. 1e3
+		if f, err := strconv.ParseFloat(trimmed, 64); err == nil && f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return f, true
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(trimmed, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return f, true
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(trimmed); err == nil {
+			return b, true
+		}
+	case "null":
+		if trimmed == "null" {
+			return nil, true
+		}
+	case "object":
+		var object map[string]interface{}
+		if err := json.Unmarshal([]byte(trimmed), &object); err == nil && object != nil {
+			return object, true
+		}
+	case "array":
+		if strings.HasPrefix(trimmed, "[") {
+			var array []interface{}
+			if err := json.Unmarshal([]byte(trimmed), &array); err == nil {
+				return array, true
+			}
+			return nil, false
+		}
+		array := []interface{}{}
+		if trimmed == "" {
+			return array, true
+		}
+		items, _ := schema["items"].(map[string]interface{})
+		for _, part := range strings.Split(raw, ",") {
+			item, err := Coerce(items, part)
+			if err != nil {
+				return nil, false
+			}
+			array = append(array, item)
+		}
+		return array, true
+	}
+	return nil, false
+}
+
+// Validate checks a value against a schema and returns every violation, in
+// the order of the value's keys. It covers the keywords tool schemas use:
+// type, enum, const, required, properties, additionalProperties, items, the
+// length, size and range limits, pattern, and allOf/anyOf/oneOf. References
+// are not followed.
+func Validate(schema map[string]interface{}, value interface{}) []Violation {
+	var v validator
+	v.validate(schema, value, nil)
+	return v.violations
+}
+
+type validator struct {
+	violations []Violation
+}
+
+func (v *validator) report(path []string, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{Pointer: Pointer(path), Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) validate(schema map[string]interface{}, value interface{}, path []string) {
+	if schema == nil {
+		return
+	}
+
+	if types := Types(schema); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if hasType(value, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.report(path, "expected %s, got %s", strings.Join(types, " or "), typeOf(value))
+			return // The other keywords would only repeat the mismatch
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			if equal(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.report(path, "must be one of %s", formatValues(enum))
+		}
+	}
+	if constant, ok := schema["const"]; ok && !equal(constant, value) {
+		v.report(path, "must be %s", formatValue(constant))
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		v.validateObject(schema, value, path)
+	case []interface{}:
+		v.validateArray(schema, value, path)
+	case string:
+		v.validateString(schema, value, path)
+	default:
+		if n, ok := number(value); ok {
+			v.validateNumber(schema, n, path)
+		}
+	}
+
+	v.validateCombinations(schema, value, path)
+}
+
+func (v *validator) validateObject(schema, object map[string]interface{}, path []string) {
+	required, _ := schema["required"].([]interface{})
+	for _, name := range required {
+		if name, ok := name.(string); ok {
+			if _, present := object[name]; !present {
+				v.report(append(path, name), "is required")
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child := append(append([]string(nil), path...), key)
+		if property, ok := properties[key].(map[string]interface{}); ok {
+			v.validate(property, object[key], child)
+			continue
+		}
+		if _, listed := properties[key]; listed {
+			continue // e.g. a boolean schema
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.report(child, "is not an allowed property")
+			}
+		case map[string]interface{}:
+			v.validate(additional, object[key], child)
+		}
+	}
+
+	if limit, ok := number(schema["minProperties"]); ok && float64(len(object)) < limit {
+		v.report(path, "must have at least %s properties", formatNumber(limit))
+	}
+	if limit, ok := number(schema["maxProperties"]); ok && float64(len(object)) > limit {
+		v.report(path, "must have at most %s properties", formatNumber(limit))
+	}
+}
+
+func (v *validator) validateArray(schema map[string]interface{}, array []interface{}, path []string) {
+	if limit, ok := number(schema["minItems"]); ok && float64(len(array)) < limit {
+		v.report(path, "must have at least %s items", formatNumber(limit))
+	}
+	if limit, ok := number(schema["maxItems"]); ok && float64(len(array)) > limit {
+		v.report(path, "must have at most %s items", formatNumber(limit))
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		for i := range array {
+			for j := 0; j < i; j++ {
+				if equal(array[i], array[j]) {
+					v.report(append(path, strconv.Itoa(i)), "duplicates item %d", j)
+					break
+				}
+			}
+		}
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		for i, item := range array {
+			v.validate(items, item, append(append([]string(nil), path...), strconv.Itoa(i)))
+		}
+	}
+}
+
+func (v *validator) validateString(schema map[string]interface{}, s string, path []string) {
+	length := float64(utf8.RuneCountInString(s))
+	if limit, ok := number(schema["minLength"]); ok && length < limit {
+		v.report(path, "must be at least %s characters", formatNumber(limit))
+	}
+	if limit, ok := number(schema["maxLength"]); ok && length > limit {
+		v.report(path, "must be at most %s characters", formatNumber(limit))
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		// A pattern Go cannot compile is the server's problem, not the value's
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(s) {
+			v.report(path, "must match %q", pattern)
+		}
+	}
+}
+
+func (v *validator) validateNumber(schema map[string]interface{}, n float64, path []string) {
+	if limit, ok := number(schema["minimum"]); ok && n < limit {
+		v.report(path, "must be at least %s", formatNumber(limit))
+	}
+	if limit, ok := number(schema["maximum"]); ok && n > limit {
+		v.report(path, "must be at most %s", formatNumber(limit))
+	}
+	if limit, ok := number(schema["exclusiveMinimum"]); ok && n <= limit {
+		v.report(path, "must be greater than %s", formatNumber(limit))
+	}
+	if limit, ok := number(schema["exclusiveMaximum"]); ok && n >= limit {
+		v.report(path, "must be less than %s", formatNumber(limit))
+	}
+	if step, ok := number(schema["multipleOf"]); ok && step > 0 {
+		if q := n / step; math.Abs(q-math.Round(q)) > 1e-9 {
+			v.report(path, "must be a multiple of %s", formatNumber(step))
+		}
+	}
+}
+
+func (v *validator) validateCombinations(schema map[string]interface{}, value interface{}, path []string) {
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			if sub, ok := sub.(map[string]interface{}); ok {
+				v.validate(sub, value, path)
+			}
+		}
+	}
+
+	matches := func(alternatives []interface{}) int {
+		n := 0
+		for _, sub := range alternatives {
+			if sub, ok := sub.(map[string]interface{}); ok && len(Validate(sub, value)) == 0 {
+				n++
+			}
+		}
+		return n
+	}
+	if some, ok := schema["anyOf"].([]interface{}); ok && matches(some) == 0 {
+		v.report(path, "must match at least one of %d alternatives", len(some))
+	}
+	if one, ok := schema["oneOf"].([]interface{}); ok {
+		if n := matches(one); n != 1 {
+			v.report(path, "must match exactly one of %d alternatives, matches %d", len(one), n)
+		}
+	}
+}
+
+// hasType reports whether value is of a JSON Schema type
+func hasType(value interface{}, t string) bool {
+	switch t {
+	case "null":
+		return value == nil
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "number":
+		_, ok := number(value)
+		return ok
+	case "integer":
+		n, ok := number(value)
+		return ok && n == math.Trunc(n)
+	}
+	return true // Unknown types are not ours to reject
+}
+
+// typeOf names the JSON type of a value
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	if n, ok := number(value); ok {
+		if n == math.Trunc(n) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// number returns a value as a float64 if it is a number
+func number(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// equal compares JSON values, treating numbers of different Go types alike
+func equal(a, b interface{}) bool {
+	if x, ok := number(a); ok {
+		y, ok := number(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(normalize(a), normalize(b))
+}
+
+// normalize turns the numbers in a value into float64s
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = normalize(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = normalize(item)
+		}
+		return out
+	}
+	if n, ok := number(value); ok {
+		return n
+	}
+	return value
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+func formatValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func formatValues(values []interface{}) string {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = formatValue(value)
+	}
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,119 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, s string) map[string]interface{} {
+	t.Helper()
+	var v map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &v))
+	return v
+}
+
+func TestCoerce(t *testing.T) {
+	tests := []struct {
+		schema string
+		raw    string
+		want   interface{}
+	}{
+		{`{"type": "integer"}`, "42", int64(42)},
+		{`{"type": "number"}`, "2.5", 2.5},
+		{`{"type": "boolean"}`, "false", false},
+		{`{"type": "string"}`, "123", "123"},
+		{`{"type": ["string", "integer"]}`, "7", int64(7)},
+		{`{"type": ["string", "integer"]}`, "seven", "seven"},
+		{`{"type": ["null", "string"]}`, "null", nil},
+		{`{"type": "array", "items": {"type": "number"}}`, "1,2.5", []interface{}{float64(1), 2.5}},
+		{`{"type": "array"}`, "", []interface{}{}},
+		{`{"type": "object"}`, `{"a": 1}`, map[string]interface{}{"a": float64(1)}},
+		{`{"anyOf": [{"type": "integer"}, {"type": "string"}]}`, "3", int64(3)},
+		{`{}`, "as is", "as is"},
+	}
+	for _, tt := range tests {
+		got, err := Coerce(parse(t, tt.schema), tt.raw)
+		require.NoError(t, err, "%s %q", tt.schema, tt.raw)
+		assert.Equal(t, tt.want, got, "%s %q", tt.schema, tt.raw)
+	}
+
+	_, err := Coerce(parse(t, `{"type": "array", "items": {"type": "integer"}}`), "1,x")
+	assert.EqualError(t, err, `expected array, got "1,x"`)
+	_, err = Coerce(parse(t, `{"type": "object"}`), "[]")
+	assert.Error(t, err)
+}
+
+func TestProperty(t *testing.T) {
+	s := parse(t, `{
+		"properties": {"filter": {"properties": {"status": {"type": "string"}}}},
+		"additionalProperties": {"type": "integer"}
+	}`)
+	assert.Equal(t, "string", Property(s, []string{"filter", "status"})["type"])
+	assert.Equal(t, "integer", Property(s, []string{"other"})["type"], "Unlisted keys use additionalProperties")
+	assert.Nil(t, Property(s, []string{"filter", "other"}))
+}
+
+func TestValidate(t *testing.T) {
+	s := parse(t, `{
+		"type": "object",
+		"required": ["query", "filter"],
+		"additionalProperties": false,
+		"properties": {
+			"query": {"type": "string", "minLength": 2, "pattern": "^[a-z]+$"},
+			"limit": {"type": "integer", "minimum": 1, "maximum": 100},
+			"tags": {"type": "array", "maxItems": 2, "uniqueItems": true, "items": {"type": "string"}},
+			"filter": {
+				"type": "object",
+				"properties": {"status": {"enum": ["open", "closed"]}, "a/b": {"const": 1}}
+			}
+		}
+	}`)
+
+	violations := Validate(s, map[string]interface{}{
+		"query":  "A",
+		"limit":  2.5,
+		"tags":   []interface{}{"x", 1, "x"},
+		"filter": map[string]interface{}{"status": "stale", "a/b": 2},
+		"extra":  true,
+	})
+	var got []string
+	for _, v := range violations {
+		got = append(got, v.String())
+	}
+	assert.Equal(t, []string{
+		"/extra: is not an allowed property",
+		`/filter/a~1b: must be 1`,
+		`/filter/status: must be one of "open", "closed"`,
+		"/limit: expected integer, got number",
+		"/query: must be at least 2 characters",
+		`/query: must match "^[a-z]+$"`,
+		"/tags: must have at most 2 items",
+		"/tags/2: duplicates item 0",
+		"/tags/1: expected string, got integer",
+	}, got)
+
+	violations = Validate(s, map[string]interface{}{"limit": int64(0)})
+	got = nil
+	for _, v := range violations {
+		got = append(got, v.String())
+	}
+	assert.Equal(t, []string{"/query: is required", "/filter: is required", "/limit: must be at least 1"}, got)
+
+	assert.Equal(t, "(root): expected object, got array", Validate(s, []interface{}{})[0].String())
+	assert.Empty(t, Validate(s, map[string]interface{}{"query": "ok", "filter": map[string]interface{}{}}))
+}
+
+func TestValidateCombinations(t *testing.T) {
+	s := parse(t, `{"oneOf": [{"type": "string"}, {"type": "integer"}, {"type": "number"}]}`)
+	assert.Empty(t, Validate(s, "x"))
+	require.Len(t, Validate(s, float64(3)), 1, "An integer is also a number")
+	assert.Equal(t, "must match exactly one of 3 alternatives, matches 2", Validate(s, float64(3))[0].Message)
+
+	s = parse(t, `{"anyOf": [{"type": "string"}, {"type": "integer"}], "allOf": [{"minimum": 5}]}`)
+	assert.Empty(t, Validate(s, float64(6)))
+	assert.Len(t, Validate(s, float64(4)), 1)
+	assert.Len(t, Validate(s, true), 1)
+}
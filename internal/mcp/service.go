@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
@@ -17,9 +19,22 @@ import (
 	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
 )
 
-// Helper functions for MCP logging
+// Helper functions for MCP logging. Messages go to the service's event log
+// and to the global MCP log shown by the debug screens.
 
-func logMCPRequest(method string, params interface{}, id interface{}) {
+func (s *service) logMCPOutgoing(msg map[string]interface{}) {
+	msgJSON, _ := json.Marshal(msg)
+	s.eventLog().LogOutgoing(string(msgJSON), nil)
+	debug.LogMCPOutgoing(string(msgJSON), nil)
+}
+
+func (s *service) logMCPIncoming(msg map[string]interface{}) {
+	msgJSON, _ := json.Marshal(msg)
+	s.eventLog().LogIncoming(string(msgJSON), nil)
+	debug.LogMCPIncoming(string(msgJSON), nil)
+}
+
+func (s *service) logMCPRequest(method string, params interface{}, id interface{}) {
 	msg := map[string]interface{}{
 		"jsonrpc": "2.0",
 		"method":  method,
@@ -28,61 +43,81 @@ func logMCPRequest(method string, params interface{}, id interface{}) {
 	if id != nil {
 		msg["id"] = id
 	}
-	msgJSON, _ := json.Marshal(msg)
-	debug.LogMCPOutgoing(string(msgJSON), nil)
+	s.logMCPOutgoing(msg)
 }
 
-func logMCPResponse(result interface{}, id interface{}) {
-	msg := map[string]interface{}{
+func (s *service) logMCPResponse(result interface{}, id interface{}) {
+	s.logMCPIncoming(map[string]interface{}{
 		"jsonrpc": "2.0",
 		"id":      id,
 		"result":  result,
-	}
-	msgJSON, _ := json.Marshal(msg)
-	debug.LogMCPIncoming(string(msgJSON), nil)
+	})
 }
 
-func logMCPError(code int, message string, id interface{}) {
-	msg := map[string]interface{}{
+func (s *service) logMCPError(code int, message string, id interface{}) {
+	s.logMCPIncoming(map[string]interface{}{
 		"jsonrpc": "2.0",
 		"id":      id,
 		"error": map[string]interface{}{
 			"code":    code,
 			"message": message,
 		},
-	}
-	msgJSON, _ := json.Marshal(msg)
-	debug.LogMCPIncoming(string(msgJSON), nil)
+	})
 }
 
-func logMCPNotification(method string, params interface{}) {
-	msg := map[string]interface{}{
+func (s *service) logMCPNotification(method string, params interface{}) {
+	s.logMCPIncoming(map[string]interface{}{
 		"jsonrpc": "2.0",
 		"method":  method,
 		"params":  params,
-	}
-	msgJSON, _ := json.Marshal(msg)
-	debug.LogMCPIncoming(string(msgJSON), nil)
+	})
 }
 
 // service implements the Service interface using the official MCP Go SDK
 type service struct {
 	info             *ServerInfo
-	requestID        int
+	requestID        atomic.Int64
 	mu               sync.Mutex
 	debugMode        bool
 	transportFactory transports.TransportFactory
 	sessionManager   *session.Manager
 	errorHandler     *errors.ErrorHandler
-	config           *UnifiedConfig // Add unified configuration
+	config           *UnifiedConfig              // Add unified configuration
+	connection       *configPkg.ConnectionConfig // The current connection, saved with the session
+	resumeSessionID  string                      // Session the next connection resumes, if any
+
+	events   *debug.MCPLogger // Traffic of this service's connections
+	eventsMu sync.Mutex
+
+	initialized atomic.Pointer[officialMCP.InitializeResult] // Latest initialize result, which the SDK keeps private
+
+	// Per-connection status, kept per service so each tab shows its own
+	state  connectionState // Stages of the current connection
+	stream streamState     // Streamable HTTP stream activity
 }
 
-// getNextRequestID returns the next request ID
+// getNextRequestID returns the next request ID. It does not take s.mu since
+// the logging middleware runs while Connect holds it.
 func (s *service) getNextRequestID() int {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.requestID++
-	return s.requestID
+	return int(s.requestID.Add(1))
+}
+
+// eventLogSize is how many messages a service keeps in its event log
+const eventLogSize = 1000
+
+// eventLog returns the service's event log, creating it on first use
+func (s *service) eventLog() *debug.MCPLogger {
+	s.eventsMu.Lock()
+	defer s.eventsMu.Unlock()
+	if s.events == nil {
+		s.events = debug.NewMCPLogger(eventLogSize)
+	}
+	return s.events
+}
+
+// EventLog returns the MCP messages exchanged by this service's connections
+func (s *service) EventLog() *debug.MCPLogger {
+	return s.eventLog()
 }
 
 // SetDebugMode enables or disables debug mode
@@ -101,18 +136,27 @@ func (s *service) SetDebugMode(debug bool) {
 func (s *service) createLoggingMiddleware() officialMCP.Middleware[*officialMCP.ClientSession] {
 	return func(next officialMCP.MethodHandler[*officialMCP.ClientSession]) officialMCP.MethodHandler[*officialMCP.ClientSession] {
 		return func(ctx context.Context, session *officialMCP.ClientSession, method string, params officialMCP.Params) (officialMCP.Result, error) {
+			// Notifications have no ID and no response
+			if strings.HasPrefix(method, "notifications/") {
+				s.logMCPRequest(method, params, nil)
+				return next(ctx, session, method, params)
+			}
+
 			// Log outgoing request
 			reqID := s.getNextRequestID()
-			logMCPRequest(method, params, reqID)
+			s.logMCPRequest(method, params, reqID)
 
 			// Call the next handler
 			result, err := next(ctx, session, method, params)
 
 			// Log response or error
 			if err != nil {
-				logMCPError(-32603, err.Error(), reqID)
+				s.logMCPError(-32603, err.Error(), reqID)
 			} else {
-				logMCPResponse(result, reqID)
+				s.logMCPResponse(result, reqID)
+				if res, ok := result.(*officialMCP.InitializeResult); ok {
+					s.initialized.Store(res)
+				}
 			}
 
 			return result, err
@@ -120,6 +164,19 @@ func (s *service) createLoggingMiddleware() officialMCP.Middleware[*officialMCP.
 	}
 }
 
+// createNotificationMiddleware creates middleware logging the notifications
+// the server sends, which make up the Events tab
+func (s *service) createNotificationMiddleware() officialMCP.Middleware[*officialMCP.ClientSession] {
+	return func(next officialMCP.MethodHandler[*officialMCP.ClientSession]) officialMCP.MethodHandler[*officialMCP.ClientSession] {
+		return func(ctx context.Context, session *officialMCP.ClientSession, method string, params officialMCP.Params) (officialMCP.Result, error) {
+			if strings.HasPrefix(method, "notifications/") {
+				s.logMCPNotification(method, params)
+			}
+			return next(ctx, session, method, params)
+		}
+	}
+}
+
 // NewServiceWithConfig creates a new MCP service with unified configuration
 func NewServiceWithConfig(config *UnifiedConfig) Service {
 	if config == nil {
@@ -142,19 +199,7 @@ func (s *service) Connect(ctx context.Context, config *configPkg.ConnectionConfi
 	defer s.mu.Unlock()
 
 	// Initialize session manager if not already done
-	if s.sessionManager == nil {
-		s.sessionManager = session.NewManager()
-
-		// Configure session manager based on unified config
-		if s.config != nil {
-			s.sessionManager.SetDebugEnabled(s.config.Debug.Enabled)
-			s.sessionManager.SetReconnectionPolicy(
-				s.config.Session.MaxReconnectAttempts,
-				s.config.Session.ReconnectDelay,
-			)
-			s.sessionManager.SetHealthCheckInterval(s.config.Session.HealthCheckInterval)
-		}
-	}
+	s.ensureSessionManager()
 
 	// Initialize error handler if not already done
 	if s.errorHandler == nil {
@@ -165,6 +210,7 @@ func (s *service) Connect(ctx context.Context, config *configPkg.ConnectionConfi
 	if s.sessionManager.IsConnected() {
 		return fmt.Errorf("already connected to MCP server - disconnect first before connecting to a new server")
 	}
+	s.initialized.Store(nil)
 
 	// Create implementation info
 	impl := &officialMCP.Implementation{
@@ -196,10 +242,9 @@ func (s *service) Connect(ctx context.Context, config *configPkg.ConnectionConfi
 		client = officialMCP.NewClient(impl, clientOptions)
 	}
 
-	// Add logging middleware for automatic request/response logging (if not using debug client)
-	if s.debugMode && s.sessionManager.GetEventTracer() == nil {
-		client.AddSendingMiddleware(s.createLoggingMiddleware())
-	}
+	// Log the connection's traffic to the service's event log
+	client.AddSendingMiddleware(s.createLoggingMiddleware())
+	client.AddReceivingMiddleware(s.createNotificationMiddleware())
 
 	// Initialize transport factory if not already done
 	if s.transportFactory == nil {
@@ -208,6 +253,13 @@ func (s *service) Connect(ctx context.Context, config *configPkg.ConnectionConfi
 
 	// Convert to new transport config format
 	transportConfig := transports.FromConnectionConfig(config, s.debugMode, 30*time.Second)
+	transportConfig.StreamObserver = s.stream.record
+	transportConfig.ProcessObserver = s.sessionManager.RecordProcessEvent
+	if s.config != nil {
+		s.config.Transport.STDIO.ApplyTo(transportConfig)
+	}
+	s.state.reset()
+	s.stream.reset()
 
 	// Log the actual connection details
 	switch config.Type {
@@ -216,7 +268,8 @@ func (s *service) Connect(ctx context.Context, config *configPkg.ConnectionConfi
 			debug.F("transport", "stdio"),
 			debug.F("command", config.Command),
 			debug.F("args", config.Args))
-	case configPkg.TransportHTTP, configPkg.TransportSSE:
+	case configPkg.TransportHTTP, configPkg.TransportSSE, configPkg.TransportWebSocket,
+		configPkg.TransportUnix, configPkg.TransportTCP, configPkg.TransportAuto, configPkg.TransportReplay:
 		debug.Info("Connecting to MCP server",
 			debug.F("transport", config.Type),
 			debug.F("url", config.URL))
@@ -226,14 +279,41 @@ func (s *service) Connect(ctx context.Context, config *configPkg.ConnectionConfi
 			debug.F("config", config))
 	}
 
-	// Create transport using factory
-	transport, contextStrategy, err := s.transportFactory.CreateTransport(transportConfig)
-	if err != nil {
-		return fmt.Errorf("failed to create transport: %w", err)
+	// Resolve auto mode to a concrete transport by probing the server
+	if transportConfig.Type == transports.TransportAuto {
+		negotiated, err := transports.NegotiateTransport(ctx, transportConfig, func(step string) {
+			debug.Info("Transport negotiation", debug.F("url", config.URL), debug.F("step", step))
+			s.state.set(StageNegotiating, step, config.URL, nil)
+		})
+		if err != nil {
+			s.state.set(StageFailed, "Transport negotiation failed", config.URL, err)
+			return fmt.Errorf("transport negotiation failed: %w", err)
+		}
+		transportConfig.Type = negotiated
+	}
+
+	// Track the stages of HTTP requests in this service's connection state
+	switch transportConfig.Type {
+	case transports.TransportHTTP, transports.TransportSSE, transports.TransportStreamableHTTP:
+		client := transports.GetHTTPClientForTransport(transportConfig.Type, transportConfig.HTTPClient)
+		transportConfig.HTTPClient = trackHTTPClient(client, &s.state, &s.stream)
+	}
+
+	// The session manager creates a fresh transport for each connection
+	// attempt. Only the first resumes a saved session; a session lost later
+	// is replaced by a new one.
+	factory := s.transportFactory
+	resumeSessionID := s.resumeSessionID
+	s.resumeSessionID = ""
+	dial := func() (officialMCP.Transport, transports.ContextStrategy, error) {
+		attemptConfig := *transportConfig
+		attemptConfig.SessionID = resumeSessionID
+		resumeSessionID = ""
+		return factory.CreateTransport(&attemptConfig)
 	}
 
 	// Use session manager to establish connection
-	err = s.sessionManager.Connect(ctx, client, transport, contextStrategy, transportConfig.Type)
+	err := s.sessionManager.Connect(ctx, client, dial, transportConfig.Type)
 	if err != nil {
 		return fmt.Errorf("failed to connect to MCP server: %w", err)
 	}
@@ -249,19 +329,37 @@ func (s *service) Connect(ctx context.Context, config *configPkg.ConnectionConfi
 	serverInfo := "Connected Server"
 	serverVersion := "Unknown"
 	protocolVersion := "2024-11-05"
+	capabilities := make(map[string]interface{})
+	if res := s.initialized.Load(); res != nil {
+		if res.ServerInfo != nil && res.ServerInfo.Name != "" {
+			serverInfo = res.ServerInfo.Name
+			serverVersion = res.ServerInfo.Version
+		}
+		if res.ProtocolVersion != "" {
+			protocolVersion = res.ProtocolVersion
+		}
+		if res.Capabilities != nil {
+			if data, err := json.Marshal(res.Capabilities); err == nil {
+				json.Unmarshal(data, &capabilities)
+			}
+		}
+	}
 
 	// Try to get more details if available through reflection or other means
 	// For now, we'll use the session ID and other available info
 	sessionID := session.ID()
 
 	// Update server info
+	s.connection = config
 	s.info.Connected = true
 	s.info.Name = serverInfo
 	s.info.Version = serverVersion
 	s.info.ProtocolVersion = protocolVersion
+	s.info.Capabilities = capabilities
+	s.info.Transport = string(transportConfig.Type)
 
 	debug.Info("Successfully connected using official MCP Go SDK",
-		debug.F("transport", config.Type),
+		debug.F("transport", transportConfig.Type),
 		debug.F("url", config.URL),
 		debug.F("sessionID", sessionID),
 		debug.F("serverInfo", serverInfo),
@@ -270,6 +368,29 @@ func (s *service) Connect(ctx context.Context, config *configPkg.ConnectionConfi
 	return nil
 }
 
+// ensureSessionManager creates the session manager if needed (must be called
+// with lock held)
+func (s *service) ensureSessionManager() {
+	if s.sessionManager != nil {
+		return
+	}
+	s.sessionManager = session.NewManager()
+
+	// Configure session manager based on unified config
+	if s.config != nil {
+		s.sessionManager.SetDebugEnabled(s.config.Debug.Enabled)
+		s.sessionManager.SetReconnectionPolicy(
+			s.config.Session.MaxReconnectAttempts,
+			s.config.Session.ReconnectDelay,
+		)
+		s.sessionManager.SetBackoff(
+			s.config.Session.ReconnectBackoff,
+			s.config.Session.MaxReconnectDelay,
+		)
+		s.sessionManager.SetHealthCheckInterval(s.config.Session.HealthCheckInterval)
+	}
+}
+
 // Disconnect closes the connection
 func (s *service) Disconnect() error {
 	s.mu.Lock()
@@ -375,10 +496,10 @@ func (s *service) CallTool(ctx context.Context, req CallToolRequest) (*CallToolR
 	}
 
 	s.mu.Lock()
-	session := s.sessionManager.GetSession()
+	clientSession := s.sessionManager.GetSession()
 	s.mu.Unlock()
 
-	if session == nil {
+	if clientSession == nil {
 		return nil, fmt.Errorf("no active session available")
 	}
 
@@ -388,8 +509,16 @@ func (s *service) CallTool(ctx context.Context, req CallToolRequest) (*CallToolR
 		Arguments: req.Arguments,
 	}
 
-	// Call the tool
-	result, err := session.CallTool(ctx, params)
+	// Call the tool, keeping it in the session history
+	call := session.CallRecord{Tool: req.Name, Arguments: req.Arguments, StartedAt: time.Now()}
+	result, err := clientSession.CallTool(ctx, params)
+	call.Duration = time.Since(call.StartedAt)
+	if err != nil {
+		call.Error = err.Error()
+	} else {
+		call.IsError = result.IsError
+	}
+	s.sessionManager.RecordCall(call)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call tool '%s': %w", req.Name, err)
 	}
@@ -674,7 +803,18 @@ func (s *service) GetConnectionHealth() map[string]interface{} {
 		}
 	}
 
-	return s.sessionManager.GetConnectionHealth()
+	health := s.sessionManager.GetConnectionHealth()
+	if stream := s.stream.get(); stream != nil && health != nil {
+		health["stream"] = map[string]interface{}{
+			"events_received":      stream.EventsReceived,
+			"connection_drops":     stream.ConnectionDrops,
+			"resumption_attempts":  stream.ResumptionAttempts,
+			"resumption_successes": stream.ResumptionSuccesses,
+			"resumption_failures":  stream.ResumptionFailures,
+			"last_event_id":        stream.LastEventID,
+		}
+	}
+	return health
 }
 
 // ConfigureReconnection allows customizing reconnection behavior
@@ -687,6 +827,136 @@ func (s *service) ConfigureReconnection(maxAttempts int, delay time.Duration) {
 	}
 }
 
+// OnConnectionStatus calls fn on every session state transition and
+// reconnection attempt until the returned function is called
+func (s *service) OnConnectionStatus(fn func(session.Status)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ensureSessionManager()
+	return s.sessionManager.Observe(fn)
+}
+
+// SetLogLevel sets the level of the log messages the server sends
+func (s *service) SetLogLevel(ctx context.Context, level string) error {
+	manager, err := s.connectedManager()
+	if err != nil {
+		return err
+	}
+	return manager.SetLogLevel(ctx, level)
+}
+
+// SubscribeResource subscribes to updates of a resource
+func (s *service) SubscribeResource(ctx context.Context, uri string) error {
+	manager, err := s.connectedManager()
+	if err != nil {
+		return err
+	}
+	return manager.Subscribe(ctx, uri)
+}
+
+// UnsubscribeResource ends a resource subscription
+func (s *service) UnsubscribeResource(ctx context.Context, uri string) error {
+	manager, err := s.connectedManager()
+	if err != nil {
+		return err
+	}
+	return manager.Unsubscribe(ctx, uri)
+}
+
+// SessionSnapshot captures the session for saving, or returns nil when
+// there is no connection to save
+func (s *service) SessionSnapshot() *session.Snapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.connection == nil || s.sessionManager == nil || !s.sessionManager.IsConnected() {
+		return nil
+	}
+	snapshot := s.sessionManager.Snapshot()
+	snapshot.Connection = s.connection
+	return snapshot
+}
+
+// SaveSession writes a snapshot to the persistence file of its server. It
+// does nothing unless session persistence is enabled.
+func (s *service) SaveSession(snapshot *session.Snapshot) error {
+	if s.config == nil || !s.config.Session.EnablePersistence || snapshot == nil {
+		return nil
+	}
+	path := session.SnapshotPath(s.config.Session.PersistenceFile)
+	if snapshot.Connection != nil {
+		path = session.ServerSnapshotPath(path, snapshot.Connection)
+	}
+	return snapshot.Save(path)
+}
+
+// PersistenceInterval returns how often the session should be saved, or 0
+// when session persistence is disabled
+func (s *service) PersistenceInterval() time.Duration {
+	if s.config == nil || !s.config.Session.EnablePersistence {
+		return 0
+	}
+	return s.config.Session.PersistenceInterval
+}
+
+// ResumeSession connects as the saved session did and restores its state.
+// A streamable HTTP session still open on the server is continued, in which
+// case resumed is true; otherwise a new session is started with the log
+// level, resource subscriptions, history and events of the saved one.
+func (s *service) ResumeSession(ctx context.Context, snapshot *session.Snapshot) (resumed bool, err error) {
+	if snapshot.SessionID != "" {
+		s.mu.Lock()
+		s.resumeSessionID = snapshot.SessionID
+		s.mu.Unlock()
+
+		err = s.Connect(ctx, snapshot.Connection)
+		if err == nil {
+			resumed = true
+		} else {
+			debug.Warn("Saved session could not be resumed, starting a new one",
+				debug.F("sessionID", snapshot.SessionID),
+				debug.F("error", err))
+		}
+	}
+	if !resumed {
+		if err = s.Connect(ctx, snapshot.Connection); err != nil {
+			return false, err
+		}
+	}
+
+	manager, err := s.connectedManager()
+	if err != nil {
+		return false, err
+	}
+	manager.Restore(snapshot)
+
+	// A resumed session kept its state on the server, but restoring it is
+	// harmless and covers servers that forget it on reinitialize
+	if snapshot.LogLevel != "" {
+		if err := manager.SetLogLevel(ctx, snapshot.LogLevel); err != nil {
+			debug.Warn("Failed to restore log level", debug.F("level", snapshot.LogLevel), debug.F("error", err))
+		}
+	}
+	for _, uri := range snapshot.Subscriptions {
+		if err := manager.Subscribe(ctx, uri); err != nil {
+			debug.Warn("Failed to restore resource subscription", debug.F("uri", uri), debug.F("error", err))
+		}
+	}
+	return resumed, nil
+}
+
+// connectedManager returns the session manager if it has a session
+func (s *service) connectedManager() (*session.Manager, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sessionManager == nil || !s.sessionManager.IsConnected() {
+		return nil, fmt.Errorf("not connected to MCP server")
+	}
+	return s.sessionManager, nil
+}
+
 // ConfigureHealthCheck allows customizing health check frequency
 func (s *service) ConfigureHealthCheck(interval time.Duration) {
 	s.mu.Lock()
@@ -904,10 +1174,16 @@ func (s *service) UpdateConfiguration(configMap map[string]interface{}) error {
 
 // GetConnectionDisplayMessage returns the current connection state display message
 func (s *service) GetConnectionDisplayMessage() string {
-	return GetConnectionDisplayMessage()
+	return s.state.get().DisplayMessage()
 }
 
 // GetServerDiagnosticMessage returns diagnostic guidance for server-side issues
 func (s *service) GetServerDiagnosticMessage() string {
-	return GetServerDiagnosticMessage()
+	return s.state.get().DiagnosticMessage()
+}
+
+// ConnectionState returns how far the service's connection has got, or nil
+// before it has started
+func (s *service) ConnectionState() *ConnectionStateInfo {
+	return s.state.get()
 }
@@ -46,13 +46,24 @@ func (s State) String() string {
 
 // Info holds information about a session
 type Info struct {
-	State          State
-	ConnectedAt    time.Time
-	LastError      *errors.ClassifiedError
-	ReconnectCount int
-	TransportType  transports.TransportType
-	ServerInfo     map[string]interface{}
-	SessionID      string
+	State           State
+	ConnectedAt     time.Time
+	LastError       *errors.ClassifiedError
+	ReconnectCount  int       // Reconnection attempt in progress or last made
+	NextReconnectAt time.Time // When the next reconnection attempt starts, if one is scheduled
+	TransportType   transports.TransportType
+	ServerInfo      map[string]interface{}
+	SessionID       string
+	Process         *ProcessInfo // Server process of a stdio session, nil otherwise
+}
+
+// ProcessInfo describes the server process behind a stdio session
+type ProcessInfo struct {
+	PID      int
+	Running  bool
+	ExitCode int    // Valid once the process has exited; -1 if it was killed by a signal
+	Signal   string // Signal that killed the process, e.g. SIGKILL
+	ExitedAt time.Time
 }
 
 // Manager handles the lifecycle of MCP sessions
@@ -60,14 +71,35 @@ type Manager struct {
 	mu              sync.RWMutex
 	client          *officialMCP.Client
 	session         *officialMCP.ClientSession
-	transport       officialMCP.Transport
+	dial            Dialer
+	requests        *requestConn // The session's connection, for requests the SDK lacks
 	contextStrategy transports.ContextStrategy
 	info            *Info
 	closeFunc       context.CancelFunc
+	reconnectCancel context.CancelFunc // Stops reconnection attempts in progress
+
+	// Session state restored after a reconnection
+	logLevel      string
+	subscriptions map[string]bool
+
+	// Tool calls made in this session, oldest first
+	callsMu sync.Mutex
+	calls   []CallRecord
+
+	// Status observers
+	observersMu  sync.Mutex
+	observers    map[int]func(Status)
+	nextObserver int
+
+	// Server process state, reported by the transport while mu may be held
+	processMu sync.Mutex
+	process   *ProcessInfo
 
 	// Configuration
 	maxReconnectAttempts int
 	reconnectDelay       time.Duration
+	reconnectBackoff     string
+	maxReconnectDelay    time.Duration
 	healthCheckInterval  time.Duration
 
 	// Error handling
@@ -85,8 +117,12 @@ func NewManager() *Manager {
 		info: &Info{
 			State: StateDisconnected,
 		},
+		subscriptions:        make(map[string]bool),
+		observers:            make(map[int]func(Status)),
 		maxReconnectAttempts: 3,
 		reconnectDelay:       2 * time.Second,
+		reconnectBackoff:     BackoffExponential,
+		maxReconnectDelay:    60 * time.Second,
 		healthCheckInterval:  30 * time.Second,
 		errorHandler:         errors.NewErrorHandler(),
 		eventTracer:          mcpDebug.NewEventTracer(1000), // Buffer up to 1000 events
@@ -107,16 +143,23 @@ func (m *Manager) SetDebugEnabled(enabled bool) {
 	debug.Info("Session manager debug mode changed", debug.F("enabled", enabled))
 }
 
-// Connect establishes a new session with proper lifecycle management
-func (m *Manager) Connect(ctx context.Context, client *officialMCP.Client, transport officialMCP.Transport, contextStrategy transports.ContextStrategy, transportType transports.TransportType) error {
+// Connect establishes a new session with proper lifecycle management. dial
+// is called for this connection and again for every reconnection attempt.
+func (m *Manager) Connect(ctx context.Context, client *officialMCP.Client, dial Dialer, transportType transports.TransportType) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	// Ensure we're in a valid state to connect
-	if m.info.State == StateConnecting || m.info.State == StateConnected {
+	if m.info.State == StateConnecting || m.info.State == StateConnected || m.info.State == StateReconnecting {
 		return fmt.Errorf("session is already connecting or connected (state: %s)", m.info.State)
 	}
 
+	transport, contextStrategy, err := dial()
+	if err != nil {
+		return fmt.Errorf("failed to create transport: %w", err)
+	}
+	requests := &requestTransport{Transport: transport}
+
 	// Set up connection context with cancellation
 	connectCtx := contextStrategy.GetConnectionContext(ctx)
 	connectCtx, cancel := context.WithCancel(connectCtx)
@@ -125,11 +168,17 @@ func (m *Manager) Connect(ctx context.Context, client *officialMCP.Client, trans
 	// Update state
 	m.setState(StateConnecting)
 	m.client = client
-	m.transport = transport
+	m.dial = dial
 	m.contextStrategy = contextStrategy
 	m.info.TransportType = transportType
 	m.info.LastError = nil
 	m.info.ReconnectCount = 0
+	m.info.NextReconnectAt = time.Time{}
+	m.logLevel = ""
+	m.subscriptions = make(map[string]bool)
+	m.processMu.Lock()
+	m.process = nil
+	m.processMu.Unlock()
 
 	// Initialize transport debugger for this transport type
 	if m.eventTracer != nil {
@@ -147,7 +196,7 @@ func (m *Manager) Connect(ctx context.Context, client *officialMCP.Client, trans
 	}
 
 	// Attempt connection
-	session, err := client.Connect(connectCtx, transport)
+	session, err := client.Connect(connectCtx, requests)
 	if err != nil {
 		// Trace connection failure
 		if m.transportDebugger != nil {
@@ -161,8 +210,8 @@ func (m *Manager) Connect(ctx context.Context, client *officialMCP.Client, trans
 			"state":          "connecting",
 		})
 
-		m.setState(StateFailed)
 		m.info.LastError = classified
+		m.setState(StateFailed)
 		cancel()
 
 		// Return user-friendly error
@@ -172,6 +221,7 @@ func (m *Manager) Connect(ctx context.Context, client *officialMCP.Client, trans
 
 	// Successfully connected
 	m.session = session
+	m.requests = requests.conn
 	m.setState(StateConnected)
 	m.info.ConnectedAt = time.Now()
 	m.info.SessionID = session.ID()
@@ -191,6 +241,9 @@ func (m *Manager) Connect(ctx context.Context, client *officialMCP.Client, trans
 		debug.F("sessionID", m.info.SessionID),
 		debug.F("connectedAt", m.info.ConnectedAt))
 
+	// Reconnect if the session ends unexpectedly
+	go m.watchSession(session)
+
 	// Start health monitoring if transport supports it
 	if contextStrategy.RequiresLongLivedConnection() {
 		go m.startHealthMonitoring(connectCtx)
@@ -219,6 +272,12 @@ func (m *Manager) disconnectLocked() error {
 
 	var lastErr error
 
+	// Stop any reconnection in progress
+	if m.reconnectCancel != nil {
+		m.reconnectCancel()
+		m.reconnectCancel = nil
+	}
+
 	// Cancel connection context
 	if m.closeFunc != nil {
 		m.closeFunc()
@@ -236,12 +295,14 @@ func (m *Manager) disconnectLocked() error {
 
 	// Clean up references
 	m.client = nil
-	m.transport = nil
+	m.dial = nil
+	m.requests = nil
 	m.contextStrategy = nil
 
 	// Update state
-	m.setState(StateClosed)
 	m.info.SessionID = ""
+	m.info.NextReconnectAt = time.Time{}
+	m.setState(StateClosed)
 
 	debug.Info("Session manager: Disconnection complete",
 		debug.F("finalState", m.info.State))
@@ -267,9 +328,49 @@ func (m *Manager) GetInfo() *Info {
 
 	// Return a copy to avoid race conditions
 	infoCopy := *m.info
+	infoCopy.Process = m.processInfo()
 	return &infoCopy
 }
 
+// RecordProcessEvent updates the server process state from the stdio
+// transport. It is used as the transport's process observer, so it may be
+// called while a connection attempt holds the session lock.
+func (m *Manager) RecordProcessEvent(evt transports.ProcessEvent) {
+	m.processMu.Lock()
+	defer m.processMu.Unlock()
+
+	switch evt.Type {
+	case transports.ProcessEventStarted:
+		m.process = &ProcessInfo{PID: evt.PID, Running: true}
+	case transports.ProcessEventExited:
+		// Ignore a previous connection's process exiting late
+		if m.process == nil || m.process.PID != evt.PID {
+			return
+		}
+		m.process.Running = false
+		m.process.ExitCode = evt.ExitCode
+		m.process.Signal = evt.Signal
+		m.process.ExitedAt = time.Now()
+
+		debug.Info("Session manager: Server process exited",
+			debug.F("pid", evt.PID),
+			debug.F("exitCode", evt.ExitCode),
+			debug.F("signal", evt.Signal))
+	}
+}
+
+// processInfo returns a copy of the server process state, or nil
+func (m *Manager) processInfo() *ProcessInfo {
+	m.processMu.Lock()
+	defer m.processMu.Unlock()
+
+	if m.process == nil {
+		return nil
+	}
+	info := *m.process
+	return &info
+}
+
 // GetConnectionHealth returns detailed connection health information
 func (m *Manager) GetConnectionHealth() map[string]interface{} {
 	m.mu.RLock()
@@ -280,6 +381,7 @@ func (m *Manager) GetConnectionHealth() map[string]interface{} {
 		"connected":              m.info.State == StateConnected,
 		"reconnect_count":        m.info.ReconnectCount,
 		"max_reconnect_attempts": m.maxReconnectAttempts,
+		"reconnect_backoff":      m.reconnectBackoff,
 		"health_check_interval":  m.healthCheckInterval.String(),
 		"transport_type":         string(m.info.TransportType),
 	}
@@ -297,6 +399,27 @@ func (m *Manager) GetConnectionHealth() map[string]interface{} {
 		health["session_id"] = m.info.SessionID
 	}
 
+	if m.info.State == StateReconnecting {
+		health["status"] = m.statusLocked().String()
+		if !m.info.NextReconnectAt.IsZero() {
+			health["next_reconnect_at"] = m.info.NextReconnectAt.Format(time.RFC3339)
+		}
+	}
+
+	if proc := m.processInfo(); proc != nil {
+		processHealth := map[string]interface{}{
+			"pid":     proc.PID,
+			"running": proc.Running,
+		}
+		if !proc.Running {
+			processHealth["exit_code"] = proc.ExitCode
+			if proc.Signal != "" {
+				processHealth["signal"] = proc.Signal
+			}
+		}
+		health["process"] = processHealth
+	}
+
 	return health
 }
 
@@ -424,7 +547,8 @@ func (m *Manager) IsConnected() bool {
 	return m.info.State == StateConnected && m.session != nil
 }
 
-// setState updates the session state (must be called with lock held)
+// setState updates the session state and publishes the transition (must be
+// called with lock held)
 func (m *Manager) setState(newState State) {
 	oldState := m.info.State
 	m.info.State = newState
@@ -433,6 +557,7 @@ func (m *Manager) setState(newState State) {
 		debug.Info("Session manager: State transition",
 			debug.F("from", oldState),
 			debug.F("to", newState))
+		m.publishLocked()
 	}
 }
 
@@ -471,7 +596,7 @@ func (m *Manager) performHealthCheck(ctx context.Context) {
 	// In the future, this could be enhanced with actual server ping
 	if session.ID() == "" {
 		debug.Error("Session manager: Health check failed - session has no ID")
-		m.handleConnectionFailure(fmt.Errorf("health check failed: session has no ID"))
+		m.handleConnectionFailure(session, fmt.Errorf("health check failed: session has no ID"))
 		return
 	}
 
@@ -481,134 +606,45 @@ func (m *Manager) performHealthCheck(ctx context.Context) {
 		debug.F("transport", transportType))
 }
 
-// handleConnectionFailure handles connection failures and triggers reconnection if appropriate
-func (m *Manager) handleConnectionFailure(err error) {
+// handleConnectionFailure handles the loss of a session, starting
+// reconnection unless reconnecting is disabled
+func (m *Manager) handleConnectionFailure(session *officialMCP.ClientSession, err error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if m.info.State != StateConnected {
-		return // Already handling failure or not connected
+	if m.info.State != StateConnected || m.session != session {
+		return // Disconnected on purpose, or already handling the failure
 	}
 
-	// Classify the error
-	classified := m.errorHandler.HandleError(context.Background(), err, "health_check", map[string]interface{}{
+	classified := m.errorHandler.HandleError(context.Background(), err, "session_lost", map[string]interface{}{
 		"transport_type": m.info.TransportType,
 		"state":          "connected",
 		"session_id":     m.info.SessionID,
 	})
-
 	debug.Error("Session manager: Connection failure detected", debug.F("classified", classified.Category))
 
-	// Check if we should attempt reconnection based on error classification
-	if m.info.ReconnectCount >= m.maxReconnectAttempts || !classified.Recoverable {
-		debug.Error("Session manager: Cannot reconnect",
-			debug.F("attempts", m.info.ReconnectCount),
-			debug.F("maxAttempts", m.maxReconnectAttempts),
-			debug.F("recoverable", classified.Recoverable))
-		m.setState(StateFailed)
-		m.info.LastError = classified
-		return
-	}
-
-	// Mark as reconnecting and attempt reconnection
-	m.setState(StateReconnecting)
+	// The lost session's transport cannot be reused, so it is closed; closing
+	// a stdio transport may wait for the server to exit
+	m.session = nil
+	m.requests = nil
+	m.info.SessionID = ""
 	m.info.LastError = classified
-	m.info.ReconnectCount++
-
-	// Start reconnection attempt in background
-	go m.attemptReconnection()
-}
-
-// attemptReconnection attempts to reconnect the session
-func (m *Manager) attemptReconnection() {
-	debug.Info("Session manager: Starting reconnection attempt",
-		debug.F("attempt", m.info.ReconnectCount),
-		debug.F("delay", m.reconnectDelay))
-
-	// Wait before attempting reconnection
-	time.Sleep(m.reconnectDelay)
-
-	m.mu.Lock()
-	client := m.client
-	transport := m.transport
-	contextStrategy := m.contextStrategy
-	m.mu.Unlock()
-
-	if client == nil || transport == nil || contextStrategy == nil {
-		debug.Error("Session manager: Cannot reconnect - missing connection components")
-		m.mu.Lock()
-		// Create classified error for missing components
-		err := fmt.Errorf("reconnection failed: missing connection components")
-		classified := m.errorHandler.HandleError(context.Background(), err, "session_reconnect", map[string]interface{}{
-			"reason": "missing_connection_components",
-		})
-		m.setState(StateFailed)
-		m.info.LastError = classified
-		m.mu.Unlock()
-		return
+	if m.closeFunc != nil {
+		m.closeFunc()
+		m.closeFunc = nil
 	}
+	go session.Close()
 
-	// Create new connection context
-	connectCtx := contextStrategy.GetConnectionContext(context.Background())
-	connectCtx, cancel := context.WithCancel(connectCtx)
-
-	// Try to reconnect
-	session, err := client.Connect(connectCtx, transport)
-	if err != nil {
-		// Classify reconnection error
-		classified := m.errorHandler.HandleError(connectCtx, err, "session_reconnect", map[string]interface{}{
-			"transport_type": m.info.TransportType,
-			"attempt":        m.info.ReconnectCount,
-		})
-
-		debug.Error("Session manager: Reconnection failed",
-			debug.F("attempt", m.info.ReconnectCount),
-			debug.F("category", classified.Category),
-			debug.F("recoverable", classified.Recoverable))
-
-		m.mu.Lock()
-		if m.info.ReconnectCount >= m.maxReconnectAttempts || !classified.Recoverable {
-			m.setState(StateFailed)
-			m.info.LastError = classified
-		} else {
-			// Will try again on next health check failure
-			m.setState(StateConnected) // Revert to connected to allow retry
-			m.info.LastError = classified
-		}
-		m.mu.Unlock()
-		cancel()
+	if m.maxReconnectAttempts <= 0 || m.dial == nil {
+		debug.Error("Session manager: Cannot reconnect", debug.F("maxAttempts", m.maxReconnectAttempts))
+		m.setState(StateFailed)
 		return
 	}
 
-	// Successfully reconnected
-	m.mu.Lock()
-	// Clean up old session if it still exists
-	if m.session != nil {
-		if closeErr := m.session.Close(); closeErr != nil {
-			debug.Error("Session manager: Failed to close old session during reconnection",
-				debug.F("error", closeErr))
-		}
-	}
-
-	// Update with new session
-	if m.closeFunc != nil {
-		m.closeFunc() // Cancel old context
-	}
-	m.closeFunc = cancel
-	m.session = session
-	m.setState(StateConnected)
-	m.info.ConnectedAt = time.Now()
-	m.info.SessionID = session.ID()
-	m.info.LastError = nil
-	// Keep reconnect count for monitoring
-
-	debug.Info("Session manager: Reconnection successful",
-		debug.F("attempt", m.info.ReconnectCount),
-		debug.F("newSessionID", m.info.SessionID))
-	m.mu.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.reconnectCancel = cancel
+	m.scheduleReconnectLocked(1)
+	m.setState(StateReconnecting)
 
-	// Restart health monitoring if needed
-	if contextStrategy.RequiresLongLivedConnection() {
-		go m.startHealthMonitoring(connectCtx)
-	}
+	go m.reconnect(ctx)
 }
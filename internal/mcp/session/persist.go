@@ -0,0 +1,232 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	mcpDebug "github.com/standardbeagle/mcp-tui/internal/mcp/debug"
+)
+
+const (
+	// snapshotVersion is the version of the session file format
+	snapshotVersion = 1
+
+	// maxCallRecords is how many tool calls are kept in the history
+	maxCallRecords = 100
+
+	// maxSnapshotEvents is how many recent events are saved with a session
+	maxSnapshotEvents = 200
+)
+
+// CallRecord is a tool call in the session history
+type CallRecord struct {
+	Tool      string                 `json:"tool"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	StartedAt time.Time              `json:"started_at"`
+	Duration  time.Duration          `json:"duration"`
+	IsError   bool                   `json:"is_error,omitempty"` // The tool reported an error result
+	Error     string                 `json:"error,omitempty"`    // The call itself failed
+}
+
+// Navigation is where the TUI was when the session was saved
+type Navigation struct {
+	Tab      int         `json:"tab"`
+	Tool     string      `json:"tool,omitempty"`     // Selected tool, found by name in case the list changed
+	Selected map[int]int `json:"selected,omitempty"` // Selected index by tab
+}
+
+// Snapshot is a saved session, written while mcp-tui runs so the next launch
+// can resume it
+type Snapshot struct {
+	Version       int                      `json:"version"`
+	SavedAt       time.Time                `json:"saved_at"`
+	Connection    *config.ConnectionConfig `json:"connection"`
+	SessionID     string                   `json:"session_id,omitempty"` // Streamable HTTP Mcp-Session-Id
+	LogLevel      string                   `json:"log_level,omitempty"`
+	Subscriptions []string                 `json:"subscriptions,omitempty"`
+	Calls         []CallRecord             `json:"calls,omitempty"`
+	Events        []*mcpDebug.Event        `json:"events,omitempty"`
+	Navigation    *Navigation              `json:"navigation,omitempty"`
+}
+
+// SnapshotPath returns the session file to use: file if set, otherwise
+// session.json next to the saved connections
+func SnapshotPath(file string) string {
+	if file != "" {
+		return file
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "session.json"
+	}
+	return filepath.Join(homeDir, ".config", "mcp-tui", "session.json")
+}
+
+// ServerSnapshotPath returns the session file of the server conn connects to:
+// base with a suffix naming the server, so each server open in a tab keeps
+// its own session
+func ServerSnapshotPath(base string, conn *config.ConnectionConfig) string {
+	key := append([]string{string(conn.Type), conn.Command, conn.URL}, conn.Args...)
+	sum := sha256.Sum256([]byte(strings.Join(key, "\x00")))
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext) + "-" + hex.EncodeToString(sum[:4]) + ext
+}
+
+// LatestSnapshot reads the most recently saved of the server sessions kept
+// next to base. Without any, the error satisfies errors.Is(err, fs.ErrNotExist).
+func LatestSnapshot(base string) (*Snapshot, error) {
+	ext := filepath.Ext(base)
+	paths, err := filepath.Glob(strings.TrimSuffix(base, ext) + "-*" + ext)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *Snapshot
+	var loadErr error
+	for _, path := range append(paths, base) {
+		snapshot, err := LoadSnapshot(path)
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				loadErr = err
+			}
+			continue
+		}
+		if latest == nil || snapshot.SavedAt.After(latest.SavedAt) {
+			latest = snapshot
+		}
+	}
+	switch {
+	case latest != nil:
+		return latest, nil
+	case loadErr != nil:
+		return nil, loadErr
+	default:
+		return nil, &fs.PathError{Op: "open", Path: base, Err: fs.ErrNotExist}
+	}
+}
+
+// LoadSnapshot reads a saved session. A missing file is reported with an
+// error satisfying errors.Is(err, fs.ErrNotExist).
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("invalid session file %s: %w", path, err)
+	}
+	if snapshot.Version != snapshotVersion {
+		return nil, fmt.Errorf("session file %s has unsupported version %d", path, snapshot.Version)
+	}
+	if snapshot.Connection == nil {
+		return nil, fmt.Errorf("session file %s has no connection", path)
+	}
+	return &snapshot, nil
+}
+
+// Save writes the snapshot to path, replacing the file in one step so an
+// interrupted save leaves the previous session intact. The file is private
+// to the user since connections may carry credentials in headers.
+func (s *Snapshot) Save(path string) error {
+	s.Version = snapshotVersion
+	s.SavedAt = time.Now()
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".session-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// Snapshot captures the session state the manager owns. The caller adds the
+// connection and anything else it wants resumed.
+func (m *Manager) Snapshot() *Snapshot {
+	m.mu.RLock()
+	snapshot := &Snapshot{
+		LogLevel:      m.logLevel,
+		Subscriptions: make([]string, 0, len(m.subscriptions)),
+	}
+	for uri := range m.subscriptions {
+		snapshot.Subscriptions = append(snapshot.Subscriptions, uri)
+	}
+	if m.requests != nil {
+		snapshot.SessionID = m.requests.SessionID()
+	}
+	m.mu.RUnlock()
+	sort.Strings(snapshot.Subscriptions)
+
+	snapshot.Calls = m.Calls()
+	if m.eventTracer != nil {
+		snapshot.Events = m.eventTracer.GetRecentEvents(maxSnapshotEvents)
+	}
+	return snapshot
+}
+
+// Restore brings back the history of a saved session. The log level and
+// subscriptions are restored by setting them on the new connection.
+func (m *Manager) Restore(snapshot *Snapshot) {
+	m.callsMu.Lock()
+	calls := append(append([]CallRecord{}, snapshot.Calls...), m.calls...)
+	if len(calls) > maxCallRecords {
+		calls = calls[len(calls)-maxCallRecords:]
+	}
+	m.calls = calls
+	m.callsMu.Unlock()
+
+	if m.eventTracer != nil {
+		m.eventTracer.Restore(snapshot.Events)
+	}
+}
+
+// RecordCall adds a tool call to the session history
+func (m *Manager) RecordCall(call CallRecord) {
+	m.callsMu.Lock()
+	defer m.callsMu.Unlock()
+
+	m.calls = append(m.calls, call)
+	if len(m.calls) > maxCallRecords {
+		m.calls = m.calls[len(m.calls)-maxCallRecords:]
+	}
+}
+
+// Calls returns the tool calls made in the session, oldest first
+func (m *Manager) Calls() []CallRecord {
+	m.callsMu.Lock()
+	defer m.callsMu.Unlock()
+
+	calls := make([]CallRecord, len(m.calls))
+	copy(calls, m.calls)
+	return calls
+}
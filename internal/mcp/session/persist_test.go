@@ -0,0 +1,103 @@
+package session
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	mcpDebug "github.com/standardbeagle/mcp-tui/internal/mcp/debug"
+)
+
+func TestSnapshotSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "session.json")
+
+	_, err := LoadSnapshot(path)
+	assert.True(t, errors.Is(err, fs.ErrNotExist), "A missing file is reported as such")
+
+	snapshot := &Snapshot{
+		Connection: &config.ConnectionConfig{
+			Type:    config.TransportStreamableHTTP,
+			URL:     "http://localhost:8080/mcp",
+			Headers: map[string]string{"Authorization": "Bearer secret"},
+		},
+		SessionID:     "session-1",
+		LogLevel:      "debug",
+		Subscriptions: []string{"file:///log.txt"},
+		Calls:         []CallRecord{{Tool: "echo", Arguments: map[string]interface{}{"message": "hi"}, Duration: time.Second}},
+		Events:        []*mcpDebug.Event{{ID: "evt_1", Type: mcpDebug.EventRequestSent, Method: "tools/call"}},
+		Navigation:    &Navigation{Tab: 1, Tool: "echo", Selected: map[int]int{0: 2, 1: 1}},
+	}
+	require.NoError(t, snapshot.Save(path))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm(), "Connections may carry credentials")
+
+	loaded, err := LoadSnapshot(path)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot.Connection, loaded.Connection)
+	assert.Equal(t, "session-1", loaded.SessionID)
+	assert.Equal(t, snapshot.Calls, loaded.Calls)
+	assert.Equal(t, "tools/call", loaded.Events[0].Method)
+	assert.Equal(t, snapshot.Navigation, loaded.Navigation)
+	assert.False(t, loaded.SavedAt.IsZero())
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"version": 99, "connection": {}}`), 0o600))
+	_, err = LoadSnapshot(path)
+	assert.ErrorContains(t, err, "unsupported version")
+}
+
+func TestManagerSnapshotRestoresHistory(t *testing.T) {
+	m := NewManager()
+	for i := 0; i < maxCallRecords+5; i++ {
+		m.RecordCall(CallRecord{Tool: "old"})
+	}
+	assert.Len(t, m.Calls(), maxCallRecords, "The history is bounded")
+
+	m = NewManager()
+	m.RecordCall(CallRecord{Tool: "new"})
+	m.Restore(&Snapshot{
+		Calls:  []CallRecord{{Tool: "saved"}},
+		Events: []*mcpDebug.Event{{ID: "evt_saved"}},
+	})
+
+	calls := m.Calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "saved", calls[0].Tool, "Saved calls come before those made since")
+	assert.Equal(t, "new", calls[1].Tool)
+
+	snapshot := m.Snapshot()
+	assert.Equal(t, calls, snapshot.Calls)
+	require.NotEmpty(t, snapshot.Events)
+	assert.Equal(t, "evt_saved", snapshot.Events[0].ID)
+	assert.Empty(t, snapshot.SessionID, "There is no session to resume without a connection")
+}
+
+func TestServerSnapshots(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "session.json")
+	_, err := LatestSnapshot(base)
+	assert.True(t, errors.Is(err, fs.ErrNotExist), "No session has been saved yet")
+
+	first := &config.ConnectionConfig{Type: config.TransportStdio, Command: "node", Args: []string{"a.js"}}
+	second := &config.ConnectionConfig{Type: config.TransportStdio, Command: "node", Args: []string{"b.js"}}
+	assert.NotEqual(t, ServerSnapshotPath(base, first), ServerSnapshotPath(base, second), "Each server keeps its own session")
+	assert.Equal(t, ServerSnapshotPath(base, first), ServerSnapshotPath(base, &config.ConnectionConfig{Type: config.TransportStdio, Command: "node", Args: []string{"a.js"}}))
+
+	require.NoError(t, (&Snapshot{Connection: second}).Save(ServerSnapshotPath(base, second)))
+	require.NoError(t, (&Snapshot{Connection: first}).Save(ServerSnapshotPath(base, first)))
+
+	latest, err := LatestSnapshot(base)
+	require.NoError(t, err)
+	assert.Equal(t, first, latest.Connection, "The session saved last is offered")
+
+	loaded, err := LoadSnapshot(ServerSnapshotPath(base, second))
+	require.NoError(t, err)
+	assert.Equal(t, second, loaded.Connection, "Saving one server leaves the others intact")
+}
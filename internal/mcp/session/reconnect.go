@@ -0,0 +1,468 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/errors"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
+)
+
+// Dialer creates a fresh transport for a connection attempt. A transport
+// cannot be connected twice (a stdio transport owns the process it started),
+// so every reconnection attempt dials again.
+type Dialer func() (officialMCP.Transport, transports.ContextStrategy, error)
+
+// Backoff strategies for the delay between reconnection attempts
+const (
+	BackoffNone        = "none"
+	BackoffLinear      = "linear"
+	BackoffExponential = "exponential"
+)
+
+// reconnectJitter is the fraction by which a reconnection delay is randomly
+// varied, so clients that lost the same server do not retry in lockstep
+const reconnectJitter = 0.2
+
+// Status is a snapshot of the session state, published to observers on
+// every transition and reconnection attempt
+type Status struct {
+	State       State
+	Attempt     int       // Reconnection attempt in progress or scheduled
+	MaxAttempts int       // Reconnection attempts allowed
+	NextAttempt time.Time // When the scheduled attempt starts; zero while one is running
+	Err         error     // Why the connection was lost or failed, if it was
+}
+
+// String describes the status for display, e.g.
+// "reconnecting (attempt 2/5, next in 4s)"
+func (s Status) String() string {
+	if s.State != StateReconnecting {
+		return s.State.String()
+	}
+	text := fmt.Sprintf("reconnecting (attempt %d/%d", s.Attempt, s.MaxAttempts)
+	if wait := time.Until(s.NextAttempt); !s.NextAttempt.IsZero() && wait > 0 {
+		text += fmt.Sprintf(", next in %s", time.Duration(math.Ceil(wait.Seconds()))*time.Second)
+	}
+	return text + ")"
+}
+
+// backoffDelay returns the delay before a reconnection attempt. random, in
+// [0, 1), varies it by up to reconnectJitter either way.
+func backoffDelay(strategy string, base, max time.Duration, attempt int, random float64) time.Duration {
+	delay := base
+	switch strategy {
+	case BackoffLinear:
+		delay = base * time.Duration(attempt)
+	case BackoffExponential:
+		for i := 1; i < attempt && (max <= 0 || delay < max); i++ {
+			delay *= 2
+		}
+	}
+	if max > 0 && delay > max {
+		delay = max
+	}
+
+	delay += time.Duration((random*2 - 1) * reconnectJitter * float64(delay))
+	if max > 0 && delay > max {
+		delay = max
+	}
+	return delay
+}
+
+// retryable reports whether another reconnection attempt could succeed.
+// Errors that need the user to change something end the attempts; errors
+// that cannot be classified, such as a server exiting during initialize,
+// are retried.
+func retryable(classified *errors.ClassifiedError) bool {
+	return classified.Recoverable || classified.Category == errors.CategoryUnknown
+}
+
+// SetBackoff sets how the delay between reconnection attempts grows: none,
+// linear or exponential, capped at maxDelay when it is positive
+func (m *Manager) SetBackoff(strategy string, maxDelay time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.reconnectBackoff = strategy
+	m.maxReconnectDelay = maxDelay
+}
+
+// Observe calls fn with the session status on every state transition and
+// reconnection attempt until the returned function is called. fn is called
+// with the session locked, so it must not block or call back into the
+// manager.
+func (m *Manager) Observe(fn func(Status)) func() {
+	m.observersMu.Lock()
+	defer m.observersMu.Unlock()
+
+	m.nextObserver++
+	id := m.nextObserver
+	m.observers[id] = fn
+	return func() {
+		m.observersMu.Lock()
+		defer m.observersMu.Unlock()
+		delete(m.observers, id)
+	}
+}
+
+// statusLocked returns the current status (must be called with lock held)
+func (m *Manager) statusLocked() Status {
+	status := Status{
+		State:       m.info.State,
+		Attempt:     m.info.ReconnectCount,
+		MaxAttempts: m.maxReconnectAttempts,
+		NextAttempt: m.info.NextReconnectAt,
+	}
+	if m.info.LastError != nil {
+		status.Err = m.info.LastError
+	}
+	return status
+}
+
+// publishLocked sends the current status to the observers (must be called
+// with lock held)
+func (m *Manager) publishLocked() {
+	status := m.statusLocked()
+
+	m.observersMu.Lock()
+	defer m.observersMu.Unlock()
+	for _, fn := range m.observers {
+		fn(status)
+	}
+}
+
+// watchSession starts reconnecting when a session ends without having been
+// disconnected, for example because the server process exited
+func (m *Manager) watchSession(session *officialMCP.ClientSession) {
+	err := session.Wait()
+	if err == nil {
+		err = fmt.Errorf("connection to the server was lost")
+	} else {
+		err = fmt.Errorf("connection to the server was lost: %w", err)
+	}
+	m.handleConnectionFailure(session, err)
+}
+
+// scheduleReconnectLocked records when the given attempt runs (must be called
+// with lock held)
+func (m *Manager) scheduleReconnectLocked(attempt int) {
+	delay := backoffDelay(m.reconnectBackoff, m.reconnectDelay, m.maxReconnectDelay, attempt, rand.Float64())
+	m.info.ReconnectCount = attempt
+	m.info.NextReconnectAt = time.Now().Add(delay)
+
+	debug.Info("Session manager: Reconnection scheduled",
+		debug.F("attempt", attempt),
+		debug.F("maxAttempts", m.maxReconnectAttempts),
+		debug.F("delay", delay))
+}
+
+// reconnect replaces a lost session, retrying with backoff until it
+// succeeds, the attempts run out or the session is disconnected
+func (m *Manager) reconnect(ctx context.Context) {
+	for {
+		m.mu.RLock()
+		attempt := m.info.ReconnectCount
+		wait := time.Until(m.info.NextReconnectAt)
+		transportType := m.info.TransportType
+		m.mu.RUnlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		m.mu.Lock()
+		m.info.NextReconnectAt = time.Time{}
+		m.publishLocked()
+		m.mu.Unlock()
+
+		err := m.redial(ctx)
+		if err == nil {
+			return
+		}
+
+		classified := m.errorHandler.HandleError(ctx, err, "session_reconnect", map[string]interface{}{
+			"transport_type": transportType,
+			"attempt":        attempt,
+		})
+		debug.Error("Session manager: Reconnection failed",
+			debug.F("attempt", attempt),
+			debug.F("category", classified.Category),
+			debug.F("recoverable", classified.Recoverable))
+
+		m.mu.Lock()
+		if ctx.Err() != nil {
+			m.mu.Unlock()
+			return
+		}
+		m.info.LastError = classified
+		if attempt >= m.maxReconnectAttempts || !retryable(classified) {
+			m.reconnectCancel = nil
+			m.info.NextReconnectAt = time.Time{}
+			m.setState(StateFailed)
+			m.mu.Unlock()
+			return
+		}
+		m.scheduleReconnectLocked(attempt + 1)
+		m.publishLocked()
+		m.mu.Unlock()
+	}
+}
+
+// redial connects with a fresh transport, performing the full initialize
+// handshake, restores the log level and resource subscriptions, and installs
+// the new session
+func (m *Manager) redial(ctx context.Context) error {
+	m.mu.RLock()
+	client := m.client
+	dial := m.dial
+	m.mu.RUnlock()
+
+	if client == nil || dial == nil {
+		return fmt.Errorf("reconnection failed: missing connection components")
+	}
+
+	transport, contextStrategy, err := dial()
+	if err != nil {
+		return err
+	}
+	requests := &requestTransport{Transport: transport}
+
+	connectCtx, cancel := context.WithCancel(contextStrategy.GetConnectionContext(context.Background()))
+	session, err := client.Connect(connectCtx, requests)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	if err := m.restoreState(ctx, session, requests.conn); err != nil {
+		session.Close()
+		cancel()
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if ctx.Err() != nil {
+		// Disconnected while the attempt was running
+		session.Close()
+		cancel()
+		return nil
+	}
+
+	m.closeFunc = cancel
+	m.session = session
+	m.requests = requests.conn
+	m.contextStrategy = contextStrategy
+	m.reconnectCancel = nil
+	m.info.ConnectedAt = time.Now()
+	m.info.SessionID = session.ID()
+	m.info.LastError = nil
+	m.info.NextReconnectAt = time.Time{}
+	m.setState(StateConnected)
+
+	debug.Info("Session manager: Reconnection successful",
+		debug.F("attempt", m.info.ReconnectCount),
+		debug.F("newSessionID", m.info.SessionID))
+
+	go m.watchSession(session)
+	if contextStrategy.RequiresLongLivedConnection() {
+		go m.startHealthMonitoring(connectCtx)
+	}
+	return nil
+}
+
+// restoreState reapplies the log level and resource subscriptions of the
+// lost session to its replacement
+func (m *Manager) restoreState(ctx context.Context, session *officialMCP.ClientSession, requests *requestConn) error {
+	m.mu.RLock()
+	level := m.logLevel
+	uris := make([]string, 0, len(m.subscriptions))
+	for uri := range m.subscriptions {
+		uris = append(uris, uri)
+	}
+	m.mu.RUnlock()
+	sort.Strings(uris)
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if level != "" {
+		if err := session.SetLevel(ctx, &officialMCP.SetLevelParams{Level: officialMCP.LoggingLevel(level)}); err != nil {
+			return fmt.Errorf("failed to restore log level %s: %w", level, err)
+		}
+	}
+	for _, uri := range uris {
+		if err := requests.call(ctx, "resources/subscribe", map[string]string{"uri": uri}); err != nil {
+			return fmt.Errorf("failed to restore subscription to %s: %w", uri, err)
+		}
+	}
+
+	debug.Info("Session manager: Session state restored",
+		debug.F("logLevel", level),
+		debug.F("subscriptions", len(uris)))
+	return nil
+}
+
+// SetLogLevel asks the server to send log messages at level and above. The
+// level is restored after a reconnection.
+func (m *Manager) SetLogLevel(ctx context.Context, level string) error {
+	session := m.GetSession()
+	if session == nil {
+		return fmt.Errorf("not connected")
+	}
+	if err := session.SetLevel(ctx, &officialMCP.SetLevelParams{Level: officialMCP.LoggingLevel(level)}); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logLevel = level
+	return nil
+}
+
+// Subscribe subscribes to updates of a resource. The subscription is
+// restored after a reconnection.
+func (m *Manager) Subscribe(ctx context.Context, uri string) error {
+	if err := m.request(ctx, "resources/subscribe", uri); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscriptions[uri] = true
+	return nil
+}
+
+// Unsubscribe ends a resource subscription
+func (m *Manager) Unsubscribe(ctx context.Context, uri string) error {
+	if err := m.request(ctx, "resources/unsubscribe", uri); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.subscriptions, uri)
+	return nil
+}
+
+// request sends a resource request on the current connection
+func (m *Manager) request(ctx context.Context, method, uri string) error {
+	m.mu.RLock()
+	requests := m.requests
+	connected := m.info.State == StateConnected
+	m.mu.RUnlock()
+
+	if !connected || requests == nil {
+		return fmt.Errorf("not connected")
+	}
+	return requests.call(ctx, method, map[string]string{"uri": uri})
+}
+
+// requestTransport wraps a transport so its connection can carry requests of
+// the manager's own
+type requestTransport struct {
+	officialMCP.Transport
+	conn *requestConn
+}
+
+// Connect implements officialMCP.Transport
+func (t *requestTransport) Connect(ctx context.Context) (officialMCP.Connection, error) {
+	conn, err := t.Transport.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.conn = &requestConn{Connection: conn, pending: make(map[string]chan *jsonrpc.Response)}
+	return t.conn, nil
+}
+
+// requestConn lets the manager send requests the SDK client has no method
+// for, such as resources/subscribe, on the session's connection. Their
+// responses are taken out of the stream before the SDK reads it.
+type requestConn struct {
+	officialMCP.Connection
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[string]chan *jsonrpc.Response
+}
+
+// Read implements officialMCP.Connection
+func (c *requestConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	for {
+		msg, err := c.Connection.Read(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if resp, ok := msg.(*jsonrpc.Response); ok {
+			if ch := c.take(resp.ID); ch != nil {
+				ch <- resp
+				continue
+			}
+		}
+		return msg, nil
+	}
+}
+
+// call sends a request and waits for its response
+func (c *requestConn) call(ctx context.Context, method string, params interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.nextID++
+	// String IDs cannot collide with the SDK's numeric ones
+	id, err := transports.MakeID(fmt.Sprintf("mcp-tui-%d", c.nextID))
+	if err != nil {
+		c.mu.Unlock()
+		return err
+	}
+	ch := make(chan *jsonrpc.Response, 1)
+	c.pending[requestKey(id)] = ch
+	c.mu.Unlock()
+
+	if err := c.Connection.Write(ctx, &jsonrpc.Request{ID: id, Method: method, Params: data}); err != nil {
+		c.take(id)
+		return err
+	}
+
+	select {
+	case resp := <-ch:
+		return resp.Error
+	case <-ctx.Done():
+		c.take(id)
+		return ctx.Err()
+	}
+}
+
+// take removes and returns the channel waiting for a response, if any
+func (c *requestConn) take(id jsonrpc.ID) chan *jsonrpc.Response {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := requestKey(id)
+	ch := c.pending[key]
+	delete(c.pending, key)
+	return ch
+}
+
+// requestKey makes a map key of a request ID
+func requestKey(id jsonrpc.ID) string {
+	return fmt.Sprintf("%T:%v", id.Raw(), id.Raw())
+}
@@ -0,0 +1,138 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		max      time.Duration
+		attempt  int
+		want     time.Duration
+	}{
+		{"none", BackoffNone, 0, 4, time.Second},
+		{"linear", BackoffLinear, 0, 3, 3 * time.Second},
+		{"exponential", BackoffExponential, 0, 4, 8 * time.Second},
+		{"capped", BackoffExponential, 5 * time.Second, 10, 5 * time.Second},
+		{"unknown strategy waits the base delay", "fibonacci", 0, 3, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A random value of 0.5 applies no jitter
+			assert.Equal(t, tt.want, backoffDelay(tt.strategy, time.Second, tt.max, tt.attempt, 0.5))
+		})
+	}
+
+	t.Run("jitter", func(t *testing.T) {
+		assert.Equal(t, 3200*time.Millisecond, backoffDelay(BackoffExponential, time.Second, 0, 3, 0))
+		assert.InDelta(t, float64(4800*time.Millisecond), float64(backoffDelay(BackoffExponential, time.Second, 0, 3, 0.999999)), float64(time.Millisecond))
+		assert.Equal(t, 5*time.Second, backoffDelay(BackoffLinear, time.Second, 5*time.Second, 5, 0.999999), "Jitter never exceeds the cap")
+	})
+}
+
+func TestStatusString(t *testing.T) {
+	status := Status{
+		State:       StateReconnecting,
+		Attempt:     2,
+		MaxAttempts: 5,
+		NextAttempt: time.Now().Add(3500 * time.Millisecond),
+	}
+	assert.Equal(t, "reconnecting (attempt 2/5, next in 4s)", status.String())
+
+	status.NextAttempt = time.Time{}
+	assert.Equal(t, "reconnecting (attempt 2/5)", status.String())
+
+	assert.Equal(t, StateConnected.String(), Status{State: StateConnected, Attempt: 2}.String())
+}
+
+// inMemoryServer serves a new in-memory server session for every dial and
+// lets the test drop the latest one
+type inMemoryServer struct {
+	server *officialMCP.Server
+
+	mu       sync.Mutex
+	dials    int
+	sessions []*officialMCP.ServerSession
+}
+
+func (s *inMemoryServer) dial() (officialMCP.Transport, transports.ContextStrategy, error) {
+	clientTransport, serverTransport := officialMCP.NewInMemoryTransports()
+	session, err := s.server.Connect(context.Background(), serverTransport)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.mu.Lock()
+	s.dials++
+	s.sessions = append(s.sessions, session)
+	s.mu.Unlock()
+	return clientTransport, transports.NewContextStrategy(transports.TransportSTDIO), nil
+}
+
+func (s *inMemoryServer) drop() {
+	s.mu.Lock()
+	session := s.sessions[len(s.sessions)-1]
+	s.mu.Unlock()
+	session.Close()
+}
+
+func TestReconnectDialsFreshTransport(t *testing.T) {
+	server := &inMemoryServer{
+		server: officialMCP.NewServer(&officialMCP.Implementation{Name: "test", Version: "1.0.0"}, nil),
+	}
+	client := officialMCP.NewClient(&officialMCP.Implementation{Name: "mcp-tui-test", Version: "1.0.0"}, nil)
+
+	m := NewManager()
+	m.SetReconnectionPolicy(3, 10*time.Millisecond)
+	m.SetBackoff(BackoffLinear, time.Second)
+
+	var mu sync.Mutex
+	var seen []Status
+	stop := m.Observe(func(status Status) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, status)
+	})
+	defer stop()
+
+	require.NoError(t, m.Connect(context.Background(), client, server.dial, transports.TransportSTDIO))
+	defer m.Disconnect()
+	require.NoError(t, m.SetLogLevel(context.Background(), "debug"))
+
+	server.drop()
+
+	require.Eventually(t, func() bool {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		return server.dials == 2 && m.GetInfo().State == StateConnected
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err := m.GetSession().ListTools(context.Background(), nil)
+	assert.NoError(t, err, "The new session is initialized")
+
+	mu.Lock()
+	defer mu.Unlock()
+	var reconnecting *Status
+	for i := range seen {
+		if seen[i].State == StateReconnecting {
+			reconnecting = &seen[i]
+			break
+		}
+	}
+	require.NotNil(t, reconnecting, "Observers see the reconnection")
+	assert.Equal(t, 1, reconnecting.Attempt)
+	assert.Equal(t, 3, reconnecting.MaxAttempts)
+	assert.Equal(t, StateConnected, seen[len(seen)-1].State)
+}
@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	mcpConfig "github.com/standardbeagle/mcp-tui/internal/mcp/config"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/session"
+)
+
+func TestResumeSavedSession(t *testing.T) {
+	server := officialMCP.NewServer(&officialMCP.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	server.AddTool(&officialMCP.Tool{Name: "echo", Description: "Echo input"},
+		func(ctx context.Context, ss *officialMCP.ServerSession, params *officialMCP.CallToolParamsFor[map[string]any]) (*officialMCP.CallToolResult, error) {
+			return &officialMCP.CallToolResult{Content: []officialMCP.Content{&officialMCP.TextContent{Text: "ok"}}}, nil
+		})
+	httpServer := httptest.NewServer(officialMCP.NewStreamableHTTPHandler(func(*http.Request) *officialMCP.Server { return server }, nil))
+	defer httpServer.Close()
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	newService := func() Service {
+		cfg := mcpConfig.Default()
+		cfg.Session.EnablePersistence = true
+		cfg.Session.PersistenceFile = path
+		return NewServiceWithConfig(cfg)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// The first run calls a tool and saves the session, then exits without
+	// ending it
+	first := newService()
+	assert.Nil(t, first.SessionSnapshot(), "Nothing to save before connecting")
+	require.NoError(t, first.Connect(ctx, &config.ConnectionConfig{Type: config.TransportStreamableHTTP, URL: httpServer.URL}))
+	_, err := first.CallTool(ctx, CallToolRequest{Name: "echo", Arguments: map[string]interface{}{"message": "hi"}})
+	require.NoError(t, err)
+
+	snapshot := first.SessionSnapshot()
+	require.NotNil(t, snapshot)
+	require.NotEmpty(t, snapshot.SessionID)
+	require.NoError(t, first.SaveSession(snapshot))
+
+	saved, err := session.LatestSnapshot(path)
+	require.NoError(t, err)
+
+	second := newService()
+	resumed, err := second.ResumeSession(ctx, saved)
+	require.NoError(t, err)
+	defer second.Disconnect()
+	assert.True(t, resumed)
+	assert.Equal(t, saved.SessionID, second.SessionSnapshot().SessionID)
+
+	calls := second.SessionSnapshot().Calls
+	require.Len(t, calls, 1, "The tool call history is restored")
+	assert.Equal(t, "echo", calls[0].Tool)
+
+	// A session the server has ended is replaced by a new one
+	saved.SessionID = "ended-session"
+	third := newService()
+	resumed, err = third.ResumeSession(ctx, saved)
+	require.NoError(t, err)
+	defer third.Disconnect()
+	assert.False(t, resumed)
+	assert.True(t, third.IsConnected())
+	assert.NotEqual(t, "ended-session", third.SessionSnapshot().SessionID)
+}
@@ -0,0 +1,447 @@
+// Package streamable serves MCP sessions over the streamable HTTP transport.
+// The mock server and the proxy both use it, each giving every client
+// session a connection to pass its messages to.
+package streamable
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
+)
+
+const (
+	// SessionIDHeader carries the session ID after initialization
+	SessionIDHeader = "Mcp-Session-Id"
+
+	// MaxBodySize is the largest POST body read, so that a client cannot
+	// make the server buffer without limit
+	MaxBodySize = 4 << 20
+)
+
+// Connect opens the connection for a new session. The connection is closed
+// when the session ends, and ctx is cancelled.
+type Connect func(ctx context.Context) (officialMCP.Connection, error)
+
+// Handler serves the streamable HTTP transport. POSTs carry client messages,
+// GET opens the standalone stream and DELETE ends the session. Responses go
+// back on the POST that carried their request, and other server messages on
+// the most recent open POST stream or, failing that, the GET stream.
+type Handler struct {
+	// Name prefixes the handler's log messages, such as "Proxy"
+	Name string
+	// OnInvalid, if set, is called with a POST body that is not JSON-RPC
+	OnInvalid func(body []byte, err error)
+	// AllowedOrigins are the browser origins served besides loopback ones
+	AllowedOrigins []string
+
+	connect Connect
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+// session is a client session and its connection
+type session struct {
+	id     string
+	conn   officialMCP.Connection
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	pending map[string]chan jsonrpc.Message // Response waiters by request ID
+	streams []chan jsonrpc.Message          // Open POST event streams, newest last
+	get     chan jsonrpc.Message            // Standalone GET stream, if open
+}
+
+// NewHandler returns a handler that connects each new session with connect
+func NewHandler(connect Connect) *Handler {
+	return &Handler{Name: "Streamable HTTP", connect: connect, sessions: make(map[string]*session)}
+}
+
+// Close ends every session and closes its connection
+func (h *Handler) Close() error {
+	h.mu.Lock()
+	sessions := make([]*session, 0, len(h.sessions))
+	for _, sess := range h.sessions {
+		sessions = append(sessions, sess)
+	}
+	h.mu.Unlock()
+
+	for _, sess := range sessions {
+		h.closeSession(sess)
+	}
+	return nil
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !CheckOrigin(w, r, h.AllowedOrigins) {
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		h.handlePost(w, r)
+	case http.MethodGet:
+		h.handleGet(w, r)
+	case http.MethodDelete:
+		h.handleDelete(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handlePost passes client messages to the session and returns the replies
+func (h *Handler) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBodySize))
+	if err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	msgs, err := transports.DecodeMessages(body)
+	if err != nil {
+		if h.OnInvalid != nil {
+			h.OnInvalid(body, err)
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var ids []string
+	initialize := false
+	for _, msg := range msgs {
+		if req, ok := msg.(*jsonrpc.Request); ok && req.ID.IsValid() {
+			ids = append(ids, idKey(req.ID))
+			initialize = initialize || req.Method == "initialize"
+		}
+	}
+
+	var sess *session
+	if initialize && r.Header.Get(SessionIDHeader) == "" {
+		if sess, err = h.newSession(); err != nil {
+			http.Error(w, fmt.Sprintf("failed to connect: %v", err), http.StatusBadGateway)
+			return
+		}
+		w.Header().Set(SessionIDHeader, sess.id)
+	} else if sess = h.lookup(w, r); sess == nil {
+		return
+	}
+
+	stream := strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+	out := make(chan jsonrpc.Message, 64)
+	sess.register(ids, out, stream)
+	defer sess.unregister(ids, out)
+
+	for _, msg := range msgs {
+		if err := sess.conn.Write(sess.ctx, msg); err != nil {
+			http.Error(w, fmt.Sprintf("write failed: %v", err), http.StatusBadGateway)
+			return
+		}
+	}
+	if len(ids) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	if stream {
+		h.streamReplies(w, r, sess, out, len(ids))
+		return
+	}
+
+	var responses []json.RawMessage
+	for len(responses) < len(ids) {
+		select {
+		case msg := <-out:
+			if _, ok := msg.(*jsonrpc.Response); !ok {
+				continue // Only an event stream can carry other messages
+			}
+			if data, err := transports.EncodeMessage(msg); err == nil {
+				responses = append(responses, data)
+			}
+		case <-r.Context().Done():
+			return
+		case <-sess.ctx.Done():
+			http.Error(w, "connection closed", http.StatusBadGateway)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if len(responses) == 1 {
+		w.Write(responses[0])
+		return
+	}
+	json.NewEncoder(w).Encode(responses)
+}
+
+// streamReplies writes server messages as events until every request is answered
+func (h *Handler) streamReplies(w http.ResponseWriter, r *http.Request, sess *session, out chan jsonrpc.Message, expected int) {
+	flusher := startStream(w)
+	for expected > 0 {
+		select {
+		case msg := <-out:
+			if err := writeEvent(w, flusher, msg); err != nil {
+				return
+			}
+			if _, ok := msg.(*jsonrpc.Response); ok {
+				expected--
+			}
+		case <-r.Context().Done():
+			return
+		case <-sess.ctx.Done():
+			return
+		}
+	}
+}
+
+// handleGet opens the standalone stream for server messages outside a request
+func (h *Handler) handleGet(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		http.Error(w, "GET requires Accept: text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+	sess := h.lookup(w, r)
+	if sess == nil {
+		return
+	}
+
+	stream := make(chan jsonrpc.Message, 64)
+	sess.mu.Lock()
+	if sess.get != nil {
+		sess.mu.Unlock()
+		http.Error(w, "a stream is already open for this session", http.StatusConflict)
+		return
+	}
+	sess.get = stream
+	sess.mu.Unlock()
+	defer func() {
+		sess.mu.Lock()
+		sess.get = nil
+		sess.mu.Unlock()
+	}()
+
+	flusher := startStream(w)
+	for {
+		select {
+		case msg := <-stream:
+			if err := writeEvent(w, flusher, msg); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		case <-sess.ctx.Done():
+			return
+		}
+	}
+}
+
+// handleDelete ends a session and closes its connection
+func (h *Handler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	sess := h.lookup(w, r)
+	if sess == nil {
+		return
+	}
+	h.closeSession(sess)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// newSession connects a new client session
+func (h *Handler) newSession() (*session, error) {
+	idBytes := make([]byte, 16)
+	rand.Read(idBytes)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	conn, err := h.connect(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	sess := &session{
+		id:      hex.EncodeToString(idBytes),
+		conn:    conn,
+		ctx:     ctx,
+		cancel:  cancel,
+		pending: make(map[string]chan jsonrpc.Message),
+	}
+
+	h.mu.Lock()
+	h.sessions[sess.id] = sess
+	h.mu.Unlock()
+	debug.Info(h.Name+": HTTP session started", debug.F("session", sess.id))
+
+	go func() {
+		var err error
+		for {
+			var msg jsonrpc.Message
+			if msg, err = conn.Read(ctx); err != nil {
+				break
+			}
+			h.dispatch(sess, msg)
+		}
+		debug.Info(h.Name+": Session connection ended", debug.F("session", sess.id), debug.F("error", err))
+		h.closeSession(sess)
+	}()
+	return sess, nil
+}
+
+// closeSession forgets a session and closes its connection
+func (h *Handler) closeSession(sess *session) {
+	h.mu.Lock()
+	_, ok := h.sessions[sess.id]
+	delete(h.sessions, sess.id)
+	h.mu.Unlock()
+	if ok {
+		sess.cancel()
+		sess.conn.Close()
+	}
+}
+
+// lookup finds the session named by the request, writing an error if there is none
+func (h *Handler) lookup(w http.ResponseWriter, r *http.Request) *session {
+	id := r.Header.Get(SessionIDHeader)
+	if id == "" {
+		http.Error(w, "missing "+SessionIDHeader+" header", http.StatusBadRequest)
+		return nil
+	}
+	h.mu.Lock()
+	sess := h.sessions[id]
+	h.mu.Unlock()
+	if sess == nil {
+		http.Error(w, fmt.Sprintf("unknown session %s", id), http.StatusNotFound)
+		return nil
+	}
+	return sess
+}
+
+// dispatch routes a server message to the HTTP request waiting for it
+func (h *Handler) dispatch(sess *session, msg jsonrpc.Message) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	var target chan jsonrpc.Message
+	if resp, ok := msg.(*jsonrpc.Response); ok {
+		target = sess.pending[idKey(resp.ID)]
+		delete(sess.pending, idKey(resp.ID))
+	} else if len(sess.streams) > 0 {
+		target = sess.streams[len(sess.streams)-1]
+	} else {
+		target = sess.get
+	}
+
+	if target == nil {
+		debug.Warn(h.Name+": Dropping server message, no open stream", debug.F("session", sess.id))
+		return
+	}
+	// Never block the connection's reader on a slow or departed client
+	select {
+	case target <- msg:
+	default:
+		debug.Warn(h.Name+": Dropping server message, stream is full", debug.F("session", sess.id))
+	}
+}
+
+// register routes the responses to ids, and optionally other server messages, to out
+func (s *session) register(ids []string, out chan jsonrpc.Message, stream bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		s.pending[id] = out
+	}
+	if stream && len(ids) > 0 {
+		s.streams = append(s.streams, out)
+	}
+}
+
+// unregister stops routing messages to out
+func (s *session) unregister(ids []string, out chan jsonrpc.Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		if s.pending[id] == out {
+			delete(s.pending, id)
+		}
+	}
+	for i, stream := range s.streams {
+		if stream == out {
+			s.streams = append(s.streams[:i], s.streams[i+1:]...)
+			break
+		}
+	}
+}
+
+// CheckOrigin reports whether a request may be served, writing an error if
+// not. Browsers send the Origin header, and a web page must not reach a
+// local server by rebinding its own host name to 127.0.0.1, so only
+// loopback origins and those in allowed are served. Requests without an
+// Origin come from other clients and are served.
+func CheckOrigin(w http.ResponseWriter, r *http.Request, allowed []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, o := range allowed {
+		if strings.EqualFold(strings.TrimSuffix(o, "/"), origin) {
+			return true
+		}
+	}
+	if u, err := url.Parse(origin); err == nil {
+		switch u.Hostname() {
+		case "localhost", "127.0.0.1", "::1":
+			return true
+		}
+	}
+	debug.Warn("Rejecting request from a disallowed origin", debug.F("origin", origin))
+	http.Error(w, fmt.Sprintf("origin %s is not allowed", origin), http.StatusForbidden)
+	return false
+}
+
+// idKey makes a map key for a JSON-RPC ID
+func idKey(id jsonrpc.ID) string {
+	return fmt.Sprintf("%T:%v", id.Raw(), id.Raw())
+}
+
+// startStream sends the headers of an event stream. They go out at once:
+// clients write one request at a time, and the next waits until this
+// response has started.
+func startStream(w http.ResponseWriter) http.Flusher {
+	flusher, _ := w.(http.Flusher)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if flusher != nil {
+		flusher.Flush()
+	}
+	return flusher
+}
+
+// writeEvent writes a message as a server-sent event and flushes it
+func writeEvent(w io.Writer, flusher http.Flusher, msg jsonrpc.Message) error {
+	data, err := transports.EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: message\ndata: %s\n\n", data); err != nil {
+		return err
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+	return nil
+}
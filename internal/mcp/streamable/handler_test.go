@@ -0,0 +1,196 @@
+package streamable
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echoConn answers every request with its method, and a "notify"
+// notification with a notification of its own
+type echoConn struct {
+	out    chan jsonrpc.Message
+	closed chan struct{}
+}
+
+func newEchoConn(context.Context) (officialMCP.Connection, error) {
+	return &echoConn{out: make(chan jsonrpc.Message, 16), closed: make(chan struct{})}, nil
+}
+
+func (c *echoConn) SessionID() string { return "" }
+
+func (c *echoConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case msg := <-c.out:
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.closed:
+		return nil, officialMCP.ErrConnectionClosed
+	}
+}
+
+func (c *echoConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	req := msg.(*jsonrpc.Request)
+	if req.ID.IsValid() {
+		result, _ := json.Marshal(map[string]string{"method": req.Method})
+		c.out <- &jsonrpc.Response{ID: req.ID, Result: result}
+	} else if req.Method == "notify" {
+		c.out <- &jsonrpc.Request{Method: "notifications/message"}
+	}
+	return nil
+}
+
+func (c *echoConn) Close() error {
+	close(c.closed)
+	return nil
+}
+
+func post(t *testing.T, url, session, accept, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", accept)
+	if session != "" {
+		req.Header.Set(SessionIDHeader, session)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestHandler(t *testing.T) {
+	handler := NewHandler(newEchoConn)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer handler.Close()
+
+	resp := post(t, server.URL, "", "application/json", `{"jsonrpc":"2.0","id":1,"method":"initialize"}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	session := resp.Header.Get(SessionIDHeader)
+	require.NotEmpty(t, session)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"result":{"method":"initialize"}}`, string(body))
+
+	// A batch answered as JSON
+	resp = post(t, server.URL, session, "application/json", `[{"jsonrpc":"2.0","id":2,"method":"a"},{"jsonrpc":"2.0","id":3,"method":"b"}]`)
+	body, _ = io.ReadAll(resp.Body)
+	assert.JSONEq(t, `[{"jsonrpc":"2.0","id":2,"result":{"method":"a"}},{"jsonrpc":"2.0","id":3,"result":{"method":"b"}}]`, string(body))
+
+	// A request answered as an event stream
+	resp = post(t, server.URL, session, "application/json, text/event-stream", `{"jsonrpc":"2.0","id":4,"method":"c"}`)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	body, _ = io.ReadAll(resp.Body)
+	assert.Equal(t, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":4,\"result\":{\"method\":\"c\"}}\n\n", string(body))
+
+	// Server messages outside a request go to the GET stream
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(SessionIDHeader, session)
+	stream, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+
+	resp = post(t, server.URL, session, "application/json", `{"jsonrpc":"2.0","method":"notify"}`)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	lines := make(chan string, 4)
+	go func() {
+		scanner := bufio.NewScanner(stream.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	for _, want := range []string{"event: message", `data: {"jsonrpc":"2.0","method":"notifications/message"}`} {
+		select {
+		case line := <-lines:
+			assert.Equal(t, want, line)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event on the GET stream")
+		}
+	}
+
+	// DELETE ends the session
+	req, err = http.NewRequest(http.MethodDelete, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set(SessionIDHeader, session)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp = post(t, server.URL, session, "application/json", `{"jsonrpc":"2.0","id":5,"method":"d"}`)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestHandlerErrors(t *testing.T) {
+	var invalid []byte
+	handler := NewHandler(newEchoConn)
+	handler.OnInvalid = func(body []byte, err error) { invalid = body }
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp := post(t, server.URL, "", "application/json", `not json`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "not json", string(invalid))
+
+	resp = post(t, server.URL, "", "application/json", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = post(t, server.URL, "", "application/json", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"pad":"`+strings.Repeat("x", MaxBodySize)+`"}}`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodPut, server.URL, nil)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestHandlerOrigin(t *testing.T) {
+	handler := NewHandler(newEchoConn)
+	handler.AllowedOrigins = []string{"https://app.example.com"}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	defer handler.Close()
+
+	tests := []struct {
+		origin string
+		want   int
+	}{
+		{"", http.StatusOK},
+		{"http://localhost:3000", http.StatusOK},
+		{"http://127.0.0.1", http.StatusOK},
+		{"http://[::1]:8080", http.StatusOK},
+		{"https://app.example.com", http.StatusOK},
+		{"https://evil.example.com", http.StatusForbidden},
+		{"http://localhost.evil.example.com", http.StatusForbidden},
+		{"null", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"initialize"}`))
+		require.NoError(t, err)
+		req.Header.Set("Accept", "application/json")
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, tt.want, resp.StatusCode, tt.origin)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -241,6 +242,60 @@ func TestTransportFallback(t *testing.T) {
 	})
 }
 
+// TestAutoTransportNegotiation tests connecting with the auto transport mode
+func TestAutoTransportNegotiation(t *testing.T) {
+	t.Run("Negotiation_Failure_Reported", func(t *testing.T) {
+		var mu sync.Mutex
+		var methods []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			methods = append(methods, r.Method)
+			mu.Unlock()
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		svc := NewService()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := svc.Connect(ctx, &config.ConnectionConfig{
+			Type: config.TransportAuto,
+			URL:  server.URL + "/assessment",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "transport negotiation failed")
+		mu.Lock()
+		probed := append([]string(nil), methods...)
+		mu.Unlock()
+		assert.Equal(t, []string{"POST", "GET"}, probed, "Should probe streamable HTTP then fall back to SSE")
+		assert.False(t, svc.IsConnected())
+
+		state := svc.(*service).ConnectionState()
+		require.NotNil(t, state)
+		assert.Equal(t, StageFailed, state.Stage)
+	})
+
+	t.Run("State_Kept_Per_Service", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// Two tabs: the second connecting must not overwrite the first's status
+		first, second := NewService(), NewService()
+		require.Error(t, first.Connect(ctx, &config.ConnectionConfig{Type: config.TransportAuto, URL: server.URL + "/first"}))
+		require.Error(t, second.Connect(ctx, &config.ConnectionConfig{Type: config.TransportAuto, URL: server.URL + "/second"}))
+
+		assert.Equal(t, server.URL+"/first", first.(*service).ConnectionState().URL)
+		assert.Equal(t, server.URL+"/second", second.(*service).ConnectionState().URL)
+		assert.Contains(t, first.GetConnectionDisplayMessage(), "Connection failed")
+		assert.Nil(t, NewService().(*service).ConnectionState())
+		assert.Equal(t, "Initializing connection...", NewService().GetConnectionDisplayMessage())
+	})
+}
+
 // detectTransportType simulates auto-detection of transport type from URL
 // This is a helper function that could be implemented in the main codebase
 func detectTransportType(url string) config.TransportType {
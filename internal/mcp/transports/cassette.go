@@ -0,0 +1,185 @@
+package transports
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+)
+
+// CassetteDirection tells whether a recorded frame was sent or received by the client
+type CassetteDirection string
+
+const (
+	CassetteSend    CassetteDirection = "send"
+	CassetteReceive CassetteDirection = "receive"
+)
+
+// CassetteEntry is one JSON-RPC frame in a cassette file. Cassettes are NDJSON:
+// one entry per line, in the order the frames crossed the transport.
+type CassetteEntry struct {
+	Time      time.Time         `json:"time"`
+	Direction CassetteDirection `json:"direction"`
+	Message   json.RawMessage   `json:"message"`
+}
+
+// Cassette is a recorded MCP session
+type Cassette struct {
+	Entries []CassetteEntry
+}
+
+// LoadCassette reads a cassette file
+func LoadCassette(path string) (*Cassette, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cassette: %w", err)
+	}
+	defer file.Close()
+
+	cassette, err := ReadCassette(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette %s: %w", path, err)
+	}
+	return cassette, nil
+}
+
+// ReadCassette parses NDJSON cassette entries from a reader
+func ReadCassette(r io.Reader) (*Cassette, error) {
+	cassette := &Cassette{}
+	decoder := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var entry CassetteEntry
+		if err := decoder.Decode(&entry); err != nil {
+			if err == io.EOF {
+				return cassette, nil
+			}
+			return nil, fmt.Errorf("entry %d: %w", line, err)
+		}
+		if entry.Direction != CassetteSend && entry.Direction != CassetteReceive {
+			return nil, fmt.Errorf("entry %d: unknown direction %q", line, entry.Direction)
+		}
+		if _, err := DecodeMessage(entry.Message); err != nil {
+			return nil, fmt.Errorf("entry %d: %w", line, err)
+		}
+		cassette.Entries = append(cassette.Entries, entry)
+	}
+}
+
+// RecordingTransport wraps another transport and writes every JSON-RPC frame
+// in both directions, with timestamps, to a cassette file
+type RecordingTransport struct {
+	inner officialMCP.Transport
+	path  string
+
+	mu      sync.Mutex
+	started bool // The file is truncated on the first connection and appended to on reconnects
+}
+
+// NewRecordingTransport creates a transport that records to the cassette at path
+func NewRecordingTransport(inner officialMCP.Transport, path string) *RecordingTransport {
+	return &RecordingTransport{inner: inner, path: path}
+}
+
+// Connect connects the wrapped transport and starts recording
+func (t *RecordingTransport) Connect(ctx context.Context) (officialMCP.Connection, error) {
+	t.mu.Lock()
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if !t.started {
+		flags |= os.O_TRUNC
+	}
+	// Cassettes hold full tool arguments and results, which may include
+	// credentials, so only the user can read them
+	file, err := os.OpenFile(t.path, flags, 0o600)
+	if err == nil && !t.started {
+		err = file.Chmod(0o600)
+		if err != nil {
+			file.Close()
+		}
+	}
+	if err == nil {
+		t.started = true
+	}
+	t.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cassette for recording: %w", err)
+	}
+
+	conn, err := t.inner.Connect(ctx)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	debug.Info("Recording MCP session", debug.F("cassette", t.path))
+	return &recordingConn{Connection: conn, file: file, writer: bufio.NewWriter(file)}, nil
+}
+
+// recordingConn tees frames of a connection into a cassette
+type recordingConn struct {
+	officialMCP.Connection
+
+	mu     sync.Mutex
+	file   *os.File
+	writer *bufio.Writer
+}
+
+// Read reads from the wrapped connection and records the frame
+func (c *recordingConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	msg, err := c.Connection.Read(ctx)
+	if err == nil {
+		c.record(CassetteReceive, msg)
+	}
+	return msg, err
+}
+
+// Write records the frame and writes it to the wrapped connection
+func (c *recordingConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	c.record(CassetteSend, msg)
+	return c.Connection.Write(ctx, msg)
+}
+
+// Close closes the wrapped connection and the cassette file
+func (c *recordingConn) Close() error {
+	err := c.Connection.Close()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file != nil {
+		c.writer.Flush()
+		c.file.Close()
+		c.file = nil
+	}
+	return err
+}
+
+// record appends a frame to the cassette, flushing so a crash keeps what was seen
+func (c *recordingConn) record(direction CassetteDirection, msg jsonrpc.Message) {
+	data, err := EncodeMessage(msg)
+	if err != nil {
+		debug.Error("Cassette: Failed to encode frame", debug.F("error", err))
+		return
+	}
+	line, err := json.Marshal(CassetteEntry{Time: time.Now().UTC(), Direction: direction, Message: data})
+	if err != nil {
+		debug.Error("Cassette: Failed to encode entry", debug.F("error", err))
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return
+	}
+	c.writer.Write(append(line, '\n'))
+	if err := c.writer.Flush(); err != nil {
+		debug.Error("Cassette: Failed to write entry", debug.F("error", err))
+	}
+}
@@ -0,0 +1,220 @@
+package transports
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// recordSession runs a short session against an in-memory SDK server through
+// the factory's recording wrapper and returns the cassette path
+func recordSession(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "session.ndjson")
+
+	server := officialMCP.NewServer(&officialMCP.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	server.AddTool(&officialMCP.Tool{Name: "echo", Description: "Echo input"},
+		func(ctx context.Context, ss *officialMCP.ServerSession, params *officialMCP.CallToolParamsFor[map[string]any]) (*officialMCP.CallToolResult, error) {
+			text, _ := params.Arguments["message"].(string)
+			return &officialMCP.CallToolResult{Content: []officialMCP.Content{&officialMCP.TextContent{Text: "echo: " + text}}}, nil
+		})
+	clientTransport, serverTransport := officialMCP.NewInMemoryTransports()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	serverSession, err := server.Connect(ctx, serverTransport)
+	if err != nil {
+		t.Fatalf("Server connect failed: %v", err)
+	}
+	defer serverSession.Close()
+
+	client := officialMCP.NewClient(&officialMCP.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, NewRecordingTransport(clientTransport, path))
+	if err != nil {
+		t.Fatalf("Client connect failed: %v", err)
+	}
+	if _, err := session.ListTools(ctx, nil); err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	if _, err := session.CallTool(ctx, &officialMCP.CallToolParams{
+		Name:      "echo",
+		Arguments: map[string]any{"message": "hello"},
+	}); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	session.Close()
+
+	return path
+}
+
+// replaySession connects a client to a cassette through the factory
+func replaySession(t *testing.T, path string, match ReplayMatch) *officialMCP.ClientSession {
+	t.Helper()
+	transport, _, err := NewFactory().CreateTransport(&TransportConfig{
+		Type:        TransportReplay,
+		URL:         path,
+		ReplayMatch: string(match),
+	})
+	if err != nil {
+		t.Fatalf("CreateTransport failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// A different client version must still replay in every mode but exact
+	client := officialMCP.NewClient(&officialMCP.Implementation{Name: "test-client", Version: "2.0.0"}, nil)
+	session, err := client.Connect(ctx, transport)
+	if err != nil {
+		t.Fatalf("Replay connect failed: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func callEcho(session *officialMCP.ClientSession, message string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := session.CallTool(ctx, &officialMCP.CallToolParams{
+		Name:      "echo",
+		Arguments: map[string]any{"message": message},
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.Content[0].(*officialMCP.TextContent).Text, nil
+}
+
+func TestRecordingTransportWritesCassette(t *testing.T) {
+	path := recordSession(t)
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette failed: %v", err)
+	}
+
+	var sends, receives int
+	for _, entry := range cassette.Entries {
+		if entry.Time.IsZero() {
+			t.Error("Expected every entry to be timestamped")
+		}
+		switch entry.Direction {
+		case CassetteSend:
+			sends++
+		case CassetteReceive:
+			receives++
+		}
+	}
+	// initialize, notifications/initialized, tools/list, tools/call and three responses
+	if sends != 4 || receives != 3 {
+		t.Errorf("Expected 4 sent and 3 received frames, got %d and %d", sends, receives)
+	}
+	if !strings.Contains(string(cassette.Entries[0].Message), `"method":"initialize"`) {
+		t.Errorf("Expected cassette to start with initialize, got %s", cassette.Entries[0].Message)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected the cassette to be readable only by its owner, got %v", info.Mode().Perm())
+	}
+}
+
+func TestReplayTransportServesCassette(t *testing.T) {
+	path := recordSession(t)
+	session := replaySession(t, path, ReplayMatchFuzzy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tools, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("Replayed ListTools failed: %v", err)
+	}
+	if len(tools.Tools) != 1 || tools.Tools[0].Name != "echo" {
+		t.Errorf("Unexpected replayed tools: %+v", tools.Tools)
+	}
+
+	text, err := callEcho(session, "hello")
+	if err != nil {
+		t.Fatalf("Replayed CallTool failed: %v", err)
+	}
+	if text != "echo: hello" {
+		t.Errorf("Expected recorded result, got %q", text)
+	}
+
+	// Repeating a request reuses its recording
+	if text, err := callEcho(session, "hello"); err != nil || text != "echo: hello" {
+		t.Errorf("Expected repeated call to replay, got %q, %v", text, err)
+	}
+
+	if err := session.Ping(ctx, nil); err != nil {
+		t.Errorf("Expected unrecorded ping to succeed, got %v", err)
+	}
+}
+
+func TestReplayTransportMatchModes(t *testing.T) {
+	path := recordSession(t)
+
+	t.Run("fuzzy rejects different params", func(t *testing.T) {
+		session := replaySession(t, path, ReplayMatchFuzzy)
+		_, err := callEcho(session, "goodbye")
+		if err == nil || !strings.Contains(err.Error(), "no recorded response for tools/call") {
+			t.Errorf("Expected no recorded response error, got %v", err)
+		}
+	})
+
+	t.Run("method ignores params", func(t *testing.T) {
+		session := replaySession(t, path, ReplayMatchMethod)
+		if text, err := callEcho(session, "goodbye"); err != nil || text != "echo: hello" {
+			t.Errorf("Expected method match to replay the recorded call, got %q, %v", text, err)
+		}
+	})
+
+	t.Run("exact requires identical initialize", func(t *testing.T) {
+		transport, _, err := NewFactory().CreateTransport(&TransportConfig{
+			Type:        TransportReplay,
+			URL:         path,
+			ReplayMatch: string(ReplayMatchExact),
+		})
+		if err != nil {
+			t.Fatalf("CreateTransport failed: %v", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		client := officialMCP.NewClient(&officialMCP.Implementation{Name: "test-client", Version: "2.0.0"}, nil)
+		if session, err := client.Connect(ctx, transport); err == nil {
+			session.Close()
+			t.Error("Expected exact match to reject a different client version")
+		}
+	})
+}
+
+func TestReplayTransportConfigValidation(t *testing.T) {
+	factory := NewFactory()
+
+	if err := factory.ValidateConfig(&TransportConfig{Type: TransportReplay}); err == nil {
+		t.Error("Expected error for replay without a cassette path")
+	}
+	if err := factory.ValidateConfig(&TransportConfig{Type: TransportReplay, URL: "c.ndjson", ReplayMatch: "loose"}); err == nil {
+		t.Error("Expected error for unknown match mode")
+	}
+	if _, _, err := factory.CreateTransport(&TransportConfig{Type: TransportReplay, URL: filepath.Join(t.TempDir(), "missing.ndjson")}); err == nil {
+		t.Error("Expected error for missing cassette")
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.ndjson")
+	os.WriteFile(bad, []byte(`{"time":"2025-01-01T00:00:00Z","direction":"sideways","message":{"jsonrpc":"2.0","id":1,"result":{}}}`+"\n"), 0644)
+	if _, err := LoadCassette(bad); err == nil {
+		t.Error("Expected error for unknown direction")
+	}
+}
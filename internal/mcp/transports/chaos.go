@@ -0,0 +1,158 @@
+package transports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/chaos"
+)
+
+// ChaosTransport wraps another transport and injects faults into its
+// JSON-RPC stream. Frames are re-encoded so the byte-level faults apply;
+// a server frame that no longer decodes ends the connection with a read
+// error, as the stdio transport does on invalid JSON. Client frames that no
+// longer decode are dropped, since the wrapped connection only carries
+// decoded messages.
+type ChaosTransport struct {
+	inner    officialMCP.Transport
+	injector *chaos.Injector
+}
+
+// NewChaosTransport creates a transport that injects faults from the injector
+func NewChaosTransport(inner officialMCP.Transport, injector *chaos.Injector) *ChaosTransport {
+	return &ChaosTransport{inner: inner, injector: injector}
+}
+
+// Connect connects the wrapped transport and starts injecting faults
+func (t *ChaosTransport) Connect(ctx context.Context) (officialMCP.Connection, error) {
+	conn, err := t.inner.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	debug.Info("Injecting faults into MCP session", debug.F("seed", t.injector.Seed()))
+	c := &chaosConn{
+		Connection: conn,
+		incoming:   make(chan jsonrpc.Message, 64),
+		done:       make(chan struct{}),
+	}
+	c.send = t.injector.Pipe(chaos.FromClient, c.writeFrame)
+	c.receive = t.injector.Pipe(chaos.FromServer, c.deliverFrame)
+	go c.readLoop()
+	return c, nil
+}
+
+// chaosConn runs the frames of a connection through the fault pipes
+type chaosConn struct {
+	officialMCP.Connection
+	send    *chaos.Pipe
+	receive *chaos.Pipe
+
+	incoming chan jsonrpc.Message
+	done     chan struct{}
+
+	failOnce sync.Once
+	readErr  error
+}
+
+// Read returns the next message that survived the fault schedule
+func (c *chaosConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case msg := <-c.incoming:
+		return msg, nil
+	case <-c.done:
+		return nil, c.readErr
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Write sends a message through the client-side fault pipe
+func (c *chaosConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	data, err := EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	if err := c.send.Send(ctx, data); err != nil {
+		if errors.Is(err, chaos.ErrDisconnect) {
+			c.Connection.Close()
+			c.fail(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// Close closes the wrapped connection
+func (c *chaosConn) Close() error {
+	c.fail(officialMCP.ErrConnectionClosed)
+	return c.Connection.Close()
+}
+
+// readLoop feeds server messages through the server-side fault pipe
+func (c *chaosConn) readLoop() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-c.done
+		cancel()
+	}()
+
+	for {
+		msg, err := c.Connection.Read(ctx)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		data, err := EncodeMessage(msg)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		if err := c.receive.Send(ctx, data); err != nil {
+			if errors.Is(err, chaos.ErrDisconnect) {
+				c.Connection.Close()
+			}
+			c.fail(err)
+			return
+		}
+	}
+}
+
+// writeFrame writes a client frame that survived the fault schedule
+func (c *chaosConn) writeFrame(frame []byte) error {
+	msg, err := DecodeMessage(frame)
+	if err != nil {
+		debug.Warn("Chaos: Dropping undecodable frame to server", debug.F("error", err))
+		return nil
+	}
+	return c.Connection.Write(context.Background(), msg)
+}
+
+// deliverFrame queues a server frame that survived the fault schedule
+func (c *chaosConn) deliverFrame(frame []byte) error {
+	msg, err := DecodeMessage(frame)
+	if err != nil {
+		c.fail(fmt.Errorf("chaos: invalid frame from server: %w", err))
+		return nil
+	}
+	select {
+	case c.incoming <- msg:
+		return nil
+	case <-c.done:
+		return c.readErr
+	}
+}
+
+// fail ends the connection's read side with err
+func (c *chaosConn) fail(err error) {
+	c.failOnce.Do(func() {
+		c.readErr = err
+		close(c.done)
+	})
+}
@@ -0,0 +1,120 @@
+package transports
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/chaos"
+)
+
+// chaosSession connects a client to an in-memory SDK server through a chaos
+// transport built from the YAML schedule
+func chaosSession(t *testing.T, schedule string) *officialMCP.ClientSession {
+	t.Helper()
+	config, err := chaos.ParseConfig([]byte(schedule))
+	if err != nil {
+		t.Fatalf("ParseConfig failed: %v", err)
+	}
+
+	server := officialMCP.NewServer(&officialMCP.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	server.AddTool(&officialMCP.Tool{Name: "echo", Description: "Echo input"},
+		func(ctx context.Context, ss *officialMCP.ServerSession, params *officialMCP.CallToolParamsFor[map[string]any]) (*officialMCP.CallToolResult, error) {
+			text, _ := params.Arguments["message"].(string)
+			return &officialMCP.CallToolResult{Content: []officialMCP.Content{&officialMCP.TextContent{Text: "echo: " + text}}}, nil
+		})
+	clientTransport, serverTransport := officialMCP.NewInMemoryTransports()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	serverSession, err := server.Connect(ctx, serverTransport)
+	if err != nil {
+		t.Fatalf("Server connect failed: %v", err)
+	}
+	t.Cleanup(func() { serverSession.Close() })
+
+	client := officialMCP.NewClient(&officialMCP.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, NewChaosTransport(clientTransport, chaos.NewInjector(config)))
+	if err != nil {
+		t.Fatalf("Client connect failed: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func TestChaosTransportSurvivableFaults(t *testing.T) {
+	session := chaosSession(t, `
+faults:
+  - {type: latency, latency: 5ms, jitter: 5ms}
+  - {type: duplicate, from: server}
+  - {type: oversize, from: server, method: tools/list, size: 65536}
+`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tools, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	if len(tools.Tools) != 1 {
+		t.Errorf("Expected 1 tool, got %d", len(tools.Tools))
+	}
+
+	result, err := session.CallTool(ctx, &officialMCP.CallToolParams{Name: "echo", Arguments: map[string]any{"message": "hi"}})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if text := result.Content[0].(*officialMCP.TextContent).Text; text != "echo: hi" {
+		t.Errorf("Expected 'echo: hi', got %q", text)
+	}
+}
+
+func TestChaosTransportCorruptResponse(t *testing.T) {
+	// Server frame 1 is the initialize response, frame 2 answers tools/list
+	session := chaosSession(t, `faults: [{type: corrupt, from: server, at: [2]}]`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := session.ListTools(ctx, nil); err == nil {
+		t.Fatal("Expected ListTools to fail on a corrupted response")
+	}
+}
+
+func TestChaosTransportDisconnect(t *testing.T) {
+	// Client frames: initialize, notifications/initialized, then tools/list
+	session := chaosSession(t, `faults: [{type: disconnect, from: client, at: [3]}]`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := session.ListTools(ctx, nil)
+	if err == nil {
+		t.Fatal("Expected ListTools to fail after an injected disconnect")
+	}
+	if !strings.Contains(err.Error(), "injected disconnect") {
+		t.Errorf("Expected an injected disconnect error, got %v", err)
+	}
+}
+
+func TestFactoryChaosPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "faults.yaml")
+	if err := os.WriteFile(path, []byte(`faults: [{type: explode}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err := NewFactory().CreateTransport(&TransportConfig{
+		Type:      TransportTCP,
+		URL:       "tcp://127.0.0.1:1",
+		ChaosPath: path,
+	})
+	if err == nil || !strings.Contains(err.Error(), "unknown fault type") {
+		t.Errorf("Expected an invalid chaos config error, got %v", err)
+	}
+}
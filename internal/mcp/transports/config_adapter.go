@@ -13,12 +13,16 @@ func FromConnectionConfig(config *configPkg.ConnectionConfig, debugMode bool, ti
 	}
 
 	transportConfig := &TransportConfig{
-		Type:      TransportType(config.Type),
-		Command:   config.Command,
-		Args:      config.Args,
-		URL:       config.URL,
-		Timeout:   timeout,
-		DebugMode: debugMode,
+		Type:        TransportType(config.Type),
+		Command:     config.Command,
+		Args:        config.Args,
+		URL:         config.URL,
+		Headers:     config.Headers,
+		ReplayMatch: config.ReplayMatch,
+		RecordPath:  config.RecordPath,
+		ChaosPath:   config.ChaosPath,
+		Timeout:     timeout,
+		DebugMode:   debugMode,
 	}
 
 	return transportConfig
@@ -35,5 +39,10 @@ func ToConnectionConfig(config *TransportConfig) *configPkg.ConnectionConfig {
 		Command: config.Command,
 		Args:    config.Args,
 		URL:     config.URL,
+		Headers: config.Headers,
+
+		RecordPath:  config.RecordPath,
+		ReplayMatch: config.ReplayMatch,
+		ChaosPath:   config.ChaosPath,
 	}
 }
@@ -56,9 +56,9 @@ func (s *httpContextStrategy) RequiresLongLivedConnection() bool {
 // NewContextStrategy creates the appropriate context strategy for a transport type
 func NewContextStrategy(transportType TransportType) ContextStrategy {
 	switch transportType {
-	case TransportSTDIO:
+	case TransportSTDIO, TransportReplay:
 		return &stdioContextStrategy{}
-	case TransportSSE:
+	case TransportSSE, TransportWebSocket, TransportUnix, TransportTCP:
 		return &sseContextStrategy{}
 	case TransportHTTP, TransportStreamableHTTP:
 		return &httpContextStrategy{}
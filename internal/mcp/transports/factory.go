@@ -3,9 +3,11 @@ package transports
 import (
 	"fmt"
 	"os/exec"
+	"strings"
 
 	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
 	configPkg "github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/chaos"
 )
 
 // factory implements the TransportFactory interface
@@ -24,6 +26,25 @@ func (f *factory) CreateTransport(config *TransportConfig) (officialMCP.Transpor
 
 	strategy := NewContextStrategy(config.Type)
 
+	transport, strategy, err := f.createTransport(config, strategy)
+	if err != nil {
+		return nil, nil, err
+	}
+	if config.ChaosPath != "" {
+		chaosConfig, err := chaos.LoadConfig(config.ChaosPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		transport = NewChaosTransport(transport, chaos.NewInjector(chaosConfig))
+	}
+	if config.RecordPath != "" {
+		transport = NewRecordingTransport(transport, config.RecordPath)
+	}
+	return transport, strategy, nil
+}
+
+// createTransport creates the transport for the configured type
+func (f *factory) createTransport(config *TransportConfig, strategy ContextStrategy) (officialMCP.Transport, ContextStrategy, error) {
 	switch config.Type {
 	case TransportSTDIO:
 		return createEnhancedSTDIOTransport(config, strategy)
@@ -33,6 +54,14 @@ func (f *factory) CreateTransport(config *TransportConfig) (officialMCP.Transpor
 		return f.createHTTPTransport(config, strategy)
 	case TransportStreamableHTTP:
 		return f.createStreamableHTTPTransport(config, strategy)
+	case TransportWebSocket:
+		return createWebSocketTransport(config, strategy)
+	case TransportUnix, TransportTCP:
+		return createSocketTransport(config, strategy)
+	case TransportReplay:
+		return createReplayTransport(config, strategy)
+	case TransportAuto:
+		return nil, nil, fmt.Errorf("auto transport must be resolved with NegotiateTransport before creating a transport")
 	default:
 		return nil, nil, fmt.Errorf("unsupported transport type: %s", config.Type)
 	}
@@ -69,28 +98,12 @@ func (f *factory) createSSETransport(config *TransportConfig, strategy ContextSt
 
 // createHTTPTransport creates an HTTP transport
 func (f *factory) createHTTPTransport(config *TransportConfig, strategy ContextStrategy) (officialMCP.Transport, ContextStrategy, error) {
-	httpClient := GetHTTPClientForTransport(TransportHTTP, config.HTTPClient)
-
-	options := &officialMCP.StreamableClientTransportOptions{
-		HTTPClient: httpClient,
-	}
-
-	transport := officialMCP.NewStreamableClientTransport(config.URL, options)
-
-	return transport, strategy, nil
+	return createStreamableTransport(config, strategy)
 }
 
 // createStreamableHTTPTransport creates a streamable HTTP transport
 func (f *factory) createStreamableHTTPTransport(config *TransportConfig, strategy ContextStrategy) (officialMCP.Transport, ContextStrategy, error) {
-	httpClient := GetHTTPClientForTransport(TransportStreamableHTTP, config.HTTPClient)
-
-	options := &officialMCP.StreamableClientTransportOptions{
-		HTTPClient: httpClient,
-	}
-
-	transport := officialMCP.NewStreamableClientTransport(config.URL, options)
-
-	return transport, strategy, nil
+	return createStreamableTransport(config, strategy)
 }
 
 // ValidateConfig validates transport configuration
@@ -111,6 +124,39 @@ func (f *factory) ValidateConfig(config *TransportConfig) error {
 			return fmt.Errorf("URL is required for %s transport", config.Type)
 		}
 
+	case TransportWebSocket:
+		if config.URL == "" {
+			return fmt.Errorf("URL is required for %s transport", config.Type)
+		}
+		if !strings.HasPrefix(config.URL, "ws://") && !strings.HasPrefix(config.URL, "wss://") {
+			return fmt.Errorf("WebSocket URL must use ws:// or wss:// scheme: %s", config.URL)
+		}
+
+	case TransportAuto:
+		if !strings.HasPrefix(config.URL, "http://") && !strings.HasPrefix(config.URL, "https://") {
+			return fmt.Errorf("auto transport requires an http:// or https:// URL: %s", config.URL)
+		}
+
+	case TransportUnix, TransportTCP:
+		if config.URL == "" {
+			return fmt.Errorf("URL is required for %s transport", config.Type)
+		}
+		network, _, err := ParseSocketAddress(config.URL)
+		if err != nil {
+			return err
+		}
+		if network != string(config.Type) {
+			return fmt.Errorf("%s transport requires a %s:// URL: %s", config.Type, config.Type, config.URL)
+		}
+
+	case TransportReplay:
+		if config.URL == "" {
+			return fmt.Errorf("cassette path is required for replay transport")
+		}
+		if _, err := ParseReplayMatch(config.ReplayMatch); err != nil {
+			return err
+		}
+
 	default:
 		return fmt.Errorf("unsupported transport type: %s", config.Type)
 	}
@@ -125,6 +171,11 @@ func (f *factory) GetSupportedTypes() []TransportType {
 		TransportSSE,
 		TransportHTTP,
 		TransportStreamableHTTP,
+		TransportWebSocket,
+		TransportUnix,
+		TransportTCP,
+		TransportAuto,
+		TransportReplay,
 	}
 }
 
@@ -139,6 +190,16 @@ func GetTransportDescription(transportType TransportType) string {
 		return "Connect via HTTP transport"
 	case TransportStreamableHTTP:
 		return "Connect via streamable HTTP transport"
+	case TransportWebSocket:
+		return "Connect via WebSocket (ws:// or wss://)"
+	case TransportUnix:
+		return "Connect via Unix domain socket (unix:///path/to.sock)"
+	case TransportTCP:
+		return "Connect via raw TCP socket (tcp://host:port)"
+	case TransportAuto:
+		return "Negotiate streamable HTTP with fallback to SSE"
+	case TransportReplay:
+		return "Replay a recorded session from a cassette file"
 	default:
 		return string(transportType)
 	}
@@ -149,9 +210,11 @@ func GetTransportReliabilityRank(transportType TransportType) int {
 	switch transportType {
 	case TransportSTDIO:
 		return 1 // Most reliable
+	case TransportUnix, TransportTCP:
+		return 2 // Same framing as STDIO over a socket
 	case TransportHTTP, TransportStreamableHTTP:
 		return 2 // Good for API-style servers
-	case TransportSSE:
+	case TransportSSE, TransportWebSocket:
 		return 3 // Works when servers implement spec correctly
 	default:
 		return 999 // Unknown
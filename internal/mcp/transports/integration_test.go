@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/standardbeagle/mcp-tui/internal/platform/process"
 )
 
 func TestEnhancedSTDIOTransportIntegration(t *testing.T) {
@@ -143,7 +145,7 @@ func TestValidateServerStartupIntegration(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := validateServerStartup(tt.command, tt.args)
+			err := validateServerStartup(tt.command, tt.args, process.Options{})
 
 			if tt.expectError {
 				if err == nil {
@@ -0,0 +1,175 @@
+package transports
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+)
+
+// The official SDK (v0.2.0) keeps its JSON-RPC wire codec internal, so transports
+// that frame messages themselves (WebSocket, sockets, cassettes) need their own
+// encoder/decoder that produces the SDK's jsonrpc.Message types.
+
+// wireMessage has all the fields of both a JSON-RPC request and response
+type wireMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *WireError      `json:"error,omitempty"`
+}
+
+// WireError is a JSON-RPC error object as it appears on the wire
+type WireError struct {
+	Code    int64           `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error implements the error interface
+func (e *WireError) Error() string {
+	return e.Message
+}
+
+// EncodeMessage serializes a JSON-RPC message to its wire form
+func EncodeMessage(msg jsonrpc.Message) ([]byte, error) {
+	wire := wireMessage{JSONRPC: "2.0"}
+
+	switch m := msg.(type) {
+	case *jsonrpc.Request:
+		wire.ID = m.ID.Raw()
+		wire.Method = m.Method
+		wire.Params = m.Params
+	case *jsonrpc.Response:
+		wire.ID = m.ID.Raw()
+		wire.Result = m.Result
+		if m.Error != nil {
+			wire.Error = toWireError(m.Error)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported JSON-RPC message type %T", msg)
+	}
+
+	data, err := json.Marshal(&wire)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON-RPC message: %w", err)
+	}
+	return data, nil
+}
+
+// DecodeMessage parses a single JSON-RPC message from its wire form
+func DecodeMessage(data []byte) (jsonrpc.Message, error) {
+	var wire wireMessage
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON-RPC message: %w", err)
+	}
+	if wire.JSONRPC != "2.0" {
+		return nil, fmt.Errorf("invalid JSON-RPC version tag %q", wire.JSONRPC)
+	}
+
+	id, err := MakeID(wire.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if wire.Method != "" {
+		return &jsonrpc.Request{
+			ID:     id,
+			Method: wire.Method,
+			Params: wire.Params,
+		}, nil
+	}
+
+	if !id.IsValid() {
+		return nil, fmt.Errorf("JSON-RPC response is missing an id")
+	}
+
+	resp := &jsonrpc.Response{
+		ID:     id,
+		Result: wire.Result,
+	}
+	// Avoid storing a typed nil in the error interface
+	if wire.Error != nil {
+		resp.Error = wire.Error
+	}
+	return resp, nil
+}
+
+// DecodeMessages parses a payload that is either a single message or a batch
+func DecodeMessages(data []byte) ([]jsonrpc.Message, error) {
+	var batch []json.RawMessage
+	if err := json.Unmarshal(data, &batch); err != nil {
+		msg, err := DecodeMessage(data)
+		if err != nil {
+			return nil, err
+		}
+		return []jsonrpc.Message{msg}, nil
+	}
+
+	if len(batch) == 0 {
+		return nil, fmt.Errorf("empty JSON-RPC batch")
+	}
+
+	msgs := make([]jsonrpc.Message, 0, len(batch))
+	for _, raw := range batch {
+		msg, err := DecodeMessage(raw)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// MakeID converts a decoded JSON id (nil, float64 or string) to a jsonrpc.ID
+func MakeID(v interface{}) (jsonrpc.ID, error) {
+	var id jsonrpc.ID
+
+	switch val := v.(type) {
+	case nil:
+		return id, nil
+	case float64:
+		setIDValue(&id, int64(val))
+	case int64:
+		setIDValue(&id, val)
+	case int:
+		setIDValue(&id, int64(val))
+	case string:
+		setIDValue(&id, val)
+	default:
+		return id, fmt.Errorf("invalid JSON-RPC id type %T", v)
+	}
+
+	return id, nil
+}
+
+// setIDValue populates the SDK's unexported ID value. The SDK only exposes ID
+// constructors in an internal package, so this is the one place we reach in.
+func setIDValue(id *jsonrpc.ID, value interface{}) {
+	field := reflect.ValueOf(id).Elem().Field(0)
+	reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem().Set(reflect.ValueOf(value))
+}
+
+// toWireError converts an arbitrary response error to its wire representation
+func toWireError(err error) *WireError {
+	if wireErr, ok := err.(*WireError); ok {
+		return wireErr
+	}
+
+	// Errors produced by the SDK marshal to {"code":...,"message":...}
+	if data, marshalErr := json.Marshal(err); marshalErr == nil {
+		var wireErr WireError
+		if json.Unmarshal(data, &wireErr) == nil && wireErr.Message != "" {
+			return &wireErr
+		}
+	}
+
+	return &WireError{
+		Code:    -32603,
+		Message: err.Error(),
+	}
+}
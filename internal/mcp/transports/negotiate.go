@@ -0,0 +1,140 @@
+package transports
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+)
+
+// negotiationProtocolVersion is the protocol version offered by the streamable HTTP probe
+const negotiationProtocolVersion = "2025-03-26"
+
+// NegotiateTransport resolves TransportAuto to a concrete transport for an HTTP
+// endpoint, following the backwards-compatibility guidance of the MCP spec: it
+// POSTs an initialize request and, if the server answers with a 4xx status,
+// falls back to the legacy SSE handshake with a GET. Each probe step is passed
+// to report (which may be nil) so callers can surface progress.
+func NegotiateTransport(ctx context.Context, config *TransportConfig, report func(step string)) (TransportType, error) {
+	if report == nil {
+		report = func(string) {}
+	}
+	if !strings.HasPrefix(config.URL, "http://") && !strings.HasPrefix(config.URL, "https://") {
+		return "", fmt.Errorf("auto transport requires an http:// or https:// URL: %s", config.URL)
+	}
+
+	client := GetHTTPClientForTransport(TransportHTTP, config.HTTPClient)
+
+	report("Probing streamable HTTP (POST initialize)...")
+	status, err := probeStreamableHTTP(ctx, client, config)
+	if err != nil {
+		return "", fmt.Errorf("streamable HTTP probe failed: %w", err)
+	}
+	if status >= 200 && status < 300 {
+		report(fmt.Sprintf("Streamable HTTP accepted (%d), using HTTP transport", status))
+		return TransportHTTP, nil
+	}
+	if status < 400 || status >= 500 {
+		return "", fmt.Errorf("streamable HTTP probe returned unexpected status %d", status)
+	}
+
+	report(fmt.Sprintf("Streamable HTTP rejected (%d), trying legacy SSE (GET)...", status))
+	if err := probeSSE(ctx, client, config); err != nil {
+		return "", fmt.Errorf("server rejected streamable HTTP with status %d and SSE fallback failed: %w", status, err)
+	}
+
+	report("Legacy SSE endpoint found, using SSE transport")
+	return TransportSSE, nil
+}
+
+// probeStreamableHTTP POSTs an initialize request and returns the response status.
+// A session opened by the probe is terminated again so the real connection starts fresh.
+func probeStreamableHTTP(ctx context.Context, client *http.Client, config *TransportConfig) (int, error) {
+	body := fmt.Sprintf(`{"jsonrpc":"2.0","id":0,"method":"initialize","params":{"protocolVersion":%q,"capabilities":{},"clientInfo":{"name":"mcp-tui","version":"probe"}}}`,
+		negotiationProtocolVersion)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.URL, bytes.NewBufferString(body))
+	if err != nil {
+		return 0, err
+	}
+	setProbeHeaders(req, config.Headers)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	debug.Info("Transport negotiation: streamable HTTP probe",
+		debug.F("url", config.URL),
+		debug.F("status", resp.StatusCode))
+
+	if sessionID := resp.Header.Get("Mcp-Session-Id"); sessionID != "" {
+		terminateProbeSession(ctx, client, config, sessionID)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// terminateProbeSession sends a best-effort DELETE for the session created by the probe
+func terminateProbeSession(ctx context.Context, client *http.Client, config *TransportConfig, sessionID string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, config.URL, nil)
+	if err != nil {
+		return
+	}
+	setProbeHeaders(req, config.Headers)
+	req.Header.Set("Mcp-Session-Id", sessionID)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.Warn("Transport negotiation: failed to terminate probe session", debug.F("error", err))
+		return
+	}
+	resp.Body.Close()
+}
+
+// probeSSE issues the legacy GET handshake and checks for an event stream
+func probeSSE(ctx context.Context, client *http.Client, config *TransportConfig) error {
+	probeCtx, cancel := context.WithCancel(ctx)
+	defer cancel() // Abandon the stream once the headers have been checked
+
+	req, err := http.NewRequestWithContext(probeCtx, http.MethodGet, config.URL, nil)
+	if err != nil {
+		return err
+	}
+	setProbeHeaders(req, config.Headers)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	debug.Info("Transport negotiation: SSE probe",
+		debug.F("url", config.URL),
+		debug.F("status", resp.StatusCode),
+		debug.F("contentType", resp.Header.Get("Content-Type")))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("GET returned status %d", resp.StatusCode)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return fmt.Errorf("GET returned %q instead of text/event-stream", resp.Header.Get("Content-Type"))
+	}
+	return nil
+}
+
+// setProbeHeaders applies user-configured headers such as Authorization
+func setProbeHeaders(req *http.Request, headers map[string]string) {
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+}
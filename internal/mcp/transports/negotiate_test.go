@@ -0,0 +1,147 @@
+package transports
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// negotiationServer records the requests made against it while probing
+type negotiationServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	methods []string
+	headers []http.Header
+}
+
+func newNegotiationServer(t *testing.T, handler http.HandlerFunc) *negotiationServer {
+	t.Helper()
+	ns := &negotiationServer{}
+	ns.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ns.mu.Lock()
+		ns.methods = append(ns.methods, r.Method)
+		ns.headers = append(ns.headers, r.Header.Clone())
+		ns.mu.Unlock()
+		handler(w, r)
+	}))
+	t.Cleanup(ns.Close)
+	return ns
+}
+
+func (ns *negotiationServer) requestMethods() string {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	return strings.Join(ns.methods, ",")
+}
+
+func negotiate(t *testing.T, url string) (TransportType, []string, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var steps []string
+	transport, err := NegotiateTransport(ctx, &TransportConfig{
+		Type:    TransportAuto,
+		URL:     url,
+		Headers: map[string]string{"Authorization": "Bearer probe"},
+	}, func(step string) {
+		steps = append(steps, step)
+	})
+	return transport, steps, err
+}
+
+func TestNegotiateTransportStreamableHTTP(t *testing.T) {
+	server := newNegotiationServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Mcp-Session-Id", "probe-session")
+			w.Write([]byte(`{"jsonrpc":"2.0","id":0,"result":{}}`))
+		case http.MethodDelete:
+			if r.Header.Get("Mcp-Session-Id") != "probe-session" {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	transport, steps, err := negotiate(t, server.URL+"/mcp")
+	if err != nil {
+		t.Fatalf("NegotiateTransport failed: %v", err)
+	}
+	if transport != TransportHTTP {
+		t.Errorf("Expected HTTP transport, got %s", transport)
+	}
+	if got := server.requestMethods(); got != "POST,DELETE" {
+		t.Errorf("Expected probe POST followed by session DELETE, got %s", got)
+	}
+	if server.headers[0].Get("Authorization") != "Bearer probe" {
+		t.Error("Expected configured headers on probe request")
+	}
+	if len(steps) != 2 {
+		t.Errorf("Expected 2 probe steps, got %v", steps)
+	}
+}
+
+func TestNegotiateTransportFallsBackToSSE(t *testing.T) {
+	server := newNegotiationServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("event: endpoint\ndata: /messages?sessionId=1\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done() // Hold the stream open like a real SSE server
+	})
+
+	transport, steps, err := negotiate(t, server.URL+"/assessment")
+	if err != nil {
+		t.Fatalf("NegotiateTransport failed: %v", err)
+	}
+	if transport != TransportSSE {
+		t.Errorf("Expected SSE transport, got %s", transport)
+	}
+	if got := server.requestMethods(); got != "POST,GET" {
+		t.Errorf("Expected POST then GET, got %s", got)
+	}
+	if len(steps) != 3 || !strings.Contains(steps[1], "405") {
+		t.Errorf("Expected probe steps to report the 405 fallback, got %v", steps)
+	}
+}
+
+func TestNegotiateTransportFailures(t *testing.T) {
+	t.Run("no SSE endpoint", func(t *testing.T) {
+		server := newNegotiationServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		if _, _, err := negotiate(t, server.URL); err == nil {
+			t.Error("Expected error when both probes fail")
+		}
+	})
+
+	t.Run("server error does not fall back", func(t *testing.T) {
+		server := newNegotiationServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		if _, _, err := negotiate(t, server.URL); err == nil {
+			t.Error("Expected error for 5xx probe response")
+		}
+		if got := server.requestMethods(); got != "POST" {
+			t.Errorf("Expected no SSE fallback after 5xx, got %s", got)
+		}
+	})
+
+	t.Run("non-HTTP URL", func(t *testing.T) {
+		if _, _, err := negotiate(t, "ws://localhost/mcp"); err == nil {
+			t.Error("Expected error for non-HTTP URL")
+		}
+	})
+}
@@ -0,0 +1,295 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+)
+
+// ReplayMatch controls how live requests are matched against recorded ones
+type ReplayMatch string
+
+const (
+	// ReplayMatchExact requires the method and params to be identical
+	ReplayMatchExact ReplayMatch = "exact"
+	// ReplayMatchFuzzy compares params without _meta, and matches initialize by
+	// method only since client info and capabilities change between builds
+	ReplayMatchFuzzy ReplayMatch = "fuzzy"
+	// ReplayMatchMethod matches on the method name alone
+	ReplayMatchMethod ReplayMatch = "method"
+)
+
+// DefaultReplayMatch is used when no match mode is configured
+const DefaultReplayMatch = ReplayMatchFuzzy
+
+// ParseReplayMatch validates a match mode, mapping "" to the default
+func ParseReplayMatch(s string) (ReplayMatch, error) {
+	switch ReplayMatch(s) {
+	case "":
+		return DefaultReplayMatch, nil
+	case ReplayMatchExact, ReplayMatchFuzzy, ReplayMatchMethod:
+		return ReplayMatch(s), nil
+	default:
+		return "", fmt.Errorf("invalid replay match mode %q (expected exact, fuzzy or method)", s)
+	}
+}
+
+// replayInteraction is a recorded request with the frames the server sent for it
+type replayInteraction struct {
+	method string
+	params json.RawMessage
+	id     jsonrpc.ID
+	frames []jsonrpc.Message // Server notifications and requests, then the response
+	used   bool
+}
+
+// ReplayTransport serves a recorded cassette as if it were the server
+type ReplayTransport struct {
+	cassette *Cassette
+	match    ReplayMatch
+}
+
+// NewReplayTransport creates a transport that answers requests from a cassette
+func NewReplayTransport(cassette *Cassette, match ReplayMatch) *ReplayTransport {
+	if match == "" {
+		match = DefaultReplayMatch
+	}
+	return &ReplayTransport{cassette: cassette, match: match}
+}
+
+// createReplayTransport loads the cassette named by the config URL
+func createReplayTransport(config *TransportConfig, strategy ContextStrategy) (officialMCP.Transport, ContextStrategy, error) {
+	match, err := ParseReplayMatch(config.ReplayMatch)
+	if err != nil {
+		return nil, nil, err
+	}
+	cassette, err := LoadCassette(config.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewReplayTransport(cassette, match), strategy, nil
+}
+
+// Connect starts a replay session. Every connection replays the cassette from the start.
+func (t *ReplayTransport) Connect(ctx context.Context) (officialMCP.Connection, error) {
+	interactions, err := buildInteractions(t.cassette)
+	if err != nil {
+		return nil, err
+	}
+	debug.Info("Replaying MCP session",
+		debug.F("interactions", len(interactions)),
+		debug.F("match", t.match))
+	return &replayConn{
+		interactions: interactions,
+		match:        t.match,
+		ready:        make(chan struct{}, 1),
+		done:         make(chan struct{}),
+	}, nil
+}
+
+// buildInteractions groups cassette frames by the request they belong to.
+// Frames the server sent are attached to the latest request sent before them,
+// except responses, which are attached to the request with the same ID.
+func buildInteractions(cassette *Cassette) ([]*replayInteraction, error) {
+	var interactions []*replayInteraction
+	byID := make(map[string]*replayInteraction)
+
+	for i, entry := range cassette.Entries {
+		msg, err := DecodeMessage(entry.Message)
+		if err != nil {
+			return nil, fmt.Errorf("cassette entry %d: %w", i+1, err)
+		}
+
+		if entry.Direction == CassetteSend {
+			req, ok := msg.(*jsonrpc.Request)
+			if !ok || !req.ID.IsValid() {
+				continue // Client notifications and responses need no answer
+			}
+			interaction := &replayInteraction{method: req.Method, params: req.Params, id: req.ID}
+			interactions = append(interactions, interaction)
+			byID[idKey(req.ID)] = interaction
+			continue
+		}
+
+		if resp, ok := msg.(*jsonrpc.Response); ok {
+			if interaction := byID[idKey(resp.ID)]; interaction != nil {
+				interaction.frames = append(interaction.frames, resp)
+			}
+			continue
+		}
+		if len(interactions) > 0 {
+			last := interactions[len(interactions)-1]
+			last.frames = append(last.frames, msg)
+		}
+	}
+
+	return interactions, nil
+}
+
+// replayConn answers writes with recorded frames
+type replayConn struct {
+	interactions []*replayInteraction
+	match        ReplayMatch
+
+	mu      sync.Mutex
+	pending []jsonrpc.Message
+	ready   chan struct{} // Signalled when pending grows
+	done    chan struct{}
+	closed  bool
+}
+
+// SessionID returns an empty session ID
+func (c *replayConn) SessionID() string {
+	return ""
+}
+
+// Read returns the next replayed frame
+func (c *replayConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	for {
+		c.mu.Lock()
+		if len(c.pending) > 0 {
+			msg := c.pending[0]
+			c.pending = c.pending[1:]
+			c.mu.Unlock()
+			return msg, nil
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-c.ready:
+		case <-c.done:
+			return nil, io.EOF
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Write matches a request against the cassette and queues the recorded answer
+func (c *replayConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return officialMCP.ErrConnectionClosed
+	}
+
+	req, ok := msg.(*jsonrpc.Request)
+	if !ok || !req.ID.IsValid() {
+		return nil // Notifications and responses to server requests are consumed
+	}
+
+	interaction := c.find(req)
+	if interaction == nil {
+		c.push(unmatchedResponse(req))
+		return nil
+	}
+
+	for _, frame := range interaction.frames {
+		if resp, ok := frame.(*jsonrpc.Response); ok {
+			// Answer with the live request ID, not the recorded one
+			frame = &jsonrpc.Response{ID: req.ID, Result: resp.Result, Error: resp.Error}
+		}
+		c.push(frame)
+	}
+	return nil
+}
+
+// Close ends the replay session
+func (c *replayConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.done)
+	}
+	return nil
+}
+
+// push queues a frame for Read. Must be called with mu held.
+func (c *replayConn) push(msg jsonrpc.Message) {
+	c.pending = append(c.pending, msg)
+	select {
+	case c.ready <- struct{}{}:
+	default:
+	}
+}
+
+// find returns the first unused matching interaction, or reuses the last match
+// when a request is repeated more often than it was recorded. Must be called with mu held.
+func (c *replayConn) find(req *jsonrpc.Request) *replayInteraction {
+	var reuse *replayInteraction
+	for _, interaction := range c.interactions {
+		if !c.matches(interaction, req) {
+			continue
+		}
+		if !interaction.used {
+			interaction.used = true
+			return interaction
+		}
+		reuse = interaction
+	}
+	return reuse
+}
+
+// matches reports whether a recorded interaction answers a live request
+func (c *replayConn) matches(interaction *replayInteraction, req *jsonrpc.Request) bool {
+	if interaction.method != req.Method {
+		return false
+	}
+
+	switch c.match {
+	case ReplayMatchMethod:
+		return true
+	case ReplayMatchFuzzy:
+		if req.Method == "initialize" {
+			return true
+		}
+		return paramsEqual(interaction.params, req.Params, true)
+	default:
+		return paramsEqual(interaction.params, req.Params, false)
+	}
+}
+
+// paramsEqual compares params structurally, optionally ignoring _meta
+func paramsEqual(recorded, live json.RawMessage, ignoreMeta bool) bool {
+	a, errA := decodeParams(recorded, ignoreMeta)
+	b, errB := decodeParams(live, ignoreMeta)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// decodeParams decodes params, treating absent and empty params alike
+func decodeParams(raw json.RawMessage, ignoreMeta bool) (interface{}, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return map[string]interface{}{}, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	if obj, ok := v.(map[string]interface{}); ok && ignoreMeta {
+		delete(obj, "_meta")
+	}
+	return v, nil
+}
+
+// unmatchedResponse answers a request the cassette has no recording for
+func unmatchedResponse(req *jsonrpc.Request) *jsonrpc.Response {
+	if req.Method == "ping" {
+		return &jsonrpc.Response{ID: req.ID, Result: json.RawMessage(`{}`)}
+	}
+	debug.Warn("Replay: No recorded response", debug.F("method", req.Method))
+	return &jsonrpc.Response{ID: req.ID, Error: &WireError{
+		Code:    -32603,
+		Message: fmt.Sprintf("replay: no recorded response for %s", req.Method),
+	}}
+}
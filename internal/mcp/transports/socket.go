@@ -0,0 +1,83 @@
+package transports
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+)
+
+// SocketTransport connects to MCP servers listening on a Unix domain socket or a
+// raw TCP port, speaking newline-delimited JSON-RPC like the stdio transport.
+type SocketTransport struct {
+	network string // "unix" or "tcp"
+	address string
+	timeout time.Duration
+}
+
+// createSocketTransport creates a socket transport from the transport configuration
+func createSocketTransport(config *TransportConfig, strategy ContextStrategy) (officialMCP.Transport, ContextStrategy, error) {
+	network, address, err := ParseSocketAddress(config.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transport := &SocketTransport{
+		network: network,
+		address: address,
+		timeout: config.Timeout,
+	}
+
+	return transport, strategy, nil
+}
+
+// ParseSocketAddress splits a unix:// or tcp:// connection string into the
+// network and address expected by net.Dial
+func ParseSocketAddress(rawURL string) (network, address string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid socket URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "unix":
+		// unix:///abs/path.sock has an empty host; unix://./rel.sock puts "." in the host
+		address = u.Host + u.Path
+		if address == "" {
+			return "", "", fmt.Errorf("socket path is required: %s", rawURL)
+		}
+		return "unix", address, nil
+	case "tcp":
+		if u.Host == "" || u.Port() == "" {
+			return "", "", fmt.Errorf("host and port are required: %s", rawURL)
+		}
+		return "tcp", u.Host, nil
+	default:
+		return "", "", fmt.Errorf("socket URL must use unix:// or tcp:// scheme: %s", rawURL)
+	}
+}
+
+// Connect dials the socket and returns a newline-delimited JSON connection
+func (t *SocketTransport) Connect(ctx context.Context) (officialMCP.Connection, error) {
+	dialer := net.Dialer{Timeout: t.timeout}
+
+	debug.Info("Socket: Dialing server",
+		debug.F("network", t.network),
+		debug.F("address", t.address))
+
+	conn, err := dialer.DialContext(ctx, t.network, t.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s socket %s: %w", t.network, t.address, err)
+	}
+
+	debug.Info("Socket: Connection established",
+		debug.F("network", t.network),
+		debug.F("address", t.address),
+		debug.F("localAddr", conn.LocalAddr().String()))
+
+	return newStreamConn(conn), nil
+}
@@ -0,0 +1,177 @@
+package transports
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+)
+
+// serveLineEcho accepts connections on a listener and echoes each line back
+func serveLineEcho(t *testing.T, listener net.Listener) {
+	t.Helper()
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					if _, err := conn.Write(append(scanner.Bytes(), '\n')); err != nil {
+						return
+					}
+				}
+			}(conn)
+		}
+	}()
+}
+
+func testSocketEcho(t *testing.T, config *TransportConfig) {
+	t.Helper()
+
+	transport, strategy, err := NewFactory().CreateTransport(config)
+	if err != nil {
+		t.Fatalf("CreateTransport failed: %v", err)
+	}
+	if !strategy.RequiresLongLivedConnection() {
+		t.Error("Expected socket transport to use a long-lived connection strategy")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := transport.Connect(ctx)
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer conn.Close()
+
+	for i := int64(1); i <= 3; i++ {
+		if err := conn.Write(ctx, newTestRequest(t, i, "tools/list")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	for i := int64(1); i <= 3; i++ {
+		msg, err := conn.Read(ctx)
+		if err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+		req, ok := msg.(*jsonrpc.Request)
+		if !ok {
+			t.Fatalf("Expected echoed request, got %T", msg)
+		}
+		if req.ID.Raw() != i || req.Method != "tools/list" {
+			t.Errorf("Expected request %d tools/list, got %v %q", i, req.ID.Raw(), req.Method)
+		}
+	}
+}
+
+func TestUnixSocketTransportEcho(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("Unix sockets unavailable: %v", err)
+	}
+	serveLineEcho(t, listener)
+
+	testSocketEcho(t, &TransportConfig{
+		Type:    TransportUnix,
+		URL:     "unix://" + path,
+		Timeout: 5 * time.Second,
+	})
+}
+
+func TestTCPTransportEcho(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	serveLineEcho(t, listener)
+
+	testSocketEcho(t, &TransportConfig{
+		Type:    TransportTCP,
+		URL:     "tcp://" + listener.Addr().String(),
+		Timeout: 5 * time.Second,
+	})
+}
+
+func TestSocketTransportServerClose(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			conn.Close()
+		}
+	}()
+
+	transport, _, err := NewFactory().CreateTransport(&TransportConfig{
+		Type: TransportTCP,
+		URL:  "tcp://" + listener.Addr().String(),
+	})
+	if err != nil {
+		t.Fatalf("CreateTransport failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := transport.Connect(ctx)
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Read(ctx); err == nil {
+		t.Error("Expected read to fail after server closed the connection")
+	}
+}
+
+func TestParseSocketAddress(t *testing.T) {
+	tests := []struct {
+		url     string
+		network string
+		address string
+		wantErr bool
+	}{
+		{"unix:///tmp/mcp.sock", "unix", "/tmp/mcp.sock", false},
+		{"unix://./mcp.sock", "unix", "./mcp.sock", false},
+		{"tcp://localhost:9000", "tcp", "localhost:9000", false},
+		{"tcp://localhost", "", "", true},
+		{"unix://", "", "", true},
+		{"http://localhost:9000", "", "", true},
+	}
+
+	for _, tt := range tests {
+		network, address, err := ParseSocketAddress(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSocketAddress(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			continue
+		}
+		if network != tt.network || address != tt.address {
+			t.Errorf("ParseSocketAddress(%q) = %q, %q, want %q, %q", tt.url, network, address, tt.network, tt.address)
+		}
+	}
+
+	f := NewFactory()
+	if err := f.ValidateConfig(&TransportConfig{Type: TransportUnix, URL: "tcp://localhost:9000"}); err == nil {
+		t.Error("Expected error for mismatched socket scheme")
+	}
+	if err := f.ValidateConfig(&TransportConfig{Type: TransportTCP, URL: "tcp://localhost:9000"}); err != nil {
+		t.Errorf("Unexpected error for tcp URL: %v", err)
+	}
+}
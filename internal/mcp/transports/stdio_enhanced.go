@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"os/exec"
 	"strings"
 	"time"
 
@@ -12,6 +11,7 @@ import (
 	configPkg "github.com/standardbeagle/mcp-tui/internal/config"
 	"github.com/standardbeagle/mcp-tui/internal/debug"
 	"github.com/standardbeagle/mcp-tui/internal/mcp/errors"
+	"github.com/standardbeagle/mcp-tui/internal/platform/process"
 )
 
 // ServerStartupError represents a server startup failure with captured output
@@ -32,7 +32,7 @@ func (e *ServerStartupError) Error() string {
 		e.Command, e.Output)
 }
 
-// EnhancedSTDIOTransport wraps the official MCP STDIO transport with pre-flight validation
+// EnhancedSTDIOTransport wraps the managed STDIO transport with pre-flight validation
 type EnhancedSTDIOTransport struct {
 	transport officialMCP.Transport
 	command   string
@@ -50,18 +50,23 @@ func createEnhancedSTDIOTransport(config *TransportConfig, strategy ContextStrat
 		debug.F("command", config.Command),
 		debug.F("args", config.Args))
 
+	options := processOptions(config)
+
 	// Perform pre-flight server validation
-	if err := validateServerStartup(config.Command, config.Args); err != nil {
+	if err := validateServerStartup(config.Command, config.Args, options); err != nil {
 		return nil, nil, err
 	}
 
 	debug.Info("Enhanced STDIO: Pre-flight validation successful, creating transport")
 
-	// Create command for STDIO transport
-	cmd := exec.Command(config.Command, config.Args...)
-
-	// Create STDIO transport using official SDK
-	transport := officialMCP.NewCommandTransport(cmd)
+	// Launch the server under the process manager so its whole process group
+	// is killed on disconnect
+	transport := &processTransport{
+		command:  config.Command,
+		args:     config.Args,
+		options:  options,
+		observer: config.ProcessObserver,
+	}
 
 	// Wrap in enhanced transport for additional monitoring
 	enhanced := &EnhancedSTDIOTransport{
@@ -74,17 +79,11 @@ func createEnhancedSTDIOTransport(config *TransportConfig, strategy ContextStrat
 }
 
 // validateServerStartup performs pre-flight validation of server startup
-func validateServerStartup(command string, args []string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	// Create command for validation
-	cmd := exec.CommandContext(ctx, command, args...)
-
+func validateServerStartup(command string, args []string, opts process.Options) error {
 	// Capture both stdout and stderr
 	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	opts.Stdout = &stdout
+	opts.Stderr = &stderr
 
 	debug.Info("Enhanced STDIO: Running pre-flight server validation",
 		debug.F("command", command),
@@ -92,12 +91,26 @@ func validateServerStartup(command string, args []string) error {
 		debug.F("timeout", "5s"))
 
 	// Start the process
-	if err := cmd.Start(); err != nil {
+	proc, err := serverProcesses().StartWithOptions(context.Background(), command, args, opts)
+	if err != nil {
 		return fmt.Errorf("failed to start server command: %w", err)
 	}
+	// Kill the process group even if the server exited, so that children it
+	// spawned (the node process behind npx, say) do not linger
+	defer proc.Kill()
 
 	// Wait for the process to complete or timeout
-	err := cmd.Wait()
+	done := make(chan error, 1)
+	go func() {
+		done <- proc.Wait()
+	}()
+	select {
+	case err = <-done:
+	case <-time.After(5 * time.Second):
+		proc.Kill()
+		err = <-done
+	}
+	exitCode, _ := proc.ExitCode()
 
 	// Capture all output
 	stdoutStr := strings.TrimSpace(stdout.String())
@@ -105,17 +118,12 @@ func validateServerStartup(command string, args []string) error {
 	combinedOutput := strings.TrimSpace(stdoutStr + "\n" + stderrStr)
 
 	debug.Info("Enhanced STDIO: Pre-flight validation complete",
-		debug.F("exitCode", cmd.ProcessState.ExitCode()),
+		debug.F("exitCode", exitCode),
 		debug.F("stdoutLen", len(stdoutStr)),
 		debug.F("stderrLen", len(stderrStr)))
 
 	// If the process exited with an error, analyze the output
 	if err != nil {
-		var exitCode int
-		if cmd.ProcessState != nil {
-			exitCode = cmd.ProcessState.ExitCode()
-		}
-
 		// Check if this looks like a startup error vs. a successful server that terminated
 		if isServerStartupError(combinedOutput, exitCode) {
 			suggestion := generateSuggestion(combinedOutput)
@@ -0,0 +1,189 @@
+package transports
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/platform/process"
+)
+
+// ProcessEventType identifies what happened to a stdio server process
+type ProcessEventType string
+
+const (
+	ProcessEventStarted ProcessEventType = "started"
+	ProcessEventExited  ProcessEventType = "exited"
+)
+
+// ProcessEvent reports the lifecycle of a stdio server process so callers can
+// show how it ended
+type ProcessEvent struct {
+	Type     ProcessEventType
+	PID      int
+	ExitCode int    // -1 when the process was killed by a signal
+	Signal   string // Terminating signal, e.g. SIGKILL, if any
+	Err      error
+}
+
+var (
+	serverManagerMu sync.Mutex
+	serverManager   process.Manager
+)
+
+// serverProcesses returns the manager that owns every stdio server process
+func serverProcesses() process.Manager {
+	serverManagerMu.Lock()
+	defer serverManagerMu.Unlock()
+
+	if serverManager == nil {
+		serverManager = process.NewPlatformManager(context.Background())
+	}
+	return serverManager
+}
+
+// CloseServerProcesses kills every stdio server still running, together with
+// the children it spawned. Call it before exiting so that no server outlives
+// mcp-tui.
+func CloseServerProcesses() error {
+	serverManagerMu.Lock()
+	manager := serverManager
+	serverManagerMu.Unlock()
+
+	if manager == nil {
+		return nil
+	}
+	return manager.KillAll()
+}
+
+// processOptions builds the process options for a stdio server
+func processOptions(config *TransportConfig) process.Options {
+	env := make([]string, 0, len(config.Environment))
+	for key, value := range config.Environment {
+		env = append(env, key+"="+value)
+	}
+	sort.Strings(env)
+
+	return process.Options{
+		Dir:         config.WorkingDirectory,
+		Env:         env,
+		Limits:      config.Limits,
+		KillTimeout: config.KillTimeout,
+	}
+}
+
+// processTransport runs a stdio server under the process manager, in its own
+// process group and with resource limits, speaking newline-delimited JSON
+// over its stdin and stdout
+type processTransport struct {
+	command  string
+	args     []string
+	options  process.Options
+	observer func(ProcessEvent)
+}
+
+// Connect starts the server process and connects to its standard streams
+func (t *processTransport) Connect(ctx context.Context) (officialMCP.Connection, error) {
+	stdinReader, stdinWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		stdinReader.Close()
+		stdinWriter.Close()
+		return nil, err
+	}
+
+	opts := t.options
+	opts.Stdin = stdinReader
+	opts.Stdout = stdoutWriter
+	proc, err := serverProcesses().StartWithOptions(context.Background(), t.command, t.args, opts)
+
+	// The server holds its own copies of its ends of the pipes
+	stdinReader.Close()
+	stdoutWriter.Close()
+	if err != nil {
+		stdinWriter.Close()
+		stdoutReader.Close()
+		return nil, fmt.Errorf("failed to start server command: %w", err)
+	}
+
+	debug.Info("STDIO: Server process started",
+		debug.F("pid", proc.PID()),
+		debug.F("command", t.command))
+	t.notify(ProcessEvent{Type: ProcessEventStarted, PID: proc.PID()})
+
+	go func() {
+		err := proc.Wait()
+		evt := ProcessEvent{Type: ProcessEventExited, PID: proc.PID(), Err: err}
+		evt.ExitCode, _ = proc.ExitCode()
+		evt.Signal, _ = proc.ExitSignal()
+		debug.Info("STDIO: Server process exited",
+			debug.F("pid", evt.PID),
+			debug.F("exitCode", evt.ExitCode),
+			debug.F("signal", evt.Signal))
+		t.notify(evt)
+	}()
+
+	pipe := &processPipe{
+		proc:        proc,
+		stdin:       stdinWriter,
+		stdout:      stdoutReader,
+		killTimeout: t.options.KillTimeout,
+	}
+	if pipe.killTimeout <= 0 {
+		pipe.killTimeout = process.DefaultKillTimeout
+	}
+	return newStreamConn(pipe), nil
+}
+
+// notify reports a process event to the observer, if any
+func (t *processTransport) notify(evt ProcessEvent) {
+	if t.observer != nil {
+		t.observer(evt)
+	}
+}
+
+// processPipe is the byte stream to a server process
+type processPipe struct {
+	proc        process.Process
+	stdin       *os.File
+	stdout      *os.File
+	killTimeout time.Duration
+}
+
+func (p *processPipe) Read(b []byte) (int, error) {
+	return p.stdout.Read(b)
+}
+
+func (p *processPipe) Write(b []byte) (int, error) {
+	return p.stdin.Write(b)
+}
+
+// Close shuts the server down as the stdio transport specifies: its input is
+// closed and it is given time to exit before being terminated. The process
+// group is killed either way, so children the server spawned (for example
+// the node process behind npx) do not outlive the connection.
+func (p *processPipe) Close() error {
+	p.stdin.Close()
+
+	exited := make(chan struct{})
+	go func() {
+		p.proc.Wait()
+		close(exited)
+	}()
+	select {
+	case <-exited:
+	case <-time.After(p.killTimeout):
+	}
+
+	err := p.proc.Kill()
+	p.stdout.Close()
+	return err
+}
@@ -0,0 +1,188 @@
+//go:build linux
+// +build linux
+
+package transports
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/standardbeagle/mcp-tui/internal/platform/process"
+)
+
+// processAlive reports whether pid exists and is not a zombie
+func processAlive(pid int) bool {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return false
+	}
+	fields := strings.Fields(string(data[bytes.LastIndexByte(data, ')')+1:]))
+	return len(fields) > 0 && fields[0] != "Z"
+}
+
+// processRecorder collects process events from a transport
+type processRecorder struct {
+	mu     sync.Mutex
+	events []ProcessEvent
+}
+
+func (r *processRecorder) record(evt ProcessEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, evt)
+}
+
+// waitFor returns the first event of the given type, waiting for it to arrive
+func (r *processRecorder) waitFor(t *testing.T, eventType ProcessEventType) ProcessEvent {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		r.mu.Lock()
+		for _, evt := range r.events {
+			if evt.Type == eventType {
+				r.mu.Unlock()
+				return evt
+			}
+		}
+		r.mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("No %s process event", eventType)
+	return ProcessEvent{}
+}
+
+func TestSTDIOTransportEcho(t *testing.T) {
+	var recorder processRecorder
+	transport, _, err := NewFactory().CreateTransport(&TransportConfig{
+		Type:            TransportSTDIO,
+		Command:         "cat",
+		ProcessObserver: recorder.record,
+	})
+	if err != nil {
+		t.Fatalf("CreateTransport failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := transport.Connect(ctx)
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	if err := conn.Write(ctx, newTestRequest(t, 1, "tools/list")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	msg, err := conn.Read(ctx)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if req, ok := msg.(*jsonrpc.Request); !ok || req.Method != "tools/list" {
+		t.Errorf("Expected echoed tools/list request, got %#v", msg)
+	}
+
+	started := recorder.waitFor(t, ProcessEventStarted)
+	if started.PID == 0 {
+		t.Error("Expected the started event to carry the PID")
+	}
+
+	// cat exits by itself once its input is closed
+	if err := conn.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	exited := recorder.waitFor(t, ProcessEventExited)
+	if exited.PID != started.PID || exited.ExitCode != 0 || exited.Signal != "" {
+		t.Errorf("Expected a clean exit of %d, got %+v", started.PID, exited)
+	}
+}
+
+func TestSTDIOTransportKillsProcessGroup(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	var recorder processRecorder
+	transport := &processTransport{
+		command: "sh",
+		// The server spawns a child and ignores SIGTERM, as a wrapper like npx might
+		args:     []string{"-c", `trap "" TERM; sleep 30 & echo $! > ` + pidFile + `; while read line; do echo "$line"; done; sleep 30`},
+		options:  process.Options{KillTimeout: 200 * time.Millisecond},
+		observer: recorder.record,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := transport.Connect(ctx)
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+
+	var child int
+	for child == 0 && ctx.Err() == nil {
+		data, _ := os.ReadFile(pidFile)
+		child, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !processAlive(child) {
+		t.Fatalf("Child process %d is not running", child)
+	}
+
+	conn.Close()
+
+	exited := recorder.waitFor(t, ProcessEventExited)
+	if exited.Signal != "SIGKILL" || exited.ExitCode != -1 {
+		t.Errorf("Expected the server to be killed with SIGKILL, got %+v", exited)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for processAlive(child) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if processAlive(child) {
+		t.Errorf("Child process %d outlived the connection", child)
+	}
+}
+
+func TestSTDIOTransportLimits(t *testing.T) {
+	var recorder processRecorder
+	transport := &processTransport{
+		command:  "cat",
+		options:  process.Options{Limits: process.Limits{CPUSeconds: 30, OpenFiles: 128}},
+		observer: recorder.record,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := transport.Connect(ctx)
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer conn.Close()
+
+	started := recorder.waitFor(t, ProcessEventStarted)
+
+	// The limits are set by a shim that then execs cat under them
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if exe, _ := os.Readlink(fmt.Sprintf("/proc/%d/exe", started.PID)); filepath.Base(exe) == "cat" {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/limits", started.PID))
+	if err != nil {
+		t.Fatalf("Reading limits failed: %v", err)
+	}
+	for _, pattern := range []string{`Max cpu time\s+30\s+30`, `Max open files\s+128\s+128`} {
+		if !regexp.MustCompile(pattern).Match(data) {
+			t.Errorf("Expected limits to match %q:\n%s", pattern, data)
+		}
+	}
+}
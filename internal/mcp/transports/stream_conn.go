@@ -0,0 +1,135 @@
+package transports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+)
+
+// streamConn implements officialMCP.Connection over any byte stream using
+// newline-delimited JSON, the same framing the stdio transport uses.
+type streamConn struct {
+	rwc       io.ReadWriteCloser
+	sessionID string
+
+	writeMu sync.Mutex
+
+	incoming chan jsonrpc.Message
+	done     chan struct{}
+
+	mu        sync.Mutex
+	readErr   error
+	closed    bool
+	closeOnce sync.Once
+}
+
+// newStreamConn wraps a byte stream and starts decoding messages from it
+func newStreamConn(rwc io.ReadWriteCloser) *streamConn {
+	c := &streamConn{
+		rwc:      rwc,
+		incoming: make(chan jsonrpc.Message, 64),
+		done:     make(chan struct{}),
+	}
+	go c.readLoop()
+	return c
+}
+
+// SessionID returns the session ID, which is empty for raw streams
+func (c *streamConn) SessionID() string {
+	return c.sessionID
+}
+
+// Read returns the next decoded message
+func (c *streamConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case msg, ok := <-c.incoming:
+		if ok {
+			return msg, nil
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.readErr != nil {
+			return nil, c.readErr
+		}
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Write encodes a message and writes it followed by a newline
+func (c *streamConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	data, err := EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	closed := c.closed
+	c.mu.Unlock()
+	if closed {
+		return officialMCP.ErrConnectionClosed
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if _, err := c.rwc.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Close closes the underlying stream
+func (c *streamConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.closed = true
+		c.mu.Unlock()
+		close(c.done)
+		err = c.rwc.Close()
+	})
+	return err
+}
+
+// readLoop decodes messages until the stream ends
+func (c *streamConn) readLoop() {
+	defer close(c.incoming)
+
+	decoder := json.NewDecoder(c.rwc)
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			c.mu.Lock()
+			if !c.closed && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				c.readErr = err
+			}
+			c.mu.Unlock()
+			return
+		}
+
+		msgs, err := DecodeMessages(raw)
+		if err != nil {
+			debug.Error("Stream: Failed to decode JSON-RPC message",
+				debug.F("error", err),
+				debug.F("data", string(raw)))
+			continue
+		}
+
+		for _, msg := range msgs {
+			select {
+			case c.incoming <- msg:
+			case <-c.done:
+				return
+			}
+		}
+	}
+}
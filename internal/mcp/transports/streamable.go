@@ -0,0 +1,664 @@
+package transports
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+)
+
+const (
+	// DefaultStreamResumeAttempts is how many times a dropped response stream is resumed
+	DefaultStreamResumeAttempts = 5
+
+	// defaultStreamRetryDelay is used until the server sends an SSE retry field
+	defaultStreamRetryDelay = 500 * time.Millisecond
+	maxStreamRetryDelay     = 10 * time.Second
+
+	sessionIDHeader       = "Mcp-Session-Id"
+	protocolVersionHeader = "MCP-Protocol-Version"
+	lastEventIDHeader     = "Last-Event-ID"
+)
+
+// ErrSessionExpired is returned once the server answers 404 for the current
+// session and a new one cannot be started in its place
+var ErrSessionExpired = errors.New("MCP session expired or was terminated by the server")
+
+// StreamEventType identifies what happened on a streamable HTTP connection
+type StreamEventType string
+
+const (
+	StreamEventReceived          StreamEventType = "event"
+	StreamEventDropped           StreamEventType = "dropped"
+	StreamEventResumeAttempt     StreamEventType = "resume_attempt"
+	StreamEventResumed           StreamEventType = "resumed"
+	StreamEventResumeFailed      StreamEventType = "resume_failed"
+	StreamEventSessionTerminated StreamEventType = "session_terminated"
+	StreamEventSessionRenewed    StreamEventType = "session_renewed"
+)
+
+// StreamEvent reports stream activity so callers can surface resumption attempts
+type StreamEvent struct {
+	Type        StreamEventType
+	SessionID   string
+	LastEventID string
+	Attempt     int
+	Err         error
+}
+
+// StreamableTransport implements the streamable HTTP client transport with
+// resumable response streams: when an SSE response drops before every response
+// has arrived it is resumed with a GET carrying Last-Event-ID, keeping the same
+// Mcp-Session-Id. When the server answers 404 for the session, a new one is
+// started by sending the last initialize request again, as the specification
+// requires. Closing the connection terminates the session with DELETE.
+type StreamableTransport struct {
+	url            string
+	client         *http.Client
+	headers        map[string]string
+	resumeAttempts int
+	observer       func(StreamEvent)
+	sessionID      string // Session to resume, if any
+}
+
+// createStreamableTransport creates a resumable streamable HTTP transport
+func createStreamableTransport(config *TransportConfig, strategy ContextStrategy) (officialMCP.Transport, ContextStrategy, error) {
+	resumeAttempts := config.StreamResumeAttempts
+	if resumeAttempts == 0 {
+		resumeAttempts = DefaultStreamResumeAttempts
+	}
+
+	transport := &StreamableTransport{
+		url:            config.URL,
+		client:         GetHTTPClientForTransport(config.Type, config.HTTPClient),
+		headers:        config.Headers,
+		resumeAttempts: resumeAttempts,
+		observer:       config.StreamObserver,
+		sessionID:      config.SessionID,
+	}
+
+	return transport, strategy, nil
+}
+
+// Connect returns a connection that POSTs messages to the transport URL. A
+// transport created with a session ID sends it from the first request, so
+// the server continues that session or answers 404 if it has ended.
+func (t *StreamableTransport) Connect(ctx context.Context) (officialMCP.Connection, error) {
+	connCtx, cancel := context.WithCancel(context.Background())
+	return &streamableConn{
+		transport: t,
+		ctx:       connCtx,
+		cancel:    cancel,
+		incoming:  make(chan jsonrpc.Message, 100),
+		done:      make(chan struct{}),
+		sessionID: t.sessionID,
+	}, nil
+}
+
+// streamableConn is a single logical session with a streamable HTTP server
+type streamableConn struct {
+	transport *StreamableTransport
+
+	ctx    context.Context // Lives as long as the connection so streams outlast Write calls
+	cancel context.CancelFunc
+
+	incoming chan jsonrpc.Message
+	done     chan struct{}
+
+	closeOnce sync.Once
+	closeErr  error
+
+	renewMu sync.Mutex // Held while a new session is started
+
+	mu              sync.Mutex
+	sessionID       string
+	protocolVersion string
+	initialize      []byte // The last initialize request, sent again to start a new session
+	expired         bool   // The server answered 404 for the session
+	err             error
+}
+
+// SessionID returns the Mcp-Session-Id assigned by the server
+func (c *streamableConn) SessionID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sessionID
+}
+
+// Read returns the next message received on any response stream
+func (c *streamableConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case msg := <-c.incoming:
+		return msg, nil
+	case <-c.done:
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Write POSTs a message and starts consuming the response. A message the
+// server refuses because it has ended the session is sent again in a new one.
+func (c *streamableConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	data, err := EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	req, isRequest := msg.(*jsonrpc.Request)
+	initializing := isRequest && req.Method == "initialize"
+
+	c.mu.Lock()
+	if initializing {
+		c.initialize = data
+		c.err = nil
+	}
+	err = c.err
+	expired := c.expired && !initializing
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if expired {
+		if err := c.renewSession(ctx); err != nil {
+			return err
+		}
+	}
+
+	resp, cancel, err := c.post(ctx, data, initializing)
+	if errors.Is(err, ErrSessionExpired) && !initializing {
+		if err = c.renewSession(ctx); err == nil {
+			resp, cancel, err = c.post(ctx, data, false)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusAccepted:
+		resp.Body.Close()
+		cancel()
+	case strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream"):
+		pending := make(map[string]bool)
+		if isRequest && req.ID.IsValid() {
+			pending[idKey(req.ID)] = true
+		}
+		go func() {
+			defer cancel()
+			c.consumeStream(resp.Body, pending)
+		}()
+	default:
+		defer cancel()
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		if len(bytes.TrimSpace(body)) == 0 {
+			return nil
+		}
+		msgs, err := DecodeMessages(body)
+		if err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		for _, m := range msgs {
+			c.deliver(m)
+		}
+	}
+
+	return nil
+}
+
+// post sends a message, returning the checked response and the function
+// that ends its request once the body has been read
+func (c *streamableConn) post(ctx context.Context, data []byte, initializing bool) (*http.Response, context.CancelFunc, error) {
+	// Tie the request to the connection, cancelling it early only if ctx ends
+	// before the response headers arrive
+	reqCtx, cancel := context.WithCancel(c.ctx)
+	stop := context.AfterFunc(ctx, cancel)
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, c.transport.url, bytes.NewReader(data))
+	if err != nil {
+		stop()
+		cancel()
+		return nil, nil, err
+	}
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	resp, err := c.transport.client.Do(req)
+	stop()
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	if err := c.checkResponse(resp, initializing); err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return resp, cancel, nil
+}
+
+// renewSession starts a new session after the server has ended the current
+// one: the last initialize request is sent again without a session ID, and
+// its response, which the client already had for the first session, is
+// read here rather than delivered. State the server kept for the old session,
+// such as subscriptions, is not restored.
+func (c *streamableConn) renewSession(ctx context.Context) error {
+	c.renewMu.Lock()
+	defer c.renewMu.Unlock()
+
+	c.mu.Lock()
+	expired, initialize, oldSessionID := c.expired, c.initialize, c.sessionID
+	c.mu.Unlock()
+	if !expired {
+		return nil // Another write has renewed it
+	}
+
+	err := c.reinitialize(ctx, initialize)
+	c.mu.Lock()
+	if err != nil {
+		c.err = fmt.Errorf("%w: %v", ErrSessionExpired, err)
+		err = c.err
+	}
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	debug.Info("Streamable HTTP: Started a new session in place of an expired one",
+		debug.F("oldSessionID", oldSessionID),
+		debug.F("sessionID", c.SessionID()))
+	c.notify(StreamEvent{Type: StreamEventSessionRenewed})
+	return nil
+}
+
+// reinitialize sends an initialize request and the initialized notification
+func (c *streamableConn) reinitialize(ctx context.Context, initialize []byte) error {
+	if initialize == nil {
+		return errors.New("no initialize request to start a new session with")
+	}
+
+	resp, cancel, err := c.post(ctx, initialize, true)
+	if err != nil {
+		return err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to read initialize response: %w", err)
+	}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		var data []byte
+		readSSEEvents(bytes.NewReader(body), func(evt sseEvent) {
+			if len(evt.data) > 0 {
+				data = evt.data
+			}
+		})
+		body = data
+	}
+
+	msgs, err := DecodeMessages(body)
+	if err != nil {
+		return fmt.Errorf("failed to decode initialize response: %w", err)
+	}
+	for _, msg := range msgs {
+		result, ok := msg.(*jsonrpc.Response)
+		if !ok {
+			continue
+		}
+		if result.Error != nil {
+			return fmt.Errorf("initialize failed: %w", result.Error)
+		}
+		c.setProtocolVersion(result.Result)
+	}
+
+	notification, err := EncodeMessage(&jsonrpc.Request{Method: "notifications/initialized"})
+	if err != nil {
+		return err
+	}
+	resp, cancel, err = c.post(ctx, notification, false)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	cancel()
+	return nil
+}
+
+// checkResponse validates the status and records the session ID. The ID of
+// a response to initialize always replaces the current one, since it names
+// the session that request started. A 404 for the current session marks it
+// expired, so the next write starts a new one.
+func (c *streamableConn) checkResponse(resp *http.Response, initializing bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if resp.StatusCode == http.StatusNotFound && c.sessionID != "" {
+		resp.Body.Close()
+		c.sessionID = ""
+		c.protocolVersion = ""
+		c.expired = !initializing
+		return ErrSessionExpired
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		if len(body) > 0 {
+			return fmt.Errorf("server returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+		}
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+
+	if sessionID := resp.Header.Get(sessionIDHeader); sessionID != "" && (initializing || c.sessionID == "") {
+		c.sessionID = sessionID
+	}
+	if initializing {
+		c.expired = false
+		c.protocolVersion = ""
+	}
+	return nil
+}
+
+// setHeaders applies configured, session and protocol version headers
+func (c *streamableConn) setHeaders(req *http.Request) {
+	for name, value := range c.transport.headers {
+		req.Header.Set(name, value)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sessionID != "" {
+		req.Header.Set(sessionIDHeader, c.sessionID)
+	}
+	if c.protocolVersion != "" {
+		req.Header.Set(protocolVersionHeader, c.protocolVersion)
+	}
+}
+
+// deliver hands a message to Read, noting the negotiated protocol version
+func (c *streamableConn) deliver(msg jsonrpc.Message) {
+	if resp, ok := msg.(*jsonrpc.Response); ok && resp.Result != nil {
+		c.setProtocolVersion(resp.Result)
+	}
+
+	select {
+	case c.incoming <- msg:
+	case <-c.done:
+	}
+}
+
+// setProtocolVersion notes the protocol version of an initialize result, if
+// none has been negotiated yet
+func (c *streamableConn) setProtocolVersion(result json.RawMessage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.protocolVersion != "" {
+		return
+	}
+	var initialize struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if json.Unmarshal(result, &initialize) == nil {
+		c.protocolVersion = initialize.ProtocolVersion
+	}
+}
+
+// consumeStream reads an SSE response stream, resuming it with Last-Event-ID if
+// it ends while responses are still outstanding
+func (c *streamableConn) consumeStream(body io.ReadCloser, pending map[string]bool) {
+	lastEventID := ""
+	retryDelay := defaultStreamRetryDelay
+	attempt := 0
+
+	for {
+		err := readSSEEvents(body, func(evt sseEvent) {
+			if evt.id != "" {
+				lastEventID = evt.id
+			}
+			if evt.retry > 0 {
+				retryDelay = evt.retry
+			}
+			if len(evt.data) == 0 {
+				return
+			}
+
+			msgs, err := DecodeMessages(evt.data)
+			if err != nil {
+				debug.Error("Streamable HTTP: Failed to decode event",
+					debug.F("error", err),
+					debug.F("data", string(evt.data)))
+				return
+			}
+			for _, msg := range msgs {
+				if resp, ok := msg.(*jsonrpc.Response); ok {
+					delete(pending, idKey(resp.ID))
+				}
+				c.deliver(msg)
+			}
+			attempt = 0 // Progress was made, so the attempt budget starts over
+			c.notify(StreamEvent{Type: StreamEventReceived, LastEventID: lastEventID})
+		})
+		body.Close()
+
+		if c.isClosed() || len(pending) == 0 {
+			return
+		}
+
+		c.notify(StreamEvent{Type: StreamEventDropped, LastEventID: lastEventID, Err: err})
+		debug.Warn("Streamable HTTP: Response stream dropped",
+			debug.F("lastEventID", lastEventID),
+			debug.F("pending", len(pending)),
+			debug.F("error", err))
+
+		if lastEventID == "" {
+			c.notify(StreamEvent{Type: StreamEventResumeFailed,
+				Err: fmt.Errorf("stream cannot be resumed: server sent no event IDs")})
+			return
+		}
+
+		body = nil
+		for body == nil {
+			attempt++
+			if attempt > c.transport.resumeAttempts {
+				c.notify(StreamEvent{Type: StreamEventResumeFailed, LastEventID: lastEventID, Attempt: attempt - 1,
+					Err: fmt.Errorf("gave up resuming stream after %d attempts", attempt-1)})
+				return
+			}
+
+			delay := retryDelay << (attempt - 1)
+			if delay > maxStreamRetryDelay || delay <= 0 {
+				delay = maxStreamRetryDelay
+			}
+			c.notify(StreamEvent{Type: StreamEventResumeAttempt, LastEventID: lastEventID, Attempt: attempt})
+			select {
+			case <-time.After(delay):
+			case <-c.done:
+				return
+			}
+
+			resumed, err := c.resume(lastEventID)
+			if err != nil {
+				debug.Warn("Streamable HTTP: Resume attempt failed",
+					debug.F("attempt", attempt),
+					debug.F("lastEventID", lastEventID),
+					debug.F("error", err))
+				if errors.Is(err, ErrSessionExpired) {
+					c.notify(StreamEvent{Type: StreamEventResumeFailed, LastEventID: lastEventID, Attempt: attempt, Err: err})
+					return
+				}
+				continue
+			}
+			body = resumed
+			c.notify(StreamEvent{Type: StreamEventResumed, LastEventID: lastEventID, Attempt: attempt})
+		}
+	}
+}
+
+// resume reopens a dropped stream with a GET carrying Last-Event-ID
+func (c *streamableConn) resume(lastEventID string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, c.transport.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.setHeaders(req)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(lastEventIDHeader, lastEventID)
+
+	resp, err := c.transport.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkResponse(resp, false); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		resp.Body.Close()
+		return nil, fmt.Errorf("resume returned %q instead of text/event-stream", resp.Header.Get("Content-Type"))
+	}
+	return resp.Body, nil
+}
+
+// Close stops all streams and terminates the session with DELETE
+func (c *streamableConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.cancel()
+
+		sessionID := c.SessionID()
+		if sessionID == "" {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.transport.url, nil)
+		if err != nil {
+			c.closeErr = err
+			return
+		}
+		c.setHeaders(req)
+
+		resp, err := c.transport.client.Do(req)
+		if err != nil {
+			c.closeErr = fmt.Errorf("failed to terminate session: %w", err)
+			return
+		}
+		resp.Body.Close()
+
+		// 405 means the server does not allow clients to terminate sessions
+		switch {
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			c.notify(StreamEvent{Type: StreamEventSessionTerminated})
+		case resp.StatusCode == http.StatusMethodNotAllowed, resp.StatusCode == http.StatusNotFound:
+			debug.Info("Streamable HTTP: Server did not terminate session",
+				debug.F("sessionID", sessionID),
+				debug.F("status", resp.StatusCode))
+		default:
+			c.closeErr = fmt.Errorf("failed to terminate session: server returned %s", resp.Status)
+		}
+	})
+	return c.closeErr
+}
+
+// isClosed reports whether Close has been called
+func (c *streamableConn) isClosed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// notify reports a stream event to the configured observer
+func (c *streamableConn) notify(evt StreamEvent) {
+	if c.transport.observer == nil {
+		return
+	}
+	evt.SessionID = c.SessionID()
+	c.transport.observer(evt)
+}
+
+// idKey returns a comparable key for a JSON-RPC ID
+func idKey(id jsonrpc.ID) string {
+	return fmt.Sprintf("%T:%v", id.Raw(), id.Raw())
+}
+
+// sseEvent is a single dispatched server-sent event
+type sseEvent struct {
+	id    string
+	name  string
+	data  []byte
+	retry time.Duration
+}
+
+// readSSEEvents parses a text/event-stream body, calling fn for each event
+func readSSEEvents(r io.Reader, fn func(sseEvent)) error {
+	reader := bufio.NewReader(r)
+	var evt sseEvent
+	var data bytes.Buffer
+	hasFields := false
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if hasFields {
+				evt.data = bytes.TrimSuffix(data.Bytes(), []byte("\n"))
+				fn(evt)
+			}
+			evt = sseEvent{}
+			data.Reset()
+			hasFields = false
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // Comment
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			if !strings.Contains(value, "\x00") {
+				evt.id = value
+				hasFields = true
+			}
+		case "event":
+			evt.name = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasFields = true
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				evt.retry = time.Duration(ms) * time.Millisecond
+				hasFields = true
+			}
+		}
+	}
+}
@@ -0,0 +1,442 @@
+package transports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// flakyStreamServer answers POSTs with an SSE stream that drops after the
+// first event, and serves the rest of the stream on a Last-Event-ID resume
+type flakyStreamServer struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	resumeIDs    []string
+	resumeSIDs   []string
+	deleteSID    string
+	sendEventIDs bool
+}
+
+func newFlakyStreamServer(t *testing.T, sendEventIDs bool) *flakyStreamServer {
+	t.Helper()
+	fs := &flakyStreamServer{sendEventIDs: sendEventIDs}
+	fs.Server = httptest.NewServer(http.HandlerFunc(fs.handle))
+	t.Cleanup(fs.Close)
+	return fs
+}
+
+func (fs *flakyStreamServer) handle(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		body, _ := io.ReadAll(r.Body)
+		msg, err := DecodeMessage(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		req := msg.(*jsonrpc.Request)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set(sessionIDHeader, "session-1")
+		w.WriteHeader(http.StatusOK)
+		id := ""
+		if fs.sendEventIDs {
+			id = "id: stream-1\n"
+		}
+		fmt.Fprintf(w, "retry: 10\n%sdata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\",\"params\":{\"progress\":1,\"request\":%v}}\n\n",
+			id, req.ID.Raw())
+		w.(http.Flusher).Flush()
+		// Drop the stream before the response is sent
+
+	case http.MethodGet:
+		fs.mu.Lock()
+		fs.resumeIDs = append(fs.resumeIDs, r.Header.Get(lastEventIDHeader))
+		fs.resumeSIDs = append(fs.resumeSIDs, r.Header.Get(sessionIDHeader))
+		attempts := len(fs.resumeIDs)
+		fs.mu.Unlock()
+
+		if attempts == 1 {
+			// First resume attempt fails, exercising the retry path
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "id: stream-2\ndata: {\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{\"protocolVersion\":\"2025-03-26\"}}\n\n")
+
+	case http.MethodDelete:
+		fs.mu.Lock()
+		fs.deleteSID = r.Header.Get(sessionIDHeader)
+		fs.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// eventRecorder collects stream events reported by the transport
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []StreamEvent
+}
+
+func (er *eventRecorder) record(evt StreamEvent) {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+	er.events = append(er.events, evt)
+}
+
+func (er *eventRecorder) count(eventType StreamEventType) int {
+	er.mu.Lock()
+	defer er.mu.Unlock()
+	n := 0
+	for _, evt := range er.events {
+		if evt.Type == eventType {
+			n++
+		}
+	}
+	return n
+}
+
+func connectStreamable(t *testing.T, url string, recorder *eventRecorder) officialMCP.Connection {
+	t.Helper()
+	transport, _, err := NewFactory().CreateTransport(&TransportConfig{
+		Type:           TransportHTTP,
+		URL:            url,
+		StreamObserver: recorder.record,
+	})
+	if err != nil {
+		t.Fatalf("CreateTransport failed: %v", err)
+	}
+	conn, err := transport.Connect(context.Background())
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	return conn
+}
+
+func TestStreamableTransportResumesDroppedStream(t *testing.T) {
+	server := newFlakyStreamServer(t, true)
+	recorder := &eventRecorder{}
+	conn := connectStreamable(t, server.URL, recorder)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := conn.Write(ctx, newTestRequest(t, 1, "tools/call")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	msg, err := conn.Read(ctx)
+	if err != nil {
+		t.Fatalf("Read notification failed: %v", err)
+	}
+	if req, ok := msg.(*jsonrpc.Request); !ok || req.Method != "notifications/progress" {
+		t.Fatalf("Expected progress notification, got %#v", msg)
+	}
+
+	msg, err = conn.Read(ctx)
+	if err != nil {
+		t.Fatalf("Read response failed: %v", err)
+	}
+	if resp, ok := msg.(*jsonrpc.Response); !ok || resp.ID.Raw() != int64(1) {
+		t.Fatalf("Expected response to request 1 from resumed stream, got %#v", msg)
+	}
+
+	server.mu.Lock()
+	resumeIDs, resumeSIDs := server.resumeIDs, server.resumeSIDs
+	server.mu.Unlock()
+	if len(resumeIDs) != 2 || resumeIDs[1] != "stream-1" {
+		t.Errorf("Expected two resume attempts with Last-Event-ID stream-1, got %v", resumeIDs)
+	}
+	if resumeSIDs[len(resumeSIDs)-1] != "session-1" {
+		t.Errorf("Expected resume to keep Mcp-Session-Id, got %v", resumeSIDs)
+	}
+	if conn.SessionID() != "session-1" {
+		t.Errorf("Expected session ID session-1, got %q", conn.SessionID())
+	}
+
+	if recorder.count(StreamEventDropped) != 1 {
+		t.Errorf("Expected 1 drop, got %d", recorder.count(StreamEventDropped))
+	}
+	if recorder.count(StreamEventResumeAttempt) != 2 || recorder.count(StreamEventResumed) != 1 {
+		t.Errorf("Expected 2 resume attempts and 1 success, got %d and %d",
+			recorder.count(StreamEventResumeAttempt), recorder.count(StreamEventResumed))
+	}
+
+	// Disconnecting terminates the session explicitly
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	server.mu.Lock()
+	deleteSID := server.deleteSID
+	server.mu.Unlock()
+	if deleteSID != "session-1" {
+		t.Errorf("Expected DELETE for session-1, got %q", deleteSID)
+	}
+	if recorder.count(StreamEventSessionTerminated) != 1 {
+		t.Error("Expected session termination to be reported")
+	}
+}
+
+func TestStreamableTransportCannotResumeWithoutEventIDs(t *testing.T) {
+	server := newFlakyStreamServer(t, false)
+	recorder := &eventRecorder{}
+	conn := connectStreamable(t, server.URL, recorder)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := conn.Write(ctx, newTestRequest(t, 1, "tools/call")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := conn.Read(ctx); err != nil {
+		t.Fatalf("Read notification failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for recorder.count(StreamEventResumeFailed) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if recorder.count(StreamEventResumeFailed) != 1 {
+		t.Error("Expected resume failure when the server sends no event IDs")
+	}
+	if recorder.count(StreamEventResumeAttempt) != 0 {
+		t.Error("Expected no resume attempts without an event ID")
+	}
+}
+
+func TestStreamableTransportSessionExpired(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(sessionIDHeader) != "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(sessionIDHeader, "short-lived")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-03-26"}}`))
+	}))
+	defer server.Close()
+
+	conn := connectStreamable(t, server.URL, &eventRecorder{})
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := conn.Write(ctx, newTestRequest(t, 1, "initialize")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := conn.Read(ctx); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	err := conn.Write(ctx, newTestRequest(t, 2, "tools/list"))
+	if !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("Expected ErrSessionExpired, got %v", err)
+	}
+}
+
+// rotatingSessionServer gives every initialize request a new session ID and
+// answers 404 for any other ID once expire has been called
+type rotatingSessionServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	sessions int
+	current  string
+	methods  []string
+}
+
+func newRotatingSessionServer(t *testing.T) *rotatingSessionServer {
+	t.Helper()
+	rs := &rotatingSessionServer{}
+	rs.Server = httptest.NewServer(http.HandlerFunc(rs.handle))
+	t.Cleanup(rs.Close)
+	return rs
+}
+
+func (rs *rotatingSessionServer) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	msg, err := DecodeMessage(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req := msg.(*jsonrpc.Request)
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.methods = append(rs.methods, req.Method)
+	if req.Method == "initialize" {
+		rs.sessions++
+		rs.current = fmt.Sprintf("session-%d", rs.sessions)
+		w.Header().Set(sessionIDHeader, rs.current)
+	} else if r.Header.Get(sessionIDHeader) != rs.current {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if !req.ID.IsValid() {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%v,"result":{"protocolVersion":"2025-03-26"}}`, req.ID.Raw())
+}
+
+// expire ends the current session
+func (rs *rotatingSessionServer) expire() {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.current = ""
+	rs.methods = nil
+}
+
+func TestStreamableTransportRenewsExpiredSession(t *testing.T) {
+	server := newRotatingSessionServer(t)
+	recorder := &eventRecorder{}
+	conn := connectStreamable(t, server.URL, recorder)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := conn.Write(ctx, newTestRequest(t, 1, "initialize")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := conn.Read(ctx); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if conn.SessionID() != "session-1" {
+		t.Fatalf("Expected session-1, got %q", conn.SessionID())
+	}
+
+	// The server ends the session; the next request starts a new one
+	server.expire()
+	if err := conn.Write(ctx, newTestRequest(t, 2, "tools/list")); err != nil {
+		t.Fatalf("Write after the session expired failed: %v", err)
+	}
+	msg, err := conn.Read(ctx)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if resp, ok := msg.(*jsonrpc.Response); !ok || resp.ID.Raw() != int64(2) {
+		t.Fatalf("Expected the response to request 2, got %#v", msg)
+	}
+	if conn.SessionID() != "session-2" {
+		t.Errorf("Expected the renewed session-2, got %q", conn.SessionID())
+	}
+	server.mu.Lock()
+	methods := append([]string(nil), server.methods...)
+	server.mu.Unlock()
+	want := []string{"tools/list", "initialize", "notifications/initialized", "tools/list"}
+	if fmt.Sprint(methods) != fmt.Sprint(want) {
+		t.Errorf("Expected requests %v, got %v", want, methods)
+	}
+	if recorder.count(StreamEventSessionRenewed) != 1 {
+		t.Error("Expected the new session to be reported")
+	}
+
+	// An initialize the client sends itself also takes the new ID
+	server.expire()
+	if err := conn.Write(ctx, newTestRequest(t, 3, "initialize")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := conn.Read(ctx); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if conn.SessionID() != "session-3" {
+		t.Errorf("Expected session-3 from the initialize response, got %q", conn.SessionID())
+	}
+}
+
+func TestStreamableTransportWithSDKServer(t *testing.T) {
+	server := officialMCP.NewServer(&officialMCP.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	server.AddTool(&officialMCP.Tool{Name: "echo", Description: "Echo input"},
+		func(ctx context.Context, ss *officialMCP.ServerSession, params *officialMCP.CallToolParamsFor[map[string]any]) (*officialMCP.CallToolResult, error) {
+			return &officialMCP.CallToolResult{Content: []officialMCP.Content{&officialMCP.TextContent{Text: "ok"}}}, nil
+		})
+	httpServer := httptest.NewServer(officialMCP.NewStreamableHTTPHandler(func(*http.Request) *officialMCP.Server { return server }, nil))
+	defer httpServer.Close()
+
+	transport, _, err := NewFactory().CreateTransport(&TransportConfig{Type: TransportStreamableHTTP, URL: httpServer.URL})
+	if err != nil {
+		t.Fatalf("CreateTransport failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := officialMCP.NewClient(&officialMCP.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, transport)
+	if err != nil {
+		t.Fatalf("Client connect failed: %v", err)
+	}
+	defer session.Close()
+
+	result, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	if len(result.Tools) != 1 || result.Tools[0].Name != "echo" {
+		t.Errorf("Unexpected tools: %+v", result.Tools)
+	}
+}
+
+func TestStreamableTransportResumesSession(t *testing.T) {
+	server := officialMCP.NewServer(&officialMCP.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	httpServer := httptest.NewServer(officialMCP.NewStreamableHTTPHandler(func(*http.Request) *officialMCP.Server { return server }, nil))
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	connect := func(sessionID string) (*officialMCP.ClientSession, error) {
+		transport, _, err := NewFactory().CreateTransport(&TransportConfig{
+			Type:      TransportStreamableHTTP,
+			URL:       httpServer.URL,
+			SessionID: sessionID,
+		})
+		if err != nil {
+			t.Fatalf("CreateTransport failed: %v", err)
+		}
+		client := officialMCP.NewClient(&officialMCP.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+		return client.Connect(ctx, transport)
+	}
+
+	// The first run ends without terminating its session
+	first, err := connect("")
+	if err != nil {
+		t.Fatalf("Client connect failed: %v", err)
+	}
+	sessionID := first.ID()
+	if sessionID == "" {
+		t.Fatal("Server assigned no session ID")
+	}
+
+	resumed, err := connect(sessionID)
+	if err != nil {
+		t.Fatalf("Resuming the session failed: %v", err)
+	}
+	defer resumed.Close()
+	if resumed.ID() != sessionID {
+		t.Errorf("Resumed session ID = %q, want %q", resumed.ID(), sessionID)
+	}
+	if _, err := resumed.ListTools(ctx, nil); err != nil {
+		t.Errorf("ListTools on the resumed session failed: %v", err)
+	}
+
+	// A session the server no longer knows cannot be resumed
+	if _, err := connect("unknown-session"); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("Expected ErrSessionExpired for an unknown session, got %v", err)
+	}
+}
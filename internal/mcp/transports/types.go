@@ -6,6 +6,7 @@ import (
 	"time"
 
 	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/platform/process"
 )
 
 // TransportType represents the different transport protocols supported
@@ -16,6 +17,11 @@ const (
 	TransportSSE            TransportType = "sse"
 	TransportHTTP           TransportType = "http"
 	TransportStreamableHTTP TransportType = "streamable-http"
+	TransportWebSocket      TransportType = "websocket"
+	TransportUnix           TransportType = "unix"
+	TransportTCP            TransportType = "tcp"
+	TransportAuto           TransportType = "auto"
+	TransportReplay         TransportType = "replay"
 )
 
 // String returns the string representation of the transport type
@@ -28,16 +34,36 @@ type TransportConfig struct {
 	Type TransportType
 
 	// STDIO specific
-	Command string
-	Args    []string
-
-	// HTTP/SSE specific
+	Command          string
+	Args             []string
+	WorkingDirectory string
+	Environment      map[string]string  // Added to the inherited environment
+	Limits           process.Limits     // Resource limits for the server process (Linux only)
+	KillTimeout      time.Duration      // Grace period before the server's process group is killed (0 uses the default)
+	ProcessObserver  func(ProcessEvent) // Notified when the server process starts and exits
+
+	// HTTP/SSE/WebSocket specific
 	URL        string
 	HTTPClient *http.Client
+	Headers    map[string]string
+
+	// WebSocket specific
+	Subprotocols []string
+	PingInterval time.Duration
+
+	// Streamable HTTP specific
+	StreamResumeAttempts int               // Resume attempts for a dropped stream (0 uses the default)
+	StreamObserver       func(StreamEvent) // Notified of stream drops, resumptions and session termination
+	SessionID            string            // Mcp-Session-Id of an earlier session to resume instead of starting one
+
+	// Replay specific (URL holds the cassette path)
+	ReplayMatch string // exact, fuzzy or method ("" uses fuzzy)
 
 	// Common options
-	Timeout   time.Duration
-	DebugMode bool
+	RecordPath string // When set, every frame is recorded to this cassette file
+	ChaosPath  string // When set, faults from this schedule are injected into the session
+	Timeout    time.Duration
+	DebugMode  bool
 }
 
 // ContextStrategy defines how contexts should be handled for different transports
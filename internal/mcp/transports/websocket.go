@@ -0,0 +1,657 @@
+package transports
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	officialMCP "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+)
+
+// DefaultWebSocketSubprotocol is offered when no subprotocols are configured
+const DefaultWebSocketSubprotocol = "mcp"
+
+// websocketGUID is the fixed key suffix defined by RFC 6455 section 1.3
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxWebSocketMessageSize bounds a single reassembled message (16MB)
+const maxWebSocketMessageSize = 16 * 1024 * 1024
+
+// webSocketWriteTimeout bounds a frame write without a deadline of its own,
+// so a peer that stops reading cannot hold the write lock forever
+const webSocketWriteTimeout = 10 * time.Second
+
+// WebSocket opcodes (RFC 6455 section 5.2)
+const (
+	wsOpContinuation byte = 0x0
+	wsOpText         byte = 0x1
+	wsOpBinary       byte = 0x2
+	wsOpClose        byte = 0x8
+	wsOpPing         byte = 0x9
+	wsOpPong         byte = 0xA
+)
+
+// WebSocket close codes used by the transport (RFC 6455 section 7.4.1)
+const (
+	WebSocketCloseNormal          = 1000
+	WebSocketCloseGoingAway       = 1001
+	WebSocketCloseProtocolError   = 1002
+	WebSocketCloseUnsupportedData = 1003
+	WebSocketCloseNoStatus        = 1005
+	WebSocketCloseAbnormal        = 1006
+	WebSocketCloseMessageTooBig   = 1009
+)
+
+// WebSocketCloseError reports how the peer (or the keepalive) closed the connection
+type WebSocketCloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *WebSocketCloseError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("websocket closed with code %d (%s): %s", e.Code, webSocketCloseCodeText(e.Code), e.Reason)
+	}
+	return fmt.Sprintf("websocket closed with code %d (%s)", e.Code, webSocketCloseCodeText(e.Code))
+}
+
+// webSocketCloseCodeText returns a short description of a close code
+func webSocketCloseCodeText(code int) string {
+	switch code {
+	case WebSocketCloseNormal:
+		return "normal closure"
+	case WebSocketCloseGoingAway:
+		return "going away"
+	case WebSocketCloseProtocolError:
+		return "protocol error"
+	case WebSocketCloseUnsupportedData:
+		return "unsupported data"
+	case WebSocketCloseNoStatus:
+		return "no status"
+	case WebSocketCloseAbnormal:
+		return "abnormal closure"
+	case 1007:
+		return "invalid payload"
+	case 1008:
+		return "policy violation"
+	case WebSocketCloseMessageTooBig:
+		return "message too big"
+	case 1011:
+		return "internal server error"
+	default:
+		return "application defined"
+	}
+}
+
+// WebSocketTransport connects to MCP servers that expose a ws:// or wss:// endpoint.
+// Each JSON-RPC message (or batch) travels in a single text message.
+type WebSocketTransport struct {
+	url          string
+	headers      map[string]string
+	subprotocols []string
+	pingInterval time.Duration
+	timeout      time.Duration
+}
+
+// createWebSocketTransport creates a WebSocket transport from the transport configuration
+func createWebSocketTransport(config *TransportConfig, strategy ContextStrategy) (officialMCP.Transport, ContextStrategy, error) {
+	subprotocols := config.Subprotocols
+	if len(subprotocols) == 0 {
+		subprotocols = []string{DefaultWebSocketSubprotocol}
+	}
+
+	transport := &WebSocketTransport{
+		url:          config.URL,
+		headers:      config.Headers,
+		subprotocols: subprotocols,
+		pingInterval: config.PingInterval,
+		timeout:      config.Timeout,
+	}
+
+	return transport, strategy, nil
+}
+
+// Connect performs the opening handshake and starts the read and keepalive loops
+func (t *WebSocketTransport) Connect(ctx context.Context) (officialMCP.Connection, error) {
+	u, err := url.Parse(t.url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WebSocket URL: %w", err)
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		if u.Scheme == "wss" {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+
+	dialCtx := ctx
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		dialCtx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+
+	debug.Info("WebSocket: Dialing server",
+		debug.F("url", t.url),
+		debug.F("subprotocols", t.subprotocols))
+
+	var dialer net.Dialer
+	netConn, err := dialer.DialContext(dialCtx, "tcp", host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial WebSocket server: %w", err)
+	}
+
+	if u.Scheme == "wss" {
+		tlsConn := tls.Client(netConn, &tls.Config{ServerName: u.Hostname()})
+		if err := tlsConn.HandshakeContext(dialCtx); err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("WebSocket TLS handshake failed: %w", err)
+		}
+		netConn = tlsConn
+	}
+
+	if deadline, ok := dialCtx.Deadline(); ok {
+		netConn.SetDeadline(deadline)
+	}
+
+	br := bufio.NewReader(netConn)
+	protocol, err := t.handshake(netConn, br, u)
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	// Clear the handshake deadline; the keepalive takes over from here
+	netConn.SetDeadline(time.Time{})
+
+	debug.Info("WebSocket: Connection established",
+		debug.F("url", t.url),
+		debug.F("subprotocol", protocol))
+
+	conn := newWebSocketConn(netConn, br, protocol, true)
+	if t.pingInterval > 0 {
+		go conn.keepalive(t.pingInterval)
+	}
+	return conn, nil
+}
+
+// handshake sends the HTTP upgrade request and validates the server's response
+func (t *WebSocketTransport) handshake(conn net.Conn, br *bufio.Reader, u *url.URL) (string, error) {
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		return "", fmt.Errorf("failed to generate WebSocket key: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+
+	httpURL := *u
+	if u.Scheme == "wss" {
+		httpURL.Scheme = "https"
+	} else {
+		httpURL.Scheme = "http"
+	}
+
+	req, err := http.NewRequest(http.MethodGet, httpURL.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build WebSocket upgrade request: %w", err)
+	}
+	for name, value := range t.headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if len(t.subprotocols) > 0 {
+		req.Header.Set("Sec-WebSocket-Protocol", strings.Join(t.subprotocols, ", "))
+	}
+
+	if err := req.Write(conn); err != nil {
+		return "", fmt.Errorf("failed to send WebSocket upgrade request: %w", err)
+	}
+
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to read WebSocket upgrade response: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("WebSocket upgrade rejected: HTTP %d %s",
+			resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") {
+		return "", fmt.Errorf("WebSocket upgrade failed: server did not switch to websocket")
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != computeAcceptKey(key) {
+		return "", fmt.Errorf("WebSocket upgrade failed: invalid Sec-WebSocket-Accept")
+	}
+
+	protocol := resp.Header.Get("Sec-WebSocket-Protocol")
+	if protocol != "" && !containsString(t.subprotocols, protocol) {
+		return "", fmt.Errorf("WebSocket server selected unrequested subprotocol %q", protocol)
+	}
+
+	return protocol, nil
+}
+
+// computeAcceptKey derives the Sec-WebSocket-Accept value for a client key
+func computeAcceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// webSocketConn implements officialMCP.Connection over a WebSocket
+type webSocketConn struct {
+	conn     net.Conn
+	br       *bufio.Reader
+	protocol string
+	isClient bool // clients must mask outgoing frames
+
+	writeMu sync.Mutex
+
+	incoming chan jsonrpc.Message
+	done     chan struct{}
+
+	mu        sync.Mutex
+	closeErr  *WebSocketCloseError
+	readErr   error
+	closed    bool
+	lastPong  time.Time
+	closeOnce sync.Once
+}
+
+// newWebSocketConn wraps an upgraded network connection and starts reading
+func newWebSocketConn(conn net.Conn, br *bufio.Reader, protocol string, isClient bool) *webSocketConn {
+	c := &webSocketConn{
+		conn:     conn,
+		br:       br,
+		protocol: protocol,
+		isClient: isClient,
+		incoming: make(chan jsonrpc.Message, 64),
+		done:     make(chan struct{}),
+		lastPong: time.Now(),
+	}
+	go c.readLoop()
+	return c
+}
+
+// Subprotocol returns the subprotocol negotiated during the handshake
+func (c *webSocketConn) Subprotocol() string {
+	return c.protocol
+}
+
+// CloseStatus returns the close code and reason once the connection has closed
+func (c *webSocketConn) CloseStatus() *WebSocketCloseError {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeErr
+}
+
+// SessionID returns an empty session ID; WebSocket has no session header
+func (c *webSocketConn) SessionID() string {
+	return ""
+}
+
+// Read returns the next JSON-RPC message received from the server
+func (c *webSocketConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case msg, ok := <-c.incoming:
+		if ok {
+			return msg, nil
+		}
+		return nil, c.terminalError()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Write sends a JSON-RPC message as a single text frame
+func (c *webSocketConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	data, err := EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	closed := c.closed
+	c.mu.Unlock()
+	if closed {
+		return officialMCP.ErrConnectionClosed
+	}
+
+	deadline, _ := ctx.Deadline()
+	return c.writeFrameBy(wsOpText, data, deadline)
+}
+
+// Close sends a normal closure frame and tears down the connection
+func (c *webSocketConn) Close() error {
+	return c.closeWithCode(WebSocketCloseNormal, "")
+}
+
+// closeWithCode sends a close frame with the given code and closes the socket
+func (c *webSocketConn) closeWithCode(code int, reason string) error {
+	var err error
+	c.closeOnce.Do(func() {
+		c.mu.Lock()
+		c.closed = true
+		if c.closeErr == nil {
+			c.closeErr = &WebSocketCloseError{Code: code, Reason: reason}
+		}
+		c.mu.Unlock()
+
+		// 1005 and 1006 only report a missing status; RFC 6455 section
+		// 7.4.1 forbids sending them, so the frame goes without a code
+		var payload []byte
+		if code != WebSocketCloseNoStatus && code != WebSocketCloseAbnormal {
+			payload = make([]byte, 2+len(reason))
+			binary.BigEndian.PutUint16(payload, uint16(code))
+			copy(payload[2:], reason)
+		}
+
+		// Best effort: the peer may already be gone. A write stalled on a
+		// peer that stopped reading is cut short first, or it would hold
+		// the write lock until its own deadline.
+		deadline := time.Now().Add(time.Second)
+		c.conn.SetWriteDeadline(deadline)
+		_ = c.writeFrameBy(wsOpClose, payload, deadline)
+
+		close(c.done)
+		err = c.conn.Close()
+	})
+	return err
+}
+
+// terminalError returns the error that ended the read loop
+func (c *webSocketConn) terminalError() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closeErr != nil && c.closeErr.Code != WebSocketCloseNormal {
+		return c.closeErr
+	}
+	if c.readErr != nil && !errors.Is(c.readErr, net.ErrClosed) {
+		return c.readErr
+	}
+	return io.EOF
+}
+
+// readLoop reads frames, answers control frames and queues decoded messages
+func (c *webSocketConn) readLoop() {
+	defer close(c.incoming)
+
+	var message []byte
+	var messageOp byte
+	// fragmented is set between the first and the final frame of a message
+	fragmented := false
+
+	for {
+		fin, opcode, payload, err := readWebSocketFrame(c.br, !c.isClient)
+		if err != nil {
+			c.mu.Lock()
+			if !c.closed {
+				c.readErr = err
+				if c.closeErr == nil {
+					c.closeErr = &WebSocketCloseError{Code: WebSocketCloseAbnormal, Reason: err.Error()}
+				}
+			}
+			c.mu.Unlock()
+			c.conn.Close()
+			return
+		}
+
+		switch opcode {
+		case wsOpPing:
+			if err := c.writeFrame(wsOpPong, payload); err != nil {
+				debug.Warn("WebSocket: Failed to answer ping", debug.F("error", err))
+			}
+			continue
+		case wsOpPong:
+			c.mu.Lock()
+			c.lastPong = time.Now()
+			c.mu.Unlock()
+			continue
+		case wsOpClose:
+			code, reason := parseClosePayload(payload)
+			debug.Info("WebSocket: Server closed connection",
+				debug.F("code", code),
+				debug.F("reason", reason))
+			c.mu.Lock()
+			if c.closeErr == nil {
+				c.closeErr = &WebSocketCloseError{Code: code, Reason: reason}
+			}
+			c.mu.Unlock()
+			// Echo the close frame as required by RFC 6455 section 5.5.1
+			c.closeWithCode(code, "")
+			return
+		case wsOpText, wsOpBinary:
+			// RFC 6455 section 5.4: a new message may not start inside a
+			// fragmented one
+			if fragmented {
+				c.closeWithCode(WebSocketCloseProtocolError, "new message before the final fragment")
+				return
+			}
+			messageOp = opcode
+			message = append(message[:0], payload...)
+		case wsOpContinuation:
+			// ...and a continuation frame needs a message to continue
+			if !fragmented {
+				c.closeWithCode(WebSocketCloseProtocolError, "continuation frame without a fragmented message")
+				return
+			}
+			message = append(message, payload...)
+		default:
+			c.closeWithCode(WebSocketCloseProtocolError, fmt.Sprintf("unknown opcode %d", opcode))
+			return
+		}
+
+		if len(message) > maxWebSocketMessageSize {
+			c.closeWithCode(WebSocketCloseMessageTooBig, "message exceeds size limit")
+			return
+		}
+		fragmented = !fin
+		if !fin {
+			continue
+		}
+
+		if messageOp == wsOpBinary {
+			debug.Warn("WebSocket: Ignoring binary message", debug.F("size", len(message)))
+			continue
+		}
+
+		msgs, err := DecodeMessages(message)
+		if err != nil {
+			debug.Error("WebSocket: Failed to decode JSON-RPC message",
+				debug.F("error", err),
+				debug.F("data", string(message)))
+			continue
+		}
+		for _, msg := range msgs {
+			select {
+			case c.incoming <- msg:
+			case <-c.done:
+				return
+			}
+		}
+	}
+}
+
+// keepalive pings the server and closes the connection if pongs stop arriving
+func (c *webSocketConn) keepalive(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			sincePong := time.Since(c.lastPong)
+			c.mu.Unlock()
+
+			if sincePong > 2*interval {
+				debug.Error("WebSocket: Keepalive timed out", debug.F("sincePong", sincePong))
+				c.mu.Lock()
+				c.closeErr = &WebSocketCloseError{Code: WebSocketCloseGoingAway, Reason: "keepalive timeout"}
+				c.mu.Unlock()
+				c.closeWithCode(WebSocketCloseGoingAway, "keepalive timeout")
+				return
+			}
+
+			if err := c.writeFrame(wsOpPing, []byte("mcp-tui")); err != nil {
+				debug.Warn("WebSocket: Failed to send ping", debug.F("error", err))
+			}
+		}
+	}
+}
+
+// writeFrame writes a single unfragmented frame within webSocketWriteTimeout
+func (c *webSocketConn) writeFrame(opcode byte, payload []byte) error {
+	return c.writeFrameBy(opcode, payload, time.Time{})
+}
+
+// writeFrameBy writes a single unfragmented frame, giving up at deadline, or
+// after webSocketWriteTimeout if it is zero. The deadline is set and cleared
+// under the same lock as the write, so no other frame's deadline applies to
+// this one.
+func (c *webSocketConn) writeFrameBy(opcode byte, payload []byte, deadline time.Time) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if deadline.IsZero() {
+		deadline = time.Now().Add(webSocketWriteTimeout)
+	}
+	c.conn.SetWriteDeadline(deadline)
+	defer c.conn.SetWriteDeadline(time.Time{})
+	return writeWebSocketFrame(c.conn, opcode, payload, c.isClient)
+}
+
+// parseClosePayload extracts the status code and reason from a close frame
+func parseClosePayload(payload []byte) (int, string) {
+	if len(payload) < 2 {
+		return WebSocketCloseNoStatus, ""
+	}
+	return int(binary.BigEndian.Uint16(payload[:2])), string(payload[2:])
+}
+
+// writeWebSocketFrame encodes one frame with FIN set, masking it when required
+func writeWebSocketFrame(w io.Writer, opcode byte, payload []byte, mask bool) error {
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | opcode
+
+	length := len(payload)
+	switch {
+	case length <= 125:
+		header[1] = byte(length)
+	case length <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	data := payload
+	if mask {
+		header[1] |= 0x80
+		var key [4]byte
+		if _, err := rand.Read(key[:]); err != nil {
+			return fmt.Errorf("failed to generate frame mask: %w", err)
+		}
+		header = append(header, key[:]...)
+		data = make([]byte, length)
+		for i := range payload {
+			data[i] = payload[i] ^ key[i%4]
+		}
+	}
+
+	if _, err := w.Write(append(header, data...)); err != nil {
+		return fmt.Errorf("failed to write WebSocket frame: %w", err)
+	}
+	return nil
+}
+
+// readWebSocketFrame decodes one frame, unmasking the payload if needed
+func readWebSocketFrame(r io.Reader, expectMasked bool) (fin bool, opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err = io.ReadFull(r, head[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin = head[0]&0x80 != 0
+	opcode = head[0] & 0x0F
+	masked := head[1]&0x80 != 0
+
+	if masked != expectMasked {
+		return false, 0, nil, fmt.Errorf("unexpected frame masking (masked=%v)", masked)
+	}
+
+	length := uint64(head[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if length > maxWebSocketMessageSize {
+		return false, 0, nil, fmt.Errorf("WebSocket frame of %d bytes exceeds size limit", length)
+	}
+
+	var key [4]byte
+	if masked {
+		if _, err = io.ReadFull(r, key[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= key[i%4]
+		}
+	}
+
+	return fin, opcode, payload, nil
+}
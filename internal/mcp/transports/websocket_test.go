@@ -0,0 +1,406 @@
+package transports
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+)
+
+// echoServer is an in-process WebSocket server that echoes text messages back
+type echoServer struct {
+	*httptest.Server
+
+	protocol string // subprotocol to select ("" selects none)
+
+	mu          sync.Mutex
+	lastHeaders http.Header
+	pongs       int
+	closes      [][]byte // Payloads of the close frames received
+	conn        net.Conn
+	bw          *bufio.ReadWriter
+}
+
+func newEchoServer(t *testing.T, protocol string) *echoServer {
+	t.Helper()
+	es := &echoServer{protocol: protocol}
+	es.Server = httptest.NewServer(http.HandlerFunc(es.handle))
+	t.Cleanup(es.Close)
+	return es
+}
+
+// wsURL returns the ws:// URL of the server
+func (es *echoServer) wsURL() string {
+	return "ws" + strings.TrimPrefix(es.URL, "http") + "/mcp"
+}
+
+func (es *echoServer) handle(w http.ResponseWriter, r *http.Request) {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		http.Error(w, "upgrade required", http.StatusUpgradeRequired)
+		return
+	}
+
+	conn, bw, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		return
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + computeAcceptKey(r.Header.Get("Sec-WebSocket-Key")) + "\r\n"
+	if es.protocol != "" {
+		response += "Sec-WebSocket-Protocol: " + es.protocol + "\r\n"
+	}
+
+	es.mu.Lock()
+	es.lastHeaders = r.Header.Clone()
+	es.conn = conn
+	es.bw = bw
+	bw.WriteString(response + "\r\n")
+	bw.Flush()
+	es.mu.Unlock()
+
+	for {
+		_, opcode, payload, err := readWebSocketFrame(bw, true)
+		if err != nil {
+			conn.Close()
+			return
+		}
+		switch opcode {
+		case wsOpText:
+			es.send(wsOpText, payload)
+		case wsOpPing:
+			es.send(wsOpPong, payload)
+		case wsOpPong:
+			es.mu.Lock()
+			es.pongs++
+			es.mu.Unlock()
+		case wsOpClose:
+			es.mu.Lock()
+			es.closes = append(es.closes, payload)
+			es.mu.Unlock()
+			es.send(wsOpClose, payload)
+			conn.Close()
+			return
+		}
+	}
+}
+
+// send writes an unmasked server frame
+func (es *echoServer) send(opcode byte, payload []byte) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	writeWebSocketFrame(es.bw, opcode, payload, false)
+	es.bw.Flush()
+}
+
+func (es *echoServer) pongCount() int {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	return es.pongs
+}
+
+func newTestRequest(t *testing.T, id int64, method string) *jsonrpc.Request {
+	t.Helper()
+	reqID, err := MakeID(float64(id))
+	if err != nil {
+		t.Fatalf("MakeID failed: %v", err)
+	}
+	return &jsonrpc.Request{ID: reqID, Method: method, Params: json.RawMessage(`{"name":"echo"}`)}
+}
+
+func dialTestWebSocket(t *testing.T, config *TransportConfig) *webSocketConn {
+	t.Helper()
+	transport, _, err := NewFactory().CreateTransport(config)
+	if err != nil {
+		t.Fatalf("CreateTransport failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := transport.Connect(ctx)
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn.(*webSocketConn)
+}
+
+func TestWebSocketTransportEcho(t *testing.T) {
+	server := newEchoServer(t, "mcp")
+
+	conn := dialTestWebSocket(t, &TransportConfig{
+		Type:    TransportWebSocket,
+		URL:     server.wsURL(),
+		Headers: map[string]string{"Authorization": "Bearer test-token"},
+		Timeout: 5 * time.Second,
+	})
+
+	if conn.Subprotocol() != "mcp" {
+		t.Errorf("Expected negotiated subprotocol 'mcp', got %q", conn.Subprotocol())
+	}
+
+	server.mu.Lock()
+	headers := server.lastHeaders
+	server.mu.Unlock()
+	if got := headers.Get("Authorization"); got != "Bearer test-token" {
+		t.Errorf("Expected custom Authorization header, got %q", got)
+	}
+	if got := headers.Get("Sec-WebSocket-Protocol"); got != DefaultWebSocketSubprotocol {
+		t.Errorf("Expected default subprotocol offer, got %q", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := conn.Write(ctx, newTestRequest(t, 7, "tools/call")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	msg, err := conn.Read(ctx)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	req, ok := msg.(*jsonrpc.Request)
+	if !ok {
+		t.Fatalf("Expected echoed request, got %T", msg)
+	}
+	if req.Method != "tools/call" {
+		t.Errorf("Expected method tools/call, got %q", req.Method)
+	}
+	if req.ID.Raw() != int64(7) {
+		t.Errorf("Expected id 7, got %v", req.ID.Raw())
+	}
+	if string(req.Params) != `{"name":"echo"}` {
+		t.Errorf("Unexpected params: %s", req.Params)
+	}
+}
+
+func TestWebSocketTransportRejectsUnrequestedSubprotocol(t *testing.T) {
+	server := newEchoServer(t, "graphql-ws")
+
+	transport, _, err := NewFactory().CreateTransport(&TransportConfig{
+		Type:         TransportWebSocket,
+		URL:          server.wsURL(),
+		Subprotocols: []string{"mcp"},
+	})
+	if err != nil {
+		t.Fatalf("CreateTransport failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = transport.Connect(ctx)
+	if err == nil {
+		t.Fatal("Expected handshake to fail for unrequested subprotocol")
+	}
+	if !strings.Contains(err.Error(), "unrequested subprotocol") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestWebSocketTransportPingPong(t *testing.T) {
+	server := newEchoServer(t, "")
+
+	conn := dialTestWebSocket(t, &TransportConfig{
+		Type:         TransportWebSocket,
+		URL:          server.wsURL(),
+		PingInterval: 20 * time.Millisecond,
+	})
+
+	// Server-initiated ping must be answered with a pong
+	server.send(wsOpPing, []byte("hello"))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for server.pongCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if server.pongCount() == 0 {
+		t.Fatal("Expected client to answer server ping")
+	}
+
+	// Client keepalive pings are answered by the server, keeping the connection open
+	time.Sleep(150 * time.Millisecond)
+	if status := conn.CloseStatus(); status != nil {
+		t.Fatalf("Expected connection to stay open, got %v", status)
+	}
+}
+
+func TestWebSocketTransportCloseCode(t *testing.T) {
+	server := newEchoServer(t, "mcp")
+
+	conn := dialTestWebSocket(t, &TransportConfig{
+		Type: TransportWebSocket,
+		URL:  server.wsURL(),
+	})
+
+	payload := []byte{0x0F, 0xA1} // 4001
+	payload = append(payload, "session expired"...)
+	server.send(wsOpClose, payload)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := conn.Read(ctx)
+	var closeErr *WebSocketCloseError
+	if !errors.As(err, &closeErr) {
+		t.Fatalf("Expected WebSocketCloseError, got %v", err)
+	}
+	if closeErr.Code != 4001 || closeErr.Reason != "session expired" {
+		t.Errorf("Unexpected close status: %+v", closeErr)
+	}
+
+	if err := conn.Write(ctx, newTestRequest(t, 1, "ping")); err == nil {
+		t.Error("Expected write after close to fail")
+	}
+}
+
+func TestWebSocketTransportEchoesCloseWithoutStatus(t *testing.T) {
+	server := newEchoServer(t, "mcp")
+
+	conn := dialTestWebSocket(t, &TransportConfig{
+		Type: TransportWebSocket,
+		URL:  server.wsURL(),
+	})
+
+	// A close frame without a status code must not be echoed with 1005
+	server.send(wsOpClose, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := conn.Read(ctx); err == nil {
+		t.Fatal("Expected read after close to fail")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		server.mu.Lock()
+		closes := server.closes
+		server.mu.Unlock()
+		if len(closes) > 0 {
+			if len(closes[0]) != 0 {
+				t.Errorf("Expected an empty close payload, got %v", closes[0])
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Close frame was not echoed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWebSocketTransportCloseDuringStalledWrite(t *testing.T) {
+	server := newEchoServer(t, "mcp")
+
+	conn := dialTestWebSocket(t, &TransportConfig{
+		Type: TransportWebSocket,
+		URL:  server.wsURL(),
+	})
+
+	// Holding the server's lock stalls its echo of the first message, so
+	// it stops reading and the large write after it blocks
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if err := conn.Write(context.Background(), newTestRequest(t, 1, "ping")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	large := newTestRequest(t, 2, "tools/call")
+	large.Params = json.RawMessage(`{"data":"` + strings.Repeat("x", 64<<20) + `"}`)
+	go conn.Write(context.Background(), large)
+	time.Sleep(200 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		conn.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close hung behind a stalled write")
+	}
+}
+
+func TestWebSocketTransportRejectsStrayContinuation(t *testing.T) {
+	server := newEchoServer(t, "mcp")
+
+	conn := dialTestWebSocket(t, &TransportConfig{
+		Type: TransportWebSocket,
+		URL:  server.wsURL(),
+	})
+
+	// A continuation frame with no fragmented message to continue
+	server.send(wsOpContinuation, []byte(`{"jsonrpc":"2.0","method":"x"}`))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := conn.Read(ctx)
+	var closeErr *WebSocketCloseError
+	if !errors.As(err, &closeErr) {
+		t.Fatalf("Expected WebSocketCloseError, got %v", err)
+	}
+	if closeErr.Code != WebSocketCloseProtocolError {
+		t.Errorf("Expected close code %d, got %+v", WebSocketCloseProtocolError, closeErr)
+	}
+}
+
+func TestWebSocketConfigValidation(t *testing.T) {
+	f := NewFactory()
+
+	if err := f.ValidateConfig(&TransportConfig{Type: TransportWebSocket}); err == nil {
+		t.Error("Expected error for missing URL")
+	}
+	if err := f.ValidateConfig(&TransportConfig{Type: TransportWebSocket, URL: "http://localhost/mcp"}); err == nil {
+		t.Error("Expected error for non-ws scheme")
+	}
+	if err := f.ValidateConfig(&TransportConfig{Type: TransportWebSocket, URL: "wss://example.com/mcp"}); err != nil {
+		t.Errorf("Unexpected error for wss URL: %v", err)
+	}
+}
+
+func TestJSONRPCCodecRoundTrip(t *testing.T) {
+	payloads := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`,
+		`{"jsonrpc":"2.0","id":"abc","result":{"tools":[]}}`,
+		`{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"Method not found"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+	}
+
+	for _, payload := range payloads {
+		msg, err := DecodeMessage([]byte(payload))
+		if err != nil {
+			t.Fatalf("DecodeMessage(%s) failed: %v", payload, err)
+		}
+		encoded, err := EncodeMessage(msg)
+		if err != nil {
+			t.Fatalf("EncodeMessage failed: %v", err)
+		}
+		if string(encoded) != payload {
+			t.Errorf("Round trip mismatch:\n got: %s\nwant: %s", encoded, payload)
+		}
+	}
+
+	msgs, err := DecodeMessages([]byte(`[` + payloads[0] + `,` + payloads[3] + `]`))
+	if err != nil || len(msgs) != 2 {
+		t.Fatalf("Expected batch of 2 messages, got %d (%v)", len(msgs), err)
+	}
+
+	if _, err := DecodeMessage([]byte(`{"jsonrpc":"1.0","id":1,"method":"x"}`)); err == nil {
+		t.Error("Expected error for wrong version tag")
+	}
+}
@@ -3,6 +3,8 @@ package mcp
 import (
 	"context"
 	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/session"
 	"time"
 )
 
@@ -29,10 +31,25 @@ type Service interface {
 	// Server info
 	GetServerInfo() *ServerInfo
 
+	// EventLog returns the MCP messages exchanged with this server
+	EventLog() *debug.MCPLogger
+
 	// Connection health and monitoring
 	GetConnectionHealth() map[string]interface{}
 	ConfigureReconnection(maxAttempts int, delay time.Duration)
 	ConfigureHealthCheck(interval time.Duration)
+	OnConnectionStatus(fn func(session.Status)) func()
+
+	// Session state, restored after a reconnection
+	SetLogLevel(ctx context.Context, level string) error
+	SubscribeResource(ctx context.Context, uri string) error
+	UnsubscribeResource(ctx context.Context, uri string) error
+
+	// Session persistence, configured by the session settings
+	SessionSnapshot() *session.Snapshot
+	SaveSession(snapshot *session.Snapshot) error
+	PersistenceInterval() time.Duration
+	ResumeSession(ctx context.Context, snapshot *session.Snapshot) (resumed bool, err error)
 
 	// Error handling and diagnostics
 	GetErrorStatistics() map[string]interface{}
@@ -136,4 +153,5 @@ type ServerInfo struct {
 	ProtocolVersion string                 `json:"protocolVersion"`
 	Capabilities    map[string]interface{} `json:"capabilities"`
 	Connected       bool                   `json:"connected"`
+	Transport       string                 `json:"transport,omitempty"` // Transport in use, after any auto negotiation
 }
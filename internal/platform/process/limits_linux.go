@@ -0,0 +1,125 @@
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// limitsEnv and targetEnv pass the limits to set, and the program to run
+	// under them, to a process started as the limits shim
+	limitsEnv = "MCP_TUI_PROCESS_LIMITS"
+	targetEnv = "MCP_TUI_PROCESS_TARGET"
+
+	// shimFailed is the exit code of a shim that could not run its program
+	shimFailed = 127
+)
+
+// rlimit names a resource limit as the shim receives it
+type rlimit struct {
+	name     string
+	resource int
+}
+
+// rlimits lists the resource limits Limits can set, in the order of its fields
+var rlimits = []rlimit{
+	{"cpu", unix.RLIMIT_CPU},
+	{"as", unix.RLIMIT_AS},
+	{"nofile", unix.RLIMIT_NOFILE},
+	{"nproc", unix.RLIMIT_NPROC},
+}
+
+func init() {
+	if spec, ok := os.LookupEnv(limitsEnv); ok {
+		runShim(spec, os.Getenv(targetEnv))
+	}
+}
+
+// limitCommand makes a command start its program under the limits. A limit
+// set on a running process would come too late for the children it forked
+// and the memory it allocated first, so the command instead runs this
+// executable as a shim: it sets the limits on itself with setrlimit(2) and
+// then execs the program, which keeps the PID, the process group and the
+// arguments, and starts with the limits in place.
+func limitCommand(cmd *exec.Cmd, limits Limits) error {
+	if limits.IsZero() || cmd.Err != nil {
+		return nil
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the executable to set resource limits with: %w", err)
+	}
+
+	var spec []string
+	for i, value := range []uint64{limits.CPUSeconds, limits.AddressSpace, limits.OpenFiles, limits.Processes} {
+		if value != 0 {
+			spec = append(spec, rlimits[i].name+"="+strconv.FormatUint(value, 10))
+		}
+	}
+	cmd.Env = append(cmd.Environ(), limitsEnv+"="+strings.Join(spec, ","), targetEnv+"="+cmd.Path)
+	cmd.Path = self
+	return nil
+}
+
+// runShim sets the limits in spec and execs target with the shim's own
+// arguments, never returning
+func runShim(spec, target string) {
+	err := setLimits(spec)
+	if err == nil {
+		env := make([]string, 0, len(os.Environ()))
+		for _, entry := range os.Environ() {
+			if !strings.HasPrefix(entry, limitsEnv+"=") && !strings.HasPrefix(entry, targetEnv+"=") {
+				env = append(env, entry)
+			}
+		}
+		err = syscall.Exec(target, os.Args, env)
+		err = fmt.Errorf("failed to run %s: %w", target, err)
+	}
+	fmt.Fprintf(os.Stderr, "mcp-tui: %v\n", err)
+	os.Exit(shimFailed)
+}
+
+// setLimits sets the limits in spec on the calling process. A limit above
+// the current hard limit is clamped to it, since only privileged processes
+// may raise a hard limit.
+func setLimits(spec string) error {
+	for _, setting := range strings.Split(spec, ",") {
+		name, text, _ := strings.Cut(setting, "=")
+		value, err := strconv.ParseUint(text, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s limit %q", name, text)
+		}
+		resource := -1
+		for _, r := range rlimits {
+			if r.name == name {
+				resource = r.resource
+			}
+		}
+		if resource < 0 {
+			return fmt.Errorf("unknown resource limit %q", name)
+		}
+
+		var current syscall.Rlimit
+		if err := syscall.Getrlimit(resource, &current); err != nil {
+			return fmt.Errorf("failed to read %s limit: %w", name, err)
+		}
+		if current.Max != unix.RLIM_INFINITY && value > current.Max {
+			value = current.Max
+		}
+		// syscall.Setrlimit, unlike a raw prlimit, also keeps the runtime
+		// from restoring its own open files limit before exec
+		if err := syscall.Setrlimit(resource, &syscall.Rlimit{Cur: value, Max: value}); err != nil {
+			return fmt.Errorf("failed to set %s limit: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+// +build !linux
+
+package process
+
+import "os/exec"
+
+// limitCommand rejects resource limits, which need setrlimit(2) and a
+// shim that execs the program
+func limitCommand(cmd *exec.Cmd, limits Limits) error {
+	if limits.IsZero() {
+		return nil
+	}
+	return ErrLimitsUnsupported
+}
@@ -2,18 +2,34 @@ package process
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 )
 
+// DefaultKillTimeout is how long a process may take to exit after being asked
+// to terminate before it is killed
+const DefaultKillTimeout = 2 * time.Second
+
+// ErrLimitsUnsupported is returned when resource limits are requested on a
+// platform that cannot apply them
+var ErrLimitsUnsupported = errors.New("resource limits are only supported on Linux")
+
 // Manager handles process lifecycle management
 type Manager interface {
 	// Start starts a process with the given command and arguments
 	Start(ctx context.Context, command string, args []string) (Process, error)
 
+	// StartWithOptions starts a process with a working directory, environment,
+	// standard streams and resource limits
+	StartWithOptions(ctx context.Context, command string, args []string, opts Options) (Process, error)
+
 	// List returns all managed processes
 	List() []Process
 
@@ -52,16 +68,71 @@ type Process interface {
 
 	// ExitCode returns the exit code if the process has terminated
 	ExitCode() (int, bool)
+
+	// ExitSignal returns the signal that terminated the process, if any
+	ExitSignal() (string, bool)
+}
+
+// Options customizes how a process is started
+type Options struct {
+	Dir string   // Working directory ("" uses the current one)
+	Env []string // KEY=value entries added to the inherited environment
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	Limits      Limits
+	KillTimeout time.Duration // Grace period between SIGTERM and SIGKILL (0 uses DefaultKillTimeout)
+}
+
+// Limits are resource limits a process starts with, set before its program
+// runs. Zero leaves a limit unchanged. Limits are inherited by the process's
+// children.
+type Limits struct {
+	CPUSeconds   uint64 // RLIMIT_CPU: CPU time in seconds
+	AddressSpace uint64 // RLIMIT_AS: virtual memory in bytes
+	OpenFiles    uint64 // RLIMIT_NOFILE: open file descriptors
+	Processes    uint64 // RLIMIT_NPROC: processes for the user, not enforced for root
+}
+
+// IsZero reports whether no limit is set
+func (l Limits) IsZero() bool {
+	return l == Limits{}
+}
+
+// newCommand builds the command for a process started with opts
+func newCommand(ctx context.Context, command string, args []string, opts Options) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Dir = opts.Dir
+	if len(opts.Env) > 0 {
+		cmd.Env = append(os.Environ(), opts.Env...)
+	}
+	cmd.Stdin = opts.Stdin
+	cmd.Stdout = opts.Stdout
+	cmd.Stderr = opts.Stderr
+	// Grandchildren holding the output pipes must not keep Wait blocked
+	cmd.WaitDelay = killTimeout(opts)
+	return cmd
+}
+
+// killTimeout returns the grace period for a process started with opts
+func killTimeout(opts Options) time.Duration {
+	if opts.KillTimeout > 0 {
+		return opts.KillTimeout
+	}
+	return DefaultKillTimeout
 }
 
 // process implements the Process interface
 type process struct {
-	cmd      *exec.Cmd
-	command  string
-	args     []string
-	finished bool
-	exitCode int
-	mu       sync.RWMutex
+	cmd        *exec.Cmd
+	command    string
+	args       []string
+	finished   bool
+	exitCode   int
+	exitSignal string
+	mu         sync.RWMutex
 }
 
 // NewProcess creates a new process wrapper
@@ -133,6 +204,7 @@ func (p *process) Wait() error {
 	p.finished = true
 	if p.cmd.ProcessState != nil {
 		p.exitCode = p.cmd.ProcessState.ExitCode()
+		p.exitSignal = signalName(p.cmd.ProcessState)
 	}
 	p.mu.Unlock()
 
@@ -147,6 +219,14 @@ func (p *process) ExitCode() (int, bool) {
 	return p.exitCode, p.finished
 }
 
+// ExitSignal returns the terminating signal if the process was killed by one
+func (p *process) ExitSignal() (string, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.exitSignal, p.exitSignal != ""
+}
+
 // manager implements the Manager interface
 type manager struct {
 	processes []Process
@@ -200,14 +280,20 @@ func NewManager() Manager {
 
 // Start starts a new process
 func (m *manager) Start(ctx context.Context, command string, args []string) (Process, error) {
+	return m.StartWithOptions(ctx, command, args, Options{})
+}
+
+// StartWithOptions starts a new process with the given options
+func (m *manager) StartWithOptions(ctx context.Context, command string, args []string, opts Options) (Process, error) {
 	// Validate command and arguments for security
 	if err := validateCommand(command, args); err != nil {
 		return nil, fmt.Errorf("command validation failed: %w", err)
 	}
 
-	cmd := exec.CommandContext(ctx, command, args...)
-
-	// Platform-specific setup will be added
+	cmd := newCommand(ctx, command, args, opts)
+	if err := limitCommand(cmd, opts.Limits); err != nil {
+		return nil, err
+	}
 
 	if err := cmd.Start(); err != nil {
 		return nil, err
@@ -246,19 +332,31 @@ func (m *manager) Kill(pid int) error {
 	return nil // Process not found, maybe already terminated
 }
 
-// KillAll terminates all managed processes
+// KillAll terminates all managed processes, concurrently so that each one's
+// grace period runs in parallel
 func (m *manager) KillAll() error {
 	m.mu.RLock()
 	processes := make([]Process, len(m.processes))
 	copy(processes, m.processes)
 	m.mu.RUnlock()
 
-	var lastErr error
+	var (
+		wg      sync.WaitGroup
+		errMu   sync.Mutex
+		lastErr error
+	)
 	for _, proc := range processes {
-		if err := proc.Kill(); err != nil {
-			lastErr = err
-		}
+		wg.Add(1)
+		go func(proc Process) {
+			defer wg.Done()
+			if err := proc.Kill(); err != nil {
+				errMu.Lock()
+				lastErr = err
+				errMu.Unlock()
+			}
+		}(proc)
 	}
+	wg.Wait()
 
 	return lastErr
 }
@@ -0,0 +1,171 @@
+//go:build linux
+// +build linux
+
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// alive reports whether pid exists and is not a zombie
+func alive(pid int) bool {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return false
+	}
+	// The state follows the parenthesised command name
+	fields := strings.Fields(string(data[bytes.LastIndexByte(data, ')')+1:]))
+	return len(fields) > 0 && fields[0] != "Z"
+}
+
+// startWithChild starts a shell that spawns a background sleep and reports its PID
+func startWithChild(t *testing.T, manager Manager, script string, opts Options) (Process, int) {
+	reader, writer := io.Pipe()
+	opts.Stdout = writer
+	proc, err := manager.StartWithOptions(context.Background(), "sh", []string{"-c", script}, opts)
+	require.NoError(t, err)
+
+	line, err := bufio.NewReader(reader).ReadString('\n')
+	require.NoError(t, err)
+	go io.Copy(io.Discard, reader)
+
+	child, err := strconv.Atoi(strings.TrimSpace(line))
+	require.NoError(t, err)
+	require.True(t, alive(child))
+	return proc, child
+}
+
+func TestStartWithOptions(t *testing.T) {
+	manager := NewUnixManager(context.Background())
+	defer manager.Close()
+
+	dir := t.TempDir()
+	var out bytes.Buffer
+	proc, err := manager.StartWithOptions(context.Background(), "sh", []string{"-c", "pwd; echo $MCP_TUI_TEST"}, Options{
+		Dir:    dir,
+		Env:    []string{"MCP_TUI_TEST=hello"},
+		Stdout: &out,
+	})
+	require.NoError(t, err)
+	require.NoError(t, proc.Wait())
+
+	assert.Equal(t, dir+"\nhello\n", out.String())
+	code, exited := proc.ExitCode()
+	assert.True(t, exited)
+	assert.Equal(t, 0, code)
+	_, signaled := proc.ExitSignal()
+	assert.False(t, signaled)
+}
+
+func TestKillProcessGroup(t *testing.T) {
+	manager := NewUnixManager(context.Background())
+	defer manager.Close()
+
+	t.Run("Grandchildren_Are_Killed", func(t *testing.T) {
+		proc, child := startWithChild(t, manager, "sleep 30 & echo $!; wait", Options{})
+
+		require.NoError(t, proc.Kill())
+		assert.False(t, proc.IsRunning())
+		assert.Eventually(t, func() bool { return !alive(child) }, 2*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("SIGTERM_Ignored", func(t *testing.T) {
+		opts := Options{KillTimeout: 200 * time.Millisecond}
+		proc, child := startWithChild(t, manager, `trap "" TERM; sleep 30 & echo $!; wait`, opts)
+
+		start := time.Now()
+		require.NoError(t, proc.Kill())
+		assert.GreaterOrEqual(t, time.Since(start), opts.KillTimeout)
+
+		signal, signaled := proc.ExitSignal()
+		assert.True(t, signaled)
+		assert.Equal(t, "SIGKILL", signal)
+		assert.Eventually(t, func() bool { return !alive(child) }, 2*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("Leader_Already_Exited", func(t *testing.T) {
+		// The shell exits at once, leaving its child behind in the group. The
+		// child holds the output pipe, so Wait returns after the kill timeout.
+		proc, child := startWithChild(t, manager, "sleep 30 & echo $!", Options{KillTimeout: 200 * time.Millisecond})
+		proc.Wait()
+		require.True(t, alive(child))
+
+		require.NoError(t, proc.Kill())
+		assert.Eventually(t, func() bool { return !alive(child) }, 2*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("Group_Already_Gone", func(t *testing.T) {
+		proc, err := manager.Start(context.Background(), "sh", []string{"-c", "exit 0"})
+		require.NoError(t, err)
+		require.NoError(t, proc.Wait())
+
+		assert.NoError(t, proc.Kill())
+		assert.NoError(t, proc.Kill(), "killing twice is harmless")
+	})
+}
+
+func TestExitStatus(t *testing.T) {
+	manager := NewUnixManager(context.Background())
+	defer manager.Close()
+
+	proc, err := manager.Start(context.Background(), "sh", []string{"-c", "exit 3"})
+	require.NoError(t, err)
+	assert.Error(t, proc.Wait())
+	code, _ := proc.ExitCode()
+	assert.Equal(t, 3, code)
+
+	proc, err = manager.Start(context.Background(), "sleep", []string{"30"})
+	require.NoError(t, err)
+	require.NoError(t, proc.Kill())
+	code, exited := proc.ExitCode()
+	assert.True(t, exited)
+	assert.Equal(t, -1, code)
+	signal, _ := proc.ExitSignal()
+	assert.Equal(t, "SIGTERM", signal)
+}
+
+func TestResourceLimits(t *testing.T) {
+	manager := NewUnixManager(context.Background())
+	defer manager.Close()
+
+	// The program reads its limits as soon as it runs, before it could fork
+	// or allocate anything, and hands them to a child it forks at once
+	var out bytes.Buffer
+	proc, err := manager.StartWithOptions(context.Background(), "sh", []string{"-c", `echo "$0"; env | grep MCP_TUI_PROCESS; sh -c "cat /proc/self/limits"`}, Options{
+		Limits: Limits{CPUSeconds: 10, AddressSpace: 1 << 30, OpenFiles: 64, Processes: 100},
+		Stdout: &out,
+	})
+	require.NoError(t, err)
+	require.NoError(t, proc.Wait())
+	output := out.String()
+
+	assert.True(t, strings.HasPrefix(output, "sh\n"), "the program keeps its arguments: %s", output)
+	assert.NotContains(t, output, "MCP_TUI_PROCESS", "the shim's environment is not passed on")
+	assert.Regexp(t, `Max cpu time\s+10\s+10\s+seconds`, output)
+	assert.Regexp(t, `Max address space\s+1073741824\s+1073741824\s+bytes`, output)
+	assert.Regexp(t, `Max open files\s+64\s+64\s+files`, output)
+	assert.Regexp(t, `Max processes\s+100\s+100\s+processes`, output)
+}
+
+func TestResourceLimitsMissingCommand(t *testing.T) {
+	manager := NewUnixManager(context.Background())
+	defer manager.Close()
+
+	_, err := manager.StartWithOptions(context.Background(), "no-such-command-mcp-tui", nil, Options{
+		Limits: Limits{OpenFiles: 64},
+	})
+	assert.ErrorIs(t, err, exec.ErrNotFound)
+}
@@ -5,10 +5,13 @@ package process
 
 import (
 	"context"
-	"os/exec"
+	"fmt"
+	"os"
 	"sync"
 	"syscall"
 	"time"
+
+	"golang.org/x/sys/unix"
 )
 
 // unixManager is the Unix-specific implementation of process management
@@ -36,9 +39,23 @@ func NewUnixManager(ctx context.Context) Manager {
 	return um
 }
 
+// NewPlatformManager creates the process manager for the current platform
+func NewPlatformManager(ctx context.Context) Manager {
+	return NewUnixManager(ctx)
+}
+
 // Start overrides the base manager to add Unix-specific process setup
 func (um *unixManager) Start(ctx context.Context, command string, args []string) (Process, error) {
-	cmd := exec.CommandContext(ctx, command, args...)
+	return um.StartWithOptions(ctx, command, args, Options{})
+}
+
+// StartWithOptions starts the process in its own process group, so that it
+// and every child it spawns can be signalled together, under its limits
+func (um *unixManager) StartWithOptions(ctx context.Context, command string, args []string, opts Options) (Process, error) {
+	cmd := newCommand(ctx, command, args, opts)
+	if err := limitCommand(cmd, opts.Limits); err != nil {
+		return nil, err
+	}
 
 	// Set process group ID for proper signal handling
 	cmd.SysProcAttr = &syscall.SysProcAttr{
@@ -56,7 +73,10 @@ func (um *unixManager) Start(ctx context.Context, command string, args []string)
 			command: command,
 			args:    args,
 		},
+		killTimeout: killTimeout(opts),
+		done:        make(chan struct{}),
 	}
+	go proc.wait()
 
 	um.mu.Lock()
 	um.processes = append(um.processes, proc)
@@ -73,7 +93,9 @@ func (um *unixManager) Close() error {
 	return nil
 }
 
-// zombieReaper continuously reaps zombie processes
+// zombieReaper periodically drops exited processes from tracking. Each
+// process is reaped by its own waiter as soon as it exits, so none is left a
+// zombie and Wait never races with the reaper.
 func (um *unixManager) zombieReaper() {
 	defer um.wg.Done()
 
@@ -85,126 +107,102 @@ func (um *unixManager) zombieReaper() {
 		case <-um.ctx.Done():
 			return
 		case <-ticker.C:
-			um.reapZombies()
-		}
-	}
-}
-
-// reapZombies reaps zombie child processes
-func (um *unixManager) reapZombies() {
-	um.mu.RLock()
-	processes := make([]Process, len(um.processes))
-	copy(processes, um.processes)
-	um.mu.RUnlock()
-
-	for _, proc := range processes {
-		if unixProc, ok := proc.(*unixProcess); ok {
-			pid := unixProc.PID()
-			if pid > 0 {
-				var status syscall.WaitStatus
-				wpid, err := syscall.Wait4(pid, &status, syscall.WNOHANG, nil)
-				if err == nil && wpid == pid {
-					// Process has exited, mark as finished
-					unixProc.markFinished(status.ExitStatus())
-				}
-			}
+			um.Cleanup()
 		}
 	}
-
-	// Clean up finished processes
-	um.Cleanup()
 }
 
 // unixProcess extends the base process with Unix-specific functionality
 type unixProcess struct {
 	*process
+	killTimeout time.Duration
+	done        chan struct{} // Closed once the process has been reaped
+	waitErr     error
 }
 
-// Kill overrides the base kill to handle process groups
-func (up *unixProcess) Kill() error {
-	up.mu.Lock()
-	defer up.mu.Unlock()
+// wait reaps the process and records how it exited
+func (up *unixProcess) wait() {
+	err := up.cmd.Wait()
 
-	if up.cmd == nil || up.cmd.Process == nil {
-		return nil
+	up.mu.Lock()
+	up.finished = true
+	up.waitErr = err
+	if state := up.cmd.ProcessState; state != nil {
+		up.exitCode = state.ExitCode()
+		up.exitSignal = signalName(state)
 	}
+	up.mu.Unlock()
 
-	return up.killProcessGroup()
+	close(up.done)
 }
 
-// killProcessGroup kills the process and its children
-func (up *unixProcess) killProcessGroup() error {
-	if up.cmd == nil || up.cmd.Process == nil {
-		return nil
-	}
+// Wait waits for the process to terminate
+func (up *unixProcess) Wait() error {
+	<-up.done
 
-	pid := up.cmd.Process.Pid
+	up.mu.RLock()
+	defer up.mu.RUnlock()
+	return up.waitErr
+}
 
-	// Try to get the process group ID
-	pgid, err := syscall.Getpgid(pid)
-	if err != nil {
-		// Process might already be dead
+// Kill terminates the whole process group: SIGTERM first, then SIGKILL once
+// the grace period passes. The group is sent SIGKILL at the end while it has
+// members, so children that outlived the leader or ignored SIGTERM do not
+// leak. A group that is already gone counts as killed.
+func (up *unixProcess) Kill() error {
+	if up.cmd == nil || up.cmd.Process == nil {
 		return nil
 	}
 
-	// Send SIGTERM to the process group
-	if pgid > 0 {
-		_ = syscall.Kill(-pgid, syscall.SIGTERM)
-	} else {
-		_ = up.cmd.Process.Signal(syscall.SIGTERM)
-	}
-
-	// Wait for graceful shutdown with timeout
-	done := make(chan error, 1)
-	go func() {
-		done <- up.cmd.Wait()
-	}()
+	// The leader's PID is the group ID because of Setpgid
+	pgid := up.cmd.Process.Pid
 
 	select {
-	case err := <-done:
-		up.markFinished(0)
-		return err
-	case <-time.After(2 * time.Second):
-		// Force kill if it didn't exit
-		if pgid > 0 {
-			_ = syscall.Kill(-pgid, syscall.SIGKILL)
-		} else {
-			_ = up.cmd.Process.Kill()
+	case <-up.done:
+	default:
+		_ = syscall.Kill(-pgid, syscall.SIGTERM)
+		select {
+		case <-up.done:
+		case <-time.After(up.killTimeout):
 		}
+	}
 
-		// Wait for the process to actually die
-		select {
-		case err := <-done:
-			up.markFinished(0)
-			return err
-		case <-time.After(1 * time.Second):
-			// Process is really stuck
-			up.markFinished(-1)
-			return nil
+	// A reaped leader's PID cannot be reused while the group it led still
+	// has members, so the group is only signalled after checking that it
+	// has some that are ours to signal
+	if err := syscall.Kill(-pgid, 0); err == nil {
+		if err := syscall.Kill(-pgid, syscall.SIGKILL); err != nil && err != syscall.ESRCH {
+			return fmt.Errorf("failed to kill process group %d: %w", pgid, err)
 		}
 	}
-}
 
-// markFinished marks the process as finished with the given exit code
-func (up *unixProcess) markFinished(exitCode int) {
-	up.finished = true
-	up.exitCode = exitCode
+	select {
+	case <-up.done:
+	case <-time.After(up.killTimeout):
+		return fmt.Errorf("process %d did not exit after SIGKILL", pgid)
+	}
+	return nil
 }
 
 // IsRunning checks if the process is still running (Unix-specific)
 func (up *unixProcess) IsRunning() bool {
-	up.mu.RLock()
-	defer up.mu.RUnlock()
-
-	if up.finished {
+	select {
+	case <-up.done:
 		return false
+	default:
+		return true
 	}
+}
 
-	if up.cmd == nil || up.cmd.Process == nil {
-		return false
+// signalName returns the name of the signal that terminated a process, or ""
+// if it exited normally
+func signalName(state *os.ProcessState) string {
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return ""
 	}
-
-	// Check if process exists by sending signal 0
-	err := up.cmd.Process.Signal(syscall.Signal(0))
-	return err == nil
+	if name := unix.SignalName(status.Signal()); name != "" {
+		return name
+	}
+	return status.Signal().String()
 }
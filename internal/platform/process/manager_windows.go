@@ -6,8 +6,6 @@ package process
 import (
 	"context"
 	"os"
-	"os/exec"
-	"sync"
 	"syscall"
 	"time"
 	"unsafe"
@@ -23,8 +21,10 @@ var (
 )
 
 const (
-	jobObjectLimitKillOnJobClose      = 0x00002000
-	jobObjectExtendedLimitInformation = 9
+	jobObjectLimitKillOnJobClose           = 0x00002000
+	jobObjectExtendedLimitInformationClass = 9
+	processSetQuota                        = 0x0100
+	processTerminate                       = 0x0001
 )
 
 type jobObjectExtendedLimitInformation struct {
@@ -75,9 +75,23 @@ func NewWindowsManager(ctx context.Context) Manager {
 	}
 }
 
+// NewPlatformManager creates the process manager for the current platform
+func NewPlatformManager(ctx context.Context) Manager {
+	return NewWindowsManager(ctx)
+}
+
 // Start creates a new process with job object management
 func (wm *windowsManager) Start(ctx context.Context, command string, args []string) (Process, error) {
-	cmd := exec.CommandContext(ctx, command, args...)
+	return wm.StartWithOptions(ctx, command, args, Options{})
+}
+
+// StartWithOptions creates a new process with job object management. Resource
+// limits are not supported on Windows.
+func (wm *windowsManager) StartWithOptions(ctx context.Context, command string, args []string, opts Options) (Process, error) {
+	cmd := newCommand(ctx, command, args, opts)
+	if err := limitCommand(cmd, opts.Limits); err != nil {
+		return nil, err
+	}
 
 	if err := cmd.Start(); err != nil {
 		return nil, err
@@ -89,7 +103,10 @@ func (wm *windowsManager) Start(ctx context.Context, command string, args []stri
 			command: command,
 			args:    args,
 		},
+		killTimeout: killTimeout(opts),
+		done:        make(chan struct{}),
 	}
+	go proc.wait()
 
 	// Create job object for process management
 	if err := proc.createJobObject(); err != nil {
@@ -114,7 +131,34 @@ func (wm *windowsManager) Close() error {
 // windowsProcess extends the base process with Windows-specific functionality
 type windowsProcess struct {
 	*process
-	jobHandle syscall.Handle
+	jobHandle   syscall.Handle
+	killTimeout time.Duration
+	done        chan struct{} // Closed once the process has exited
+	waitErr     error
+}
+
+// wait waits for the process to exit and records how it exited
+func (wp *windowsProcess) wait() {
+	err := wp.cmd.Wait()
+
+	wp.mu.Lock()
+	wp.finished = true
+	wp.waitErr = err
+	if state := wp.cmd.ProcessState; state != nil {
+		wp.exitCode = state.ExitCode()
+	}
+	wp.mu.Unlock()
+
+	close(wp.done)
+}
+
+// Wait waits for the process to terminate
+func (wp *windowsProcess) Wait() error {
+	<-wp.done
+
+	wp.mu.RLock()
+	defer wp.mu.RUnlock()
+	return wp.waitErr
 }
 
 // createJobObject creates a Windows job object for the process
@@ -147,74 +191,56 @@ func (wp *windowsProcess) createJobObject() error {
 
 // Kill overrides the base kill to use job objects
 func (wp *windowsProcess) Kill() error {
-	wp.mu.Lock()
-	defer wp.mu.Unlock()
-
 	if wp.cmd == nil || wp.cmd.Process == nil {
 		return nil
 	}
 
 	// If we have a job handle, terminate the entire job
+	wp.mu.Lock()
 	if wp.jobHandle != 0 {
 		terminateJobObject(wp.jobHandle, 1)
 		closeHandle(wp.jobHandle)
 		wp.jobHandle = 0
-		time.Sleep(100 * time.Millisecond)
+	}
+	wp.mu.Unlock()
+
+	select {
+	case <-wp.done:
+		return nil
+	default:
 	}
 
 	// Try graceful termination first
 	wp.cmd.Process.Signal(os.Interrupt)
 
-	// Wait for process to exit
-	done := make(chan error, 1)
-	go func() {
-		done <- wp.cmd.Wait()
-	}()
-
 	select {
-	case err := <-done:
-		wp.markFinished(0)
-		return err
-	case <-time.After(2 * time.Second):
+	case <-wp.done:
+		return nil
+	case <-time.After(wp.killTimeout):
 		// Force kill if it didn't exit
 		err := wp.cmd.Process.Kill()
 
 		select {
-		case <-done:
-			wp.markFinished(0)
-			return err
-		case <-time.After(1 * time.Second):
-			wp.markFinished(-1)
-			return err
+		case <-wp.done:
+		case <-time.After(wp.killTimeout):
 		}
+		return err
 	}
 }
 
-// markFinished marks the process as finished
-func (wp *windowsProcess) markFinished(exitCode int) {
-	wp.finished = true
-	wp.exitCode = exitCode
-}
-
 // IsRunning checks if the process is running on Windows
 func (wp *windowsProcess) IsRunning() bool {
-	wp.mu.RLock()
-	defer wp.mu.RUnlock()
-
-	if wp.finished {
-		return false
-	}
-
-	if wp.cmd == nil || wp.cmd.Process == nil {
-		return false
-	}
-
-	// On Windows, we can check the process state
-	if wp.cmd.ProcessState != nil {
+	select {
+	case <-wp.done:
 		return false
+	default:
+		return true
 	}
+}
 
-	return true
+// signalName returns "", since Windows processes are not ended by signals
+func signalName(state *os.ProcessState) string {
+	return ""
 }
 
 // Windows API helper functions
@@ -233,7 +259,7 @@ func setJobObjectLimits(jobHandle syscall.Handle) error {
 
 	_, _, err := procSetInformationJobObject.Call(
 		uintptr(jobHandle),
-		jobObjectExtendedLimitInformation,
+		jobObjectExtendedLimitInformationClass,
 		uintptr(unsafe.Pointer(&info)),
 		unsafe.Sizeof(info),
 	)
@@ -245,7 +271,7 @@ func setJobObjectLimits(jobHandle syscall.Handle) error {
 }
 
 func assignProcessToJob(jobHandle syscall.Handle, pid int) error {
-	handle, err := syscall.OpenProcess(syscall.PROCESS_SET_QUOTA|syscall.PROCESS_TERMINATE, false, uint32(pid))
+	handle, err := syscall.OpenProcess(processSetQuota|processTerminate, false, uint32(pid))
 	if err != nil {
 		return err
 	}
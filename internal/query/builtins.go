@@ -0,0 +1,810 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// builtin is a function taking its input and the unevaluated arguments
+type builtin func(input interface{}, args []node, vars *env) ([]interface{}, error)
+
+// builtins are the functions by name and number of arguments
+var builtins map[string]builtin
+
+func init() {
+	builtins = map[string]builtin{
+		"empty/0": func(interface{}, []node, *env) ([]interface{}, error) { return nil, nil },
+		"not/0":   value(func(v interface{}) (interface{}, error) { return !truthy(v), nil }),
+		"type/0":  value(func(v interface{}) (interface{}, error) { return typeName(v), nil }),
+		"length/0": value(func(v interface{}) (interface{}, error) {
+			switch v := v.(type) {
+			case nil:
+				return 0.0, nil
+			case string:
+				return float64(utf8.RuneCountInString(v)), nil
+			case []interface{}:
+				return float64(len(v)), nil
+			case map[string]interface{}:
+				return float64(len(v)), nil
+			case float64:
+				return math.Abs(v), nil
+			}
+			return nil, fmt.Errorf("%s has no length", typeName(v))
+		}),
+		"keys/0": value(func(v interface{}) (interface{}, error) {
+			switch v := v.(type) {
+			case map[string]interface{}:
+				keys := []interface{}{}
+				for _, key := range sortedKeys(v) {
+					keys = append(keys, key)
+				}
+				return keys, nil
+			case []interface{}:
+				keys := make([]interface{}, len(v))
+				for i := range v {
+					keys[i] = float64(i)
+				}
+				return keys, nil
+			}
+			return nil, fmt.Errorf("%s has no keys", typeName(v))
+		}),
+		"has/1": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			return withArg(input, args[0], vars, func(key interface{}) (interface{}, error) {
+				switch v := input.(type) {
+				case map[string]interface{}:
+					if k, ok := key.(string); ok {
+						_, found := v[k]
+						return found, nil
+					}
+				case []interface{}:
+					if k, ok := key.(float64); ok {
+						return k >= 0 && int(k) < len(v), nil
+					}
+				}
+				return nil, fmt.Errorf("cannot check whether %s has a %s key", typeName(input), typeName(key))
+			})
+		},
+		"contains/1": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			return withArg(input, args[0], vars, func(part interface{}) (interface{}, error) {
+				if typeName(input) != typeName(part) {
+					return nil, fmt.Errorf("%s and %s cannot have their containment checked", typeName(input), typeName(part))
+				}
+				return contains(input, part), nil
+			})
+		},
+
+		// Selection and mapping
+		"select/1": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			conds, err := eval(args[0], input, vars)
+			if err != nil {
+				return nil, err
+			}
+			var out []interface{}
+			for _, cond := range conds {
+				if truthy(cond) {
+					out = append(out, input)
+				}
+			}
+			return out, nil
+		},
+		"map/1": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			items, err := iterate(input)
+			if err != nil {
+				return nil, err
+			}
+			mapped := []interface{}{}
+			for _, item := range items {
+				values, err := eval(args[0], item, vars)
+				if err != nil {
+					return nil, err
+				}
+				mapped = append(mapped, values...)
+			}
+			return []interface{}{mapped}, nil
+		},
+		"map_values/1": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			first := func(item interface{}) (interface{}, bool, error) {
+				values, err := eval(args[0], item, vars)
+				if err != nil || len(values) == 0 {
+					return nil, false, err
+				}
+				return values[0], true, nil
+			}
+			switch v := input.(type) {
+			case map[string]interface{}:
+				out := make(map[string]interface{}, len(v))
+				for key, item := range v {
+					mapped, ok, err := first(item)
+					if err != nil {
+						return nil, err
+					}
+					if ok {
+						out[key] = mapped
+					}
+				}
+				return []interface{}{out}, nil
+			case []interface{}:
+				out := []interface{}{}
+				for _, item := range v {
+					mapped, ok, err := first(item)
+					if err != nil {
+						return nil, err
+					}
+					if ok {
+						out = append(out, mapped)
+					}
+				}
+				return []interface{}{out}, nil
+			}
+			return nil, fmt.Errorf("cannot iterate over %s", typeName(input))
+		},
+		"with_entries/1": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			return eval(pipeNode{callNode{name: "to_entries"}, pipeNode{callNode{name: "map", args: args}, callNode{name: "from_entries"}}}, input, vars)
+		},
+		"to_entries/0": value(func(v interface{}) (interface{}, error) {
+			object, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s has no keys", typeName(v))
+			}
+			entries := []interface{}{}
+			for _, key := range sortedKeys(object) {
+				entries = append(entries, map[string]interface{}{"key": key, "value": object[key]})
+			}
+			return entries, nil
+		}),
+		"from_entries/0": value(func(v interface{}) (interface{}, error) {
+			entries, ok := v.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot make an object from %s", typeName(v))
+			}
+			object := make(map[string]interface{}, len(entries))
+			for _, entry := range entries {
+				e, ok := entry.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("cannot make an object entry from %s", typeName(entry))
+				}
+				key := firstPresent(e, "key", "k", "name", "Name", "Key", "K")
+				var k string
+				switch key := key.(type) {
+				case string:
+					k = key
+				case float64, bool:
+					k = formatScalar(key)
+				default:
+					return nil, fmt.Errorf("object keys must be strings, not %s", typeName(key))
+				}
+				object[k] = firstPresent(e, "value", "v", "Value", "V")
+			}
+			return object, nil
+		}),
+
+		// Arrays
+		"add/0": value(func(v interface{}) (interface{}, error) {
+			items, err := iterate(v)
+			if err != nil {
+				return nil, err
+			}
+			var sum interface{}
+			for _, item := range items {
+				if sum, err = binary("+", sum, item); err != nil {
+					return nil, err
+				}
+			}
+			return sum, nil
+		}),
+		"first/0":   value(func(v interface{}) (interface{}, error) { return index(v, 0.0) }),
+		"last/0":    value(func(v interface{}) (interface{}, error) { return index(v, -1.0) }),
+		"reverse/0": value(reverse),
+		"sort/0": array(func(items []interface{}) (interface{}, error) {
+			sorted := append([]interface{}{}, items...)
+			sort.SliceStable(sorted, func(i, j int) bool { return compare(sorted[i], sorted[j]) < 0 })
+			return sorted, nil
+		}),
+		"unique/0": array(func(items []interface{}) (interface{}, error) {
+			return uniqueBy(items, items), nil
+		}),
+		"min/0": array(func(items []interface{}) (interface{}, error) { return extreme(items, items, -1), nil }),
+		"max/0": array(func(items []interface{}) (interface{}, error) { return extreme(items, items, 1), nil }),
+		"flatten/0": array(func(items []interface{}) (interface{}, error) {
+			return flatten(items, -1), nil
+		}),
+		"flatten/1": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			return withArg(input, args[0], vars, func(depth interface{}) (interface{}, error) {
+				items, ok := input.([]interface{})
+				d, isNumber := depth.(float64)
+				if !ok || !isNumber || d < 0 {
+					return nil, fmt.Errorf("flatten needs an array and a depth of 0 or more")
+				}
+				return flatten(items, int(d)), nil
+			})
+		},
+		"sort_by/1": byKey(func(items, keys []interface{}) interface{} {
+			order := make([]int, len(items))
+			for i := range order {
+				order[i] = i
+			}
+			sort.SliceStable(order, func(i, j int) bool { return compare(keys[order[i]], keys[order[j]]) < 0 })
+			sorted := make([]interface{}, len(items))
+			for i, o := range order {
+				sorted[i] = items[o]
+			}
+			return sorted
+		}),
+		"group_by/1": byKey(func(items, keys []interface{}) interface{} {
+			groups := []interface{}{}
+			var groupKeys []interface{}
+			for i, item := range items {
+				found := false
+				for g, key := range groupKeys {
+					if compare(key, keys[i]) == 0 {
+						groups[g] = append(groups[g].([]interface{}), item)
+						found = true
+						break
+					}
+				}
+				if !found {
+					groupKeys = append(groupKeys, keys[i])
+					groups = append(groups, []interface{}{item})
+				}
+			}
+			sort.SliceStable(groups, func(i, j int) bool { return compare(groupKeys[i], groupKeys[j]) < 0 })
+			return groups
+		}),
+		"unique_by/1": byKey(func(items, keys []interface{}) interface{} { return uniqueBy(items, keys) }),
+		"min_by/1":    byKey(func(items, keys []interface{}) interface{} { return extreme(items, keys, -1) }),
+		"max_by/1":    byKey(func(items, keys []interface{}) interface{} { return extreme(items, keys, 1) }),
+		"any/0": array(func(items []interface{}) (interface{}, error) {
+			for _, item := range items {
+				if truthy(item) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}),
+		"all/0": array(func(items []interface{}) (interface{}, error) {
+			for _, item := range items {
+				if !truthy(item) {
+					return false, nil
+				}
+			}
+			return true, nil
+		}),
+		"any/1": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			return quantify(input, args[0], vars, true)
+		},
+		"all/1": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			return quantify(input, args[0], vars, false)
+		},
+		"first/1": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			values, err := eval(args[0], input, vars)
+			if err != nil || len(values) == 0 {
+				return nil, err
+			}
+			return values[:1], nil
+		},
+		"last/1": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			values, err := eval(args[0], input, vars)
+			if err != nil || len(values) == 0 {
+				return nil, err
+			}
+			return values[len(values)-1:], nil
+		},
+		"limit/2": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			counts, err := eval(args[0], input, vars)
+			if err != nil {
+				return nil, err
+			}
+			var out []interface{}
+			for _, n := range counts {
+				count, ok := n.(float64)
+				if !ok {
+					return nil, fmt.Errorf("limit needs a number, not %s", typeName(n))
+				}
+				values, err := eval(args[1], input, vars)
+				if err != nil {
+					return nil, err
+				}
+				if count > 0 {
+					out = append(out, values[:min(len(values), int(count))]...)
+				}
+			}
+			return out, nil
+		},
+		"range/1": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			return rangeOf(input, literalNode{0.0}, args[0], vars)
+		},
+		"range/2": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			return rangeOf(input, args[0], args[1], vars)
+		},
+		"recurse/0": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			return recurse(input), nil
+		},
+		"recurse/1": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			out := []interface{}{input}
+			children, err := eval(args[0], input, vars)
+			if err != nil {
+				return nil, err
+			}
+			for _, child := range children {
+				values, err := builtins["recurse/1"](child, args, vars)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, values...)
+			}
+			return out, nil
+		},
+
+		// Types
+		"arrays/0":   ofType("array"),
+		"objects/0":  ofType("object"),
+		"strings/0":  ofType("string"),
+		"numbers/0":  ofType("number"),
+		"booleans/0": ofType("boolean"),
+		"nulls/0":    ofType("null"),
+		"values/0": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			if input == nil {
+				return nil, nil
+			}
+			return []interface{}{input}, nil
+		},
+		"scalars/0": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			switch input.(type) {
+			case []interface{}, map[string]interface{}:
+				return nil, nil
+			}
+			return []interface{}{input}, nil
+		},
+
+		// Conversions
+		"fromjson/0": value(func(v interface{}) (interface{}, error) {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s cannot be parsed as JSON", typeName(v))
+			}
+			var decoded interface{}
+			if err := json.Unmarshal([]byte(s), &decoded); err != nil {
+				return nil, fmt.Errorf("%s cannot be parsed as JSON: %w", brief(s), err)
+			}
+			return decoded, nil
+		}),
+		"tojson/0": value(func(v interface{}) (interface{}, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		}),
+		"tostring/0": value(func(v interface{}) (interface{}, error) { return toText(v) }),
+		"tonumber/0": value(func(v interface{}) (interface{}, error) {
+			switch v := v.(type) {
+			case float64:
+				return v, nil
+			case string:
+				if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+					return f, nil
+				}
+			}
+			return nil, fmt.Errorf("%s (%s) cannot be parsed as a number", typeName(v), brief(v))
+		}),
+		"floor/0": number(math.Floor),
+		"ceil/0":  number(math.Ceil),
+		"round/0": number(math.Round),
+		"sqrt/0":  number(math.Sqrt),
+		"abs/0":   number(math.Abs),
+
+		// Strings
+		"ascii_downcase/0": text(func(s string) (interface{}, error) { return strings.ToLower(s), nil }),
+		"ascii_upcase/0":   text(func(s string) (interface{}, error) { return strings.ToUpper(s), nil }),
+		"trim/0":           text(func(s string) (interface{}, error) { return strings.TrimSpace(s), nil }),
+		"ltrim/0":          text(func(s string) (interface{}, error) { return strings.TrimLeft(s, " \t\r\n"), nil }),
+		"rtrim/0":          text(func(s string) (interface{}, error) { return strings.TrimRight(s, " \t\r\n"), nil }),
+		"startswith/1":     textArg(func(s, arg string) (interface{}, error) { return strings.HasPrefix(s, arg), nil }),
+		"endswith/1":       textArg(func(s, arg string) (interface{}, error) { return strings.HasSuffix(s, arg), nil }),
+		"ltrimstr/1":       textArg(func(s, arg string) (interface{}, error) { return strings.TrimPrefix(s, arg), nil }),
+		"rtrimstr/1":       textArg(func(s, arg string) (interface{}, error) { return strings.TrimSuffix(s, arg), nil }),
+		"split/1":          textArg(func(s, arg string) (interface{}, error) { return splitString(s, arg), nil }),
+		"test/1": textArg(func(s, pattern string) (interface{}, error) {
+			re, err := compilePattern(pattern, "")
+			if err != nil {
+				return nil, err
+			}
+			return re.MatchString(s), nil
+		}),
+		"test/2": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			return textArgs(input, args, vars, func(s string, args []string) (interface{}, error) {
+				re, err := compilePattern(args[0], args[1])
+				if err != nil {
+					return nil, err
+				}
+				return re.MatchString(s), nil
+			})
+		},
+		"sub/2": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			return textArgs(input, args, vars, func(s string, args []string) (interface{}, error) {
+				re, err := compilePattern(args[0], "")
+				if err != nil {
+					return nil, err
+				}
+				if loc := re.FindStringIndex(s); loc != nil {
+					return s[:loc[0]] + args[1] + s[loc[1]:], nil
+				}
+				return s, nil
+			})
+		},
+		"gsub/2": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			return textArgs(input, args, vars, func(s string, args []string) (interface{}, error) {
+				re, err := compilePattern(args[0], "")
+				if err != nil {
+					return nil, err
+				}
+				return re.ReplaceAllLiteralString(s, args[1]), nil
+			})
+		},
+		"join/1": func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+			return withArg(input, args[0], vars, func(sep interface{}) (interface{}, error) {
+				s, ok := sep.(string)
+				items, isArray := input.([]interface{})
+				if !ok || !isArray {
+					return nil, fmt.Errorf("cannot join %s with %s", typeName(input), typeName(sep))
+				}
+				parts := make([]string, len(items))
+				for i, item := range items {
+					switch item := item.(type) {
+					case nil:
+					case string:
+						parts[i] = item
+					case float64, bool:
+						parts[i] = formatScalar(item)
+					default:
+						return nil, fmt.Errorf("cannot join %s", typeName(item))
+					}
+				}
+				return strings.Join(parts, s), nil
+			})
+		},
+	}
+}
+
+// hasBuiltin reports whether a function exists with that many arguments
+func hasBuiltin(name string, arity int) bool {
+	_, ok := builtins[fmt.Sprintf("%s/%d", name, arity)]
+	return ok
+}
+
+func callBuiltin(n callNode, input interface{}, vars *env) ([]interface{}, error) {
+	fn, ok := builtins[fmt.Sprintf("%s/%d", n.name, len(n.args))]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s/%d", n.name, len(n.args))
+	}
+	return fn(input, n.args, vars)
+}
+
+// value makes a builtin of a function of the input alone
+func value(fn func(interface{}) (interface{}, error)) builtin {
+	return func(input interface{}, _ []node, _ *env) ([]interface{}, error) {
+		out, err := fn(input)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{out}, nil
+	}
+}
+
+// array makes a builtin of a function of an array input
+func array(fn func([]interface{}) (interface{}, error)) builtin {
+	return value(func(v interface{}) (interface{}, error) {
+		items, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s is not an array", typeName(v))
+		}
+		return fn(items)
+	})
+}
+
+// number makes a builtin of a math function
+func number(fn func(float64) float64) builtin {
+	return value(func(v interface{}) (interface{}, error) {
+		f, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a number", typeName(v))
+		}
+		return fn(f), nil
+	})
+}
+
+// text makes a builtin of a function of a string input
+func text(fn func(string) (interface{}, error)) builtin {
+	return value(func(v interface{}) (interface{}, error) {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a string", typeName(v))
+		}
+		return fn(s)
+	})
+}
+
+// textArg makes a builtin of a function of a string input and argument
+func textArg(fn func(s, arg string) (interface{}, error)) builtin {
+	return func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+		return textArgs(input, args, vars, func(s string, args []string) (interface{}, error) {
+			return fn(s, args[0])
+		})
+	}
+}
+
+// textArgs calls fn with a string input and every combination of the
+// string arguments' outputs
+func textArgs(input interface{}, args []node, vars *env, fn func(string, []string) (interface{}, error)) ([]interface{}, error) {
+	s, ok := input.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a string", typeName(input))
+	}
+	combos := [][]string{{}}
+	for _, arg := range args {
+		values, err := eval(arg, input, vars)
+		if err != nil {
+			return nil, err
+		}
+		var next [][]string
+		for _, combo := range combos {
+			for _, v := range values {
+				str, ok := v.(string)
+				if !ok {
+					return nil, fmt.Errorf("%s is not a string", typeName(v))
+				}
+				next = append(next, append(append([]string{}, combo...), str))
+			}
+		}
+		combos = next
+	}
+
+	var out []interface{}
+	for _, combo := range combos {
+		v, err := fn(s, combo)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// withArg calls fn for each output of arg
+func withArg(input interface{}, arg node, vars *env, fn func(interface{}) (interface{}, error)) ([]interface{}, error) {
+	values, err := eval(arg, input, vars)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, v := range values {
+		result, err := fn(v)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, result)
+	}
+	return out, nil
+}
+
+// byKey makes a builtin of a function of an array and the key f gives
+// each item
+func byKey(fn func(items, keys []interface{}) interface{}) builtin {
+	return func(input interface{}, args []node, vars *env) ([]interface{}, error) {
+		items, ok := input.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s is not an array", typeName(input))
+		}
+		keys := make([]interface{}, len(items))
+		for i, item := range items {
+			values, err := eval(args[0], item, vars)
+			if err != nil {
+				return nil, err
+			}
+			if values == nil {
+				values = []interface{}{}
+			}
+			keys[i] = values
+		}
+		return []interface{}{fn(items, keys)}, nil
+	}
+}
+
+func ofType(name string) builtin {
+	return func(input interface{}, _ []node, _ *env) ([]interface{}, error) {
+		if typeName(input) == name {
+			return []interface{}{input}, nil
+		}
+		return nil, nil
+	}
+}
+
+func quantify(input interface{}, cond node, vars *env, anyOf bool) ([]interface{}, error) {
+	items, err := iterate(input)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		values, err := eval(cond, item, vars)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			if truthy(v) == anyOf {
+				return []interface{}{anyOf}, nil
+			}
+		}
+	}
+	return []interface{}{!anyOf}, nil
+}
+
+func rangeOf(input interface{}, fromArg, toArg node, vars *env) ([]interface{}, error) {
+	froms, err := eval(fromArg, input, vars)
+	if err != nil {
+		return nil, err
+	}
+	tos, err := eval(toArg, input, vars)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, from := range froms {
+		for _, to := range tos {
+			f, fok := from.(float64)
+			t, tok := to.(float64)
+			if !fok || !tok {
+				return nil, fmt.Errorf("range bounds must be numbers")
+			}
+			for i := f; i < t; i++ {
+				out = append(out, i)
+			}
+		}
+	}
+	return out, nil
+}
+
+func reverse(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case nil:
+		return []interface{}{}, nil
+	case string:
+		runes := []rune(v)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes), nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[len(v)-1-i] = item
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("cannot reverse %s", typeName(v))
+}
+
+// uniqueBy returns the items with distinct keys, sorted by key
+func uniqueBy(items, keys []interface{}) []interface{} {
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return compare(keys[order[i]], keys[order[j]]) < 0 })
+	out := []interface{}{}
+	for i, o := range order {
+		if i == 0 || compare(keys[o], keys[order[i-1]]) != 0 {
+			out = append(out, items[o])
+		}
+	}
+	return out
+}
+
+// extreme returns the item with the smallest key (sign -1) or largest (1)
+func extreme(items, keys []interface{}, sign int) interface{} {
+	if len(items) == 0 {
+		return nil
+	}
+	best := 0
+	for i := 1; i < len(items); i++ {
+		if c := compare(keys[i], keys[best]); c*sign > 0 || c == 0 && sign > 0 {
+			best = i
+		}
+	}
+	return items[best]
+}
+
+// flatten flattens nested arrays to depth, or fully when depth is negative
+func flatten(items []interface{}, depth int) []interface{} {
+	out := []interface{}{}
+	for _, item := range items {
+		if nested, ok := item.([]interface{}); ok && depth != 0 {
+			out = append(out, flatten(nested, depth-1)...)
+		} else {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// contains reports whether b is inside a: substrings, array items and
+// object fields, recursively
+func contains(a, b interface{}) bool {
+	switch a := a.(type) {
+	case string:
+		return strings.Contains(a, b.(string))
+	case []interface{}:
+		for _, want := range b.([]interface{}) {
+			found := false
+			for _, item := range a {
+				if typeName(item) == typeName(want) && contains(item, want) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		for key, want := range b.(map[string]interface{}) {
+			item, ok := a[key]
+			if !ok || typeName(item) != typeName(want) || !contains(item, want) {
+				return false
+			}
+		}
+		return true
+	}
+	return compare(a, b) == 0
+}
+
+func firstPresent(object map[string]interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		if v, ok := object[key]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// toText returns a string as it is and any other value as JSON
+func toText(v interface{}) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+func formatScalar(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// compilePattern compiles a regular expression with jq's flags: i for case
+// insensitive, x for extended, s for single line and g, which is implied
+func compilePattern(pattern, flags string) (*regexp.Regexp, error) {
+	var prefix string
+	for _, flag := range flags {
+		switch flag {
+		case 'i', 's':
+			prefix += string(flag)
+		case 'x':
+			pattern = regexp.MustCompile(`\s+|#.*`).ReplaceAllString(pattern, "")
+		case 'g':
+		default:
+			return nil, fmt.Errorf("%q is not a valid regular expression flag", flag)
+		}
+	}
+	if prefix != "" {
+		pattern = "(?" + prefix + ")" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+	}
+	return re, nil
+}
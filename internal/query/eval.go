@@ -0,0 +1,741 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// env holds the variables bound with "as"
+type env struct {
+	name   string
+	value  interface{}
+	parent *env
+}
+
+func (e *env) lookup(name string) (interface{}, bool) {
+	for ; e != nil; e = e.parent {
+		if e.name == name {
+			return e.value, true
+		}
+	}
+	return nil, false
+}
+
+// eval returns the outputs of n for one input
+func eval(n node, input interface{}, vars *env) ([]interface{}, error) {
+	switch n := n.(type) {
+	case nil, identityNode:
+		return []interface{}{input}, nil
+
+	case literalNode:
+		return []interface{}{n.value}, nil
+
+	case formatNode:
+		return evalFormat(n, input, vars)
+
+	case varNode:
+		value, ok := vars.lookup(n.name)
+		if !ok {
+			return nil, fmt.Errorf("$%s is not defined", n.name)
+		}
+		return []interface{}{value}, nil
+
+	case recurseNode:
+		return recurse(input), nil
+
+	case fieldNode:
+		targets, err := eval(n.target, input, vars)
+		if err != nil {
+			return nil, err
+		}
+		keys, err := eval(n.name, input, vars)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, target := range targets {
+			for _, key := range keys {
+				value, err := index(target, key)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, value)
+			}
+		}
+		return out, nil
+
+	case sliceNode:
+		return evalSlice(n, input, vars)
+
+	case iterateNode:
+		targets, err := eval(n.target, input, vars)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, target := range targets {
+			values, err := iterate(target)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, values...)
+		}
+		return out, nil
+
+	case optionalNode:
+		out, err := eval(n.body, input, vars)
+		if err != nil {
+			return nil, nil // ? turns errors into no output
+		}
+		return out, nil
+
+	case pipeNode:
+		lefts, err := eval(n.left, input, vars)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, left := range lefts {
+			rights, err := eval(n.right, left, vars)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, rights...)
+		}
+		return out, nil
+
+	case commaNode:
+		lefts, err := eval(n.left, input, vars)
+		if err != nil {
+			return nil, err
+		}
+		rights, err := eval(n.right, input, vars)
+		if err != nil {
+			return nil, err
+		}
+		return append(lefts, rights...), nil
+
+	case asNode:
+		sources, err := eval(n.source, input, vars)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, source := range sources {
+			values, err := eval(n.body, input, &env{name: n.name, value: source, parent: vars})
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, values...)
+		}
+		return out, nil
+
+	case binaryNode:
+		return evalBinary(n, input, vars)
+
+	case negateNode:
+		values, err := eval(n.body, input, vars)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]interface{}, len(values))
+		for i, value := range values {
+			f, ok := value.(float64)
+			if !ok {
+				return nil, fmt.Errorf("%s cannot be negated", typeName(value))
+			}
+			out[i] = -f
+		}
+		return out, nil
+
+	case arrayNode:
+		items := []interface{}{}
+		if n.body != nil {
+			values, err := eval(n.body, input, vars)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, values...)
+		}
+		return []interface{}{items}, nil
+
+	case objectNode:
+		return evalObject(n, input, vars)
+
+	case ifNode:
+		conds, err := eval(n.cond, input, vars)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, cond := range conds {
+			branch := n.otherwise
+			if truthy(cond) {
+				branch = n.then
+			} else if branch == nil {
+				out = append(out, input)
+				continue
+			}
+			values, err := eval(branch, input, vars)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, values...)
+		}
+		return out, nil
+
+	case callNode:
+		return callBuiltin(n, input, vars)
+	}
+	return nil, fmt.Errorf("cannot evaluate %T", n)
+}
+
+// decodeText returns the JSON object or array a string holds. Tool results
+// carry their data as text content, so strings holding JSON are indexed and
+// iterated as the values they hold.
+func decodeText(value interface{}) (interface{}, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	trimmed := strings.TrimSpace(s)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return nil, false
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(trimmed), &decoded); err != nil {
+		return nil, false
+	}
+	return decoded, true
+}
+
+// index returns value[key] for an object key or an array position
+func index(value, key interface{}) (interface{}, error) {
+	if decoded, ok := decodeText(value); ok {
+		value = decoded
+	}
+	switch v := value.(type) {
+	case nil:
+		switch key.(type) {
+		case string, float64, nil:
+			return nil, nil
+		}
+	case map[string]interface{}:
+		if k, ok := key.(string); ok {
+			return v[k], nil
+		}
+	case []interface{}:
+		if k, ok := key.(float64); ok {
+			i := int(math.Floor(k))
+			if i < 0 {
+				i += len(v)
+			}
+			if i < 0 || i >= len(v) {
+				return nil, nil
+			}
+			return v[i], nil
+		}
+	}
+	if k, ok := key.(string); ok {
+		return nil, fmt.Errorf("cannot index %s with %q", typeName(value), k)
+	}
+	return nil, fmt.Errorf("cannot index %s with %s", typeName(value), typeName(key))
+}
+
+// iterate returns the values of an array, or of an object in key order
+func iterate(value interface{}) ([]interface{}, error) {
+	if decoded, ok := decodeText(value); ok {
+		value = decoded
+	}
+	switch v := value.(type) {
+	case []interface{}:
+		return v, nil
+	case map[string]interface{}:
+		keys := sortedKeys(v)
+		out := make([]interface{}, len(keys))
+		for i, key := range keys {
+			out[i] = v[key]
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("cannot iterate over %s", typeName(value))
+}
+
+// recurse returns a value followed by every value inside it
+func recurse(value interface{}) []interface{} {
+	out := []interface{}{value}
+	if children, err := iterate(value); err == nil {
+		for _, child := range children {
+			out = append(out, recurse(child)...)
+		}
+	}
+	return out
+}
+
+func evalSlice(n sliceNode, input interface{}, vars *env) ([]interface{}, error) {
+	targets, err := eval(n.target, input, vars)
+	if err != nil {
+		return nil, err
+	}
+	bound := func(b node) ([]interface{}, error) {
+		if b == nil {
+			return []interface{}{nil}, nil
+		}
+		return eval(b, input, vars)
+	}
+	froms, err := bound(n.from)
+	if err != nil {
+		return nil, err
+	}
+	tos, err := bound(n.to)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []interface{}
+	for _, target := range targets {
+		for _, from := range froms {
+			for _, to := range tos {
+				value, err := slice(target, from, to)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, value)
+			}
+		}
+	}
+	return out, nil
+}
+
+// slice returns value[from:to] of an array or string, with negative bounds
+// counting from the end
+func slice(value, from, to interface{}) (interface{}, error) {
+	if decoded, ok := decodeText(value); ok {
+		if _, isArray := decoded.([]interface{}); isArray {
+			value = decoded
+		}
+	}
+
+	var length int
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		length = len(v)
+	case string:
+		length = len([]rune(v))
+	default:
+		return nil, fmt.Errorf("cannot slice %s", typeName(value))
+	}
+
+	clamp := func(b interface{}, def int) (int, error) {
+		if b == nil {
+			return def, nil
+		}
+		f, ok := b.(float64)
+		if !ok {
+			return 0, fmt.Errorf("slice bounds must be numbers, not %s", typeName(b))
+		}
+		i := int(math.Floor(f))
+		if i < 0 {
+			i += length
+		}
+		return max(0, min(length, i)), nil
+	}
+	start, err := clamp(from, 0)
+	if err != nil {
+		return nil, err
+	}
+	end, err := clamp(to, length)
+	if err != nil {
+		return nil, err
+	}
+	end = max(start, end)
+
+	if s, ok := value.(string); ok {
+		return string([]rune(s)[start:end]), nil
+	}
+	return append([]interface{}{}, value.([]interface{})[start:end]...), nil
+}
+
+// evalFormat joins the parts of an interpolated string, making one string
+// for every combination of their outputs
+func evalFormat(n formatNode, input interface{}, vars *env) ([]interface{}, error) {
+	texts := []string{""}
+	for _, part := range n.parts {
+		values, err := eval(part, input, vars)
+		if err != nil {
+			return nil, err
+		}
+		// Like jq, the outputs of a later part vary slowest
+		var next []string
+		for _, value := range values {
+			s, err := toText(value)
+			if err != nil {
+				return nil, err
+			}
+			for _, prefix := range texts {
+				next = append(next, prefix+s)
+			}
+		}
+		texts = next
+	}
+
+	out := make([]interface{}, len(texts))
+	for i, text := range texts {
+		out[i] = text
+	}
+	return out, nil
+}
+
+func evalObject(n objectNode, input interface{}, vars *env) ([]interface{}, error) {
+	objects := []map[string]interface{}{{}}
+	for _, entry := range n.entries {
+		keys, err := eval(entry.key, input, vars)
+		if err != nil {
+			return nil, err
+		}
+		values, err := eval(entry.value, input, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		// Every combination of keys and values makes an object
+		var next []map[string]interface{}
+		for _, object := range objects {
+			for _, key := range keys {
+				k, ok := key.(string)
+				if !ok {
+					return nil, fmt.Errorf("object keys must be strings, not %s", typeName(key))
+				}
+				for _, value := range values {
+					combined := make(map[string]interface{}, len(object)+1)
+					for existing, v := range object {
+						combined[existing] = v
+					}
+					combined[k] = value
+					next = append(next, combined)
+				}
+			}
+		}
+		objects = next
+	}
+
+	out := make([]interface{}, len(objects))
+	for i, object := range objects {
+		out[i] = object
+	}
+	return out, nil
+}
+
+func evalBinary(n binaryNode, input interface{}, vars *env) ([]interface{}, error) {
+	switch n.op {
+	case "//":
+		// The left side's truthy outputs, or the right side's when there are none
+		lefts, _ := eval(n.left, input, vars)
+		var out []interface{}
+		for _, left := range lefts {
+			if truthy(left) {
+				out = append(out, left)
+			}
+		}
+		if len(out) > 0 {
+			return out, nil
+		}
+		return eval(n.right, input, vars)
+
+	case "and", "or":
+		lefts, err := eval(n.left, input, vars)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, left := range lefts {
+			if truthy(left) == (n.op == "or") {
+				out = append(out, n.op == "or")
+				continue
+			}
+			rights, err := eval(n.right, input, vars)
+			if err != nil {
+				return nil, err
+			}
+			for _, right := range rights {
+				out = append(out, truthy(right))
+			}
+		}
+		return out, nil
+	}
+
+	lefts, err := eval(n.left, input, vars)
+	if err != nil {
+		return nil, err
+	}
+	rights, err := eval(n.right, input, vars)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, right := range rights {
+		for _, left := range lefts {
+			value, err := binary(n.op, left, right)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, value)
+		}
+	}
+	return out, nil
+}
+
+// binary applies an arithmetic or comparison operator
+func binary(op string, left, right interface{}) (interface{}, error) {
+	switch op {
+	case "==":
+		return compare(left, right) == 0, nil
+	case "!=":
+		return compare(left, right) != 0, nil
+	case "<":
+		return compare(left, right) < 0, nil
+	case "<=":
+		return compare(left, right) <= 0, nil
+	case ">":
+		return compare(left, right) > 0, nil
+	case ">=":
+		return compare(left, right) >= 0, nil
+	}
+
+	l, lok := left.(float64)
+	r, rok := right.(float64)
+	if lok && rok {
+		switch op {
+		case "+":
+			return l + r, nil
+		case "-":
+			return l - r, nil
+		case "*":
+			return l * r, nil
+		case "/":
+			if r == 0 {
+				return nil, fmt.Errorf("%v and %v cannot be divided because the divisor is zero", l, r)
+			}
+			return l / r, nil
+		case "%":
+			if int(r) == 0 {
+				return nil, fmt.Errorf("%v and %v cannot be divided because the divisor is zero", l, r)
+			}
+			return float64(int(l) % int(r)), nil
+		}
+	}
+
+	switch op {
+	case "+":
+		if left == nil {
+			return right, nil
+		}
+		if right == nil {
+			return left, nil
+		}
+		switch l := left.(type) {
+		case string:
+			if r, ok := right.(string); ok {
+				return l + r, nil
+			}
+		case []interface{}:
+			if r, ok := right.([]interface{}); ok {
+				return append(append([]interface{}{}, l...), r...), nil
+			}
+		case map[string]interface{}:
+			if r, ok := right.(map[string]interface{}); ok {
+				merged := make(map[string]interface{}, len(l)+len(r))
+				for k, v := range l {
+					merged[k] = v
+				}
+				for k, v := range r {
+					merged[k] = v
+				}
+				return merged, nil
+			}
+		}
+	case "-":
+		if l, ok := left.([]interface{}); ok {
+			if r, ok := right.([]interface{}); ok {
+				out := []interface{}{}
+				for _, item := range l {
+					if !containsValue(r, item) {
+						out = append(out, item)
+					}
+				}
+				return out, nil
+			}
+		}
+	case "*":
+		if l, ok := left.(map[string]interface{}); ok {
+			if r, ok := right.(map[string]interface{}); ok {
+				return deepMerge(l, r), nil
+			}
+		}
+	case "/":
+		if l, ok := left.(string); ok {
+			if r, ok := right.(string); ok {
+				return splitString(l, r), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s (%s) and %s (%s) cannot be combined with %s",
+		typeName(left), brief(left), typeName(right), brief(right), op)
+}
+
+func deepMerge(l, r map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(l)+len(r))
+	for k, v := range l {
+		merged[k] = v
+	}
+	for k, v := range r {
+		lv, lok := merged[k].(map[string]interface{})
+		rv, rok := v.(map[string]interface{})
+		if lok && rok {
+			merged[k] = deepMerge(lv, rv)
+		} else {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+func splitString(s, sep string) []interface{} {
+	out := []interface{}{}
+	if s == "" {
+		return out
+	}
+	for _, part := range strings.Split(s, sep) {
+		out = append(out, part)
+	}
+	return out
+}
+
+// truthy reports whether a value counts as true: anything but false and null
+func truthy(value interface{}) bool {
+	if b, ok := value.(bool); ok {
+		return b
+	}
+	return value != nil
+}
+
+// typeOrder ranks the JSON types in jq's sort order
+func typeOrder(value interface{}) int {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case bool:
+		if v {
+			return 2
+		}
+		return 1
+	case float64:
+		return 3
+	case string:
+		return 4
+	case []interface{}:
+		return 5
+	}
+	return 6
+}
+
+// compare orders two values the way jq sorts them
+func compare(a, b interface{}) int {
+	if ta, tb := typeOrder(a), typeOrder(b); ta != tb {
+		return ta - tb
+	}
+	switch a := a.(type) {
+	case float64:
+		b := b.(float64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(a, b.(string))
+	case []interface{}:
+		b := b.([]interface{})
+		for i := 0; i < len(a) && i < len(b); i++ {
+			if c := compare(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+		return len(a) - len(b)
+	case map[string]interface{}:
+		b := b.(map[string]interface{})
+		ak, bk := sortedKeys(a), sortedKeys(b)
+		keysA, keysB := make([]interface{}, len(ak)), make([]interface{}, len(bk))
+		for i, k := range ak {
+			keysA[i] = k
+		}
+		for i, k := range bk {
+			keysB[i] = k
+		}
+		if c := compare(keysA, keysB); c != 0 {
+			return c
+		}
+		for _, k := range ak {
+			if c := compare(a[k], b[k]); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+func containsValue(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		if compare(item, value) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// typeName names the JSON type of a value
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// brief shows a value in an error message, shortened
+func brief(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	if s := string(data); len(s) > 30 {
+		return s[:27] + "..."
+	}
+	return string(data)
+}
@@ -0,0 +1,226 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF     tokenKind = iota
+	tokenDot               // .
+	tokenRecurse           // ..
+	tokenField             // .name
+	tokenIdent             // name, keyword or function
+	tokenNumber            // 1, 2.5
+	tokenString            // "text"
+	tokenOp                // | , // == != < <= > >= + - * / % ( ) [ ] { } : ; ?
+)
+
+type token struct {
+	kind  tokenKind
+	text  string       // Identifier, field name, operator or decoded string
+	num   float64      // Value of a number
+	pos   int          // Offset in the expression, for errors
+	parts []stringPart // Text and expressions of a string with \(...), else nil
+}
+
+// stringPart is a piece of an interpolated string: decoded text, or the
+// source of an expression whose outputs are inserted
+type stringPart struct {
+	text string
+	expr bool
+	pos  int
+}
+
+// twoCharOps are the operators spelled with two characters
+var twoCharOps = []string{"//", "==", "!=", "<=", ">="}
+
+// lex splits an expression into tokens
+func lex(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		case c == '#':
+			// A comment runs to the end of the line
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+
+		case c == '.':
+			switch {
+			case i+1 < len(src) && src[i+1] == '.':
+				tokens = append(tokens, token{kind: tokenRecurse, text: "..", pos: i})
+				i += 2
+			case i+1 < len(src) && isIdentStart(rune(src[i+1])):
+				end := identEnd(src, i+1)
+				tokens = append(tokens, token{kind: tokenField, text: src[i+1 : end], pos: i})
+				i = end
+			case i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+				end, num, err := lexNumber(src, i)
+				if err != nil {
+					return nil, err
+				}
+				tokens = append(tokens, token{kind: tokenNumber, num: num, text: src[i:end], pos: i})
+				i = end
+			default:
+				tokens = append(tokens, token{kind: tokenDot, text: ".", pos: i})
+				i++
+			}
+
+		case c >= '0' && c <= '9':
+			end, num, err := lexNumber(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenNumber, num: num, text: src[i:end], pos: i})
+			i = end
+
+		case c == '"':
+			end, text, parts, err := lexString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, pos: i, parts: parts})
+			i = end
+
+		case isIdentStart(rune(c)) || c == '$':
+			end := identEnd(src, i+1)
+			tokens = append(tokens, token{kind: tokenIdent, text: src[i:end], pos: i})
+			i = end
+
+		default:
+			op := string(c)
+			for _, two := range twoCharOps {
+				if strings.HasPrefix(src[i:], two) {
+					op = two
+				}
+			}
+			if !strings.Contains("|,=!<>+-*/%()[]{}:;?", op[:1]) || op == "=" || op == "!" {
+				return nil, fmt.Errorf("unexpected %q at offset %d", op, i)
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(src)}), nil
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// identEnd returns the end of the identifier starting before from
+func identEnd(src string, from int) int {
+	for from < len(src) && (src[from] == '_' || src[from] < 0x80 && (unicode.IsLetter(rune(src[from])) || unicode.IsDigit(rune(src[from])))) {
+		from++
+	}
+	return from
+}
+
+func lexNumber(src string, start int) (int, float64, error) {
+	end := start
+	for end < len(src) && (src[end] >= '0' && src[end] <= '9' || src[end] == '.' ||
+		src[end] == 'e' || src[end] == 'E' ||
+		(src[end] == '-' || src[end] == '+') && end > start && (src[end-1] == 'e' || src[end-1] == 'E')) {
+		end++
+	}
+	num, err := strconv.ParseFloat(src[start:end], 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid number %q at offset %d", src[start:end], start)
+	}
+	return end, num, nil
+}
+
+// lexString decodes the string literal starting at start, using JSON escapes.
+// A string with \(...) in it is also returned as parts, alternating text and
+// the source of each expression.
+func lexString(src string, start int) (int, string, []stringPart, error) {
+	var b strings.Builder
+	var parts []stringPart
+	for i := start + 1; i < len(src); i++ {
+		switch c := src[i]; c {
+		case '"':
+			if parts != nil && b.Len() > 0 {
+				parts = append(parts, stringPart{text: b.String()})
+			}
+			return i + 1, b.String(), parts, nil
+		case '\\':
+			i++
+			if i >= len(src) {
+				break
+			}
+			switch src[i] {
+			case '"', '\\', '/':
+				b.WriteByte(src[i])
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'u':
+				if i+4 >= len(src) {
+					return 0, "", nil, fmt.Errorf("invalid escape in string at offset %d", i-1)
+				}
+				r, err := strconv.ParseUint(src[i+1:i+5], 16, 32)
+				if err != nil {
+					return 0, "", nil, fmt.Errorf("invalid escape in string at offset %d", i-1)
+				}
+				b.WriteRune(rune(r))
+				i += 4
+			case '(':
+				end, err := interpolationEnd(src, i)
+				if err != nil {
+					return 0, "", nil, err
+				}
+				if b.Len() > 0 {
+					parts = append(parts, stringPart{text: b.String()})
+					b.Reset()
+				}
+				parts = append(parts, stringPart{text: src[i+1 : end], expr: true, pos: i + 1})
+				i = end
+			default:
+				return 0, "", nil, fmt.Errorf("invalid escape \\%c in string at offset %d", src[i], i-1)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return 0, "", nil, fmt.Errorf("unterminated string at offset %d", start)
+}
+
+// interpolationEnd returns the offset of the parenthesis that closes the one
+// at open, skipping the strings inside
+func interpolationEnd(src string, open int) (int, error) {
+	depth := 0
+	for i := open; i < len(src); i++ {
+		switch src[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		case '"':
+			end, _, _, err := lexString(src, i)
+			if err != nil {
+				return 0, err
+			}
+			i = end - 1
+		}
+	}
+	return 0, fmt.Errorf("unterminated \\( in string at offset %d", open-1)
+}
@@ -0,0 +1,510 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The nodes of a parsed expression. A nil target stands for the input.
+type (
+	node interface{}
+
+	identityNode struct{}
+	recurseNode  struct{}
+	literalNode  struct{ value interface{} }
+	formatNode   struct{ parts []node } // "text \(expr)", joined as strings
+	varNode      struct{ name string }
+
+	fieldNode    struct{ target, name node } // name evaluates to a key
+	sliceNode    struct{ target, from, to node }
+	iterateNode  struct{ target node }
+	optionalNode struct{ body node }
+
+	pipeNode   struct{ left, right node }
+	commaNode  struct{ left, right node }
+	binaryNode struct {
+		op          string
+		left, right node
+	}
+	negateNode struct{ body node }
+
+	arrayNode  struct{ body node } // nil body for []
+	objectNode struct{ entries []objectEntry }
+	callNode   struct {
+		name string
+		args []node
+	}
+	ifNode struct{ cond, then, otherwise node } // nil otherwise passes the input through
+	asNode struct {
+		source node
+		name   string
+		body   node
+	}
+)
+
+type objectEntry struct {
+	key, value node
+}
+
+// parser is a recursive descent parser over the tokens of an expression
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func parse(src string) (node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return identityNode{}, nil
+	}
+	n, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.unexpected(t)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it is the operator or keyword op
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); (t.kind == tokenOp || t.kind == tokenIdent) && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		return fmt.Errorf("expected %q, %w", op, p.unexpected(p.peek()))
+	}
+	return nil
+}
+
+func (p *parser) unexpected(t token) error {
+	if t.kind == tokenEOF {
+		return fmt.Errorf("unexpected end of expression")
+	}
+	text := t.text
+	if t.kind == tokenField {
+		text = "." + text
+	}
+	return fmt.Errorf("unexpected %q at offset %d", text, t.pos)
+}
+
+// parsePipe parses a | b, and a as $name | b
+func (p *parser) parsePipe() (node, error) {
+	left, err := p.parseComma()
+	if err != nil {
+		return nil, err
+	}
+	if p.accept("as") {
+		t := p.next()
+		if t.kind != tokenIdent || !strings.HasPrefix(t.text, "$") || len(t.text) < 2 {
+			return nil, fmt.Errorf("expected a $variable after \"as\", %w", p.unexpected(t))
+		}
+		if err := p.expect("|"); err != nil {
+			return nil, err
+		}
+		body, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return asNode{source: left, name: t.text[1:], body: body}, nil
+	}
+	if p.accept("|") {
+		right, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		return pipeNode{left, right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseComma() (node, error) {
+	left, err := p.parseAlternative()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(",") {
+		right, err := p.parseAlternative()
+		if err != nil {
+			return nil, err
+		}
+		left = commaNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAlternative() (node, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.accept("//") {
+		right, err := p.parseAlternative()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{"//", left, right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	return p.parseLeftAssoc([]string{"or"}, p.parseAnd)
+}
+
+func (p *parser) parseAnd() (node, error) {
+	return p.parseLeftAssoc([]string{"and"}, p.parseComparison)
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.accept(op) {
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			return binaryNode{op, left, right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	return p.parseLeftAssoc([]string{"+", "-"}, p.parseMultiplicative)
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	return p.parseLeftAssoc([]string{"*", "/", "%"}, p.parseUnary)
+}
+
+// parseLeftAssoc parses operands joined by any of ops, left to right
+func (p *parser) parseLeftAssoc(ops []string, operand func() (node, error)) (node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		matched := ""
+		for _, op := range ops {
+			if p.accept(op) {
+				matched = op
+				break
+			}
+		}
+		if matched == "" {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{matched, left, right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept("-") {
+		body, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negateNode{body}, nil
+	}
+	return p.parsePostfix()
+}
+
+// parsePostfix parses a term followed by field accesses, indexes, slices,
+// iteration and ?
+func (p *parser) parsePostfix() (node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		switch {
+		case t.kind == tokenField:
+			p.next()
+			n = fieldNode{n, literalNode{t.text}}
+		case t.kind == tokenDot && p.tokens[p.pos+1].kind == tokenString:
+			p.next()
+			name, err := stringNode(p.next())
+			if err != nil {
+				return nil, err
+			}
+			n = fieldNode{n, name}
+		case t.kind == tokenDot && p.tokens[p.pos+1].kind == tokenOp && p.tokens[p.pos+1].text == "[":
+			p.next() // .a.[0] means .a[0]
+		case t.kind == tokenOp && t.text == "[":
+			if n, err = p.parseBracket(n); err != nil {
+				return nil, err
+			}
+		case t.kind == tokenOp && t.text == "?":
+			p.next()
+			n = optionalNode{n}
+		default:
+			return n, nil
+		}
+	}
+}
+
+// parseBracket parses [], [index] and [from:to] after target
+func (p *parser) parseBracket(target node) (node, error) {
+	p.next()
+	if p.accept("]") {
+		return iterateNode{target}, nil
+	}
+
+	var from, to node
+	var err error
+	if !p.accept(":") {
+		if from, err = p.parsePipe(); err != nil {
+			return nil, err
+		}
+		if p.accept("]") {
+			return fieldNode{target, from}, nil
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+	}
+	if !p.accept("]") {
+		if to, err = p.parsePipe(); err != nil {
+			return nil, err
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	}
+	return sliceNode{target, from, to}, nil
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenDot:
+		if next := p.peek(); next.kind == tokenString {
+			name, err := stringNode(p.next())
+			if err != nil {
+				return nil, err
+			}
+			return fieldNode{nil, name}, nil
+		}
+		return identityNode{}, nil
+	case tokenField:
+		return fieldNode{nil, literalNode{t.text}}, nil
+	case tokenRecurse:
+		// ..name is short for .. | .name?, the value of a key at any depth
+		if next := p.peek(); next.pos == t.pos+2 &&
+			(next.kind == tokenString || next.kind == tokenIdent && !strings.HasPrefix(next.text, "$")) {
+			name, err := stringNode(p.next())
+			if err != nil {
+				return nil, err
+			}
+			return pipeNode{recurseNode{}, optionalNode{fieldNode{nil, name}}}, nil
+		}
+		return recurseNode{}, nil
+	case tokenNumber:
+		return literalNode{t.num}, nil
+	case tokenString:
+		return stringNode(t)
+	case tokenIdent:
+		return p.parseIdent(t)
+	case tokenOp:
+		switch t.text {
+		case "(":
+			n, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			return n, p.expect(")")
+		case "[":
+			if p.accept("]") {
+				return arrayNode{}, nil
+			}
+			body, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			return arrayNode{body}, p.expect("]")
+		case "{":
+			return p.parseObject()
+		}
+	}
+	return nil, p.unexpected(t)
+}
+
+// stringNode returns the node for a string token: a literal, or for a string
+// with \(...) in it the text and the parsed expressions to join
+func stringNode(t token) (node, error) {
+	if t.parts == nil {
+		return literalNode{t.text}, nil
+	}
+	var n formatNode
+	for _, part := range t.parts {
+		if !part.expr {
+			n.parts = append(n.parts, literalNode{part.text})
+			continue
+		}
+		if strings.TrimSpace(part.text) == "" {
+			return nil, fmt.Errorf("empty \\(...) in string at offset %d", part.pos-2)
+		}
+		body, err := parse(part.text)
+		if err != nil {
+			return nil, fmt.Errorf("in \\(...) at offset %d: %w", part.pos-2, err)
+		}
+		n.parts = append(n.parts, body)
+	}
+	return n, nil
+}
+
+func (p *parser) parseIdent(t token) (node, error) {
+	switch t.text {
+	case "true":
+		return literalNode{true}, nil
+	case "false":
+		return literalNode{false}, nil
+	case "null":
+		return literalNode{nil}, nil
+	case "if":
+		return p.parseIf()
+	case "and", "or", "as", "then", "elif", "else", "end":
+		return nil, p.unexpected(t)
+	}
+	if strings.HasPrefix(t.text, "$") {
+		if len(t.text) < 2 {
+			return nil, p.unexpected(t)
+		}
+		return varNode{t.text[1:]}, nil
+	}
+
+	call := callNode{name: t.text}
+	if p.accept("(") {
+		for {
+			arg, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if p.accept(")") {
+				break
+			}
+			if err := p.expect(";"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !hasBuiltin(call.name, len(call.args)) {
+		return nil, fmt.Errorf("unknown function %s/%d at offset %d", call.name, len(call.args), t.pos)
+	}
+	return call, nil
+}
+
+// parseIf parses the rest of if cond then a elif cond then b else c end
+func (p *parser) parseIf() (node, error) {
+	cond, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("then"); err != nil {
+		return nil, err
+	}
+	then, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+
+	n := ifNode{cond: cond, then: then}
+	switch {
+	case p.accept("elif"):
+		if n.otherwise, err = p.parseIf(); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case p.accept("else"):
+		if n.otherwise, err = p.parsePipe(); err != nil {
+			return nil, err
+		}
+	}
+	return n, p.expect("end")
+}
+
+// parseObject parses the rest of {key: value, ...}, where {name} is short
+// for {name: .name} and {$v} for {v: $v}
+func (p *parser) parseObject() (node, error) {
+	var n objectNode
+	if p.accept("}") {
+		return n, nil
+	}
+	for {
+		var entry objectEntry
+		t := p.next()
+		switch {
+		case t.kind == tokenIdent && strings.HasPrefix(t.text, "$"):
+			entry = objectEntry{literalNode{t.text[1:]}, varNode{t.text[1:]}}
+		case t.kind == tokenString && t.parts != nil:
+			key, err := stringNode(t)
+			if err != nil {
+				return nil, err
+			}
+			entry.key = key
+		case t.kind == tokenIdent || t.kind == tokenString:
+			entry = objectEntry{literalNode{t.text}, fieldNode{nil, literalNode{t.text}}}
+		case t.kind == tokenOp && t.text == "(":
+			key, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			entry.key = key
+		default:
+			return nil, p.unexpected(t)
+		}
+
+		if p.accept(":") {
+			value, err := p.parseAlternative()
+			if err != nil {
+				return nil, err
+			}
+			entry.value = value
+		} else if entry.value == nil {
+			return nil, fmt.Errorf("expected \":\" after a computed key, %w", p.unexpected(p.peek()))
+		}
+		n.entries = append(n.entries, entry)
+
+		if p.accept("}") {
+			return n, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package query
+
+import (
+	"fmt"
+	"math"
+)
+
+// PathKind is the kind of a step of a path
+type PathKind int
+
+const (
+	PathKey     PathKind = iota // .name: the value of a key
+	PathIndex                   // [N]: the element at an index, counted from the end if negative
+	PathEach                    // []: every element or value
+	PathRecurse                 // ..: the value and every value below it
+)
+
+// PathStep is one step of a path
+type PathStep struct {
+	Kind  PathKind
+	Key   string
+	Index int
+}
+
+// ParsePath parses an expression that only selects values, such as
+// .tools[].description or ..timestamp, into the steps of its path. Steps may
+// be joined with |, and ? is ignored, since a path need not match.
+func ParsePath(src string) ([]PathStep, error) {
+	root, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	var steps []PathStep
+	if err := pathSteps(root, &steps); err != nil {
+		return nil, err
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+	return steps, nil
+}
+
+// pathSteps appends the steps of the path n to steps
+func pathSteps(n node, steps *[]PathStep) error {
+	switch n := n.(type) {
+	case nil, identityNode:
+	case recurseNode:
+		*steps = append(*steps, PathStep{Kind: PathRecurse})
+	case fieldNode:
+		if err := pathSteps(n.target, steps); err != nil {
+			return err
+		}
+		name := n.name
+		negative := false
+		if negate, ok := name.(negateNode); ok {
+			name, negative = negate.body, true // [-1]
+		}
+		literal, ok := name.(literalNode)
+		if !ok {
+			return fmt.Errorf("keys and indexes in a path must be literals")
+		}
+		if negative {
+			number, ok := literal.value.(float64)
+			if !ok {
+				return fmt.Errorf("cannot negate %s", typeName(literal.value))
+			}
+			literal.value = -number
+		}
+		switch key := literal.value.(type) {
+		case string:
+			*steps = append(*steps, PathStep{Kind: PathKey, Key: key})
+		case float64:
+			if key != math.Trunc(key) {
+				return fmt.Errorf("index %v is not a whole number", key)
+			}
+			*steps = append(*steps, PathStep{Kind: PathIndex, Index: int(key)})
+		default:
+			return fmt.Errorf("cannot index with %s", typeName(key))
+		}
+	case iterateNode:
+		if err := pathSteps(n.target, steps); err != nil {
+			return err
+		}
+		*steps = append(*steps, PathStep{Kind: PathEach})
+	case optionalNode:
+		return pathSteps(n.body, steps)
+	case pipeNode:
+		if err := pathSteps(n.left, steps); err != nil {
+			return err
+		}
+		return pathSteps(n.right, steps)
+	default:
+		return fmt.Errorf("not a path: only .name, [N], [] and .. may be used")
+	}
+	return nil
+}
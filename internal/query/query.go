@@ -0,0 +1,72 @@
+// Package query evaluates jq-like expressions over JSON values, without an
+// external jq binary. MCP tools often return JSON as text content, so a
+// string holding a JSON object or array is indexed and iterated as the value
+// it holds: .content[0].text.items[] works as well as
+// .content[0].text | fromjson | .items[].
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Query is a parsed expression
+type Query struct {
+	src  string
+	root node
+}
+
+// Parse parses a jq-like expression. An empty expression returns its input.
+func Parse(src string) (*Query, error) {
+	root, err := parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query %q: %w", src, err)
+	}
+	return &Query{src: src, root: root}, nil
+}
+
+// Run evaluates the query against input, which is first converted to its
+// JSON form, and returns every output
+func (q *Query) Run(input interface{}) ([]interface{}, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query input: %w", err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to convert query input: %w", err)
+	}
+
+	results, err := eval(q.root, value, nil)
+	if err != nil {
+		return nil, fmt.Errorf("query %q failed: %w", q.src, err)
+	}
+	return results, nil
+}
+
+// String returns the expression the query was parsed from
+func (q *Query) String() string {
+	return q.src
+}
+
+// FormatText formats outputs the way jq -r prints them: strings as they are
+// and other values as indented JSON, one per line
+func FormatText(results []interface{}) (string, error) {
+	var b strings.Builder
+	for i, result := range results {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if text, ok := result.(string); ok {
+			b.WriteString(text)
+			continue
+		}
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal query result to JSON: %w", err)
+		}
+		b.Write(data)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,347 @@
+package query
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const toolsList = `{
+	"tools": [
+		{"name": "echo", "description": "Echo a message", "inputSchema": {"required": ["message"]}},
+		{"name": "add", "description": "Add numbers"}
+	]
+}`
+
+// callResult is a tool result whose text content is itself JSON
+const callResult = `{
+	"content": [{"type": "text", "text": "{\"items\": [{\"id\": 1, \"status\": \"open\"}, {\"id\": 2, \"status\": \"closed\"}, {\"id\": 3, \"status\": \"open\"}]}"}],
+	"isError": false
+}`
+
+// run evaluates expr against the JSON input and returns the outputs as
+// compact JSON, one per line
+func run(t *testing.T, expr, input string) string {
+	t.Helper()
+	q, err := Parse(expr)
+	require.NoError(t, err, expr)
+	var value interface{}
+	require.NoError(t, json.Unmarshal([]byte(input), &value))
+	results, err := q.Run(value)
+	require.NoError(t, err, expr)
+
+	var out string
+	for _, result := range results {
+		data, err := json.Marshal(result)
+		require.NoError(t, err)
+		out += string(data) + "\n"
+	}
+	return out
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		expr  string
+		input string
+		want  string
+	}{
+		{"", `{"a": 1}`, "{\"a\":1}\n"},
+		{".tools[].name", toolsList, "\"echo\"\n\"add\"\n"},
+		{".tools[0].inputSchema.required[0]", toolsList, "\"message\"\n"},
+		{".tools[-1].name", toolsList, "\"add\"\n"},
+		{`.tools[] | select(.name == "add") | .description`, toolsList, "\"Add numbers\"\n"},
+		{".tools | length", toolsList, "2\n"},
+		{"[.tools[].name] | join(\", \")", toolsList, "\"echo, add\"\n"},
+		{".tools | map(.name) | sort", toolsList, "[\"add\",\"echo\"]\n"},
+		{".tools[] | {name, required: .inputSchema.required}", toolsList,
+			"{\"name\":\"echo\",\"required\":[\"message\"]}\n{\"name\":\"add\",\"required\":null}\n"},
+		{".missing // \"none\"", `{}`, "\"none\"\n"},
+		{".a.b?", `{"a": 5}`, ""},
+		{"[.[] | . * 2]", `[1, 2, 3]`, "[2,4,6]\n"},
+		{".[1:]", `[1, 2, 3]`, "[2,3]\n"},
+		{`.[] as $x | $x + 1`, `[1, 2]`, "2\n3\n"},
+		{`if . > 1 then "big" elif . == 1 then "one" else "small" end`, `1`, "\"one\"\n"},
+		{"to_entries | map(.key)", `{"b": 1, "a": 2}`, "[\"a\",\"b\"]\n"},
+		{"group_by(.k) | map(length)", `[{"k": 1}, {"k": 2}, {"k": 1}]`, "[2,1]\n"},
+		{`.s | test("^HE"; "i")`, `{"s": "hello"}`, "true\n"},
+		{`.s | gsub("l"; "L")`, `{"s": "hello"}`, "\"heLLo\"\n"},
+		{"[limit(2; .[])]", `[1, 2, 3]`, "[1,2]\n"},
+		{"[..] | length", `{"a": [1]}`, "3\n"},
+		{"[..id]", `{"id": 1, "b": [{"id": 2}]}`, "[1,2]\n"},
+		{"tojson", `{"a": 1}`, "\"{\\\"a\\\":1}\"\n"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, run(t, tt.expr, tt.input), tt.expr)
+	}
+}
+
+func TestBuiltins(t *testing.T) {
+	tests := []struct {
+		expr  string
+		input string
+		want  string
+	}{
+		{"[.[] | empty]", `[1, 2]`, "[]\n"},
+		{"map(not)", `[true, false, null, 0]`, "[false,true,true,false]\n"},
+		{"map(type)", `[null, true, 1, "a", [], {}]`, `["null","boolean","number","string","array","object"]` + "\n"},
+		{"map(length)", `[null, "héllo", [1, 2], {"a": 1}, -3]`, "[0,5,2,1,3]\n"},
+		{"keys", `{"b": 1, "a": 2}`, `["a","b"]` + "\n"},
+		{"keys", `["x", "y"]`, "[0,1]\n"},
+		{`has("a"), has("z")`, `{"a": null}`, "true\nfalse\n"},
+		{"has(1), has(5)", `[0, 1]`, "true\nfalse\n"},
+		{`contains({a: [1]}), contains({b: "baz"})`, `{"a": [1, 2], "b": "foobar"}`, "true\nfalse\n"},
+		{`contains("oba")`, `"foobar"`, "true\n"},
+		{"map(select(. > 1))", `[1, 2, 3]`, "[2,3]\n"},
+		{"map(.a)", `[{"a": 1}, {"a": 2}]`, "[1,2]\n"},
+		{"map(.[])", `[[1, 2], [3]]`, "[1,2,3]\n"},
+		{"map_values(. * 10)", `{"a": 1, "b": 2}`, `{"a":10,"b":20}` + "\n"},
+		{"map_values(empty)", `[1, 2]`, "[]\n"},
+		{"with_entries({key: .value, value: .key})", `{"a": "b"}`, `{"b":"a"}` + "\n"},
+		{"to_entries", `{"a": 1}`, `[{"key":"a","value":1}]` + "\n"},
+		{"from_entries", `[{"name": "a", "v": 1}, {"k": 2, "value": true}]`, `{"2":true,"a":1}` + "\n"},
+		{"add", `[1, 2, 3]`, "6\n"},
+		{"add", `["a", "b"]`, "\"ab\"\n"},
+		{"add", `[]`, "null\n"},
+		{"first, last", `[1, 2, 3]`, "1\n3\n"},
+		{"reverse", `[1, 2, 3]`, "[3,2,1]\n"},
+		{"reverse", `"abc"`, "\"cba\"\n"},
+		{"sort", `[3, "a", null, true, 1]`, `[null,true,1,3,"a"]` + "\n"},
+		{"unique", `[2, 1, 2]`, "[1,2]\n"},
+		{"min, max", `[3, 1, 2]`, "1\n3\n"},
+		{"min", `[]`, "null\n"},
+		{"flatten", `[1, [2, [3]]]`, "[1,2,3]\n"},
+		{"flatten(1)", `[1, [2, [3]]]`, "[1,2,[3]]\n"},
+		{"sort_by(.n)", `[{"n": 2}, {"n": 1}, {"n": 2, "x": 1}]`, `[{"n":1},{"n":2},{"n":2,"x":1}]` + "\n"},
+		{".tools | sort_by(.name) | map(.name)", toolsList, `["add","echo"]` + "\n"},
+		{"group_by(.k) | map(map(.v))", `[{"k": 2, "v": 1}, {"k": 1, "v": 2}, {"k": 2, "v": 3}]`, "[[2],[1,3]]\n"},
+		{"unique_by(length)", `["a", "bb", "c"]`, `["a","bb"]` + "\n"},
+		{"min_by(.n), max_by(.n)", `[{"n": 2}, {"n": 1}, {"n": 3}]`, `{"n":1}` + "\n" + `{"n":3}` + "\n"},
+		{"any, all", `[true, false]`, "true\nfalse\n"},
+		{"any(. > 2), all(. > 0)", `[1, 2, 3]`, "true\ntrue\n"},
+		{"first(.[]), last(.[])", `[1, 2, 3]`, "1\n3\n"},
+		{"[limit(0; .[])]", `[1, 2]`, "[]\n"},
+		{"[range(3)]", `null`, "[0,1,2]\n"},
+		{"[range(1; 3)]", `null`, "[1,2]\n"},
+		{"[recurse] | length", `[[1]]`, "3\n"},
+		{"[recurse(.child | select(. != null))] | length", `{"child": {"child": null}}`, "2\n"},
+		{"[.[] | arrays]", `[[], {}, "a", 1, true, null]`, "[[]]\n"},
+		{"[.[] | objects]", `[[], {}, "a", 1, true, null]`, "[{}]\n"},
+		{"[.[] | strings]", `[[], {}, "a", 1, true, null]`, `["a"]` + "\n"},
+		{"[.[] | numbers]", `[[], {}, "a", 1, true, null]`, "[1]\n"},
+		{"[.[] | booleans]", `[[], {}, "a", 1, true, null]`, "[true]\n"},
+		{"[.[] | nulls]", `[[], {}, "a", 1, true, null]`, "[null]\n"},
+		{"[.[] | values]", `[1, null]`, "[1]\n"},
+		{"[.[] | scalars]", `[[], {}, "a", 1]`, `["a",1]` + "\n"},
+		{"fromjson", `"[1, {\"a\": 2}]"`, `[1,{"a":2}]` + "\n"},
+		{"tojson", `[1, "a"]`, `"[1,\"a\"]"` + "\n"},
+		{"map(tostring)", `["a", 1, null, {"b": true}]`, `["a","1","null","{\"b\":true}"]` + "\n"},
+		{"map(tonumber)", `[1, " 2.5 "]`, "[1,2.5]\n"},
+		{"map(floor), map(ceil), map(round)", `[1.5, -1.5]`, "[1,-2]\n[2,-1]\n[2,-2]\n"},
+		{"sqrt, abs", `16`, "4\n16\n"},
+		{"abs", `-2`, "2\n"},
+		{"ascii_downcase, ascii_upcase", `"MiXed"`, `"mixed"` + "\n" + `"MIXED"` + "\n"},
+		{"trim, ltrim, rtrim", `"  x  "`, `"x"` + "\n" + `"x  "` + "\n" + `"  x"` + "\n"},
+		{`startswith("ab"), endswith("ab")`, `"abc"`, "true\nfalse\n"},
+		{`ltrimstr("a"), rtrimstr("c"), ltrimstr("z")`, `"abc"`, `"bc"` + "\n" + `"ab"` + "\n" + `"abc"` + "\n"},
+		{`split(", ")`, `"a, b, c"`, `["a","b","c"]` + "\n"},
+		{`test("b+")`, `"abbc"`, "true\n"},
+		{`test("B"; "i"), test("B")`, `"abc"`, "true\nfalse\n"},
+		{`sub("b+"; "x")`, `"abbcb"`, `"axcb"` + "\n"},
+		{`gsub("b+"; "x")`, `"abbcb"`, `"axcx"` + "\n"},
+		{`join("-")`, `["a", 1, null, true]`, `"a-1--true"` + "\n"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, run(t, tt.expr, tt.input), tt.expr)
+	}
+}
+
+func TestOperators(t *testing.T) {
+	tests := []struct {
+		expr  string
+		input string
+		want  string
+	}{
+		{".a // .b", `{"a": false, "b": 2}`, "2\n"},
+		{".a // .b", `{"a": 0, "b": 2}`, "0\n"},
+		{".[] // 9", `[null, false]`, "9\n"},
+		{"(.[] | select(. > 1)) // 0", `[1, 2, 3]`, "2\n3\n"},
+		{".a // .b // \"c\"", `{}`, "\"c\"\n"},
+		{".[] | select(.ok) | .id", `[{"id": 1, "ok": true}, {"id": 2, "ok": null}]`, "1\n"},
+		{"select(.[] > 1)", `[1, 2, 3]`, "[1,2,3]\n[1,2,3]\n"},
+		{"map(select(.tags | contains([\"a\"])) | .id)", `[{"id": 1, "tags": ["a"]}, {"id": 2, "tags": []}]`, "[1]\n"},
+		{"map(.a, .b)", `[{"a": 1, "b": 2}]`, "[1,2]\n"},
+		{"map(.a) | add", `[{"a": 1}, {"a": 2}]`, "3\n"},
+		{"sort_by(.a, .b) | map(.id)", `[{"id": 1, "a": 2}, {"id": 2, "a": 1}]`, "[2,1]\n"},
+		{"sort_by(-.n) | map(.n)", `[{"n": 1}, {"n": 3}, {"n": 2}]`, "[3,2,1]\n"},
+		{"1 + 2 * 3 - 4 / 2 % 3", `null`, "5\n"},
+		{`{a: 1} + {b: 2}, {a: {b: 1}} * {a: {c: 2}}`, `null`, `{"a":1,"b":2}` + "\n" + `{"a":{"b":1,"c":2}}` + "\n"},
+		{"[1, 2, 2] - [2]", `null`, "[1]\n"},
+		{`"a,b" / ","`, `null`, `["a","b"]` + "\n"},
+		{".a and .b, .a or .b", `{"a": true, "b": false}`, "false\ntrue\n"},
+		{".[2:4], .[:-1], .[-2:]", `"abcde"`, `"cd"` + "\n" + `"abcd"` + "\n" + `"de"` + "\n"},
+		{`.["a b"], ."a b"`, `{"a b": 1}`, "1\n1\n"},
+		{`.a."b"`, `{"a": {"b": 1}}`, "1\n"},
+		{`{(.k): .v, "x": 1}`, `{"k": "key", "v": 2}`, `{"key":2,"x":1}` + "\n"},
+		{"[.[]?]", `1`, "[]\n"},
+		{"-.a", `{"a": 1}`, "-1\n"},
+		{"# comment\n.a # trailing\n", `{"a": 1}`, "1\n"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, run(t, tt.expr, tt.input), tt.expr)
+	}
+}
+
+func TestInterpolation(t *testing.T) {
+	tests := []struct {
+		expr  string
+		input string
+		want  string
+	}{
+		{`"\(.id)"`, `{"id": 7}`, `"7"` + "\n"},
+		{`"id \(.id): \(.name)!"`, `{"id": 1, "name": "echo"}`, `"id 1: echo!"` + "\n"},
+		{`"\(.)"`, `{"a": [1, null]}`, `"{\"a\":[1,null]}"` + "\n"},
+		{`"\(.a) \(.b)"`, `{"a": null, "b": true}`, `"null true"` + "\n"},
+		{`"\(1, 2)-\(3, 4)"`, `null`, `"1-3"` + "\n" + `"2-3"` + "\n" + `"1-4"` + "\n" + `"2-4"` + "\n"},
+		{`"x\(empty)"`, `null`, ""},
+		{`"\("in\("ner")")"`, `null`, `"inner"` + "\n"},
+		{`"\(.a | ("(" + . + ")"))"`, `{"a": "b"}`, `"(b)"` + "\n"},
+		{`"tab\t\(.a)\n"`, `{"a": 1}`, `"tab\t1\n"` + "\n"},
+		{`.tools[] | "\(.name): \(.description)"`, toolsList, `"echo: Echo a message"` + "\n" + `"add: Add numbers"` + "\n"},
+		{`{"\(.k)": .v}`, `{"k": "a", "v": 1}`, `{"a":1}` + "\n"},
+		{`."\(.k)"`, `{"k": "a", "a": 2}`, "2\n"},
+		{`.x."\(.k)"`, `{"k": "a", "x": {"a": 3}}`, "3\n"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, run(t, tt.expr, tt.input), tt.expr)
+	}
+}
+
+func TestRunTextContent(t *testing.T) {
+	// The example from the documentation, with an explicit fromjson
+	assert.Equal(t, "{\"id\":1,\"status\":\"open\"}\n{\"id\":3,\"status\":\"open\"}\n",
+		run(t, `.content[0].text | fromjson | .items[] | select(.status=="open")`, callResult))
+
+	// Text holding JSON is indexed as the value it holds
+	assert.Equal(t, "1\n3\n",
+		run(t, `.content[0].text.items[] | select(.status=="open") | .id`, callResult))
+
+	// fromjson decodes text content, and a second time for doubly encoded text
+	assert.Equal(t, "[1,2,3]\n",
+		run(t, `.content[0].text | fromjson | .items | map(.id)`, callResult))
+	assert.Equal(t, "2\n",
+		run(t, `.text | fromjson | fromjson | .a`, `{"text": "\"{\\\"a\\\": 2}\""}`))
+	assert.Equal(t, "\"open\"\n",
+		run(t, `.content[0].text | fromjson | .items | first | .status`, callResult))
+
+	// Plain text stays text
+	assert.Equal(t, "\"hello\"\n", run(t, ".text", `{"text": "hello"}`))
+	assert.Equal(t, "5\n", run(t, ".text | length", `{"text": "hello"}`))
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		".a |",
+		"nosuchfunction",
+		"select(.a; .b; .c)",
+		".[",
+		`"unterminated`,
+		"if . then 1",
+		"{(.a)}",
+		"1 == 2 == 3",
+		")",
+		".a]",
+		"[1, 2",
+		"{a: 1",
+		"{a 1}",
+		"{1: 2}",
+		".a as x | x",
+		"if . then 1 else 2",
+		"elif",
+		"map()",
+		"map(.a",
+		"limit(1)",
+		".a ..",
+		"$",
+		"1 +",
+		"= 1",
+		"!",
+		"@base64",
+		"1e",
+		`"bad \q escape"`,
+		`"\u12"`,
+		`"\(.a"`,
+		`"\(.a`,
+		`"\()"`,
+		`"\( )"`,
+		`"\(.a |)"`,
+		`"\(nosuchfunction)"`,
+		`{"\(.a)"}`,
+		`"\("unterminated)"`,
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	steps, err := ParsePath(`.tools[]."with space"[-1] | ..id?`)
+	require.NoError(t, err)
+	assert.Equal(t, []PathStep{
+		{Kind: PathKey, Key: "tools"},
+		{Kind: PathEach},
+		{Kind: PathKey, Key: "with space"},
+		{Kind: PathIndex, Index: -1},
+		{Kind: PathRecurse},
+		{Kind: PathKey, Key: "id"},
+	}, steps)
+
+	for _, expr := range []string{"", ".", ".a[", ".a | length", ".a[.b]", ".[1.5]", `.[-"a"]`, ".[1:2]", ".a, .b", "$x"} {
+		_, err := ParsePath(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		expr  string
+		input string
+		want  string
+	}{
+		{".a", `[1]`, "cannot index array"},
+		{".[]", `5`, "cannot iterate over number"},
+		{"fromjson", `"not json"`, "cannot be parsed as JSON"},
+		{`. + 1`, `"a"`, "cannot be combined with +"},
+		{"$x", `null`, "$x is not defined"},
+		{"keys", `1`, "number has no keys"},
+		{"length", `true`, "boolean has no length"},
+		{"has(1)", `{}`, "cannot check whether object has a number key"},
+		{`contains(1)`, `"a"`, "cannot have their containment checked"},
+		{"map(.)", `1`, "cannot iterate over number"},
+		{"to_entries", `[]`, "array has no keys"},
+		{"from_entries", `[1]`, "cannot make an object entry from number"},
+		{"from_entries", `[{"key": null}]`, "object keys must be strings"},
+		{"sort", `{}`, "object"},
+		{"flatten(-1)", `[]`, "depth of 0 or more"},
+		{"limit(.; 1)", `"a"`, "limit needs a number"},
+		{"fromjson", `1`, "number cannot be parsed as JSON"},
+		{"tonumber", `"abc"`, "cannot be parsed as a number"},
+		{"floor", `"a"`, "string"},
+		{"ascii_upcase", `1`, "number"},
+		{`test("(")`, `"a"`, "("},
+		{`join(",")`, `[[1]]`, "cannot join array"},
+		{`{(.a): 1}`, `{"a": 1}`, "object keys must be strings, not number"},
+		{`"\(.[])"`, `1`, "cannot iterate over number"},
+		{`.a | ."\(.)"`, `{"a": 1}`, `cannot index number with "1"`},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.expr)
+		require.NoError(t, err)
+		var value interface{}
+		require.NoError(t, json.Unmarshal([]byte(tt.input), &value))
+		_, err = q.Run(value)
+		require.Error(t, err, tt.expr)
+		assert.Contains(t, err.Error(), tt.want, tt.expr)
+	}
+}
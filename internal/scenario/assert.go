@@ -0,0 +1,166 @@
+package scenario
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp/schema"
+	"github.com/standardbeagle/mcp-tui/internal/query"
+)
+
+// check returns the ways a step's outcome fails its assertions. An error, or
+// a tool result flagged isError, fails the step unless an assertion expects it.
+func check(step Step, out outcome) []string {
+	expectsError, expectsIsError := false, false
+	for _, a := range step.Expect {
+		expectsError = expectsError || a.Error != ""
+		expectsIsError = expectsIsError || a.IsError != nil
+	}
+	if out.err != nil && !expectsError {
+		return []string{out.err.Error()}
+	}
+	if out.tool != nil && out.tool.IsError && !expectsIsError {
+		return []string{fmt.Sprintf("tool returned an error result: %s", toolText(out.tool))}
+	}
+
+	var failures []string
+	for _, a := range step.Expect {
+		if err := a.check(out); err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+	return failures
+}
+
+// check checks one assertion against an outcome
+func (a *Assertion) check(out outcome) error {
+	if a.Error != "" {
+		re, err := regexp.Compile(a.Error)
+		if err != nil {
+			return fmt.Errorf("invalid error pattern: %w", err)
+		}
+		if out.err == nil {
+			return fmt.Errorf("expected an error matching %q, the step succeeded", a.Error)
+		}
+		if !re.MatchString(out.err.Error()) {
+			return fmt.Errorf("error %q does not match %q", out.err.Error(), a.Error)
+		}
+	}
+	if a.Latency > 0 && out.latency > time.Duration(a.Latency) {
+		return fmt.Errorf("took %s, over the %s latency budget", out.latency.Round(time.Millisecond), time.Duration(a.Latency))
+	}
+	if a.IsError != nil {
+		if out.tool == nil {
+			return errors.New("isError only applies to call steps")
+		}
+		if out.tool.IsError != *a.IsError {
+			return fmt.Errorf("isError is %t, want %t", out.tool.IsError, *a.IsError)
+		}
+	}
+
+	if a.Equals == nil && a.Matches == "" && a.Schema == nil {
+		return nil
+	}
+	if err := a.checkValue(out); err != nil {
+		if a.Path != "" {
+			return fmt.Errorf("%s: %w", a.Path, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// checkValue checks the value the assertion's path selects
+func (a *Assertion) checkValue(out outcome) error {
+	if out.err != nil {
+		return fmt.Errorf("no result to check: %w", out.err)
+	}
+	value, err := selectValue(a.Path, out.value)
+	if err != nil {
+		return err
+	}
+
+	if a.Equals != nil {
+		var want any
+		if err := json.Unmarshal(a.Equals, &want); err != nil {
+			return fmt.Errorf("invalid expected value: %w", err)
+		}
+		if !reflect.DeepEqual(value, want) {
+			return fmt.Errorf("got %s, want %s", compact(value), compact(want))
+		}
+	}
+	if a.Matches != "" {
+		re, err := regexp.Compile(a.Matches)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		if !re.MatchString(text(value)) {
+			return fmt.Errorf("%s does not match %q", compact(value), a.Matches)
+		}
+	}
+	if a.Schema != nil {
+		if violations := schema.Validate(a.Schema, value); len(violations) > 0 {
+			messages := make([]string, len(violations))
+			for i, v := range violations {
+				messages[i] = v.String()
+			}
+			return fmt.Errorf("does not match the schema: %s", strings.Join(messages, "; "))
+		}
+	}
+	return nil
+}
+
+// selectValue returns the single value a query selects from value, or the
+// whole value for an empty path. Several values are collected with [...].
+func selectValue(path string, value any) (any, error) {
+	if path == "" {
+		return value, nil
+	}
+	q, err := query.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+	results, err := q.Run(value)
+	if err != nil {
+		return nil, err
+	}
+	switch len(results) {
+	case 0:
+		return nil, errors.New("selects nothing")
+	case 1:
+		return results[0], nil
+	}
+	return nil, fmt.Errorf("selects %d values; wrap the path in [...] to collect them", len(results))
+}
+
+// normalize converts a value to its JSON form, so it compares equal to the
+// same value decoded from a scenario
+func normalize(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return out, nil
+}
+
+// compact formats a value as one line of JSON for failure messages
+func compact(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	const max = 200
+	if len(data) > max {
+		return string(data[:max]) + "..."
+	}
+	return string(data)
+}
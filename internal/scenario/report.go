@@ -0,0 +1,285 @@
+package scenario
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format is a report format
+type Format string
+
+const (
+	FormatText  Format = "text"
+	FormatJUnit Format = "junit"
+	FormatTAP   Format = "tap"
+)
+
+// ParseFormat parses a report format name
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(name)); format {
+	case FormatText, FormatJUnit, FormatTAP:
+		return format, nil
+	}
+	return "", fmt.Errorf("invalid report format %q (valid: text, junit, tap)", name)
+}
+
+// Report is the JSON document of a run, for the structured output formats
+type Report struct {
+	Scenarios  []ScenarioReport `json:"scenarios"`
+	Passed     int              `json:"passed"`
+	Failed     int              `json:"failed"`
+	Skipped    int              `json:"skipped"`
+	DurationMs float64          `json:"durationMs"`
+}
+
+// ScenarioReport is the outcome of one scenario
+type ScenarioReport struct {
+	Name       string       `json:"name"`
+	File       string       `json:"file,omitempty"`
+	Status     Status       `json:"status"`
+	DurationMs float64      `json:"durationMs"`
+	Steps      []StepReport `json:"steps"`
+}
+
+// StepReport is the outcome of one step
+type StepReport struct {
+	Scenario   string   `json:"scenario"`
+	Phase      Phase    `json:"phase"`
+	Name       string   `json:"name"`
+	Status     Status   `json:"status"`
+	DurationMs float64  `json:"durationMs"`
+	Failures   []string `json:"failures,omitempty"`
+}
+
+// NewReport summarizes scenario results as a JSON document
+func NewReport(results []*Result) *Report {
+	report := &Report{Scenarios: []ScenarioReport{}}
+	var total time.Duration
+	for _, result := range results {
+		scenario := ScenarioReport{
+			Name:       result.Scenario.Name,
+			File:       result.Scenario.File,
+			Status:     Passed,
+			DurationMs: milliseconds(result.Duration),
+			Steps:      []StepReport{},
+		}
+		if result.Failed() {
+			scenario.Status = Failed
+		}
+		for _, step := range result.Steps {
+			scenario.Steps = append(scenario.Steps, StepReport{
+				Scenario:   result.Scenario.Name,
+				Phase:      step.Phase,
+				Name:       step.Name,
+				Status:     step.Status,
+				DurationMs: milliseconds(step.Duration),
+				Failures:   step.Failures,
+			})
+		}
+		report.Scenarios = append(report.Scenarios, scenario)
+		report.Passed += result.Count(Passed)
+		report.Failed += result.Count(Failed)
+		report.Skipped += result.Count(Skipped)
+		total += result.Duration
+	}
+	report.DurationMs = milliseconds(total)
+	return report
+}
+
+// Steps returns the steps of every scenario in the report, in order
+func (r *Report) Steps() []StepReport {
+	steps := []StepReport{}
+	for _, scenario := range r.Scenarios {
+		steps = append(steps, scenario.Steps...)
+	}
+	return steps
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// Write reports scenario results in a format
+func Write(w io.Writer, format Format, results []*Result) error {
+	switch format {
+	case FormatJUnit:
+		return WriteJUnit(w, results)
+	case FormatTAP:
+		return WriteTAP(w, results)
+	}
+	return WriteText(w, results)
+}
+
+// stepLabel names a step with its phase, except for the scenario's steps
+func stepLabel(step StepResult) string {
+	if step.Phase == PhaseStep {
+		return step.Name
+	}
+	return string(step.Phase) + ": " + step.Name
+}
+
+// WriteText writes a readable report: a line per step, failures indented
+// below it, and a summary
+func WriteText(w io.Writer, results []*Result) error {
+	var b strings.Builder
+	passed, failed, skipped := 0, 0, 0
+	var total time.Duration
+	for i, result := range results {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s\n", result.Scenario.Name)
+		for _, step := range result.Steps {
+			switch step.Status {
+			case Passed:
+				fmt.Fprintf(&b, "  ✓ %s (%s)\n", stepLabel(step), step.Duration.Round(time.Millisecond))
+			case Failed:
+				fmt.Fprintf(&b, "  ✗ %s (%s)\n", stepLabel(step), step.Duration.Round(time.Millisecond))
+				for _, failure := range step.Failures {
+					fmt.Fprintf(&b, "      %s\n", indent(failure, "      "))
+				}
+			case Skipped:
+				fmt.Fprintf(&b, "  - %s (skipped: %s)\n", stepLabel(step), strings.Join(step.Failures, "; "))
+			}
+		}
+		passed += result.Count(Passed)
+		failed += result.Count(Failed)
+		skipped += result.Count(Skipped)
+		total += result.Duration
+	}
+	fmt.Fprintf(&b, "\n%d passed, %d failed, %d skipped in %s\n", passed, failed, skipped, total.Round(time.Millisecond))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func indent(text, prefix string) string {
+	return strings.ReplaceAll(text, "\n", "\n"+prefix)
+}
+
+// JUnit XML elements, as read by CI servers
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	File     string      `xml:"file,attr,omitempty"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes a JUnit XML report: a test suite per scenario and a test
+// case per step
+func WriteJUnit(w io.Writer, results []*Result) error {
+	report := junitSuites{}
+	var total time.Duration
+	for _, result := range results {
+		suite := junitSuite{
+			Name:     result.Scenario.Name,
+			File:     result.Scenario.File,
+			Tests:    len(result.Steps),
+			Failures: result.Count(Failed),
+			Skipped:  result.Count(Skipped),
+			Time:     seconds(result.Duration),
+		}
+		for _, step := range result.Steps {
+			testCase := junitCase{
+				Name:      stepLabel(step),
+				Classname: result.Scenario.Name,
+				Time:      seconds(step.Duration),
+			}
+			message := &junitMessage{Message: strings.Join(step.Failures, "; "), Text: strings.Join(step.Failures, "\n")}
+			switch step.Status {
+			case Failed:
+				testCase.Failure = message
+			case Skipped:
+				testCase.Skipped = message
+			}
+			suite.Cases = append(suite.Cases, testCase)
+		}
+		report.Suites = append(report.Suites, suite)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Skipped += suite.Skipped
+		total += result.Duration
+	}
+	report.Time = seconds(total)
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JUnit report: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", xml.Header, data)
+	return err
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// WriteTAP writes a TAP version 13 report with a test point per step and
+// failures in YAML diagnostic blocks
+func WriteTAP(w io.Writer, results []*Result) error {
+	var b strings.Builder
+	total := 0
+	for _, result := range results {
+		total += len(result.Steps)
+	}
+	fmt.Fprintf(&b, "TAP version 13\n1..%d\n", total)
+
+	n := 0
+	for _, result := range results {
+		fmt.Fprintf(&b, "# %s\n", result.Scenario.Name)
+		for _, step := range result.Steps {
+			n++
+			description := tapEscape(result.Scenario.Name + ": " + stepLabel(step))
+			switch step.Status {
+			case Passed:
+				fmt.Fprintf(&b, "ok %d - %s\n", n, description)
+			case Skipped:
+				fmt.Fprintf(&b, "ok %d - %s # SKIP %s\n", n, description, tapEscape(strings.Join(step.Failures, "; ")))
+			case Failed:
+				fmt.Fprintf(&b, "not ok %d - %s\n", n, description)
+				b.WriteString("  ---\n  message: |\n")
+				for _, failure := range step.Failures {
+					for _, line := range strings.Split(failure, "\n") {
+						fmt.Fprintf(&b, "    %s\n", line)
+					}
+				}
+				fmt.Fprintf(&b, "  duration_ms: %d\n  ...\n", step.Duration.Milliseconds())
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// tapEscape keeps a description on one line and # from starting a directive
+func tapEscape(s string) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	return strings.ReplaceAll(s, "#", `\#`)
+}
@@ -0,0 +1,431 @@
+package scenario
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+)
+
+// DefaultTimeout is the step timeout when neither the step, the scenario nor
+// the runner sets one
+const DefaultTimeout = 30 * time.Second
+
+// Phase is the part of a scenario a step belongs to
+type Phase string
+
+const (
+	PhaseSetup    Phase = "setup"
+	PhaseStep     Phase = "step"
+	PhaseTeardown Phase = "teardown"
+)
+
+// Status is how a step ended
+type Status string
+
+const (
+	Passed  Status = "passed"
+	Failed  Status = "failed"
+	Skipped Status = "skipped"
+)
+
+// StepResult is the outcome of one step
+type StepResult struct {
+	Phase    Phase
+	Name     string
+	Status   Status
+	Duration time.Duration
+	Failures []string // Why the step failed or was skipped
+}
+
+// Result is the outcome of a scenario
+type Result struct {
+	Scenario *Scenario
+	Steps    []StepResult
+	Duration time.Duration
+}
+
+// Count returns how many steps ended with status
+func (r *Result) Count(status Status) int {
+	n := 0
+	for _, step := range r.Steps {
+		if step.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Failed reports whether any step failed
+func (r *Result) Failed() bool {
+	return r.Count(Failed) > 0
+}
+
+// Runner runs scenarios
+type Runner struct {
+	// Resolve returns the connection for a connection string, "" meaning the
+	// default server. Connection strings are parsed as on the command line
+	// when it is nil.
+	Resolve func(server string) (*config.ConnectionConfig, error)
+
+	// NewService creates the service for each connection; mcp.NewService when nil
+	NewService func() mcp.Service
+
+	Timeout time.Duration  // Step timeout when the scenario sets none
+	Vars    map[string]any // Variables overriding the scenario's
+}
+
+// Run runs a scenario: setup, steps and teardown. A failure in setup skips
+// the steps, a failed step skips the steps after it, and teardown always runs.
+// Without a connect step, a connection to the scenario's server is made first.
+func (r *Runner) Run(ctx context.Context, s *Scenario) *Result {
+	start := time.Now()
+	rn := &run{runner: r, scenario: s, vars: make(map[string]any)}
+	defer rn.disconnect()
+	result := &Result{Scenario: s}
+	defer func() { result.Duration = time.Since(start) }()
+
+	if err := rn.init(); err != nil {
+		result.Steps = append(result.Steps, StepResult{Phase: PhaseSetup, Name: "variables", Status: Failed, Failures: []string{err.Error()}})
+		return result
+	}
+
+	setup := s.Setup
+	if !s.connects() {
+		setup = append([]Step{{Connect: &ConnectStep{}}}, setup...)
+	}
+
+	failed := false
+	for _, phase := range []struct {
+		phase Phase
+		steps []Step
+	}{{PhaseSetup, setup}, {PhaseStep, s.Steps}} {
+		for _, step := range phase.steps {
+			if failed {
+				result.Steps = append(result.Steps, StepResult{
+					Phase: phase.phase, Name: step.title(), Status: Skipped,
+					Failures: []string{"an earlier step failed"},
+				})
+				continue
+			}
+			stepResult := rn.step(ctx, phase.phase, step)
+			failed = stepResult.Status == Failed
+			result.Steps = append(result.Steps, stepResult)
+		}
+	}
+	for _, step := range s.Teardown {
+		result.Steps = append(result.Steps, rn.step(ctx, PhaseTeardown, step))
+	}
+	return result
+}
+
+// connects reports whether the scenario has its own connect step
+func (s *Scenario) connects() bool {
+	for _, steps := range [][]Step{s.Setup, s.Steps} {
+		for _, step := range steps {
+			if step.Connect != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// run is the state of one scenario run
+type run struct {
+	runner   *Runner
+	scenario *Scenario
+	vars     map[string]any
+
+	service     mcp.Service
+	inbox       *inbox
+	unsubscribe func()
+}
+
+// init sets the scenario's variables, then the runner's
+func (rn *run) init() error {
+	for name, value := range rn.scenario.Vars {
+		expanded, err := expand(value, nil)
+		if err != nil {
+			return fmt.Errorf("vars.%s: %w", name, err)
+		}
+		rn.vars[name] = expanded
+	}
+	for name, value := range rn.runner.Vars {
+		rn.vars[name] = value
+	}
+	return nil
+}
+
+// outcome is what a step's operation produced
+type outcome struct {
+	value   any // The result in its JSON form
+	err     error
+	latency time.Duration
+	tool    *mcp.CallToolResult // Set for call steps
+}
+
+// step runs one step, checks its assertions and captures its variables
+func (rn *run) step(ctx context.Context, phase Phase, step Step) StepResult {
+	result := StepResult{Phase: phase, Name: step.title()}
+	fail := func(failures ...string) StepResult {
+		result.Status = Failed
+		result.Failures = failures
+		return result
+	}
+
+	expanded, err := rn.expandStep(step)
+	if err != nil {
+		return fail(err.Error())
+	}
+	result.Name = expanded.title()
+
+	timeout := time.Duration(expanded.Timeout)
+	if timeout <= 0 {
+		timeout = time.Duration(rn.scenario.Timeout)
+	}
+	if timeout <= 0 {
+		timeout = rn.runner.Timeout
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	out := rn.perform(stepCtx, expanded, timeout)
+	out.latency = time.Since(start)
+	result.Duration = out.latency
+
+	if failures := check(expanded, out); len(failures) > 0 {
+		return fail(failures...)
+	}
+	if err := rn.capture(expanded, out); err != nil {
+		return fail(err.Error())
+	}
+	result.Status = Passed
+	return result
+}
+
+// expandStep replaces the variables in a step and decodes it again
+func (rn *run) expandStep(step Step) (Step, error) {
+	if step.raw == nil {
+		return step, nil
+	}
+	raw, err := expand(step.raw, rn.vars)
+	if err != nil {
+		return Step{}, err
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return Step{}, err
+	}
+	var expanded Step
+	if err := json.Unmarshal(data, &expanded); err != nil {
+		return Step{}, fmt.Errorf("invalid step after expanding variables: %w", err)
+	}
+	if err := expanded.validate(); err != nil {
+		return Step{}, err
+	}
+	return expanded, nil
+}
+
+// perform runs a step's operation
+func (rn *run) perform(ctx context.Context, step Step, timeout time.Duration) outcome {
+	if step.Connect != nil {
+		info, err := rn.connect(ctx, step.Connect.Server)
+		return outcomeOf(info, err)
+	}
+	if rn.service == nil {
+		return outcome{err: errors.New("not connected")}
+	}
+
+	switch {
+	case step.List == "tools":
+		tools, err := rn.service.ListTools(ctx)
+		return outcomeOf(map[string]any{"tools": tools, "count": len(tools)}, err)
+	case step.List == "resources":
+		resources, err := rn.service.ListResources(ctx)
+		return outcomeOf(map[string]any{"resources": resources, "count": len(resources)}, err)
+	case step.List == "prompts":
+		prompts, err := rn.service.ListPrompts(ctx)
+		return outcomeOf(map[string]any{"prompts": prompts, "count": len(prompts)}, err)
+	case step.Call != nil:
+		res, err := rn.service.CallTool(ctx, mcp.CallToolRequest{Name: step.Call.Tool, Arguments: step.Call.Arguments})
+		out := outcomeOf(res, err)
+		out.tool = res
+		return out
+	case step.Read != "":
+		contents, err := rn.service.ReadResource(ctx, step.Read)
+		return outcomeOf(map[string]any{"uri": step.Read, "contents": contents, "count": len(contents)}, err)
+	case step.Prompt != nil:
+		res, err := rn.service.GetPrompt(ctx, mcp.GetPromptRequest{Name: step.Prompt.Name, Arguments: step.Prompt.Arguments})
+		return outcomeOf(res, err)
+	case step.Wait != nil:
+		notification, err := rn.inbox.take(ctx, step.Wait)
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = fmt.Errorf("no %s notification within %s", step.Wait.Notification, timeout)
+		}
+		return outcomeOf(notification, err)
+	}
+	return outcome{err: fmt.Errorf("cannot list %q", step.List)}
+}
+
+// outcomeOf converts an operation's result to its JSON form
+func outcomeOf(value any, err error) outcome {
+	if err != nil {
+		return outcome{err: err}
+	}
+	normalized, err := normalize(value)
+	return outcome{value: normalized, err: err}
+}
+
+// connect replaces the connection with one to server, or to the scenario's
+// server when it is empty
+func (rn *run) connect(ctx context.Context, server string) (*mcp.ServerInfo, error) {
+	rn.disconnect()
+	if server == "" && rn.scenario.Server != "" {
+		expanded, err := expandString(rn.scenario.Server, rn.vars)
+		if err != nil {
+			return nil, fmt.Errorf("server: %w", err)
+		}
+		server = text(expanded)
+	}
+
+	var connConfig *config.ConnectionConfig
+	var err error
+	switch {
+	case rn.runner.Resolve != nil:
+		connConfig, err = rn.runner.Resolve(server)
+	case server == "":
+		err = errors.New("no server to connect to: set server in the scenario")
+	default:
+		connConfig = config.ParseConnectionString(server)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	newService := rn.runner.NewService
+	if newService == nil {
+		newService = mcp.NewService
+	}
+	service := newService()
+	box := newInbox()
+	unsubscribe := service.EventLog().Subscribe(box.add)
+	if err := service.Connect(ctx, connConfig); err != nil {
+		unsubscribe()
+		return nil, err
+	}
+	rn.service, rn.inbox, rn.unsubscribe = service, box, unsubscribe
+	return service.GetServerInfo(), nil
+}
+
+func (rn *run) disconnect() {
+	if rn.service == nil {
+		return
+	}
+	rn.unsubscribe()
+	rn.service.Disconnect()
+	rn.service, rn.inbox, rn.unsubscribe = nil, nil, nil
+}
+
+// capture sets the step's variables from its result
+func (rn *run) capture(step Step, out outcome) error {
+	names := make([]string, 0, len(step.Capture))
+	for name := range step.Capture {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if out.err != nil {
+			return fmt.Errorf("cannot capture %s: the step failed", name)
+		}
+		value, err := selectValue(step.Capture[name], out.value)
+		if err != nil {
+			return fmt.Errorf("capture %s: %w", name, err)
+		}
+		rn.vars[name] = value
+	}
+	return nil
+}
+
+// inbox queues the notifications a connection receives until a wait step
+// takes them
+type inbox struct {
+	mu      sync.Mutex
+	pending []map[string]any
+	signal  chan struct{}
+}
+
+func newInbox() *inbox {
+	return &inbox{signal: make(chan struct{}, 1)}
+}
+
+func (b *inbox) add(entry debug.MCPLogEntry) {
+	if entry.Direction != "←" || entry.MessageType != debug.MCPMessageNotification {
+		return
+	}
+	notification, err := normalize(map[string]any{"method": entry.Method, "params": entry.Params})
+	if err != nil {
+		return
+	}
+	b.mu.Lock()
+	b.pending = append(b.pending, notification.(map[string]any))
+	b.mu.Unlock()
+	select {
+	case b.signal <- struct{}{}:
+	default:
+	}
+}
+
+// take removes and returns the first pending notification the wait matches,
+// waiting for one until ctx is done
+func (b *inbox) take(ctx context.Context, wait *WaitStep) (map[string]any, error) {
+	for {
+		b.mu.Lock()
+		for i, notification := range b.pending {
+			if notification["method"] == wait.Notification && matchesAll(wait.Where, notification) {
+				b.pending = append(b.pending[:i], b.pending[i+1:]...)
+				b.mu.Unlock()
+				return notification, nil
+			}
+		}
+		b.mu.Unlock()
+
+		select {
+		case <-b.signal:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func matchesAll(assertions []Assertion, value any) bool {
+	for _, a := range assertions {
+		if a.check(outcome{value: value}) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// toolText joins the text content of a tool result
+func toolText(res *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range res.Content {
+		if content.Text != "" {
+			parts = append(parts, content.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
@@ -0,0 +1,224 @@
+// Package scenario runs test scenarios against MCP servers. A scenario is a
+// YAML or JSON file listing steps (connect, list, call a tool, read a
+// resource, get a prompt, wait for a notification) with assertions on their
+// results, so a server's behavior can be checked in CI without writing Go.
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/standardbeagle/mcp-tui/internal/yamlutil"
+)
+
+// Scenario is a test scenario file
+type Scenario struct {
+	Name     string            `json:"name"`
+	Server   string            `json:"server"`  // Connection string, as given on the command line
+	Timeout  yamlutil.Duration `json:"timeout"` // Default step timeout
+	Vars     map[string]any    `json:"vars"`    // Initial variables; values may use ${env.NAME}
+	Setup    []Step            `json:"setup"`
+	Steps    []Step            `json:"steps"`
+	Teardown []Step            `json:"teardown"` // Run even when setup or a step fails
+
+	File string `json:"-"` // Where the scenario was loaded from
+}
+
+// Step is one operation and the assertions on its result. Exactly one of
+// Connect, List, Call, Read, Prompt and Wait is set.
+type Step struct {
+	Name    string            `json:"name"`
+	Timeout yamlutil.Duration `json:"timeout"`
+	Connect *ConnectStep      `json:"connect"`
+	List    string            `json:"list"` // tools, resources or prompts
+	Call    *CallStep         `json:"call"`
+	Read    string            `json:"read"` // Resource URI
+	Prompt  *PromptStep       `json:"prompt"`
+	Wait    *WaitStep         `json:"wait"`
+	Expect  []Assertion       `json:"expect"`
+	Capture map[string]string `json:"capture"` // Variable name to the path of its value in the result
+
+	raw map[string]any // The step as written, expanded with variables when it runs
+}
+
+// ConnectStep connects to a server, replacing the current connection. It is
+// written as true for the scenario's server, or as a connection string.
+type ConnectStep struct {
+	Server string `json:"server"`
+}
+
+// CallStep calls a tool
+type CallStep struct {
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// PromptStep gets a prompt
+type PromptStep struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// WaitStep waits for a notification from the server. Notifications received
+// since the connection was made count, each satisfying one wait.
+type WaitStep struct {
+	Notification string      `json:"notification"` // Method, e.g. notifications/message
+	Where        []Assertion `json:"where"`        // Notifications failing these are left for later waits
+}
+
+// Assertion checks a step's outcome. The value checks (equals, matches,
+// schema) apply to the value Path selects from the result.
+type Assertion struct {
+	Path    string            `json:"path"`    // Query selecting the value; the whole result when empty
+	Equals  json.RawMessage   `json:"equals"`  // Expected value; null is compared, absence is not
+	Matches string            `json:"matches"` // Regular expression the value's text must match
+	Schema  map[string]any    `json:"schema"`  // JSON Schema the value must satisfy
+	IsError *bool             `json:"isError"` // Whether a tool result is flagged as an error
+	Latency yamlutil.Duration `json:"latency"` // Longest the step may take
+	Error   string            `json:"error"`   // Regular expression the step's error must match
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the step as written so
+// its variables can be expanded when it runs
+func (s *Step) UnmarshalJSON(data []byte) error {
+	type plain Step
+	if err := json.Unmarshal(data, (*plain)(s)); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &s.raw)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting true, a connection
+// string or an object
+func (c *ConnectStep) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch val := v.(type) {
+	case bool:
+		if !val {
+			return fmt.Errorf("connect must be true, a connection string or an object")
+		}
+	case string:
+		c.Server = val
+	case map[string]any:
+		type plain ConnectStep
+		return json.Unmarshal(data, (*plain)(c))
+	default:
+		return fmt.Errorf("connect must be true, a connection string or an object")
+	}
+	return nil
+}
+
+// Load reads a scenario from a YAML or JSON file
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+	s, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %w", path, err)
+	}
+	s.File = path
+	if s.Name == "" {
+		s.Name = path
+	}
+	return s, nil
+}
+
+// Parse parses a YAML or JSON scenario. YAML is decoded to generic values and
+// re-encoded as JSON so both formats share the JSON field names.
+func Parse(data []byte) (*Scenario, error) {
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	jsonData, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("scenario is not JSON-compatible: %w", err)
+	}
+
+	var s Scenario
+	if err := json.Unmarshal(jsonData, &s); err != nil {
+		return nil, err
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Validate checks that every step has one operation and what it needs
+func (s *Scenario) Validate() error {
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("no steps")
+	}
+	for _, phase := range []struct {
+		name  string
+		steps []Step
+	}{{"setup", s.Setup}, {"steps", s.Steps}, {"teardown", s.Teardown}} {
+		for i := range phase.steps {
+			if err := phase.steps[i].validate(); err != nil {
+				return fmt.Errorf("%s[%d]: %w", phase.name, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Step) validate() error {
+	kinds := 0
+	for _, set := range []bool{s.Connect != nil, s.List != "", s.Call != nil, s.Read != "", s.Prompt != nil, s.Wait != nil} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return fmt.Errorf("a step needs exactly one of connect, list, call, read, prompt and wait")
+	}
+
+	switch {
+	case s.List != "" && s.List != "tools" && s.List != "resources" && s.List != "prompts" && !hasVariable(s.List):
+		return fmt.Errorf("list must be tools, resources or prompts, not %q", s.List)
+	case s.Call != nil && s.Call.Tool == "":
+		return fmt.Errorf("call: tool is required")
+	case s.Prompt != nil && s.Prompt.Name == "":
+		return fmt.Errorf("prompt: name is required")
+	case s.Wait != nil && s.Wait.Notification == "":
+		return fmt.Errorf("wait: notification is required")
+	}
+	for name := range s.Capture {
+		if !variableName.MatchString(name) {
+			return fmt.Errorf("capture: invalid variable name %q", name)
+		}
+	}
+	return nil
+}
+
+// title names a step in reports, after the operation when it has no name
+func (s *Step) title() string {
+	switch {
+	case s.Name != "":
+		return s.Name
+	case s.Connect != nil && s.Connect.Server != "":
+		return "connect " + s.Connect.Server
+	case s.Connect != nil:
+		return "connect"
+	case s.List != "":
+		return "list " + s.List
+	case s.Call != nil:
+		return "call " + s.Call.Tool
+	case s.Read != "":
+		return "read " + s.Read
+	case s.Prompt != nil:
+		return "prompt " + s.Prompt.Name
+	case s.Wait != nil:
+		return "wait " + s.Wait.Notification
+	}
+	return "step"
+}
@@ -0,0 +1,321 @@
+package scenario
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/mock"
+	"github.com/standardbeagle/mcp-tui/internal/yamlutil"
+)
+
+const testServer = `
+server:
+  name: scenario-mock
+  version: 1.2.3
+tools:
+  - name: greet
+    inputSchema:
+      type: object
+      properties:
+        name: {type: string}
+      required: [name]
+    responses:
+      - when: {name: nobody}
+        text: "nobody to greet"
+        isError: true
+    response:
+      text: '{"greeting": "Hello, {{.args.name}}!", "id": 7}'
+    notifications:
+      - method: notifications/message
+        params: {level: info, data: "greeting {{.args.name}}"}
+resources:
+  - uri: mock://readme
+    mimeType: text/plain
+    text: "read me"
+prompts:
+  - name: ask
+    arguments:
+      - name: topic
+        required: true
+    messages:
+      - role: user
+        text: "Tell me about {{.args.topic}}"
+`
+
+// newTestRunner serves the mock server over HTTP and returns a runner
+// connecting to it
+func newTestRunner(t *testing.T) *Runner {
+	def, err := mock.ParseDefinition([]byte(testServer))
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(mock.NewServer(def).HTTPHandler())
+	t.Cleanup(httpServer.Close)
+
+	return &Runner{
+		Resolve: func(server string) (*config.ConnectionConfig, error) {
+			return &config.ConnectionConfig{Type: config.TransportHTTP, URL: httpServer.URL}, nil
+		},
+		Timeout: 5 * time.Second,
+	}
+}
+
+func statuses(result *Result) []Status {
+	var out []Status
+	for _, step := range result.Steps {
+		out = append(out, step.Status)
+	}
+	return out
+}
+
+func TestParse(t *testing.T) {
+	s, err := Parse([]byte(`
+name: smoke
+timeout: 2s
+steps:
+  - connect: true
+  - connect: "node other.js"
+  - list: tools
+    expect:
+      - {path: .count, equals: 1}
+      - {path: .missing, equals: null}
+      - {path: ".tools[0].name"}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, yamlutil.Duration(2*time.Second), s.Timeout)
+	assert.Equal(t, "", s.Steps[0].Connect.Server)
+	assert.Equal(t, "node other.js", s.Steps[1].Connect.Server)
+	assert.JSONEq(t, "1", string(s.Steps[2].Expect[0].Equals))
+	assert.Equal(t, "null", string(s.Steps[2].Expect[1].Equals), "equals: null is an expected value")
+	assert.Nil(t, s.Steps[2].Expect[2].Equals, "a missing equals is not checked")
+
+	for _, bad := range []string{
+		`steps: []`,
+		`steps: [{name: nothing}]`,
+		`steps: [{list: tools, read: "mock://x"}]`,
+		`steps: [{list: widgets}]`,
+		`steps: [{call: {arguments: {}}}]`,
+		`steps: [{connect: false}]`,
+		`steps: [{wait: {}}]`,
+		`steps: [{list: tools, capture: {"bad name": ".count"}}]`,
+		`steps: [{list: tools, timeout: soon}]`,
+	} {
+		_, err := Parse([]byte(bad))
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestExampleScenario(t *testing.T) {
+	s, err := Load(filepath.Join("..", "..", "examples", "scenario.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "mock weather service", s.Name)
+	assert.Len(t, s.Steps, 7)
+}
+
+func TestExpand(t *testing.T) {
+	t.Setenv("SCENARIO_TOKEN", "secret")
+	vars := map[string]any{"id": float64(7), "name": "Grace", "tags": []any{"a"}}
+
+	got, err := expand(map[string]any{
+		"id":     "${id}",
+		"text":   "id ${id} for ${name} with ${tags}",
+		"token":  "Bearer ${env.SCENARIO_TOKEN}",
+		"list":   []any{"${name}", "$${name}"},
+		"number": 3.0,
+	}, vars)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"id":     float64(7),
+		"text":   `id 7 for Grace with ["a"]`,
+		"token":  "Bearer secret",
+		"list":   []any{"Grace", "${name}"},
+		"number": 3.0,
+	}, got)
+
+	_, err = expand("${missing}", vars)
+	assert.ErrorContains(t, err, `undefined variable "missing"`)
+	_, err = expand("${env.SCENARIO_UNSET_VARIABLE}", vars)
+	assert.ErrorContains(t, err, "SCENARIO_UNSET_VARIABLE is not set")
+}
+
+func TestRunScenario(t *testing.T) {
+	s, err := Parse([]byte(`
+name: greeter
+vars: {who: Grace}
+setup:
+  - list: tools
+    expect:
+      - {path: .count, equals: 1}
+      - {path: "[.tools[].name]", equals: [greet]}
+steps:
+  - call: {tool: greet, arguments: {name: "${who}"}}
+    expect:
+      - {path: ".content[0].text.greeting", equals: "Hello, Grace!"}
+      - {path: ".content[0].text | fromjson", schema: {type: object, required: [id]}}
+      - {latency: 5s}
+    capture: {id: ".content[0].text.id"}
+  - wait:
+      notification: notifications/message
+      where: [{path: .params.data, matches: "Grace$"}]
+    expect:
+      - {path: .params.level, equals: info}
+  - call: {tool: greet, arguments: {name: nobody}}
+    expect:
+      - {isError: true}
+      - {path: ".content[0].text", matches: "^nobody"}
+  - read: mock://readme
+    expect:
+      - {path: ".contents[0].text", equals: "read me"}
+  - prompt: {name: ask, arguments: {topic: "id ${id}"}}
+    expect:
+      - {path: ".messages[0].content[0].text.text", equals: "Tell me about id 7"}
+  - read: mock://missing
+    expect:
+      - {error: "(?i)not found|unknown"}
+`))
+	require.NoError(t, err)
+
+	result := newTestRunner(t).Run(context.Background(), s)
+	for _, step := range result.Steps {
+		assert.Equal(t, Passed, step.Status, "%s: %v", step.Name, step.Failures)
+	}
+	require.Len(t, result.Steps, 8)
+	assert.Equal(t, "setup: connect", stepLabel(result.Steps[0]), "the runner connects when no step does")
+	assert.False(t, result.Failed())
+}
+
+func TestRunFailuresSkipAndTeardown(t *testing.T) {
+	s, err := Parse([]byte(`
+name: failing
+timeout: 500ms
+steps:
+  - connect: true
+    expect:
+      - {path: .name, equals: scenario-mock}
+  - call: {tool: greet, arguments: {name: nobody}}
+  - call: {tool: greet, arguments: {name: Ada}}
+teardown:
+  - list: prompts
+    expect:
+      - {path: .count, equals: 2}
+`))
+	require.NoError(t, err)
+
+	result := newTestRunner(t).Run(context.Background(), s)
+	assert.Equal(t, []Status{Passed, Failed, Skipped, Failed}, statuses(result))
+	assert.Contains(t, result.Steps[1].Failures[0], "tool returned an error result: nobody to greet")
+	assert.Equal(t, []string{".count: got 1, want 2"}, result.Steps[3].Failures)
+	assert.True(t, result.Failed())
+
+	s, err = Parse([]byte(`
+steps:
+  - wait: {notification: notifications/never}
+    timeout: 200ms
+  - list: tools
+setup:
+  - call: {tool: greet, arguments: {name: x}}
+    capture: {missing: ".content[0].text.nothing[]"}
+`))
+	require.NoError(t, err)
+	result = newTestRunner(t).Run(context.Background(), s)
+	assert.Equal(t, []Status{Passed, Failed, Skipped, Skipped}, statuses(result))
+	assert.Contains(t, result.Steps[1].Failures[0], "capture missing")
+
+	s, err = Parse([]byte(`steps: [{wait: {notification: notifications/never}, timeout: 200ms}]`))
+	require.NoError(t, err)
+	result = newTestRunner(t).Run(context.Background(), s)
+	assert.Equal(t, []string{"no notifications/never notification within 200ms"}, result.Steps[1].Failures)
+}
+
+func testResults() []*Result {
+	return []*Result{{
+		Scenario: &Scenario{Name: "smoke", File: "smoke.yaml"},
+		Duration: 1500 * time.Millisecond,
+		Steps: []StepResult{
+			{Phase: PhaseSetup, Name: "connect", Status: Passed, Duration: 20 * time.Millisecond},
+			{Phase: PhaseStep, Name: "call greet", Status: Failed, Duration: 5 * time.Millisecond, Failures: []string{`.text: got "a", want "b"`}},
+			{Phase: PhaseStep, Name: "list #tools", Status: Skipped, Failures: []string{"an earlier step failed"}},
+		},
+	}}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatJUnit, testResults()))
+
+	var report junitSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, 3, report.Tests)
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, 1, report.Skipped)
+	require.Len(t, report.Suites, 1)
+	suite := report.Suites[0]
+	assert.Equal(t, "smoke", suite.Name)
+	assert.Equal(t, "1.500", suite.Time)
+	assert.Equal(t, "setup: connect", suite.Cases[0].Name)
+	require.NotNil(t, suite.Cases[1].Failure)
+	assert.Equal(t, `.text: got "a", want "b"`, suite.Cases[1].Failure.Message)
+	assert.NotNil(t, suite.Cases[2].Skipped)
+}
+
+func TestWriteTAP(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatTAP, testResults()))
+	assert.Equal(t, `TAP version 13
+1..3
+# smoke
+ok 1 - smoke: setup: connect
+not ok 2 - smoke: call greet
+  ---
+  message: |
+    .text: got "a", want "b"
+  duration_ms: 5
+  ...
+ok 3 - smoke: list \#tools # SKIP an earlier step failed
+`, buf.String())
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, FormatText, testResults()))
+	assert.Equal(t, `smoke
+  ✓ setup: connect (20ms)
+  ✗ call greet (5ms)
+      .text: got "a", want "b"
+  - list #tools (skipped: an earlier step failed)
+
+1 passed, 1 failed, 1 skipped in 1.5s
+`, buf.String())
+
+	_, err := ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestNewReport(t *testing.T) {
+	report := NewReport(testResults())
+	assert.Equal(t, 1, report.Passed)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, 1500.0, report.DurationMs)
+	require.Len(t, report.Scenarios, 1)
+	assert.Equal(t, Failed, report.Scenarios[0].Status)
+	assert.Equal(t, "smoke.yaml", report.Scenarios[0].File)
+
+	steps := report.Steps()
+	require.Len(t, steps, 3)
+	assert.Equal(t, StepReport{
+		Scenario:   "smoke",
+		Phase:      PhaseStep,
+		Name:       "call greet",
+		Status:     Failed,
+		DurationMs: 5,
+		Failures:   []string{`.text: got "a", want "b"`},
+	}, steps[1])
+}
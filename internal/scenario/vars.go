@@ -0,0 +1,101 @@
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// reference matches ${name} and ${env.NAME}; $${...} is a literal ${...}
+var reference = regexp.MustCompile(`\$?\$\{([^}]*)\}`)
+
+// variableName is what capture and vars may name
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func hasVariable(s string) bool {
+	return reference.MatchString(s)
+}
+
+// expand replaces variable references in every string of value. A string
+// that is a single reference takes the variable's value, keeping its type;
+// references inside longer strings are replaced by the value's text.
+func expand(value any, vars map[string]any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return expandString(v, vars)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			expanded, err := expand(item, vars)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = expanded
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			expanded, err := expand(item, vars)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = expanded
+		}
+		return out, nil
+	}
+	return value, nil
+}
+
+func expandString(s string, vars map[string]any) (any, error) {
+	if m := reference.FindStringSubmatch(s); m != nil && m[0] == s && !strings.HasPrefix(s, "$$") {
+		return lookup(m[1], vars)
+	}
+
+	var err error
+	out := reference.ReplaceAllStringFunc(s, func(ref string) string {
+		if strings.HasPrefix(ref, "$$") {
+			return ref[1:]
+		}
+		value, lookupErr := lookup(reference.FindStringSubmatch(ref)[1], vars)
+		if lookupErr != nil {
+			err = lookupErr
+			return ref
+		}
+		return text(value)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func lookup(name string, vars map[string]any) (any, error) {
+	if env, ok := strings.CutPrefix(name, "env."); ok {
+		value, set := os.LookupEnv(env)
+		if !set {
+			return nil, fmt.Errorf("environment variable %s is not set", env)
+		}
+		return value, nil
+	}
+	value, ok := vars[name]
+	if !ok {
+		return nil, fmt.Errorf("undefined variable %q", name)
+	}
+	return value, nil
+}
+
+// text is a value as it appears inside a string: strings as they are and
+// other values as JSON
+func text(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
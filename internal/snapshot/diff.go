@@ -0,0 +1,235 @@
+package snapshot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// contextLines is how many unchanged lines surround each change in a diff
+const contextLines = 3
+
+// Change is how a file differs between a saved and a live snapshot
+type Change string
+
+const (
+	Added   Change = "added"   // Only the live server has the file
+	Removed Change = "removed" // Only the saved snapshot has the file
+	Changed Change = "changed"
+)
+
+// FileDiff is a file that differs, with a unified diff from the saved file
+// to the live one
+type FileDiff struct {
+	File   string `json:"file"`
+	Change Change `json:"change"`
+	Diff   string `json:"diff"`
+}
+
+// Compare returns the files that differ between a saved snapshot and a live
+// one, in order of file name
+func Compare(saved, live *Snapshot) []FileDiff {
+	names := saved.Names()
+	for _, name := range live.Names() {
+		if _, ok := saved.Files[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diffs []FileDiff
+	for _, name := range names {
+		before, inSaved := saved.Files[name]
+		after, inLive := live.Files[name]
+		var change Change
+		switch {
+		case !inLive:
+			change = Removed
+		case !inSaved:
+			change = Added
+		case string(before) != string(after):
+			change = Changed
+		default:
+			continue
+		}
+		diffs = append(diffs, FileDiff{
+			File:   name,
+			Change: change,
+			Diff:   UnifiedDiff("saved/"+name, "live/"+name, string(before), string(after)),
+		})
+	}
+	return diffs
+}
+
+// UnifiedDiff returns a unified diff between two texts, or "" when they are
+// equal
+func UnifiedDiff(fromName, toName, from, to string) string {
+	a, b := splitLines(from), splitLines(to)
+	edits := diffLines(a, b)
+
+	var out strings.Builder
+	for start := 0; start < len(edits); {
+		// Find the next change and the hunk around it
+		for start < len(edits) && edits[start].op == opEqual {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+		first := max(start-contextLines, 0)
+		end := start
+		for i := start; i < len(edits); i++ {
+			if edits[i].op != opEqual {
+				end = i + 1
+				continue
+			}
+			if i-end >= 2*contextLines {
+				break
+			}
+		}
+		last := min(end+contextLines, len(edits))
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+		}
+		aStart, bStart := edits[first].aLine, edits[first].bLine
+		aCount, bCount := 0, 0
+		for _, e := range edits[first:last] {
+			if e.op != opInsert {
+				aCount++
+			}
+			if e.op != opDelete {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+		for _, e := range edits[first:last] {
+			switch e.op {
+			case opEqual:
+				out.WriteString(" " + a[e.aLine] + "\n")
+			case opDelete:
+				out.WriteString("-" + a[e.aLine] + "\n")
+			case opInsert:
+				out.WriteString("+" + b[e.bLine] + "\n")
+			}
+		}
+		start = last
+	}
+	return out.String()
+}
+
+// hunkRange formats the start and length of a hunk, counting lines from 1
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+type editOp int
+
+const (
+	opEqual editOp = iota
+	opDelete
+	opInsert
+)
+
+// edit is one line of a diff. aLine and bLine are where it falls in each
+// text, counting from 0.
+type edit struct {
+	op           editOp
+	aLine, bLine int
+}
+
+// diffLines returns the shortest edit script from a to b, found with Myers'
+// algorithm
+func diffLines(a, b []string) []edit {
+	n, m := len(a), len(b)
+	// A file that is only on one side needs no search
+	if n == 0 || m == 0 {
+		edits := make([]edit, 0, n+m)
+		for i := range a {
+			edits = append(edits, edit{op: opDelete, aLine: i})
+		}
+		for j := range b {
+			edits = append(edits, edit{op: opInsert, bLine: j})
+		}
+		return edits
+	}
+	maxD := n + m
+	offset := maxD + 1
+	v := make([]int, 2*maxD+2)
+	var trace [][]int
+
+	for d := 0; d <= maxD; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b, offset, d)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack walks the saved frontiers back from the end to recover the
+// edits
+func backtrack(trace [][]int, a, b []string, offset, d int) []edit {
+	x, y := len(a), len(b)
+	var edits []edit
+	for ; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{op: opEqual, aLine: x, bLine: y})
+		}
+		if x == prevX {
+			y--
+			edits = append(edits, edit{op: opInsert, aLine: x, bLine: y})
+		} else {
+			x--
+			edits = append(edits, edit{op: opDelete, aLine: x, bLine: y})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		edits = append(edits, edit{op: opEqual, aLine: x, bLine: y})
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
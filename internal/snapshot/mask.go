@@ -0,0 +1,95 @@
+package snapshot
+
+import (
+	"fmt"
+
+	"github.com/standardbeagle/mcp-tui/internal/query"
+)
+
+// Masked replaces masked values
+const Masked = "<masked>"
+
+// Mask is a parsed mask path, a --query path expression made of:
+//
+//	.name      the value of a key (."quoted" for other characters)
+//	[N]        the element at an index, from the end if negative
+//	[]         every element or value
+//	..name     the value of a key at any depth
+//
+// It starts at the whole snapshot, so its first key names a file:
+// .tools[].description, .calls.now.content[0].text, ..timestamp.
+type Mask struct {
+	path  string
+	steps []query.PathStep
+}
+
+// ParseMask parses a mask path
+func ParseMask(path string) (Mask, error) {
+	steps, err := query.ParsePath(path)
+	if err != nil {
+		return Mask{}, fmt.Errorf("invalid mask %q: %w", path, err)
+	}
+	return Mask{path: path, steps: steps}, nil
+}
+
+// String returns the path the mask was parsed from
+func (m Mask) String() string {
+	return m.path
+}
+
+// Apply replaces every value the mask matches with Masked. It changes value
+// in place where it can and returns the result. Values the path does not
+// reach are left alone, since a volatile field may come and go.
+func (m Mask) Apply(value any) any {
+	return applySteps(value, m.steps)
+}
+
+func applySteps(value any, steps []query.PathStep) any {
+	if len(steps) == 0 {
+		return Masked
+	}
+	step, rest := steps[0], steps[1:]
+	switch step.Kind {
+	case query.PathKey:
+		if obj, ok := value.(map[string]any); ok {
+			if child, ok := obj[step.Key]; ok {
+				obj[step.Key] = applySteps(child, rest)
+			}
+		}
+	case query.PathIndex:
+		if list, ok := value.([]any); ok {
+			index := step.Index
+			if index < 0 {
+				index += len(list)
+			}
+			if index >= 0 && index < len(list) {
+				list[index] = applySteps(list[index], rest)
+			}
+		}
+	case query.PathEach:
+		switch v := value.(type) {
+		case map[string]any:
+			for key, child := range v {
+				v[key] = applySteps(child, rest)
+			}
+		case []any:
+			for i, child := range v {
+				v[i] = applySteps(child, rest)
+			}
+		}
+	case query.PathRecurse:
+		// The rest of the path applies to the value and everything below it
+		value = applySteps(value, rest)
+		switch v := value.(type) {
+		case map[string]any:
+			for key, child := range v {
+				v[key] = applySteps(child, steps)
+			}
+		case []any:
+			for i, child := range v {
+				v[i] = applySteps(child, steps)
+			}
+		}
+	}
+	return value
+}
@@ -0,0 +1,368 @@
+// Package snapshot captures an MCP server's catalog (its tools, resources and
+// prompts) and the results of scripted tool calls as normalized JSON files,
+// and compares a live server against files saved earlier, so that a change
+// to a tool name, schema or description shows up as a diff in review.
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+)
+
+// ConfigFile is the name of the configuration file in a snapshot directory
+const ConfigFile = "snapshot.yaml"
+
+// callsDir holds the scripted call results within a snapshot directory
+const callsDir = "calls"
+
+// callName is what a call's name may be, since it names its file
+var callName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Config is a snapshot directory's configuration: the tool calls whose
+// results are captured, and the paths of volatile values to mask
+type Config struct {
+	Calls []Call   `json:"calls" yaml:"calls,omitempty"`
+	Mask  []string `json:"mask" yaml:"mask,omitempty"`
+}
+
+// Call is a scripted tool call
+type Call struct {
+	Name      string         `json:"name" yaml:"name,omitempty"` // Names the result file; the tool's name when empty
+	Tool      string         `json:"tool" yaml:"tool"`
+	Arguments map[string]any `json:"arguments" yaml:"arguments,omitempty"`
+}
+
+// LoadConfig reads a snapshot configuration. A missing file is an empty
+// configuration.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot config: %w", err)
+	}
+	cfg, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ParseConfig parses a YAML or JSON snapshot configuration. YAML is decoded
+// to generic values and re-encoded as JSON so both formats share the JSON
+// field names.
+func ParseConfig(data []byte) (*Config, error) {
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	var cfg Config
+	if raw != nil {
+		jsonData, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("config is not JSON-compatible: %w", err)
+		}
+		if err := json.Unmarshal(jsonData, &cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate checks that every call has a tool and a unique file name, and
+// that every mask parses
+func (c *Config) Validate() error {
+	seen := make(map[string]bool)
+	for i, call := range c.Calls {
+		if call.Tool == "" {
+			return fmt.Errorf("calls[%d]: tool is required", i)
+		}
+		name := call.name()
+		if !callName.MatchString(name) {
+			return fmt.Errorf("calls[%d]: name %q must be letters, digits, '.', '_' or '-'", i, name)
+		}
+		if seen[name] {
+			return fmt.Errorf("calls[%d]: name %q is used twice; give the calls different names", i, name)
+		}
+		seen[name] = true
+	}
+	for _, path := range c.Mask {
+		if _, err := ParseMask(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddMasks adds masks not in the configuration already and reports whether
+// it added any
+func (c *Config) AddMasks(paths []string) (bool, error) {
+	added := false
+	for _, path := range paths {
+		if _, err := ParseMask(path); err != nil {
+			return false, err
+		}
+		if !slices.Contains(c.Mask, path) {
+			c.Mask = append(c.Mask, path)
+			added = true
+		}
+	}
+	return added, nil
+}
+
+// Save writes the configuration as YAML
+func (c *Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot config: %w", err)
+	}
+	if err := writePrivate(path, data); err != nil {
+		return fmt.Errorf("failed to write snapshot config: %w", err)
+	}
+	return nil
+}
+
+func (c Call) name() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Tool
+}
+
+// Snapshot is a set of JSON files by their path relative to the snapshot
+// directory, with forward slashes
+type Snapshot struct {
+	Files map[string][]byte
+}
+
+// Names returns the snapshot's file names in order
+func (s *Snapshot) Names() []string {
+	names := make([]string, 0, len(s.Files))
+	for name := range s.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Capture captures a connected server: server.json holds its name,
+// protocol version and capabilities (not its version, which changes with
+// every release), tools.json, resources.json and prompts.json the lists the
+// server advertises, sorted by name or URI, and calls/<name>.json each
+// scripted call's result. Masked values are replaced with "<masked>".
+func Capture(ctx context.Context, service mcp.Service, cfg *Config) (*Snapshot, error) {
+	masks := make([]Mask, len(cfg.Mask))
+	for i, path := range cfg.Mask {
+		mask, err := ParseMask(path)
+		if err != nil {
+			return nil, err
+		}
+		masks[i] = mask
+	}
+
+	info := service.GetServerInfo()
+	capabilities := info.Capabilities
+	if capabilities == nil {
+		capabilities = map[string]interface{}{}
+	}
+	doc := map[string]any{
+		"server": map[string]any{
+			"name":            info.Name,
+			"protocolVersion": info.ProtocolVersion,
+			"capabilities":    capabilities,
+		},
+	}
+
+	if _, ok := capabilities["tools"]; ok {
+		tools, err := service.ListTools(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		sort.SliceStable(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+		doc["tools"] = nonNil(tools)
+	}
+	if _, ok := capabilities["resources"]; ok {
+		resources, err := service.ListResources(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list resources: %w", err)
+		}
+		sort.SliceStable(resources, func(i, j int) bool { return resources[i].URI < resources[j].URI })
+		doc["resources"] = nonNil(resources)
+	}
+	if _, ok := capabilities["prompts"]; ok {
+		prompts, err := service.ListPrompts(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list prompts: %w", err)
+		}
+		sort.SliceStable(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+		doc["prompts"] = nonNil(prompts)
+	}
+
+	if len(cfg.Calls) > 0 {
+		calls := make(map[string]any)
+		for _, call := range cfg.Calls {
+			result, err := service.CallTool(ctx, mcp.CallToolRequest{Name: call.Tool, Arguments: call.Arguments})
+			if err != nil {
+				return nil, fmt.Errorf("call %s: %w", call.name(), err)
+			}
+			calls[call.name()] = result
+		}
+		doc["calls"] = calls
+	}
+
+	value, err := normalize(doc)
+	if err != nil {
+		return nil, err
+	}
+	for _, mask := range masks {
+		value = mask.Apply(value)
+	}
+
+	snap := &Snapshot{Files: make(map[string][]byte)}
+	root := value.(map[string]any)
+	for key, part := range root {
+		if calls, ok := part.(map[string]any); ok && key == "calls" {
+			for name, result := range calls {
+				if snap.Files[callsDir+"/"+name+".json"], err = encode(result); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if snap.Files[key+".json"], err = encode(part); err != nil {
+			return nil, err
+		}
+	}
+	return snap, nil
+}
+
+// Load reads the snapshot files in a directory
+func Load(dir string) (*Snapshot, error) {
+	names, err := snapshotFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no snapshot in %s: save one first with snapshot save %s", dir, dir)
+	}
+	snap := &Snapshot{Files: make(map[string][]byte)}
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		snap.Files[name] = data
+	}
+	return snap, nil
+}
+
+// Save writes the snapshot to a directory, removing the snapshot files of
+// an earlier save that this one does not have
+func (s *Snapshot) Save(dir string) error {
+	if err := os.MkdirAll(filepath.Join(dir, callsDir), 0o700); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	existing, err := snapshotFiles(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range existing {
+		if _, ok := s.Files[name]; !ok {
+			if err := os.Remove(filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+				return fmt.Errorf("failed to remove stale snapshot file: %w", err)
+			}
+		}
+	}
+	for _, name := range s.Names() {
+		if err := writePrivate(filepath.Join(dir, filepath.FromSlash(name)), s.Files[name]); err != nil {
+			return fmt.Errorf("failed to write snapshot: %w", err)
+		}
+	}
+	// Leave no empty calls directory behind
+	if entries, err := os.ReadDir(filepath.Join(dir, callsDir)); err == nil && len(entries) == 0 {
+		os.Remove(filepath.Join(dir, callsDir))
+	}
+	return nil
+}
+
+// snapshotFiles lists the snapshot files in a directory: the JSON files at
+// its top and in its calls directory
+func snapshotFiles(dir string) ([]string, error) {
+	var names []string
+	for _, sub := range []string{"", callsDir} {
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+		}
+		for _, entry := range entries {
+			if entry.Type().IsRegular() && strings.HasSuffix(entry.Name(), ".json") {
+				names = append(names, strings.TrimPrefix(sub+"/"+entry.Name(), "/"))
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// normalize converts a value to its JSON form
+func normalize(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return out, nil
+}
+
+// encode formats a value as indented JSON with sorted keys and a final
+// newline, so that files diff well
+func encode(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(value); err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// nonNil makes an empty list encode as [] rather than null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// writePrivate writes a file readable only by its owner, since tool results
+// can hold anything the server returns. The mode of a file from an earlier
+// save is tightened too.
+func writePrivate(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0o600)
+}
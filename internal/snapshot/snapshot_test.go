@@ -0,0 +1,278 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/mock"
+)
+
+const testServer = `
+server:
+  name: snapshot-mock
+  version: 1.0.0
+tools:
+  - name: greet
+    description: Greets someone
+    inputSchema:
+      type: object
+      properties:
+        name: {type: string}
+      required: [name]
+    response:
+      text: "Hello, {{.args.name}}!"
+  - name: add
+    description: Adds two numbers
+    inputSchema:
+      type: object
+      properties:
+        a: {type: number}
+        b: {type: number}
+resources:
+  - uri: mock://readme
+    text: "read me"
+`
+
+// connect serves a mock server definition over HTTP and returns a service
+// connected to it
+func connect(t *testing.T, definition string) mcp.Service {
+	def, err := mock.ParseDefinition([]byte(definition))
+	require.NoError(t, err)
+	httpServer := httptest.NewServer(mock.NewServer(def).HTTPHandler())
+	t.Cleanup(httpServer.Close)
+
+	service := mcp.NewService()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, service.Connect(ctx, &config.ConnectionConfig{Type: config.TransportHTTP, URL: httpServer.URL}))
+	t.Cleanup(func() { service.Disconnect() })
+	return service
+}
+
+func capture(t *testing.T, definition string, cfg *Config) *Snapshot {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	snap, err := Capture(ctx, connect(t, definition), cfg)
+	require.NoError(t, err)
+	return snap
+}
+
+func TestCapture(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`
+calls:
+  - {tool: greet, arguments: {name: Ann}}
+mask:
+  - .calls.greet.content[0].text
+`))
+	require.NoError(t, err)
+
+	snap := capture(t, testServer, cfg)
+	assert.Equal(t, []string{"calls/greet.json", "resources.json", "server.json", "tools.json"}, snap.Names())
+	assert.Equal(t, `{
+  "capabilities": {
+    "logging": {},
+    "resources": {},
+    "tools": {}
+  },
+  "name": "snapshot-mock",
+  "protocolVersion": "2025-06-18"
+}
+`, string(snap.Files["server.json"]))
+	assert.Equal(t, `{
+  "content": [
+    {
+      "text": "<masked>",
+      "type": "text"
+    }
+  ]
+}
+`, string(snap.Files["calls/greet.json"]))
+	// Tools are sorted by name
+	assert.Regexp(t, `(?s)"name": "add".*"name": "greet"`, string(snap.Files["tools.json"]))
+}
+
+func TestSaveLoadAndCompare(t *testing.T) {
+	dir := t.TempDir()
+	saved := capture(t, testServer, &Config{})
+	require.NoError(t, saved.Save(dir))
+	// A file from an earlier save that this one lacks is removed
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "prompts.json"), []byte("[]\n"), 0o644))
+	require.NoError(t, saved.Save(dir))
+
+	loaded, err := Load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, saved.Files, loaded.Files)
+	assert.Empty(t, Compare(loaded, capture(t, testServer, &Config{})))
+
+	changed := `
+server:
+  name: snapshot-mock
+tools:
+  - name: greet
+    description: Greets a person
+    inputSchema:
+      type: object
+      properties:
+        name: {type: string}
+      required: [name]
+  - name: add
+    description: Adds two numbers
+    inputSchema:
+      type: object
+      properties:
+        a: {type: number}
+        b: {type: number}
+prompts:
+  - name: ask
+    messages:
+      - {role: user, text: hi}
+`
+	diffs := Compare(loaded, capture(t, changed, &Config{}))
+	require.Len(t, diffs, 4)
+	assert.Equal(t, FileDiff{File: "prompts.json", Change: Added}, FileDiff{File: diffs[0].File, Change: diffs[0].Change})
+	assert.Equal(t, Removed, diffs[1].Change)
+	assert.Equal(t, "resources.json", diffs[1].File)
+	assert.Equal(t, "server.json", diffs[2].File)
+	assert.Equal(t, "tools.json", diffs[3].File)
+	assert.Contains(t, diffs[3].Diff, "-    \"description\": \"Greets someone\",\n+    \"description\": \"Greets a person\",\n")
+}
+
+func TestSavePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not enforced on Windows")
+	}
+	dir := filepath.Join(t.TempDir(), "snapshot")
+	saved := capture(t, testServer, &Config{})
+	require.NoError(t, saved.Save(dir))
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+
+	// A file an earlier version wrote readable by others is tightened
+	require.NoError(t, os.Chmod(filepath.Join(dir, "tools.json"), 0o644))
+	require.NoError(t, saved.Save(dir))
+	for _, name := range saved.Names() {
+		info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name)))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm(), name)
+	}
+
+	configPath := filepath.Join(dir, "snapshot.yaml")
+	require.NoError(t, (&Config{}).Save(configPath))
+	info, err = os.Stat(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestLoadEmptyDirectory(t *testing.T) {
+	_, err := Load(t.TempDir())
+	assert.ErrorContains(t, err, "no snapshot in")
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		config string
+		err    string
+	}{
+		{"calls: [{arguments: {a: 1}}]", "calls[0]: tool is required"},
+		{"calls: [{tool: a}, {tool: a}]", `calls[1]: name "a" is used twice`},
+		{"calls: [{name: ../up, tool: a}]", `calls[0]: name "../up" must be`},
+		{"mask: ['.tools[x]']", `invalid mask ".tools[x]"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.config, func(t *testing.T) {
+			_, err := ParseConfig([]byte(tt.config))
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+
+	cfg, err := ParseConfig(nil)
+	require.NoError(t, err)
+	assert.Equal(t, &Config{}, cfg)
+}
+
+func TestMask(t *testing.T) {
+	value := func() any {
+		v, err := normalize(map[string]any{
+			"tools": []any{
+				map[string]any{"name": "a", "meta": map[string]any{"timestamp": 1, "id": "x"}},
+				map[string]any{"name": "b", "meta": map[string]any{"timestamp": 2}},
+			},
+			"calls": map[string]any{
+				"now":        map[string]any{"content": []any{map[string]any{"text": "12:00"}}},
+				"with space": map[string]any{"timestamp": 3},
+			},
+		})
+		require.NoError(t, err)
+		return v
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{".tools[].name", `{"calls":{"now":{"content":[{"text":"12:00"}]},"with space":{"timestamp":3}},"tools":[{"meta":{"id":"x","timestamp":1},"name":"<masked>"},{"meta":{"timestamp":2},"name":"<masked>"}]}`},
+		{".tools[-1].meta", `{"calls":{"now":{"content":[{"text":"12:00"}]},"with space":{"timestamp":3}},"tools":[{"meta":{"id":"x","timestamp":1},"name":"a"},{"meta":"<masked>","name":"b"}]}`},
+		{"..timestamp", `{"calls":{"now":{"content":[{"text":"12:00"}]},"with space":{"timestamp":"<masked>"}},"tools":[{"meta":{"id":"x","timestamp":"<masked>"},"name":"a"},{"meta":{"timestamp":"<masked>"},"name":"b"}]}`},
+		{`.calls[].content[0].text`, `{"calls":{"now":{"content":[{"text":"<masked>"}]},"with space":{"timestamp":3}},"tools":[{"meta":{"id":"x","timestamp":1},"name":"a"},{"meta":{"timestamp":2},"name":"b"}]}`},
+		{`.calls."with space"`, `{"calls":{"now":{"content":[{"text":"12:00"}]},"with space":"<masked>"},"tools":[{"meta":{"id":"x","timestamp":1},"name":"a"},{"meta":{"timestamp":2},"name":"b"}]}`},
+		{".missing.path[3]", `{"calls":{"now":{"content":[{"text":"12:00"}]},"with space":{"timestamp":3}},"tools":[{"meta":{"id":"x","timestamp":1},"name":"a"},{"meta":{"timestamp":2},"name":"b"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			mask, err := ParseMask(tt.path)
+			require.NoError(t, err)
+			got, err := encode(mask.Apply(value()))
+			require.NoError(t, err)
+			want, err := encode(mustDecode(t, tt.want))
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(got))
+		})
+	}
+
+	for _, path := range []string{"", ".", ".a[", `.a."b`, ".a | length", ".calls.weather-paris"} {
+		_, err := ParseMask(path)
+		assert.Error(t, err, path)
+	}
+}
+
+func mustDecode(t *testing.T, s string) any {
+	var v any
+	require.NoError(t, json.Unmarshal([]byte(s), &v))
+	return v
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	from := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n"
+	to := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\nn\n"
+	assert.Equal(t, `--- old
++++ new
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -11,3 +11,4 @@
+ k
+ l
+ m
++n
+`, UnifiedDiff("old", "new", from, to))
+
+	assert.Equal(t, "", UnifiedDiff("old", "new", from, from))
+	assert.Equal(t, "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+x\n+y\n", UnifiedDiff("old", "new", "", "x\ny\n"))
+	assert.Equal(t, "--- old\n+++ new\n@@ -1 +0,0 @@\n-x\n", UnifiedDiff("old", "new", "x\n", ""))
+}
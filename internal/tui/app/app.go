@@ -8,6 +8,8 @@ import (
 
 	"github.com/standardbeagle/mcp-tui/internal/config"
 	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/tui/screens"
+	"github.com/standardbeagle/mcp-tui/internal/watch"
 )
 
 // App represents the TUI application
@@ -15,6 +17,7 @@ type App struct {
 	config           *config.Config
 	connectionConfig *config.ConnectionConfig
 	logger           debug.Logger
+	initialScreen    screens.Screen // Starts here instead of connecting, if set
 }
 
 // New creates a new TUI application
@@ -26,12 +29,35 @@ func New(cfg *config.Config, connConfig *config.ConnectionConfig) *App {
 	}
 }
 
+// NewWithScreen creates a TUI application that starts on the given screen
+func NewWithScreen(cfg *config.Config, screen screens.Screen) *App {
+	return &App{
+		config:        cfg,
+		logger:        debug.Component("tui-app"),
+		initialScreen: screen,
+	}
+}
+
 // Run starts the TUI application
 func (a *App) Run(ctx context.Context) error {
 	a.logger.Info("Starting TUI application")
 
 	// Create screen manager to handle navigation
-	model := NewScreenManager(a.config, a.connectionConfig)
+	var model *ScreenManager
+	if a.initialScreen != nil {
+		model = NewScreenManagerWithScreen(a.config, a.initialScreen)
+	} else {
+		model = NewScreenManager(a.config, a.connectionConfig)
+	}
+
+	// Restart the server whenever the watched files change
+	if len(a.config.Watch) > 0 && a.initialScreen == nil {
+		watcher, err := watch.New(a.config.Watch)
+		if err != nil {
+			return err
+		}
+		model.SetWatch(watcher.Watch(ctx))
+	}
 
 	// Create program with context
 	program := tea.NewProgram(
@@ -46,6 +72,9 @@ func (a *App) Run(ctx context.Context) error {
 		return fmt.Errorf("TUI program failed: %w", err)
 	}
 
+	// Save the session a final time, so the next launch resumes from here
+	model.SaveSession()
+
 	// Check if the final model has any exit status
 	if exitModel, ok := finalModel.(interface{ ExitCode() int }); ok {
 		if code := exitModel.ExitCode(); code != 0 {
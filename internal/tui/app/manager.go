@@ -1,22 +1,34 @@
 package app
 
 import (
+	"errors"
+	"io/fs"
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/standardbeagle/mcp-tui/internal/config"
 	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/session"
 	"github.com/standardbeagle/mcp-tui/internal/tui/models"
 	"github.com/standardbeagle/mcp-tui/internal/tui/screens"
 )
 
-// ScreenManager manages screen transitions and navigation
+// ScreenManager manages screen transitions and navigation. Each connected
+// server has a tab of its own with its own screens.
 type ScreenManager struct {
 	config           *config.Config
 	connectionConfig *config.ConnectionConfig
 	logger           debug.Logger
 
-	currentScreen screens.Screen
-	screenStack   []screens.Screen
+	tabs          []*serverTab
+	active        int            // Index of the tab showing
+	nextTabID     int            // ID of the next tab opened
 	overlayScreen screens.Screen // Overlay screen that preserves underlying screen
+
+	width  int
+	height int
+
+	changes <-chan []string // Watched file changes, if watching
 }
 
 // NewScreenManager creates a new screen manager
@@ -25,13 +37,13 @@ func NewScreenManager(cfg *config.Config, connConfig *config.ConnectionConfig) *
 		config:           cfg,
 		connectionConfig: connConfig,
 		logger:           debug.Component("screen-manager"),
-		screenStack:      make([]screens.Screen, 0),
 	}
 
 	// Initialize the appropriate starting screen
+	var screen screens.Screen
 	if connConfig != nil {
 		// Quick connect mode - go directly to main screen
-		sm.currentScreen = screens.NewMainScreen(cfg, connConfig)
+		screen = screens.NewMainScreen(cfg, connConfig)
 	} else {
 		// Check for auto-connect scenarios
 		autoConnectConfig := sm.checkAutoConnect()
@@ -40,13 +52,74 @@ func NewScreenManager(cfg *config.Config, connConfig *config.ConnectionConfig) *
 				debug.F("transport", autoConnectConfig.Type),
 				debug.F("command", autoConnectConfig.Command),
 				debug.F("url", autoConnectConfig.URL))
-			sm.currentScreen = screens.NewMainScreen(cfg, autoConnectConfig)
+			screen = screens.NewMainScreen(cfg, autoConnectConfig)
 		} else {
 			// Interactive mode - start with connection screen
-			sm.currentScreen = screens.NewConnectionScreen(cfg)
+			screen = screens.NewConnectionScreen(cfg)
 		}
 	}
 
+	// Offer to resume the session saved by the previous run
+	if snapshot := sm.savedSession(); snapshot != nil {
+		screen = screens.NewResumeScreen(cfg, snapshot, screen)
+	}
+
+	sm.tabs = []*serverTab{sm.newTab(screen)}
+	return sm
+}
+
+// savedSession returns the session saved by the previous run if persistence
+// is enabled and it is for the server being connected to, if any
+func (sm *ScreenManager) savedSession() *session.Snapshot {
+	if !sm.config.PersistSession {
+		return nil
+	}
+
+	// Without a server to connect to, the session saved last is offered
+	path := session.SnapshotPath(sm.config.PersistFile)
+	load := session.LatestSnapshot
+	if sm.connectionConfig != nil {
+		path = session.ServerSnapshotPath(path, sm.connectionConfig)
+		load = session.LoadSnapshot
+	}
+	snapshot, err := load(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			sm.logger.Warn("Ignoring saved session", debug.F("path", path), debug.F("error", err))
+		}
+		return nil
+	}
+
+	if conn := sm.connectionConfig; conn != nil {
+		saved := snapshot.Connection
+		if conn.Type != saved.Type || conn.Command != saved.Command || conn.URL != saved.URL ||
+			strings.Join(conn.Args, "\x00") != strings.Join(saved.Args, "\x00") {
+			return nil
+		}
+	}
+	return snapshot
+}
+
+// SaveSession saves the session of every tab's server, if persistence is
+// enabled. The tab showing is saved last, so its session is offered next.
+func (sm *ScreenManager) SaveSession() {
+	for i, tab := range sm.tabs {
+		if main := tab.main(); main != nil && i != sm.active {
+			main.SaveSession()
+		}
+	}
+	if main := sm.mainScreen(); main != nil {
+		main.SaveSession()
+	}
+}
+
+// NewScreenManagerWithScreen creates a screen manager that starts on screen
+func NewScreenManagerWithScreen(cfg *config.Config, screen screens.Screen) *ScreenManager {
+	sm := &ScreenManager{
+		config: cfg,
+		logger: debug.Component("screen-manager"),
+	}
+	sm.tabs = []*serverTab{sm.newTab(screen)}
 	return sm
 }
 
@@ -79,36 +152,144 @@ func (sm *ScreenManager) checkAutoConnect() *config.ConnectionConfig {
 	return entry.ToConnectionConfig()
 }
 
+// SetWatch restarts the server of the first tab whenever changes delivers
+// the paths of changed files
+func (sm *ScreenManager) SetWatch(changes <-chan []string) {
+	sm.changes = changes
+}
+
 // Init initializes the screen manager
 func (sm *ScreenManager) Init() tea.Cmd {
 	// Request initial window size and initialize current screen
+	tab := sm.activeTab()
 	return tea.Batch(
 		tea.WindowSize(),
-		sm.currentScreen.Init(),
+		wrapTabCmd(tab.id, tab.current.Init()),
+		sm.waitForChange(),
 	)
 }
 
+// waitForChange returns a command that delivers the next watched file change
+func (sm *ScreenManager) waitForChange() tea.Cmd {
+	if sm.changes == nil {
+		return nil
+	}
+	changes := sm.changes
+	return func() tea.Msg {
+		paths, ok := <-changes
+		if !ok {
+			return nil
+		}
+		return screens.ServerChangedMsg{Paths: paths}
+	}
+}
+
+// mainScreen returns the main screen of the tab showing
+func (sm *ScreenManager) mainScreen() *screens.MainScreen {
+	return sm.activeTab().main()
+}
+
+// updateMainScreen delivers a message to the tab's main screen, which owns
+// the connection, whether or not it is showing
+func (sm *ScreenManager) updateMainScreen(tab *serverTab, msg tea.Msg) tea.Cmd {
+	main := tab.main()
+	if main == nil {
+		return nil
+	}
+	_, cmd := main.Update(msg)
+	return cmd
+}
+
+// handleServerRestart delivers a watch restart message to the tab's main
+// screen and to the screen showing if that is another one, so a tool form
+// can follow changes to its tool
+func (sm *ScreenManager) handleServerRestart(tab *serverTab, msg tea.Msg) tea.Cmd {
+	cmds := []tea.Cmd{sm.updateMainScreen(tab, msg)}
+	if main := tab.main(); tab.current != screens.Screen(main) {
+		model, cmd := tab.current.Update(msg)
+		if newScreen, ok := model.(screens.Screen); ok {
+			tab.current = newScreen
+		}
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
 // Update handles messages and screen transitions
 func (sm *ScreenManager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Results of a tab's commands go back to that tab, showing or not
+	if msg, ok := msg.(tabMsg); ok {
+		tab := sm.findTab(msg.tab)
+		if tab == nil {
+			return sm, nil // The tab was closed
+		}
+		return sm, sm.updateTab(tab, msg.msg)
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		sm.width, sm.height = msg.Width, msg.Height
+		return sm, sm.resize()
+
+	case tea.KeyMsg:
+		if sm.overlayScreen == nil {
+			if cmd, ok := sm.handleTabKey(msg); ok {
+				return sm, cmd
+			}
+		}
+
+	case screens.ServerChangedMsg:
+		// Watch mode follows the server mcp-tui was started with
+		tab := sm.findTab(0)
+		if tab == nil || tab.main() == nil {
+			// Nothing is connected yet, e.g. on the connection screen
+			return sm, sm.waitForChange()
+		}
+		return sm, tea.Batch(wrapTabCmd(tab.id, sm.handleServerRestart(tab, msg)), sm.waitForChange())
+	}
+
+	return sm, sm.updateTab(sm.activeTab(), msg)
+}
+
+// updateTab handles a message for the screens of tab
+func (sm *ScreenManager) updateTab(tab *serverTab, msg tea.Msg) tea.Cmd {
+	return wrapTabCmd(tab.id, sm.routeTab(tab, msg))
+}
+
+// routeTab delivers a message to the screens of tab, carrying out the
+// screen transitions it asks for
+func (sm *ScreenManager) routeTab(tab *serverTab, msg tea.Msg) tea.Cmd {
+	// Connection changes reach the main screen even under an overlay
+	switch msg := msg.(type) {
+	case screens.ConnectionStatusMsg:
+		return sm.updateMainScreen(tab, msg)
+	case screens.ServerRestartedMsg:
+		return sm.handleServerRestart(tab, msg)
+	case screens.OpenServerMsg:
+		return sm.openServer(tab, msg.Screen)
+	case screens.CatalogOpenMsg:
+		return sm.openCatalogItem(msg)
+	}
+
 	// If we have an overlay screen, route messages to it first
-	if sm.overlayScreen != nil {
+	if sm.overlayScreen != nil && tab == sm.activeTab() {
 		switch msg := msg.(type) {
 		case screens.BackMsg:
 			// For overlay screens, back means close the overlay
 			sm.overlayScreen = nil
 			sm.logger.Info("Closing overlay screen")
-			return sm, nil
+			return nil
 
 		case screens.ToggleOverlayMsg:
 			// Toggle off the overlay if it's the same screen
 			if msg.Screen != nil && sm.overlayScreen.Name() == msg.Screen.Name() {
 				sm.overlayScreen = nil
 				sm.logger.Info("Toggling off overlay screen")
-				return sm, nil
+				return nil
 			}
 			// Otherwise, replace with new overlay
 			sm.overlayScreen = msg.Screen
-			return sm, sm.overlayScreen.Init()
+			return sm.overlayScreen.Init()
 
 		default:
 			// Forward to overlay screen
@@ -116,18 +297,8 @@ func (sm *ScreenManager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if newScreen, ok := model.(screens.Screen); ok {
 				sm.overlayScreen = newScreen
 			}
-			return sm, cmd
-		}
-	}
-
-	// Handle window size messages for all screens
-	if wsMsg, ok := msg.(tea.WindowSizeMsg); ok {
-		// Update current screen size
-		model, cmd := sm.currentScreen.Update(wsMsg)
-		if newScreen, ok := model.(screens.Screen); ok {
-			sm.currentScreen = newScreen
+			return cmd
 		}
-		return sm, cmd
 	}
 
 	// Handle messages for main screen flow
@@ -137,73 +308,73 @@ func (sm *ScreenManager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.Transition.Screen.IsOverlay() {
 			sm.overlayScreen = msg.Transition.Screen
 			sm.logger.Info("Opening overlay screen", debug.F("overlay", msg.Transition.Screen.Name()))
-			return sm, sm.overlayScreen.Init()
+			return tea.Batch(sm.sizeScreen(sm.overlayScreen), sm.overlayScreen.Init())
 		}
 
 		// Normal screen transition
 		// Push current screen to stack if it can go back
-		if sm.currentScreen.CanGoBack() {
-			sm.screenStack = append(sm.screenStack, sm.currentScreen)
+		if tab.current.CanGoBack() {
+			tab.stack = append(tab.stack, tab.current)
 		}
 
 		// Transition to new screen
-		sm.currentScreen = msg.Transition.Screen
+		tab.current = msg.Transition.Screen
 		sm.logger.Info("Screen transition",
-			debug.F("from", sm.getCurrentScreenName()),
+			debug.F("from", tab.getCurrentScreenName()),
 			debug.F("to", msg.Transition.Screen.Name()))
 
-		return sm, sm.currentScreen.Init()
+		return tab.current.Init()
 
 	case screens.ToggleOverlayMsg:
 		// Toggle on the overlay
 		if msg.Screen != nil {
 			sm.overlayScreen = msg.Screen
 			sm.logger.Info("Toggling on overlay screen", debug.F("overlay", msg.Screen.Name()))
-			return sm, sm.overlayScreen.Init()
+			return tea.Batch(sm.sizeScreen(sm.overlayScreen), sm.overlayScreen.Init())
 		}
-		return sm, nil
+		return nil
 
 	case screens.BackMsg:
 		// Go back to previous screen if available
-		if len(sm.screenStack) > 0 {
+		if len(tab.stack) > 0 {
 			// Pop from stack
-			previousScreen := sm.screenStack[len(sm.screenStack)-1]
-			sm.screenStack = sm.screenStack[:len(sm.screenStack)-1]
+			previousScreen := tab.stack[len(tab.stack)-1]
+			tab.stack = tab.stack[:len(tab.stack)-1]
 
 			sm.logger.Info("Going back",
-				debug.F("from", sm.currentScreen.Name()),
+				debug.F("from", tab.current.Name()),
 				debug.F("to", previousScreen.Name()))
 
-			sm.currentScreen = previousScreen
-			return sm, nil
+			tab.current = previousScreen
+			return nil
 		}
 
-		// No previous screen, handle as quit
-		return sm, tea.Quit
+		// No previous screen: close the tab, or quit with the last one
+		if len(sm.tabs) > 1 {
+			return sm.closeTab(tab)
+		}
+		return tea.Quit
 
 	default:
 		// Forward message to current screen
-		model, cmd := sm.currentScreen.Update(msg)
+		model, cmd := tab.current.Update(msg)
 		if newScreen, ok := model.(screens.Screen); ok {
-			sm.currentScreen = newScreen
+			tab.current = newScreen
 		}
-		return sm, cmd
+		return cmd
 	}
 }
 
-// View renders the current screen
+// View renders the current screen, below the server tabs when more than one
+// server is open
 func (sm *ScreenManager) View() string {
+	view := sm.activeTab().current.View()
 	// If we have an overlay screen, render it instead
 	if sm.overlayScreen != nil {
-		return sm.overlayScreen.View()
+		view = sm.overlayScreen.View()
 	}
-	return sm.currentScreen.View()
-}
-
-// getCurrentScreenName returns the name of the current screen for logging
-func (sm *ScreenManager) getCurrentScreenName() string {
-	if len(sm.screenStack) > 0 {
-		return sm.screenStack[len(sm.screenStack)-1].Name()
+	if len(sm.tabs) > 1 {
+		return sm.renderTabs() + "\n" + view
 	}
-	return "none"
+	return view
 }
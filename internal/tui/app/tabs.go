@@ -0,0 +1,326 @@
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/tui/screens"
+)
+
+// serverTab is a server open in a tab of its own, with the screens opened
+// from its main screen. A tab opened for a new connection starts on the
+// connection screen.
+type serverTab struct {
+	id      int
+	current screens.Screen
+	stack   []screens.Screen
+}
+
+// main returns the tab's main screen, whether it is showing or further back
+func (t *serverTab) main() *screens.MainScreen {
+	if main, ok := t.current.(*screens.MainScreen); ok {
+		return main
+	}
+	for i := len(t.stack) - 1; i >= 0; i-- {
+		if main, ok := t.stack[i].(*screens.MainScreen); ok {
+			return main
+		}
+	}
+	return nil
+}
+
+// getCurrentScreenName returns the name of the current screen for logging
+func (t *serverTab) getCurrentScreenName() string {
+	if len(t.stack) > 0 {
+		return t.stack[len(t.stack)-1].Name()
+	}
+	return "none"
+}
+
+// tabMsg carries the result of a command of a tab's screens, so it reaches
+// that tab even when another one is showing
+type tabMsg struct {
+	tab int
+	msg tea.Msg
+}
+
+// teaPackage is the package of Bubble Tea's own messages, which are for the
+// program rather than a tab
+var teaPackage = reflect.TypeOf(tea.QuitMsg{}).PkgPath()
+
+// wrapTabCmd returns a command delivering the messages of cmd to the tab
+// with the given ID
+func wrapTabCmd(tab int, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case nil:
+			return nil
+		case tabMsg:
+			// Already bound for a tab, e.g. one the command opened
+			return msg
+		case tea.BatchMsg:
+			cmds := make(tea.BatchMsg, len(msg))
+			for i, cmd := range msg {
+				cmds[i] = wrapTabCmd(tab, cmd)
+			}
+			return cmds
+		default:
+			if reflect.TypeOf(msg).PkgPath() == teaPackage {
+				return msg
+			}
+			return tabMsg{tab: tab, msg: msg}
+		}
+	}
+}
+
+// newTab creates a tab showing screen
+func (sm *ScreenManager) newTab(screen screens.Screen) *serverTab {
+	tab := &serverTab{id: sm.nextTabID, current: screen}
+	sm.nextTabID++
+	return tab
+}
+
+// activeTab returns the tab showing
+func (sm *ScreenManager) activeTab() *serverTab {
+	return sm.tabs[sm.active]
+}
+
+// findTab returns the tab with the given ID, or nil once it is closed
+func (sm *ScreenManager) findTab(id int) *serverTab {
+	for _, tab := range sm.tabs {
+		if tab.id == id {
+			return tab
+		}
+	}
+	return nil
+}
+
+// screenSize returns the size left to screens below the server tabs
+func (sm *ScreenManager) screenSize() tea.WindowSizeMsg {
+	size := tea.WindowSizeMsg{Width: sm.width, Height: sm.height}
+	if len(sm.tabs) > 1 {
+		size.Height--
+	}
+	return size
+}
+
+// sizeScreen tells a screen the size it has, once the window size is known
+func (sm *ScreenManager) sizeScreen(screen screens.Screen) tea.Cmd {
+	if sm.width == 0 && sm.height == 0 {
+		return nil
+	}
+	_, cmd := screen.Update(sm.screenSize())
+	return cmd
+}
+
+// resize tells the screens of every tab the size they have
+func (sm *ScreenManager) resize() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, tab := range sm.tabs {
+		cmds = append(cmds, wrapTabCmd(tab.id, sm.sizeScreen(tab.current)))
+	}
+	if sm.overlayScreen != nil {
+		cmds = append(cmds, wrapTabCmd(sm.activeTab().id, sm.sizeScreen(sm.overlayScreen)))
+	}
+	return tea.Batch(cmds...)
+}
+
+// handleTabKey switches, opens and closes server tabs. It reports whether
+// the key was one of the tab keys.
+func (sm *ScreenManager) handleTabKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	key := msg.String()
+	switch key {
+	case "ctrl+t":
+		return sm.openTab(screens.NewConnectionScreen(sm.config)), true
+	case "alt+a":
+		return sm.openCatalog(), true
+	case "alt+w":
+		if len(sm.tabs) == 1 {
+			return nil, true
+		}
+		return sm.closeTab(sm.activeTab()), true
+	case "ctrl+pgdown":
+		sm.active = (sm.active + 1) % len(sm.tabs)
+		return nil, true
+	case "ctrl+pgup":
+		sm.active = (sm.active + len(sm.tabs) - 1) % len(sm.tabs)
+		return nil, true
+	}
+
+	if len(key) == len("alt+1") && strings.HasPrefix(key, "alt+") && key[4] >= '1' && key[4] <= '9' {
+		if n := int(key[4] - '1'); n < len(sm.tabs) {
+			sm.active = n
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+// openTab opens screen in a new tab after the one showing, and shows it
+func (sm *ScreenManager) openTab(screen screens.Screen) tea.Cmd {
+	tab := sm.newTab(screen)
+	sm.active++
+	sm.tabs = append(sm.tabs[:sm.active], append([]*serverTab{tab}, sm.tabs[sm.active:]...)...)
+	sm.logger.Info("Opened server tab", debug.F("tab", tab.id), debug.F("screen", screen.Name()))
+
+	// The other tabs lose a line to the tab strip when it first appears
+	var resize tea.Cmd
+	if len(sm.tabs) == 2 {
+		resize = sm.resize()
+	} else {
+		resize = wrapTabCmd(tab.id, sm.sizeScreen(screen))
+	}
+	return tea.Batch(resize, wrapTabCmd(tab.id, screen.Init()))
+}
+
+// openServer shows a server chosen on the connection screen of tab. The
+// server replaces the connection screen unless the tab still has a live
+// connection, in which case it opens in a new tab.
+func (sm *ScreenManager) openServer(tab *serverTab, screen *screens.MainScreen) tea.Cmd {
+	if main := tab.main(); main != nil {
+		switch main.ServerState() {
+		case screens.ServerDisconnected, screens.ServerFailed:
+			main.Close()
+		default:
+			return sm.openTab(screen)
+		}
+	}
+
+	tab.current = screen
+	tab.stack = nil
+	sm.logger.Info("Opened server", debug.F("tab", tab.id), debug.F("server", screen.ServerName()))
+	return tea.Batch(sm.sizeScreen(screen), screen.Init())
+}
+
+// closeTab disconnects the server of a tab and closes it
+func (sm *ScreenManager) closeTab(tab *serverTab) tea.Cmd {
+	if main := tab.main(); main != nil {
+		main.Close()
+	}
+
+	for i, t := range sm.tabs {
+		if t != tab {
+			continue
+		}
+		sm.tabs = append(sm.tabs[:i], sm.tabs[i+1:]...)
+		if sm.active > i || sm.active == len(sm.tabs) {
+			sm.active--
+		}
+		break
+	}
+	sm.logger.Info("Closed server tab", debug.F("tab", tab.id))
+
+	// The tab strip goes away with the second last tab
+	if len(sm.tabs) == 1 {
+		return sm.resize()
+	}
+	return nil
+}
+
+// openCatalog shows the aggregated catalog of the connected servers
+func (sm *ScreenManager) openCatalog() tea.Cmd {
+	var servers []screens.CatalogServer
+	for _, tab := range sm.tabs {
+		if main := tab.main(); main != nil && main.ServerState() == screens.ServerConnected {
+			// Servers are known to hosts by the name they report
+			name := main.Service().GetServerInfo().Name
+			if name == "" {
+				name = main.ServerName()
+			}
+			servers = append(servers, screens.CatalogServer{Tab: tab.id, Name: name, Service: main.Service()})
+		}
+	}
+	if len(servers) == 0 {
+		return nil
+	}
+
+	sm.overlayScreen = screens.NewCatalogScreen(servers)
+	sm.logger.Info("Opening catalog", debug.F("servers", len(servers)))
+	return wrapTabCmd(sm.activeTab().id, tea.Batch(sm.sizeScreen(sm.overlayScreen), sm.overlayScreen.Init()))
+}
+
+// openCatalogItem shows an item chosen in the catalog in its server's tab
+func (sm *ScreenManager) openCatalogItem(msg screens.CatalogOpenMsg) tea.Cmd {
+	sm.overlayScreen = nil
+	tab := sm.findTab(msg.Tab)
+	if tab == nil {
+		return nil // The tab was closed
+	}
+	for i, t := range sm.tabs {
+		if t == tab {
+			sm.active = i
+		}
+	}
+
+	if msg.Screen != nil {
+		return sm.updateTab(tab, screens.TransitionMsg{Transition: screens.ScreenTransition{Screen: msg.Screen}})
+	}
+
+	// Bring the main screen back to the front with the item selected
+	main := tab.main()
+	if main == nil {
+		return nil
+	}
+	for tab.current != screens.Screen(main) && len(tab.stack) > 0 {
+		tab.current = tab.stack[len(tab.stack)-1]
+		tab.stack = tab.stack[:len(tab.stack)-1]
+	}
+	main.SelectItem(msg.Kind, msg.Name)
+	return nil
+}
+
+var (
+	tabStyle       = lipgloss.NewStyle().Padding(0, 1).Foreground(lipgloss.Color("8"))
+	activeTabStyle = lipgloss.NewStyle().Padding(0, 1).Foreground(lipgloss.Color("15")).Background(lipgloss.Color("4")).Bold(true)
+	tabHelpStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	// serverIndicators show the state of each tab's server
+	serverIndicators = map[screens.ServerState]string{
+		screens.ServerConnecting:   lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Render("◌"),
+		screens.ServerConnected:    lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Render("●"),
+		screens.ServerReconnecting: lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Render("↻"),
+		screens.ServerDisconnected: lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render("○"),
+		screens.ServerFailed:       lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Render("✗"),
+	}
+)
+
+// renderTabs renders the strip of server tabs, each with the state of its
+// connection
+func (sm *ScreenManager) renderTabs() string {
+	maxLabel := 24
+	if sm.width > 0 {
+		maxLabel = max(8, sm.width/len(sm.tabs)-8)
+	}
+
+	tabs := make([]string, len(sm.tabs))
+	for i, tab := range sm.tabs {
+		indicator, label := " ", tab.current.Name()
+		if main := tab.main(); main != nil {
+			indicator, label = serverIndicators[main.ServerState()], main.ServerName()
+		}
+		if runes := []rune(label); len(runes) > maxLabel {
+			label = string(runes[:maxLabel-1]) + "…"
+		}
+
+		style := tabStyle
+		if i == sm.active {
+			style = activeTabStyle
+		}
+		tabs[i] = indicator + style.Render(fmt.Sprintf("%d %s", i+1, label))
+	}
+
+	strip := strings.Join(tabs, " ")
+	help := tabHelpStyle.Render("  Alt+1-9: Switch • Ctrl+T: New • Alt+W: Close • Alt+A: All")
+	if sm.width == 0 || lipgloss.Width(strip)+lipgloss.Width(help) <= sm.width {
+		strip += help
+	}
+	return strip
+}
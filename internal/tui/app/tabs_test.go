@@ -0,0 +1,119 @@
+package app
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/tui/screens"
+)
+
+// recordingScreen records the messages it receives
+type recordingScreen struct {
+	*screens.BaseScreen
+	msgs []tea.Msg
+}
+
+func newRecordingScreen(name string) *recordingScreen {
+	return &recordingScreen{BaseScreen: screens.NewBaseScreen(name, false)}
+}
+
+func (s *recordingScreen) Init() tea.Cmd { return nil }
+
+func (s *recordingScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	s.msgs = append(s.msgs, msg)
+	return s, nil
+}
+
+func (s *recordingScreen) View() string { return s.Name() }
+
+type pingMsg struct{}
+
+func TestWrapTabCmd(t *testing.T) {
+	cmd := wrapTabCmd(3, tea.Batch(
+		func() tea.Msg { return pingMsg{} },
+		tea.Quit,
+	))
+
+	batch, ok := cmd().(tea.BatchMsg)
+	require.True(t, ok)
+	require.Len(t, batch, 2)
+	assert.Equal(t, tabMsg{tab: 3, msg: pingMsg{}}, batch[0]())
+	assert.Equal(t, tea.QuitMsg{}, batch[1](), "Bubble Tea's own messages are for the program")
+	assert.Nil(t, wrapTabCmd(3, nil))
+
+	nested := wrapTabCmd(1, wrapTabCmd(2, func() tea.Msg { return pingMsg{} }))
+	assert.Equal(t, tabMsg{tab: 2, msg: pingMsg{}}, nested(), "Messages keep the tab they are bound for")
+}
+
+func TestTabMessagesReachTheirTab(t *testing.T) {
+	first, second := newRecordingScreen("First"), newRecordingScreen("Second")
+	sm := NewScreenManagerWithScreen(&config.Config{}, first)
+	sm.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	sm.openTab(second)
+	require.Len(t, sm.tabs, 2)
+	assert.Equal(t, 1, sm.active, "The new tab shows")
+	assert.Equal(t, tea.WindowSizeMsg{Width: 100, Height: 39}, first.msgs[len(first.msgs)-1],
+		"The tab strip takes a line")
+
+	// A result for the first tab reaches it while the second shows
+	sm.Update(tabMsg{tab: 0, msg: pingMsg{}})
+	assert.Contains(t, first.msgs, tea.Msg(pingMsg{}))
+	assert.NotContains(t, second.msgs, tea.Msg(pingMsg{}))
+
+	// Other messages go to the tab showing
+	sm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	assert.IsType(t, tea.KeyMsg{}, second.msgs[len(second.msgs)-1])
+
+	assert.Contains(t, sm.View(), "1 First")
+	assert.Contains(t, sm.View(), "2 Second")
+
+	sm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("1"), Alt: true})
+	assert.Equal(t, 0, sm.active)
+
+	sm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w"), Alt: true})
+	require.Len(t, sm.tabs, 1)
+	assert.Equal(t, second, sm.activeTab().current)
+	assert.Equal(t, "Second", sm.View(), "The tab strip goes with the second tab")
+
+	// Results for a closed tab are dropped
+	sm.Update(tabMsg{tab: 0, msg: pingMsg{}})
+}
+
+func TestOpenServer(t *testing.T) {
+	cfg := &config.Config{}
+	picker := newRecordingScreen("Connection")
+	sm := NewScreenManagerWithScreen(cfg, picker)
+	conn := &config.ConnectionConfig{Type: config.TransportStdio, Command: "server"}
+
+	// A server chosen on a tab without a connection takes the tab
+	first := screens.NewMainScreen(cfg, conn)
+	sm.Update(tabMsg{tab: 0, msg: screens.OpenServerMsg{Screen: first}})
+	require.Len(t, sm.tabs, 1)
+	assert.Equal(t, screens.Screen(first), sm.activeTab().current)
+
+	// A server chosen on a tab with a live connection opens in a new tab
+	first.Update(screens.ConnectionStartedMsg{})
+	require.Equal(t, screens.ServerConnecting, first.ServerState())
+	second := screens.NewMainScreen(cfg, conn)
+	sm.Update(tabMsg{tab: 0, msg: screens.OpenServerMsg{Screen: second}})
+	require.Len(t, sm.tabs, 2)
+	assert.Equal(t, screens.Screen(first), sm.tabs[0].current)
+	assert.Equal(t, screens.Screen(second), sm.activeTab().current)
+}
+
+func TestCatalogOpensInServerTab(t *testing.T) {
+	first := newRecordingScreen("First")
+	sm := NewScreenManagerWithScreen(&config.Config{}, first)
+	sm.openTab(newRecordingScreen("Second"))
+	sm.overlayScreen = screens.NewCatalogScreen(nil)
+
+	tool := newRecordingScreen("Tool")
+	sm.Update(tabMsg{tab: 1, msg: screens.CatalogOpenMsg{Tab: 0, Name: "echo", Screen: tool}})
+	assert.Nil(t, sm.overlayScreen, "The catalog closes")
+	assert.Equal(t, 0, sm.active, "The item's server shows")
+	assert.Equal(t, screens.Screen(tool), sm.tabs[0].current)
+}
@@ -27,6 +27,9 @@ type ConnectionEntry struct {
 	LastUsed    *time.Time           `json:"lastUsed,omitempty"`
 	Success     bool                 `json:"success"`
 	Tags        []string             `json:"tags,omitempty"`
+
+	// NegotiatedTransport records which transport an "auto" connection settled on
+	NegotiatedTransport config.TransportType `json:"negotiatedTransport,omitempty"`
 }
 
 // ConnectionsConfig represents the saved connections configuration file
@@ -231,6 +234,14 @@ func (cm *ConnectionsManager) loadVSCodeFormat(data []byte) bool {
 			entry.Transport = config.TransportSSE
 		case "http":
 			entry.Transport = config.TransportHTTP
+		case "ws", "websocket":
+			entry.Transport = config.TransportWebSocket
+		case "auto":
+			entry.Transport = config.TransportAuto
+		case "unix":
+			entry.Transport = config.TransportUnix
+		case "tcp":
+			entry.Transport = config.TransportTCP
 		default:
 			entry.Transport = config.TransportStdio
 		}
@@ -379,6 +390,21 @@ func (cm *ConnectionsManager) UpdateLastUsed(serverID string, success bool) {
 	}
 }
 
+// RecordNegotiatedTransport remembers the transport an auto connection negotiated so
+// later connections skip the probe. An empty transport clears the remembered choice.
+func (cm *ConnectionsManager) RecordNegotiatedTransport(serverID string, transport config.TransportType) {
+	entry, exists := cm.config.Servers[serverID]
+	if !exists || entry.Transport != config.TransportAuto || entry.NegotiatedTransport == transport {
+		return
+	}
+
+	cm.logger.Info("Recording negotiated transport",
+		debug.F("serverID", serverID),
+		debug.F("transport", transport))
+	entry.NegotiatedTransport = transport
+	cm.SaveConnections()
+}
+
 // updateRecentConnections updates the recent connections list
 func (cm *ConnectionsManager) updateRecentConnections(serverID string, success bool) {
 	if cm.config.RecentConnections == nil {
@@ -412,11 +438,16 @@ func (cm *ConnectionsManager) updateRecentConnections(serverID string, success b
 
 // ToConnectionConfig converts a ConnectionEntry to config.ConnectionConfig
 func (entry *ConnectionEntry) ToConnectionConfig() *config.ConnectionConfig {
+	transport := entry.Transport
+	if transport == config.TransportAuto && entry.NegotiatedTransport != "" {
+		transport = entry.NegotiatedTransport
+	}
 	return &config.ConnectionConfig{
-		Type:    entry.Transport,
+		Type:    transport,
 		Command: entry.Command,
 		Args:    entry.Args,
 		URL:     entry.URL,
+		Headers: entry.Headers,
 	}
 }
 
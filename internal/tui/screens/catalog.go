@@ -0,0 +1,539 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+)
+
+const (
+	// catalogSeparator joins a server's namespace and an item name, as hosts
+	// namespace tools from several servers
+	catalogSeparator = "__"
+
+	// similarDescriptions is how much two descriptions must share, as the
+	// Jaccard index of their words, to be flagged as overlapping
+	similarDescriptions = 0.5
+)
+
+// catalogKinds are the kinds of items in the catalog, in the order of the
+// main screen's tabs
+var catalogKinds = []string{"Tools", "Resources", "Prompts"}
+
+// CatalogServer is a connected server whose items the catalog merges
+type CatalogServer struct {
+	Tab     int // ID of the server's tab
+	Name    string
+	Service mcp.Service
+}
+
+// CatalogItem is a tool, resource or prompt of one server in the catalog
+type CatalogItem struct {
+	Kind        int    // 0=tools, 1=resources, 2=prompts
+	Server      int    // Index of the server
+	Name        string // Name on the server; the URI of a resource
+	Namespaced  string // Name namespaced by the server, unique in the catalog
+	Description string
+	Tool        *mcp.Tool
+
+	Duplicates []string // Items of other servers with the same name
+	Similar    []string // Items of other servers with overlapping descriptions
+}
+
+// catalogList is what one server lists, or the error listing it
+type catalogList struct {
+	Tools     []mcp.Tool
+	Resources []mcp.Resource
+	Prompts   []mcp.Prompt
+	Err       error
+}
+
+// catalogNamespaces derives a namespace from each server name, made unique
+// by numbering repeats
+func catalogNamespaces(names []string) []string {
+	namespaces := make([]string, len(names))
+	seen := make(map[string]int)
+	for i, name := range names {
+		ns := strings.Map(func(r rune) rune {
+			switch {
+			case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-':
+				return unicode.ToLower(r)
+			default:
+				return '_'
+			}
+		}, name)
+		ns = strings.Trim(ns, "_")
+		if ns == "" {
+			ns = "server"
+		}
+		seen[ns]++
+		if n := seen[ns]; n > 1 {
+			ns = fmt.Sprintf("%s-%d", ns, n)
+		}
+		namespaces[i] = ns
+	}
+	return namespaces
+}
+
+// buildCatalog merges the lists of servers, namespacing each item by its
+// server and flagging names and descriptions that collide across servers
+func buildCatalog(namespaces []string, lists []catalogList) []CatalogItem {
+	var items []CatalogItem
+	add := func(kind, server int, name, description string, tool *mcp.Tool) {
+		items = append(items, CatalogItem{
+			Kind:        kind,
+			Server:      server,
+			Name:        name,
+			Namespaced:  namespaces[server] + catalogSeparator + name,
+			Description: description,
+			Tool:        tool,
+		})
+	}
+	for server, list := range lists {
+		for i := range list.Tools {
+			add(0, server, list.Tools[i].Name, list.Tools[i].Description, &list.Tools[i])
+		}
+		for _, resource := range list.Resources {
+			add(1, server, resource.URI, resource.Description, nil)
+		}
+		for _, prompt := range list.Prompts {
+			add(2, server, prompt.Name, prompt.Description, nil)
+		}
+	}
+
+	words := make([]map[string]bool, len(items))
+	for i, item := range items {
+		words[i] = descriptionWords(item.Description)
+	}
+	for i := range items {
+		for j := range items {
+			a, b := &items[i], items[j]
+			if a.Kind != b.Kind || a.Server == b.Server {
+				continue
+			}
+			if a.Name == b.Name {
+				a.Duplicates = append(a.Duplicates, b.Namespaced)
+			} else if jaccard(words[i], words[j]) >= similarDescriptions {
+				a.Similar = append(a.Similar, b.Namespaced)
+			}
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Kind != items[j].Kind {
+			return items[i].Kind < items[j].Kind
+		}
+		return items[i].Name < items[j].Name
+	})
+	return items
+}
+
+// descriptionWords returns the words of a description worth comparing
+func descriptionWords(description string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) > 2 {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+// jaccard returns how much two sets of words overlap, from 0 to 1. Short
+// descriptions are not compared since a couple of words say little.
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) < 3 || len(b) < 3 {
+		return 0
+	}
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// catalogLoadedMsg carries the lists of every server
+type catalogLoadedMsg struct {
+	Lists []catalogList
+}
+
+// CatalogOpenMsg asks for an item of the catalog to be shown in its
+// server's tab: Screen is opened there if set, otherwise the item is
+// selected on the main screen
+type CatalogOpenMsg struct {
+	Tab    int
+	Kind   int
+	Name   string
+	Screen Screen
+}
+
+// CatalogScreen merges the tools, resources and prompts of every connected
+// server into one list, the way hosts present them to a model
+type CatalogScreen struct {
+	*BaseScreen
+	logger debug.Logger
+
+	servers    []CatalogServer
+	namespaces []string
+	lists      []catalogList
+	items      []CatalogItem
+	loading    bool
+
+	kind      int
+	selected  int
+	scroll    int
+	filter    string
+	searching bool
+
+	titleStyle     lipgloss.Style
+	tabStyle       lipgloss.Style
+	activeTabStyle lipgloss.Style
+	selectedStyle  lipgloss.Style
+	warnStyle      lipgloss.Style
+	dimStyle       lipgloss.Style
+	detailStyle    lipgloss.Style
+}
+
+// NewCatalogScreen creates a catalog of the given servers
+func NewCatalogScreen(servers []CatalogServer) *CatalogScreen {
+	names := make([]string, len(servers))
+	for i, server := range servers {
+		names[i] = server.Name
+	}
+	return &CatalogScreen{
+		BaseScreen: NewOverlayScreen("Catalog"),
+		logger:     debug.Component("catalog-screen"),
+		servers:    servers,
+		namespaces: catalogNamespaces(names),
+		titleStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("13")).
+			Bold(true),
+		tabStyle: lipgloss.NewStyle().
+			Padding(0, 1).
+			Foreground(lipgloss.Color("8")),
+		activeTabStyle: lipgloss.NewStyle().
+			Padding(0, 1).
+			Foreground(lipgloss.Color("15")).
+			Background(lipgloss.Color("4")).
+			Bold(true),
+		selectedStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("0")).
+			Background(lipgloss.Color("6")).
+			Bold(true),
+		warnStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("11")),
+		dimStyle:  lipgloss.NewStyle().Foreground(lipgloss.Color("241")),
+		detailStyle: lipgloss.NewStyle().
+			Padding(0, 1).
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("12")),
+	}
+}
+
+// Init loads the lists of every server
+func (cs *CatalogScreen) Init() tea.Cmd {
+	cs.loading = true
+	return cs.load()
+}
+
+// load lists the items of every server at once
+func (cs *CatalogScreen) load() tea.Cmd {
+	servers := cs.servers
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		lists := make([]catalogList, len(servers))
+		var wg sync.WaitGroup
+		for i, server := range servers {
+			wg.Add(1)
+			go func(list *catalogList, service mcp.Service) {
+				defer wg.Done()
+				// Servers without resources or prompts fail to list them
+				if list.Tools, list.Err = service.ListTools(ctx); list.Err != nil {
+					return
+				}
+				list.Resources, _ = service.ListResources(ctx)
+				list.Prompts, _ = service.ListPrompts(ctx)
+			}(&lists[i], server.Service)
+		}
+		wg.Wait()
+		return catalogLoadedMsg{Lists: lists}
+	}
+}
+
+// Update handles messages for the catalog screen
+func (cs *CatalogScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		cs.UpdateSize(msg.Width, msg.Height)
+		return cs, nil
+
+	case catalogLoadedMsg:
+		cs.loading = false
+		cs.lists = msg.Lists
+		cs.items = buildCatalog(cs.namespaces, msg.Lists)
+		cs.clampSelection()
+		for i, list := range msg.Lists {
+			if list.Err != nil {
+				cs.logger.Warn("Could not list server items",
+					debug.F("server", cs.servers[i].Name), debug.F("error", list.Err))
+			}
+		}
+		return cs, nil
+
+	case tea.KeyMsg:
+		if cs.searching {
+			return cs.handleSearchKey(msg)
+		}
+		return cs.handleKeyMsg(msg)
+	}
+	return cs, nil
+}
+
+// handleSearchKey edits the search filter
+func (cs *CatalogScreen) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		cs.searching = false
+	case tea.KeyEsc:
+		cs.searching = false
+		cs.filter = ""
+	case tea.KeyBackspace:
+		if runes := []rune(cs.filter); len(runes) > 0 {
+			cs.filter = string(runes[:len(runes)-1])
+		}
+	case tea.KeyCtrlC:
+		return cs, tea.Quit
+	case tea.KeyRunes, tea.KeySpace:
+		cs.filter += string(msg.Runes)
+	}
+	cs.selected, cs.scroll = 0, 0
+	return cs, nil
+}
+
+// handleKeyMsg handles keyboard input
+func (cs *CatalogScreen) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return cs, tea.Quit
+
+	case "esc", "b", "alt+left", "alt+a":
+		return cs, func() tea.Msg { return BackMsg{} }
+
+	case "/":
+		cs.searching = true
+		return cs, nil
+
+	case "tab", "right":
+		cs.kind = (cs.kind + 1) % len(catalogKinds)
+		cs.selected, cs.scroll = 0, 0
+		return cs, nil
+
+	case "shift+tab", "left":
+		cs.kind = (cs.kind - 1 + len(catalogKinds)) % len(catalogKinds)
+		cs.selected, cs.scroll = 0, 0
+		return cs, nil
+
+	case "up", "k":
+		if cs.selected > 0 {
+			cs.selected--
+		}
+		return cs, nil
+
+	case "down", "j":
+		if cs.selected < len(cs.visibleItems())-1 {
+			cs.selected++
+		}
+		return cs, nil
+
+	case "r":
+		cs.loading = true
+		return cs, cs.load()
+
+	case "enter":
+		return cs, cs.open()
+	}
+	return cs, nil
+}
+
+// open shows the selected item in its server's tab, with the form of a tool
+// open ready to call it
+func (cs *CatalogScreen) open() tea.Cmd {
+	items := cs.visibleItems()
+	if cs.selected >= len(items) {
+		return nil
+	}
+	item := items[cs.selected]
+	server := cs.servers[item.Server]
+
+	msg := CatalogOpenMsg{Tab: server.Tab, Kind: item.Kind, Name: item.Name}
+	if item.Tool != nil {
+		msg.Screen = NewToolScreen(*item.Tool, server.Service)
+	}
+	return func() tea.Msg { return msg }
+}
+
+// visibleItems returns the items of the kind showing that match the filter
+func (cs *CatalogScreen) visibleItems() []CatalogItem {
+	filter := strings.ToLower(cs.filter)
+	var items []CatalogItem
+	for _, item := range cs.items {
+		if item.Kind != cs.kind {
+			continue
+		}
+		if filter != "" && !strings.Contains(strings.ToLower(item.Namespaced), filter) &&
+			!strings.Contains(strings.ToLower(item.Description), filter) {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// clampSelection keeps the selection in the list after it changes
+func (cs *CatalogScreen) clampSelection() {
+	if n := len(cs.visibleItems()); cs.selected >= n {
+		cs.selected = max(0, n-1)
+	}
+}
+
+// View renders the catalog
+func (cs *CatalogScreen) View() string {
+	var builder strings.Builder
+
+	collisions := 0
+	for _, item := range cs.items {
+		if len(item.Duplicates) > 0 {
+			collisions++
+		}
+	}
+	builder.WriteString(cs.titleStyle.Render("🗂  All Servers"))
+	servers := "servers"
+	if len(cs.servers) == 1 {
+		servers = "server"
+	}
+	builder.WriteString(cs.dimStyle.Render(fmt.Sprintf("  %d %s • %d items • %d with colliding names",
+		len(cs.servers), servers, len(cs.items), collisions)))
+	builder.WriteString("\n\n")
+
+	var tabs []string
+	for kind, name := range catalogKinds {
+		count := 0
+		for _, item := range cs.items {
+			if item.Kind == kind {
+				count++
+			}
+		}
+		style := cs.tabStyle
+		if kind == cs.kind {
+			style = cs.activeTabStyle
+		}
+		tabs = append(tabs, style.Render(fmt.Sprintf("%s (%d)", name, count)))
+	}
+	builder.WriteString(strings.Join(tabs, " │ "))
+	builder.WriteString("\n")
+
+	switch {
+	case cs.searching:
+		builder.WriteString("Search: " + cs.filter + "█")
+	case cs.filter != "":
+		builder.WriteString(cs.dimStyle.Render("Search: " + cs.filter + " (/ to edit)"))
+	}
+	builder.WriteString("\n")
+
+	for i, list := range cs.lists {
+		if list.Err != nil {
+			builder.WriteString(cs.warnStyle.Render(fmt.Sprintf("⚠ %s: %v", cs.namespaces[i], list.Err)))
+			builder.WriteString("\n")
+		}
+	}
+
+	items := cs.visibleItems()
+	switch {
+	case cs.loading && len(cs.items) == 0:
+		builder.WriteString("Loading items from every server...\n")
+	case len(items) == 0:
+		builder.WriteString(cs.dimStyle.Render("No matching " + strings.ToLower(catalogKinds[cs.kind])))
+		builder.WriteString("\n")
+	default:
+		builder.WriteString(cs.renderList(items))
+		builder.WriteString(cs.renderDetail(items[cs.selected]))
+	}
+
+	builder.WriteString("\n")
+	builder.WriteString(cs.dimStyle.Render("↑↓: Navigate • Tab: Kind • /: Search • Enter: Open in its server • r: Reload • Esc: Close"))
+	return builder.String()
+}
+
+// renderList renders the items around the selection that fit the screen
+func (cs *CatalogScreen) renderList(items []CatalogItem) string {
+	rows := 15
+	if h := cs.Height(); h > 0 {
+		rows = max(3, h-18)
+	}
+	if cs.selected < cs.scroll {
+		cs.scroll = cs.selected
+	} else if cs.selected >= cs.scroll+rows {
+		cs.scroll = cs.selected - rows + 1
+	}
+
+	var builder strings.Builder
+	end := min(len(items), cs.scroll+rows)
+	for i := cs.scroll; i < end; i++ {
+		item := items[i]
+		line := item.Namespaced
+		if len(item.Duplicates) > 0 {
+			line += cs.warnStyle.Render("  ⚠ name collision")
+		} else if len(item.Similar) > 0 {
+			line += cs.warnStyle.Render("  ≈ similar description")
+		}
+		if i == cs.selected {
+			line = cs.selectedStyle.Render("▶ "+item.Namespaced) + strings.TrimPrefix(line, item.Namespaced)
+		} else {
+			line = "  " + line
+		}
+		builder.WriteString(line + "\n")
+	}
+	if end < len(items) {
+		builder.WriteString(cs.dimStyle.Render(fmt.Sprintf("  … %d more", len(items)-end)))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// renderDetail renders the selected item and what it collides with
+func (cs *CatalogScreen) renderDetail(item CatalogItem) string {
+	lines := []string{
+		"Server:      " + cs.servers[item.Server].Name,
+		"Name:        " + item.Name,
+	}
+	if item.Description != "" {
+		lines = append(lines, "Description: "+item.Description)
+	}
+	if len(item.Duplicates) > 0 {
+		lines = append(lines, cs.warnStyle.Render("Same name on: "+strings.Join(item.Duplicates, ", ")))
+	}
+	if len(item.Similar) > 0 {
+		lines = append(lines, cs.warnStyle.Render("Description overlaps: "+strings.Join(item.Similar, ", ")))
+	}
+
+	style := cs.detailStyle
+	if w := cs.Width(); w > 4 {
+		style = style.Width(w - 4)
+	}
+	return style.Render(strings.Join(lines, "\n")) + "\n"
+}
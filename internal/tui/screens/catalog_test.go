@@ -0,0 +1,84 @@
+package screens
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+)
+
+func TestCatalogNamespaces(t *testing.T) {
+	assert.Equal(t,
+		[]string{"github", "node_server_js", "github-2", "server"},
+		catalogNamespaces([]string{"GitHub", "node server.js", "github", "///"}))
+}
+
+func TestBuildCatalog(t *testing.T) {
+	items := buildCatalog([]string{"files", "git"}, []catalogList{
+		{
+			Tools: []mcp.Tool{
+				{Name: "search", Description: "Search files in the workspace by name"},
+				{Name: "read", Description: "Read a file"},
+			},
+			Resources: []mcp.Resource{{URI: "file:///README.md"}},
+		},
+		{
+			Tools: []mcp.Tool{
+				{Name: "search", Description: "Search commit messages"},
+				{Name: "grep", Description: "Search files in the workspace by content"},
+			},
+			Prompts: []mcp.Prompt{{Name: "review"}},
+		},
+	})
+
+	byName := make(map[string]CatalogItem)
+	for _, item := range items {
+		byName[item.Namespaced] = item
+	}
+	require.Len(t, byName, 6, "Namespacing keeps every item apart")
+
+	assert.Equal(t, []string{"git__search"}, byName["files__search"].Duplicates)
+	assert.Equal(t, []string{"files__search"}, byName["git__search"].Duplicates)
+	assert.Equal(t, []string{"git__grep"}, byName["files__search"].Similar,
+		"Overlapping descriptions are flagged across servers")
+	assert.Empty(t, byName["files__read"].Duplicates)
+	assert.Empty(t, byName["files__read"].Similar, "Short descriptions are not compared")
+
+	require.NotNil(t, byName["git__grep"].Tool)
+	assert.Equal(t, "grep", byName["git__grep"].Tool.Name)
+	assert.Equal(t, 1, byName["files__file:///README.md"].Kind)
+	assert.Equal(t, 2, byName["git__review"].Kind)
+}
+
+func TestCatalogScreenSearchAndOpen(t *testing.T) {
+	cs := NewCatalogScreen([]CatalogServer{{Tab: 4, Name: "files"}, {Tab: 7, Name: "git"}})
+	cs.Update(catalogLoadedMsg{Lists: []catalogList{
+		{Tools: []mcp.Tool{{Name: "search"}, {Name: "read"}}},
+		{Tools: []mcp.Tool{{Name: "search"}, {Name: "log", Description: "Show commit history"}}},
+	}})
+	require.Len(t, cs.visibleItems(), 4)
+	assert.Contains(t, cs.View(), "⚠ name collision")
+
+	key := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	cs.Update(key("/"))
+	cs.Update(key("commit"))
+	cs.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	items := cs.visibleItems()
+	require.Len(t, items, 1, "The search matches descriptions too")
+	assert.Equal(t, "git__log", items[0].Namespaced)
+
+	_, cmd := cs.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	msg, ok := cmd().(CatalogOpenMsg)
+	require.True(t, ok)
+	assert.Equal(t, 7, msg.Tab)
+	assert.Equal(t, "log", msg.Name)
+	assert.IsType(t, &ToolScreen{}, msg.Screen, "A tool opens ready to call")
+
+	cs.Update(key("/"))
+	cs.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Len(t, cs.visibleItems(), 4, "Escape clears the search")
+}
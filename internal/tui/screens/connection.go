@@ -101,7 +101,7 @@ func NewConnectionScreenWithConfig(cfg *config.Config, prevConfig *config.Connec
 	cs.argsInput.Width = 80
 
 	cs.urlInput = textinput.New()
-	cs.urlInput.Placeholder = "http://localhost:3000/sse or http://localhost:3000"
+	cs.urlInput.Placeholder = urlPlaceholder(config.TransportHTTP)
 	cs.urlInput.CharLimit = 1024
 	cs.urlInput.Width = 80
 
@@ -119,13 +119,8 @@ func NewConnectionScreenWithConfig(cfg *config.Config, prevConfig *config.Connec
 			debug.F("url", prevConfig.URL))
 
 		// Set transport type
-		switch prevConfig.Type {
-		case "stdio":
-			cs.transportType = config.TransportStdio
-		case "sse":
-			cs.transportType = config.TransportSSE
-		case "http":
-			cs.transportType = config.TransportHTTP
+		if transportIndex(prevConfig.Type) >= 0 {
+			cs.transportType = prevConfig.Type
 		}
 
 		// Set input values
@@ -449,7 +444,7 @@ func (cs *ConnectionScreen) handleManualEntryInput(msg tea.KeyMsg) (tea.Model, t
 		} else {
 			isInTextInput = cs.focusIndex == 1 || cs.focusIndex == 2
 		}
-	case config.TransportSSE, config.TransportHTTP:
+	default:
 		isInTextInput = cs.focusIndex == 1
 	}
 
@@ -488,7 +483,7 @@ func (cs *ConnectionScreen) handleManualEntryInput(msg tea.KeyMsg) (tea.Model, t
 						cs.argsInput, cmd = cs.argsInput.Update(msg)
 					}
 				}
-			case config.TransportSSE, config.TransportHTTP:
+			default:
 				if cs.focusIndex == 1 {
 					cs.urlInput, cmd = cs.urlInput.Update(msg)
 				}
@@ -523,42 +518,21 @@ func (cs *ConnectionScreen) handleManualEntryInput(msg tea.KeyMsg) (tea.Model, t
 	case "left":
 		if cs.focusIndex == 0 { // Transport type selection
 			cs.blurAllInputs()
-			switch cs.transportType {
-			case config.TransportStdio:
-				cs.transportType = config.TransportHTTP // Wrap around
-			case config.TransportSSE:
-				cs.transportType = config.TransportStdio
-			case config.TransportHTTP:
-				cs.transportType = config.TransportSSE
-			}
+			cs.setTransportIndex(transportIndex(cs.transportType) - 1) // Wraps around
 		}
 		return cs, nil
 
 	case "right":
 		if cs.focusIndex == 0 { // Transport type selection
 			cs.blurAllInputs()
-			switch cs.transportType {
-			case config.TransportStdio:
-				cs.transportType = config.TransportSSE
-			case config.TransportSSE:
-				cs.transportType = config.TransportHTTP
-			case config.TransportHTTP:
-				cs.transportType = config.TransportStdio // Wrap around
-			}
+			cs.setTransportIndex(transportIndex(cs.transportType) + 1) // Wraps around
 		}
 		return cs, nil
 
-	case "1", "2", "3":
+	case "1", "2", "3", "4", "5", "6", "7":
 		if cs.focusIndex == 0 { // Transport type selection
 			oldTransport := cs.transportType
-			switch msg.String() {
-			case "1":
-				cs.transportType = config.TransportStdio
-			case "2":
-				cs.transportType = config.TransportSSE
-			case "3":
-				cs.transportType = config.TransportHTTP
-			}
+			cs.setTransportIndex(int(msg.String()[0] - '1'))
 			// If transport type changed, reset focus
 			if oldTransport != cs.transportType {
 				cs.blurAllInputs()
@@ -619,9 +593,10 @@ func (cs *ConnectionScreen) handleSavedConnectionConnect() (tea.Model, tea.Cmd)
 	// Update last used
 	cs.connectionsManager.UpdateLastUsed(currentConnection.ID, false) // Will be updated to true on success
 
-	// Transition to main screen
+	// Open the server in a tab
 	mainScreen := NewMainScreen(cs.config, connConfig)
-	return mainScreen, mainScreen.Init()
+	mainScreen.SetSavedConnection(cs.connectionsManager, currentConnection.ID)
+	return cs, openServer(mainScreen)
 }
 
 // handleDiscoveredFileLoad loads connections from the selected discovered file
@@ -697,7 +672,7 @@ func (cs *ConnectionScreen) updateInputFocus() {
 				cs.argsInput.Focus()
 			}
 		}
-	case config.TransportSSE, config.TransportHTTP:
+	default:
 		if cs.focusIndex == 1 {
 			cs.urlInput.Focus()
 		}
@@ -756,15 +731,15 @@ func (cs *ConnectionScreen) handleConnect() (tea.Model, tea.Cmd) {
 			debug.F("transport", "stdio"),
 			debug.F("command", command),
 			debug.F("args", args))
-	case config.TransportHTTP, config.TransportSSE:
+	default:
 		cs.logger.Info("Connecting to MCP server",
 			debug.F("transport", cs.transportType),
 			debug.F("url", url))
 	}
 
-	// Transition to main screen
+	// Open the server in a tab
 	mainScreen := NewMainScreen(cs.config, connConfig)
-	return mainScreen, mainScreen.Init()
+	return cs, openServer(mainScreen)
 }
 
 // validateInputs validates the form inputs
@@ -775,7 +750,7 @@ func (cs *ConnectionScreen) validateInputs() error {
 			return fmt.Errorf("command is required for STDIO transport")
 		}
 
-	case config.TransportSSE, config.TransportHTTP:
+	default:
 		if cs.urlInput.Value() == "" {
 			return fmt.Errorf("URL is required for %s transport", cs.transportType)
 		}
@@ -1038,7 +1013,7 @@ func (cs *ConnectionScreen) renderManualEntry() string {
 	switch cs.transportType {
 	case config.TransportStdio:
 		builder.WriteString(cs.renderStdioFields())
-	case config.TransportSSE, config.TransportHTTP:
+	default:
 		builder.WriteString(cs.renderURLFields())
 	}
 
@@ -1055,7 +1030,7 @@ func (cs *ConnectionScreen) renderHelpText() string {
 	case "discovery":
 		helpText = "←/→: Navigate files • Enter: Load config • M: Switch mode • Tab: Navigate • Ctrl+D/F12: Debug • Esc/Ctrl+C: Quit"
 	default: // "manual"
-		helpText = "←/→: Switch transport • 1/2/3: Select transport • Tab/Shift+Tab: Navigate • Enter: Connect"
+		helpText = "←/→: Switch transport • 1-7: Select transport • Tab/Shift+Tab: Navigate • Enter: Connect"
 		if cs.transportType == config.TransportStdio {
 			helpText += " • C: Toggle command mode"
 		}
@@ -1068,6 +1043,53 @@ func (cs *ConnectionScreen) renderHelpText() string {
 	return cs.helpStyle.Render(helpText)
 }
 
+// manualTransports lists the transports offered by the manual entry selector,
+// in the order of their number keys
+var manualTransports = []struct {
+	transport config.TransportType
+	label     string
+}{
+	{config.TransportStdio, "STDIO"},
+	{config.TransportSSE, "SSE"},
+	{config.TransportHTTP, "HTTP"},
+	{config.TransportWebSocket, "WebSocket"},
+	{config.TransportUnix, "Unix"},
+	{config.TransportTCP, "TCP"},
+	{config.TransportAuto, "Auto"},
+}
+
+// transportIndex returns the selector position of a transport, or -1 if it is not offered
+func transportIndex(transport config.TransportType) int {
+	for i, option := range manualTransports {
+		if option.transport == transport {
+			return i
+		}
+	}
+	return -1
+}
+
+// setTransportIndex selects the transport at the given position, wrapping around
+func (cs *ConnectionScreen) setTransportIndex(index int) {
+	n := len(manualTransports)
+	cs.transportType = manualTransports[((index%n)+n)%n].transport
+}
+
+// urlPlaceholder returns example input for the URL field of a transport
+func urlPlaceholder(transport config.TransportType) string {
+	switch transport {
+	case config.TransportWebSocket:
+		return "ws://localhost:3000/mcp or wss://example.com/mcp"
+	case config.TransportUnix:
+		return "unix:///tmp/mcp.sock"
+	case config.TransportTCP:
+		return "tcp://localhost:9000"
+	case config.TransportAuto:
+		return "http://localhost:3000/mcp (probes streamable HTTP, then SSE)"
+	default:
+		return "http://localhost:3000/sse or http://localhost:3000"
+	}
+}
+
 // renderTransportSelection renders the transport type selection
 func (cs *ConnectionScreen) renderTransportSelection() string {
 	title := "Transport Type:"
@@ -1079,57 +1101,23 @@ func (cs *ConnectionScreen) renderTransportSelection() string {
 
 	// Create horizontal options with proper styling
 	var options []string
-
-	// STDIO option
-	stdioText := "1) STDIO"
-	if cs.transportType == config.TransportStdio {
-		stdioStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("0")).
-			Background(lipgloss.Color("6")).
-			Bold(true).
-			Padding(0, 1)
-		stdioText = stdioStyle.Render(stdioText + " ✓")
-	} else {
-		stdioStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("7")).
-			Padding(0, 1)
-		stdioText = stdioStyle.Render(stdioText)
-	}
-	options = append(options, stdioText)
-
-	// SSE option
-	sseText := "2) SSE"
-	if cs.transportType == config.TransportSSE {
-		sseStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("0")).
-			Background(lipgloss.Color("6")).
-			Bold(true).
-			Padding(0, 1)
-		sseText = sseStyle.Render(sseText + " ✓")
-	} else {
-		sseStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("7")).
-			Padding(0, 1)
-		sseText = sseStyle.Render(sseText)
-	}
-	options = append(options, sseText)
-
-	// HTTP option
-	httpText := "3) HTTP"
-	if cs.transportType == config.TransportHTTP {
-		httpStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("0")).
-			Background(lipgloss.Color("6")).
-			Bold(true).
-			Padding(0, 1)
-		httpText = httpStyle.Render(httpText + " ✓")
-	} else {
-		httpStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("7")).
-			Padding(0, 1)
-		httpText = httpStyle.Render(httpText)
+	for i, option := range manualTransports {
+		text := fmt.Sprintf("%d) %s", i+1, option.label)
+		if cs.transportType == option.transport {
+			selectedStyle := lipgloss.NewStyle().
+				Foreground(lipgloss.Color("0")).
+				Background(lipgloss.Color("6")).
+				Bold(true).
+				Padding(0, 1)
+			text = selectedStyle.Render(text + " ✓")
+		} else {
+			optionStyle := lipgloss.NewStyle().
+				Foreground(lipgloss.Color("7")).
+				Padding(0, 1)
+			text = optionStyle.Render(text)
+		}
+		options = append(options, text)
 	}
-	options = append(options, httpText)
 
 	// Join horizontally with spacing
 	horizontalOptions := strings.Join(options, "  ")
@@ -1185,6 +1173,11 @@ func (cs *ConnectionScreen) renderStdioFields() string {
 // renderURLFields renders fields for URL-based transports
 func (cs *ConnectionScreen) renderURLFields() string {
 	urlLabel := "URL:"
+	switch cs.transportType {
+	case config.TransportUnix, config.TransportTCP:
+		urlLabel = "Address:"
+	}
+	cs.urlInput.Placeholder = urlPlaceholder(cs.transportType)
 	if cs.focusIndex == 1 {
 		urlLabel = cs.focusedStyle.Render(urlLabel)
 		return fmt.Sprintf("%s\n%s", urlLabel, cs.focusedStyle.Render(cs.urlInput.View()))
@@ -12,7 +12,9 @@ import (
 	"github.com/standardbeagle/mcp-tui/internal/config"
 	"github.com/standardbeagle/mcp-tui/internal/debug"
 	"github.com/standardbeagle/mcp-tui/internal/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/session"
 	"github.com/standardbeagle/mcp-tui/internal/tui/components"
+	"github.com/standardbeagle/mcp-tui/internal/tui/models"
 )
 
 // MainScreen is the primary interface for browsing tools, resources, and prompts
@@ -87,6 +89,23 @@ type MainScreen struct {
 	connecting       bool
 	connectingStart  time.Time
 
+	// Watch mode restarts
+	restarting     bool
+	restartPending bool // Files changed again during a restart
+
+	// Automatic reconnection after the connection is lost
+	statusUpdates chan session.Status
+	reconnecting  *session.Status // Latest status while reconnecting
+
+	// Session persistence
+	resume     *session.Snapshot   // Saved session to resume on connecting
+	navigation *session.Navigation // Saved selections not yet restored
+	persisting bool                // The session is being saved periodically
+
+	// Saved connection this screen was opened from, if any
+	connectionsManager *models.ConnectionsManager
+	savedConnectionID  string
+
 	// Styles
 	tabStyle       lipgloss.Style
 	activeTabStyle lipgloss.Style
@@ -101,8 +120,10 @@ type ConnectionStartedMsg struct{}
 
 // ConnectionCompleteMsg indicates connection is complete
 type ConnectionCompleteMsg struct {
-	Success bool
-	Error   error
+	Success   bool
+	Error     error
+	Transport config.TransportType // Transport actually used, after any auto negotiation
+	Resumed   bool                 // A saved streamable HTTP session was continued
 }
 
 // ItemsLoadedMsg contains loaded items for a tab
@@ -159,7 +180,7 @@ type spinnerTickMsg struct{}
 
 // NewMainScreen creates a new main screen
 func NewMainScreen(cfg *config.Config, connConfig *config.ConnectionConfig) *MainScreen {
-	service := mcp.NewService()
+	service := mcp.NewServiceWithConfig(serviceConfig(cfg))
 	// Enable debug mode if configured
 	if cfg.DebugMode {
 		service.SetDebugMode(true)
@@ -179,8 +200,17 @@ func NewMainScreen(cfg *config.Config, connConfig *config.ConnectionConfig) *Mai
 		events:           []debug.MCPLogEntry{},
 		connectionStatus: "Connecting...",
 		connecting:       true,
+		statusUpdates:    make(chan session.Status, 16),
 	}
 
+	// Follow reconnections, which the session manager makes on its own
+	service.OnConnectionStatus(func(status session.Status) {
+		select {
+		case ms.statusUpdates <- status:
+		default:
+		}
+	})
+
 	// Initialize styles
 	ms.initStyles()
 
@@ -239,6 +269,7 @@ func (ms *MainScreen) Init() tea.Cmd {
 		func() tea.Msg { return ConnectionStartedMsg{} },
 		ms.connectToServer(),
 		ms.tickEvents(), // Start periodic event refresh
+		ms.waitForStatus(),
 	)
 }
 
@@ -261,7 +292,8 @@ func (ms *MainScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case config.TransportStdio:
 			ms.connectionStatus = fmt.Sprintf("Connecting to stdio: %s %s",
 				ms.connectionConfig.Command, strings.Join(ms.connectionConfig.Args, " "))
-		case config.TransportHTTP, config.TransportSSE:
+		case config.TransportHTTP, config.TransportSSE, config.TransportWebSocket,
+			config.TransportUnix, config.TransportTCP, config.TransportAuto, config.TransportReplay:
 			ms.connectionStatus = fmt.Sprintf("Connecting to %s: %s",
 				ms.connectionConfig.Type, ms.connectionConfig.URL)
 		default:
@@ -274,10 +306,16 @@ func (ms *MainScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case ConnectionCompleteMsg:
 		ms.connecting = false
+		ms.recordSavedConnection(msg)
 		if msg.Success {
 			ms.connected = true
 			ms.connectionStatus = fmt.Sprintf("Connected to %s %s",
 				ms.connectionConfig.Command, strings.Join(ms.connectionConfig.Args, " "))
+			if msg.Transport != "" && msg.Transport != ms.connectionConfig.Type {
+				ms.connectionStatus = fmt.Sprintf("Connected to %s (negotiated %s)",
+					ms.connectionConfig.URL, msg.Transport)
+			}
+			ms.applyResume(msg)
 			// Set loading states for all tabs
 			now := time.Now()
 			ms.toolsLoading = true
@@ -293,6 +331,7 @@ func (ms *MainScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				ms.loadResources(),
 				ms.loadPrompts(),
 				ms.loadEvents(),
+				ms.startPersisting(),
 			)
 		} else {
 			ms.connected = false
@@ -315,6 +354,20 @@ func (ms *MainScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return ms, nil
 
+	case persistTickMsg:
+		ms.SaveSession()
+		ms.persisting = false
+		return ms, ms.startPersisting()
+
+	case ConnectionStatusMsg:
+		return ms, tea.Batch(ms.handleConnectionStatus(msg.Status), ms.waitForStatus())
+
+	case ServerChangedMsg:
+		return ms, ms.startRestart(msg.Paths)
+
+	case ServerRestartedMsg:
+		return ms, ms.finishRestart(msg)
+
 	case ToolsLoadedMsg:
 		ms.toolsLoading = false
 		if msg.Error != nil {
@@ -327,6 +380,7 @@ func (ms *MainScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			ms.toolCount = msg.ActualCount
 		}
 		ms.ensureInitialFocus(0)
+		ms.restoreNavigation(0)
 		return ms, nil
 
 	case ResourcesLoadedMsg:
@@ -341,6 +395,7 @@ func (ms *MainScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			ms.resourceCount = msg.ActualCount
 		}
 		ms.ensureInitialFocus(1)
+		ms.restoreNavigation(1)
 		return ms, nil
 
 	case PromptsLoadedMsg:
@@ -355,6 +410,7 @@ func (ms *MainScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			ms.promptCount = msg.ActualCount
 		}
 		ms.ensureInitialFocus(2)
+		ms.restoreNavigation(2)
 		return ms, nil
 
 	case ResourceContentLoadedMsg:
@@ -393,7 +449,7 @@ func (ms *MainScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case 3: // Events
 			ms.eventsLoading = false
 			// Re-fetch events from logger
-			if mcpLogger := debug.GetMCPLogger(); mcpLogger != nil {
+			if mcpLogger := ms.mcpService.EventLog(); mcpLogger != nil {
 				allEntries := mcpLogger.GetEntries()
 				var events []debug.MCPLogEntry
 				for _, entry := range allEntries {
@@ -425,8 +481,13 @@ func (ms *MainScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case spinnerTickMsg:
 		// Continue spinner animation while connecting
 		if ms.connecting {
+			// Count down to the next reconnection attempt
+			if ms.reconnecting != nil {
+				ms.connectionStatus = reconnectingText(*ms.reconnecting)
+			}
 			// Update connection status with detailed HTTP progress for HTTP/SSE transports
-			if ms.connectionConfig.Type == config.TransportHTTP || ms.connectionConfig.Type == config.TransportSSE {
+			switch ms.connectionConfig.Type {
+			case config.TransportHTTP, config.TransportSSE, config.TransportAuto:
 				if detailedStatus := ms.mcpService.GetConnectionDisplayMessage(); detailedStatus != "" {
 					ms.connectionStatus = detailedStatus
 					// Add diagnostic message if helpful
@@ -1390,6 +1451,10 @@ func (ms *MainScreen) connectToServer() tea.Cmd {
 			debug.F("command", ms.connectionConfig.Command),
 			debug.F("args", ms.connectionConfig.Args))
 
+		if ms.resume != nil {
+			return ms.resumeSession()
+		}
+
 		// Use context with timeout
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
@@ -1406,12 +1471,35 @@ func (ms *MainScreen) connectToServer() tea.Cmd {
 
 		ms.logger.Info("MCP connection successful")
 		return ConnectionCompleteMsg{
-			Success: true,
-			Error:   nil,
+			Success:   true,
+			Error:     nil,
+			Transport: config.TransportType(ms.mcpService.GetServerInfo().Transport),
 		}
 	}
 }
 
+// SetSavedConnection links the screen to the saved connection it was opened from
+// so the outcome, including any negotiated transport, is written back to it
+func (ms *MainScreen) SetSavedConnection(manager *models.ConnectionsManager, connectionID string) {
+	ms.connectionsManager = manager
+	ms.savedConnectionID = connectionID
+}
+
+// recordSavedConnection persists the connection outcome on the saved connection
+func (ms *MainScreen) recordSavedConnection(msg ConnectionCompleteMsg) {
+	if ms.connectionsManager == nil || ms.savedConnectionID == "" {
+		return
+	}
+
+	if msg.Success {
+		ms.connectionsManager.RecordNegotiatedTransport(ms.savedConnectionID, msg.Transport)
+	} else {
+		// Forget a stale negotiated choice so the next attempt probes again
+		ms.connectionsManager.RecordNegotiatedTransport(ms.savedConnectionID, "")
+	}
+	ms.connectionsManager.UpdateLastUsed(ms.savedConnectionID, msg.Success)
+}
+
 // loadTools loads the list of tools
 func (ms *MainScreen) loadTools() tea.Cmd {
 	return func() tea.Msg {
@@ -1600,7 +1688,7 @@ func (ms *MainScreen) tickEvents() tea.Cmd {
 func (ms *MainScreen) loadEvents() tea.Cmd {
 	return func() tea.Msg {
 		// Get all MCP log entries
-		if mcpLogger := debug.GetMCPLogger(); mcpLogger != nil {
+		if mcpLogger := ms.mcpService.EventLog(); mcpLogger != nil {
 			allEntries := mcpLogger.GetEntries()
 
 			// Filter for notifications and events without IDs
@@ -0,0 +1,92 @@
+package screens
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/session"
+)
+
+// ConnectionStatusMsg reports a session state transition or reconnection
+// attempt
+type ConnectionStatusMsg struct {
+	Status session.Status
+}
+
+// waitForStatus returns a command that delivers the next session status
+func (ms *MainScreen) waitForStatus() tea.Cmd {
+	updates := ms.statusUpdates
+	return func() tea.Msg {
+		return ConnectionStatusMsg{Status: <-updates}
+	}
+}
+
+// handleConnectionStatus shows reconnection progress and reloads the lists
+// once the session is back. Transitions of connections the screen starts
+// itself are reported by their own messages.
+func (ms *MainScreen) handleConnectionStatus(status session.Status) tea.Cmd {
+	switch {
+	case status.State == session.StateReconnecting:
+		ms.logger.Info("Reconnecting", debug.F("status", status.String()))
+		started := ms.reconnecting == nil
+		ms.reconnecting = &status
+		ms.connected = false
+		ms.connecting = true
+		ms.connectionStatus = reconnectingText(status)
+		if started {
+			ms.connectingStart = time.Now()
+			return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
+				return spinnerTickMsg{}
+			})
+		}
+		return nil
+
+	case ms.reconnecting == nil:
+		return nil
+
+	case status.State == session.StateConnected:
+		ms.reconnecting = nil
+		ms.connecting = false
+		ms.connected = true
+		ms.connectionStatus = "Reconnected"
+		ms.SetStatus("Reconnected to the server", StatusSuccess)
+
+		// The server may have changed while it was away
+		now := time.Now()
+		ms.toolsLoading = true
+		ms.toolsLoadStart = now
+		ms.resourcesLoading = true
+		ms.resourcesLoadStart = now
+		ms.promptsLoading = true
+		ms.promptsLoadStart = now
+		return tea.Batch(ms.loadTools(), ms.loadResources(), ms.loadPrompts())
+
+	case status.State == session.StateFailed:
+		ms.reconnecting = nil
+		ms.connecting = false
+		ms.connectionStatus = fmt.Sprintf("Reconnection failed after %d attempts", status.Attempt)
+		if status.Err != nil {
+			ms.connectionStatus = fmt.Sprintf("Reconnection failed after %d attempts: %v", status.Attempt, status.Err)
+			ms.SetError(status.Err)
+		}
+		return nil
+
+	default:
+		// Disconnected while reconnecting
+		ms.reconnecting = nil
+		ms.connecting = false
+		return nil
+	}
+}
+
+// reconnectingText describes a reconnection in progress for the status line
+func reconnectingText(status session.Status) string {
+	text := "Connection lost, " + status.String()
+	if status.NextAttempt.IsZero() {
+		text += "…"
+	}
+	return text
+}
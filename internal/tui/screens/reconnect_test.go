@@ -0,0 +1,41 @@
+package screens
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/session"
+)
+
+func TestMainScreenShowsReconnection(t *testing.T) {
+	ms := NewMainScreen(config.Default(), &config.ConnectionConfig{Type: config.TransportStdio, Command: "node"})
+	ms.connecting = false
+	ms.connected = true
+
+	// Transitions of the screen's own connections are left alone
+	assert.Nil(t, ms.handleConnectionStatus(session.Status{State: session.StateDisconnected}))
+	assert.True(t, ms.connected)
+
+	assert.NotNil(t, ms.handleConnectionStatus(session.Status{
+		State:       session.StateReconnecting,
+		Attempt:     2,
+		MaxAttempts: 5,
+		NextAttempt: time.Now().Add(3500 * time.Millisecond),
+	}))
+	assert.False(t, ms.connected)
+	assert.Contains(t, ms.connectionStatus, "reconnecting (attempt 2/5, next in 4s)")
+
+	assert.NotNil(t, ms.handleConnectionStatus(session.Status{State: session.StateConnected}))
+	assert.True(t, ms.connected)
+	assert.Nil(t, ms.reconnecting)
+	assert.True(t, ms.toolsLoading, "Lists are reloaded after reconnecting")
+
+	ms.handleConnectionStatus(session.Status{State: session.StateReconnecting, Attempt: 5, MaxAttempts: 5})
+	ms.handleConnectionStatus(session.Status{State: session.StateFailed, Attempt: 5, Err: fmt.Errorf("connection refused")})
+	assert.False(t, ms.connecting)
+	assert.Contains(t, ms.connectionStatus, "Reconnection failed after 5 attempts: connection refused")
+}
@@ -0,0 +1,259 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	mcpConfig "github.com/standardbeagle/mcp-tui/internal/mcp/config"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/session"
+)
+
+// persistTickMsg is sent when the session is due to be saved
+type persistTickMsg struct{}
+
+// serviceConfig carries the session settings from the command line into the
+// MCP service configuration
+func serviceConfig(cfg *config.Config) *mcpConfig.UnifiedConfig {
+	unified := mcpConfig.Default()
+	unified.Debug.Enabled = true // Always enabled - this is a testing tool
+	unified.Session.EnablePersistence = cfg.PersistSession
+	unified.Session.PersistenceFile = session.SnapshotPath(cfg.PersistFile)
+	if cfg.PersistInterval > 0 {
+		unified.Session.PersistenceInterval = cfg.PersistInterval
+	}
+	return unified
+}
+
+// ResumeScreen offers to resume the session saved by the previous run
+type ResumeScreen struct {
+	*BaseScreen
+
+	config   *config.Config
+	snapshot *session.Snapshot
+	fresh    Screen // Shown when the saved session is not resumed
+
+	titleStyle lipgloss.Style
+	boxStyle   lipgloss.Style
+	helpStyle  lipgloss.Style
+}
+
+// NewResumeScreen creates a screen offering to resume snapshot, or to go on
+// to fresh instead
+func NewResumeScreen(cfg *config.Config, snapshot *session.Snapshot, fresh Screen) *ResumeScreen {
+	return &ResumeScreen{
+		BaseScreen: NewBaseScreen("Resume", false),
+		config:     cfg,
+		snapshot:   snapshot,
+		fresh:      fresh,
+		titleStyle: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("13")).
+			Bold(true).
+			Margin(1, 0),
+		boxStyle: lipgloss.NewStyle().
+			Padding(1, 2).
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("12")),
+		helpStyle: lipgloss.NewStyle().Foreground(lipgloss.Color("241")),
+	}
+}
+
+// Init does nothing until the user chooses
+func (rs *ResumeScreen) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages for the resume screen
+func (rs *ResumeScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		rs.UpdateSize(msg.Width, msg.Height)
+		return rs, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return rs, tea.Quit
+		case "enter", "y", "r":
+			return rs, transitionTo(NewResumedMainScreen(rs.config, rs.snapshot))
+		case "n", "esc":
+			return rs, transitionTo(rs.fresh)
+		}
+	}
+	return rs, nil
+}
+
+// View renders the saved session and the choices
+func (rs *ResumeScreen) View() string {
+	var builder strings.Builder
+	builder.WriteString(rs.titleStyle.Render("💾 Resume previous session?"))
+	builder.WriteString("\n")
+
+	s := rs.snapshot
+	lines := []string{
+		"Server:  " + describeConnection(s.Connection),
+		"Saved:   " + describeAge(time.Since(s.SavedAt)) + " ago",
+	}
+	if s.SessionID != "" {
+		lines = append(lines, "Session: "+s.SessionID+" (resumed if the server still has it)")
+	} else {
+		lines = append(lines, "Session: reconnects with the saved state restored")
+	}
+	lines = append(lines, fmt.Sprintf("History: %d tool calls, %d events", len(s.Calls), len(s.Events)))
+	if len(s.Calls) > 0 {
+		last := s.Calls[len(s.Calls)-1]
+		lines = append(lines, "Last:    "+last.Tool+" at "+last.StartedAt.Format("15:04:05"))
+	}
+	if s.LogLevel != "" || len(s.Subscriptions) > 0 {
+		lines = append(lines, fmt.Sprintf("State:   log level %q, %d resource subscriptions",
+			s.LogLevel, len(s.Subscriptions)))
+	}
+	builder.WriteString(rs.boxStyle.Render(strings.Join(lines, "\n")))
+	builder.WriteString("\n\n")
+	builder.WriteString(rs.helpStyle.Render("Enter/y: Resume • n/Esc: Start fresh • q/Ctrl+C: Quit"))
+	return builder.String()
+}
+
+// transitionTo returns a command switching to screen
+func transitionTo(screen Screen) tea.Cmd {
+	return func() tea.Msg {
+		return TransitionMsg{Transition: ScreenTransition{Screen: screen}}
+	}
+}
+
+// describeConnection names the server of a connection for display
+func describeConnection(conn *config.ConnectionConfig) string {
+	if conn.Type == config.TransportStdio {
+		return strings.TrimSpace(conn.Command + " " + strings.Join(conn.Args, " "))
+	}
+	return fmt.Sprintf("%s (%s)", conn.URL, conn.Type)
+}
+
+// describeAge rounds a duration for display, e.g. "3m" or "2h"
+func describeAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
+
+// NewResumedMainScreen creates a main screen that connects as the saved
+// session did and returns to where it was
+func NewResumedMainScreen(cfg *config.Config, snapshot *session.Snapshot) *MainScreen {
+	ms := NewMainScreen(cfg, snapshot.Connection)
+	ms.resume = snapshot
+	return ms
+}
+
+// resumeSession connects by resuming the saved session
+func (ms *MainScreen) resumeSession() tea.Msg {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resumed, err := ms.mcpService.ResumeSession(ctx, ms.resume)
+	if err != nil {
+		ms.logger.Error("Resuming the saved session failed", debug.F("error", err))
+		return ConnectionCompleteMsg{Success: false, Error: err}
+	}
+	return ConnectionCompleteMsg{
+		Success:   true,
+		Resumed:   resumed,
+		Transport: config.TransportType(ms.mcpService.GetServerInfo().Transport),
+	}
+}
+
+// applyResume returns to the saved tab once the saved session is connected.
+// Selections are restored as their lists load.
+func (ms *MainScreen) applyResume(msg ConnectionCompleteMsg) {
+	if ms.resume == nil {
+		return
+	}
+	if msg.Resumed {
+		ms.SetStatus("Resumed session "+ms.resume.SessionID, StatusSuccess)
+	} else {
+		ms.SetStatus("Reconnected with the saved session state", StatusSuccess)
+	}
+	if nav := ms.resume.Navigation; nav != nil {
+		ms.activeTab = nav.Tab
+		ms.navigation = &session.Navigation{Tool: nav.Tool, Selected: make(map[int]int)}
+		for tab, idx := range nav.Selected {
+			if tab <= 2 { // Events are not restored
+				ms.navigation.Selected[tab] = idx
+			}
+		}
+	}
+	ms.resume = nil
+}
+
+// restoreNavigation selects what was selected in the saved session once the
+// tab's list has loaded
+func (ms *MainScreen) restoreNavigation(tab int) {
+	nav := ms.navigation
+	if nav == nil {
+		return
+	}
+
+	count := map[int]int{0: ms.toolCount, 1: ms.resourceCount, 2: ms.promptCount}[tab]
+	if idx, ok := nav.Selected[tab]; ok && idx < count {
+		ms.selectedIndex[tab] = idx
+	}
+	delete(nav.Selected, tab)
+	if tab == 0 && nav.Tool != "" {
+		// The tool is found by name in case the list changed
+		for i, tool := range ms.tools {
+			if tool.Name == nav.Tool {
+				ms.selectedIndex[0] = i
+				break
+			}
+		}
+		nav.Tool = ""
+	}
+
+	if len(nav.Selected) == 0 && nav.Tool == "" {
+		ms.navigation = nil
+	}
+}
+
+// startPersisting schedules saving the session, if persistence is enabled
+func (ms *MainScreen) startPersisting() tea.Cmd {
+	interval := ms.mcpService.PersistenceInterval()
+	if interval <= 0 || ms.persisting {
+		return nil
+	}
+	ms.persisting = true
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return persistTickMsg{}
+	})
+}
+
+// SaveSession saves the session with the screen's navigation, when
+// persistence is enabled and there is a connection to save
+func (ms *MainScreen) SaveSession() {
+	snapshot := ms.mcpService.SessionSnapshot()
+	if snapshot == nil {
+		return
+	}
+
+	nav := &session.Navigation{Tab: ms.activeTab, Selected: make(map[int]int)}
+	for tab, idx := range ms.selectedIndex {
+		nav.Selected[tab] = idx
+	}
+	nav.Tool = ms.selectedToolName()
+	snapshot.Navigation = nav
+
+	if err := ms.mcpService.SaveSession(snapshot); err != nil {
+		ms.logger.Error("Failed to save session", debug.F("error", err))
+	}
+}
@@ -0,0 +1,61 @@
+package screens
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/session"
+)
+
+func TestMainScreenResumesNavigation(t *testing.T) {
+	snapshot := &session.Snapshot{
+		Connection: &config.ConnectionConfig{Type: config.TransportStreamableHTTP, URL: "http://localhost:8080/mcp"},
+		SessionID:  "session-1",
+		Navigation: &session.Navigation{Tab: 1, Tool: "beta", Selected: map[int]int{0: 0, 1: 1, 2: 5, 3: 7}},
+	}
+	ms := NewResumedMainScreen(config.Default(), snapshot)
+	ms.Update(ConnectionCompleteMsg{Success: true, Resumed: true})
+	assert.Equal(t, 1, ms.activeTab)
+	assert.Contains(t, ms.statusMsg, "Resumed session session-1")
+
+	// The tool is found by name although the list changed
+	ms.Update(toolsLoaded(mcp.Tool{Name: "delta"}, mcp.Tool{Name: "alpha"}, mcp.Tool{Name: "beta"}))
+	assert.Equal(t, 2, ms.selectedIndex[0])
+
+	ms.Update(ResourcesLoadedMsg{Items: []string{"a", "b", "c"}, ActualCount: 3})
+	assert.Equal(t, 1, ms.selectedIndex[1])
+
+	// A selection beyond the list is not restored
+	ms.Update(PromptsLoadedMsg{Items: []string{"a"}, ActualCount: 1})
+	assert.Equal(t, 0, ms.selectedIndex[2])
+	assert.Nil(t, ms.navigation, "Navigation is restored once")
+}
+
+func TestResumeScreenChoices(t *testing.T) {
+	snapshot := &session.Snapshot{
+		Connection: &config.ConnectionConfig{Type: config.TransportStdio, Command: "node", Args: []string{"server.js"}},
+		Calls:      []session.CallRecord{{Tool: "echo"}},
+	}
+	fresh := NewConnectionScreen(config.Default())
+	rs := NewResumeScreen(config.Default(), snapshot, fresh)
+	assert.Contains(t, rs.View(), "node server.js")
+	assert.Contains(t, rs.View(), "1 tool calls")
+
+	transition := func(key string) Screen {
+		_, cmd := rs.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		require.NotNil(t, cmd)
+		msg, ok := cmd().(TransitionMsg)
+		require.True(t, ok)
+		return msg.Transition.Screen
+	}
+
+	main, ok := transition("y").(*MainScreen)
+	require.True(t, ok)
+	assert.Equal(t, snapshot, main.resume)
+	assert.Equal(t, fresh, transition("n"))
+}
@@ -0,0 +1,112 @@
+package screens
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+)
+
+// OpenServerMsg is sent when a connection is chosen, so the server opens in
+// a tab of its own instead of replacing the session of another
+type OpenServerMsg struct {
+	Screen *MainScreen
+}
+
+// openServer returns a command opening the server of screen
+func openServer(screen *MainScreen) tea.Cmd {
+	return func() tea.Msg {
+		return OpenServerMsg{Screen: screen}
+	}
+}
+
+// ServerState summarizes a server's connection for the server tabs
+type ServerState int
+
+const (
+	ServerConnecting ServerState = iota
+	ServerConnected
+	ServerReconnecting
+	ServerDisconnected
+	ServerFailed
+)
+
+// String returns the state name
+func (s ServerState) String() string {
+	switch s {
+	case ServerConnecting:
+		return "connecting"
+	case ServerConnected:
+		return "connected"
+	case ServerReconnecting:
+		return "reconnecting"
+	case ServerDisconnected:
+		return "disconnected"
+	case ServerFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// ServerState returns the state of the screen's connection
+func (ms *MainScreen) ServerState() ServerState {
+	switch {
+	case ms.reconnecting != nil:
+		return ServerReconnecting
+	case ms.connected:
+		return ServerConnected
+	case ms.connecting:
+		return ServerConnecting
+	case ms.LastError() != nil:
+		return ServerFailed
+	default:
+		return ServerDisconnected
+	}
+}
+
+// ServerName names the screen's server for display
+func (ms *MainScreen) ServerName() string {
+	if ms.connectionConfig == nil {
+		return "server"
+	}
+	return describeConnection(ms.connectionConfig)
+}
+
+// Service returns the service connected to the screen's server
+func (ms *MainScreen) Service() mcp.Service {
+	return ms.mcpService
+}
+
+// SelectItem shows the tab of a kind of item, 0=tools, 1=resources or
+// 2=prompts, with the named item selected. Resources are named by URI.
+func (ms *MainScreen) SelectItem(kind int, name string) {
+	ms.activeTab = kind
+	switch kind {
+	case 0:
+		for i, tool := range ms.tools {
+			if tool.Name == name {
+				ms.selectedIndex[kind] = i
+			}
+		}
+	case 1:
+		for i, resource := range ms.resourceObjects {
+			if resource.URI == name {
+				ms.selectedIndex[kind] = i
+			}
+		}
+	case 2:
+		for i, prompt := range ms.promptObjects {
+			if prompt.Name == name {
+				ms.selectedIndex[kind] = i
+			}
+		}
+	}
+}
+
+// Close disconnects from the server when its tab is closed
+func (ms *MainScreen) Close() {
+	if err := ms.mcpService.Disconnect(); err != nil {
+		ms.logger.Error("Failed to disconnect cleanly", debug.F("error", err))
+	}
+}
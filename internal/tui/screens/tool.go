@@ -19,6 +19,7 @@ import (
 
 	"github.com/standardbeagle/mcp-tui/internal/debug"
 	"github.com/standardbeagle/mcp-tui/internal/mcp"
+	"github.com/standardbeagle/mcp-tui/internal/query"
 	"github.com/standardbeagle/mcp-tui/internal/tui/components"
 )
 
@@ -52,6 +53,12 @@ type ToolScreen struct {
 	resultFields  []resultField // Parsed JSON fields
 	resultCursor  int           // Current field in result view
 
+	// Result filter, a query expression applied to the result JSON
+	filterInput  textinput.Model
+	filtering    bool   // Whether the filter box has focus
+	filterOutput string // What the filter selects from the result
+	filterErr    error  // Why the filter could not be applied
+
 	// Styles
 	titleStyle          lipgloss.Style
 	labelStyle          lipgloss.Style
@@ -90,6 +97,11 @@ func NewToolScreen(tool mcp.Tool, service mcp.Service) *ToolScreen {
 		mcpService: service,
 	}
 
+	ts.filterInput = textinput.New()
+	ts.filterInput.Placeholder = `.content[0].text | fromjson | .items[]`
+	ts.filterInput.Prompt = "/ "
+	ts.filterInput.CharLimit = 500
+
 	// Initialize styles
 	ts.initStyles()
 
@@ -350,6 +362,18 @@ func (ts *ToolScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		ts.UpdateSize(msg.Width, msg.Height)
 		return ts, nil
 
+	case ServerChangedMsg:
+		ts.SetStatus("Restarting: "+describeChange(msg.Paths), StatusInfo)
+		return ts, nil
+
+	case ServerRestartedMsg:
+		if msg.Error != nil {
+			ts.SetError(fmt.Errorf("server restart failed: %w", msg.Error))
+		} else {
+			ts.refreshTool(msg.Tools.Tools)
+		}
+		return ts, nil
+
 	case tea.KeyMsg:
 		return ts.handleKeyMsg(msg)
 
@@ -396,6 +420,7 @@ func (ts *ToolScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Parse result fields for viewing
 				ts.parseResultFields()
 			}
+			ts.applyFilter()
 
 			// Show execution count in status
 			execMsg := fmt.Sprintf("Tool executed successfully (#%d)", ts.executionCount)
@@ -443,6 +468,11 @@ func (ts *ToolScreen) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return ts, nil
 	}
 
+	// The filter box takes keys while it has focus
+	if ts.filtering {
+		return ts.handleFilterKey(msg)
+	}
+
 	// If we're in an input field, let the textinput handle most keys first
 	if ts.cursor < len(ts.fields) {
 		field := &ts.fields[ts.cursor]
@@ -539,7 +569,13 @@ func (ts *ToolScreen) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	case "ctrl+c":
 		// Copy result to clipboard if available
-		if ts.result != nil && ts.resultJSON != "" {
+		if ts.result != nil && ts.filterActive() {
+			if err := ts.copyToClipboard(ts.filterOutput); err == nil {
+				ts.SetStatus("Filtered result copied to clipboard!", StatusSuccess)
+			} else {
+				ts.SetStatus("Failed to copy to clipboard", StatusError)
+			}
+		} else if ts.result != nil && ts.resultJSON != "" {
 			if err := ts.copyToClipboard(ts.resultJSON); err == nil {
 				ts.SetStatus("Result copied to clipboard!", StatusSuccess)
 			} else {
@@ -558,6 +594,15 @@ func (ts *ToolScreen) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return ts, nil
 
+	case "/":
+		// Filter the result with a query expression
+		if ts.result != nil {
+			ts.filtering = true
+			ts.viewingResult = false
+			return ts, ts.filterInput.Focus()
+		}
+		return ts, nil
+
 	case "v":
 		// Enter result viewing mode if we have results
 		if ts.result != nil && len(ts.resultFields) > 0 {
@@ -568,7 +613,11 @@ func (ts *ToolScreen) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return ts, nil
 
 	case "esc":
-		// Go back to previous screen
+		// Clear the filter first, then go back to previous screen
+		if ts.filterInput.Value() != "" {
+			ts.clearFilter()
+			return ts, nil
+		}
 		return ts, func() tea.Msg { return BackMsg{} }
 
 	case "b", "alt+left":
@@ -1019,6 +1068,18 @@ func (ts *ToolScreen) View() string {
 				Italic(true)
 			builder.WriteString("\n")
 			builder.WriteString(viewHelpStyle.Render("↑/↓: Navigate • Enter/c/y: Copy field • Ctrl+C: Copy all • v/Esc: Exit view"))
+		} else if ts.filtering || ts.filterInput.Value() != "" {
+			// Filtered result display
+			builder.WriteString(ts.filterInput.View())
+			builder.WriteString("\n")
+			switch {
+			case ts.filterErr != nil:
+				builder.WriteString(ts.errorStyle.Render(ts.filterErr.Error()))
+			case ts.filterActive():
+				builder.WriteString(ts.resultStyle.Render(ts.filterOutput))
+			default:
+				builder.WriteString(ts.resultStyle.Render(ts.resultJSON))
+			}
 		} else {
 			// Normal result display
 			builder.WriteString(ts.resultStyle.Render(ts.resultJSON))
@@ -1078,11 +1139,15 @@ func (ts *ToolScreen) View() string {
 	if ts.viewingResult {
 		// Already shown inline help for viewing mode
 		helpText = ""
+	} else if ts.filtering {
+		helpText = "Type a query, e.g. .content[0].text.items[] | select(.status==\"open\") • Enter: Keep filter • Esc: Clear filter"
+	} else if ts.result != nil && ts.filterInput.Value() != "" {
+		helpText = "/: Edit filter • Esc: Clear filter • Ctrl+C: Copy filtered • c: CLI command • Ctrl+L: Debug Log • b/Alt+←: Back"
 	} else if ts.result != nil {
 		if len(ts.resultFields) > 1 {
-			helpText = "v: View fields • c: CLI command • Ctrl+C: Copy all • Ctrl+L: Debug Log • b/Alt+←: Back • Esc: Back"
+			helpText = "v: View fields • /: Filter • c: CLI command • Ctrl+C: Copy all • Ctrl+L: Debug Log • b/Alt+←: Back • Esc: Back"
 		} else {
-			helpText = "c: CLI command • Ctrl+C: Copy result • Ctrl+L: Debug Log • b/Alt+←: Back • Esc: Back"
+			helpText = "/: Filter • c: CLI command • Ctrl+C: Copy result • Ctrl+L: Debug Log • b/Alt+←: Back • Esc: Back"
 		}
 	} else if ts.cursor < len(ts.fields) {
 		helpText = "Tab: Navigate • Enter: Submit • c: CLI command • Ctrl+V: Paste • Ctrl+L: Debug Log • b: Back • Esc: Back"
@@ -1118,6 +1183,63 @@ func (ts *ToolScreen) View() string {
 	return builder.String()
 }
 
+// handleFilterKey edits the filter box, applying the filter as it changes
+func (ts *ToolScreen) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		ts.filtering = false
+		ts.filterInput.Blur()
+		return ts, nil
+	case "esc":
+		ts.clearFilter()
+		return ts, nil
+	case "ctrl+c":
+		return ts, func() tea.Msg { return BackMsg{} }
+	}
+
+	var cmd tea.Cmd
+	ts.filterInput, cmd = ts.filterInput.Update(msg)
+	ts.applyFilter()
+	return ts, cmd
+}
+
+// applyFilter runs the filter expression against the current result
+func (ts *ToolScreen) applyFilter() {
+	ts.filterOutput, ts.filterErr = "", nil
+	expr := strings.TrimSpace(ts.filterInput.Value())
+	if expr == "" || ts.result == nil {
+		return
+	}
+
+	q, err := query.Parse(expr)
+	if err != nil {
+		ts.filterErr = err
+		return
+	}
+	results, err := q.Run(ts.result)
+	if err != nil {
+		ts.filterErr = err
+		return
+	}
+	if len(results) == 0 {
+		ts.filterOutput = "(no results)"
+		return
+	}
+	ts.filterOutput, ts.filterErr = query.FormatText(results)
+}
+
+// filterActive reports whether a filter has selected something to show
+func (ts *ToolScreen) filterActive() bool {
+	return ts.filterErr == nil && ts.filterOutput != ""
+}
+
+func (ts *ToolScreen) clearFilter() {
+	ts.filtering = false
+	ts.filterInput.Blur()
+	ts.filterInput.SetValue("")
+	ts.filterOutput, ts.filterErr = "", nil
+}
+
 // parseResultFields extracts copyable fields from JSON result
 func (ts *ToolScreen) parseResultFields() {
 	ts.resultFields = []resultField{}
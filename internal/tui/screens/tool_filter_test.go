@@ -0,0 +1,72 @@
+package screens
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func typeFilter(ts *ToolScreen, text string) {
+	for _, r := range text {
+		ts.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+func TestToolResultFilter(t *testing.T) {
+	ts := NewToolScreen(mcp.Tool{Name: "issues"}, nil)
+	ts.Update(toolExecutionCompleteMsg{
+		Result: &mcp.CallToolResult{
+			Content: []mcp.Content{
+				{Type: "text", Text: `{"items": [{"id": 1, "status": "open"}, {"id": 2, "status": "closed"}]}`},
+			},
+		},
+	})
+
+	ts.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	require.True(t, ts.filtering, "/ should open the filter box")
+
+	typeFilter(ts, `.content[0].text | fromjson | .items[] | select(.status=="open") | .id`)
+	assert.NoError(t, ts.filterErr)
+	assert.Equal(t, "1", ts.filterOutput)
+	assert.Contains(t, ts.View(), "select(.status==")
+
+	// Enter keeps the filter, which applies to the next result too
+	ts.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, ts.filtering)
+	ts.Update(toolExecutionCompleteMsg{
+		Result: &mcp.CallToolResult{
+			Content: []mcp.Content{{Type: "text", Text: `{"items": [{"id": 7, "status": "open"}]}`}},
+		},
+	})
+	assert.Equal(t, "7", ts.filterOutput)
+
+	// Esc clears the filter before it leaves the screen
+	_, cmd := ts.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Nil(t, cmd)
+	assert.Empty(t, ts.filterInput.Value())
+	assert.Empty(t, ts.filterOutput)
+}
+
+func TestToolResultFilterErrors(t *testing.T) {
+	ts := NewToolScreen(mcp.Tool{Name: "echo"}, nil)
+	ts.Update(toolExecutionCompleteMsg{
+		Result: &mcp.CallToolResult{Content: []mcp.Content{{Type: "text", Text: "plain text"}}},
+	})
+
+	ts.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	typeFilter(ts, ".content[0].text.items")
+	require.Error(t, ts.filterErr)
+	assert.Contains(t, ts.View(), "cannot index string")
+
+	// An incomplete expression is an error until it is finished
+	ts.clearFilter()
+	ts.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	typeFilter(ts, ".content[")
+	assert.Error(t, ts.filterErr)
+	typeFilter(ts, "0].type")
+	assert.NoError(t, ts.filterErr)
+	assert.Equal(t, "text", ts.filterOutput)
+}
@@ -0,0 +1,308 @@
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/proxy"
+)
+
+// TrafficScreen shows the live traffic of a running proxy
+type TrafficScreen struct {
+	*BaseScreen
+
+	socketPath string
+	attachment *proxy.Attachment
+	attached   bool
+
+	entries       []debug.MCPLogEntry
+	selectedIndex int
+	scrollOffset  int
+	follow        bool // Keep the newest message selected
+	showDetail    bool
+
+	titleStyle    lipgloss.Style
+	logStyle      lipgloss.Style
+	selectedStyle lipgloss.Style
+	detailStyle   lipgloss.Style
+	helpStyle     lipgloss.Style
+}
+
+// trafficAttachedMsg reports the outcome of attaching to the proxy
+type trafficAttachedMsg struct {
+	attachment *proxy.Attachment
+	err        error
+}
+
+// trafficEntryMsg carries one relayed message
+type trafficEntryMsg struct {
+	entry debug.MCPLogEntry
+}
+
+// trafficDetachedMsg reports that the proxy went away
+type trafficDetachedMsg struct {
+	err error
+}
+
+// NewTrafficScreen creates a screen attached to the proxy monitor at socketPath
+func NewTrafficScreen(socketPath string) *TrafficScreen {
+	ts := &TrafficScreen{
+		BaseScreen: NewBaseScreen("Traffic", false),
+		socketPath: socketPath,
+		follow:     true,
+	}
+
+	ts.titleStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")).
+		Bold(true).
+		Margin(1, 0)
+
+	ts.logStyle = lipgloss.NewStyle().
+		Padding(0, 1).
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("8"))
+
+	ts.selectedStyle = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("0")).
+		Background(lipgloss.Color("6")).
+		Bold(true)
+
+	ts.detailStyle = lipgloss.NewStyle().
+		Padding(1).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("12"))
+
+	ts.helpStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	return ts
+}
+
+// Init attaches to the proxy
+func (ts *TrafficScreen) Init() tea.Cmd {
+	socketPath := ts.socketPath
+	return func() tea.Msg {
+		attachment, err := proxy.Attach(socketPath)
+		return trafficAttachedMsg{attachment: attachment, err: err}
+	}
+}
+
+// waitForEntry returns a command that delivers the next relayed message
+func (ts *TrafficScreen) waitForEntry() tea.Cmd {
+	attachment := ts.attachment
+	return func() tea.Msg {
+		entry, err := attachment.Next()
+		if err != nil {
+			return trafficDetachedMsg{err: err}
+		}
+		return trafficEntryMsg{entry: entry}
+	}
+}
+
+// Update handles messages for the traffic screen
+func (ts *TrafficScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		ts.UpdateSize(msg.Width, msg.Height)
+		ts.adjustScrollOffset()
+		return ts, nil
+
+	case trafficAttachedMsg:
+		if msg.err != nil {
+			ts.SetError(msg.err)
+			return ts, nil
+		}
+		ts.attachment = msg.attachment
+		ts.attached = true
+		ts.SetStatus("Attached to "+ts.socketPath, StatusSuccess)
+		return ts, ts.waitForEntry()
+
+	case trafficEntryMsg:
+		ts.entries = append(ts.entries, msg.entry)
+		if ts.follow {
+			ts.selectedIndex = len(ts.entries) - 1
+			ts.adjustScrollOffset()
+		}
+		return ts, ts.waitForEntry()
+
+	case trafficDetachedMsg:
+		ts.attached = false
+		ts.attachment.Close()
+		ts.SetStatus("Proxy disconnected", StatusWarning)
+		return ts, nil
+
+	case tea.KeyMsg:
+		return ts.handleKeyMsg(msg)
+	}
+
+	return ts, nil
+}
+
+// handleKeyMsg handles keyboard input
+func (ts *TrafficScreen) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if ts.showDetail {
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return ts, tea.Quit
+		case "b", "esc", "enter", "alt+left":
+			ts.showDetail = false
+		}
+		return ts, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q", "esc":
+		if ts.attachment != nil {
+			ts.attachment.Close()
+		}
+		return ts, tea.Quit
+
+	case "up", "k":
+		ts.moveSelection(-1)
+	case "down", "j":
+		ts.moveSelection(1)
+	case "pgup":
+		ts.moveSelection(-10)
+	case "pgdown":
+		ts.moveSelection(10)
+	case "home", "g":
+		ts.moveSelection(-len(ts.entries))
+	case "end", "G":
+		ts.follow = true
+		ts.selectedIndex = max(0, len(ts.entries)-1)
+		ts.adjustScrollOffset()
+
+	case "f":
+		ts.follow = !ts.follow
+		if ts.follow {
+			ts.selectedIndex = max(0, len(ts.entries)-1)
+			ts.adjustScrollOffset()
+		}
+
+	case "x":
+		ts.entries = nil
+		ts.selectedIndex = 0
+		ts.scrollOffset = 0
+
+	case "enter":
+		if ts.selectedIndex < len(ts.entries) {
+			ts.showDetail = true
+		}
+	}
+
+	return ts, nil
+}
+
+// moveSelection moves the selection, leaving follow mode unless it lands on the newest message
+func (ts *TrafficScreen) moveSelection(offset int) {
+	if len(ts.entries) == 0 {
+		return
+	}
+	ts.selectedIndex = min(max(ts.selectedIndex+offset, 0), len(ts.entries)-1)
+	ts.follow = ts.selectedIndex == len(ts.entries)-1
+	ts.adjustScrollOffset()
+}
+
+// visibleRows returns how many messages fit on screen
+func (ts *TrafficScreen) visibleRows() int {
+	if ts.Height() == 0 {
+		return 18
+	}
+	return max(ts.Height()-12, 3)
+}
+
+// adjustScrollOffset keeps the selected message visible
+func (ts *TrafficScreen) adjustScrollOffset() {
+	rows := ts.visibleRows()
+	if ts.selectedIndex < ts.scrollOffset {
+		ts.scrollOffset = ts.selectedIndex
+	} else if ts.selectedIndex >= ts.scrollOffset+rows {
+		ts.scrollOffset = ts.selectedIndex - rows + 1
+	}
+}
+
+// View renders the traffic screen
+func (ts *TrafficScreen) View() string {
+	var builder strings.Builder
+
+	builder.WriteString(ts.titleStyle.Render("📡 MCP Proxy Traffic"))
+	builder.WriteString("\n")
+
+	if ts.showDetail && ts.selectedIndex < len(ts.entries) {
+		entry := ts.entries[ts.selectedIndex]
+		builder.WriteString(fmt.Sprintf("%s | Direction: %s | Type: %s",
+			entry.Timestamp.Format("15:04:05.000"), entry.Direction, entry.MessageType))
+		if entry.Method != "" {
+			builder.WriteString(" | Method: " + entry.Method)
+		}
+		builder.WriteString("\n")
+		builder.WriteString(ts.detailStyle.Render(entry.GetFormattedJSON()))
+		builder.WriteString("\n")
+		builder.WriteString(ts.helpStyle.Render("b/Esc/Enter: Back • q/Ctrl+C: Quit"))
+		return builder.String()
+	}
+
+	builder.WriteString(ts.renderSummary())
+	builder.WriteString("\n")
+
+	if len(ts.entries) == 0 {
+		builder.WriteString(ts.logStyle.Render("Waiting for traffic..."))
+	} else {
+		end := min(ts.scrollOffset+ts.visibleRows(), len(ts.entries))
+		var lines []string
+		for i := ts.scrollOffset; i < end; i++ {
+			line := ts.entries[i].DetailedString()
+			if i == ts.selectedIndex {
+				lines = append(lines, ts.selectedStyle.Render("▶ "+line))
+			} else {
+				lines = append(lines, "  "+line)
+			}
+		}
+		builder.WriteString(ts.logStyle.Render(strings.Join(lines, "\n")))
+	}
+
+	builder.WriteString("\n")
+	builder.WriteString(ts.helpStyle.Render("↑↓: Navigate • Enter: Details • f: Follow • x: Clear • q/Esc: Quit"))
+
+	if statusMsg, level := ts.StatusMessage(); statusMsg != "" {
+		color := map[StatusLevel]string{StatusSuccess: "10", StatusWarning: "11", StatusError: "9"}[level]
+		if color == "" {
+			color = "12"
+		}
+		builder.WriteString("\n")
+		builder.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(true).Render(statusMsg))
+	}
+
+	return builder.String()
+}
+
+// renderSummary renders the connection state and message counts
+func (ts *TrafficScreen) renderSummary() string {
+	var requests, responses, notifications, errors int
+	for _, entry := range ts.entries {
+		switch entry.MessageType {
+		case debug.MCPMessageRequest:
+			requests++
+		case debug.MCPMessageResponse:
+			responses++
+		case debug.MCPMessageNotification:
+			notifications++
+		case debug.MCPMessageError:
+			errors++
+		}
+	}
+
+	state := "🔴 detached"
+	if ts.attached {
+		state = "🟢 attached"
+	}
+	follow := ""
+	if ts.follow {
+		follow = " • following"
+	}
+	return fmt.Sprintf("%s • %d messages: %d requests, %d responses, %d notifications, %d errors%s",
+		state, len(ts.entries), requests, responses, notifications, errors, follow)
+}
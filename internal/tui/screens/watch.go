@@ -0,0 +1,195 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+)
+
+// ServerChangedMsg reports that watched server files changed, so the server
+// should be restarted
+type ServerChangedMsg struct {
+	Paths []string
+}
+
+// ServerRestartedMsg reports the outcome of a watch mode restart, with the
+// server's lists as reloaded after reinitializing
+type ServerRestartedMsg struct {
+	Error     error
+	Tools     ToolsLoadedMsg
+	Resources ResourcesLoadedMsg
+	Prompts   PromptsLoadedMsg
+}
+
+// startRestart restarts the server after the given files changed. The tab,
+// selections and any open tool form are kept.
+func (ms *MainScreen) startRestart(paths []string) tea.Cmd {
+	if ms.restarting || (ms.connecting && !ms.connected && ms.reconnecting == nil) {
+		// Restart again once the current attempt is over
+		ms.restartPending = true
+		return nil
+	}
+
+	ms.logger.Info("Restarting server after file changes", debug.F("paths", paths))
+	ms.restarting = true
+	ms.reconnecting = nil // Restarting replaces any reconnection
+	ms.connecting = true
+	ms.connectingStart = time.Now()
+	ms.connectionStatus = "Restarting: " + describeChange(paths)
+
+	return tea.Batch(
+		ms.restartServer(),
+		tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
+			return spinnerTickMsg{}
+		}),
+	)
+}
+
+// restartServer reconnects, which starts a fresh server process, and reloads
+// the lists in one step so they arrive even while another screen is showing
+func (ms *MainScreen) restartServer() tea.Cmd {
+	return func() tea.Msg {
+		if err := ms.mcpService.Disconnect(); err != nil {
+			ms.logger.Warn("Disconnect before restart failed", debug.F("error", err))
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := ms.mcpService.Connect(ctx, ms.connectionConfig); err != nil {
+			return ServerRestartedMsg{Error: err}
+		}
+
+		return ServerRestartedMsg{
+			Tools:     ms.loadTools()().(ToolsLoadedMsg),
+			Resources: ms.loadResources()().(ResourcesLoadedMsg),
+			Prompts:   ms.loadPrompts()().(PromptsLoadedMsg),
+		}
+	}
+}
+
+// finishRestart shows the restarted server's lists, keeping the selections
+func (ms *MainScreen) finishRestart(msg ServerRestartedMsg) tea.Cmd {
+	ms.restarting = false
+	ms.connecting = false
+
+	if msg.Error != nil {
+		ms.connected = false
+		ms.connectionStatus = fmt.Sprintf("Restart failed: %v", msg.Error)
+		ms.SetError(msg.Error)
+	} else {
+		selectedTool := ms.selectedToolName()
+
+		ms.connected = true
+		ms.connectionStatus = fmt.Sprintf("Connected to %s %s",
+			ms.connectionConfig.Command, strings.Join(ms.connectionConfig.Args, " "))
+		ms.Update(msg.Tools)
+		ms.Update(msg.Resources)
+		ms.Update(msg.Prompts)
+
+		ms.restoreSelections(selectedTool)
+		ms.SetStatus("Server restarted", StatusSuccess)
+	}
+
+	if ms.restartPending {
+		ms.restartPending = false
+		return ms.startRestart(nil)
+	}
+	return nil
+}
+
+// selectedToolName returns the name of the selected tool, if any
+func (ms *MainScreen) selectedToolName() string {
+	if idx, ok := ms.selectedIndex[0]; ok && idx < len(ms.tools) {
+		return ms.tools[idx].Name
+	}
+	return ""
+}
+
+// restoreSelections selects the same tool as before a restart, wherever it
+// is now listed, and keeps the other selections within their lists
+func (ms *MainScreen) restoreSelections(toolName string) {
+	for i, tool := range ms.tools {
+		if tool.Name == toolName {
+			ms.selectedIndex[0] = i
+			break
+		}
+	}
+
+	for tab, count := range map[int]int{0: ms.toolCount, 1: ms.resourceCount, 2: ms.promptCount} {
+		if idx, ok := ms.selectedIndex[tab]; ok && idx >= count {
+			if count == 0 {
+				delete(ms.selectedIndex, tab)
+			} else {
+				ms.selectedIndex[tab] = count - 1
+			}
+		}
+	}
+}
+
+// describeChange summarizes changed paths for status messages
+func describeChange(paths []string) string {
+	switch len(paths) {
+	case 0:
+		return "files changed"
+	case 1:
+		return filepath.Base(paths[0]) + " changed"
+	default:
+		return fmt.Sprintf("%s and %d more changed", filepath.Base(paths[0]), len(paths)-1)
+	}
+}
+
+// refreshTool follows changes the restarted server made to the tool, keeping
+// the values already filled in for arguments that still exist
+func (ts *ToolScreen) refreshTool(tools []mcp.Tool) {
+	var updated *mcp.Tool
+	for i := range tools {
+		if tools[i].Name == ts.tool.Name {
+			updated = &tools[i]
+			break
+		}
+	}
+	if updated == nil {
+		ts.SetStatus(fmt.Sprintf("Server restarted: tool %s is no longer offered", ts.tool.Name), StatusWarning)
+		return
+	}
+
+	values := make(map[string]string, len(ts.fields))
+	for _, field := range ts.fields {
+		values[field.name] = field.input.Value()
+	}
+	// The cursor follows its field, or stays on the same button below them
+	current, button := "", -1
+	if ts.cursor < len(ts.fields) {
+		current = ts.fields[ts.cursor].name
+	} else {
+		button = ts.cursor - len(ts.fields)
+	}
+
+	ts.tool = *updated
+	ts.parseSchema()
+
+	ts.cursor = 0
+	if button >= 0 {
+		ts.cursor = len(ts.fields) + button
+	}
+	for i := range ts.fields {
+		if value := values[ts.fields[i].name]; value != "" {
+			ts.fields[i].input.SetValue(value)
+			ts.validateField(i)
+		}
+		if ts.fields[i].name == current {
+			ts.cursor = i
+		}
+	}
+	if ts.cursor < len(ts.fields) {
+		ts.fields[ts.cursor].input.Focus()
+	}
+	ts.SetStatus("Server restarted", StatusSuccess)
+}
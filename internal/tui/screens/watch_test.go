@@ -0,0 +1,93 @@
+package screens
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/standardbeagle/mcp-tui/internal/config"
+	"github.com/standardbeagle/mcp-tui/internal/mcp"
+)
+
+func toolsLoaded(tools ...mcp.Tool) ToolsLoadedMsg {
+	items := make([]string, len(tools))
+	for i, tool := range tools {
+		items[i] = tool.Name
+	}
+	return ToolsLoadedMsg{Tools: tools, Items: items, ActualCount: len(tools)}
+}
+
+func TestMainScreenRestartKeepsState(t *testing.T) {
+	ms := NewMainScreen(config.Default(), &config.ConnectionConfig{Type: config.TransportStdio, Command: "node"})
+	ms.connecting = false
+	ms.connected = true
+	ms.Update(toolsLoaded(mcp.Tool{Name: "alpha"}, mcp.Tool{Name: "beta"}, mcp.Tool{Name: "gamma"}))
+	ms.selectedIndex[0] = 1
+	ms.activeTab = 0
+
+	ms.startRestart([]string{"/src/server.go"})
+	assert.True(t, ms.restarting)
+	assert.Contains(t, ms.connectionStatus, "server.go changed")
+
+	// A change during the restart is handled after it
+	assert.Nil(t, ms.startRestart([]string{"/src/tools.go"}))
+	assert.True(t, ms.restartPending)
+
+	// The server now lists a new tool first; beta stays selected
+	cmd := ms.finishRestart(ServerRestartedMsg{
+		Tools: toolsLoaded(mcp.Tool{Name: "delta"}, mcp.Tool{Name: "alpha"}, mcp.Tool{Name: "beta"}),
+	})
+	assert.True(t, ms.connected)
+	assert.Equal(t, 0, ms.activeTab)
+	assert.Equal(t, 2, ms.selectedIndex[0])
+	assert.Equal(t, "beta", ms.selectedToolName())
+
+	// The pending change restarts again
+	assert.NotNil(t, cmd)
+	assert.True(t, ms.restarting)
+	assert.False(t, ms.restartPending)
+
+	ms.finishRestart(ServerRestartedMsg{Error: fmt.Errorf("exit status 1")})
+	assert.False(t, ms.connected)
+	assert.False(t, ms.restarting)
+	assert.Contains(t, ms.connectionStatus, "Restart failed")
+}
+
+func TestToolScreenRefreshKeepsValues(t *testing.T) {
+	schema := func(props ...string) map[string]interface{} {
+		properties := make(map[string]interface{})
+		for _, prop := range props {
+			properties[prop] = map[string]interface{}{"type": "string"}
+		}
+		return map[string]interface{}{"type": "object", "properties": properties}
+	}
+
+	ts := NewToolScreen(mcp.Tool{Name: "search", InputSchema: schema("query", "limit")}, nil)
+	require.Len(t, ts.fields, 2)
+	for i := range ts.fields {
+		ts.fields[i].input.SetValue("value of " + ts.fields[i].name)
+		if ts.fields[i].name == "limit" {
+			ts.cursor = i
+		}
+	}
+
+	// The rebuilt server drops limit and adds lang
+	ts.Update(ServerRestartedMsg{Tools: toolsLoaded(
+		mcp.Tool{Name: "other"},
+		mcp.Tool{Name: "search", InputSchema: schema("query", "lang")},
+	)})
+
+	values := make(map[string]string)
+	for _, field := range ts.fields {
+		values[field.name] = field.input.Value()
+	}
+	assert.Equal(t, map[string]string{"query": "value of query", "lang": ""}, values)
+	assert.Equal(t, 0, ts.cursor, "Cursor moves to the first field when its field is gone")
+	assert.Contains(t, ts.statusMsg, "Server restarted")
+
+	ts.Update(ServerRestartedMsg{Tools: toolsLoaded(mcp.Tool{Name: "other"})})
+	assert.Contains(t, ts.statusMsg, "no longer offered")
+	assert.Len(t, ts.fields, 2, "The form is kept when the tool disappears")
+}
@@ -0,0 +1,284 @@
+// Package watch polls files for changes so a server under development can be
+// restarted after every rebuild
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultInterval is how often the watched files are scanned
+	DefaultInterval = 500 * time.Millisecond
+
+	// DefaultSettle is how long the files must stay unchanged before a change
+	// is reported, so a rebuild writing many files restarts the server once
+	DefaultSettle = 300 * time.Millisecond
+)
+
+// skippedDirs are never descended into when walking for a pattern
+var skippedDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+}
+
+// Watcher reports changes to the files matching a set of patterns. A pattern
+// is a file, a directory (watched recursively), or a glob in which "**"
+// matches any number of directories, e.g. "src/**/*.go".
+type Watcher struct {
+	patterns []pattern
+	interval time.Duration
+	settle   time.Duration
+}
+
+// pattern is a parsed watch pattern
+type pattern struct {
+	raw      string
+	root     string   // Directory or file the pattern is rooted at
+	segments []string // Slash-separated glob segments, nil for a plain path
+}
+
+// fileState is what a scan remembers about a file
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// New creates a watcher for the given patterns
+func New(patterns []string) (*Watcher, error) {
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("no paths to watch")
+	}
+
+	w := &Watcher{interval: DefaultInterval, settle: DefaultSettle}
+	for _, raw := range patterns {
+		p, err := parsePattern(raw)
+		if err != nil {
+			return nil, err
+		}
+		w.patterns = append(w.patterns, p)
+	}
+	return w, nil
+}
+
+// parsePattern splits a pattern into the directory to walk and the glob to match
+func parsePattern(raw string) (pattern, error) {
+	cleaned := filepath.ToSlash(filepath.Clean(strings.TrimSpace(raw)))
+	if cleaned == "." && strings.TrimSpace(raw) == "" {
+		return pattern{}, fmt.Errorf("empty watch pattern")
+	}
+
+	segments := strings.Split(cleaned, "/")
+	glob := -1
+	for i, segment := range segments {
+		if segment != "**" {
+			if _, err := path.Match(segment, ""); err != nil {
+				return pattern{}, fmt.Errorf("invalid watch pattern %q: %w", raw, err)
+			}
+		}
+		if glob < 0 && strings.ContainsAny(segment, "*?[") {
+			glob = i
+		}
+	}
+
+	if glob < 0 {
+		return pattern{raw: raw, root: filepath.FromSlash(cleaned)}, nil
+	}
+
+	root := strings.Join(segments[:glob], "/")
+	switch {
+	case glob == 0:
+		root = "."
+	case root == "":
+		root = "/"
+	}
+	return pattern{raw: raw, root: filepath.FromSlash(root), segments: segments}, nil
+}
+
+// Patterns returns the patterns being watched
+func (w *Watcher) Patterns() []string {
+	patterns := make([]string, len(w.patterns))
+	for i, p := range w.patterns {
+		patterns[i] = p.raw
+	}
+	return patterns
+}
+
+// Watch scans the files until ctx is done, sending the paths that changed
+// once they have settled. The channel is closed when ctx is done.
+func (w *Watcher) Watch(ctx context.Context) <-chan []string {
+	changes := make(chan []string)
+
+	go func() {
+		defer close(changes)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		previous := w.scan()
+		pending := make(map[string]bool)
+		var lastChange time.Time
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current := w.scan()
+			if changed := diff(previous, current); len(changed) > 0 {
+				for _, name := range changed {
+					pending[name] = true
+				}
+				lastChange = time.Now()
+				previous = current
+				continue
+			}
+			previous = current
+
+			if len(pending) == 0 || time.Since(lastChange) < w.settle {
+				continue
+			}
+
+			paths := make([]string, 0, len(pending))
+			for name := range pending {
+				paths = append(paths, name)
+			}
+			sort.Strings(paths)
+			pending = make(map[string]bool)
+
+			select {
+			case changes <- paths:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return changes
+}
+
+// scan records the state of every file matching the patterns
+func (w *Watcher) scan() map[string]fileState {
+	files := make(map[string]fileState)
+	for _, p := range w.patterns {
+		p.scan(files)
+	}
+	return files
+}
+
+// scan adds the files matching the pattern to files
+func (p pattern) scan(files map[string]fileState) {
+	if p.segments == nil {
+		// Follow a symlinked file, as binaries are often linked into place
+		info, err := os.Stat(p.root)
+		if err != nil {
+			return
+		}
+		if !info.IsDir() {
+			files[p.root] = fileState{size: info.Size(), modTime: info.ModTime()}
+			return
+		}
+	}
+
+	filepath.WalkDir(p.root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Missing files are fine: they are reported when they appear
+			return nil
+		}
+
+		if entry.IsDir() {
+			if name == p.root {
+				return nil
+			}
+			if skippedDirs[entry.Name()] {
+				return filepath.SkipDir
+			}
+			if p.segments != nil && !matchPrefix(p.segments, splitPath(name)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if p.segments != nil && !match(p.segments, splitPath(name)) {
+			return nil
+		}
+		if info, err := entry.Info(); err == nil {
+			files[name] = fileState{size: info.Size(), modTime: info.ModTime()}
+		}
+		return nil
+	})
+}
+
+// splitPath splits a file path into slash-separated segments
+func splitPath(name string) []string {
+	return strings.Split(filepath.ToSlash(filepath.Clean(name)), "/")
+}
+
+// match reports whether the path segments match the glob segments
+func match(segments, names []string) bool {
+	if len(segments) == 0 {
+		return len(names) == 0
+	}
+	if segments[0] == "**" {
+		for i := 0; i <= len(names); i++ {
+			if match(segments[1:], names[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(names) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(segments[0], names[0]); !ok {
+		return false
+	}
+	return match(segments[1:], names[1:])
+}
+
+// matchPrefix reports whether files below the directory could match the glob
+func matchPrefix(segments, names []string) bool {
+	if len(names) == 0 || (len(names) == 1 && names[0] == ".") {
+		return true
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if segments[0] == "**" {
+		return true
+	}
+	if ok, _ := path.Match(segments[0], names[0]); !ok {
+		return false
+	}
+	return matchPrefix(segments[1:], names[1:])
+}
+
+// diff returns the files that were added, removed or modified between scans
+func diff(previous, current map[string]fileState) []string {
+	var changed []string
+	for name, state := range current {
+		if old, ok := previous[name]; !ok || old != state {
+			changed = append(changed, name)
+		}
+	}
+	for name := range previous {
+		if _, ok := current[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// Exists reports whether any file currently matches the patterns
+func (w *Watcher) Exists() bool {
+	return len(w.scan()) > 0
+}
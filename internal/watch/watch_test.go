@@ -0,0 +1,137 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		root    string
+		glob    bool
+	}{
+		{"server.js", "server.js", false},
+		{"./bin/server", filepath.FromSlash("bin/server"), false},
+		{"*.go", ".", true},
+		{"src/**/*.go", "src", true},
+		{"/opt/app/*.py", filepath.FromSlash("/opt/app"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			p, err := parsePattern(tt.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tt.root, p.root)
+			assert.Equal(t, tt.glob, p.segments != nil)
+		})
+	}
+
+	_, err := parsePattern("src/[")
+	assert.Error(t, err)
+	_, err = New(nil)
+	assert.Error(t, err)
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "cmd/main.go", false},
+		{"src/**/*.go", "src/main.go", true},
+		{"src/**/*.go", "src/a/b/main.go", true},
+		{"src/**/*.go", "lib/main.go", false},
+		{"**", "anything/at/all", true},
+		{"src/**/test/*.js", "src/x/test/a.js", true},
+		{"src/**/test/*.js", "src/x/a.js", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, match(splitPath(tt.pattern), splitPath(tt.path)))
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"main.go", "lib/util.go", "lib/README.md", "node_modules/dep/index.go", ".git/HEAD"} {
+		writeFile(t, filepath.Join(dir, name), "x")
+	}
+
+	w, err := New([]string{filepath.Join(dir, "**/*.go"), filepath.Join(dir, "lib")})
+	require.NoError(t, err)
+
+	var names []string
+	for name := range w.scan() {
+		rel, err := filepath.Rel(dir, name)
+		require.NoError(t, err)
+		names = append(names, filepath.ToSlash(rel))
+	}
+	assert.ElementsMatch(t, []string{"main.go", "lib/util.go", "lib/README.md"}, names)
+	assert.True(t, w.Exists())
+}
+
+func TestWatchReportsSettledChanges(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "server.go")
+	writeFile(t, source, "v1")
+
+	w, err := New([]string{filepath.Join(dir, "*.go")})
+	require.NoError(t, err)
+	w.interval = 20 * time.Millisecond
+	w.settle = 60 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := w.Watch(ctx)
+
+	// Let the first scan happen before changing anything
+	time.Sleep(50 * time.Millisecond)
+
+	// A burst of writes is reported once
+	added := filepath.Join(dir, "tools.go")
+	writeFile(t, source, "version 2")
+	writeFile(t, added, "new")
+
+	select {
+	case paths := <-changes:
+		assert.Equal(t, []string{source, added}, paths)
+	case <-time.After(5 * time.Second):
+		t.Fatal("No change reported")
+	}
+
+	select {
+	case paths := <-changes:
+		t.Fatalf("Unexpected second change: %v", paths)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// Removing a file is a change too
+	require.NoError(t, os.Remove(added))
+	select {
+	case paths := <-changes:
+		assert.Equal(t, []string{added}, paths)
+	case <-time.After(5 * time.Second):
+		t.Fatal("No change reported for the removed file")
+	}
+
+	cancel()
+	for range changes {
+	}
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+	require.NoError(t, os.WriteFile(name, []byte(content), 0o644))
+}
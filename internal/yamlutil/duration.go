@@ -0,0 +1,35 @@
+// Package yamlutil holds the types shared by the YAML and JSON files mcp-tui
+// reads: scenarios, mock server definitions and chaos fault schedules.
+package yamlutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration accepts Go duration strings ("250ms", "2s") or milliseconds
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch val := v.(type) {
+	case float64:
+		*d = Duration(time.Duration(val) * time.Millisecond)
+	case string:
+		parsed, err := time.ParseDuration(val)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", val, err)
+		}
+		*d = Duration(parsed)
+	case nil:
+		*d = 0
+	default:
+		return fmt.Errorf("invalid duration %v", v)
+	}
+	return nil
+}
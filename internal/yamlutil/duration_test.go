@@ -0,0 +1,32 @@
+package yamlutil
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDurationUnmarshal(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{`"250ms"`, 250 * time.Millisecond},
+		{`"2s"`, 2 * time.Second},
+		{`1500`, 1500 * time.Millisecond},
+		{`null`, 0},
+	}
+	for _, tt := range tests {
+		var d Duration
+		require.NoError(t, json.Unmarshal([]byte(tt.input), &d), tt.input)
+		assert.Equal(t, tt.want, time.Duration(d), tt.input)
+	}
+
+	for _, input := range []string{`"soon"`, `true`, `[1]`} {
+		var d Duration
+		assert.Error(t, json.Unmarshal([]byte(input), &d), input)
+	}
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
+	"strings"
 	"syscall"
 
 	"github.com/spf13/cobra"
@@ -11,8 +13,12 @@ import (
 	"github.com/standardbeagle/mcp-tui/internal/cli"
 	"github.com/standardbeagle/mcp-tui/internal/config"
 	"github.com/standardbeagle/mcp-tui/internal/debug"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/proxy"
+	"github.com/standardbeagle/mcp-tui/internal/mcp/transports"
 	platformSignal "github.com/standardbeagle/mcp-tui/internal/platform/signal"
 	"github.com/standardbeagle/mcp-tui/internal/tui/app"
+	"github.com/standardbeagle/mcp-tui/internal/tui/screens"
+	"github.com/standardbeagle/mcp-tui/internal/watch"
 )
 
 var (
@@ -27,6 +33,12 @@ func main() {
 	// Initialize configuration
 	cfg = config.Default()
 
+	// --watch is taken out first so it may appear anywhere, even before the
+	// connection string
+	watchPatterns, args := cli.SplitWatchFlag(os.Args[1:])
+	cfg.Watch = watchPatterns
+	os.Args = append([]string{os.Args[0]}, args...)
+
 	// Early parse to check for connection string pattern
 	// This allows: mcp-tui "server command" tool list
 	if len(os.Args) > 1 {
@@ -48,6 +60,9 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Stdio servers and the processes they spawned must not outlive mcp-tui
+	defer transports.CloseServerProcesses()
+
 	// Set up signal handling
 	sigHandler := platformSignal.NewHandler()
 	sigHandler.Register(func(sig os.Signal) {
@@ -57,6 +72,16 @@ func main() {
 	sigHandler.Start()
 	defer sigHandler.Stop()
 
+	// In watch mode a CLI command is run again after every change, each run
+	// starting its own server
+	if len(cfg.Watch) > 0 && config.HasSubcommand(os.Args[1:]) {
+		if err := runWatchMode(ctx, os.Args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Create root command
 	rootCmd := createRootCommand(ctx)
 
@@ -77,8 +102,9 @@ func main() {
 	}
 
 	// Execute
-	if err := rootCmd.Execute(); err != nil {
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		debug.Error("Application failed", debug.F("error", err))
+		transports.CloseServerProcesses()
 		os.Exit(1)
 	}
 }
@@ -97,6 +123,48 @@ Examples:
   
   # Connect to HTTP/SSE server
   mcp-tui --url http://localhost:8000/mcp
+
+  # Record a session, then replay it without the server
+  mcp-tui "npx -y @modelcontextprotocol/server-everything stdio" tool list --record session.ndjson
+  mcp-tui replay session.ndjson tool list
+
+  # Inject faults between the client and a server
+  mcp-tui "mcp-tui chaos --fault drop,probability=0.1 -- node server.js" tool list
+
+  # Capture a real client's traffic, and watch it from another terminal
+  mcp-tui proxy -- node server.js
+  mcp-tui attach
+
+  # Restart the server whenever it is rebuilt, keeping the TUI state
+  mcp-tui --watch "src/**/*.go,bin/server" "./bin/server"
+
+  # Re-run a tool call against every rebuild
+  mcp-tui --watch "src/**/*.go" "go run ./cmd/server" tool call echo message=hi
+
+  # Keep one connection open and run commands at a prompt
+  mcp-tui "node server.js" shell
+
+  # Run test scenarios in CI and write a JUnit report
+  mcp-tui test tests/*.yaml --report junit -o report.xml
+
+  # Check a server for protocol conformance
+  mcp-tui "node server.js" check
+
+  # Guard a server's tools, resources and prompts against silent changes
+  mcp-tui "node server.js" snapshot save testdata/golden
+  mcp-tui "node server.js" snapshot verify testdata/golden
+
+  # Review a release for breaking changes to tool contracts
+  mcp-tui diff "node server-v1.js" "node server-v2.js"
+
+  # Measure throughput and latency with 10 requests in flight
+  mcp-tui "node server.js" bench call search query=test --concurrency 10 --requests 1000
+
+  # Fuzz every tool with inputs generated from its input schema
+  mcp-tui "node server.js" fuzz --all
+
+  # Share a stdio server over HTTP
+  mcp-tui serve --http :8080 -- node server.js
   
   # Interactive mode (connection screen)
   mcp-tui`,
@@ -123,6 +191,7 @@ Examples:
 				parsedArgs := config.ParseArgs(args, cmdFlag, urlFlag, argsFlag)
 				connectionConfig = parsedArgs.Connection
 			}
+			cli.ApplyConnectionOptions(cmd, connectionConfig)
 
 			// Run TUI mode with connection config
 			runTUIMode(ctx, connectionConfig)
@@ -132,24 +201,131 @@ Examples:
 	// Add persistent flags
 	rootCmd.PersistentFlags().StringVar(&cfg.Command, "cmd", "", "Command to run MCP server (STDIO mode)")
 	rootCmd.PersistentFlags().StringSliceVar(&cfg.Args, "args", []string{}, "Arguments for MCP server command")
-	rootCmd.PersistentFlags().StringVar(&url, "url", "", "URL for HTTP/SSE server")
-	rootCmd.PersistentFlags().String("transport", "stdio", "Transport type (stdio, sse, http, streamable-http)")
+	rootCmd.PersistentFlags().StringVar(&url, "url", "", "URL for HTTP/SSE/WebSocket server, or unix:// / tcp:// socket address")
+	rootCmd.PersistentFlags().String("transport", "stdio", "Transport type (stdio, sse, http, streamable-http, websocket, unix, tcp, auto, replay)")
 	rootCmd.PersistentFlags().DurationVar(&cfg.ConnectionTimeout, "timeout", cfg.ConnectionTimeout, "Connection timeout")
 	// Debug mode always enabled - this is a testing/debug tool
 	cfg.DebugMode = true
 	rootCmd.PersistentFlags().StringVar(&cfg.LogLevel, "log-level", "error", "Log level (debug, info, warn, error)")
-	rootCmd.PersistentFlags().StringP("format", "f", "text", "Output format (text, json)")
+	rootCmd.PersistentFlags().StringP("format", "f", "text", "Output format (text, json, yaml, table, csv, ndjson)")
+	rootCmd.PersistentFlags().StringSlice("columns", nil, "Columns shown by the table and csv formats (e.g. name,description)")
+	rootCmd.PersistentFlags().StringP("query", "q", "", "jq-like expression applied to the JSON output (e.g. '.tools[].name')")
 	rootCmd.PersistentFlags().Bool("porcelain", false, "Machine-readable output (disables progress messages)")
+	rootCmd.PersistentFlags().String("record", "", "Record every JSON-RPC frame to a cassette file (NDJSON)")
+	rootCmd.PersistentFlags().String("replay-match", "fuzzy", "How replayed requests are matched to the cassette (exact, fuzzy, method)")
+	rootCmd.PersistentFlags().String("chaos", "", "Inject faults from a chaos schedule (YAML/JSON) into the session")
+	rootCmd.PersistentFlags().BoolVar(&cfg.PersistSession, "persist", false, "Save the TUI session and offer to resume it on the next launch")
+	rootCmd.PersistentFlags().StringVar(&cfg.PersistFile, "persist-file", "", "Session file for --persist, suffixed per server (default ~/.config/mcp-tui/session.json)")
+	rootCmd.PersistentFlags().DurationVar(&cfg.PersistInterval, "persist-interval", cfg.PersistInterval, "How often --persist saves the session")
+	// Taken out by main before parsing; declared here for the help output
+	rootCmd.PersistentFlags().StringSlice("watch", nil, "Restart the server when these files, directories or globs (e.g. src/**/*.go) change")
 
 	// Add subcommands
 	rootCmd.AddCommand(createToolCommand())
 	rootCmd.AddCommand(createResourceCommand())
 	rootCmd.AddCommand(createPromptCommand())
 	rootCmd.AddCommand(createServerCommand())
+	rootCmd.AddCommand(createShellCommand())
+	rootCmd.AddCommand(createTestCommand())
+	rootCmd.AddCommand(createCheckCommand())
+	rootCmd.AddCommand(createSnapshotCommand())
+	rootCmd.AddCommand(createDiffCommand())
+	rootCmd.AddCommand(createBenchCommand())
+	rootCmd.AddCommand(createFuzzCommand())
+	rootCmd.AddCommand(createMockCommand())
+	rootCmd.AddCommand(createReplayCommand(ctx))
+	rootCmd.AddCommand(createChaosCommand())
+	rootCmd.AddCommand(createProxyCommand())
+	rootCmd.AddCommand(createAttachCommand(ctx))
+	rootCmd.AddCommand(createServeCommand())
 
 	return rootCmd
 }
 
+func createMockCommand() *cobra.Command {
+	mockCmd := cli.NewMockCommand()
+	return mockCmd.CreateCommand()
+}
+
+func createChaosCommand() *cobra.Command {
+	chaosCmd := cli.NewChaosCommand()
+	return chaosCmd.CreateCommand()
+}
+
+func createProxyCommand() *cobra.Command {
+	proxyCmd := cli.NewProxyCommand()
+	return proxyCmd.CreateCommand()
+}
+
+func createServeCommand() *cobra.Command {
+	serveCmd := cli.NewServeCommand()
+	return serveCmd.CreateCommand()
+}
+
+// createAttachCommand shows the live traffic of a running proxy in the TUI
+func createAttachCommand(ctx context.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "attach [monitor socket]",
+		Short: "Watch the live traffic of a running proxy",
+		Long: `Attach to a proxy started with 'mcp-tui proxy' and show the messages it
+relays as they happen, starting with those captured before attaching.
+
+Without an argument, the most recently started proxy is used.
+
+Examples:
+  mcp-tui attach
+  mcp-tui attach /tmp/mcp-tui-1000/mcp-tui-proxy-12345.sock`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var socketPath string
+			if len(args) == 1 {
+				socketPath = args[0]
+			} else {
+				found, err := proxy.FindMonitor()
+				if err != nil {
+					return err
+				}
+				socketPath = found
+			}
+
+			debug.SetGlobalOutput(io.Discard)
+			defer debug.SetGlobalOutput(os.Stderr)
+			return app.NewWithScreen(cfg, screens.NewTrafficScreen(socketPath)).Run(ctx)
+		},
+	}
+}
+
+// createReplayCommand serves a recorded cassette in place of a live server.
+// "mcp-tui replay <cassette> tool list" is rewritten to a connection by the
+// pre-parse in main, so this command only runs the TUI against the cassette.
+func createReplayCommand(ctx context.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "replay <cassette> [tool|resource|prompt|server ...]",
+		Short: "Replay a recorded session from a cassette file",
+		Long: `Serve a cassette recorded with --record as if it were the server.
+
+Requests are matched against the recording by method and params; use
+--replay-match to choose exact, fuzzy (ignores _meta and initialize params)
+or method-only matching.
+
+Examples:
+  mcp-tui replay session.ndjson
+  mcp-tui replay session.ndjson tool list
+  mcp-tui replay session.ndjson tool call echo message=hi --replay-match method`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return fmt.Errorf("place flags after the subcommand: mcp-tui replay %s %s [flags]",
+					args[0], strings.Join(args[1:], " "))
+			}
+			connectionConfig := &config.ConnectionConfig{Type: config.TransportReplay, URL: args[0]}
+			cli.ApplyConnectionOptions(cmd, connectionConfig)
+			runTUIMode(ctx, connectionConfig)
+			return nil
+		},
+	}
+}
+
 func createToolCommand() *cobra.Command {
 	toolCmd := cli.NewToolCommand()
 	return toolCmd.CreateCommand()
@@ -170,6 +346,50 @@ func createServerCommand() *cobra.Command {
 	return serverCmd.CreateCommand()
 }
 
+func createShellCommand() *cobra.Command {
+	shellCmd := cli.NewShellCommand()
+	return shellCmd.CreateCommand()
+}
+
+func createTestCommand() *cobra.Command {
+	testCmd := cli.NewScenarioCommand()
+	return testCmd.CreateCommand()
+}
+
+func createCheckCommand() *cobra.Command {
+	checkCmd := cli.NewCheckCommand()
+	return checkCmd.CreateCommand()
+}
+
+func createSnapshotCommand() *cobra.Command {
+	snapshotCmd := cli.NewSnapshotCommand()
+	return snapshotCmd.CreateCommand()
+}
+
+func createDiffCommand() *cobra.Command {
+	diffCmd := cli.NewDiffCommand()
+	return diffCmd.CreateCommand()
+}
+
+func createBenchCommand() *cobra.Command {
+	benchCmd := cli.NewBenchCommand()
+	return benchCmd.CreateCommand()
+}
+
+func createFuzzCommand() *cobra.Command {
+	fuzzCmd := cli.NewFuzzCommand()
+	return fuzzCmd.CreateCommand()
+}
+
+// runWatchMode runs a CLI command again whenever the watched files change
+func runWatchMode(ctx context.Context, args []string) error {
+	watcher, err := watch.New(cfg.Watch)
+	if err != nil {
+		return err
+	}
+	return cli.RunWatch(ctx, watcher, args)
+}
+
 func runTUIMode(ctx context.Context, connectionConfig *config.ConnectionConfig) {
 	logger := debug.Component("tui")
 	logger.Info("Starting TUI mode")
@@ -184,6 +404,7 @@ func runTUIMode(ctx context.Context, connectionConfig *config.ConnectionConfig)
 		// Re-enable stderr logging before exiting
 		debug.SetGlobalOutput(os.Stderr)
 		logger.Error("TUI application failed", debug.F("error", err))
+		transports.CloseServerProcesses()
 		os.Exit(1)
 	}
 